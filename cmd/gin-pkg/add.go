@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add code to an existing project",
+}
+
+var addResourceCmd = &cobra.Command{
+	Use:   "resource [name]",
+	Short: "Add an ent schema for a new resource",
+	Long: `Adds an ent schema for a new resource to internal/ent/schema and regenerates the ent code.
+Run it from the root of a project created with gin-pkg new. The schema records who created
+and changed its rows and soft-deletes them, unless --hard-delete is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return addResource(args[0])
+	},
+}
+
+// hardDelete makes the new resource remove rows on delete instead of soft-deleting them
+var hardDelete bool
+
+func init() {
+	addResourceCmd.Flags().BoolVar(&hardDelete, "hard-delete", false, "remove rows on delete instead of setting deleted_at")
+	addCmd.AddCommand(addResourceCmd)
+	rootCmd.AddCommand(addCmd)
+}
+
+var resourceNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*([_-][A-Za-z0-9]+)*$`)
+
+func addResource(name string) error {
+	if !resourceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid resource name %q, use e.g. Product or order_item", name)
+	}
+	typeName, fileName := resourceNames(name)
+
+	schemaDir := filepath.Join("internal", "ent", "schema")
+	if _, err := os.Stat(schemaDir); err != nil {
+		return fmt.Errorf("%s not found, run gin-pkg add from the root of the project", schemaDir)
+	}
+	modulePath, err := readModulePath()
+	if err != nil {
+		return err
+	}
+	schemaPath := filepath.Join(schemaDir, fileName+".go")
+	if _, err := os.Stat(schemaPath); err == nil {
+		return fmt.Errorf("%s already exists", schemaPath)
+	}
+
+	if err := os.WriteFile(schemaPath, []byte(resourceSchema(typeName, modulePath)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", schemaPath, err)
+	}
+	fmt.Printf("Created %s\n", schemaPath)
+
+	// Regenerate the ent code
+	cmd := exec.Command("go", "generate", "./internal/ent")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("Warning: failed to generate the ent code: %v\n", err)
+		fmt.Printf("Fix the schema and run: go generate ./internal/ent\n")
+		return nil
+	}
+
+	fmt.Printf("\nResource %s added! 🎉\n\n", typeName)
+	fmt.Printf("Use it through client.%s; the table is created on the next start.\n", typeName)
+	if !hardDelete {
+		fmt.Printf("Deleted rows are kept with deleted_at set; wrap the context with schema.SkipSoftDelete to restore or purge them.\n")
+	}
+	return nil
+}
+
+// resourceNames returns the Go type name and the file name of a resource, e.g. OrderItem
+// and order_item for order-item
+func resourceNames(name string) (string, string) {
+	var typeName, fileName strings.Builder
+	upper := true
+	for i, r := range name {
+		if r == '_' || r == '-' {
+			upper = true
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && !upper {
+			fileName.WriteByte('_')
+		}
+		if upper {
+			typeName.WriteRune(unicode.ToUpper(r))
+			if i > 0 {
+				fileName.WriteByte('_')
+			}
+		} else {
+			typeName.WriteRune(r)
+		}
+		fileName.WriteRune(unicode.ToLower(r))
+		upper = false
+	}
+	return typeName.String(), fileName.String()
+}
+
+// readModulePath returns the module path declared in the project's go.mod
+func readModulePath() (string, error) {
+	content, err := os.ReadFile("go.mod")
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", fmt.Errorf("go.mod does not declare a module")
+}
+
+// resourceSchema returns the source of the ent schema of a new resource in the module at
+// modulePath
+func resourceSchema(typeName, modulePath string) string {
+	mixins := "\t\tTimeMixin{},\n\t\tAuditFieldsMixin{},\n"
+	if !hardDelete {
+		mixins += "\t\tSoftDeleteMixin{},\n"
+	}
+	return fmt.Sprintf(`package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"%[3]s/pkg/idgen"
+)
+
+// %[1]s holds the schema definition for the %[1]s entity.
+type %[1]s struct {
+	ent.Schema
+}
+
+// Fields of the %[1]s.
+func (%[1]s) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("name").
+			NotEmpty().
+			Comment("名称"),
+	}
+}
+
+// Edges of the %[1]s.
+func (%[1]s) Edges() []ent.Edge {
+	return nil
+}
+
+// Mixin of the %[1]s schema.
+func (%[1]s) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+%[2]s	}
+}
+`, typeName, mixins, modulePath)
+}
@@ -24,13 +24,28 @@ var newCmd = &cobra.Command{
 	Use:   "new [project-name]",
 	Short: "Create a new project",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := databaseDefaults[databaseDriver]; !ok {
+			return fmt.Errorf("unsupported database %q, must be postgres or mysql", databaseDriver)
+		}
 		projectName := args[0]
 		createNewProject(projectName)
+		return nil
 	},
 }
 
+// databaseDriver is the database the generated project is configured for
+var databaseDriver string
+
+// databaseDefaults holds the settings of the database section of the generated
+// config/default.yaml that differ between the supported databases
+var databaseDefaults = map[string]map[string]string{
+	"postgres": {"driver": "postgres", "port": "5432", "username": "postgres", "password": "postgres"},
+	"mysql":    {"driver": "mysql", "port": "3306", "username": "root", "password": "mysql"},
+}
+
 func init() {
+	newCmd.Flags().StringVar(&databaseDriver, "db", "postgres", "database of the new project: postgres or mysql")
 	rootCmd.AddCommand(newCmd)
 }
 
@@ -68,6 +83,9 @@ func createNewProject(projectName string) {
 	// Update module name in go.mod
 	updateModuleName(projectPath, projectName)
 
+	// Configure the selected database
+	configureDatabase(projectPath)
+
 	fmt.Printf("\nProject created successfully! 🎉\n\n")
 	fmt.Printf("To get started:\n\n")
 	fmt.Printf("  cd %s\n", projectName)
@@ -266,6 +284,45 @@ func updateImportsInGoFiles(projectPath, projectName string) {
 	}
 }
 
+func configureDatabase(projectPath string) {
+	configPath := filepath.Join(projectPath, "config", "default.yaml")
+
+	// Read config
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		fmt.Printf("Warning: failed to read config/default.yaml: %v\n", err)
+		return
+	}
+
+	// Replace the values of the database section, keeping any comment after them
+	lines := strings.Split(string(content), "\n")
+	inDatabase := false
+	for i, line := range lines {
+		if !strings.HasPrefix(line, " ") {
+			inDatabase = line == "database:"
+			continue
+		}
+		if !inDatabase {
+			continue
+		}
+		key, rest, found := strings.Cut(strings.TrimSpace(line), ":")
+		value, ok := databaseDefaults[databaseDriver][key]
+		if !found || !ok {
+			continue
+		}
+		comment := ""
+		if j := strings.Index(rest, "#"); j >= 0 {
+			comment = "  " + rest[j:]
+		}
+		lines[i] = fmt.Sprintf("  %s: %s%s", key, value, comment)
+	}
+
+	// Write updated config
+	if err := os.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		fmt.Printf("Warning: failed to update config/default.yaml: %v\n", err)
+	}
+}
+
 func createProjectFiles(projectPath, projectName string) {
 	// Create README.md
 	readmeContent := fmt.Sprintf("# %s\n\n", projectName) +
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/hewenyu/gin-pkg/internal/app"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+const backupUsage = `Usage: server backup [flags] <command>
+
+Commands:
+  create         dump the database with pg_dump and store it in the file storage under
+                 backup.prefix, printing the key of the backup
+  restore <key>  replace the objects in the database with those of the backup stored under
+                 key, in a single transaction; stop the servers first
+
+Backups cover every schema, including those of tenants with database.schemaPerTenant.
+
+Flags:
+`
+
+// runBackup implements `server backup`: it backs up the configured database to the file
+// storage or restores it from there without starting the application
+func runBackup(args []string) {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	configPath := flags.String("config", "config/default.yaml", "path to configuration file")
+	logPath := flags.String("log", "logs/backup.log", "path to log file")
+	allowProduction := flags.Bool("allow-production", false, "allow restore in a production environment")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), backupUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	command, key, err := parseBackupArgs(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	logger.SetDefaultLogger(logger.GetDualLogger(*logPath, logger.InfoLevel, false))
+	defer logger.Sync()
+
+	application, err := app.NewApp(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create application: %v\n", err)
+		os.Exit(1)
+	}
+	env := application.Config().Server.Environment
+	if env == "production" && !*allowProduction && command == "restore" {
+		fmt.Fprintln(os.Stderr, "Refusing to restore a backup in production; pass -allow-production if you really mean it")
+		os.Exit(1)
+	}
+	backups, err := application.BackupService()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	if command == "create" {
+		key, err := backups.Backup(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		logger.Infof("Backed up the database to %s", key)
+		fmt.Println(key)
+		return
+	}
+
+	if err := backups.Restore(ctx, key); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	logger.Infof("Restored the database from %s", key)
+	fmt.Printf("Restored the database from %s\n", key)
+}
+
+// parseBackupArgs returns the command and backup key given to `server backup`
+func parseBackupArgs(args []string) (string, string, error) {
+	if len(args) == 0 {
+		return "", "", fmt.Errorf("missing command")
+	}
+	switch command := args[0]; {
+	case command != "create" && command != "restore":
+		return "", "", fmt.Errorf("unknown command %q", command)
+	case command == "restore" && len(args) < 2:
+		return "", "", fmt.Errorf("restore requires the key of a backup")
+	case command == "create" && len(args) > 1, len(args) > 2:
+		return "", "", fmt.Errorf("too many arguments")
+	case command == "restore":
+		return command, args[1], nil
+	default:
+		return command, "", nil
+	}
+}
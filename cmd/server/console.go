@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hewenyu/gin-pkg/internal/app"
+	"github.com/hewenyu/gin-pkg/internal/console"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// runConsole implements `server console`: it boots the services without starting the HTTP
+// server and reads commands from the terminal, or from a script given with -script
+func runConsole(args []string) {
+	flags := flag.NewFlagSet("console", flag.ExitOnError)
+	configPath := flags.String("config", "config/default.yaml", "path to configuration file")
+	scriptPath := flags.String("script", "", "run the commands in this file instead of prompting (- for stdin)")
+	logPath := flags.String("log", "logs/console.log", "path to log file")
+	force := flags.Bool("force", false, "allow running against a production environment")
+	flags.Parse(args)
+
+	// 控制台输出保持干净，日志只写入文件
+	logger.SetDefaultLogger(logger.GetFileLogger(*logPath, logger.InfoLevel))
+	defer logger.Sync()
+
+	application, err := app.NewApp(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create application: %v\n", err)
+		os.Exit(1)
+	}
+	if env := application.Config().Server.Environment; env == "production" && !*force {
+		fmt.Fprintln(os.Stderr, "Refusing to open the console in production; pass -force if you really mean it")
+		os.Exit(1)
+	}
+
+	if err := application.Initialize(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize application: %v\n", err)
+		os.Exit(1)
+	}
+	defer application.Cleanup()
+
+	c := console.New(application.ConsoleEnv(), os.Stdout)
+
+	var in io.Reader = os.Stdin
+	interactive := *scriptPath == ""
+	if *scriptPath != "" && *scriptPath != "-" {
+		f, err := os.Open(*scriptPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open script: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	if interactive {
+		fmt.Printf("gin-pkg console (%s). Type help for a list of commands, exit to leave.\n", application.Config().Server.Environment)
+	}
+	if err := c.Run(context.Background(), in, interactive); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		application.Cleanup()
+		os.Exit(1)
+	}
+}
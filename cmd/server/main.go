@@ -11,6 +11,20 @@ import (
 )
 
 func main() {
+	// 子命令：server console、server migrate、server backup
+	if len(os.Args) > 1 && os.Args[1] == "console" {
+		runConsole(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		runBackup(os.Args[2:])
+		return
+	}
+
 	// Parse command line flags
 	configPath := flag.String("config", "config/default.yaml", "path to configuration file")
 	debugMode := flag.Bool("debug", false, "enable debug logging")
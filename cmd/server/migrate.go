@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/app"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+const migrateUsage = `Usage: server migrate [flags] <command>
+
+Commands:
+  status           list the migrations and whether they are applied
+  up [version]     apply the pending migrations up to version, or all of them followed by
+                   the schema migration
+  down [version]   revert the migrations newer than version, or the latest one
+  force <version>  record the database as migrated to version and clear the dirty state,
+                   after repairing an interrupted migration by hand
+
+With database.schemaPerTenant the commands run on the shared schema and then on the
+schema of every tenant, unless -schema selects one.
+
+Flags:
+`
+
+// runMigrate implements `server migrate`: it shows and changes the migration state of the
+// configured database without starting the application
+func runMigrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	configPath := flags.String("config", "config/default.yaml", "path to configuration file")
+	logPath := flags.String("log", "logs/migrate.log", "path to log file")
+	allowProduction := flags.Bool("allow-production", false, "allow down and force in a production environment")
+	schema := flags.String("schema", "", "run only on this schema, public or tenant_<id>")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), migrateUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	command, version, err := parseMigrateArgs(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	logger.SetDefaultLogger(logger.GetDualLogger(*logPath, logger.InfoLevel, false))
+	defer logger.Sync()
+
+	application, err := app.NewApp(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create application: %v\n", err)
+		os.Exit(1)
+	}
+	env := application.Config().Server.Environment
+	if env == "production" && !*allowProduction && (command == "down" || command == "force") {
+		fmt.Fprintf(os.Stderr, "Refusing to run migrate %s in production; pass -allow-production if you really mean it\n", command)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	migrators, err := application.Migrators(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to the database: %v\n", err)
+		application.Cleanup()
+		os.Exit(1)
+	}
+	defer application.Cleanup()
+
+	if *schema != "" {
+		migrators = selectSchema(migrators, *schema)
+		if len(migrators) == 0 {
+			fmt.Fprintf(os.Stderr, "Unknown schema %q\n", *schema)
+			application.Cleanup()
+			os.Exit(1)
+		}
+	}
+	if command == "status" {
+		fmt.Printf("Environment: %s\n", env)
+	}
+	for _, migrator := range migrators {
+		if len(migrators) > 1 || *schema != "" {
+			fmt.Printf("\nSchema: %s\n", migrator.Schema)
+		}
+		if err := migrate(ctx, migrator, command, version); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", migrator.Schema, err)
+			application.Cleanup()
+			os.Exit(1)
+		}
+	}
+}
+
+// selectSchema returns the migrator of schema, if any
+func selectSchema(migrators []app.SchemaMigrator, schema string) []app.SchemaMigrator {
+	for _, migrator := range migrators {
+		if migrator.Schema == schema {
+			return []app.SchemaMigrator{migrator}
+		}
+	}
+	return nil
+}
+
+// parseMigrateArgs returns the command and version given to `server migrate`. The version
+// is -1 when it is not given.
+func parseMigrateArgs(args []string) (string, int, error) {
+	if len(args) == 0 {
+		return "", 0, fmt.Errorf("missing command")
+	}
+	command, version := args[0], -1
+	switch {
+	case command != "status" && command != "up" && command != "down" && command != "force":
+		return "", 0, fmt.Errorf("unknown command %q", command)
+	case command == "status" && len(args) > 1, len(args) > 2:
+		return "", 0, fmt.Errorf("too many arguments")
+	case command == "force" && len(args) < 2:
+		return "", 0, fmt.Errorf("force requires a version")
+	}
+	if len(args) == 2 {
+		v, err := strconv.Atoi(args[1])
+		// up 0 would apply nothing
+		if err != nil || v < 0 || v == 0 && command == "up" {
+			return "", 0, fmt.Errorf("invalid version %q", args[1])
+		}
+		version = v
+	}
+	return command, version, nil
+}
+
+// migrate runs a migrate command on a schema
+func migrate(ctx context.Context, migrator app.SchemaMigrator, command string, version int) error {
+	switch command {
+	case "up":
+		if version >= 0 && version < migrator.Latest() {
+			return migrator.Up(ctx, version)
+		}
+		if err := migrator.Up(ctx, 0); err != nil {
+			return err
+		}
+		return migrator.MigrateSchema(ctx)
+
+	case "down":
+		if version < 0 {
+			// 默认只回滚最新的一个迁移
+			statuses, err := migrator.Status(ctx)
+			if err != nil {
+				return err
+			}
+			version = 0
+			for i := len(statuses) - 1; i >= 0; i-- {
+				if statuses[i].Applied {
+					if i > 0 {
+						version = statuses[i-1].Version
+					}
+					break
+				}
+			}
+		}
+		return migrator.Down(ctx, version)
+
+	case "force":
+		return migrator.Force(ctx, version)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT\tREVERSIBLE")
+	for _, s := range statuses {
+		state, appliedAt := "pending", "-"
+		if s.Dirty {
+			state = "dirty"
+		} else if s.Applied {
+			state = "applied"
+		}
+		if !s.AppliedAt.IsZero() {
+			appliedAt = s.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		reversible := "no"
+		if s.Reversible {
+			reversible = "yes"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt, reversible)
+	}
+	return w.Flush()
+}
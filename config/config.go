@@ -2,6 +2,10 @@ package config
 
 import (
 	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -13,22 +17,123 @@ type Config struct {
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Auth     AuthConfig     `mapstructure:"auth"`
 	Security SecurityConfig `mapstructure:"security"`
+	// Users 用户生命周期配置
+	Users UsersConfig `mapstructure:"users"`
+	// Orgs 多租户组织配置
+	Orgs OrgsConfig `mapstructure:"orgs"`
+	// ServiceAccount 服务账号令牌配置
+	ServiceAccount ServiceAccountConfig `mapstructure:"serviceAccount"`
+	// Settings 运行时配置与功能开关的缓存配置
+	Settings SettingsConfig `mapstructure:"settings"`
+	// Metrics 指标导出配置
+	Metrics MetricsConfig `mapstructure:"metrics"`
+	// Health 存活与就绪探针配置
+	Health HealthConfig `mapstructure:"health"`
+	// Guardrails 并发与内存保护配置
+	Guardrails GuardrailsConfig `mapstructure:"guardrails"`
+	// Mail 邮件发送配置
+	Mail MailConfig `mapstructure:"mail"`
+	// EmailChange 修改邮箱流程配置
+	EmailChange EmailChangeConfig `mapstructure:"emailChange"`
+	// Invitation 邀请注册配置
+	Invitation InvitationConfig `mapstructure:"invitation"`
+	// Storage 文件存储配置
+	Storage StorageConfig `mapstructure:"storage"`
+	// Backup 数据库备份配置
+	Backup BackupConfig `mapstructure:"backup"`
+	// Avatar 默认头像生成配置
+	Avatar AvatarConfig `mapstructure:"avatar"`
+	// SMS 短信发送配置
+	SMS SMSConfig `mapstructure:"sms"`
+	// OTP 短信验证码登录配置
+	OTP OTPConfig `mapstructure:"otp"`
+	// Runbook 运维操作接口配置
+	Runbook RunbookConfig `mapstructure:"runbook"`
+	// OAuth 内置 OAuth2 授权服务配置
+	OAuth OAuthConfig `mapstructure:"oauth"`
+	// Guest 匿名访客令牌配置
+	Guest GuestConfig `mapstructure:"guest"`
+	// TokenExchange 用受信任的外部身份令牌换取本服务令牌的配置
+	TokenExchange TokenExchangeConfig `mapstructure:"tokenExchange"`
+	// CORS 跨域访问配置
+	CORS CORSConfig `mapstructure:"cors"`
+	// Encryption 敏感字段加密配置
+	Encryption EncryptionConfig `mapstructure:"encryption"`
+	// I18n 错误信息多语言配置
+	I18n I18nConfig `mapstructure:"i18n"`
+	// Startup 启动时等待数据库、Redis 等依赖的配置
+	Startup StartupConfig `mapstructure:"startup"`
 }
 
 type ServerConfig struct {
 	Port         int           `mapstructure:"port"`
 	ReadTimeout  time.Duration `mapstructure:"readTimeout"`
 	WriteTimeout time.Duration `mapstructure:"writeTimeout"`
+	// Environment 运行环境（development、staging、production），生产环境禁用开发者控制台
+	Environment string `mapstructure:"environment"`
+	// RequestIDHeader 请求ID所在的请求头与响应头
+	RequestIDHeader string `mapstructure:"requestIDHeader"`
+	// TrustedProxies 可信代理的 IP 或 CIDR，只有来自这些地址的请求才按 X-Forwarded-For 和 X-Real-IP 确定客户端 IP；
+	// 默认为空，不信任任何代理，客户端 IP 取连接的对端地址
+	TrustedProxies []string `mapstructure:"trustedProxies"`
 }
 
 type DatabaseConfig struct {
+	// Driver 数据库类型：postgres 或 mysql
 	Driver   string `mapstructure:"driver"`
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
 	Database string `mapstructure:"database"`
-	SSLMode  string `mapstructure:"sslMode"`
+	// SSLMode 取 PostgreSQL 的 sslmode 值（disable、require、verify-full 等），MySQL 会映射为对应的 tls 参数
+	SSLMode string `mapstructure:"sslMode"`
+	// SkipMigrations 启动时不执行迁移，只检查迁移状态；迁移由 server migrate 命令执行
+	SkipMigrations bool `mapstructure:"skipMigrations"`
+	// Replicas 只读副本，只读查询轮流发往健康的副本，写入、事务和加锁读取使用主库
+	Replicas []DatabaseReplicaConfig `mapstructure:"replicas"`
+	// ReplicaHealthCheckInterval 副本健康检查间隔，未通过检查的副本暂停使用
+	ReplicaHealthCheckInterval time.Duration `mapstructure:"replicaHealthCheckInterval"`
+	// ReadPrimaryHeader 客户端将该请求头设为 true 时，本次请求从主库读取
+	ReadPrimaryHeader string `mapstructure:"readPrimaryHeader"`
+	// IDStrategy 新建记录的主键生成方式：uuidv4、uuidv7 或 ulid；后两者按创建时间排序
+	IDStrategy string `mapstructure:"idStrategy"`
+	// LogQueries 以 Debug 级别记录每条 SQL 的耗时、行数和请求ID，不记录参数
+	LogQueries bool `mapstructure:"logQueries"`
+	// SlowQueryThreshold 耗时达到该值的 SQL 以 Warn 级别记录，0 表示不记录
+	SlowQueryThreshold time.Duration `mapstructure:"slowQueryThreshold"`
+	// TagQueries 在每条 SQL 末尾附加 sqlcommenter 格式的注释，包含请求ID、路由和用户ID，便于在数据库侧定位慢查询来源
+	TagQueries bool `mapstructure:"tagQueries"`
+	// SchemaPerTenant 为 auth.tenantSecrets 中的每个租户使用独立的 PostgreSQL schema（tenant_<租户ID>），仅支持 postgres
+	SchemaPerTenant bool `mapstructure:"schemaPerTenant"`
+	// TenantHeader 请求所属租户的请求头，未携带时使用共享的 public schema
+	TenantHeader string `mapstructure:"tenantHeader"`
+	// Postgres PostgreSQL 驱动（pgx）的连接选项
+	Postgres PostgresConfig `mapstructure:"postgres"`
+}
+
+// PostgresConfig configures the pgx driver of PostgreSQL connections, including those of
+// replicas and tenant schemas. QueryExecMode sets how statements are sent:
+// "cache_statement" (default) prepares every statement once per connection and keeps up to
+// StatementCacheCapacity of them, "cache_describe" only caches the parameter and result
+// types of up to DescriptionCacheCapacity statements, "describe_exec" asks for them on every
+// run, and "exec" and "simple_protocol" prepare nothing, as PgBouncer in transaction mode
+// requires. RuntimeParams are session parameters set on every connection, such as
+// application_name or statement_timeout.
+type PostgresConfig struct {
+	QueryExecMode            string            `mapstructure:"queryExecMode"`
+	StatementCacheCapacity   int               `mapstructure:"statementCacheCapacity"`
+	DescriptionCacheCapacity int               `mapstructure:"descriptionCacheCapacity"`
+	RuntimeParams            map[string]string `mapstructure:"runtimeParams"`
+}
+
+// DatabaseReplicaConfig is a read replica of the database. Username and Password default to
+// those of the primary; the database name and sslMode are always the primary's.
+type DatabaseReplicaConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
 }
 
 type RedisConfig struct {
@@ -36,6 +141,82 @@ type RedisConfig struct {
 	Port     int    `mapstructure:"port"`
 	Password string `mapstructure:"password"`
 	DB       int    `mapstructure:"db"`
+	// 部署模式：single 连接单个节点（host、port）；cluster 连接 Redis Cluster（addrs），如开启集群模式的 ElastiCache；
+	// sentinel 通过 Redis Sentinel 查找主节点并在故障转移后自动切换
+	Mode string `mapstructure:"mode"`
+	// 集群的种子节点（host:port），其余节点自动发现
+	Addrs []string `mapstructure:"addrs"`
+	// 集群路由选项
+	Cluster RedisClusterConfig `mapstructure:"cluster"`
+	// Sentinel 配置
+	Sentinel RedisSentinelConfig `mapstructure:"sentinel"`
+	// ACL 用户名（Redis 6+），为空时使用 default 用户
+	Username string `mapstructure:"username"`
+	// 连接超时，0 表示使用 go-redis 默认值（建立连接 5s，读写 3s）
+	DialTimeout  time.Duration `mapstructure:"dialTimeout"`
+	ReadTimeout  time.Duration `mapstructure:"readTimeout"`
+	WriteTimeout time.Duration `mapstructure:"writeTimeout"`
+	// TLS 配置，托管的 Redis 服务通常要求 TLS
+	TLS RedisTLSConfig `mapstructure:"tls"`
+	// Redis 不可用时的进程内降级存储
+	Fallback RedisFallbackConfig `mapstructure:"fallback"`
+	// 热点缓存（应用凭证、用户）前的进程内 LRU
+	LocalCache RedisLocalCacheConfig `mapstructure:"localCache"`
+}
+
+// RedisLocalCacheConfig keeps the hottest entries of the app credential and user caches in
+// an in-process LRU for TTL, in front of Redis. With PubSub, changes are published over Redis
+// so every instance drops its local copy at once; without it, other instances may serve a
+// stale entry for up to TTL. TTL defaults to 1s and MaxEntries to 10000.
+type RedisLocalCacheConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	TTL        time.Duration `mapstructure:"ttl"`
+	MaxEntries int           `mapstructure:"maxEntries"`
+	PubSub     bool          `mapstructure:"pubSub"`
+}
+
+// RedisFallbackConfig keeps nonces, replay markers and token revocations in a bounded
+// in-process store while Redis is unavailable. Entries written during an outage are only
+// seen by the instance that wrote them, so a token revoked on one instance stays valid on
+// the others until Redis is back. MaxEntries defaults to 100000.
+type RedisFallbackConfig struct {
+	Enabled    bool `mapstructure:"enabled"`
+	MaxEntries int  `mapstructure:"maxEntries"`
+}
+
+// RedisTLSConfig enables TLS for the connections to every Redis node, and to the sentinels
+// in sentinel mode. CAFile verifies the server certificate instead of the system pool;
+// CertFile and KeyFile present a client certificate when the server requires mutual TLS.
+type RedisTLSConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"caFile"`
+	CertFile           string `mapstructure:"certFile"`
+	KeyFile            string `mapstructure:"keyFile"`
+	ServerName         string `mapstructure:"serverName"`
+	InsecureSkipVerify bool   `mapstructure:"insecureSkipVerify"`
+}
+
+// RedisSentinelConfig locates the master through Redis Sentinel. Username and Password
+// authenticate with the sentinels when they require it; redis.username and redis.password
+// are used for the master. Operations are
+// retried for FailoverTimeout while a failover is in progress.
+type RedisSentinelConfig struct {
+	MasterName      string        `mapstructure:"masterName"`
+	Addrs           []string      `mapstructure:"addrs"`
+	Username        string        `mapstructure:"username"`
+	Password        string        `mapstructure:"password"`
+	FailoverTimeout time.Duration `mapstructure:"failoverTimeout"`
+}
+
+// RedisClusterConfig holds the routing options of cluster mode. ReadOnly sends read-only
+// commands to replicas; RouteByLatency and RouteRandomly pick the master or replica closest
+// to the instance or a random one for them, and imply ReadOnly. Replicas may lag behind the
+// master, so a token revoked a moment ago can still be seen as valid.
+type RedisClusterConfig struct {
+	MaxRedirects   int  `mapstructure:"maxRedirects"`
+	ReadOnly       bool `mapstructure:"readOnly"`
+	RouteByLatency bool `mapstructure:"routeByLatency"`
+	RouteRandomly  bool `mapstructure:"routeRandomly"`
 }
 
 type AuthConfig struct {
@@ -43,6 +224,8 @@ type AuthConfig struct {
 	RefreshTokenSecret     string        `mapstructure:"refreshTokenSecret"`
 	AccessTokenDuration    time.Duration `mapstructure:"accessTokenDuration"`
 	RefreshTokenDuration   time.Duration `mapstructure:"refreshTokenDuration"`
+	RememberMeDuration     time.Duration `mapstructure:"rememberMeDuration"`
+	ImpersonationDuration  time.Duration `mapstructure:"impersonationDuration"`
 	EnableRegistration     bool          `mapstructure:"enableRegistration"`
 	DefaultAccessTokenExp  int64         `mapstructure:"defaultAccessTokenExp"`
 	DefaultRefreshTokenExp int64         `mapstructure:"defaultRefreshTokenExp"`
@@ -50,18 +233,551 @@ type AuthConfig struct {
 	DefaultAdminUsername   string        `mapstructure:"defaultAdminUsername"`
 	DefaultAdminPassword   string        `mapstructure:"defaultAdminPassword"`
 	CreateDefaultAdmin     bool          `mapstructure:"createDefaultAdmin"`
+	// 密码强度策略
+	PasswordPolicy PasswordPolicyConfig `mapstructure:"passwordPolicy"`
+	// 密码哈希算法配置
+	PasswordHashing PasswordHashingConfig `mapstructure:"passwordHashing"`
+	// 角色到权限范围（scope）的映射，签发令牌时写入 scopes 声明
+	RoleScopes map[string][]string `mapstructure:"roleScopes"`
+	// 令牌签发者（iss），校验时必须一致
+	Issuer string `mapstructure:"issuer"`
+	// 令牌受众（aud），设置后签发时写入并在校验时强制要求
+	Audience string `mapstructure:"audience"`
+	// 校验 exp、nbf、iat 时容忍的时钟偏差
+	ClockSkewLeeway time.Duration `mapstructure:"clockSkewLeeway"`
+	// 各租户的签名密钥：签发给租户的令牌携带 tenant_id 声明，并使用该租户的密钥签名和校验
+	TenantSecrets []TenantSecretConfig `mapstructure:"tenantSecrets"`
+	// 加密令牌内容（JWE），客户端和中间环节无法读取邮箱、角色等声明
+	TokenEncryption TokenEncryptionConfig `mapstructure:"tokenEncryption"`
+	// 令牌形式：jwt（自包含的签名令牌）或 opaque（随机令牌，声明保存在 Redis 会话中）
+	TokenMode string `mapstructure:"tokenMode"`
+	// 通过 HttpOnly Cookie 下发令牌，供浏览器客户端使用，启用后校验 CSRF
+	Cookies TokenCookiesConfig `mapstructure:"cookies"`
+	// 将令牌绑定到签发时的客户端，降低令牌被盗用的风险
+	TokenBinding TokenBindingConfig `mapstructure:"tokenBinding"`
+	// 在进程内短暂缓存令牌吊销状态的查询，减少每个请求访问 Redis 的次数
+	RevocationCache RevocationCacheConfig `mapstructure:"revocationCache"`
+	// 在数据库中记录签发的刷新令牌，支持查看和吊销登录会话
+	RefreshTokenStore RefreshTokenStoreConfig `mapstructure:"refreshTokenStore"`
+}
+
+// TenantSecretConfig is the secret signing the access and refresh tokens of a tenant.
+// Tenants are listed rather than keyed by ID because configuration keys are case-insensitive.
+type TenantSecretConfig struct {
+	TenantID string `mapstructure:"tenantId"`
+	Secret   string `mapstructure:"secret"`
+}
+
+// TokenEncryptionConfig encrypts signed tokens as compact JWE (dir, A256GCM) with Key, a
+// base64-encoded 32-byte AES key, for the token types that are enabled
+type TokenEncryptionConfig struct {
+	Key           string `mapstructure:"key"`
+	AccessTokens  bool   `mapstructure:"accessTokens"`
+	RefreshTokens bool   `mapstructure:"refreshTokens"`
+}
+
+// RevocationCacheConfig caches blacklist and per-user revocation lookups in process for TTL.
+// With PubSub, revocations are broadcast over Redis so other instances drop their cached
+// lookups at once; without it they notice revocations within TTL.
+type RevocationCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+	PubSub  bool          `mapstructure:"pubSub"`
+}
+
+// RefreshTokenStoreConfig records issued refresh tokens in the database, hashed, with the
+// device they were issued to, so users can list and revoke their sessions. Rotated and
+// revoked tokens are kept for auditing until Retention after they expire; they are purged
+// every PurgeInterval.
+type RefreshTokenStoreConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Retention     time.Duration `mapstructure:"retention"`
+	PurgeInterval time.Duration `mapstructure:"purgeInterval"`
+}
+
+// TokenBindingConfig binds issued tokens to a hash of the client's IP network prefix and/or
+// User-Agent. Mode is enforce (reject tokens used by another client) or log-only.
+type TokenBindingConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`
+	Mode             string `mapstructure:"mode"`
+	IP               bool   `mapstructure:"ip"`
+	IPv4PrefixLength int    `mapstructure:"ipv4PrefixLength"`
+	IPv6PrefixLength int    `mapstructure:"ipv6PrefixLength"`
+	UserAgent        bool   `mapstructure:"userAgent"`
+}
+
+// TokenCookiesConfig delivers tokens in HttpOnly cookies instead of response bodies.
+// Cookie-authenticated requests are protected against CSRF with security.csrf.
+// SameSite is lax, strict or none; none requires Secure.
+type TokenCookiesConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`
+	AccessTokenName  string `mapstructure:"accessTokenName"`
+	RefreshTokenName string `mapstructure:"refreshTokenName"`
+	Domain           string `mapstructure:"domain"`
+	Path             string `mapstructure:"path"`
+	RefreshPath      string `mapstructure:"refreshPath"`
+	Secure           bool   `mapstructure:"secure"`
+	SameSite         string `mapstructure:"sameSite"`
+}
+
+// PasswordHashingConfig selects the password hashing algorithm (bcrypt, argon2id or scrypt)
+// and its cost parameters. Hashes from other algorithms are upgraded on the next login.
+type PasswordHashingConfig struct {
+	Algorithm     string `mapstructure:"algorithm"`
+	BcryptCost    int    `mapstructure:"bcryptCost"`
+	Argon2Time    uint32 `mapstructure:"argon2Time"`
+	Argon2Memory  uint32 `mapstructure:"argon2Memory"`
+	Argon2Threads uint8  `mapstructure:"argon2Threads"`
+	ScryptN       int    `mapstructure:"scryptN"`
+	ScryptR       int    `mapstructure:"scryptR"`
+	ScryptP       int    `mapstructure:"scryptP"`
+}
+
+// PasswordPolicyConfig configures the password strength requirements
+type PasswordPolicyConfig struct {
+	MinLength           int      `mapstructure:"minLength"`
+	MaxLength           int      `mapstructure:"maxLength"`
+	RequireUppercase    bool     `mapstructure:"requireUppercase"`
+	RequireLowercase    bool     `mapstructure:"requireLowercase"`
+	RequireDigit        bool     `mapstructure:"requireDigit"`
+	RequireSymbol       bool     `mapstructure:"requireSymbol"`
+	MinCharacterClasses int      `mapstructure:"minCharacterClasses"`
+	DenyCommon          bool     `mapstructure:"denyCommon"`
+	DeniedPasswords     []string `mapstructure:"deniedPasswords"`
+	HistorySize         int      `mapstructure:"historySize"`
 }
 
 type SecurityConfig struct {
+	// 签名校验模式：enforce（拒绝）、log-only（仅记录）或 off（关闭），生产环境只能为 enforce
+	Mode                    string        `mapstructure:"mode"`
 	TimestampValidityWindow time.Duration `mapstructure:"timestampValidityWindow"`
 	NonceValidityDuration   time.Duration `mapstructure:"nonceValidityDuration"`
 	SignatureSecret         string        `mapstructure:"signatureSecret"`
+	// 轮换签名密钥时切换到的新密钥，通过运维接口生效
+	NextSignatureSecret string `mapstructure:"nextSignatureSecret"`
+	// 轮换期间仍然接受的旧密钥，所有客户端切换后移除
+	SecondarySignatureSecret string `mapstructure:"secondarySignatureSecret"`
+	// 通过运维接口轮换后旧密钥继续有效的时长
+	SignatureSecretOverlap time.Duration `mapstructure:"signatureSecretOverlap"`
+	// 登录/注册接口的限流配置
+	LoginRateLimit    RateLimitConfig `mapstructure:"loginRateLimit"`
+	RegisterRateLimit RateLimitConfig `mapstructure:"registerRateLimit"`
+	OTPRateLimit      RateLimitConfig `mapstructure:"otpRateLimit"`
+	// 登录/注册接口的人机验证配置
+	Captcha CaptchaConfig `mapstructure:"captcha"`
+	// JSON 请求体严格校验
+	StrictJSON StrictJSONConfig `mapstructure:"strictJSON"`
+	// 接受的签名版本（X-Sign-Version），为空时接受全部内置版本
+	SignVersions []string `mapstructure:"signVersions"`
+	// 跳过时间戳、随机数与签名校验的路由
+	SkipPaths []SkipPathConfig `mapstructure:"skipPaths"`
+	// 接入方独立的应用标识与签名密钥
+	AppCredentials AppCredentialsConfig `mapstructure:"appCredentials"`
+	// 随机数的存储后端：redis、postgres 或 memory
+	NonceStore NonceStoreConfig `mapstructure:"nonceStore"`
+	// 按时间戳、随机数与签名去重，拒绝重放的请求
+	ReplayProtection ReplayProtectionConfig `mapstructure:"replayProtection"`
+	// 时间戳、随机数与签名使用的请求头和参数名
+	Names SecurityNamesConfig `mapstructure:"names"`
+	// 令牌通过 Cookie 下发时的 CSRF 防护
+	CSRF CSRFConfig `mapstructure:"csrf"`
+	// 签名校验失败时记录规范化字符串与收到的签名（不记录期望签名，以免日志被用于重放），仅用于排查客户端接入问题
+	VerboseSignatureLogs bool `mapstructure:"verboseSignatureLogs"`
+}
+
+// SecurityNamesConfig sets the headers and parameters carrying the signing values, e.g. to
+// match an existing gateway. Empty header names are HeaderPrefix followed by Timestamp, Nonce,
+// Sign and Sign-Version; empty parameter names are timestamp, nonce and sign.
+type SecurityNamesConfig struct {
+	HeaderPrefix      string `mapstructure:"headerPrefix"`
+	TimestampHeader   string `mapstructure:"timestampHeader"`
+	NonceHeader       string `mapstructure:"nonceHeader"`
+	SignHeader        string `mapstructure:"signHeader"`
+	SignVersionHeader string `mapstructure:"signVersionHeader"`
+	TimestampParam    string `mapstructure:"timestampParam"`
+	NonceParam        string `mapstructure:"nonceParam"`
+	SignParam         string `mapstructure:"signParam"`
+}
+
+// CSRFConfig names the double-submit CSRF cookie and header used when auth.cookies is enabled
+type CSRFConfig struct {
+	CookieName string `mapstructure:"cookieName"`
+	HeaderName string `mapstructure:"headerName"`
+}
+
+// NonceStoreConfig selects where issued nonces are kept. Backend is "redis" (the default),
+// "postgres", which needs database.driver postgres and purges expired nonces every
+// CleanupInterval, or "memory", which holds up to MaxEntries nonces in process and only suits
+// a single instance.
+type NonceStoreConfig struct {
+	Backend         string        `mapstructure:"backend"`
+	CleanupInterval time.Duration `mapstructure:"cleanupInterval"`
+	MaxEntries      int           `mapstructure:"maxEntries"`
+}
+
+// ReplayProtectionConfig rejects signed requests that were already accepted within the
+// timestamp validity window. NonceOptional lets clients skip fetching a nonce, which is
+// only safe with replay protection enabled.
+type ReplayProtectionConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	NonceOptional bool `mapstructure:"nonceOptional"`
+}
+
+// AppCredentialsConfig configures per-client app keys. Clients send their app key in
+// HeaderName and sign with their own secret; Required rejects the shared signature secret.
+// App secrets are encrypted with EncryptionKey and resolved credentials cached for CacheTTL.
+type AppCredentialsConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	HeaderName    string        `mapstructure:"headerName"`
+	Required      bool          `mapstructure:"required"`
+	EncryptionKey string        `mapstructure:"encryptionKey"`
+	CacheTTL      time.Duration `mapstructure:"cacheTTL"`
+}
+
+// SkipPathConfig exempts routes from request signing. Path is a path.Match pattern over the
+// registered route path, where a trailing "/**" matches every route below the prefix.
+// An empty Method matches every method.
+type SkipPathConfig struct {
+	Method string `mapstructure:"method"`
+	Path   string `mapstructure:"path"`
+}
+
+// StrictJSONConfig enables rejecting JSON bodies with unknown fields, duplicate keys,
+// or beyond the declared size and nesting limits
+type StrictJSONConfig struct {
+	Enabled      bool  `mapstructure:"enabled"`
+	MaxBodyBytes int64 `mapstructure:"maxBodyBytes"`
+	MaxDepth     int   `mapstructure:"maxDepth"`
+}
+
+// CaptchaConfig configures captcha verification on the auth endpoints.
+// Provider is recaptcha, hcaptcha or turnstile.
+type CaptchaConfig struct {
+	Provider   string        `mapstructure:"provider"`
+	Secret     string        `mapstructure:"secret"`
+	MinScore   float64       `mapstructure:"minScore"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	HeaderName string        `mapstructure:"headerName"`
+	Register   bool          `mapstructure:"register"`
+	Login      bool          `mapstructure:"login"`
+}
+
+// RateLimitConfig configures per-IP and per-account request limits for an endpoint.
+// A limit of 0 disables that dimension. Requests are counted in fixed windows, or in sliding
+// windows with SlidingWindow, which keeps clients from spending a limit twice around the
+// end of a window.
+type RateLimitConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	PerIPLimit       int           `mapstructure:"perIPLimit"`
+	PerIPWindow      time.Duration `mapstructure:"perIPWindow"`
+	PerAccountLimit  int           `mapstructure:"perAccountLimit"`
+	PerAccountWindow time.Duration `mapstructure:"perAccountWindow"`
+	SlidingWindow    bool          `mapstructure:"slidingWindow"`
+}
+
+// UsersConfig configures the user lifecycle and bulk imports. Deleted users are soft-deleted
+// and can be restored until the purge job removes them permanently after DeletedRetention.
+// Imports with more than ImportAsyncRows rows run in the background.
+type UsersConfig struct {
+	PurgeDeleted       bool          `mapstructure:"purgeDeleted"`
+	DeletedRetention   time.Duration `mapstructure:"deletedRetention"`
+	PurgeInterval      time.Duration `mapstructure:"purgeInterval"`
+	ImportMaxBytes     int64         `mapstructure:"importMaxBytes"`
+	ImportMaxRows      int           `mapstructure:"importMaxRows"`
+	ImportAsyncRows    int           `mapstructure:"importAsyncRows"`
+	ImportJobRetention time.Duration `mapstructure:"importJobRetention"`
+	// TrackLastSeen records the last authenticated request of every user. Requests are
+	// buffered in Redis at most once per LastSeenWriteInterval and user, and written to the
+	// database every LastSeenFlushInterval. Users count as online for OnlineWindow.
+	TrackLastSeen         bool          `mapstructure:"trackLastSeen"`
+	LastSeenWriteInterval time.Duration `mapstructure:"lastSeenWriteInterval"`
+	LastSeenFlushInterval time.Duration `mapstructure:"lastSeenFlushInterval"`
+	OnlineWindow          time.Duration `mapstructure:"onlineWindow"`
+	// TrackActivity records logins, profile updates, password changes and similar actions in
+	// the timeline of each user. Entries are queued, up to ActivityQueueSize, and written in
+	// the background; entries recorded while the queue is full are dropped.
+	TrackActivity     bool `mapstructure:"trackActivity"`
+	ActivityQueueSize int  `mapstructure:"activityQueueSize"`
+	// CacheTTL caches user lookups by ID and email in Redis; zero disables the cache
+	CacheTTL time.Duration `mapstructure:"cacheTTL"`
+	// StatsCacheTTL caches the admin user statistics in Redis; zero computes them on every request
+	StatsCacheTTL time.Duration `mapstructure:"statsCacheTTL"`
+}
+
+// OrgsConfig configures organizations, which let one deployment host isolated tenants.
+// Requests act in the organization of the user's token; requests whose token names no
+// organization, and unauthenticated ones such as registrations, may select one in HeaderName.
+type OrgsConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	HeaderName string `mapstructure:"headerName"`
+}
+
+// ServiceAccountConfig configures long-lived service account tokens for
+// legacy integrations that cannot implement request signing
+type ServiceAccountConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	HeaderName       string        `mapstructure:"headerName"`
+	MaxLifetime      time.Duration `mapstructure:"maxLifetime"`
+	RotationReminder time.Duration `mapstructure:"rotationReminder"`
+	ReminderInterval time.Duration `mapstructure:"reminderInterval"`
+}
+
+// SettingsConfig configures the stale-while-revalidate cache in front of runtime settings,
+// feature flags and role permissions. Values are served from memory for FreshFor, then served stale for up to
+// StaleFor while being refreshed in the background.
+type SettingsConfig struct {
+	FreshFor       time.Duration `mapstructure:"freshFor"`
+	StaleFor       time.Duration `mapstructure:"staleFor"`
+	RefreshTimeout time.Duration `mapstructure:"refreshTimeout"`
+}
+
+// MetricsConfig configures the expvar metrics endpoint
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+}
+
+// HealthConfig configures the liveness and readiness probes
+type HealthConfig struct {
+	// LivenessPath 存活探针路径，进程能处理请求即返回 200
+	LivenessPath string `mapstructure:"livenessPath"`
+	// ReadinessPath 就绪探针路径，依赖（如 Redis）不可用时返回 503
+	ReadinessPath string `mapstructure:"readinessPath"`
+	// Timeout 就绪检查的超时时间
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// GuardrailsConfig configures optional load-shedding and runtime watermark monitoring.
+// Zero values disable the corresponding guardrail.
+type GuardrailsConfig struct {
+	MaxConcurrentRequests int           `mapstructure:"maxConcurrentRequests"`
+	GoroutineWatermark    int           `mapstructure:"goroutineWatermark"`
+	HeapWatermarkMB       uint64        `mapstructure:"heapWatermarkMB"`
+	CheckInterval         time.Duration `mapstructure:"checkInterval"`
+	DumpDir               string        `mapstructure:"dumpDir"`
+	DumpCooldown          time.Duration `mapstructure:"dumpCooldown"`
+}
+
+// MailConfig configures outgoing email. The "log" driver only writes emails to the log.
+type MailConfig struct {
+	Driver   string `mapstructure:"driver"`
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+// EmailChangeConfig configures the email change flow. The URLs point at the frontend pages
+// that post the token from the link back to the API.
+type EmailChangeConfig struct {
+	ConfirmTTL     time.Duration `mapstructure:"confirmTTL"`
+	RollbackWindow time.Duration `mapstructure:"rollbackWindow"`
+	ConfirmURL     string        `mapstructure:"confirmURL"`
+	RollbackURL    string        `mapstructure:"rollbackURL"`
+}
+
+// InvitationConfig configures invitation-based registration
+type InvitationConfig struct {
+	TTL time.Duration `mapstructure:"ttl"`
+	// AcceptURL is the frontend registration page; invite emails are only sent when it is set
+	AcceptURL string `mapstructure:"acceptURL"`
+}
+
+// StorageConfig configures where files such as avatars are stored. Driver is "local"
+// (files below LocalDir) or "s3".
+type StorageConfig struct {
+	Driver            string `mapstructure:"driver"`
+	LocalDir          string `mapstructure:"localDir"`
+	S3Endpoint        string `mapstructure:"s3Endpoint"`
+	S3Region          string `mapstructure:"s3Region"`
+	S3Bucket          string `mapstructure:"s3Bucket"`
+	S3AccessKeyID     string `mapstructure:"s3AccessKeyId"`
+	S3SecretAccessKey string `mapstructure:"s3SecretAccessKey"`
+	S3PathStyle       bool   `mapstructure:"s3PathStyle"`
+}
+
+// BackupConfig configures pg_dump backups of the PostgreSQL database to the file storage.
+// `server backup` works whenever the driver is postgres; Enabled adds the backup-database
+// runbook action and, with Interval, periodic backups by the server.
+type BackupConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Prefix 备份在文件存储中的键前缀，备份保存为 <Prefix><UTC时间>.dump
+	Prefix        string `mapstructure:"prefix"`
+	PgDumpPath    string `mapstructure:"pgDumpPath"`
+	PgRestorePath string `mapstructure:"pgRestorePath"`
+	// Interval 定时备份间隔，0 表示不定时备份；每个实例都会执行，多实例部署时只应在一个实例上设置
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// StartupConfig configures how long startup waits for the database and Redis to accept
+// connections. A failed attempt is retried up to ConnectRetries times, waiting RetryBackoff
+// and then twice as long after every attempt, at most MaxRetryBackoff. Without retries
+// startup fails on the first error.
+type StartupConfig struct {
+	ConnectRetries  int           `mapstructure:"connectRetries"`
+	RetryBackoff    time.Duration `mapstructure:"retryBackoff"`
+	MaxRetryBackoff time.Duration `mapstructure:"maxRetryBackoff"`
+}
+
+// I18nConfig configures the translation of error messages. Responses are translated into
+// the locale saved in the user's preferences, else the best match of Accept-Language, else
+// DefaultLocale. CatalogDir holds extra catalogs, one <locale>.json file per language.
+type I18nConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	DefaultLocale string `mapstructure:"defaultLocale"`
+	CatalogDir    string `mapstructure:"catalogDir"`
+}
+
+// AvatarConfig configures avatars. Provider (identicon, initials or gravatar) generates the
+// avatars of users without an uploaded one. Uploads are scaled down to UploadSize and served
+// from UploadBaseURL, which can point at a CDN or public bucket in front of the storage.
+type AvatarConfig struct {
+	Provider         string `mapstructure:"provider"`
+	Size             int    `mapstructure:"size"`
+	GravatarFallback string `mapstructure:"gravatarFallback"`
+	UploadSize       int    `mapstructure:"uploadSize"`
+	UploadMaxBytes   int64  `mapstructure:"uploadMaxBytes"`
+	UploadBaseURL    string `mapstructure:"uploadBaseUrl"`
+}
+
+// SMSConfig configures how SMS messages are sent. Driver is "log" (development) or "twilio".
+type SMSConfig struct {
+	Driver                    string `mapstructure:"driver"`
+	TwilioAccountSID          string `mapstructure:"twilioAccountSid"`
+	TwilioAuthToken           string `mapstructure:"twilioAuthToken"`
+	TwilioFrom                string `mapstructure:"twilioFrom"`
+	TwilioMessagingServiceSID string `mapstructure:"twilioMessagingServiceSid"`
+}
+
+// OTPConfig configures login with a one-time code sent by SMS
+type OTPConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	CodeLength  int           `mapstructure:"codeLength"`
+	CodeTTL     time.Duration `mapstructure:"codeTTL"`
+	MaxAttempts int           `mapstructure:"maxAttempts"`
+}
+
+// RunbookConfig configures the admin endpoints for operational actions.
+// Each admin may run each action at most PerActionLimit times per PerActionWindow.
+type RunbookConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	PerActionLimit  int           `mapstructure:"perActionLimit"`
+	PerActionWindow time.Duration `mapstructure:"perActionWindow"`
+}
+
+// OAuthConfig configures the built-in OAuth2 authorization server for first-party apps
+type OAuthConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	CodeTTL time.Duration `mapstructure:"codeTTL"`
+}
+
+// GuestConfig configures anonymous guest tokens. They carry the guest role, have no user
+// record or refresh token, and are only accepted on Routes ("METHOD /full/path").
+type GuestConfig struct {
+	Enabled   bool            `mapstructure:"enabled"`
+	TTL       time.Duration   `mapstructure:"ttl"`
+	Routes    []string        `mapstructure:"routes"`
+	RateLimit RateLimitConfig `mapstructure:"rateLimit"`
+}
+
+// TokenExchangeConfig configures POST /api/v1/auth/token/exchange, which exchanges tokens of
+// trusted external issuers for token pairs of this API
+type TokenExchangeConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Timeout bounds a single JWKS request
+	Timeout time.Duration `mapstructure:"timeout"`
+	// JWKSRefreshInterval is how long fetched issuer keys are used before they are fetched again
+	JWKSRefreshInterval time.Duration               `mapstructure:"jwksRefreshInterval"`
+	Issuers             []TokenExchangeIssuerConfig `mapstructure:"issuers"`
+	RateLimit           RateLimitConfig             `mapstructure:"rateLimit"`
+}
+
+// TokenExchangeIssuerConfig is a trusted issuer and the rules mapping its identities to
+// local users. Tokens are verified with the keys at JWKSURL or, for internal services, with
+// the shared HS256 Secret. RoleMapping keys are lowercased external roles or groups.
+type TokenExchangeIssuerConfig struct {
+	Name                 string              `mapstructure:"name"`
+	Issuer               string              `mapstructure:"issuer"`
+	Audience             string              `mapstructure:"audience"`
+	JWKSURL              string              `mapstructure:"jwksURL"`
+	Secret               string              `mapstructure:"secret"`
+	EmailClaim           string              `mapstructure:"emailClaim"`
+	AllowUnverifiedEmail bool                `mapstructure:"allowUnverifiedEmail"`
+	CreateUsers          bool                `mapstructure:"createUsers"`
+	RoleClaim            string              `mapstructure:"roleClaim"`
+	RoleMapping          map[string][]string `mapstructure:"roleMapping"`
+	DefaultRoles         []string            `mapstructure:"defaultRoles"`
+	Scopes               []string            `mapstructure:"scopes"`
+}
+
+// CORSConfig lets browser clients on AllowedOrigins call the API. Origins are exact,
+// "*" or a leading wildcard label such as "https://*.example.com". AllowedHeaders defaults
+// to Authorization, Content-Type and the signing, app key, captcha, request ID and, with
+// database replicas, read primary headers;
+// ExposedHeaders defaults to the request ID header.
+type CORSConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	AllowedOrigins   []string      `mapstructure:"allowedOrigins"`
+	AllowedMethods   []string      `mapstructure:"allowedMethods"`
+	AllowedHeaders   []string      `mapstructure:"allowedHeaders"`
+	ExposedHeaders   []string      `mapstructure:"exposedHeaders"`
+	AllowCredentials bool          `mapstructure:"allowCredentials"`
+	MaxAge           time.Duration `mapstructure:"maxAge"`
+}
+
+// EncryptionConfig holds the keys sensitive fields are encrypted with. Keys are base64-encoded
+// 32-byte AES keys by ID; new values use CurrentKeyID and the others stay readable for rotation.
+// Key IDs are lower-cased like all configuration map keys.
+type EncryptionConfig struct {
+	CurrentKeyID string            `mapstructure:"currentKeyId"`
+	Keys         map[string]string `mapstructure:"keys"`
 }
 
-// Load reads configuration from file or environment variables
+// bindEnv binds the environment variable of every key of the configuration struct t, whose
+// keys are under prefix
+func bindEnv(t reflect.Type, prefix string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			if err := bindEnv(field.Type, key+"."); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := viper.BindEnv(key); err != nil {
+			return fmt.Errorf("failed to bind environment variable of %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// tenantSchemaPattern matches the tenant IDs that make valid schema names with the tenant_
+// prefix, within the 63 bytes PostgreSQL allows
+var tenantSchemaPattern = regexp.MustCompile(`^[a-z0-9_]{1,56}$`)
+
+// EnvPrefix prefixes the environment variables overriding configuration keys. A key's
+// variable is its path in upper case with dots replaced by underscores: server.port is
+// overridden by GINPKG_SERVER_PORT and redis.localCache.ttl by GINPKG_REDIS_LOCALCACHE_TTL.
+const EnvPrefix = "GINPKG"
+
+// Load reads configuration from file, overridden by environment variables (see EnvPrefix).
+// Lists are given comma-separated; maps and lists of objects can only be set in the file.
 func Load(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	// AutomaticEnv only overrides the keys viper already knows, so keys left out of the
+	// file are bound explicitly
+	if err := bindEnv(reflect.TypeOf(Config{}), ""); err != nil {
+		return nil, err
+	}
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -73,12 +789,74 @@ func Load(configPath string) (*Config, error) {
 	}
 
 	// Set defaults if not specified
+	switch config.Database.Driver {
+	case "":
+		config.Database.Driver = "postgres"
+		fallthrough
+	case "postgres":
+		if config.Database.Port == 0 {
+			config.Database.Port = 5432
+		}
+		if err := validatePostgresConfig(&config.Database.Postgres); err != nil {
+			return nil, err
+		}
+	case "mysql":
+		if config.Database.Port == 0 {
+			config.Database.Port = 3306
+		}
+	default:
+		return nil, fmt.Errorf("invalid database.driver %q, must be postgres or mysql", config.Database.Driver)
+	}
+	for i := range config.Database.Replicas {
+		replica := &config.Database.Replicas[i]
+		if replica.Host == "" {
+			return nil, fmt.Errorf("database.replicas[%d] requires host", i)
+		}
+		if replica.Port == 0 {
+			replica.Port = config.Database.Port
+		}
+		if replica.Username == "" {
+			replica.Username = config.Database.Username
+			replica.Password = config.Database.Password
+		}
+	}
+	if config.Database.ReplicaHealthCheckInterval == 0 {
+		config.Database.ReplicaHealthCheckInterval = 10 * time.Second
+	}
+	if config.Database.ReadPrimaryHeader == "" {
+		config.Database.ReadPrimaryHeader = "X-Read-Primary"
+	}
+	switch config.Database.IDStrategy {
+	case "":
+		config.Database.IDStrategy = "uuidv4"
+	case "uuidv4", "uuidv7", "ulid":
+	default:
+		return nil, fmt.Errorf("invalid database.idStrategy %q, must be uuidv4, uuidv7 or ulid", config.Database.IDStrategy)
+	}
+	if err := validateRedisConfig(&config.Redis); err != nil {
+		return nil, err
+	}
+	if config.Startup.ConnectRetries < 0 {
+		return nil, fmt.Errorf("invalid startup.connectRetries %d, must not be negative", config.Startup.ConnectRetries)
+	}
+	if config.Startup.RetryBackoff <= 0 {
+		config.Startup.RetryBackoff = time.Second
+	}
+	if config.Startup.MaxRetryBackoff <= 0 {
+		config.Startup.MaxRetryBackoff = 30 * time.Second
+	}
 	if config.Auth.AccessTokenDuration == 0 {
 		config.Auth.AccessTokenDuration = 24 * time.Hour
 	}
 	if config.Auth.RefreshTokenDuration == 0 {
 		config.Auth.RefreshTokenDuration = 30 * 24 * time.Hour
 	}
+	if config.Auth.RememberMeDuration == 0 {
+		config.Auth.RememberMeDuration = 90 * 24 * time.Hour
+	}
+	if config.Auth.ImpersonationDuration == 0 {
+		config.Auth.ImpersonationDuration = 15 * time.Minute
+	}
 	if config.Security.TimestampValidityWindow == 0 {
 		config.Security.TimestampValidityWindow = 60 * time.Second
 	}
@@ -100,6 +878,597 @@ func Load(configPath string) (*Config, error) {
 	if config.Auth.DefaultAdminPassword == "" {
 		config.Auth.DefaultAdminPassword = "admin123456"
 	}
+	if config.Auth.Issuer == "" {
+		config.Auth.Issuer = "gin-pkg"
+	}
+	if config.Auth.ClockSkewLeeway < 0 || config.Auth.ClockSkewLeeway > 5*time.Minute {
+		return nil, fmt.Errorf("auth.clockSkewLeeway must be between 0 and 5m, got %s", config.Auth.ClockSkewLeeway)
+	}
+	tenants := make(map[string]bool, len(config.Auth.TenantSecrets))
+	for i, tenant := range config.Auth.TenantSecrets {
+		if tenant.TenantID == "" || tenant.Secret == "" {
+			return nil, fmt.Errorf("auth.tenantSecrets[%d] requires tenantId and secret", i)
+		}
+		if tenants[tenant.TenantID] {
+			return nil, fmt.Errorf("auth.tenantSecrets lists tenant %q twice", tenant.TenantID)
+		}
+		tenants[tenant.TenantID] = true
+		// 租户ID是 schema 名的一部分
+		if config.Database.SchemaPerTenant && !tenantSchemaPattern.MatchString(tenant.TenantID) {
+			return nil, fmt.Errorf("auth.tenantSecrets[%d].tenantId %q must be at most 56 lower-case letters, digits and underscores with database.schemaPerTenant", i, tenant.TenantID)
+		}
+	}
+	if config.Database.SchemaPerTenant {
+		if config.Database.Driver != "postgres" {
+			return nil, fmt.Errorf("database.schemaPerTenant requires the postgres driver")
+		}
+		if len(config.Database.Replicas) > 0 {
+			return nil, fmt.Errorf("database.schemaPerTenant cannot be combined with database.replicas")
+		}
+		if config.Database.TenantHeader == "" {
+			config.Database.TenantHeader = "X-Tenant-ID"
+		}
+	}
+	if config.Auth.TokenMode == "" {
+		config.Auth.TokenMode = "jwt"
+	}
+	if config.Auth.TokenMode != "jwt" && config.Auth.TokenMode != "opaque" {
+		return nil, fmt.Errorf("invalid auth.tokenMode %q, must be jwt or opaque", config.Auth.TokenMode)
+	}
+	if encryption := config.Auth.TokenEncryption; encryption.AccessTokens || encryption.RefreshTokens {
+		if encryption.Key == "" {
+			return nil, fmt.Errorf("auth.tokenEncryption.key is required when token encryption is enabled")
+		}
+		// 不透明令牌本身不含声明，无需加密
+		if config.Auth.TokenMode != "jwt" {
+			return nil, fmt.Errorf("auth.tokenEncryption requires auth.tokenMode jwt")
+		}
+	}
+	if config.Auth.RoleScopes == nil {
+		config.Auth.RoleScopes = map[string][]string{
+			"admin": {"*"},
+			"user":  {"profile:read", "profile:write"},
+		}
+	}
+
+	if config.Auth.PasswordPolicy.MinLength == 0 {
+		config.Auth.PasswordPolicy.MinLength = 8
+	}
+	if config.Auth.PasswordPolicy.MaxLength == 0 {
+		config.Auth.PasswordPolicy.MaxLength = 72 // bcrypt only uses the first 72 bytes
+	}
+	if config.Auth.PasswordHashing.Algorithm == "" {
+		config.Auth.PasswordHashing.Algorithm = "bcrypt"
+	}
+	for _, skip := range config.Security.SkipPaths {
+		if _, err := path.Match(strings.TrimSuffix(skip.Path, "/**"), ""); err != nil || skip.Path == "" {
+			return nil, fmt.Errorf("invalid security.skipPaths pattern %q", skip.Path)
+		}
+	}
+	if config.Security.SignatureSecretOverlap == 0 {
+		config.Security.SignatureSecretOverlap = 24 * time.Hour
+	}
+	if config.Security.SecondarySignatureSecret != "" && config.Security.SecondarySignatureSecret == config.Security.SignatureSecret {
+		return nil, fmt.Errorf("security.secondarySignatureSecret must differ from security.signatureSecret")
+	}
+	switch config.Security.Mode {
+	case "":
+		config.Security.Mode = "enforce"
+	case "enforce", "log-only", "off":
+	default:
+		return nil, fmt.Errorf("invalid security.mode %q, must be enforce, log-only or off", config.Security.Mode)
+	}
+	if err := validateNonceStoreConfig(&config.Security.NonceStore, config.Database.Driver); err != nil {
+		return nil, err
+	}
+	if config.Security.ReplayProtection.NonceOptional && !config.Security.ReplayProtection.Enabled {
+		return nil, fmt.Errorf("security.replayProtection.nonceOptional requires security.replayProtection.enabled")
+	}
+	setRateLimitDefaults(&config.Security.LoginRateLimit)
+	setRateLimitDefaults(&config.Security.RegisterRateLimit)
+	setRateLimitDefaults(&config.Security.OTPRateLimit)
+	setRateLimitDefaults(&config.Guest.RateLimit)
+	if config.Guest.TTL == 0 {
+		config.Guest.TTL = 15 * time.Minute
+	}
+	if config.Server.Environment == "" {
+		config.Server.Environment = "development"
+	}
+	if config.Server.RequestIDHeader == "" {
+		config.Server.RequestIDHeader = "X-Request-ID"
+	}
+	if config.Server.Environment == "production" && config.Security.Mode != "enforce" {
+		return nil, fmt.Errorf("security.mode must be enforce in production, got %q", config.Security.Mode)
+	}
+	if config.Server.Environment == "production" && config.Redis.TLS.InsecureSkipVerify {
+		return nil, fmt.Errorf("redis.tls.insecureSkipVerify cannot be set in production")
+	}
+	if config.Security.StrictJSON.MaxBodyBytes == 0 {
+		config.Security.StrictJSON.MaxBodyBytes = 1 << 20
+	}
+	if config.Security.StrictJSON.MaxDepth == 0 {
+		config.Security.StrictJSON.MaxDepth = 10
+	}
+	if config.Security.Captcha.Timeout == 0 {
+		config.Security.Captcha.Timeout = 5 * time.Second
+	}
+	if config.Security.Captcha.HeaderName == "" {
+		config.Security.Captcha.HeaderName = "X-Captcha-Token"
+	}
+	setSecurityNameDefaults(&config.Security.Names)
+	if config.Security.AppCredentials.HeaderName == "" {
+		config.Security.AppCredentials.HeaderName = config.Security.Names.HeaderPrefix + "App-Key"
+	}
+	if config.Security.AppCredentials.CacheTTL == 0 {
+		config.Security.AppCredentials.CacheTTL = 5 * time.Minute
+	}
+	if config.ServiceAccount.HeaderName == "" {
+		config.ServiceAccount.HeaderName = "X-Service-Token"
+	}
+	if err := setTokenCookieDefaults(&config); err != nil {
+		return nil, err
+	}
+	if config.Auth.RevocationCache.TTL == 0 {
+		config.Auth.RevocationCache.TTL = 5 * time.Second
+	}
+	if config.Auth.RefreshTokenStore.PurgeInterval == 0 {
+		config.Auth.RefreshTokenStore.PurgeInterval = time.Hour
+	}
+	if config.Auth.RefreshTokenStore.Retention < 0 || config.Auth.RefreshTokenStore.PurgeInterval < 0 {
+		return nil, fmt.Errorf("auth.refreshTokenStore.retention and purgeInterval cannot be negative")
+	}
+	if err := setTokenBindingDefaults(&config.Auth.TokenBinding); err != nil {
+		return nil, err
+	}
+	if err := setCORSDefaults(&config); err != nil {
+		return nil, err
+	}
+	if err := setTokenExchangeDefaults(&config.TokenExchange); err != nil {
+		return nil, err
+	}
+	if len(config.Encryption.Keys) > 0 {
+		config.Encryption.CurrentKeyID = strings.ToLower(config.Encryption.CurrentKeyID)
+		if _, ok := config.Encryption.Keys[config.Encryption.CurrentKeyID]; !ok {
+			return nil, fmt.Errorf("encryption.currentKeyId %q is not one of encryption.keys", config.Encryption.CurrentKeyID)
+		}
+	}
+	if config.Users.DeletedRetention == 0 {
+		config.Users.DeletedRetention = 30 * 24 * time.Hour
+	}
+	if config.Users.PurgeInterval == 0 {
+		config.Users.PurgeInterval = 24 * time.Hour
+	}
+	if config.Users.ImportMaxBytes == 0 {
+		config.Users.ImportMaxBytes = 10 << 20
+	}
+	if config.Users.ImportMaxRows == 0 {
+		config.Users.ImportMaxRows = 10000
+	}
+	if config.Users.ImportAsyncRows == 0 {
+		config.Users.ImportAsyncRows = 100
+	}
+	if config.Users.ImportJobRetention == 0 {
+		config.Users.ImportJobRetention = 24 * time.Hour
+	}
+	if config.Users.LastSeenWriteInterval == 0 {
+		config.Users.LastSeenWriteInterval = time.Minute
+	}
+	if config.Users.LastSeenFlushInterval == 0 {
+		config.Users.LastSeenFlushInterval = time.Minute
+	}
+	if config.Users.OnlineWindow == 0 {
+		config.Users.OnlineWindow = 5 * time.Minute
+	}
+	if config.Users.ActivityQueueSize == 0 {
+		config.Users.ActivityQueueSize = 1000
+	}
+	if config.Orgs.HeaderName == "" {
+		config.Orgs.HeaderName = "X-Org-ID"
+	}
+	if config.ServiceAccount.MaxLifetime == 0 {
+		config.ServiceAccount.MaxLifetime = 365 * 24 * time.Hour
+	}
+	if config.ServiceAccount.RotationReminder == 0 {
+		config.ServiceAccount.RotationReminder = 14 * 24 * time.Hour
+	}
+	if config.ServiceAccount.ReminderInterval == 0 {
+		config.ServiceAccount.ReminderInterval = 24 * time.Hour
+	}
+	if config.Settings.FreshFor == 0 {
+		config.Settings.FreshFor = 30 * time.Second
+	}
+	if config.Settings.StaleFor == 0 {
+		config.Settings.StaleFor = 10 * time.Minute
+	}
+	if config.Settings.RefreshTimeout == 0 {
+		config.Settings.RefreshTimeout = 2 * time.Second
+	}
+	if config.Guardrails.CheckInterval == 0 {
+		config.Guardrails.CheckInterval = 15 * time.Second
+	}
+	if config.Guardrails.DumpDir == "" {
+		config.Guardrails.DumpDir = "logs/dumps"
+	}
+	if config.Guardrails.DumpCooldown == 0 {
+		config.Guardrails.DumpCooldown = 10 * time.Minute
+	}
+	if config.Mail.Driver == "" {
+		config.Mail.Driver = "log"
+	}
+	if config.Mail.Port == 0 {
+		config.Mail.Port = 587
+	}
+	if config.EmailChange.ConfirmTTL == 0 {
+		config.EmailChange.ConfirmTTL = 24 * time.Hour
+	}
+	if config.EmailChange.RollbackWindow == 0 {
+		config.EmailChange.RollbackWindow = 72 * time.Hour
+	}
+	if config.SMS.Driver == "" {
+		config.SMS.Driver = "log"
+	}
+	if config.OTP.CodeLength == 0 {
+		config.OTP.CodeLength = 6
+	}
+	if config.OTP.CodeTTL == 0 {
+		config.OTP.CodeTTL = 5 * time.Minute
+	}
+	if config.OTP.MaxAttempts == 0 {
+		config.OTP.MaxAttempts = 5
+	}
+	if config.Runbook.PerActionWindow == 0 {
+		config.Runbook.PerActionWindow = time.Hour
+	}
+	if config.OAuth.CodeTTL == 0 {
+		config.OAuth.CodeTTL = time.Minute
+	}
+	if config.Invitation.TTL == 0 {
+		config.Invitation.TTL = 7 * 24 * time.Hour
+	}
+	if config.Storage.Driver == "" {
+		config.Storage.Driver = "local"
+	}
+	switch config.Storage.Driver {
+	case "local":
+	case "s3":
+		if config.Storage.S3Bucket == "" || config.Storage.S3Region == "" {
+			return nil, fmt.Errorf("storage.s3Bucket and storage.s3Region are required when storage.driver is s3")
+		}
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", config.Storage.Driver)
+	}
+	if config.Storage.LocalDir == "" {
+		config.Storage.LocalDir = "data/storage"
+	}
+	if config.Backup.Enabled && config.Database.Driver != "postgres" {
+		return nil, fmt.Errorf("backup.enabled requires the postgres driver")
+	}
+	if config.Backup.Prefix == "" {
+		config.Backup.Prefix = "backups/"
+	}
+	if config.Backup.PgDumpPath == "" {
+		config.Backup.PgDumpPath = "pg_dump"
+	}
+	if config.Backup.PgRestorePath == "" {
+		config.Backup.PgRestorePath = "pg_restore"
+	}
+	if config.Avatar.Provider == "" {
+		config.Avatar.Provider = "identicon"
+	}
+	if config.Avatar.Size == 0 {
+		config.Avatar.Size = 128
+	}
+	if config.Avatar.UploadSize == 0 {
+		config.Avatar.UploadSize = 256
+	}
+	if config.Avatar.UploadMaxBytes == 0 {
+		config.Avatar.UploadMaxBytes = 5 << 20
+	}
+	if config.Avatar.UploadBaseURL == "" {
+		config.Avatar.UploadBaseURL = "/api/v1/avatars"
+	}
+	if config.Metrics.Path == "" {
+		config.Metrics.Path = "/debug/vars"
+	}
+	if config.Health.LivenessPath == "" {
+		config.Health.LivenessPath = "/healthz"
+	}
+	if config.Health.ReadinessPath == "" {
+		config.Health.ReadinessPath = "/readyz"
+	}
+	if config.Health.Timeout <= 0 {
+		config.Health.Timeout = 2 * time.Second
+	}
+	if config.I18n.DefaultLocale == "" {
+		config.I18n.DefaultLocale = "en"
+	}
 
 	return &config, nil
 }
+
+// setRateLimitDefaults fills in missing rate limit windows
+func setRateLimitDefaults(rl *RateLimitConfig) {
+	if rl.PerIPWindow == 0 {
+		rl.PerIPWindow = time.Minute
+	}
+	if rl.PerAccountWindow == 0 {
+		rl.PerAccountWindow = 15 * time.Minute
+	}
+}
+
+// setSecurityNameDefaults derives the unset security header names from the prefix
+func setSecurityNameDefaults(names *SecurityNamesConfig) {
+	if names.HeaderPrefix == "" {
+		names.HeaderPrefix = "X-"
+	}
+	for _, name := range []struct {
+		value    *string
+		fallback string
+	}{
+		{&names.TimestampHeader, names.HeaderPrefix + "Timestamp"},
+		{&names.NonceHeader, names.HeaderPrefix + "Nonce"},
+		{&names.SignHeader, names.HeaderPrefix + "Sign"},
+		{&names.SignVersionHeader, names.HeaderPrefix + "Sign-Version"},
+		{&names.TimestampParam, "timestamp"},
+		{&names.NonceParam, "nonce"},
+		{&names.SignParam, "sign"},
+	} {
+		if *name.value == "" {
+			*name.value = name.fallback
+		}
+	}
+}
+
+// setCORSDefaults fills in the CORS methods and headers from the configured header names
+func setCORSDefaults(config *Config) error {
+	cors := &config.CORS
+	if !cors.Enabled {
+		return nil
+	}
+	if len(cors.AllowedOrigins) == 0 {
+		return fmt.Errorf("cors.allowedOrigins must not be empty when cors is enabled")
+	}
+	if cors.AllowCredentials {
+		for _, origin := range cors.AllowedOrigins {
+			// 携带凭证时允许任意来源等于向所有网站开放登录态
+			if origin == "*" {
+				return fmt.Errorf("cors.allowCredentials cannot be combined with the \"*\" origin")
+			}
+		}
+	}
+	if len(cors.AllowedMethods) == 0 {
+		cors.AllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	}
+	if len(cors.AllowedHeaders) == 0 {
+		names := config.Security.Names
+		cors.AllowedHeaders = []string{
+			"Authorization",
+			"Content-Type",
+			names.TimestampHeader,
+			names.NonceHeader,
+			names.SignHeader,
+			names.SignVersionHeader,
+			config.Security.AppCredentials.HeaderName,
+			config.Security.Captcha.HeaderName,
+			config.Server.RequestIDHeader,
+		}
+		if config.Auth.Cookies.Enabled {
+			cors.AllowedHeaders = append(cors.AllowedHeaders, config.Security.CSRF.HeaderName)
+		}
+		if len(config.Database.Replicas) > 0 {
+			cors.AllowedHeaders = append(cors.AllowedHeaders, config.Database.ReadPrimaryHeader)
+		}
+		if config.Database.SchemaPerTenant {
+			cors.AllowedHeaders = append(cors.AllowedHeaders, config.Database.TenantHeader)
+		}
+	}
+	if len(cors.ExposedHeaders) == 0 {
+		cors.ExposedHeaders = []string{config.Server.RequestIDHeader}
+	}
+	if cors.MaxAge == 0 {
+		cors.MaxAge = 10 * time.Minute
+	}
+	return nil
+}
+
+// setTokenExchangeDefaults fills in the JWKS timeouts and checks the trusted issuers
+func setTokenExchangeDefaults(exchange *TokenExchangeConfig) error {
+	if !exchange.Enabled {
+		return nil
+	}
+	setRateLimitDefaults(&exchange.RateLimit)
+	if exchange.Timeout == 0 {
+		exchange.Timeout = 5 * time.Second
+	}
+	if exchange.JWKSRefreshInterval == 0 {
+		exchange.JWKSRefreshInterval = time.Hour
+	}
+	if len(exchange.Issuers) == 0 {
+		return fmt.Errorf("tokenExchange requires at least one issuer")
+	}
+	names := map[string]bool{}
+	for _, issuer := range exchange.Issuers {
+		if issuer.Name == "" || issuer.Issuer == "" {
+			return fmt.Errorf("tokenExchange.issuers entries require name and issuer")
+		}
+		if names[issuer.Name] {
+			return fmt.Errorf("tokenExchange issuer %q is configured twice", issuer.Name)
+		}
+		names[issuer.Name] = true
+		if (issuer.JWKSURL == "") == (issuer.Secret == "") {
+			return fmt.Errorf("tokenExchange issuer %q requires exactly one of jwksURL and secret", issuer.Name)
+		}
+	}
+	return nil
+}
+
+// setTokenBindingDefaults fills in the binding mode and prefix lengths and checks that
+// something is bound
+func setTokenBindingDefaults(binding *TokenBindingConfig) error {
+	if !binding.Enabled {
+		return nil
+	}
+	switch binding.Mode {
+	case "":
+		binding.Mode = "enforce"
+	case "enforce", "log-only":
+	default:
+		return fmt.Errorf("invalid auth.tokenBinding.mode %q, must be enforce or log-only", binding.Mode)
+	}
+	if !binding.IP && !binding.UserAgent {
+		return fmt.Errorf("auth.tokenBinding requires ip or userAgent")
+	}
+	if binding.IPv4PrefixLength == 0 {
+		binding.IPv4PrefixLength = 24
+	}
+	if binding.IPv6PrefixLength == 0 {
+		binding.IPv6PrefixLength = 64
+	}
+	if binding.IPv4PrefixLength < 0 || binding.IPv4PrefixLength > 32 || binding.IPv6PrefixLength < 0 || binding.IPv6PrefixLength > 128 {
+		return fmt.Errorf("auth.tokenBinding prefix lengths must be at most 32 for IPv4 and 128 for IPv6")
+	}
+	return nil
+}
+
+// setTokenCookieDefaults fills in the token cookie and CSRF names and checks the SameSite mode
+func setTokenCookieDefaults(config *Config) error {
+	cookies := &config.Auth.Cookies
+	if !cookies.Enabled {
+		return nil
+	}
+	if cookies.AccessTokenName == "" {
+		cookies.AccessTokenName = "access_token"
+	}
+	if cookies.RefreshTokenName == "" {
+		cookies.RefreshTokenName = "refresh_token"
+	}
+	if cookies.Path == "" {
+		cookies.Path = "/"
+	}
+	if cookies.RefreshPath == "" {
+		cookies.RefreshPath = "/api/v1/auth"
+	}
+	switch cookies.SameSite {
+	case "":
+		cookies.SameSite = "lax"
+	case "lax", "strict":
+	case "none":
+		if !cookies.Secure {
+			return fmt.Errorf("auth.cookies.sameSite none requires auth.cookies.secure")
+		}
+	default:
+		return fmt.Errorf("invalid auth.cookies.sameSite %q, must be lax, strict or none", cookies.SameSite)
+	}
+
+	if config.Security.CSRF.CookieName == "" {
+		config.Security.CSRF.CookieName = "csrf_token"
+	}
+	if config.Security.CSRF.HeaderName == "" {
+		config.Security.CSRF.HeaderName = "X-CSRF-Token"
+	}
+	return nil
+}
+
+// validateRedisConfig checks the deployment mode and sets its default
+func validateRedisConfig(cfg *RedisConfig) error {
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = "single"
+	case "single":
+	case "cluster":
+		if len(cfg.Addrs) == 0 {
+			return fmt.Errorf("redis.addrs is required when redis.mode is cluster")
+		}
+		// Redis Cluster 只支持 0 号数据库
+		if cfg.DB != 0 {
+			return fmt.Errorf("redis.db must be 0 when redis.mode is cluster")
+		}
+	case "sentinel":
+		if cfg.Sentinel.MasterName == "" || len(cfg.Sentinel.Addrs) == 0 {
+			return fmt.Errorf("redis.sentinel.masterName and redis.sentinel.addrs are required when redis.mode is sentinel")
+		}
+	default:
+		return fmt.Errorf("invalid redis.mode %q, must be single, cluster or sentinel", cfg.Mode)
+	}
+	if cfg.Sentinel.FailoverTimeout < 0 {
+		return fmt.Errorf("redis.sentinel.failoverTimeout cannot be negative")
+	}
+	if cfg.Sentinel.FailoverTimeout == 0 {
+		cfg.Sentinel.FailoverTimeout = 15 * time.Second
+	}
+	if cfg.DialTimeout < 0 || cfg.ReadTimeout < 0 || cfg.WriteTimeout < 0 {
+		return fmt.Errorf("redis timeouts cannot be negative")
+	}
+	tls := cfg.TLS
+	if !tls.Enabled && (tls.CAFile != "" || tls.CertFile != "" || tls.KeyFile != "" || tls.InsecureSkipVerify) {
+		return fmt.Errorf("redis.tls options are set but redis.tls.enabled is false")
+	}
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		return fmt.Errorf("redis.tls.certFile and redis.tls.keyFile must be set together")
+	}
+	if cfg.Cluster.MaxRedirects < 0 {
+		return fmt.Errorf("redis.cluster.maxRedirects cannot be negative")
+	}
+	if cfg.Fallback.MaxEntries < 0 {
+		return fmt.Errorf("redis.fallback.maxEntries cannot be negative")
+	}
+	if cfg.Fallback.MaxEntries == 0 {
+		cfg.Fallback.MaxEntries = 100000
+	}
+	if cfg.LocalCache.TTL < 0 || cfg.LocalCache.MaxEntries < 0 {
+		return fmt.Errorf("redis.localCache.ttl and redis.localCache.maxEntries cannot be negative")
+	}
+	if cfg.LocalCache.TTL == 0 {
+		cfg.LocalCache.TTL = time.Second
+	}
+	if cfg.LocalCache.MaxEntries == 0 {
+		cfg.LocalCache.MaxEntries = 10000
+	}
+	return nil
+}
+
+// validateNonceStoreConfig checks the nonce store backend and sets its defaults
+func validateNonceStoreConfig(cfg *NonceStoreConfig, databaseDriver string) error {
+	switch cfg.Backend {
+	case "":
+		cfg.Backend = "redis"
+	case "redis", "memory":
+	case "postgres":
+		if databaseDriver != "postgres" {
+			return fmt.Errorf("security.nonceStore.backend postgres requires database.driver postgres")
+		}
+	default:
+		return fmt.Errorf("invalid security.nonceStore.backend %q, must be redis, postgres or memory", cfg.Backend)
+	}
+	if cfg.CleanupInterval < 0 || cfg.MaxEntries < 0 {
+		return fmt.Errorf("security.nonceStore.cleanupInterval and maxEntries cannot be negative")
+	}
+	if cfg.CleanupInterval == 0 {
+		cfg.CleanupInterval = 10 * time.Minute
+	}
+	if cfg.MaxEntries == 0 {
+		cfg.MaxEntries = 100000
+	}
+	return nil
+}
+
+// validatePostgresConfig checks the pgx options and sets their defaults
+func validatePostgresConfig(cfg *PostgresConfig) error {
+	switch cfg.QueryExecMode {
+	case "":
+		cfg.QueryExecMode = "cache_statement"
+	case "cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol":
+	default:
+		return fmt.Errorf("invalid database.postgres.queryExecMode %q, must be cache_statement, cache_describe, describe_exec, exec or simple_protocol", cfg.QueryExecMode)
+	}
+	if cfg.StatementCacheCapacity < 0 || cfg.DescriptionCacheCapacity < 0 {
+		return fmt.Errorf("database.postgres cache capacities cannot be negative")
+	}
+	for name := range cfg.RuntimeParams {
+		// 会话时区固定为 UTC，search_path 用于按租户切换 schema
+		if name == "timezone" || name == "search_path" {
+			return fmt.Errorf("database.postgres.runtimeParams cannot set %s", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	file := `
+server:
+  port: 8080
+  readTimeout: 10s
+database:
+  host: localhost
+cors:
+  allowedOrigins: [https://file.example.com]
+`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(viper.Reset)
+
+	// Keys in the file
+	t.Setenv("GINPKG_SERVER_PORT", "9090")
+	t.Setenv("GINPKG_SERVER_READTIMEOUT", "42s")
+	t.Setenv("GINPKG_DATABASE_HOST", "db.internal")
+	t.Setenv("GINPKG_CORS_ALLOWEDORIGINS", "https://app.example.com,https://admin.example.com")
+	// Keys left out of the file, nested several levels deep
+	t.Setenv("GINPKG_REDIS_LOCALCACHE_TTL", "2s")
+	t.Setenv("GINPKG_REDIS_LOCALCACHE_ENABLED", "true")
+	t.Setenv("GINPKG_SERVER_TRUSTEDPROXIES", "10.0.0.0/8")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("server.port = %d, want 9090", cfg.Server.Port)
+	}
+	if cfg.Server.ReadTimeout != 42*time.Second {
+		t.Errorf("server.readTimeout = %v, want 42s", cfg.Server.ReadTimeout)
+	}
+	if cfg.Database.Host != "db.internal" {
+		t.Errorf("database.host = %q, want db.internal", cfg.Database.Host)
+	}
+	if want := []string{"https://app.example.com", "https://admin.example.com"}; !slices.Equal(cfg.CORS.AllowedOrigins, want) {
+		t.Errorf("cors.allowedOrigins = %v, want %v", cfg.CORS.AllowedOrigins, want)
+	}
+	if !cfg.Redis.LocalCache.Enabled || cfg.Redis.LocalCache.TTL != 2*time.Second {
+		t.Errorf("redis.localCache = %+v, want enabled with a 2s TTL", cfg.Redis.LocalCache)
+	}
+	if want := []string{"10.0.0.0/8"}; !slices.Equal(cfg.Server.TrustedProxies, want) {
+		t.Errorf("server.trustedProxies = %v, want %v", cfg.Server.TrustedProxies, want)
+	}
+	// Keys without a variable keep the value of the file
+	if cfg.Server.WriteTimeout != 0 || cfg.Database.Driver != "postgres" {
+		t.Errorf("server.writeTimeout = %v and database.driver = %q, want the file's values and defaults", cfg.Server.WriteTimeout, cfg.Database.Driver)
+	}
+}
@@ -2,6 +2,9 @@ package app
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,35 +12,116 @@ import (
 	"syscall"
 	"time"
 
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
 	"github.com/gin-gonic/gin"
 	"github.com/hewenyu/gin-pkg/config"
+	"github.com/hewenyu/gin-pkg/internal/console"
 	"github.com/hewenyu/gin-pkg/internal/ent"
+	_ "github.com/hewenyu/gin-pkg/internal/ent/runtime" // 注册默认值、校验器和软删除钩子
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
 	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/migration"
 	"github.com/hewenyu/gin-pkg/internal/model"
 	"github.com/hewenyu/gin-pkg/internal/router"
+	"github.com/hewenyu/gin-pkg/internal/service/activity"
+	"github.com/hewenyu/gin-pkg/internal/service/appcredential"
+	"github.com/hewenyu/gin-pkg/internal/service/audit"
 	"github.com/hewenyu/gin-pkg/internal/service/auth"
+	"github.com/hewenyu/gin-pkg/internal/service/emailchange"
 	"github.com/hewenyu/gin-pkg/internal/service/factory"
+	"github.com/hewenyu/gin-pkg/internal/service/group"
+	"github.com/hewenyu/gin-pkg/internal/service/invitation"
+	"github.com/hewenyu/gin-pkg/internal/service/oauth"
+	"github.com/hewenyu/gin-pkg/internal/service/organization"
+	"github.com/hewenyu/gin-pkg/internal/service/otp"
+	"github.com/hewenyu/gin-pkg/internal/service/preference"
+	"github.com/hewenyu/gin-pkg/internal/service/presence"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/internal/service/refreshtoken"
+	"github.com/hewenyu/gin-pkg/internal/service/runbook"
+	"github.com/hewenyu/gin-pkg/internal/service/serviceaccount"
+	"github.com/hewenyu/gin-pkg/internal/service/setting"
+	"github.com/hewenyu/gin-pkg/internal/service/stats"
+	"github.com/hewenyu/gin-pkg/internal/service/tokenexchange"
 	userService "github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/internal/service/userbulk"
+	"github.com/hewenyu/gin-pkg/pkg/auth/captcha"
+	"github.com/hewenyu/gin-pkg/pkg/auth/exchange"
 	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/auth/password"
 	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+	"github.com/hewenyu/gin-pkg/pkg/avatar"
+	"github.com/hewenyu/gin-pkg/pkg/dbbackup"
+	"github.com/hewenyu/gin-pkg/pkg/dbreplica"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/fieldcrypt"
+	"github.com/hewenyu/gin-pkg/pkg/guardrail"
+	"github.com/hewenyu/gin-pkg/pkg/health"
+	"github.com/hewenyu/gin-pkg/pkg/i18n"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
 	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/mailer"
+	"github.com/hewenyu/gin-pkg/pkg/sms"
+	"github.com/hewenyu/gin-pkg/pkg/storage"
 	"github.com/hewenyu/gin-pkg/pkg/util"
-
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/hewenyu/gin-pkg/pkg/util/cache"
+	"github.com/hewenyu/gin-pkg/pkg/util/nonce"
 )
 
 // App represents the application
 type App struct {
-	config          *config.Config
-	router          *gin.Engine
-	dbClient        *ent.Client
+	config   *config.Config
+	router   *gin.Engine
+	dbClient *ent.Client
+	// dbReplicas routes reads to the database replicas; nil when database.replicas is not configured
+	dbReplicas      *dbreplica.Driver
 	redisClient     *util.RedisClient
 	serviceFactory  *factory.ServiceFactory
 	tokenService    jwt.TokenService
 	securityService security.SecurityService
+	nonceService    nonce.NonceService
 	userService     userService.UserService
+	userBulkService userbulk.UserBulkService
 	authService     auth.AuthService
 	server          *http.Server
+
+	serviceAccountService serviceaccount.ServiceAccountService
+	settingService        setting.SettingService
+	emailChangeService    emailchange.EmailChangeService
+	preferenceService     preference.PreferenceService
+	presenceService       presence.PresenceService
+	activityService       activity.ActivityService
+	refreshTokenService   refreshtoken.RefreshTokenService
+	statsService          stats.StatsService
+	storage               storage.Storage
+	avatarProvider        avatar.Provider
+	avatarUploader        *avatar.Uploader
+	captchaVerifier       captcha.Verifier
+	runbookService        runbook.RunbookService
+	otpService            otp.OTPService
+	invitationService     invitation.InvitationService
+	rbacService           rbac.RBACService
+	groupService          group.GroupService
+	orgService            organization.OrganizationService
+	auditService          audit.AuditService
+	oauthService          oauth.OAuthService
+	tokenExchangeService  tokenexchange.TokenExchangeService
+	appCredentialService  appcredential.AppCredentialService
+	// tokenCache caches token revocation lookups; nil when auth.revocationCache is disabled
+	tokenCache *jwt.CachedTokenStore
+	// localCaches are the caches with an in-process LRU in front of Redis by namespace; empty
+	// when redis.localCache is disabled
+	localCaches map[string]*cache.TieredCache
+	// fieldEncryptor encrypts sensitive fields; nil when encryption.keys is not configured
+	fieldEncryptor *fieldcrypt.Encryptor
+	// messageCatalog translates error messages; nil when i18n.enabled is not set
+	messageCatalog *i18n.Catalog
+
+	// stopBackground cancels background jobs started by the application
+	stopBackground context.CancelFunc
+	// activityWritten is closed once the activity writer has written its queue after stopBackground
+	activityWritten chan struct{}
 }
 
 // NewApp creates a new application instance
@@ -50,6 +134,10 @@ func NewApp(configPath string) (*App, error) {
 
 	// 使用我们的日志记录器创建Gin引擎
 	router := logger.GetGinEngine()
+	// 只有可信代理转发的请求才按 X-Forwarded-For 确定客户端 IP，默认不信任任何代理
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid server.trustedProxies: %w", err)
+	}
 
 	return &App{
 		config: cfg,
@@ -57,10 +145,37 @@ func NewApp(configPath string) (*App, error) {
 	}, nil
 }
 
+// Config returns the loaded configuration
+func (a *App) Config() *config.Config {
+	return a.config
+}
+
+// ConsoleEnv returns the services exposed to the developer console. Initialize must have been called.
+func (a *App) ConsoleEnv() console.Env {
+	return console.Env{
+		Config:   a.config,
+		Client:   a.dbClient,
+		Users:    a.userService,
+		UserBulk: a.userBulkService,
+		Tokens:   a.tokenService,
+	}
+}
+
 // Initialize sets up the application components
 func (a *App) Initialize() error {
 	var err error
 
+	// Initialize Redis connection first, as migrations are locked in Redis
+	a.redisClient, err = a.setupRedis()
+	if err != nil {
+		return err
+	}
+	logger.Info("Redis connection established")
+	if fallback := a.config.Redis.Fallback; fallback.Enabled {
+		a.redisClient.EnableFallback(fallback.MaxEntries)
+		logger.Warn("Redis fallback enabled: nonces and token revocations are kept in process memory while Redis is unavailable, and only this instance sees them")
+	}
+
 	// Initialize database connection
 	a.dbClient, err = a.setupDatabase()
 	if err != nil {
@@ -68,54 +183,293 @@ func (a *App) Initialize() error {
 	}
 	logger.Info("Database connection established")
 
-	// Initialize Redis connection
-	a.redisClient, err = a.setupRedis()
+	a.storage, err = a.setupStorage()
 	if err != nil {
 		return err
 	}
-	logger.Info("Redis connection established")
+
+	if len(a.config.Encryption.Keys) > 0 {
+		a.fieldEncryptor, err = a.setupFieldEncryptor()
+		if err != nil {
+			return err
+		}
+		// 加密 ent 模式中标记为加密的字段，如用户手机号
+		schema.SetFieldEncryptor(a.fieldEncryptor)
+		logger.Debug("Field encryptor initialized")
+	}
 
 	// Create service factory
 	a.serviceFactory = factory.NewServiceFactory(a.dbClient, a.redisClient)
 	logger.Info("Service factory created")
 
 	// Initialize services
+	tokenStore := a.serviceFactory.CreateTokenStore()
+	if cacheConfig := a.config.Auth.RevocationCache; cacheConfig.Enabled {
+		a.tokenCache = a.serviceFactory.CreateCachedTokenStore(tokenStore, cacheConfig.TTL, cacheConfig.PubSub)
+		tokenStore = a.tokenCache
+	}
+	tenantSecrets := make(map[string]string, len(a.config.Auth.TenantSecrets))
+	for _, tenant := range a.config.Auth.TenantSecrets {
+		tenantSecrets[tenant.TenantID] = tenant.Secret
+	}
+	tokenEncryption, err := a.tokenEncryption()
+	if err != nil {
+		return err
+	}
+	var refreshTokens jwt.RefreshTokenStore
+	if a.config.Auth.RefreshTokenStore.Enabled {
+		a.refreshTokenService = a.serviceFactory.CreateRefreshTokenService(a.tenants())
+		refreshTokens = a.refreshTokenService
+	}
 	a.tokenService = a.serviceFactory.CreateTokenService(
 		a.config.Auth.AccessTokenSecret,
 		a.config.Auth.RefreshTokenSecret,
 		a.config.Auth.AccessTokenDuration,
 		a.config.Auth.RefreshTokenDuration,
+		a.config.Auth.RememberMeDuration,
+		a.config.Auth.ImpersonationDuration,
 		a.config.Auth.DefaultAccessTokenExp,
 		a.config.Auth.DefaultRefreshTokenExp,
+		a.config.Auth.RoleScopes,
+		a.config.Auth.Issuer,
+		a.config.Auth.Audience,
+		a.config.Auth.ClockSkewLeeway,
+		tenantSecrets,
+		tokenEncryption,
+		a.config.Auth.TokenMode == "opaque",
+		tokenStore,
+		refreshTokens,
 	)
 	logger.Debug("Token service initialized")
 
+	signatures, err := security.NewSignatureRegistry(a.config.Security.SignVersions...)
+	if err != nil {
+		return fmt.Errorf("failed to configure request signing: %w", err)
+	}
+	if a.nonceService, err = a.setupNonceService(); err != nil {
+		return err
+	}
 	a.securityService = a.serviceFactory.CreateSecurityService(
 		a.config.Security.SignatureSecret,
+		a.config.Security.SecondarySignatureSecret,
 		a.config.Security.NonceValidityDuration,
+		signatures,
+		a.nonceService,
 	)
 	logger.Debug("Security service initialized")
 
-	a.userService = a.serviceFactory.CreateUserService(a.tokenService)
+	hashing := a.config.Auth.PasswordHashing
+	hasher, err := password.NewHasher(password.HasherConfig{
+		Algorithm:  hashing.Algorithm,
+		BcryptCost: hashing.BcryptCost,
+		Argon2: password.Argon2Params{
+			Time:    hashing.Argon2Time,
+			Memory:  hashing.Argon2Memory,
+			Threads: hashing.Argon2Threads,
+		},
+		Scrypt: password.ScryptParams{
+			N: hashing.ScryptN,
+			R: hashing.ScryptR,
+			P: hashing.ScryptP,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure password hashing: %w", err)
+	}
+
+	policy := a.config.Auth.PasswordPolicy
+	a.userService = a.serviceFactory.CreateUserService(a.tokenService, password.Policy{
+		MinLength:           policy.MinLength,
+		MaxLength:           policy.MaxLength,
+		RequireUppercase:    policy.RequireUppercase,
+		RequireLowercase:    policy.RequireLowercase,
+		RequireDigit:        policy.RequireDigit,
+		RequireSymbol:       policy.RequireSymbol,
+		MinCharacterClasses: policy.MinCharacterClasses,
+		DenyCommon:          policy.DenyCommon,
+		DeniedPasswords:     policy.DeniedPasswords,
+		HistorySize:         policy.HistorySize,
+	}, hasher)
+	if ttl := a.config.Users.CacheTTL; ttl > 0 {
+		a.userService = a.serviceFactory.CreateCachedUserService(a.userService, a.createCache("user"), userService.CacheOptions{TTL: ttl})
+	}
 	a.authService = a.serviceFactory.CreateAuthService(a.userService, a.tokenService, a.securityService)
 	logger.Debug("User and auth services initialized")
 
+	a.emailChangeService = a.serviceFactory.CreateEmailChangeService(a.tokenService, hasher, a.setupMailer(), emailchange.Options{
+		ConfirmTTL:     a.config.EmailChange.ConfirmTTL,
+		RollbackWindow: a.config.EmailChange.RollbackWindow,
+		ConfirmURL:     a.config.EmailChange.ConfirmURL,
+		RollbackURL:    a.config.EmailChange.RollbackURL,
+	})
+	logger.Debug("Email change service initialized")
+
+	a.userBulkService = a.serviceFactory.CreateUserBulkService(a.userService, userbulk.Options{
+		MaxRows:      a.config.Users.ImportMaxRows,
+		JobRetention: a.config.Users.ImportJobRetention,
+	})
+
+	a.invitationService = a.serviceFactory.CreateInvitationService(a.userService, a.setupMailer(), invitation.Options{
+		TTL:       a.config.Invitation.TTL,
+		AcceptURL: a.config.Invitation.AcceptURL,
+	})
+
+	a.serviceAccountService = a.serviceFactory.CreateServiceAccountService(a.config.ServiceAccount.MaxLifetime)
+	logger.Debug("Service account service initialized")
+
+	a.settingService = a.serviceFactory.CreateSettingService(cache.SWROptions{
+		FreshFor:       a.config.Settings.FreshFor,
+		StaleFor:       a.config.Settings.StaleFor,
+		RefreshTimeout: a.config.Settings.RefreshTimeout,
+	})
+	// 预热缓存，避免首个请求阻塞在数据库上
+	for _, ctx := range a.schemaContexts(context.Background()) {
+		if err := a.settingService.Warm(ctx); err != nil {
+			logger.Warnf("Failed to warm settings cache: %v", err)
+		}
+	}
+	logger.Debug("Setting service initialized")
+
+	a.rbacService = a.serviceFactory.CreateRBACService(cache.SWROptions{
+		FreshFor:       a.config.Settings.FreshFor,
+		StaleFor:       a.config.Settings.StaleFor,
+		RefreshTimeout: a.config.Settings.RefreshTimeout,
+	})
+	// 同步权限目录与内置角色，新增权限自动授予管理员
+	for _, ctx := range a.schemaContexts(context.Background()) {
+		if err := a.rbacService.Seed(ctx); err != nil {
+			return fmt.Errorf("failed to seed roles and permissions: %w", err)
+		}
+	}
+	logger.Debug("RBAC service initialized")
+
+	a.preferenceService = a.serviceFactory.CreatePreferenceService()
+	if a.config.I18n.Enabled {
+		a.messageCatalog, err = i18n.NewCatalog(a.config.I18n.DefaultLocale, a.config.I18n.CatalogDir)
+		if err != nil {
+			return fmt.Errorf("failed to load message catalogs: %w", err)
+		}
+	}
+	a.activityService = a.serviceFactory.CreateActivityService(activity.Options{
+		QueueSize: a.config.Users.ActivityQueueSize,
+	})
+	a.statsService = a.serviceFactory.CreateStatsService(stats.Options{
+		CacheTTL: a.config.Users.StatsCacheTTL,
+	})
+	a.presenceService = a.serviceFactory.CreatePresenceService(presence.Options{
+		WriteInterval: a.config.Users.LastSeenWriteInterval,
+		OnlineWindow:  a.config.Users.OnlineWindow,
+	})
+	a.orgService = a.serviceFactory.CreateOrganizationService(cache.SWROptions{
+		FreshFor:       a.config.Settings.FreshFor,
+		StaleFor:       a.config.Settings.StaleFor,
+		RefreshTimeout: a.config.Settings.RefreshTimeout,
+	})
+	a.groupService = a.serviceFactory.CreateGroupService()
+	a.auditService = a.serviceFactory.CreateAuditService()
+
+	provider, err := avatar.NewProvider(a.config.Avatar.Provider, a.config.Avatar.Size, a.config.Avatar.GravatarFallback)
+	if err != nil {
+		return err
+	}
+	a.avatarProvider = avatar.NewCachedProvider(provider, a.storage)
+	a.avatarUploader = avatar.NewUploader(a.storage, avatar.UploadOptions{
+		Size:    a.config.Avatar.UploadSize,
+		BaseURL: a.config.Avatar.UploadBaseURL,
+	})
+
+	if captchaCfg := a.config.Security.Captcha; captchaCfg.Register || captchaCfg.Login {
+		a.captchaVerifier, err = captcha.NewVerifier(captchaCfg.Provider, captcha.Options{
+			Secret:   captchaCfg.Secret,
+			MinScore: captchaCfg.MinScore,
+			Timeout:  captchaCfg.Timeout,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if a.config.OTP.Enabled {
+		a.otpService = a.serviceFactory.CreateOTPService(a.tokenService, a.setupSMSSender(), otp.Options{
+			CodeLength:  a.config.OTP.CodeLength,
+			CodeTTL:     a.config.OTP.CodeTTL,
+			MaxAttempts: a.config.OTP.MaxAttempts,
+		})
+	}
+
+	if a.config.Runbook.Enabled {
+		a.runbookService = a.setupRunbook()
+	}
+
+	if a.config.OAuth.Enabled {
+		a.oauthService = a.serviceFactory.CreateOAuthService(a.userService, a.tokenService, oauth.Options{
+			CodeTTL: a.config.OAuth.CodeTTL,
+		})
+	}
+
+	if a.config.TokenExchange.Enabled {
+		a.tokenExchangeService, err = a.setupTokenExchange(hasher)
+		if err != nil {
+			return err
+		}
+	}
+
+	if appCfg := a.config.Security.AppCredentials; appCfg.Enabled {
+		if appCfg.EncryptionKey == "" {
+			return errors.New("security.appCredentials.encryptionKey is required when app credentials are enabled")
+		}
+		a.appCredentialService = a.serviceFactory.CreateAppCredentialService(appcredential.Options{
+			EncryptionKey: appCfg.EncryptionKey,
+			CacheTTL:      appCfg.CacheTTL,
+		}, a.createCache("appcredential"))
+	}
+
 	// 检查并创建默认管理员账户
 	if a.config.Auth.CreateDefaultAdmin {
-		if err := a.ensureAdminUser(); err != nil {
-			logger.Warnf("Failed to create default admin user: %v", err)
+		for _, ctx := range a.schemaContexts(context.Background()) {
+			if err := a.ensureAdminUser(ctx); err != nil {
+				logger.Warnf("Failed to create default admin user: %v", err)
+			}
 		}
 	}
 
 	// Set up routes
-	router.Setup(
-		a.router,
-		a.userService,
-		a.tokenService,
-		a.securityService,
-		a.config.Auth.EnableRegistration,
-		a.config.Security.TimestampValidityWindow,
-	)
+	rateLimits := a.serviceFactory.CreateCache("http")
+	router.Setup(a.router, router.Dependencies{
+		Config:                  a.config,
+		UserService:             a.userService,
+		UserBulkService:         a.userBulkService,
+		TokenService:            a.tokenService,
+		SecurityService:         a.securityService,
+		ServiceAccountService:   a.serviceAccountService,
+		SettingService:          a.settingService,
+		EmailChangeService:      a.emailChangeService,
+		PreferenceService:       a.preferenceService,
+		MessageCatalog:          a.messageCatalog,
+		PresenceService:         a.presenceService,
+		ActivityService:         a.activityService,
+		RefreshTokenService:     a.refreshTokenService,
+		StatsService:            a.statsService,
+		AvatarProvider:          a.avatarProvider,
+		AvatarUploader:          a.avatarUploader,
+		CaptchaVerifier:         a.captchaVerifier,
+		RunbookService:          a.runbookService,
+		OTPService:              a.otpService,
+		InvitationService:       a.invitationService,
+		RBACService:             a.rbacService,
+		GroupService:            a.groupService,
+		OrganizationService:     a.orgService,
+		AuditService:            a.auditService,
+		OAuthService:            a.oauthService,
+		TokenExchangeService:    a.tokenExchangeService,
+		AppCredentialService:    a.appCredentialService,
+		RateLimitCounter:        rateLimits.Increment,
+		SlidingRateLimitCounter: rateLimits.IncrementSliding,
+		ReadinessChecks: []health.Check{
+			// 启用降级存储时 Redis 不可用不影响就绪
+			{Name: "redis", Check: a.redisClient.Ping, Degradable: a.config.Redis.Fallback.Enabled},
+		},
+	})
 	logger.Info("API routes configured")
 
 	// Initialize HTTP server
@@ -130,15 +484,13 @@ func (a *App) Initialize() error {
 	return nil
 }
 
-// ensureAdminUser 检查并创建默认管理员账户
-func (a *App) ensureAdminUser() error {
-	ctx := context.Background()
-
-	// 首先检查管理员账户是否已存在
-	adminEmail := a.config.Auth.DefaultAdminEmail
+// ensureAdminUser 检查并创建默认管理员账户，按 schema 分租户时创建在 ctx 所属租户的 schema 中
+func (a *App) ensureAdminUser(ctx context.Context) error {
+	// 首先检查管理员账户是否已存在；已软删除的管理员不会被重新创建
+	adminEmail := schema.NormalizeEmail(a.config.Auth.DefaultAdminEmail)
 	exists, err := a.dbClient.User.Query().
 		Where(user.Email(adminEmail)).
-		Exist(ctx)
+		Exist(schema.SkipSoftDelete(ctx))
 
 	if err != nil {
 		return fmt.Errorf("failed to check if admin user exists: %w", err)
@@ -158,7 +510,7 @@ func (a *App) ensureAdminUser() error {
 		Email:    a.config.Auth.DefaultAdminEmail,
 		Username: a.config.Auth.DefaultAdminUsername,
 		Password: a.config.Auth.DefaultAdminPassword,
-		Role:     "admin", // 设置为管理员角色
+		Roles:    []string{"admin"}, // 设置为管理员角色
 	}
 
 	// 创建用户
@@ -171,8 +523,245 @@ func (a *App) ensureAdminUser() error {
 	return nil
 }
 
+// startBackgroundJobs starts periodic maintenance jobs
+func (a *App) startBackgroundJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopBackground = cancel
+
+	if a.dbReplicas != nil {
+		go a.dbReplicas.Run(ctx)
+	}
+
+	if a.config.ServiceAccount.Enabled {
+		go a.runServiceAccountRotationReminders(ctx)
+	}
+
+	if a.config.Users.PurgeDeleted {
+		go a.runDeletedUserPurge(ctx)
+	}
+
+	if a.config.Users.TrackLastSeen {
+		go a.runLastSeenFlush(ctx)
+	}
+
+	if a.config.Auth.RefreshTokenStore.Enabled {
+		go a.runRefreshTokenPurge(ctx)
+	}
+
+	if nonces, ok := a.nonceService.(*nonce.NoncePostgresService); ok {
+		go a.runNoncePurge(ctx, nonces)
+	}
+
+	if a.config.Backup.Enabled && a.config.Backup.Interval > 0 {
+		go a.runBackups(ctx)
+	}
+
+	if a.config.Users.TrackActivity {
+		a.activityWritten = make(chan struct{})
+		go func() {
+			defer close(a.activityWritten)
+			a.activityService.Run(ctx)
+		}()
+	}
+
+	if a.tokenCache != nil && a.config.Auth.RevocationCache.PubSub {
+		go a.redisClient.SubscribeRevocations(ctx, a.tokenCache.HandleRevocationEvent, a.tokenCache.Flush)
+	}
+	if a.config.Redis.LocalCache.PubSub {
+		for namespace, local := range a.localCaches {
+			go a.redisClient.SubscribeCacheInvalidations(ctx, namespace, local.HandleInvalidation, local.Flush)
+		}
+	}
+
+	guardrails := a.config.Guardrails
+	if guardrails.GoroutineWatermark > 0 || guardrails.HeapWatermarkMB > 0 {
+		monitor := guardrail.NewMonitor(guardrail.MonitorConfig{
+			GoroutineWatermark: guardrails.GoroutineWatermark,
+			HeapWatermark:      guardrails.HeapWatermarkMB << 20,
+			CheckInterval:      guardrails.CheckInterval,
+			DumpDir:            guardrails.DumpDir,
+			DumpCooldown:       guardrails.DumpCooldown,
+		})
+		go monitor.Run(ctx)
+	}
+}
+
+// runServiceAccountRotationReminders periodically warns about service account tokens nearing expiry
+func (a *App) runServiceAccountRotationReminders(ctx context.Context) {
+	ticker := time.NewTicker(a.config.ServiceAccount.ReminderInterval)
+	defer ticker.Stop()
+
+	for {
+		a.runExclusively(ctx, "service-account-reminders", a.config.ServiceAccount.ReminderInterval, func(ctx context.Context) {
+			var accounts []*ent.ServiceAccount
+			for _, schemaCtx := range a.schemaContexts(ctx) {
+				expiring, err := a.serviceAccountService.ListExpiringServiceAccounts(schemaCtx, a.config.ServiceAccount.RotationReminder)
+				if err != nil {
+					logger.Warnf("Failed to check service account expiry: %v", err)
+				}
+				accounts = append(accounts, expiring...)
+			}
+			for _, account := range accounts {
+				if time.Now().After(account.ExpiresAt) {
+					logger.Warnf("Service account %s (%s) token expired at %s, rotate or revoke it",
+						account.Name, account.ID, account.ExpiresAt.Format(time.RFC3339))
+					continue
+				}
+				logger.Warnf("Service account %s (%s) token expires at %s, rotate it soon",
+					account.Name, account.ID, account.ExpiresAt.Format(time.RFC3339))
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDeletedUserPurge periodically removes users that were soft-deleted longer than the retention period
+func (a *App) runDeletedUserPurge(ctx context.Context) {
+	ticker := time.NewTicker(a.config.Users.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		a.runExclusively(ctx, "purge-deleted-users", a.config.Users.PurgeInterval, func(ctx context.Context) {
+			for _, schemaCtx := range a.schemaContexts(ctx) {
+				purged, err := a.userService.PurgeDeletedUsers(schemaCtx, time.Now().Add(-a.config.Users.DeletedRetention))
+				if err != nil {
+					logger.Warnf("Failed to purge deleted users: %v", err)
+				} else if purged > 0 {
+					logger.Infof("Purged %d deleted users", purged)
+				}
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runRefreshTokenPurge periodically removes the recorded refresh tokens that expired longer
+// than the retention period ago
+func (a *App) runRefreshTokenPurge(ctx context.Context) {
+	storeConfig := a.config.Auth.RefreshTokenStore
+	ticker := time.NewTicker(storeConfig.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		a.runExclusively(ctx, "purge-refresh-tokens", storeConfig.PurgeInterval, func(ctx context.Context) {
+			for _, schemaCtx := range a.schemaContexts(ctx) {
+				purged, err := a.refreshTokenService.PurgeExpired(schemaCtx, time.Now().Add(-storeConfig.Retention))
+				if err != nil {
+					logger.Warnf("Failed to purge expired refresh tokens: %v", err)
+				} else if purged > 0 {
+					logger.Infof("Purged %d expired refresh tokens", purged)
+				}
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNoncePurge periodically removes the nonces kept in the database that expired unused
+func (a *App) runNoncePurge(ctx context.Context, nonces *nonce.NoncePostgresService) {
+	ticker := time.NewTicker(a.config.Security.NonceStore.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		a.runExclusively(ctx, "purge-nonces", a.config.Security.NonceStore.CleanupInterval, func(ctx context.Context) {
+			purged, err := nonces.PurgeExpired(ctx)
+			if err != nil {
+				logger.Warnf("Failed to purge expired nonces: %v", err)
+			} else if purged > 0 {
+				logger.Debugf("Purged %d expired nonces", purged)
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runExclusively runs fn as the job of the current period of interval, unless another
+// instance already claimed that period, so replicas running the same periodic job do not
+// repeat each other's work. The claim is kept until the period ends. Without Redis the run is
+// skipped, as the other instances might do it too.
+func (a *App) runExclusively(ctx context.Context, job string, interval time.Duration, fn func(ctx context.Context)) {
+	period := time.Now().UnixMilli() / max(interval.Milliseconds(), 1)
+	lock, err := a.redisClient.TryLock(ctx, fmt.Sprintf("job:%s:%d", job, period), interval)
+	if errors.Is(err, util.ErrLockHeld) {
+		logger.Debugf("Skipping %s, another instance runs it in this period", job)
+		return
+	}
+	if err != nil {
+		logger.Warnf("Failed to claim %s, skipping it: %v", job, err)
+		return
+	}
+	defer lock.Stop()
+	fn(lock.Context())
+}
+
+// runLastSeenFlush periodically writes the last-seen times buffered in Redis to the database
+func (a *App) runLastSeenFlush(ctx context.Context) {
+	ticker := time.NewTicker(a.config.Users.LastSeenFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := a.presenceService.Flush(ctx); err != nil {
+			logger.Warnf("Failed to flush last seen times: %v", err)
+		}
+	}
+}
+
+// runBackups periodically backs up the database, starting one interval after startup so
+// restarts do not each take a backup
+func (a *App) runBackups(ctx context.Context) {
+	ticker := time.NewTicker(a.config.Backup.Interval)
+	defer ticker.Stop()
+	backups := a.backupService()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		a.runExclusively(ctx, "backup", a.config.Backup.Interval, func(ctx context.Context) {
+			start := time.Now()
+			key, err := backups.Backup(ctx)
+			if err != nil {
+				logger.Errorf("Failed to back up the database: %v", err)
+				return
+			}
+			logger.Infof("Backed up the database to %s in %s", key, time.Since(start).Round(time.Millisecond))
+		})
+	}
+}
+
 // Run starts the application
 func (a *App) Run() error {
+	a.startBackgroundJobs()
+
 	// Start HTTP server in a goroutine
 	go func() {
 		logger.Infof("Server listening on port %d", a.config.Server.Port)
@@ -202,6 +791,16 @@ func (a *App) Run() error {
 
 // Cleanup performs cleanup operations
 func (a *App) Cleanup() {
+	if a.stopBackground != nil {
+		a.stopBackground()
+	}
+	if a.activityWritten != nil {
+		// 关闭数据库前等待写入队列中剩余的活动记录
+		<-a.activityWritten
+	}
+	if a.nonceService != nil {
+		a.nonceService.Close()
+	}
 	if a.dbClient != nil {
 		a.dbClient.Close()
 		logger.Debug("Database connection closed")
@@ -216,40 +815,402 @@ func (a *App) Cleanup() {
 	logger.Sync()
 }
 
+// createCache creates the Redis cache of the hot keys under namespace, with an in-process LRU
+// in front when redis.localCache is enabled
+func (a *App) createCache(namespace string) cache.Cache {
+	cfg := a.config.Redis.LocalCache
+	if !cfg.Enabled {
+		return a.serviceFactory.CreateCache(namespace)
+	}
+	local := a.serviceFactory.CreateTieredCache(namespace, cache.TieredCacheOptions{
+		TTL:        cfg.TTL,
+		MaxEntries: cfg.MaxEntries,
+	}, cfg.PubSub)
+	if a.localCaches == nil {
+		a.localCaches = make(map[string]*cache.TieredCache)
+	}
+	a.localCaches[namespace] = local
+	return local
+}
+
 // setupDatabase initializes the database connection
 func (a *App) setupDatabase() (*ent.Client, error) {
-	dbURL := fmt.Sprintf(
-		"postgresql://%s:%s@%s:%d/%s?sslmode=%s",
-		a.config.Database.Username,
-		a.config.Database.Password,
-		a.config.Database.Host,
-		a.config.Database.Port,
-		a.config.Database.Database,
-		a.config.Database.SSLMode,
-	)
-	client, err := ent.Open(a.config.Database.Driver, dbURL)
+	if err := idgen.Set(a.config.Database.IDStrategy); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if !a.config.Database.SkipMigrations {
+		lock, err := a.lockMigrations(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := lock.Unlock(context.Background()); err != nil {
+				logger.Warnf("Failed to release the migration lock: %v", err)
+			}
+		}()
+		// 锁丢失时中止迁移，避免与其他实例同时执行
+		ctx = lock.Context()
+	}
+	client, replicas, err := openDatabase(ctx, a.config.Database, a.config.Startup, a.tenants())
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
 	}
+	a.dbReplicas = replicas
+	return client, nil
+}
 
-	// Run schema migrations
-	if err := client.Schema.Create(context.Background()); err != nil {
-		return nil, fmt.Errorf("failed to run database migrations: %w", err)
+const (
+	// migrationLock is the name of the lock held while migrating the database
+	migrationLock = "migrate"
+	// migrationLockTTL is how long the migration lock outlives an instance that died holding it
+	migrationLockTTL = 30 * time.Second
+)
+
+// lockMigrations takes the lock in Redis that lets only one of the instances starting at the
+// same time migrate the database, waiting while another instance holds it
+func (a *App) lockMigrations(ctx context.Context) (*util.Lock, error) {
+	lock, err := a.redisClient.TryLock(ctx, migrationLock, migrationLockTTL)
+	if errors.Is(err, util.ErrLockHeld) {
+		logger.Info("Waiting for another instance to finish migrating the database")
+		lock, err = a.redisClient.Lock(ctx, migrationLock, migrationLockTTL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock database migrations: %w", err)
 	}
+	return lock, nil
+}
 
-	return client, nil
+// SchemaMigrator migrates a schema of the database: the shared one or that of a tenant
+type SchemaMigrator struct {
+	// Schema is the name of the schema, public for the shared one
+	Schema string
+	*migration.Migrator
+	drv *entsql.Driver
+}
+
+// MigrateSchema runs the ent schema migration, which creates missing tables, columns and
+// indexes
+func (m SchemaMigrator) MigrateSchema(ctx context.Context) error {
+	if err := ent.NewClient(ent.Driver(m.drv)).Schema.Create(ctx); err != nil {
+		return fmt.Errorf("failed to run database migrations: %w", err)
+	}
+	return nil
+}
+
+// Migrators connects to the database without migrating it and returns the migrators of the
+// shared schema and of the schema of every tenant, for the migrate command. Missing tenant
+// schemas are created. Cleanup closes the connections.
+func (a *App) Migrators(ctx context.Context) ([]SchemaMigrator, error) {
+	drv, err := waitForDatabase(ctx, a.config.Database, a.config.Startup)
+	if err != nil {
+		return nil, err
+	}
+	tenants := dbtenant.New(drv, func(tenant string) (dialect.Driver, error) {
+		return connectTenantDatabase(a.config.Database, tenant)
+	})
+	a.dbClient = ent.NewClient(ent.Driver(tenants))
+
+	migrators := []SchemaMigrator{{Schema: "public", Migrator: newMigrator(drv), drv: drv}}
+	for _, tenant := range a.tenants() {
+		if err := createTenantSchema(ctx, drv, tenant); err != nil {
+			return nil, err
+		}
+		tenantDrv, err := tenants.Tenant(tenant)
+		if err != nil {
+			return nil, err
+		}
+		sqlDrv := tenantDrv.(*entsql.Driver)
+		migrators = append(migrators, SchemaMigrator{
+			Schema:   dbtenant.Schema(tenant),
+			Migrator: newMigrator(sqlDrv),
+			drv:      sqlDrv,
+		})
+	}
+	return migrators, nil
+}
+
+// schemaContexts returns ctx using the shared schema followed by ctx using the schema of each
+// tenant, for startup and background work that must cover every schema
+func (a *App) schemaContexts(ctx context.Context) []context.Context {
+	contexts := []context.Context{ctx}
+	for _, tenant := range a.tenants() {
+		contexts = append(contexts, dbtenant.With(ctx, tenant))
+	}
+	return contexts
+}
+
+// tenants returns the tenants with their own database schema, none unless
+// database.schemaPerTenant is set
+func (a *App) tenants() []string {
+	if !a.config.Database.SchemaPerTenant {
+		return nil
+	}
+	tenants := make([]string, 0, len(a.config.Auth.TenantSecrets))
+	for _, tenant := range a.config.Auth.TenantSecrets {
+		tenants = append(tenants, tenant.TenantID)
+	}
+	return tenants
+}
+
+// setupMailer creates the mailer selected by the configuration
+func (a *App) setupMailer() mailer.Mailer {
+	if a.config.Mail.Driver == "smtp" {
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     a.config.Mail.Host,
+			Port:     a.config.Mail.Port,
+			Username: a.config.Mail.Username,
+			Password: a.config.Mail.Password,
+			From:     a.config.Mail.From,
+		})
+	}
+	return mailer.NewLogMailer()
+}
+
+// setupRunbook creates the runbook service with the operational actions exposed to admins
+func (a *App) setupRunbook() runbook.RunbookService {
+	flushers := map[string]runbook.CacheFlusher{
+		"settings": func(ctx context.Context) (int64, error) {
+			a.settingService.ClearCache()
+			return -1, nil
+		},
+	}
+	for _, namespace := range util.Namespaces {
+		flushers["redis:"+namespace] = func(ctx context.Context) (int64, error) {
+			return a.redisClient.FlushNamespace(ctx, namespace)
+		}
+	}
+
+	seeders := map[string]runbook.Seeder{
+		"default-admin": a.ensureAdminUser,
+	}
+
+	actions := []runbook.Action{
+		runbook.FlushCacheAction(flushers),
+		runbook.RotateSignatureSecretAction(a.securityService, a.config.Security.NextSignatureSecret, a.config.Security.SignatureSecretOverlap),
+		runbook.SeedAction(seeders),
+	}
+	if a.config.Backup.Enabled {
+		actions = append(actions, runbook.BackupDatabaseAction(a.backupService().Backup))
+	}
+	return runbook.NewRunbookService(actions...)
+}
+
+// setupStorage creates the file storage selected by the configuration
+func (a *App) setupStorage() (storage.Storage, error) {
+	if a.config.Storage.Driver == "s3" {
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:        a.config.Storage.S3Endpoint,
+			Region:          a.config.Storage.S3Region,
+			Bucket:          a.config.Storage.S3Bucket,
+			AccessKeyID:     a.config.Storage.S3AccessKeyID,
+			SecretAccessKey: a.config.Storage.S3SecretAccessKey,
+			PathStyle:       a.config.Storage.S3PathStyle,
+		})
+	}
+	return storage.NewLocalStorage(a.config.Storage.LocalDir)
+}
+
+// backupService creates the service backing up the database to the file storage
+func (a *App) backupService() *dbbackup.Service {
+	db := a.config.Database
+	return dbbackup.New(dbbackup.Connection{
+		Host:     db.Host,
+		Port:     db.Port,
+		Username: db.Username,
+		Password: db.Password,
+		Database: db.Database,
+		SSLMode:  db.SSLMode,
+	}, a.storage, dbbackup.Options{
+		Prefix:        a.config.Backup.Prefix,
+		PgDumpPath:    a.config.Backup.PgDumpPath,
+		PgRestorePath: a.config.Backup.PgRestorePath,
+	})
+}
+
+// BackupService returns the service backing up the database to the file storage without
+// initializing the application, for the backup command
+func (a *App) BackupService() (*dbbackup.Service, error) {
+	if a.config.Database.Driver != "postgres" {
+		return nil, fmt.Errorf("backups require the postgres driver")
+	}
+	if a.storage == nil {
+		store, err := a.setupStorage()
+		if err != nil {
+			return nil, err
+		}
+		a.storage = store
+	}
+	return a.backupService(), nil
+}
+
+// setupSMSSender creates the SMS sender selected by the configuration
+func (a *App) setupSMSSender() sms.Sender {
+	if a.config.SMS.Driver == "twilio" {
+		return sms.NewTwilioSender(sms.TwilioConfig{
+			AccountSID:          a.config.SMS.TwilioAccountSID,
+			AuthToken:           a.config.SMS.TwilioAuthToken,
+			From:                a.config.SMS.TwilioFrom,
+			MessagingServiceSID: a.config.SMS.TwilioMessagingServiceSID,
+		})
+	}
+	return sms.NewLogSender()
+}
+
+// setupTokenExchange creates the token exchange service from the trusted issuers and their mapping rules
+func (a *App) setupTokenExchange(hasher *password.Hasher) (tokenexchange.TokenExchangeService, error) {
+	cfg := a.config.TokenExchange
+	issuers := make([]exchange.Issuer, 0, len(cfg.Issuers))
+	rules := make([]tokenexchange.Rule, 0, len(cfg.Issuers))
+	for _, issuer := range cfg.Issuers {
+		issuers = append(issuers, exchange.Issuer{
+			Name:     issuer.Name,
+			Issuer:   issuer.Issuer,
+			Audience: issuer.Audience,
+			JWKSURL:  issuer.JWKSURL,
+			Secret:   issuer.Secret,
+		})
+		rules = append(rules, tokenexchange.Rule{
+			Issuer:               issuer.Name,
+			EmailClaim:           issuer.EmailClaim,
+			AllowUnverifiedEmail: issuer.AllowUnverifiedEmail,
+			CreateUsers:          issuer.CreateUsers,
+			RoleClaim:            issuer.RoleClaim,
+			RoleMapping:          issuer.RoleMapping,
+			DefaultRoles:         issuer.DefaultRoles,
+			Scopes:               issuer.Scopes,
+		})
+	}
+
+	verifier, err := exchange.NewVerifier(issuers, exchange.Options{
+		Leeway:              a.config.Auth.ClockSkewLeeway,
+		Timeout:             cfg.Timeout,
+		JWKSRefreshInterval: cfg.JWKSRefreshInterval,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up token exchange: %w", err)
+	}
+	return a.serviceFactory.CreateTokenExchangeService(a.tokenService, verifier, hasher, rules), nil
+}
+
+// tokenEncryption decodes the configured token encryption key
+func (a *App) tokenEncryption() (jwt.TokenEncryption, error) {
+	cfg := a.config.Auth.TokenEncryption
+	if !cfg.AccessTokens && !cfg.RefreshTokens {
+		return jwt.TokenEncryption{}, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(cfg.Key)
+	if err != nil {
+		return jwt.TokenEncryption{}, fmt.Errorf("auth.tokenEncryption.key is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return jwt.TokenEncryption{}, fmt.Errorf("auth.tokenEncryption.key must be 32 bytes, got %d", len(key))
+	}
+	return jwt.TokenEncryption{Key: key, AccessTokens: cfg.AccessTokens, RefreshTokens: cfg.RefreshTokens}, nil
+}
+
+// setupFieldEncryptor loads the configured field encryption keys
+func (a *App) setupFieldEncryptor() (*fieldcrypt.Encryptor, error) {
+	keys := make(map[string][]byte, len(a.config.Encryption.Keys))
+	for id, encoded := range a.config.Encryption.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return fieldcrypt.New(context.Background(), fieldcrypt.StaticKeys(a.config.Encryption.CurrentKeyID, keys))
+}
+
+// setupNonceService creates the nonce store selected by security.nonceStore. The postgres
+// backend gets its own connection to the shared schema, so nonces are shared by every tenant.
+func (a *App) setupNonceService() (nonce.NonceService, error) {
+	cfg := a.config.Security.NonceStore
+	var db *sql.DB
+	switch cfg.Backend {
+	case "postgres":
+		drv, err := connectDatabase(a.config.Database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect the nonce store: %w", err)
+		}
+		db = drv.DB()
+	case "memory":
+		logger.Warn("Nonces are kept in process memory: only this instance accepts the nonces it issued, and they are lost on restart")
+	}
+	return a.serviceFactory.CreateNonceService(cfg.Backend, cfg.MaxEntries, db), nil
 }
 
 // setupRedis initializes the Redis connection
 func (a *App) setupRedis() (*util.RedisClient, error) {
-	redis, err := util.NewRedisClient(
-		a.config.Redis.Host,
-		a.config.Redis.Port,
-		a.config.Redis.Password,
-		a.config.Redis.DB,
-	)
+	conn, err := a.redisConnOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	var redis *util.RedisClient
+	err = waitFor(context.Background(), "Redis", a.config.Startup, func(ctx context.Context) error {
+		var err error
+		cfg := a.config.Redis
+		switch cfg.Mode {
+		case "cluster":
+			redis, err = util.NewRedisClusterClientWithOptions(util.RedisClusterOptions{
+				Addrs:          cfg.Addrs,
+				Password:       cfg.Password,
+				MaxRedirects:   cfg.Cluster.MaxRedirects,
+				ReadOnly:       cfg.Cluster.ReadOnly,
+				RouteByLatency: cfg.Cluster.RouteByLatency,
+				RouteRandomly:  cfg.Cluster.RouteRandomly,
+				Conn:           conn,
+			})
+		case "sentinel":
+			redis, err = util.NewRedisSentinelClient(util.RedisSentinelOptions{
+				MasterName:       cfg.Sentinel.MasterName,
+				SentinelAddrs:    cfg.Sentinel.Addrs,
+				SentinelUsername: cfg.Sentinel.Username,
+				SentinelPassword: cfg.Sentinel.Password,
+				Password:         cfg.Password,
+				DB:               cfg.DB,
+				Conn:             conn,
+				FailoverTimeout:  cfg.Sentinel.FailoverTimeout,
+			})
+		default:
+			redis, err = util.NewRedisClientWithOptions(util.RedisOptions{
+				Host:     cfg.Host,
+				Port:     cfg.Port,
+				Password: cfg.Password,
+				DB:       cfg.DB,
+				Conn:     conn,
+			})
+		}
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 	return redis, nil
 }
+
+// redisConnOptions returns the username, TLS settings and timeouts of Redis connections
+func (a *App) redisConnOptions() (util.RedisConnOptions, error) {
+	cfg := a.config.Redis
+	conn := util.RedisConnOptions{
+		Username:     cfg.Username,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+	if cfg.TLS.Enabled {
+		tlsConfig, err := util.TLSOptions{
+			CAFile:             cfg.TLS.CAFile,
+			CertFile:           cfg.TLS.CertFile,
+			KeyFile:            cfg.TLS.KeyFile,
+			ServerName:         cfg.TLS.ServerName,
+			InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
+		}.Config()
+		if err != nil {
+			return util.RedisConnOptions{}, fmt.Errorf("invalid redis.tls: %w", err)
+		}
+		conn.TLSConfig = tlsConfig
+	}
+	return conn, nil
+}
@@ -0,0 +1,343 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hewenyu/gin-pkg/config"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/migration"
+	"github.com/hewenyu/gin-pkg/pkg/dbreplica"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/querylog"
+	"github.com/hewenyu/gin-pkg/pkg/sqlcomment"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// openDatabase connects to the configured database and, unless database.skipMigrations is
+// set, brings its schema up to date. Either way it refuses a database left dirty by an
+// interrupted migration. With database.replicas configured, the client reads from them
+// through the returned replica driver, which is nil otherwise. It waits for the primary to
+// accept connections as configured by startup. Each of tenants gets its own schema, which is
+// migrated like the shared one, and the client runs the statements of a context in the
+// schema of its tenant.
+func openDatabase(ctx context.Context, cfg config.DatabaseConfig, startup config.StartupConfig, tenants []string) (*ent.Client, *dbreplica.Driver, error) {
+	drv, err := waitForDatabase(ctx, cfg, startup)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := migrateDatabase(ctx, cfg, drv); err != nil {
+		drv.Close()
+		return nil, nil, err
+	}
+	for _, tenant := range tenants {
+		if err := migrateTenantDatabase(ctx, cfg, drv, tenant); err != nil {
+			drv.Close()
+			return nil, nil, err
+		}
+	}
+	if len(tenants) > 0 {
+		return ent.NewClient(ent.Driver(logQueries(cfg, tagQueries(cfg, tenantDriver(cfg, drv, tenants))))), nil, nil
+	}
+	if len(cfg.Replicas) == 0 {
+		return ent.NewClient(ent.Driver(logQueries(cfg, tagQueries(cfg, drv)))), nil, nil
+	}
+
+	replicas := make([]dbreplica.Replica, 0, len(cfg.Replicas))
+	for _, replicaConfig := range cfg.Replicas {
+		replicaCfg := cfg
+		replicaCfg.Host = replicaConfig.Host
+		replicaCfg.Port = replicaConfig.Port
+		replicaCfg.Username = replicaConfig.Username
+		replicaCfg.Password = replicaConfig.Password
+		// 副本暂时不可用不影响启动，由健康检查决定是否使用
+		replicaDrv, err := connectDatabase(replicaCfg)
+		if err != nil {
+			drv.Close()
+			return nil, nil, fmt.Errorf("failed to configure database replica %s: %w", replicaConfig.Host, err)
+		}
+		replicas = append(replicas, dbreplica.Replica{
+			Name:   fmt.Sprintf("%s:%d", replicaConfig.Host, replicaConfig.Port),
+			Driver: replicaDrv,
+			Ping:   replicaDrv.DB().PingContext,
+		})
+	}
+	router := dbreplica.New(drv, replicas, dbreplica.Options{
+		HealthCheckInterval: cfg.ReplicaHealthCheckInterval,
+	})
+	return ent.NewClient(ent.Driver(logQueries(cfg, tagQueries(cfg, router)))), router, nil
+}
+
+// logQueries wraps drv to log its statements when database.logQueries or
+// database.slowQueryThreshold is set
+func logQueries(cfg config.DatabaseConfig, drv dialect.Driver) dialect.Driver {
+	if !cfg.LogQueries && cfg.SlowQueryThreshold <= 0 {
+		return drv
+	}
+	return querylog.New(drv, querylog.Options{
+		Debug:         cfg.LogQueries,
+		SlowThreshold: cfg.SlowQueryThreshold,
+	})
+}
+
+// tagQueries wraps drv to tag its statements with the request ID, route and user of their
+// context when database.tagQueries is set. The logged statements are those before tagging.
+func tagQueries(cfg config.DatabaseConfig, drv dialect.Driver) dialect.Driver {
+	if !cfg.TagQueries {
+		return drv
+	}
+	return sqlcomment.New(drv)
+}
+
+// migrateDatabase brings the schema of the primary up to date, or only checks it when
+// database.skipMigrations is set
+func migrateDatabase(ctx context.Context, cfg config.DatabaseConfig, drv *entsql.Driver) error {
+	migrator := newMigrator(drv)
+
+	if cfg.SkipMigrations {
+		pending, err := migrator.Check(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check database migrations: %w", err)
+		}
+		if pending > 0 {
+			logger.Warnf("%d database migrations are pending, run server migrate up", pending)
+		}
+		return nil
+	}
+
+	// 版本化迁移需在结构迁移之前执行；迁移只在主库上执行
+	if err := migrator.Up(ctx, 0); err != nil {
+		return fmt.Errorf("failed to run database migrations: %w", err)
+	}
+	if err := ent.NewClient(ent.Driver(drv)).Schema.Create(ctx); err != nil {
+		return fmt.Errorf("failed to run database migrations: %w", err)
+	}
+	return nil
+}
+
+// tenantDriver returns a driver running the statements of a tenant's context in the schema
+// of the tenant, and the others on shared. Tenants not in tenants are refused.
+func tenantDriver(cfg config.DatabaseConfig, shared *entsql.Driver, tenants []string) *dbtenant.Driver {
+	known := make(map[string]bool, len(tenants))
+	for _, tenant := range tenants {
+		known[tenant] = true
+	}
+	return dbtenant.New(shared, func(tenant string) (dialect.Driver, error) {
+		if !known[tenant] {
+			return nil, fmt.Errorf("unknown tenant %q", tenant)
+		}
+		ctx := context.Background()
+		var exists bool
+		err := shared.DB().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)`,
+			dbtenant.Schema(tenant)).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up the schema: %w", err)
+		}
+		// 表名未限定 schema 时会回退到 public，schema 不存在时不能使用
+		if !exists {
+			return nil, fmt.Errorf("schema %s does not exist, run server migrate up", dbtenant.Schema(tenant))
+		}
+		return connectTenantDatabase(cfg, tenant)
+	})
+}
+
+// migrateTenantDatabase creates the schema of tenant and brings it up to date like the
+// shared schema, or only checks it when database.skipMigrations is set
+func migrateTenantDatabase(ctx context.Context, cfg config.DatabaseConfig, shared *entsql.Driver, tenant string) error {
+	if !cfg.SkipMigrations {
+		if err := createTenantSchema(ctx, shared, tenant); err != nil {
+			return err
+		}
+	}
+	drv, err := connectTenantDatabase(cfg, tenant)
+	if err != nil {
+		return err
+	}
+	defer drv.Close()
+	if err := migrateDatabase(ctx, cfg, drv); err != nil {
+		return fmt.Errorf("tenant %s: %w", tenant, err)
+	}
+	return nil
+}
+
+// createTenantSchema creates the schema of tenant unless it exists
+func createTenantSchema(ctx context.Context, shared *entsql.Driver, tenant string) error {
+	// 租户ID已在配置加载时校验，只含小写字母、数字和下划线
+	if _, err := shared.DB().ExecContext(ctx, `CREATE SCHEMA IF NOT EXISTS "`+dbtenant.Schema(tenant)+`"`); err != nil {
+		return fmt.Errorf("failed to create the schema of tenant %s: %w", tenant, err)
+	}
+	return nil
+}
+
+// connectTenantDatabase opens a connection to the configured database whose statements run
+// in the schema of tenant. The public schema stays on the search path for the types and
+// functions of extensions, such as citext.
+func connectTenantDatabase(cfg config.DatabaseConfig, tenant string) (*entsql.Driver, error) {
+	dsn, err := databaseDSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database DSN: %w", err)
+	}
+	query := u.Query()
+	query.Set("search_path", dbtenant.Schema(tenant)+",public")
+	u.RawQuery = query.Encode()
+	drv, err := openPostgres(cfg, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the database of tenant %s: %w", tenant, err)
+	}
+	return drv, nil
+}
+
+// connectDatabase opens a connection to the configured database
+func connectDatabase(cfg config.DatabaseConfig) (*entsql.Driver, error) {
+	dsn, err := databaseDSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Driver == dialect.Postgres {
+		drv, err := openPostgres(cfg, dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		return drv, nil
+	}
+	drv, err := entsql.Open(cfg.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return drv, nil
+}
+
+// openPostgres opens a PostgreSQL database with the pgx driver, configured by
+// database.postgres
+func openPostgres(cfg config.DatabaseConfig, dsn string) (*entsql.Driver, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database DSN: %w", err)
+	}
+	if cfg.Postgres.QueryExecMode != "" {
+		mode, ok := queryExecModes[cfg.Postgres.QueryExecMode]
+		if !ok {
+			return nil, fmt.Errorf("unsupported database.postgres.queryExecMode %q", cfg.Postgres.QueryExecMode)
+		}
+		connConfig.DefaultQueryExecMode = mode
+	}
+	if cfg.Postgres.StatementCacheCapacity > 0 {
+		connConfig.StatementCacheCapacity = cfg.Postgres.StatementCacheCapacity
+	}
+	if cfg.Postgres.DescriptionCacheCapacity > 0 {
+		connConfig.DescriptionCacheCapacity = cfg.Postgres.DescriptionCacheCapacity
+	}
+	for name, value := range cfg.Postgres.RuntimeParams {
+		connConfig.RuntimeParams[name] = value
+	}
+	db := stdlib.OpenDB(*connConfig, stdlib.OptionAfterConnect(scanTimesInUTC))
+	return entsql.OpenDB(dialect.Postgres, db), nil
+}
+
+// scanTimesInUTC makes a connection return timestamptz values in UTC. pgx returns them in
+// the local time zone whatever the time zone of the session is.
+func scanTimesInUTC(ctx context.Context, conn *pgx.Conn) error {
+	conn.TypeMap().RegisterType(&pgtype.Type{
+		Name:  "timestamptz",
+		OID:   pgtype.TimestamptzOID,
+		Codec: &pgtype.TimestamptzCodec{ScanLocation: time.UTC},
+	})
+	return nil
+}
+
+// queryExecModes maps the values of database.postgres.queryExecMode to the pgx modes
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// waitForDatabase connects to the configured database and waits until it accepts
+// connections
+func waitForDatabase(ctx context.Context, cfg config.DatabaseConfig, startup config.StartupConfig) (*entsql.Driver, error) {
+	drv, err := connectDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := waitFor(ctx, "Database", startup, drv.DB().PingContext); err != nil {
+		drv.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return drv, nil
+}
+
+// newMigrator creates the migrator of the versioned migrations
+func newMigrator(drv *entsql.Driver) *migration.Migrator {
+	return migration.New(drv.DB(), drv.Dialect(), migrations)
+}
+
+// databaseDSN builds the data source name of the configured database. Sessions are pinned
+// to UTC, so times read back match the times in responses.
+func databaseDSN(cfg config.DatabaseConfig) (string, error) {
+	switch cfg.Driver {
+	case dialect.Postgres:
+		query := url.Values{}
+		query.Set("timezone", "UTC")
+		if cfg.SSLMode != "" {
+			query.Set("sslmode", cfg.SSLMode)
+		}
+		dsn := url.URL{
+			Scheme:   "postgresql",
+			User:     url.UserPassword(cfg.Username, cfg.Password),
+			Host:     cfg.Host + ":" + strconv.Itoa(cfg.Port),
+			Path:     "/" + cfg.Database,
+			RawQuery: query.Encode(),
+		}
+		return dsn.String(), nil
+
+	case dialect.MySQL:
+		tls, ok := mysqlTLS[cfg.SSLMode]
+		if !ok {
+			return "", fmt.Errorf("unsupported database sslMode %q for mysql", cfg.SSLMode)
+		}
+		dsn := mysql.NewConfig()
+		dsn.User = cfg.Username
+		dsn.Passwd = cfg.Password
+		dsn.Net = "tcp"
+		dsn.Addr = cfg.Host + ":" + strconv.Itoa(cfg.Port)
+		dsn.DBName = cfg.Database
+		dsn.TLSConfig = tls
+		// DATETIME 列不带时区，读写都按 UTC 解释
+		dsn.ParseTime = true
+		dsn.Loc = time.UTC
+		dsn.Params = map[string]string{"time_zone": "'+00:00'"}
+		// utf8mb4 才能存储完整的 Unicode，其默认排序规则不区分大小写，与 PostgreSQL 的 citext 一致
+		dsn.Collation = "utf8mb4_unicode_ci"
+		return dsn.FormatDSN(), nil
+
+	default:
+		return "", fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
+
+// mysqlTLS maps the PostgreSQL sslMode values used in the configuration to the tls
+// parameter of the MySQL driver
+var mysqlTLS = map[string]string{
+	"":            "",
+	"disable":     "false",
+	"allow":       "preferred",
+	"prefer":      "preferred",
+	"require":     "skip-verify",
+	"verify-ca":   "true",
+	"verify-full": "true",
+}
@@ -0,0 +1,564 @@
+package app
+
+import (
+	"context"
+	sqldb "database/sql"
+	"errors"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"github.com/go-sql-driver/mysql"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/hewenyu/gin-pkg/config"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/migration"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/emailchange"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/internal/service/stats"
+	"github.com/hewenyu/gin-pkg/internal/service/tokenexchange"
+	userservice "github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/pkg/actorctx"
+	"github.com/hewenyu/gin-pkg/pkg/auth/exchange"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/auth/password"
+	"github.com/hewenyu/gin-pkg/pkg/mailer"
+	"github.com/hewenyu/gin-pkg/pkg/util/cache"
+	_ "github.com/lib/pq" // previous PostgreSQL driver, compared in BenchmarkPostgresDriver
+)
+
+func TestDatabaseDSN(t *testing.T) {
+	t.Run("postgres", func(t *testing.T) {
+		dsn, err := databaseDSN(config.DatabaseConfig{
+			Driver:   "postgres",
+			Host:     "db",
+			Port:     5432,
+			Username: "app",
+			Password: "p@ss/word",
+			Database: "gin",
+			SSLMode:  "require",
+		})
+		if err != nil {
+			t.Fatalf("databaseDSN() error = %v", err)
+		}
+		u, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatalf("DSN %q does not parse: %v", dsn, err)
+		}
+		password, _ := u.User.Password()
+		if u.Host != "db:5432" || u.Path != "/gin" || u.User.Username() != "app" || password != "p@ss/word" {
+			t.Errorf("DSN %q does not address app@db:5432/gin", dsn)
+		}
+		if q := u.Query(); q.Get("sslmode") != "require" || q.Get("timezone") != "UTC" {
+			t.Errorf("DSN %q has query %v, want sslmode=require and timezone=UTC", dsn, q)
+		}
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		dsn, err := databaseDSN(config.DatabaseConfig{
+			Driver:   "mysql",
+			Host:     "db",
+			Port:     3306,
+			Username: "app",
+			Password: "p@ss/word",
+			Database: "gin",
+			SSLMode:  "verify-full",
+		})
+		if err != nil {
+			t.Fatalf("databaseDSN() error = %v", err)
+		}
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			t.Fatalf("DSN %q does not parse: %v", dsn, err)
+		}
+		if cfg.Addr != "db:3306" || cfg.DBName != "gin" || cfg.User != "app" || cfg.Passwd != "p@ss/word" {
+			t.Errorf("DSN %q does not address app@db:3306/gin", dsn)
+		}
+		if cfg.TLSConfig != "true" || !cfg.ParseTime || cfg.Loc != time.UTC || cfg.Params["time_zone"] != "'+00:00'" {
+			t.Errorf("DSN %q should verify TLS and use UTC", dsn)
+		}
+	})
+
+	tests := []struct {
+		name string
+		cfg  config.DatabaseConfig
+	}{
+		{name: "unknown driver", cfg: config.DatabaseConfig{Driver: "oracle"}},
+		{name: "unknown mysql sslMode", cfg: config.DatabaseConfig{Driver: "mysql", SSLMode: "always"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := databaseDSN(tt.cfg); err == nil {
+				t.Error("databaseDSN() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestOpenPostgres(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Driver: "postgres",
+		Postgres: config.PostgresConfig{
+			QueryExecMode: "exec",
+			RuntimeParams: map[string]string{"application_name": "gin-pkg"},
+		},
+	}
+	drv, err := openPostgres(cfg, "postgres://app@db:5432/gin?sslmode=disable")
+	if err != nil {
+		t.Fatalf("openPostgres() error = %v", err)
+	}
+	drv.Close()
+
+	cfg.Postgres.QueryExecMode = "prepare_everything"
+	if _, err := openPostgres(cfg, "postgres://app@db:5432/gin?sslmode=disable"); err == nil {
+		t.Error("openPostgres() accepted an unknown query exec mode")
+	}
+}
+
+// TestDatabaseIntegration migrates a real database and exercises the queries that depend on
+// the dialect. Each database is tested when TEST_<DRIVER>_HOST is set, e.g. TEST_MYSQL_HOST,
+// with the optional TEST_<DRIVER>_PORT, _USERNAME, _PASSWORD, _DATABASE and _SSLMODE.
+func TestDatabaseIntegration(t *testing.T) {
+	for _, driver := range []string{"postgres", "mysql"} {
+		t.Run(driver, func(t *testing.T) {
+			cfg, ok := testDatabaseConfig(driver)
+			if !ok {
+				t.Skipf("TEST_%s_HOST is not set", strings.ToUpper(driver))
+			}
+			testDatabase(t, cfg)
+		})
+	}
+}
+
+// testDatabaseConfig reads the configuration of a test database from the environment
+func testDatabaseConfig(driver string) (config.DatabaseConfig, bool) {
+	prefix := "TEST_" + strings.ToUpper(driver) + "_"
+	env := func(name, fallback string) string {
+		if value := os.Getenv(prefix + name); value != "" {
+			return value
+		}
+		return fallback
+	}
+
+	cfg := config.DatabaseConfig{
+		Driver:   driver,
+		Host:     os.Getenv(prefix + "HOST"),
+		Username: env("USERNAME", map[string]string{"postgres": "postgres", "mysql": "root"}[driver]),
+		Password: env("PASSWORD", ""),
+		Database: env("DATABASE", "gin_pkg_test"),
+		SSLMode:  env("SSLMODE", "disable"),
+	}
+	cfg.Port, _ = strconv.Atoi(env("PORT", map[string]string{"postgres": "5432", "mysql": "3306"}[driver]))
+	return cfg, cfg.Host != ""
+}
+
+func testDatabase(t *testing.T, cfg config.DatabaseConfig) {
+	ctx := context.Background()
+	client, _, err := openDatabase(ctx, cfg, config.StartupConfig{}, nil)
+	if err != nil {
+		t.Fatalf("openDatabase() error = %v", err)
+	}
+	client.Close()
+
+	// Migrating the schema again must be a no-op
+	client, _, err = openDatabase(ctx, cfg, config.StartupConfig{}, nil)
+	if err != nil {
+		t.Fatalf("openDatabase() on a migrated database error = %v", err)
+	}
+	defer client.Close()
+
+	testMigrations(t, cfg)
+
+	suffix := uuid.New().String()[:8]
+	before := time.Now().Add(-time.Second)
+	u, err := client.User.Create().
+		SetEmail("Integration-" + suffix + "@Example.com").
+		SetUsername("integration-" + suffix).
+		SetPasswordHash("hash").
+		SetRoles([]string{"auditor-" + suffix}).
+		SetAvatarURL("https://example.com/" + strings.Repeat("a", 500)).
+		Save(ctx)
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	defer client.User.DeleteOneID(u.ID).Exec(schema.SkipSoftDelete(ctx))
+
+	// Emails compare without case, like citext on PostgreSQL
+	found, err := client.User.Query().Where(user.Email("INTEGRATION-" + suffix + "@EXAMPLE.COM")).Only(ctx)
+	if err != nil {
+		t.Fatalf("failed to find user by email in another case: %v", err)
+	}
+	if _, offset := found.CreatedAt.Zone(); !found.CreatedAt.After(before) || offset != 0 {
+		t.Errorf("created_at = %v, want a UTC time after %v", found.CreatedAt, before)
+	}
+
+	// Roles are queried inside the JSON array
+	count, err := client.User.Query().
+		Where(func(s *sql.Selector) {
+			s.Where(sqljson.ValueContains(user.FieldRoles, "auditor-"+suffix))
+		}).
+		Count(ctx)
+	if err != nil || count != 1 {
+		t.Errorf("users with role = %d, %v, want 1", count, err)
+	}
+
+	a, err := client.Activity.Create().
+		SetUserID(u.ID).
+		SetAction("login").
+		SetUserAgent(strings.Repeat("Mozilla/5.0 ", 100)).
+		Save(ctx)
+	if err != nil {
+		t.Fatalf("failed to store a long user agent: %v", err)
+	}
+	defer client.Activity.DeleteOneID(a.ID).Exec(ctx)
+
+	// Signups are grouped by UTC day with dialect specific SQL
+	userStats, err := stats.NewStatsService(client, stats.Cache{}, stats.Options{}).UserStats(ctx, 1)
+	if err != nil {
+		t.Fatalf("UserStats() error = %v", err)
+	}
+	if len(userStats.Signups) != 1 || userStats.Signups[0].Count < 1 {
+		t.Errorf("signups = %+v, want today's signup", userStats.Signups)
+	}
+
+	testAdminRole(t, client)
+	testEmailChangeRollback(t, client)
+	testTokenExchangeRace(t, client)
+}
+
+// testAdminRole checks that only admins grant or revoke the admin role and that the last
+// active admin keeps it and cannot be deactivated or deleted. It runs in a transaction that is rolled back, in which the
+// existing admins are deactivated.
+func testAdminRole(t *testing.T, client *ent.Client) {
+	tx, err := client.Tx(context.Background())
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	ctx := ent.NewTxContext(context.Background(), tx)
+
+	if err := rbac.NewRBACService(client, cache.SWROptions{}).Seed(ctx); err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if _, err := tx.User.Update().Where(user.Active(true)).SetActive(false).Save(schema.SkipOrgScope(ctx)); err != nil {
+		t.Fatalf("failed to deactivate users: %v", err)
+	}
+	suffix := uuid.New().String()[:8]
+	create := func(name string, roles []string) *ent.User {
+		u, err := tx.User.Create().
+			SetEmail(name + "-" + suffix + "@example.com").
+			SetUsername(name + "-" + suffix).
+			SetPasswordHash("hash").
+			SetRoles(roles).
+			Save(ctx)
+		if err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		return u
+	}
+	admin := create("admin", []string{rbac.RoleAdmin})
+	member := create("member", []string{rbac.RoleUser})
+
+	tokenService := jwt.NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, jwt.TokenEncryption{}, jwt.NewMemoryTokenStore(), nil)
+	users := userservice.NewUserService(client, tokenService, password.Policy{}, nil)
+	asAdmin := actorctx.WithRoles(actorctx.With(ctx, admin.ID), []string{rbac.RoleAdmin})
+	asMember := actorctx.WithRoles(actorctx.With(ctx, member.ID), []string{rbac.RoleUser})
+
+	if _, err := users.ChangeRoles(asMember, member.ID, []string{rbac.RoleAdmin}, nil); !errors.Is(err, userservice.ErrAdminRequired) {
+		t.Errorf("non-admin granting admin with ChangeRoles: error = %v, want %v", err, userservice.ErrAdminRequired)
+	}
+	if _, err := users.UpdateUser(asMember, member.ID, model.UpdateUserInput{Roles: []string{rbac.RoleAdmin}}); !errors.Is(err, userservice.ErrAdminRequired) {
+		t.Errorf("non-admin granting admin with UpdateUser: error = %v, want %v", err, userservice.ErrAdminRequired)
+	}
+	if _, err := users.ChangeRoles(asAdmin, admin.ID, nil, []string{rbac.RoleAdmin}); !errors.Is(err, userservice.ErrLastAdmin) {
+		t.Errorf("revoking admin from the last admin with ChangeRoles: error = %v, want %v", err, userservice.ErrLastAdmin)
+	}
+	if _, err := users.UpdateUser(asAdmin, admin.ID, model.UpdateUserInput{Roles: []string{rbac.RoleUser}}); !errors.Is(err, userservice.ErrLastAdmin) {
+		t.Errorf("revoking admin from the last admin with UpdateUser: error = %v, want %v", err, userservice.ErrLastAdmin)
+	}
+
+	second := create("second-admin", []string{rbac.RoleAdmin})
+	if _, err := users.SetActive(asAdmin, second.ID, false); err != nil {
+		t.Errorf("deactivating an admin while another is active: error = %v", err)
+	}
+	if _, err := users.SetActive(asMember, admin.ID, false); !errors.Is(err, userservice.ErrLastAdmin) {
+		t.Errorf("deactivating the last admin: error = %v, want %v", err, userservice.ErrLastAdmin)
+	}
+	if err := users.DeleteUser(asMember, admin.ID); !errors.Is(err, userservice.ErrLastAdmin) {
+		t.Errorf("deleting the last admin: error = %v, want %v", err, userservice.ErrLastAdmin)
+	}
+	if err := users.DeleteUser(asAdmin, admin.ID); !errors.Is(err, userservice.ErrSelfLockout) {
+		t.Errorf("deleting yourself: error = %v, want %v", err, userservice.ErrSelfLockout)
+	}
+}
+
+// recordingMailer keeps the tokens of the links it is asked to send, by recipient
+type recordingMailer struct {
+	tokens map[string]string
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if _, token, ok := strings.Cut(msg.Body, "token="); ok {
+		m.tokens[msg.To], _ = url.QueryUnescape(strings.Fields(token)[0])
+	}
+	return nil
+}
+
+// testEmailChangeRollback changes the email of a user twice, as an attacker would, and checks
+// that the rollback link sent to the first address restores it for good. It runs in a
+// transaction that is rolled back.
+func testEmailChangeRollback(t *testing.T, client *ent.Client) {
+	tx, err := client.Tx(context.Background())
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	ctx := ent.NewTxContext(context.Background(), tx)
+
+	hasher, err := password.NewHasher(password.HasherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hasher.Hash("Secret123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	suffix := uuid.New().String()[:8]
+	emailA, emailB, emailC := "a-"+suffix+"@example.com", "b-"+suffix+"@example.com", "c-"+suffix+"@example.com"
+	u, err := tx.User.Create().
+		SetEmail(emailA).
+		SetUsername("email-change-" + suffix).
+		SetPasswordHash(hash).
+		Save(ctx)
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	mails := &recordingMailer{tokens: make(map[string]string)}
+	tokenService := jwt.NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, jwt.TokenEncryption{}, jwt.NewMemoryTokenStore(), nil)
+	changes := emailchange.NewEmailChangeService(client, tokenService, hasher, mails, emailchange.Options{
+		ConfirmTTL:     time.Hour,
+		RollbackWindow: time.Hour,
+	})
+	change := func(from, to string) {
+		t.Helper()
+		if _, err := changes.RequestEmailChange(ctx, u.ID, model.ChangeEmailInput{NewEmail: to, Password: "Secret123!"}, "127.0.0.1"); err != nil {
+			t.Fatalf("RequestEmailChange(%s) error = %v", to, err)
+		}
+		if _, err := changes.ConfirmEmailChange(ctx, mails.tokens[to]); err != nil {
+			t.Fatalf("ConfirmEmailChange(%s) error = %v", to, err)
+		}
+	}
+	change(emailA, emailB)
+	rollbackA := mails.tokens[emailA]
+	change(emailB, emailC)
+	rollbackB := mails.tokens[emailB]
+
+	restored, err := changes.RollbackEmailChange(ctx, rollbackA)
+	if err != nil {
+		t.Fatalf("RollbackEmailChange(link sent to A) error = %v", err)
+	}
+	if restored.Email != emailA {
+		t.Errorf("email after rollback = %s, want %s", restored.Email, emailA)
+	}
+	// The link of the B to C change went to an address the attacker controls
+	if _, err := changes.RollbackEmailChange(ctx, rollbackB); err == nil {
+		t.Error("RollbackEmailChange(link sent to B) succeeded after the account was restored")
+	}
+	if current, err := tx.User.Get(ctx, u.ID); err != nil || current.Email != emailA {
+		t.Errorf("email = %v, %v, want %s", current, err, emailA)
+	}
+}
+
+// testTokenExchangeRace exchanges tokens of a new external identity concurrently and checks
+// that all of them succeed for the same, single user
+func testTokenExchangeRace(t *testing.T, client *ent.Client) {
+	ctx := context.Background()
+	verifier, err := exchange.NewVerifier([]exchange.Issuer{{Name: "sso", Issuer: "https://sso.example.com", Secret: "shared-secret"}}, exchange.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher, err := password.NewHasher(password.HasherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenService := jwt.NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, jwt.TokenEncryption{}, jwt.NewMemoryTokenStore(), nil)
+	exchanges := tokenexchange.NewTokenExchangeService(client, tokenService, verifier, hasher,
+		[]tokenexchange.Rule{{Issuer: "sso", CreateUsers: true}})
+
+	email := "exchange-" + uuid.New().String()[:8] + "@example.com"
+	defer client.User.Delete().Where(user.Email(email)).Exec(schema.SkipSoftDelete(ctx))
+	token, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{
+		"iss":            "https://sso.example.com",
+		"sub":            "race",
+		"email":          email,
+		"email_verified": true,
+		"exp":            time.Now().Add(time.Minute).Unix(),
+	}).SignedString([]byte("shared-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 4
+	ids := make(chan string, n)
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, u, err := exchanges.Exchange(ctx, token)
+			if err != nil {
+				errs <- err
+				return
+			}
+			ids <- u.ID
+		}()
+	}
+	var first string
+	for i := 0; i < n; i++ {
+		select {
+		case err := <-errs:
+			t.Errorf("concurrent Exchange() error = %v", err)
+		case id := <-ids:
+			if first == "" {
+				first = id
+			} else if id != first {
+				t.Errorf("concurrent exchanges returned users %s and %s", first, id)
+			}
+		}
+	}
+	if count, err := client.User.Query().Where(user.Email(email)).Count(ctx); err != nil || count != 1 {
+		t.Errorf("users with the exchanged email = %d, %v, want 1", count, err)
+	}
+}
+
+// testMigrations applies and reverts a versioned migration on a migrated database
+func testMigrations(t *testing.T, cfg config.DatabaseConfig) {
+	ctx := context.Background()
+	drv, err := connectDatabase(cfg)
+	if err != nil {
+		t.Fatalf("connectDatabase() error = %v", err)
+	}
+	defer drv.Close()
+
+	if pending, err := newMigrator(drv).Check(ctx); err != nil || pending != 0 {
+		t.Errorf("Check() = %d, %v, want no pending migrations", pending, err)
+	}
+
+	migrator := migration.New(drv.DB(), drv.Dialect(), []migration.Migration{{
+		Version: 1 << 30,
+		Name:    "integration test table",
+		Up: func(ctx context.Context, tx *sqldb.Tx) error {
+			_, err := tx.ExecContext(ctx, "CREATE TABLE migration_integration_test (id INT)")
+			return err
+		},
+		Down: func(ctx context.Context, tx *sqldb.Tx) error {
+			_, err := tx.ExecContext(ctx, "DROP TABLE migration_integration_test")
+			return err
+		},
+	}})
+	if err := migrator.Up(ctx, 0); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil || len(statuses) != 1 || !statuses[0].Applied {
+		t.Errorf("Status() after Up = %+v, %v, want the migration applied", statuses, err)
+	}
+	if _, err := drv.DB().ExecContext(ctx, "SELECT id FROM migration_integration_test"); err != nil {
+		t.Errorf("table of the applied migration is missing: %v", err)
+	}
+
+	if err := migrator.Down(ctx, 0); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	statuses, err = migrator.Status(ctx)
+	if err != nil || len(statuses) != 1 || statuses[0].Applied {
+		t.Errorf("Status() after Down = %+v, %v, want the migration pending", statuses, err)
+	}
+	if _, err := drv.DB().ExecContext(ctx, "SELECT id FROM migration_integration_test"); err == nil {
+		t.Error("table of the reverted migration still exists")
+	}
+}
+
+// BenchmarkPostgresDriver compares lib/pq, the previous PostgreSQL driver, with pgx in each
+// query exec mode on the queries of the login (user by email) and user lookup (user by ID)
+// paths. It runs against the database of TEST_POSTGRES_HOST:
+//
+//	TEST_POSTGRES_HOST=127.0.0.1 go test ./internal/app -run '^$' -bench PostgresDriver
+func BenchmarkPostgresDriver(b *testing.B) {
+	cfg, ok := testDatabaseConfig("postgres")
+	if !ok {
+		b.Skip("TEST_POSTGRES_HOST is not set")
+	}
+	ctx := context.Background()
+	client, _, err := openDatabase(ctx, cfg, config.StartupConfig{}, nil)
+	if err != nil {
+		b.Fatalf("openDatabase() error = %v", err)
+	}
+	defer client.Close()
+
+	suffix := uuid.New().String()[:8]
+	u, err := client.User.Create().
+		SetEmail("bench-" + suffix + "@example.com").
+		SetUsername("bench-" + suffix).
+		SetPasswordHash("hash").
+		Save(ctx)
+	if err != nil {
+		b.Fatalf("failed to create user: %v", err)
+	}
+	defer client.User.DeleteOneID(u.ID).Exec(schema.SkipSoftDelete(ctx))
+
+	dsn, err := databaseDSN(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	drivers := []struct {
+		name string
+		open func() (*sql.Driver, error)
+	}{
+		{name: "lib/pq", open: func() (*sql.Driver, error) { return sql.Open(dialect.Postgres, dsn) }},
+	}
+	for _, mode := range []string{"cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol"} {
+		pgCfg := cfg
+		pgCfg.Postgres = config.PostgresConfig{QueryExecMode: mode}
+		drivers = append(drivers, struct {
+			name string
+			open func() (*sql.Driver, error)
+		}{name: "pgx/" + mode, open: func() (*sql.Driver, error) { return openPostgres(pgCfg, dsn) }})
+	}
+
+	for _, d := range drivers {
+		drv, err := d.open()
+		if err != nil {
+			b.Fatalf("failed to open %s: %v", d.name, err)
+		}
+		users := userservice.NewUserService(ent.NewClient(ent.Driver(drv)), nil, password.Policy{}, nil)
+
+		b.Run(d.name+"/login", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := users.GetUserByEmail(ctx, u.Email); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(d.name+"/user", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := users.GetUserByID(ctx, u.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		drv.Close()
+	}
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"github.com/hewenyu/gin-pkg/internal/migration"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/util/nonce"
+)
+
+// migrations are the versioned migrations of the database, oldest first. They run before the
+// ent schema migration, so they can prepare existing data for schema changes it would
+// otherwise fail on. Append new migrations with the next version; never renumber or remove
+// one that has been released.
+var migrations = []migration.Migration{
+	{
+		Version:  1,
+		Name:     "copy user role to roles",
+		Dialects: []string{dialect.Postgres},
+		Up:       migrateUserRoles,
+	},
+	{
+		Version:  2,
+		Name:     "lower-case user emails",
+		Dialects: []string{dialect.Postgres},
+		Up:       migrateUserEmails,
+	},
+	{
+		Version:  3,
+		Name:     "create nonces table",
+		Dialects: []string{dialect.Postgres},
+		Up:       createNoncesTable,
+		Down:     dropNoncesTable,
+	},
+}
+
+// migrateUserRoles copies the single role column of existing users into the roles list.
+// It runs before the schema migration, which adds roles as a NOT NULL column and would
+// fail on a populated table. The old role column is left in place and no longer read.
+func migrateUserRoles(ctx context.Context, tx *sql.Tx) error {
+	var legacy bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (
+		SELECT 1 FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'role'
+	)`).Scan(&legacy)
+	if err != nil {
+		return fmt.Errorf("failed to inspect users table: %w", err)
+	}
+	if !legacy {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE users ADD COLUMN IF NOT EXISTS roles jsonb`); err != nil {
+		return fmt.Errorf("failed to add roles column: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE users SET roles = jsonb_build_array(role) WHERE roles IS NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to copy user roles: %w", err)
+	}
+
+	if n, _ := res.RowsAffected(); n > 0 {
+		logger.Infof("Migrated the role of %d users to roles", n)
+	}
+	return nil
+}
+
+// migrateUserEmails stores the emails of existing users in lower case and installs citext,
+// the case-insensitive type of the email column. It runs before the schema migration, which
+// changes the column to citext and would fail on addresses that differ only in case; those
+// are reported so an operator can merge or rename the accounts first.
+func migrateUserEmails(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `CREATE EXTENSION IF NOT EXISTS citext`); err != nil {
+		return fmt.Errorf("failed to install the citext extension: %w", err)
+	}
+
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (
+		SELECT 1 FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name = 'users'
+	)`).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to inspect users table: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT lower(trim(email)) FROM users
+		GROUP BY lower(trim(email)) HAVING count(*) > 1 ORDER BY 1`)
+	if err != nil {
+		return fmt.Errorf("failed to check for duplicate emails: %w", err)
+	}
+	defer rows.Close()
+	var duplicates []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return fmt.Errorf("failed to check for duplicate emails: %w", err)
+		}
+		duplicates = append(duplicates, email)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check for duplicate emails: %w", err)
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("several users share each of these emails when case is ignored, rename all but one of them: %s",
+			strings.Join(duplicates, ", "))
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE users SET email = lower(trim(email)) WHERE email <> lower(trim(email))`)
+	if err != nil {
+		return fmt.Errorf("failed to normalize user emails: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		logger.Infof("Normalized the email of %d users", n)
+	}
+	return nil
+}
+
+// createNoncesTable creates the table of nonce.NoncePostgresService, used when
+// security.nonceStore.backend is postgres. It is created whatever the backend, so switching
+// to postgres needs no migration.
+func createNoncesTable(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+nonce.Table+` (
+		nonce text PRIMARY KEY,
+		expires_at timestamptz NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create the nonces table: %w", err)
+	}
+	// 清理过期随机数时按过期时间查找
+	if _, err := tx.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS nonces_expires_at ON `+nonce.Table+` (expires_at)`); err != nil {
+		return fmt.Errorf("failed to index the nonces table: %w", err)
+	}
+	return nil
+}
+
+// dropNoncesTable drops the table of nonce.NoncePostgresService, dropping unused nonces
+func dropNoncesTable(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS `+nonce.Table); err != nil {
+		return fmt.Errorf("failed to drop the nonces table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/config"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// waitFor calls connect until it succeeds, retrying failed attempts startup.connectRetries
+// times with exponential backoff, so the application can start before its dependencies
+// accept connections. name identifies the dependency in the logs.
+func waitFor(ctx context.Context, name string, cfg config.StartupConfig, connect func(ctx context.Context) error) error {
+	backoff := cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := connect(ctx)
+		if err == nil {
+			if attempt > 1 {
+				logger.Infof("%s is available after %d attempts", name, attempt)
+			}
+			return nil
+		}
+		if attempt > cfg.ConnectRetries {
+			if cfg.ConnectRetries == 0 {
+				return err
+			}
+			return fmt.Errorf("still unavailable after %d attempts: %w", attempt, err)
+		}
+
+		logger.Warnf("%s is unavailable (attempt %d of %d), retrying in %s: %v",
+			name, attempt, cfg.ConnectRetries+1, backoff, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > cfg.MaxRetryBackoff {
+			backoff = cfg.MaxRetryBackoff
+		}
+	}
+}
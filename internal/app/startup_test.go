@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/config"
+)
+
+func TestWaitFor(t *testing.T) {
+	errRefused := errors.New("connection refused")
+	cfg := config.StartupConfig{
+		ConnectRetries:  3,
+		RetryBackoff:    time.Millisecond,
+		MaxRetryBackoff: 2 * time.Millisecond,
+	}
+	// connectAfter fails until its n-th attempt
+	connectAfter := func(n int, attempts *int) func(context.Context) error {
+		return func(context.Context) error {
+			*attempts++
+			if *attempts < n {
+				return errRefused
+			}
+			return nil
+		}
+	}
+
+	t.Run("available after retries", func(t *testing.T) {
+		var attempts int
+		if err := waitFor(context.Background(), "database", cfg, connectAfter(3, &attempts)); err != nil {
+			t.Fatalf("waitFor() error = %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("attempts = %d, want 3", attempts)
+		}
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		var attempts int
+		err := waitFor(context.Background(), "database", cfg, connectAfter(10, &attempts))
+		if !errors.Is(err, errRefused) || !strings.Contains(err.Error(), "after 4 attempts") {
+			t.Errorf("waitFor() error = %v, want the last error after 4 attempts", err)
+		}
+		if attempts != 4 {
+			t.Errorf("attempts = %d, want 4", attempts)
+		}
+	})
+
+	t.Run("no retries", func(t *testing.T) {
+		var attempts int
+		err := waitFor(context.Background(), "database", config.StartupConfig{}, connectAfter(2, &attempts))
+		if err != errRefused || attempts != 1 {
+			t.Errorf("waitFor() = %v after %d attempts, want the first error", err, attempts)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var attempts int
+		slow := cfg
+		slow.RetryBackoff = time.Hour
+		err := waitFor(ctx, "database", slow, connectAfter(10, &attempts))
+		if !errors.Is(err, errRefused) || attempts != 1 {
+			t.Errorf("waitFor() = %v after %d attempts, want to give up after the first", err, attempts)
+		}
+	})
+}
@@ -0,0 +1,355 @@
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	entuser "github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/userbulk"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// registerCommands returns the built-in commands
+func (c *Console) registerCommands() map[string]command {
+	return map[string]command{
+		"help": {
+			help: "List the available commands",
+			run: func(ctx context.Context, args []string) (any, error) {
+				return c.helpText(), nil
+			},
+		},
+		"exit": {
+			help: "Leave the console",
+			run: func(ctx context.Context, args []string) (any, error) {
+				return nil, errExit
+			},
+		},
+		"stats": {
+			help: "Count the rows of the main tables",
+			run:  c.stats,
+		},
+		"users list": {
+			usage: "[limit]",
+			help:  "List users, newest first (default 20)",
+			run:   c.listUsers,
+		},
+		"users get": {
+			usage: "<id|email>",
+			help:  "Show a user",
+			run: func(ctx context.Context, args []string) (any, error) {
+				if len(args) != 1 {
+					return nil, errors.New("usage: users get <id|email>")
+				}
+				return c.findUser(ctx, args[0])
+			},
+		},
+		"users create": {
+			usage: "<email> <username> <password> [role,...]",
+			help:  "Create a user through UserService",
+			run:   c.createUser,
+		},
+		"users update": {
+			usage: "<id|email> field=value...",
+			help:  "Update username, roles (comma-separated), active, avatar_url or phone",
+			run:   c.updateUser,
+		},
+		"users delete": {
+			usage: "<id|email>",
+			help:  "Delete a user",
+			run: func(ctx context.Context, args []string) (any, error) {
+				if len(args) != 1 {
+					return nil, errors.New("usage: users delete <id|email>")
+				}
+				u, err := c.findUser(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := c.env.Users.DeleteUser(ctx, u.ID); err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("deleted user %s (%s)", u.ID, u.Email), nil
+			},
+		},
+		"users export": {
+			usage:   "<file>",
+			help:    "Write every user to a .csv or .json file",
+			timeout: bulkCommandTimeout,
+			run:     c.exportUsers,
+		},
+		"users import": {
+			usage:   "<file> [skip|update|error]",
+			help:    "Create users from a .csv or .json file; existing emails are skipped by default",
+			timeout: bulkCommandTimeout,
+			run:     c.importUsers,
+		},
+		"tokens issue": {
+			usage: "<id|email>",
+			help:  "Issue a token pair for a user",
+			run: func(ctx context.Context, args []string) (any, error) {
+				if len(args) != 1 {
+					return nil, errors.New("usage: tokens issue <id|email>")
+				}
+				u, err := c.findUser(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				return c.env.Tokens.GenerateTokenPair(ctx, u.ID, u.Email, u.Roles)
+			},
+		},
+		"tokens inspect": {
+			usage: "<token> [access|refresh]",
+			help:  "Validate a token and show its claims",
+			run: func(ctx context.Context, args []string) (any, error) {
+				if len(args) < 1 || len(args) > 2 {
+					return nil, errors.New("usage: tokens inspect <token> [access|refresh]")
+				}
+				tokenType := jwt.AccessToken
+				if len(args) == 2 {
+					tokenType = jwt.TokenType(args[1])
+				}
+				return c.env.Tokens.ValidateToken(ctx, args[0], tokenType)
+			},
+		},
+		"tokens revoke": {
+			usage: "<id|email>",
+			help:  "Revoke every token issued to a user",
+			run: func(ctx context.Context, args []string) (any, error) {
+				if len(args) != 1 {
+					return nil, errors.New("usage: tokens revoke <id|email>")
+				}
+				u, err := c.findUser(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := c.env.Tokens.RevokeUserTokens(ctx, u.ID); err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("revoked all tokens of %s", u.Email), nil
+			},
+		},
+		"tokens ban": {
+			usage: "<id|email> <duration>",
+			help:  "Reject every token of a user, including new ones, for a duration such as 24h",
+			run: func(ctx context.Context, args []string) (any, error) {
+				if len(args) != 2 {
+					return nil, errors.New("usage: tokens ban <id|email> <duration>")
+				}
+				duration, err := time.ParseDuration(args[1])
+				if err != nil || duration <= 0 {
+					return nil, fmt.Errorf("invalid duration %q", args[1])
+				}
+				u, err := c.findUser(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				until := time.Now().Add(duration)
+				if err := c.env.Tokens.BlacklistUser(ctx, u.ID, until); err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("rejecting all tokens of %s until %s", u.Email, until.Format(time.RFC3339)), nil
+			},
+		},
+		"tokens unban": {
+			usage: "<id|email>",
+			help:  "Lift a ban set with tokens ban",
+			run: func(ctx context.Context, args []string) (any, error) {
+				if len(args) != 1 {
+					return nil, errors.New("usage: tokens unban <id|email>")
+				}
+				u, err := c.findUser(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := c.env.Tokens.BlacklistUser(ctx, u.ID, time.Time{}); err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("lifted the ban of %s", u.Email), nil
+			},
+		},
+	}
+}
+
+// stats counts the rows of the main tables
+func (c *Console) stats(ctx context.Context, args []string) (any, error) {
+	counts := map[string]func(context.Context) (int, error){
+		"users":            c.env.Client.User.Query().Count,
+		"settings":         c.env.Client.Setting.Query().Count,
+		"service_accounts": c.env.Client.ServiceAccount.Query().Count,
+		"email_changes":    c.env.Client.EmailChange.Query().Count,
+	}
+
+	result := make(map[string]int, len(counts))
+	for name, count := range counts {
+		n, err := count(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", name, err)
+		}
+		result[name] = n
+	}
+	return result, nil
+}
+
+// listUsers lists the newest users
+func (c *Console) listUsers(ctx context.Context, args []string) (any, error) {
+	limit := 20
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid limit %q", args[0])
+		}
+		limit = n
+	}
+
+	users, err := c.env.Client.User.Query().
+		Order(ent.Desc(entuser.FieldCreatedAt)).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// createUser creates a user through the user service, so policies and hashing apply
+func (c *Console) createUser(ctx context.Context, args []string) (any, error) {
+	if len(args) < 3 || len(args) > 4 {
+		return nil, errors.New("usage: users create <email> <username> <password> [role,...]")
+	}
+	input := model.CreateUserInput{
+		Email:    args[0],
+		Username: args[1],
+		Password: args[2],
+	}
+	if len(args) == 4 {
+		input.Roles = strings.Split(args[3], ",")
+	}
+	return c.env.Users.CreateUser(ctx, input)
+}
+
+// updateUser applies field=value assignments to a user. Changing active, or setting
+// must_change_password, also revokes the user's tokens, as the admin endpoints do.
+func (c *Console) updateUser(ctx context.Context, args []string) (any, error) {
+	if len(args) < 2 {
+		return nil, errors.New("usage: users update <id|email> field=value...")
+	}
+	u, err := c.findUser(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var input model.UpdateUserInput
+	var active, mustChangePassword *bool
+	for _, assignment := range args[1:] {
+		field, value, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected field=value, got %q", assignment)
+		}
+		switch field {
+		case "username":
+			input.Username = value
+		case "roles":
+			input.Roles = strings.Split(value, ",")
+		case "active":
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid active value %q", value)
+			}
+			active = &parsed
+		case "must_change_password":
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid must_change_password value %q", value)
+			}
+			mustChangePassword = &parsed
+		case "avatar_url":
+			input.AvatarURL = &value
+		case "phone":
+			input.Phone = &value
+		default:
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+	}
+	updated, err := c.env.Users.UpdateUser(ctx, u.ID, input)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		if updated, err = c.env.Users.SetActive(ctx, u.ID, *active); err != nil {
+			return nil, err
+		}
+	}
+	if mustChangePassword != nil {
+		if updated, err = c.env.Users.SetMustChangePassword(ctx, u.ID, *mustChangePassword); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+// exportUsers writes every user to a file, in the format given by its extension
+func (c *Console) exportUsers(ctx context.Context, args []string) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("usage: users export <file>")
+	}
+	format, err := userbulk.ParseFormat(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		return nil, err
+	}
+	count, err := c.env.UserBulk.ExportUsers(ctx, f, format)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("exported %d users to %s", count, args[0]), nil
+}
+
+// importUsers imports users from a file and prints the report, including the rows that failed
+func (c *Console) importUsers(ctx context.Context, args []string) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, errors.New("usage: users import <file> [skip|update|error]")
+	}
+	format, err := userbulk.ParseFormat(args[0])
+	if err != nil {
+		return nil, err
+	}
+	onDuplicate := userbulk.DuplicateSkip
+	if len(args) == 2 {
+		if onDuplicate, err = userbulk.ParseDuplicatePolicy(args[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := c.env.UserBulk.ParseImport(f, format)
+	if err != nil {
+		return nil, err
+	}
+	return c.env.UserBulk.ImportUsers(ctx, rows, onDuplicate)
+}
+
+// findUser looks a user up by email if the argument contains @, otherwise by ID
+func (c *Console) findUser(ctx context.Context, idOrEmail string) (*ent.User, error) {
+	if strings.Contains(idOrEmail, "@") {
+		return c.env.Users.GetUserByEmail(ctx, idOrEmail)
+	}
+	return c.env.Users.GetUserByID(ctx, idOrEmail)
+}
@@ -0,0 +1,205 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/config"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/internal/service/userbulk"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// commandTimeout bounds a single command so a stuck query does not hang the session
+const commandTimeout = 30 * time.Second
+
+// bulkCommandTimeout bounds commands that go through every user, such as imports and exports
+const bulkCommandTimeout = 30 * time.Minute
+
+// errExit is returned by the exit command to end an interactive session
+var errExit = errors.New("exit")
+
+// Env holds the services the console operates on
+type Env struct {
+	Config   *config.Config
+	Client   *ent.Client
+	Users    user.UserService
+	UserBulk userbulk.UserBulkService
+	Tokens   jwt.TokenService
+}
+
+// command is a console command; args are the words after the command name
+type command struct {
+	usage string
+	help  string
+	// timeout overrides commandTimeout
+	timeout time.Duration
+	run     func(ctx context.Context, args []string) (any, error)
+}
+
+// Console runs commands against the service graph, interactively or from a script.
+// Commands print their result as indented JSON.
+type Console struct {
+	env      Env
+	out      io.Writer
+	commands map[string]command
+}
+
+// New creates a console writing its output to out
+func New(env Env, out io.Writer) *Console {
+	c := &Console{env: env, out: out}
+	c.commands = c.registerCommands()
+	return c
+}
+
+// Run reads commands line by line from in. Interactive sessions print a prompt and keep
+// going after a failed command; scripts stop at the first error. Lines starting with #
+// are comments.
+func (c *Console) Run(ctx context.Context, in io.Reader, interactive bool) error {
+	scanner := bufio.NewScanner(in)
+	lineNo := 0
+	for {
+		if interactive {
+			fmt.Fprint(c.out, "> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		lineNo++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !interactive {
+			fmt.Fprintf(c.out, "> %s\n", line)
+		}
+
+		err := c.Exec(ctx, line)
+		if errors.Is(err, errExit) {
+			return nil
+		}
+		if err != nil {
+			if !interactive {
+				return fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			fmt.Fprintf(c.out, "error: %v\n", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Exec runs a single command line
+func (c *Console) Exec(ctx context.Context, line string) error {
+	words, err := splitWords(line)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	// Commands are one or two words, e.g. "help" or "users list"
+	name, args := words[0], words[1:]
+	cmd, ok := c.commands[name]
+	if !ok && len(words) > 1 {
+		name, args = words[0]+" "+words[1], words[2:]
+		cmd, ok = c.commands[name]
+	}
+	if !ok {
+		return fmt.Errorf("unknown command %q, type help for a list of commands", strings.Join(words, " "))
+	}
+
+	timeout := commandTimeout
+	if cmd.timeout > 0 {
+		timeout = cmd.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := cmd.run(ctx, args)
+	if err != nil {
+		return err
+	}
+	return c.print(result)
+}
+
+// print writes a command result
+func (c *Console) print(result any) error {
+	switch r := result.(type) {
+	case nil:
+		return nil
+	case string:
+		_, err := fmt.Fprintln(c.out, r)
+		return err
+	default:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.out, string(data))
+		return err
+	}
+}
+
+// helpText lists the available commands
+func (c *Console) helpText() string {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		cmd := c.commands[name]
+		fmt.Fprintf(&b, "  %-45s %s\n", strings.TrimSpace(name+" "+cmd.usage), cmd.help)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// splitWords splits a command line on whitespace, keeping single- or double-quoted words together
+func splitWords(line string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	var quote rune
+	inWord := false
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
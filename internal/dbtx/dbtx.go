@@ -0,0 +1,50 @@
+package dbtx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+)
+
+// WithTx runs fn in a transaction of client, committing it when fn returns nil and rolling
+// it back when fn fails or panics. The context passed to fn carries the transaction, so
+// services called with it write in the transaction too (see Client). When ctx already
+// carries a transaction, fn runs in it and the outermost WithTx commits or rolls back, so
+// operations that use WithTx themselves can be combined into one atomic operation.
+func WithTx(ctx context.Context, client *ent.Client, fn func(ctx context.Context, tx *ent.Tx) error) (err error) {
+	if tx := ent.TxFromContext(ctx); tx != nil {
+		return fn(ctx, tx)
+	}
+
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			tx.Rollback()
+			panic(v)
+		}
+	}()
+
+	if err := fn(ent.NewTxContext(ctx, tx), tx); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return fmt.Errorf("%w: rollback failed: %v", err, rerr)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Client returns the client of the transaction ctx carries, or client outside a transaction.
+// Services query through it so they take part in the transaction of their caller.
+func Client(ctx context.Context, client *ent.Client) *ent.Client {
+	if tx := ent.TxFromContext(ctx); tx != nil {
+		return tx.Client()
+	}
+	return client
+}
@@ -0,0 +1,119 @@
+package dbtx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"entgo.io/ent/dialect"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+)
+
+// fakeDriver counts the transactions started, committed and rolled back
+type fakeDriver struct {
+	dialect.Driver
+	started, committed, rolledBack int
+}
+
+func (d *fakeDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	d.started++
+	return &fakeTx{d: d}, nil
+}
+
+func (d *fakeDriver) Dialect() string { return dialect.Postgres }
+
+type fakeTx struct {
+	dialect.Tx
+	d *fakeDriver
+}
+
+func (t *fakeTx) Commit() error {
+	t.d.committed++
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.d.rolledBack++
+	return nil
+}
+
+func TestWithTx(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name                  string
+		fn                    func(ctx context.Context, tx *ent.Tx) error
+		wantErr               error
+		committed, rolledBack int
+	}{
+		{
+			name:      "commits on success",
+			fn:        func(ctx context.Context, tx *ent.Tx) error { return nil },
+			committed: 1,
+		},
+		{
+			name:       "rolls back on error",
+			fn:         func(ctx context.Context, tx *ent.Tx) error { return errFailed },
+			wantErr:    errFailed,
+			rolledBack: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drv := &fakeDriver{}
+			client := ent.NewClient(ent.Driver(drv))
+
+			err := WithTx(context.Background(), client, tt.fn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if drv.committed != tt.committed || drv.rolledBack != tt.rolledBack {
+				t.Errorf("committed %d and rolled back %d times, want %d and %d",
+					drv.committed, drv.rolledBack, tt.committed, tt.rolledBack)
+			}
+		})
+	}
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	drv := &fakeDriver{}
+	client := ent.NewClient(ent.Driver(drv))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected the panic to be re-raised")
+		}
+		if drv.rolledBack != 1 || drv.committed != 0 {
+			t.Errorf("committed %d and rolled back %d times", drv.committed, drv.rolledBack)
+		}
+	}()
+	WithTx(context.Background(), client, func(ctx context.Context, tx *ent.Tx) error {
+		panic("boom")
+	})
+}
+
+func TestWithTxJoinsOuterTransaction(t *testing.T) {
+	drv := &fakeDriver{}
+	client := ent.NewClient(ent.Driver(drv))
+
+	err := WithTx(context.Background(), client, func(ctx context.Context, outer *ent.Tx) error {
+		if Client(ctx, client) != outer.Client() {
+			t.Error("Client does not return the client of the transaction")
+		}
+		return WithTx(ctx, client, func(ctx context.Context, inner *ent.Tx) error {
+			if inner != outer {
+				t.Error("nested WithTx started a new transaction")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drv.started != 1 || drv.committed != 1 {
+		t.Errorf("started %d and committed %d transactions, want 1 and 1", drv.started, drv.committed)
+	}
+	if Client(context.Background(), client) != client {
+		t.Error("Client outside a transaction does not return the given client")
+	}
+}
@@ -0,0 +1,362 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/activity"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+)
+
+// ActivityCreate is the builder for creating a Activity entity.
+type ActivityCreate struct {
+	config
+	mutation *ActivityMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (ac *ActivityCreate) SetCreatedAt(t time.Time) *ActivityCreate {
+	ac.mutation.SetCreatedAt(t)
+	return ac
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (ac *ActivityCreate) SetNillableCreatedAt(t *time.Time) *ActivityCreate {
+	if t != nil {
+		ac.SetCreatedAt(*t)
+	}
+	return ac
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ac *ActivityCreate) SetUpdatedAt(t time.Time) *ActivityCreate {
+	ac.mutation.SetUpdatedAt(t)
+	return ac
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (ac *ActivityCreate) SetNillableUpdatedAt(t *time.Time) *ActivityCreate {
+	if t != nil {
+		ac.SetUpdatedAt(*t)
+	}
+	return ac
+}
+
+// SetUserID sets the "user_id" field.
+func (ac *ActivityCreate) SetUserID(s string) *ActivityCreate {
+	ac.mutation.SetUserID(s)
+	return ac
+}
+
+// SetAction sets the "action" field.
+func (ac *ActivityCreate) SetAction(s string) *ActivityCreate {
+	ac.mutation.SetAction(s)
+	return ac
+}
+
+// SetImpersonatedBy sets the "impersonated_by" field.
+func (ac *ActivityCreate) SetImpersonatedBy(s string) *ActivityCreate {
+	ac.mutation.SetImpersonatedBy(s)
+	return ac
+}
+
+// SetNillableImpersonatedBy sets the "impersonated_by" field if the given value is not nil.
+func (ac *ActivityCreate) SetNillableImpersonatedBy(s *string) *ActivityCreate {
+	if s != nil {
+		ac.SetImpersonatedBy(*s)
+	}
+	return ac
+}
+
+// SetIP sets the "ip" field.
+func (ac *ActivityCreate) SetIP(s string) *ActivityCreate {
+	ac.mutation.SetIP(s)
+	return ac
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (ac *ActivityCreate) SetNillableIP(s *string) *ActivityCreate {
+	if s != nil {
+		ac.SetIP(*s)
+	}
+	return ac
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (ac *ActivityCreate) SetUserAgent(s string) *ActivityCreate {
+	ac.mutation.SetUserAgent(s)
+	return ac
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (ac *ActivityCreate) SetNillableUserAgent(s *string) *ActivityCreate {
+	if s != nil {
+		ac.SetUserAgent(*s)
+	}
+	return ac
+}
+
+// SetID sets the "id" field.
+func (ac *ActivityCreate) SetID(s string) *ActivityCreate {
+	ac.mutation.SetID(s)
+	return ac
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (ac *ActivityCreate) SetNillableID(s *string) *ActivityCreate {
+	if s != nil {
+		ac.SetID(*s)
+	}
+	return ac
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (ac *ActivityCreate) SetUser(u *User) *ActivityCreate {
+	return ac.SetUserID(u.ID)
+}
+
+// Mutation returns the ActivityMutation object of the builder.
+func (ac *ActivityCreate) Mutation() *ActivityMutation {
+	return ac.mutation
+}
+
+// Save creates the Activity in the database.
+func (ac *ActivityCreate) Save(ctx context.Context) (*Activity, error) {
+	ac.defaults()
+	return withHooks(ctx, ac.sqlSave, ac.mutation, ac.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ac *ActivityCreate) SaveX(ctx context.Context) *Activity {
+	v, err := ac.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ac *ActivityCreate) Exec(ctx context.Context) error {
+	_, err := ac.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ac *ActivityCreate) ExecX(ctx context.Context) {
+	if err := ac.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ac *ActivityCreate) defaults() {
+	if _, ok := ac.mutation.CreatedAt(); !ok {
+		v := activity.DefaultCreatedAt()
+		ac.mutation.SetCreatedAt(v)
+	}
+	if _, ok := ac.mutation.UpdatedAt(); !ok {
+		v := activity.DefaultUpdatedAt()
+		ac.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := ac.mutation.ID(); !ok {
+		v := activity.DefaultID()
+		ac.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ac *ActivityCreate) check() error {
+	if _, ok := ac.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Activity.created_at"`)}
+	}
+	if _, ok := ac.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "Activity.updated_at"`)}
+	}
+	if _, ok := ac.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "Activity.user_id"`)}
+	}
+	if _, ok := ac.mutation.Action(); !ok {
+		return &ValidationError{Name: "action", err: errors.New(`ent: missing required field "Activity.action"`)}
+	}
+	if v, ok := ac.mutation.Action(); ok {
+		if err := activity.ActionValidator(v); err != nil {
+			return &ValidationError{Name: "action", err: fmt.Errorf(`ent: validator failed for field "Activity.action": %w`, err)}
+		}
+	}
+	if v, ok := ac.mutation.ID(); ok {
+		if err := activity.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`ent: validator failed for field "Activity.id": %w`, err)}
+		}
+	}
+	if len(ac.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "Activity.user"`)}
+	}
+	return nil
+}
+
+func (ac *ActivityCreate) sqlSave(ctx context.Context) (*Activity, error) {
+	if err := ac.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ac.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ac.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected Activity.ID type: %T", _spec.ID.Value)
+		}
+	}
+	ac.mutation.id = &_node.ID
+	ac.mutation.done = true
+	return _node, nil
+}
+
+func (ac *ActivityCreate) createSpec() (*Activity, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Activity{config: ac.config}
+		_spec = sqlgraph.NewCreateSpec(activity.Table, sqlgraph.NewFieldSpec(activity.FieldID, field.TypeString))
+	)
+	if id, ok := ac.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := ac.mutation.CreatedAt(); ok {
+		_spec.SetField(activity.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := ac.mutation.UpdatedAt(); ok {
+		_spec.SetField(activity.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := ac.mutation.Action(); ok {
+		_spec.SetField(activity.FieldAction, field.TypeString, value)
+		_node.Action = value
+	}
+	if value, ok := ac.mutation.ImpersonatedBy(); ok {
+		_spec.SetField(activity.FieldImpersonatedBy, field.TypeString, value)
+		_node.ImpersonatedBy = value
+	}
+	if value, ok := ac.mutation.IP(); ok {
+		_spec.SetField(activity.FieldIP, field.TypeString, value)
+		_node.IP = value
+	}
+	if value, ok := ac.mutation.UserAgent(); ok {
+		_spec.SetField(activity.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
+	if nodes := ac.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   activity.UserTable,
+			Columns: []string{activity.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeString),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// ActivityCreateBulk is the builder for creating many Activity entities in bulk.
+type ActivityCreateBulk struct {
+	config
+	err      error
+	builders []*ActivityCreate
+}
+
+// Save creates the Activity entities in the database.
+func (acb *ActivityCreateBulk) Save(ctx context.Context) ([]*Activity, error) {
+	if acb.err != nil {
+		return nil, acb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(acb.builders))
+	nodes := make([]*Activity, len(acb.builders))
+	mutators := make([]Mutator, len(acb.builders))
+	for i := range acb.builders {
+		func(i int, root context.Context) {
+			builder := acb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ActivityMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, acb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, acb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, acb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (acb *ActivityCreateBulk) SaveX(ctx context.Context) []*Activity {
+	v, err := acb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (acb *ActivityCreateBulk) Exec(ctx context.Context) error {
+	_, err := acb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (acb *ActivityCreateBulk) ExecX(ctx context.Context) {
+	if err := acb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
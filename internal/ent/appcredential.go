@@ -0,0 +1,219 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/internal/ent/appcredential"
+)
+
+// AppCredential is the model entity for the AppCredential schema.
+type AppCredential struct {
+	config `json:"-"`
+	// ID of the ent.
+	// 主键
+	ID string `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// 创建人，用户ID或 service:<服务账号ID>
+	CreatedBy string `json:"created_by,omitempty"`
+	// 最后修改人
+	UpdatedBy string `json:"updated_by,omitempty"`
+	// 接入方名称
+	Name string `json:"name,omitempty"`
+	// 应用标识，客户端通过请求头发送
+	AppKey string `json:"app_key,omitempty"`
+	// 加密后的签名密钥，签名校验需要明文密钥，因此不能只保存哈希
+	SecretCiphertext string `json:"-"`
+	// 状态
+	Status appcredential.Status `json:"status,omitempty"`
+	// 限流窗口内的最大请求数，0表示不限制
+	RateLimit int `json:"rate_limit,omitempty"`
+	// 限流窗口（秒）
+	RateWindow int `json:"rate_window,omitempty"`
+	// 吊销时间
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*AppCredential) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case appcredential.FieldRateLimit, appcredential.FieldRateWindow:
+			values[i] = new(sql.NullInt64)
+		case appcredential.FieldID, appcredential.FieldCreatedBy, appcredential.FieldUpdatedBy, appcredential.FieldName, appcredential.FieldAppKey, appcredential.FieldSecretCiphertext, appcredential.FieldStatus:
+			values[i] = new(sql.NullString)
+		case appcredential.FieldCreatedAt, appcredential.FieldUpdatedAt, appcredential.FieldRevokedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the AppCredential fields.
+func (ac *AppCredential) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case appcredential.FieldID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value.Valid {
+				ac.ID = value.String
+			}
+		case appcredential.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				ac.CreatedAt = value.Time
+			}
+		case appcredential.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				ac.UpdatedAt = value.Time
+			}
+		case appcredential.FieldCreatedBy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field created_by", values[i])
+			} else if value.Valid {
+				ac.CreatedBy = value.String
+			}
+		case appcredential.FieldUpdatedBy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_by", values[i])
+			} else if value.Valid {
+				ac.UpdatedBy = value.String
+			}
+		case appcredential.FieldName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field name", values[i])
+			} else if value.Valid {
+				ac.Name = value.String
+			}
+		case appcredential.FieldAppKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field app_key", values[i])
+			} else if value.Valid {
+				ac.AppKey = value.String
+			}
+		case appcredential.FieldSecretCiphertext:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field secret_ciphertext", values[i])
+			} else if value.Valid {
+				ac.SecretCiphertext = value.String
+			}
+		case appcredential.FieldStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status", values[i])
+			} else if value.Valid {
+				ac.Status = appcredential.Status(value.String)
+			}
+		case appcredential.FieldRateLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field rate_limit", values[i])
+			} else if value.Valid {
+				ac.RateLimit = int(value.Int64)
+			}
+		case appcredential.FieldRateWindow:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field rate_window", values[i])
+			} else if value.Valid {
+				ac.RateWindow = int(value.Int64)
+			}
+		case appcredential.FieldRevokedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field revoked_at", values[i])
+			} else if value.Valid {
+				ac.RevokedAt = new(time.Time)
+				*ac.RevokedAt = value.Time
+			}
+		default:
+			ac.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the AppCredential.
+// This includes values selected through modifiers, order, etc.
+func (ac *AppCredential) Value(name string) (ent.Value, error) {
+	return ac.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this AppCredential.
+// Note that you need to call AppCredential.Unwrap() before calling this method if this AppCredential
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ac *AppCredential) Update() *AppCredentialUpdateOne {
+	return NewAppCredentialClient(ac.config).UpdateOne(ac)
+}
+
+// Unwrap unwraps the AppCredential entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ac *AppCredential) Unwrap() *AppCredential {
+	_tx, ok := ac.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: AppCredential is not a transactional entity")
+	}
+	ac.config.driver = _tx.drv
+	return ac
+}
+
+// String implements the fmt.Stringer.
+func (ac *AppCredential) String() string {
+	var builder strings.Builder
+	builder.WriteString("AppCredential(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ac.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(ac.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(ac.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("created_by=")
+	builder.WriteString(ac.CreatedBy)
+	builder.WriteString(", ")
+	builder.WriteString("updated_by=")
+	builder.WriteString(ac.UpdatedBy)
+	builder.WriteString(", ")
+	builder.WriteString("name=")
+	builder.WriteString(ac.Name)
+	builder.WriteString(", ")
+	builder.WriteString("app_key=")
+	builder.WriteString(ac.AppKey)
+	builder.WriteString(", ")
+	builder.WriteString("secret_ciphertext=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("status=")
+	builder.WriteString(fmt.Sprintf("%v", ac.Status))
+	builder.WriteString(", ")
+	builder.WriteString("rate_limit=")
+	builder.WriteString(fmt.Sprintf("%v", ac.RateLimit))
+	builder.WriteString(", ")
+	builder.WriteString("rate_window=")
+	builder.WriteString(fmt.Sprintf("%v", ac.RateWindow))
+	builder.WriteString(", ")
+	if v := ac.RevokedAt; v != nil {
+		builder.WriteString("revoked_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// AppCredentials is a parsable slice of AppCredential.
+type AppCredentials []*AppCredential
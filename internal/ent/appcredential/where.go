@@ -0,0 +1,705 @@
+// Code generated by ent, DO NOT EDIT.
+
+package appcredential
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldID, id))
+}
+
+// IDEqualFold applies the EqualFold predicate on the ID field.
+func IDEqualFold(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEqualFold(FieldID, id))
+}
+
+// IDContainsFold applies the ContainsFold predicate on the ID field.
+func IDContainsFold(id string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContainsFold(FieldID, id))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// CreatedBy applies equality check predicate on the "created_by" field. It's identical to CreatedByEQ.
+func CreatedBy(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldCreatedBy, v))
+}
+
+// UpdatedBy applies equality check predicate on the "updated_by" field. It's identical to UpdatedByEQ.
+func UpdatedBy(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldUpdatedBy, v))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldName, v))
+}
+
+// AppKey applies equality check predicate on the "app_key" field. It's identical to AppKeyEQ.
+func AppKey(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldAppKey, v))
+}
+
+// SecretCiphertext applies equality check predicate on the "secret_ciphertext" field. It's identical to SecretCiphertextEQ.
+func SecretCiphertext(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldSecretCiphertext, v))
+}
+
+// RateLimit applies equality check predicate on the "rate_limit" field. It's identical to RateLimitEQ.
+func RateLimit(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldRateLimit, v))
+}
+
+// RateWindow applies equality check predicate on the "rate_window" field. It's identical to RateWindowEQ.
+func RateWindow(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldRateWindow, v))
+}
+
+// RevokedAt applies equality check predicate on the "revoked_at" field. It's identical to RevokedAtEQ.
+func RevokedAt(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldRevokedAt, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// CreatedByEQ applies the EQ predicate on the "created_by" field.
+func CreatedByEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldCreatedBy, v))
+}
+
+// CreatedByNEQ applies the NEQ predicate on the "created_by" field.
+func CreatedByNEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldCreatedBy, v))
+}
+
+// CreatedByIn applies the In predicate on the "created_by" field.
+func CreatedByIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldCreatedBy, vs...))
+}
+
+// CreatedByNotIn applies the NotIn predicate on the "created_by" field.
+func CreatedByNotIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldCreatedBy, vs...))
+}
+
+// CreatedByGT applies the GT predicate on the "created_by" field.
+func CreatedByGT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldCreatedBy, v))
+}
+
+// CreatedByGTE applies the GTE predicate on the "created_by" field.
+func CreatedByGTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldCreatedBy, v))
+}
+
+// CreatedByLT applies the LT predicate on the "created_by" field.
+func CreatedByLT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldCreatedBy, v))
+}
+
+// CreatedByLTE applies the LTE predicate on the "created_by" field.
+func CreatedByLTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldCreatedBy, v))
+}
+
+// CreatedByContains applies the Contains predicate on the "created_by" field.
+func CreatedByContains(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContains(FieldCreatedBy, v))
+}
+
+// CreatedByHasPrefix applies the HasPrefix predicate on the "created_by" field.
+func CreatedByHasPrefix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasPrefix(FieldCreatedBy, v))
+}
+
+// CreatedByHasSuffix applies the HasSuffix predicate on the "created_by" field.
+func CreatedByHasSuffix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasSuffix(FieldCreatedBy, v))
+}
+
+// CreatedByIsNil applies the IsNil predicate on the "created_by" field.
+func CreatedByIsNil() predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIsNull(FieldCreatedBy))
+}
+
+// CreatedByNotNil applies the NotNil predicate on the "created_by" field.
+func CreatedByNotNil() predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotNull(FieldCreatedBy))
+}
+
+// CreatedByEqualFold applies the EqualFold predicate on the "created_by" field.
+func CreatedByEqualFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEqualFold(FieldCreatedBy, v))
+}
+
+// CreatedByContainsFold applies the ContainsFold predicate on the "created_by" field.
+func CreatedByContainsFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContainsFold(FieldCreatedBy, v))
+}
+
+// UpdatedByEQ applies the EQ predicate on the "updated_by" field.
+func UpdatedByEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldUpdatedBy, v))
+}
+
+// UpdatedByNEQ applies the NEQ predicate on the "updated_by" field.
+func UpdatedByNEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldUpdatedBy, v))
+}
+
+// UpdatedByIn applies the In predicate on the "updated_by" field.
+func UpdatedByIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldUpdatedBy, vs...))
+}
+
+// UpdatedByNotIn applies the NotIn predicate on the "updated_by" field.
+func UpdatedByNotIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldUpdatedBy, vs...))
+}
+
+// UpdatedByGT applies the GT predicate on the "updated_by" field.
+func UpdatedByGT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldUpdatedBy, v))
+}
+
+// UpdatedByGTE applies the GTE predicate on the "updated_by" field.
+func UpdatedByGTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldUpdatedBy, v))
+}
+
+// UpdatedByLT applies the LT predicate on the "updated_by" field.
+func UpdatedByLT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldUpdatedBy, v))
+}
+
+// UpdatedByLTE applies the LTE predicate on the "updated_by" field.
+func UpdatedByLTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldUpdatedBy, v))
+}
+
+// UpdatedByContains applies the Contains predicate on the "updated_by" field.
+func UpdatedByContains(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContains(FieldUpdatedBy, v))
+}
+
+// UpdatedByHasPrefix applies the HasPrefix predicate on the "updated_by" field.
+func UpdatedByHasPrefix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasPrefix(FieldUpdatedBy, v))
+}
+
+// UpdatedByHasSuffix applies the HasSuffix predicate on the "updated_by" field.
+func UpdatedByHasSuffix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasSuffix(FieldUpdatedBy, v))
+}
+
+// UpdatedByIsNil applies the IsNil predicate on the "updated_by" field.
+func UpdatedByIsNil() predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIsNull(FieldUpdatedBy))
+}
+
+// UpdatedByNotNil applies the NotNil predicate on the "updated_by" field.
+func UpdatedByNotNil() predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotNull(FieldUpdatedBy))
+}
+
+// UpdatedByEqualFold applies the EqualFold predicate on the "updated_by" field.
+func UpdatedByEqualFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEqualFold(FieldUpdatedBy, v))
+}
+
+// UpdatedByContainsFold applies the ContainsFold predicate on the "updated_by" field.
+func UpdatedByContainsFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContainsFold(FieldUpdatedBy, v))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContainsFold(FieldName, v))
+}
+
+// AppKeyEQ applies the EQ predicate on the "app_key" field.
+func AppKeyEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldAppKey, v))
+}
+
+// AppKeyNEQ applies the NEQ predicate on the "app_key" field.
+func AppKeyNEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldAppKey, v))
+}
+
+// AppKeyIn applies the In predicate on the "app_key" field.
+func AppKeyIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldAppKey, vs...))
+}
+
+// AppKeyNotIn applies the NotIn predicate on the "app_key" field.
+func AppKeyNotIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldAppKey, vs...))
+}
+
+// AppKeyGT applies the GT predicate on the "app_key" field.
+func AppKeyGT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldAppKey, v))
+}
+
+// AppKeyGTE applies the GTE predicate on the "app_key" field.
+func AppKeyGTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldAppKey, v))
+}
+
+// AppKeyLT applies the LT predicate on the "app_key" field.
+func AppKeyLT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldAppKey, v))
+}
+
+// AppKeyLTE applies the LTE predicate on the "app_key" field.
+func AppKeyLTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldAppKey, v))
+}
+
+// AppKeyContains applies the Contains predicate on the "app_key" field.
+func AppKeyContains(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContains(FieldAppKey, v))
+}
+
+// AppKeyHasPrefix applies the HasPrefix predicate on the "app_key" field.
+func AppKeyHasPrefix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasPrefix(FieldAppKey, v))
+}
+
+// AppKeyHasSuffix applies the HasSuffix predicate on the "app_key" field.
+func AppKeyHasSuffix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasSuffix(FieldAppKey, v))
+}
+
+// AppKeyEqualFold applies the EqualFold predicate on the "app_key" field.
+func AppKeyEqualFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEqualFold(FieldAppKey, v))
+}
+
+// AppKeyContainsFold applies the ContainsFold predicate on the "app_key" field.
+func AppKeyContainsFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContainsFold(FieldAppKey, v))
+}
+
+// SecretCiphertextEQ applies the EQ predicate on the "secret_ciphertext" field.
+func SecretCiphertextEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextNEQ applies the NEQ predicate on the "secret_ciphertext" field.
+func SecretCiphertextNEQ(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextIn applies the In predicate on the "secret_ciphertext" field.
+func SecretCiphertextIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldSecretCiphertext, vs...))
+}
+
+// SecretCiphertextNotIn applies the NotIn predicate on the "secret_ciphertext" field.
+func SecretCiphertextNotIn(vs ...string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldSecretCiphertext, vs...))
+}
+
+// SecretCiphertextGT applies the GT predicate on the "secret_ciphertext" field.
+func SecretCiphertextGT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextGTE applies the GTE predicate on the "secret_ciphertext" field.
+func SecretCiphertextGTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextLT applies the LT predicate on the "secret_ciphertext" field.
+func SecretCiphertextLT(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextLTE applies the LTE predicate on the "secret_ciphertext" field.
+func SecretCiphertextLTE(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextContains applies the Contains predicate on the "secret_ciphertext" field.
+func SecretCiphertextContains(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContains(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextHasPrefix applies the HasPrefix predicate on the "secret_ciphertext" field.
+func SecretCiphertextHasPrefix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasPrefix(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextHasSuffix applies the HasSuffix predicate on the "secret_ciphertext" field.
+func SecretCiphertextHasSuffix(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldHasSuffix(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextEqualFold applies the EqualFold predicate on the "secret_ciphertext" field.
+func SecretCiphertextEqualFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEqualFold(FieldSecretCiphertext, v))
+}
+
+// SecretCiphertextContainsFold applies the ContainsFold predicate on the "secret_ciphertext" field.
+func SecretCiphertextContainsFold(v string) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldContainsFold(FieldSecretCiphertext, v))
+}
+
+// StatusEQ applies the EQ predicate on the "status" field.
+func StatusEQ(v Status) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldStatus, v))
+}
+
+// StatusNEQ applies the NEQ predicate on the "status" field.
+func StatusNEQ(v Status) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldStatus, v))
+}
+
+// StatusIn applies the In predicate on the "status" field.
+func StatusIn(vs ...Status) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldStatus, vs...))
+}
+
+// StatusNotIn applies the NotIn predicate on the "status" field.
+func StatusNotIn(vs ...Status) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldStatus, vs...))
+}
+
+// RateLimitEQ applies the EQ predicate on the "rate_limit" field.
+func RateLimitEQ(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldRateLimit, v))
+}
+
+// RateLimitNEQ applies the NEQ predicate on the "rate_limit" field.
+func RateLimitNEQ(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldRateLimit, v))
+}
+
+// RateLimitIn applies the In predicate on the "rate_limit" field.
+func RateLimitIn(vs ...int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldRateLimit, vs...))
+}
+
+// RateLimitNotIn applies the NotIn predicate on the "rate_limit" field.
+func RateLimitNotIn(vs ...int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldRateLimit, vs...))
+}
+
+// RateLimitGT applies the GT predicate on the "rate_limit" field.
+func RateLimitGT(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldRateLimit, v))
+}
+
+// RateLimitGTE applies the GTE predicate on the "rate_limit" field.
+func RateLimitGTE(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldRateLimit, v))
+}
+
+// RateLimitLT applies the LT predicate on the "rate_limit" field.
+func RateLimitLT(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldRateLimit, v))
+}
+
+// RateLimitLTE applies the LTE predicate on the "rate_limit" field.
+func RateLimitLTE(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldRateLimit, v))
+}
+
+// RateWindowEQ applies the EQ predicate on the "rate_window" field.
+func RateWindowEQ(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldRateWindow, v))
+}
+
+// RateWindowNEQ applies the NEQ predicate on the "rate_window" field.
+func RateWindowNEQ(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldRateWindow, v))
+}
+
+// RateWindowIn applies the In predicate on the "rate_window" field.
+func RateWindowIn(vs ...int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldRateWindow, vs...))
+}
+
+// RateWindowNotIn applies the NotIn predicate on the "rate_window" field.
+func RateWindowNotIn(vs ...int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldRateWindow, vs...))
+}
+
+// RateWindowGT applies the GT predicate on the "rate_window" field.
+func RateWindowGT(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldRateWindow, v))
+}
+
+// RateWindowGTE applies the GTE predicate on the "rate_window" field.
+func RateWindowGTE(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldRateWindow, v))
+}
+
+// RateWindowLT applies the LT predicate on the "rate_window" field.
+func RateWindowLT(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldRateWindow, v))
+}
+
+// RateWindowLTE applies the LTE predicate on the "rate_window" field.
+func RateWindowLTE(v int) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldRateWindow, v))
+}
+
+// RevokedAtEQ applies the EQ predicate on the "revoked_at" field.
+func RevokedAtEQ(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldEQ(FieldRevokedAt, v))
+}
+
+// RevokedAtNEQ applies the NEQ predicate on the "revoked_at" field.
+func RevokedAtNEQ(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNEQ(FieldRevokedAt, v))
+}
+
+// RevokedAtIn applies the In predicate on the "revoked_at" field.
+func RevokedAtIn(vs ...time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIn(FieldRevokedAt, vs...))
+}
+
+// RevokedAtNotIn applies the NotIn predicate on the "revoked_at" field.
+func RevokedAtNotIn(vs ...time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotIn(FieldRevokedAt, vs...))
+}
+
+// RevokedAtGT applies the GT predicate on the "revoked_at" field.
+func RevokedAtGT(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGT(FieldRevokedAt, v))
+}
+
+// RevokedAtGTE applies the GTE predicate on the "revoked_at" field.
+func RevokedAtGTE(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldGTE(FieldRevokedAt, v))
+}
+
+// RevokedAtLT applies the LT predicate on the "revoked_at" field.
+func RevokedAtLT(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLT(FieldRevokedAt, v))
+}
+
+// RevokedAtLTE applies the LTE predicate on the "revoked_at" field.
+func RevokedAtLTE(v time.Time) predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldLTE(FieldRevokedAt, v))
+}
+
+// RevokedAtIsNil applies the IsNil predicate on the "revoked_at" field.
+func RevokedAtIsNil() predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldIsNull(FieldRevokedAt))
+}
+
+// RevokedAtNotNil applies the NotNil predicate on the "revoked_at" field.
+func RevokedAtNotNil() predicate.AppCredential {
+	return predicate.AppCredential(sql.FieldNotNull(FieldRevokedAt))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.AppCredential) predicate.AppCredential {
+	return predicate.AppCredential(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.AppCredential) predicate.AppCredential {
+	return predicate.AppCredential(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.AppCredential) predicate.AppCredential {
+	return predicate.AppCredential(sql.NotPredicates(p))
+}
@@ -0,0 +1,465 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/appcredential"
+)
+
+// AppCredentialCreate is the builder for creating a AppCredential entity.
+type AppCredentialCreate struct {
+	config
+	mutation *AppCredentialMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (acc *AppCredentialCreate) SetCreatedAt(t time.Time) *AppCredentialCreate {
+	acc.mutation.SetCreatedAt(t)
+	return acc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableCreatedAt(t *time.Time) *AppCredentialCreate {
+	if t != nil {
+		acc.SetCreatedAt(*t)
+	}
+	return acc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (acc *AppCredentialCreate) SetUpdatedAt(t time.Time) *AppCredentialCreate {
+	acc.mutation.SetUpdatedAt(t)
+	return acc
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableUpdatedAt(t *time.Time) *AppCredentialCreate {
+	if t != nil {
+		acc.SetUpdatedAt(*t)
+	}
+	return acc
+}
+
+// SetCreatedBy sets the "created_by" field.
+func (acc *AppCredentialCreate) SetCreatedBy(s string) *AppCredentialCreate {
+	acc.mutation.SetCreatedBy(s)
+	return acc
+}
+
+// SetNillableCreatedBy sets the "created_by" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableCreatedBy(s *string) *AppCredentialCreate {
+	if s != nil {
+		acc.SetCreatedBy(*s)
+	}
+	return acc
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (acc *AppCredentialCreate) SetUpdatedBy(s string) *AppCredentialCreate {
+	acc.mutation.SetUpdatedBy(s)
+	return acc
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableUpdatedBy(s *string) *AppCredentialCreate {
+	if s != nil {
+		acc.SetUpdatedBy(*s)
+	}
+	return acc
+}
+
+// SetName sets the "name" field.
+func (acc *AppCredentialCreate) SetName(s string) *AppCredentialCreate {
+	acc.mutation.SetName(s)
+	return acc
+}
+
+// SetAppKey sets the "app_key" field.
+func (acc *AppCredentialCreate) SetAppKey(s string) *AppCredentialCreate {
+	acc.mutation.SetAppKey(s)
+	return acc
+}
+
+// SetSecretCiphertext sets the "secret_ciphertext" field.
+func (acc *AppCredentialCreate) SetSecretCiphertext(s string) *AppCredentialCreate {
+	acc.mutation.SetSecretCiphertext(s)
+	return acc
+}
+
+// SetStatus sets the "status" field.
+func (acc *AppCredentialCreate) SetStatus(a appcredential.Status) *AppCredentialCreate {
+	acc.mutation.SetStatus(a)
+	return acc
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableStatus(a *appcredential.Status) *AppCredentialCreate {
+	if a != nil {
+		acc.SetStatus(*a)
+	}
+	return acc
+}
+
+// SetRateLimit sets the "rate_limit" field.
+func (acc *AppCredentialCreate) SetRateLimit(i int) *AppCredentialCreate {
+	acc.mutation.SetRateLimit(i)
+	return acc
+}
+
+// SetNillableRateLimit sets the "rate_limit" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableRateLimit(i *int) *AppCredentialCreate {
+	if i != nil {
+		acc.SetRateLimit(*i)
+	}
+	return acc
+}
+
+// SetRateWindow sets the "rate_window" field.
+func (acc *AppCredentialCreate) SetRateWindow(i int) *AppCredentialCreate {
+	acc.mutation.SetRateWindow(i)
+	return acc
+}
+
+// SetNillableRateWindow sets the "rate_window" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableRateWindow(i *int) *AppCredentialCreate {
+	if i != nil {
+		acc.SetRateWindow(*i)
+	}
+	return acc
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (acc *AppCredentialCreate) SetRevokedAt(t time.Time) *AppCredentialCreate {
+	acc.mutation.SetRevokedAt(t)
+	return acc
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableRevokedAt(t *time.Time) *AppCredentialCreate {
+	if t != nil {
+		acc.SetRevokedAt(*t)
+	}
+	return acc
+}
+
+// SetID sets the "id" field.
+func (acc *AppCredentialCreate) SetID(s string) *AppCredentialCreate {
+	acc.mutation.SetID(s)
+	return acc
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (acc *AppCredentialCreate) SetNillableID(s *string) *AppCredentialCreate {
+	if s != nil {
+		acc.SetID(*s)
+	}
+	return acc
+}
+
+// Mutation returns the AppCredentialMutation object of the builder.
+func (acc *AppCredentialCreate) Mutation() *AppCredentialMutation {
+	return acc.mutation
+}
+
+// Save creates the AppCredential in the database.
+func (acc *AppCredentialCreate) Save(ctx context.Context) (*AppCredential, error) {
+	if err := acc.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, acc.sqlSave, acc.mutation, acc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (acc *AppCredentialCreate) SaveX(ctx context.Context) *AppCredential {
+	v, err := acc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (acc *AppCredentialCreate) Exec(ctx context.Context) error {
+	_, err := acc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (acc *AppCredentialCreate) ExecX(ctx context.Context) {
+	if err := acc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (acc *AppCredentialCreate) defaults() error {
+	if _, ok := acc.mutation.CreatedAt(); !ok {
+		if appcredential.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized appcredential.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
+		v := appcredential.DefaultCreatedAt()
+		acc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := acc.mutation.UpdatedAt(); !ok {
+		if appcredential.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized appcredential.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := appcredential.DefaultUpdatedAt()
+		acc.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := acc.mutation.Status(); !ok {
+		v := appcredential.DefaultStatus
+		acc.mutation.SetStatus(v)
+	}
+	if _, ok := acc.mutation.RateLimit(); !ok {
+		v := appcredential.DefaultRateLimit
+		acc.mutation.SetRateLimit(v)
+	}
+	if _, ok := acc.mutation.RateWindow(); !ok {
+		v := appcredential.DefaultRateWindow
+		acc.mutation.SetRateWindow(v)
+	}
+	if _, ok := acc.mutation.ID(); !ok {
+		if appcredential.DefaultID == nil {
+			return fmt.Errorf("ent: uninitialized appcredential.DefaultID (forgotten import ent/runtime?)")
+		}
+		v := appcredential.DefaultID()
+		acc.mutation.SetID(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (acc *AppCredentialCreate) check() error {
+	if _, ok := acc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "AppCredential.created_at"`)}
+	}
+	if _, ok := acc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "AppCredential.updated_at"`)}
+	}
+	if _, ok := acc.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "AppCredential.name"`)}
+	}
+	if v, ok := acc.mutation.Name(); ok {
+		if err := appcredential.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "AppCredential.name": %w`, err)}
+		}
+	}
+	if _, ok := acc.mutation.AppKey(); !ok {
+		return &ValidationError{Name: "app_key", err: errors.New(`ent: missing required field "AppCredential.app_key"`)}
+	}
+	if v, ok := acc.mutation.AppKey(); ok {
+		if err := appcredential.AppKeyValidator(v); err != nil {
+			return &ValidationError{Name: "app_key", err: fmt.Errorf(`ent: validator failed for field "AppCredential.app_key": %w`, err)}
+		}
+	}
+	if _, ok := acc.mutation.SecretCiphertext(); !ok {
+		return &ValidationError{Name: "secret_ciphertext", err: errors.New(`ent: missing required field "AppCredential.secret_ciphertext"`)}
+	}
+	if v, ok := acc.mutation.SecretCiphertext(); ok {
+		if err := appcredential.SecretCiphertextValidator(v); err != nil {
+			return &ValidationError{Name: "secret_ciphertext", err: fmt.Errorf(`ent: validator failed for field "AppCredential.secret_ciphertext": %w`, err)}
+		}
+	}
+	if _, ok := acc.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "AppCredential.status"`)}
+	}
+	if v, ok := acc.mutation.Status(); ok {
+		if err := appcredential.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "AppCredential.status": %w`, err)}
+		}
+	}
+	if _, ok := acc.mutation.RateLimit(); !ok {
+		return &ValidationError{Name: "rate_limit", err: errors.New(`ent: missing required field "AppCredential.rate_limit"`)}
+	}
+	if v, ok := acc.mutation.RateLimit(); ok {
+		if err := appcredential.RateLimitValidator(v); err != nil {
+			return &ValidationError{Name: "rate_limit", err: fmt.Errorf(`ent: validator failed for field "AppCredential.rate_limit": %w`, err)}
+		}
+	}
+	if _, ok := acc.mutation.RateWindow(); !ok {
+		return &ValidationError{Name: "rate_window", err: errors.New(`ent: missing required field "AppCredential.rate_window"`)}
+	}
+	if v, ok := acc.mutation.RateWindow(); ok {
+		if err := appcredential.RateWindowValidator(v); err != nil {
+			return &ValidationError{Name: "rate_window", err: fmt.Errorf(`ent: validator failed for field "AppCredential.rate_window": %w`, err)}
+		}
+	}
+	if v, ok := acc.mutation.ID(); ok {
+		if err := appcredential.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`ent: validator failed for field "AppCredential.id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (acc *AppCredentialCreate) sqlSave(ctx context.Context) (*AppCredential, error) {
+	if err := acc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := acc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, acc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected AppCredential.ID type: %T", _spec.ID.Value)
+		}
+	}
+	acc.mutation.id = &_node.ID
+	acc.mutation.done = true
+	return _node, nil
+}
+
+func (acc *AppCredentialCreate) createSpec() (*AppCredential, *sqlgraph.CreateSpec) {
+	var (
+		_node = &AppCredential{config: acc.config}
+		_spec = sqlgraph.NewCreateSpec(appcredential.Table, sqlgraph.NewFieldSpec(appcredential.FieldID, field.TypeString))
+	)
+	if id, ok := acc.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := acc.mutation.CreatedAt(); ok {
+		_spec.SetField(appcredential.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := acc.mutation.UpdatedAt(); ok {
+		_spec.SetField(appcredential.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := acc.mutation.CreatedBy(); ok {
+		_spec.SetField(appcredential.FieldCreatedBy, field.TypeString, value)
+		_node.CreatedBy = value
+	}
+	if value, ok := acc.mutation.UpdatedBy(); ok {
+		_spec.SetField(appcredential.FieldUpdatedBy, field.TypeString, value)
+		_node.UpdatedBy = value
+	}
+	if value, ok := acc.mutation.Name(); ok {
+		_spec.SetField(appcredential.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := acc.mutation.AppKey(); ok {
+		_spec.SetField(appcredential.FieldAppKey, field.TypeString, value)
+		_node.AppKey = value
+	}
+	if value, ok := acc.mutation.SecretCiphertext(); ok {
+		_spec.SetField(appcredential.FieldSecretCiphertext, field.TypeString, value)
+		_node.SecretCiphertext = value
+	}
+	if value, ok := acc.mutation.Status(); ok {
+		_spec.SetField(appcredential.FieldStatus, field.TypeEnum, value)
+		_node.Status = value
+	}
+	if value, ok := acc.mutation.RateLimit(); ok {
+		_spec.SetField(appcredential.FieldRateLimit, field.TypeInt, value)
+		_node.RateLimit = value
+	}
+	if value, ok := acc.mutation.RateWindow(); ok {
+		_spec.SetField(appcredential.FieldRateWindow, field.TypeInt, value)
+		_node.RateWindow = value
+	}
+	if value, ok := acc.mutation.RevokedAt(); ok {
+		_spec.SetField(appcredential.FieldRevokedAt, field.TypeTime, value)
+		_node.RevokedAt = &value
+	}
+	return _node, _spec
+}
+
+// AppCredentialCreateBulk is the builder for creating many AppCredential entities in bulk.
+type AppCredentialCreateBulk struct {
+	config
+	err      error
+	builders []*AppCredentialCreate
+}
+
+// Save creates the AppCredential entities in the database.
+func (accb *AppCredentialCreateBulk) Save(ctx context.Context) ([]*AppCredential, error) {
+	if accb.err != nil {
+		return nil, accb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(accb.builders))
+	nodes := make([]*AppCredential, len(accb.builders))
+	mutators := make([]Mutator, len(accb.builders))
+	for i := range accb.builders {
+		func(i int, root context.Context) {
+			builder := accb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*AppCredentialMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, accb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, accb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, accb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (accb *AppCredentialCreateBulk) SaveX(ctx context.Context) []*AppCredential {
+	v, err := accb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (accb *AppCredentialCreateBulk) Exec(ctx context.Context) error {
+	_, err := accb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (accb *AppCredentialCreateBulk) ExecX(ctx context.Context) {
+	if err := accb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
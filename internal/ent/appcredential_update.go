@@ -0,0 +1,626 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/appcredential"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+)
+
+// AppCredentialUpdate is the builder for updating AppCredential entities.
+type AppCredentialUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *AppCredentialMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the AppCredentialUpdate builder.
+func (acu *AppCredentialUpdate) Where(ps ...predicate.AppCredential) *AppCredentialUpdate {
+	acu.mutation.Where(ps...)
+	return acu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (acu *AppCredentialUpdate) SetUpdatedAt(t time.Time) *AppCredentialUpdate {
+	acu.mutation.SetUpdatedAt(t)
+	return acu
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (acu *AppCredentialUpdate) SetUpdatedBy(s string) *AppCredentialUpdate {
+	acu.mutation.SetUpdatedBy(s)
+	return acu
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (acu *AppCredentialUpdate) SetNillableUpdatedBy(s *string) *AppCredentialUpdate {
+	if s != nil {
+		acu.SetUpdatedBy(*s)
+	}
+	return acu
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (acu *AppCredentialUpdate) ClearUpdatedBy() *AppCredentialUpdate {
+	acu.mutation.ClearUpdatedBy()
+	return acu
+}
+
+// SetName sets the "name" field.
+func (acu *AppCredentialUpdate) SetName(s string) *AppCredentialUpdate {
+	acu.mutation.SetName(s)
+	return acu
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (acu *AppCredentialUpdate) SetNillableName(s *string) *AppCredentialUpdate {
+	if s != nil {
+		acu.SetName(*s)
+	}
+	return acu
+}
+
+// SetSecretCiphertext sets the "secret_ciphertext" field.
+func (acu *AppCredentialUpdate) SetSecretCiphertext(s string) *AppCredentialUpdate {
+	acu.mutation.SetSecretCiphertext(s)
+	return acu
+}
+
+// SetNillableSecretCiphertext sets the "secret_ciphertext" field if the given value is not nil.
+func (acu *AppCredentialUpdate) SetNillableSecretCiphertext(s *string) *AppCredentialUpdate {
+	if s != nil {
+		acu.SetSecretCiphertext(*s)
+	}
+	return acu
+}
+
+// SetStatus sets the "status" field.
+func (acu *AppCredentialUpdate) SetStatus(a appcredential.Status) *AppCredentialUpdate {
+	acu.mutation.SetStatus(a)
+	return acu
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (acu *AppCredentialUpdate) SetNillableStatus(a *appcredential.Status) *AppCredentialUpdate {
+	if a != nil {
+		acu.SetStatus(*a)
+	}
+	return acu
+}
+
+// SetRateLimit sets the "rate_limit" field.
+func (acu *AppCredentialUpdate) SetRateLimit(i int) *AppCredentialUpdate {
+	acu.mutation.ResetRateLimit()
+	acu.mutation.SetRateLimit(i)
+	return acu
+}
+
+// SetNillableRateLimit sets the "rate_limit" field if the given value is not nil.
+func (acu *AppCredentialUpdate) SetNillableRateLimit(i *int) *AppCredentialUpdate {
+	if i != nil {
+		acu.SetRateLimit(*i)
+	}
+	return acu
+}
+
+// AddRateLimit adds i to the "rate_limit" field.
+func (acu *AppCredentialUpdate) AddRateLimit(i int) *AppCredentialUpdate {
+	acu.mutation.AddRateLimit(i)
+	return acu
+}
+
+// SetRateWindow sets the "rate_window" field.
+func (acu *AppCredentialUpdate) SetRateWindow(i int) *AppCredentialUpdate {
+	acu.mutation.ResetRateWindow()
+	acu.mutation.SetRateWindow(i)
+	return acu
+}
+
+// SetNillableRateWindow sets the "rate_window" field if the given value is not nil.
+func (acu *AppCredentialUpdate) SetNillableRateWindow(i *int) *AppCredentialUpdate {
+	if i != nil {
+		acu.SetRateWindow(*i)
+	}
+	return acu
+}
+
+// AddRateWindow adds i to the "rate_window" field.
+func (acu *AppCredentialUpdate) AddRateWindow(i int) *AppCredentialUpdate {
+	acu.mutation.AddRateWindow(i)
+	return acu
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (acu *AppCredentialUpdate) SetRevokedAt(t time.Time) *AppCredentialUpdate {
+	acu.mutation.SetRevokedAt(t)
+	return acu
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (acu *AppCredentialUpdate) SetNillableRevokedAt(t *time.Time) *AppCredentialUpdate {
+	if t != nil {
+		acu.SetRevokedAt(*t)
+	}
+	return acu
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (acu *AppCredentialUpdate) ClearRevokedAt() *AppCredentialUpdate {
+	acu.mutation.ClearRevokedAt()
+	return acu
+}
+
+// Mutation returns the AppCredentialMutation object of the builder.
+func (acu *AppCredentialUpdate) Mutation() *AppCredentialMutation {
+	return acu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (acu *AppCredentialUpdate) Save(ctx context.Context) (int, error) {
+	if err := acu.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks(ctx, acu.sqlSave, acu.mutation, acu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (acu *AppCredentialUpdate) SaveX(ctx context.Context) int {
+	affected, err := acu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (acu *AppCredentialUpdate) Exec(ctx context.Context) error {
+	_, err := acu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (acu *AppCredentialUpdate) ExecX(ctx context.Context) {
+	if err := acu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (acu *AppCredentialUpdate) defaults() error {
+	if _, ok := acu.mutation.UpdatedAt(); !ok {
+		if appcredential.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized appcredential.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := appcredential.UpdateDefaultUpdatedAt()
+		acu.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (acu *AppCredentialUpdate) check() error {
+	if v, ok := acu.mutation.Name(); ok {
+		if err := appcredential.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "AppCredential.name": %w`, err)}
+		}
+	}
+	if v, ok := acu.mutation.SecretCiphertext(); ok {
+		if err := appcredential.SecretCiphertextValidator(v); err != nil {
+			return &ValidationError{Name: "secret_ciphertext", err: fmt.Errorf(`ent: validator failed for field "AppCredential.secret_ciphertext": %w`, err)}
+		}
+	}
+	if v, ok := acu.mutation.Status(); ok {
+		if err := appcredential.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "AppCredential.status": %w`, err)}
+		}
+	}
+	if v, ok := acu.mutation.RateLimit(); ok {
+		if err := appcredential.RateLimitValidator(v); err != nil {
+			return &ValidationError{Name: "rate_limit", err: fmt.Errorf(`ent: validator failed for field "AppCredential.rate_limit": %w`, err)}
+		}
+	}
+	if v, ok := acu.mutation.RateWindow(); ok {
+		if err := appcredential.RateWindowValidator(v); err != nil {
+			return &ValidationError{Name: "rate_window", err: fmt.Errorf(`ent: validator failed for field "AppCredential.rate_window": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (acu *AppCredentialUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *AppCredentialUpdate {
+	acu.modifiers = append(acu.modifiers, modifiers...)
+	return acu
+}
+
+func (acu *AppCredentialUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := acu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(appcredential.Table, appcredential.Columns, sqlgraph.NewFieldSpec(appcredential.FieldID, field.TypeString))
+	if ps := acu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := acu.mutation.UpdatedAt(); ok {
+		_spec.SetField(appcredential.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if acu.mutation.CreatedByCleared() {
+		_spec.ClearField(appcredential.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := acu.mutation.UpdatedBy(); ok {
+		_spec.SetField(appcredential.FieldUpdatedBy, field.TypeString, value)
+	}
+	if acu.mutation.UpdatedByCleared() {
+		_spec.ClearField(appcredential.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := acu.mutation.Name(); ok {
+		_spec.SetField(appcredential.FieldName, field.TypeString, value)
+	}
+	if value, ok := acu.mutation.SecretCiphertext(); ok {
+		_spec.SetField(appcredential.FieldSecretCiphertext, field.TypeString, value)
+	}
+	if value, ok := acu.mutation.Status(); ok {
+		_spec.SetField(appcredential.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := acu.mutation.RateLimit(); ok {
+		_spec.SetField(appcredential.FieldRateLimit, field.TypeInt, value)
+	}
+	if value, ok := acu.mutation.AddedRateLimit(); ok {
+		_spec.AddField(appcredential.FieldRateLimit, field.TypeInt, value)
+	}
+	if value, ok := acu.mutation.RateWindow(); ok {
+		_spec.SetField(appcredential.FieldRateWindow, field.TypeInt, value)
+	}
+	if value, ok := acu.mutation.AddedRateWindow(); ok {
+		_spec.AddField(appcredential.FieldRateWindow, field.TypeInt, value)
+	}
+	if value, ok := acu.mutation.RevokedAt(); ok {
+		_spec.SetField(appcredential.FieldRevokedAt, field.TypeTime, value)
+	}
+	if acu.mutation.RevokedAtCleared() {
+		_spec.ClearField(appcredential.FieldRevokedAt, field.TypeTime)
+	}
+	_spec.AddModifiers(acu.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, acu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{appcredential.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	acu.mutation.done = true
+	return n, nil
+}
+
+// AppCredentialUpdateOne is the builder for updating a single AppCredential entity.
+type AppCredentialUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *AppCredentialMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (acuo *AppCredentialUpdateOne) SetUpdatedAt(t time.Time) *AppCredentialUpdateOne {
+	acuo.mutation.SetUpdatedAt(t)
+	return acuo
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (acuo *AppCredentialUpdateOne) SetUpdatedBy(s string) *AppCredentialUpdateOne {
+	acuo.mutation.SetUpdatedBy(s)
+	return acuo
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (acuo *AppCredentialUpdateOne) SetNillableUpdatedBy(s *string) *AppCredentialUpdateOne {
+	if s != nil {
+		acuo.SetUpdatedBy(*s)
+	}
+	return acuo
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (acuo *AppCredentialUpdateOne) ClearUpdatedBy() *AppCredentialUpdateOne {
+	acuo.mutation.ClearUpdatedBy()
+	return acuo
+}
+
+// SetName sets the "name" field.
+func (acuo *AppCredentialUpdateOne) SetName(s string) *AppCredentialUpdateOne {
+	acuo.mutation.SetName(s)
+	return acuo
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (acuo *AppCredentialUpdateOne) SetNillableName(s *string) *AppCredentialUpdateOne {
+	if s != nil {
+		acuo.SetName(*s)
+	}
+	return acuo
+}
+
+// SetSecretCiphertext sets the "secret_ciphertext" field.
+func (acuo *AppCredentialUpdateOne) SetSecretCiphertext(s string) *AppCredentialUpdateOne {
+	acuo.mutation.SetSecretCiphertext(s)
+	return acuo
+}
+
+// SetNillableSecretCiphertext sets the "secret_ciphertext" field if the given value is not nil.
+func (acuo *AppCredentialUpdateOne) SetNillableSecretCiphertext(s *string) *AppCredentialUpdateOne {
+	if s != nil {
+		acuo.SetSecretCiphertext(*s)
+	}
+	return acuo
+}
+
+// SetStatus sets the "status" field.
+func (acuo *AppCredentialUpdateOne) SetStatus(a appcredential.Status) *AppCredentialUpdateOne {
+	acuo.mutation.SetStatus(a)
+	return acuo
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (acuo *AppCredentialUpdateOne) SetNillableStatus(a *appcredential.Status) *AppCredentialUpdateOne {
+	if a != nil {
+		acuo.SetStatus(*a)
+	}
+	return acuo
+}
+
+// SetRateLimit sets the "rate_limit" field.
+func (acuo *AppCredentialUpdateOne) SetRateLimit(i int) *AppCredentialUpdateOne {
+	acuo.mutation.ResetRateLimit()
+	acuo.mutation.SetRateLimit(i)
+	return acuo
+}
+
+// SetNillableRateLimit sets the "rate_limit" field if the given value is not nil.
+func (acuo *AppCredentialUpdateOne) SetNillableRateLimit(i *int) *AppCredentialUpdateOne {
+	if i != nil {
+		acuo.SetRateLimit(*i)
+	}
+	return acuo
+}
+
+// AddRateLimit adds i to the "rate_limit" field.
+func (acuo *AppCredentialUpdateOne) AddRateLimit(i int) *AppCredentialUpdateOne {
+	acuo.mutation.AddRateLimit(i)
+	return acuo
+}
+
+// SetRateWindow sets the "rate_window" field.
+func (acuo *AppCredentialUpdateOne) SetRateWindow(i int) *AppCredentialUpdateOne {
+	acuo.mutation.ResetRateWindow()
+	acuo.mutation.SetRateWindow(i)
+	return acuo
+}
+
+// SetNillableRateWindow sets the "rate_window" field if the given value is not nil.
+func (acuo *AppCredentialUpdateOne) SetNillableRateWindow(i *int) *AppCredentialUpdateOne {
+	if i != nil {
+		acuo.SetRateWindow(*i)
+	}
+	return acuo
+}
+
+// AddRateWindow adds i to the "rate_window" field.
+func (acuo *AppCredentialUpdateOne) AddRateWindow(i int) *AppCredentialUpdateOne {
+	acuo.mutation.AddRateWindow(i)
+	return acuo
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (acuo *AppCredentialUpdateOne) SetRevokedAt(t time.Time) *AppCredentialUpdateOne {
+	acuo.mutation.SetRevokedAt(t)
+	return acuo
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (acuo *AppCredentialUpdateOne) SetNillableRevokedAt(t *time.Time) *AppCredentialUpdateOne {
+	if t != nil {
+		acuo.SetRevokedAt(*t)
+	}
+	return acuo
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (acuo *AppCredentialUpdateOne) ClearRevokedAt() *AppCredentialUpdateOne {
+	acuo.mutation.ClearRevokedAt()
+	return acuo
+}
+
+// Mutation returns the AppCredentialMutation object of the builder.
+func (acuo *AppCredentialUpdateOne) Mutation() *AppCredentialMutation {
+	return acuo.mutation
+}
+
+// Where appends a list predicates to the AppCredentialUpdate builder.
+func (acuo *AppCredentialUpdateOne) Where(ps ...predicate.AppCredential) *AppCredentialUpdateOne {
+	acuo.mutation.Where(ps...)
+	return acuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (acuo *AppCredentialUpdateOne) Select(field string, fields ...string) *AppCredentialUpdateOne {
+	acuo.fields = append([]string{field}, fields...)
+	return acuo
+}
+
+// Save executes the query and returns the updated AppCredential entity.
+func (acuo *AppCredentialUpdateOne) Save(ctx context.Context) (*AppCredential, error) {
+	if err := acuo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, acuo.sqlSave, acuo.mutation, acuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (acuo *AppCredentialUpdateOne) SaveX(ctx context.Context) *AppCredential {
+	node, err := acuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (acuo *AppCredentialUpdateOne) Exec(ctx context.Context) error {
+	_, err := acuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (acuo *AppCredentialUpdateOne) ExecX(ctx context.Context) {
+	if err := acuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (acuo *AppCredentialUpdateOne) defaults() error {
+	if _, ok := acuo.mutation.UpdatedAt(); !ok {
+		if appcredential.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized appcredential.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := appcredential.UpdateDefaultUpdatedAt()
+		acuo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (acuo *AppCredentialUpdateOne) check() error {
+	if v, ok := acuo.mutation.Name(); ok {
+		if err := appcredential.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "AppCredential.name": %w`, err)}
+		}
+	}
+	if v, ok := acuo.mutation.SecretCiphertext(); ok {
+		if err := appcredential.SecretCiphertextValidator(v); err != nil {
+			return &ValidationError{Name: "secret_ciphertext", err: fmt.Errorf(`ent: validator failed for field "AppCredential.secret_ciphertext": %w`, err)}
+		}
+	}
+	if v, ok := acuo.mutation.Status(); ok {
+		if err := appcredential.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "AppCredential.status": %w`, err)}
+		}
+	}
+	if v, ok := acuo.mutation.RateLimit(); ok {
+		if err := appcredential.RateLimitValidator(v); err != nil {
+			return &ValidationError{Name: "rate_limit", err: fmt.Errorf(`ent: validator failed for field "AppCredential.rate_limit": %w`, err)}
+		}
+	}
+	if v, ok := acuo.mutation.RateWindow(); ok {
+		if err := appcredential.RateWindowValidator(v); err != nil {
+			return &ValidationError{Name: "rate_window", err: fmt.Errorf(`ent: validator failed for field "AppCredential.rate_window": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (acuo *AppCredentialUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *AppCredentialUpdateOne {
+	acuo.modifiers = append(acuo.modifiers, modifiers...)
+	return acuo
+}
+
+func (acuo *AppCredentialUpdateOne) sqlSave(ctx context.Context) (_node *AppCredential, err error) {
+	if err := acuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(appcredential.Table, appcredential.Columns, sqlgraph.NewFieldSpec(appcredential.FieldID, field.TypeString))
+	id, ok := acuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "AppCredential.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := acuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, appcredential.FieldID)
+		for _, f := range fields {
+			if !appcredential.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != appcredential.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := acuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := acuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(appcredential.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if acuo.mutation.CreatedByCleared() {
+		_spec.ClearField(appcredential.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := acuo.mutation.UpdatedBy(); ok {
+		_spec.SetField(appcredential.FieldUpdatedBy, field.TypeString, value)
+	}
+	if acuo.mutation.UpdatedByCleared() {
+		_spec.ClearField(appcredential.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := acuo.mutation.Name(); ok {
+		_spec.SetField(appcredential.FieldName, field.TypeString, value)
+	}
+	if value, ok := acuo.mutation.SecretCiphertext(); ok {
+		_spec.SetField(appcredential.FieldSecretCiphertext, field.TypeString, value)
+	}
+	if value, ok := acuo.mutation.Status(); ok {
+		_spec.SetField(appcredential.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := acuo.mutation.RateLimit(); ok {
+		_spec.SetField(appcredential.FieldRateLimit, field.TypeInt, value)
+	}
+	if value, ok := acuo.mutation.AddedRateLimit(); ok {
+		_spec.AddField(appcredential.FieldRateLimit, field.TypeInt, value)
+	}
+	if value, ok := acuo.mutation.RateWindow(); ok {
+		_spec.SetField(appcredential.FieldRateWindow, field.TypeInt, value)
+	}
+	if value, ok := acuo.mutation.AddedRateWindow(); ok {
+		_spec.AddField(appcredential.FieldRateWindow, field.TypeInt, value)
+	}
+	if value, ok := acuo.mutation.RevokedAt(); ok {
+		_spec.SetField(appcredential.FieldRevokedAt, field.TypeTime, value)
+	}
+	if acuo.mutation.RevokedAtCleared() {
+		_spec.ClearField(appcredential.FieldRevokedAt, field.TypeTime)
+	}
+	_spec.AddModifiers(acuo.modifiers...)
+	_node = &AppCredential{config: acuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, acuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{appcredential.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	acuo.mutation.done = true
+	return _node, nil
+}
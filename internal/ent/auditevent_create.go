@@ -0,0 +1,417 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/auditevent"
+)
+
+// AuditEventCreate is the builder for creating a AuditEvent entity.
+type AuditEventCreate struct {
+	config
+	mutation *AuditEventMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (aec *AuditEventCreate) SetCreatedAt(t time.Time) *AuditEventCreate {
+	aec.mutation.SetCreatedAt(t)
+	return aec
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableCreatedAt(t *time.Time) *AuditEventCreate {
+	if t != nil {
+		aec.SetCreatedAt(*t)
+	}
+	return aec
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (aec *AuditEventCreate) SetUpdatedAt(t time.Time) *AuditEventCreate {
+	aec.mutation.SetUpdatedAt(t)
+	return aec
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableUpdatedAt(t *time.Time) *AuditEventCreate {
+	if t != nil {
+		aec.SetUpdatedAt(*t)
+	}
+	return aec
+}
+
+// SetAction sets the "action" field.
+func (aec *AuditEventCreate) SetAction(s string) *AuditEventCreate {
+	aec.mutation.SetAction(s)
+	return aec
+}
+
+// SetActorID sets the "actor_id" field.
+func (aec *AuditEventCreate) SetActorID(s string) *AuditEventCreate {
+	aec.mutation.SetActorID(s)
+	return aec
+}
+
+// SetNillableActorID sets the "actor_id" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableActorID(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetActorID(*s)
+	}
+	return aec
+}
+
+// SetImpersonatedBy sets the "impersonated_by" field.
+func (aec *AuditEventCreate) SetImpersonatedBy(s string) *AuditEventCreate {
+	aec.mutation.SetImpersonatedBy(s)
+	return aec
+}
+
+// SetNillableImpersonatedBy sets the "impersonated_by" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableImpersonatedBy(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetImpersonatedBy(*s)
+	}
+	return aec
+}
+
+// SetSubject sets the "subject" field.
+func (aec *AuditEventCreate) SetSubject(s string) *AuditEventCreate {
+	aec.mutation.SetSubject(s)
+	return aec
+}
+
+// SetNillableSubject sets the "subject" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableSubject(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetSubject(*s)
+	}
+	return aec
+}
+
+// SetDetails sets the "details" field.
+func (aec *AuditEventCreate) SetDetails(s string) *AuditEventCreate {
+	aec.mutation.SetDetails(s)
+	return aec
+}
+
+// SetNillableDetails sets the "details" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableDetails(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetDetails(*s)
+	}
+	return aec
+}
+
+// SetOutcome sets the "outcome" field.
+func (aec *AuditEventCreate) SetOutcome(a auditevent.Outcome) *AuditEventCreate {
+	aec.mutation.SetOutcome(a)
+	return aec
+}
+
+// SetStatus sets the "status" field.
+func (aec *AuditEventCreate) SetStatus(i int) *AuditEventCreate {
+	aec.mutation.SetStatus(i)
+	return aec
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableStatus(i *int) *AuditEventCreate {
+	if i != nil {
+		aec.SetStatus(*i)
+	}
+	return aec
+}
+
+// SetIP sets the "ip" field.
+func (aec *AuditEventCreate) SetIP(s string) *AuditEventCreate {
+	aec.mutation.SetIP(s)
+	return aec
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableIP(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetIP(*s)
+	}
+	return aec
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (aec *AuditEventCreate) SetUserAgent(s string) *AuditEventCreate {
+	aec.mutation.SetUserAgent(s)
+	return aec
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableUserAgent(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetUserAgent(*s)
+	}
+	return aec
+}
+
+// SetID sets the "id" field.
+func (aec *AuditEventCreate) SetID(s string) *AuditEventCreate {
+	aec.mutation.SetID(s)
+	return aec
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableID(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetID(*s)
+	}
+	return aec
+}
+
+// Mutation returns the AuditEventMutation object of the builder.
+func (aec *AuditEventCreate) Mutation() *AuditEventMutation {
+	return aec.mutation
+}
+
+// Save creates the AuditEvent in the database.
+func (aec *AuditEventCreate) Save(ctx context.Context) (*AuditEvent, error) {
+	aec.defaults()
+	return withHooks(ctx, aec.sqlSave, aec.mutation, aec.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (aec *AuditEventCreate) SaveX(ctx context.Context) *AuditEvent {
+	v, err := aec.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (aec *AuditEventCreate) Exec(ctx context.Context) error {
+	_, err := aec.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (aec *AuditEventCreate) ExecX(ctx context.Context) {
+	if err := aec.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (aec *AuditEventCreate) defaults() {
+	if _, ok := aec.mutation.CreatedAt(); !ok {
+		v := auditevent.DefaultCreatedAt()
+		aec.mutation.SetCreatedAt(v)
+	}
+	if _, ok := aec.mutation.UpdatedAt(); !ok {
+		v := auditevent.DefaultUpdatedAt()
+		aec.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := aec.mutation.ID(); !ok {
+		v := auditevent.DefaultID()
+		aec.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (aec *AuditEventCreate) check() error {
+	if _, ok := aec.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "AuditEvent.created_at"`)}
+	}
+	if _, ok := aec.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "AuditEvent.updated_at"`)}
+	}
+	if _, ok := aec.mutation.Action(); !ok {
+		return &ValidationError{Name: "action", err: errors.New(`ent: missing required field "AuditEvent.action"`)}
+	}
+	if v, ok := aec.mutation.Action(); ok {
+		if err := auditevent.ActionValidator(v); err != nil {
+			return &ValidationError{Name: "action", err: fmt.Errorf(`ent: validator failed for field "AuditEvent.action": %w`, err)}
+		}
+	}
+	if _, ok := aec.mutation.Outcome(); !ok {
+		return &ValidationError{Name: "outcome", err: errors.New(`ent: missing required field "AuditEvent.outcome"`)}
+	}
+	if v, ok := aec.mutation.Outcome(); ok {
+		if err := auditevent.OutcomeValidator(v); err != nil {
+			return &ValidationError{Name: "outcome", err: fmt.Errorf(`ent: validator failed for field "AuditEvent.outcome": %w`, err)}
+		}
+	}
+	if v, ok := aec.mutation.ID(); ok {
+		if err := auditevent.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`ent: validator failed for field "AuditEvent.id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (aec *AuditEventCreate) sqlSave(ctx context.Context) (*AuditEvent, error) {
+	if err := aec.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := aec.createSpec()
+	if err := sqlgraph.CreateNode(ctx, aec.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected AuditEvent.ID type: %T", _spec.ID.Value)
+		}
+	}
+	aec.mutation.id = &_node.ID
+	aec.mutation.done = true
+	return _node, nil
+}
+
+func (aec *AuditEventCreate) createSpec() (*AuditEvent, *sqlgraph.CreateSpec) {
+	var (
+		_node = &AuditEvent{config: aec.config}
+		_spec = sqlgraph.NewCreateSpec(auditevent.Table, sqlgraph.NewFieldSpec(auditevent.FieldID, field.TypeString))
+	)
+	if id, ok := aec.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := aec.mutation.CreatedAt(); ok {
+		_spec.SetField(auditevent.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := aec.mutation.UpdatedAt(); ok {
+		_spec.SetField(auditevent.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := aec.mutation.Action(); ok {
+		_spec.SetField(auditevent.FieldAction, field.TypeString, value)
+		_node.Action = value
+	}
+	if value, ok := aec.mutation.ActorID(); ok {
+		_spec.SetField(auditevent.FieldActorID, field.TypeString, value)
+		_node.ActorID = value
+	}
+	if value, ok := aec.mutation.ImpersonatedBy(); ok {
+		_spec.SetField(auditevent.FieldImpersonatedBy, field.TypeString, value)
+		_node.ImpersonatedBy = value
+	}
+	if value, ok := aec.mutation.Subject(); ok {
+		_spec.SetField(auditevent.FieldSubject, field.TypeString, value)
+		_node.Subject = value
+	}
+	if value, ok := aec.mutation.Details(); ok {
+		_spec.SetField(auditevent.FieldDetails, field.TypeString, value)
+		_node.Details = value
+	}
+	if value, ok := aec.mutation.Outcome(); ok {
+		_spec.SetField(auditevent.FieldOutcome, field.TypeEnum, value)
+		_node.Outcome = value
+	}
+	if value, ok := aec.mutation.Status(); ok {
+		_spec.SetField(auditevent.FieldStatus, field.TypeInt, value)
+		_node.Status = value
+	}
+	if value, ok := aec.mutation.IP(); ok {
+		_spec.SetField(auditevent.FieldIP, field.TypeString, value)
+		_node.IP = value
+	}
+	if value, ok := aec.mutation.UserAgent(); ok {
+		_spec.SetField(auditevent.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
+	return _node, _spec
+}
+
+// AuditEventCreateBulk is the builder for creating many AuditEvent entities in bulk.
+type AuditEventCreateBulk struct {
+	config
+	err      error
+	builders []*AuditEventCreate
+}
+
+// Save creates the AuditEvent entities in the database.
+func (aecb *AuditEventCreateBulk) Save(ctx context.Context) ([]*AuditEvent, error) {
+	if aecb.err != nil {
+		return nil, aecb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(aecb.builders))
+	nodes := make([]*AuditEvent, len(aecb.builders))
+	mutators := make([]Mutator, len(aecb.builders))
+	for i := range aecb.builders {
+		func(i int, root context.Context) {
+			builder := aecb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*AuditEventMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, aecb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, aecb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, aecb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (aecb *AuditEventCreateBulk) SaveX(ctx context.Context) []*AuditEvent {
+	v, err := aecb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (aecb *AuditEventCreateBulk) Exec(ctx context.Context) error {
+	_, err := aecb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (aecb *AuditEventCreateBulk) ExecX(ctx context.Context) {
+	if err := aecb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,267 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/internal/ent/emailchange"
+)
+
+// EmailChange is the model entity for the EmailChange schema.
+type EmailChange struct {
+	config `json:"-"`
+	// ID of the ent.
+	// 主键
+	ID string `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// 创建人，用户ID或 service:<服务账号ID>
+	CreatedBy string `json:"created_by,omitempty"`
+	// 最后修改人
+	UpdatedBy string `json:"updated_by,omitempty"`
+	// 用户ID
+	UserID string `json:"user_id,omitempty"`
+	// 原邮箱
+	OldEmail string `json:"old_email,omitempty"`
+	// 新邮箱
+	NewEmail string `json:"new_email,omitempty"`
+	// 状态
+	Status emailchange.Status `json:"status,omitempty"`
+	// 新邮箱确认令牌哈希
+	ConfirmTokenHash string `json:"-"`
+	// 确认链接过期时间
+	ConfirmExpiresAt time.Time `json:"confirm_expires_at,omitempty"`
+	// 确认时间
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	// 回滚令牌哈希
+	RollbackTokenHash *string `json:"-"`
+	// 回滚链接过期时间
+	RollbackExpiresAt *time.Time `json:"rollback_expires_at,omitempty"`
+	// 回滚时间
+	RolledBackAt *time.Time `json:"rolled_back_at,omitempty"`
+	// 发起请求的IP
+	RequestedIP  string `json:"requested_ip,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*EmailChange) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case emailchange.FieldID, emailchange.FieldCreatedBy, emailchange.FieldUpdatedBy, emailchange.FieldUserID, emailchange.FieldOldEmail, emailchange.FieldNewEmail, emailchange.FieldStatus, emailchange.FieldConfirmTokenHash, emailchange.FieldRollbackTokenHash, emailchange.FieldRequestedIP:
+			values[i] = new(sql.NullString)
+		case emailchange.FieldCreatedAt, emailchange.FieldUpdatedAt, emailchange.FieldConfirmExpiresAt, emailchange.FieldConfirmedAt, emailchange.FieldRollbackExpiresAt, emailchange.FieldRolledBackAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the EmailChange fields.
+func (ec *EmailChange) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case emailchange.FieldID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value.Valid {
+				ec.ID = value.String
+			}
+		case emailchange.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				ec.CreatedAt = value.Time
+			}
+		case emailchange.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				ec.UpdatedAt = value.Time
+			}
+		case emailchange.FieldCreatedBy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field created_by", values[i])
+			} else if value.Valid {
+				ec.CreatedBy = value.String
+			}
+		case emailchange.FieldUpdatedBy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_by", values[i])
+			} else if value.Valid {
+				ec.UpdatedBy = value.String
+			}
+		case emailchange.FieldUserID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				ec.UserID = value.String
+			}
+		case emailchange.FieldOldEmail:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field old_email", values[i])
+			} else if value.Valid {
+				ec.OldEmail = value.String
+			}
+		case emailchange.FieldNewEmail:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field new_email", values[i])
+			} else if value.Valid {
+				ec.NewEmail = value.String
+			}
+		case emailchange.FieldStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status", values[i])
+			} else if value.Valid {
+				ec.Status = emailchange.Status(value.String)
+			}
+		case emailchange.FieldConfirmTokenHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field confirm_token_hash", values[i])
+			} else if value.Valid {
+				ec.ConfirmTokenHash = value.String
+			}
+		case emailchange.FieldConfirmExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field confirm_expires_at", values[i])
+			} else if value.Valid {
+				ec.ConfirmExpiresAt = value.Time
+			}
+		case emailchange.FieldConfirmedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field confirmed_at", values[i])
+			} else if value.Valid {
+				ec.ConfirmedAt = new(time.Time)
+				*ec.ConfirmedAt = value.Time
+			}
+		case emailchange.FieldRollbackTokenHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field rollback_token_hash", values[i])
+			} else if value.Valid {
+				ec.RollbackTokenHash = new(string)
+				*ec.RollbackTokenHash = value.String
+			}
+		case emailchange.FieldRollbackExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field rollback_expires_at", values[i])
+			} else if value.Valid {
+				ec.RollbackExpiresAt = new(time.Time)
+				*ec.RollbackExpiresAt = value.Time
+			}
+		case emailchange.FieldRolledBackAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field rolled_back_at", values[i])
+			} else if value.Valid {
+				ec.RolledBackAt = new(time.Time)
+				*ec.RolledBackAt = value.Time
+			}
+		case emailchange.FieldRequestedIP:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field requested_ip", values[i])
+			} else if value.Valid {
+				ec.RequestedIP = value.String
+			}
+		default:
+			ec.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the EmailChange.
+// This includes values selected through modifiers, order, etc.
+func (ec *EmailChange) Value(name string) (ent.Value, error) {
+	return ec.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this EmailChange.
+// Note that you need to call EmailChange.Unwrap() before calling this method if this EmailChange
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ec *EmailChange) Update() *EmailChangeUpdateOne {
+	return NewEmailChangeClient(ec.config).UpdateOne(ec)
+}
+
+// Unwrap unwraps the EmailChange entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ec *EmailChange) Unwrap() *EmailChange {
+	_tx, ok := ec.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: EmailChange is not a transactional entity")
+	}
+	ec.config.driver = _tx.drv
+	return ec
+}
+
+// String implements the fmt.Stringer.
+func (ec *EmailChange) String() string {
+	var builder strings.Builder
+	builder.WriteString("EmailChange(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ec.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(ec.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(ec.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("created_by=")
+	builder.WriteString(ec.CreatedBy)
+	builder.WriteString(", ")
+	builder.WriteString("updated_by=")
+	builder.WriteString(ec.UpdatedBy)
+	builder.WriteString(", ")
+	builder.WriteString("user_id=")
+	builder.WriteString(ec.UserID)
+	builder.WriteString(", ")
+	builder.WriteString("old_email=")
+	builder.WriteString(ec.OldEmail)
+	builder.WriteString(", ")
+	builder.WriteString("new_email=")
+	builder.WriteString(ec.NewEmail)
+	builder.WriteString(", ")
+	builder.WriteString("status=")
+	builder.WriteString(fmt.Sprintf("%v", ec.Status))
+	builder.WriteString(", ")
+	builder.WriteString("confirm_token_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("confirm_expires_at=")
+	builder.WriteString(ec.ConfirmExpiresAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := ec.ConfirmedAt; v != nil {
+		builder.WriteString("confirmed_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("rollback_token_hash=<sensitive>")
+	builder.WriteString(", ")
+	if v := ec.RollbackExpiresAt; v != nil {
+		builder.WriteString("rollback_expires_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := ec.RolledBackAt; v != nil {
+		builder.WriteString("rolled_back_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("requested_ip=")
+	builder.WriteString(ec.RequestedIP)
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// EmailChanges is a parsable slice of EmailChange.
+type EmailChanges []*EmailChange
@@ -0,0 +1,218 @@
+// Code generated by ent, DO NOT EDIT.
+
+package emailchange
+
+import (
+	"fmt"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the emailchange type in the database.
+	Label = "email_change"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
+	FieldUpdatedAt = "updated_at"
+	// FieldCreatedBy holds the string denoting the created_by field in the database.
+	FieldCreatedBy = "created_by"
+	// FieldUpdatedBy holds the string denoting the updated_by field in the database.
+	FieldUpdatedBy = "updated_by"
+	// FieldUserID holds the string denoting the user_id field in the database.
+	FieldUserID = "user_id"
+	// FieldOldEmail holds the string denoting the old_email field in the database.
+	FieldOldEmail = "old_email"
+	// FieldNewEmail holds the string denoting the new_email field in the database.
+	FieldNewEmail = "new_email"
+	// FieldStatus holds the string denoting the status field in the database.
+	FieldStatus = "status"
+	// FieldConfirmTokenHash holds the string denoting the confirm_token_hash field in the database.
+	FieldConfirmTokenHash = "confirm_token_hash"
+	// FieldConfirmExpiresAt holds the string denoting the confirm_expires_at field in the database.
+	FieldConfirmExpiresAt = "confirm_expires_at"
+	// FieldConfirmedAt holds the string denoting the confirmed_at field in the database.
+	FieldConfirmedAt = "confirmed_at"
+	// FieldRollbackTokenHash holds the string denoting the rollback_token_hash field in the database.
+	FieldRollbackTokenHash = "rollback_token_hash"
+	// FieldRollbackExpiresAt holds the string denoting the rollback_expires_at field in the database.
+	FieldRollbackExpiresAt = "rollback_expires_at"
+	// FieldRolledBackAt holds the string denoting the rolled_back_at field in the database.
+	FieldRolledBackAt = "rolled_back_at"
+	// FieldRequestedIP holds the string denoting the requested_ip field in the database.
+	FieldRequestedIP = "requested_ip"
+	// Table holds the table name of the emailchange in the database.
+	Table = "email_changes"
+)
+
+// Columns holds all SQL columns for emailchange fields.
+var Columns = []string{
+	FieldID,
+	FieldCreatedAt,
+	FieldUpdatedAt,
+	FieldCreatedBy,
+	FieldUpdatedBy,
+	FieldUserID,
+	FieldOldEmail,
+	FieldNewEmail,
+	FieldStatus,
+	FieldConfirmTokenHash,
+	FieldConfirmExpiresAt,
+	FieldConfirmedAt,
+	FieldRollbackTokenHash,
+	FieldRollbackExpiresAt,
+	FieldRolledBackAt,
+	FieldRequestedIP,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Note that the variables below are initialized by the runtime
+// package on the initialization of the application. Therefore,
+// it should be imported in the main as follows:
+//
+//	import _ "github.com/hewenyu/gin-pkg/internal/ent/runtime"
+var (
+	Hooks [1]ent.Hook
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
+	DefaultUpdatedAt func() time.Time
+	// UpdateDefaultUpdatedAt holds the default value on update for the "updated_at" field.
+	UpdateDefaultUpdatedAt func() time.Time
+	// UserIDValidator is a validator for the "user_id" field. It is called by the builders before save.
+	UserIDValidator func(string) error
+	// OldEmailValidator is a validator for the "old_email" field. It is called by the builders before save.
+	OldEmailValidator func(string) error
+	// NewEmailValidator is a validator for the "new_email" field. It is called by the builders before save.
+	NewEmailValidator func(string) error
+	// ConfirmTokenHashValidator is a validator for the "confirm_token_hash" field. It is called by the builders before save.
+	ConfirmTokenHashValidator func(string) error
+	// DefaultID holds the default value on creation for the "id" field.
+	DefaultID func() string
+	// IDValidator is a validator for the "id" field. It is called by the builders before save.
+	IDValidator func(string) error
+)
+
+// Status defines the type for the "status" enum field.
+type Status string
+
+// StatusPending is the default value of the Status enum.
+const DefaultStatus = StatusPending
+
+// Status values.
+const (
+	StatusPending    Status = "pending"
+	StatusConfirmed  Status = "confirmed"
+	StatusRolledBack Status = "rolled_back"
+	StatusCancelled  Status = "cancelled"
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// StatusValidator is a validator for the "status" field enum values. It is called by the builders before save.
+func StatusValidator(s Status) error {
+	switch s {
+	case StatusPending, StatusConfirmed, StatusRolledBack, StatusCancelled:
+		return nil
+	default:
+		return fmt.Errorf("emailchange: invalid enum value for status field: %q", s)
+	}
+}
+
+// OrderOption defines the ordering options for the EmailChange queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
+
+// ByUpdatedAt orders the results by the updated_at field.
+func ByUpdatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUpdatedAt, opts...).ToFunc()
+}
+
+// ByCreatedBy orders the results by the created_by field.
+func ByCreatedBy(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedBy, opts...).ToFunc()
+}
+
+// ByUpdatedBy orders the results by the updated_by field.
+func ByUpdatedBy(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUpdatedBy, opts...).ToFunc()
+}
+
+// ByUserID orders the results by the user_id field.
+func ByUserID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUserID, opts...).ToFunc()
+}
+
+// ByOldEmail orders the results by the old_email field.
+func ByOldEmail(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldOldEmail, opts...).ToFunc()
+}
+
+// ByNewEmail orders the results by the new_email field.
+func ByNewEmail(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldNewEmail, opts...).ToFunc()
+}
+
+// ByStatus orders the results by the status field.
+func ByStatus(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStatus, opts...).ToFunc()
+}
+
+// ByConfirmTokenHash orders the results by the confirm_token_hash field.
+func ByConfirmTokenHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldConfirmTokenHash, opts...).ToFunc()
+}
+
+// ByConfirmExpiresAt orders the results by the confirm_expires_at field.
+func ByConfirmExpiresAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldConfirmExpiresAt, opts...).ToFunc()
+}
+
+// ByConfirmedAt orders the results by the confirmed_at field.
+func ByConfirmedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldConfirmedAt, opts...).ToFunc()
+}
+
+// ByRollbackTokenHash orders the results by the rollback_token_hash field.
+func ByRollbackTokenHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRollbackTokenHash, opts...).ToFunc()
+}
+
+// ByRollbackExpiresAt orders the results by the rollback_expires_at field.
+func ByRollbackExpiresAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRollbackExpiresAt, opts...).ToFunc()
+}
+
+// ByRolledBackAt orders the results by the rolled_back_at field.
+func ByRolledBackAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRolledBackAt, opts...).ToFunc()
+}
+
+// ByRequestedIP orders the results by the requested_ip field.
+func ByRequestedIP(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRequestedIP, opts...).ToFunc()
+}
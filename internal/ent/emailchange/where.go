@@ -0,0 +1,1000 @@
+// Code generated by ent, DO NOT EDIT.
+
+package emailchange
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldID, id))
+}
+
+// IDEqualFold applies the EqualFold predicate on the ID field.
+func IDEqualFold(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldID, id))
+}
+
+// IDContainsFold applies the ContainsFold predicate on the ID field.
+func IDContainsFold(id string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldID, id))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// CreatedBy applies equality check predicate on the "created_by" field. It's identical to CreatedByEQ.
+func CreatedBy(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldCreatedBy, v))
+}
+
+// UpdatedBy applies equality check predicate on the "updated_by" field. It's identical to UpdatedByEQ.
+func UpdatedBy(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldUpdatedBy, v))
+}
+
+// UserID applies equality check predicate on the "user_id" field. It's identical to UserIDEQ.
+func UserID(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldUserID, v))
+}
+
+// OldEmail applies equality check predicate on the "old_email" field. It's identical to OldEmailEQ.
+func OldEmail(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldOldEmail, v))
+}
+
+// NewEmail applies equality check predicate on the "new_email" field. It's identical to NewEmailEQ.
+func NewEmail(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldNewEmail, v))
+}
+
+// ConfirmTokenHash applies equality check predicate on the "confirm_token_hash" field. It's identical to ConfirmTokenHashEQ.
+func ConfirmTokenHash(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldConfirmTokenHash, v))
+}
+
+// ConfirmExpiresAt applies equality check predicate on the "confirm_expires_at" field. It's identical to ConfirmExpiresAtEQ.
+func ConfirmExpiresAt(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldConfirmExpiresAt, v))
+}
+
+// ConfirmedAt applies equality check predicate on the "confirmed_at" field. It's identical to ConfirmedAtEQ.
+func ConfirmedAt(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldConfirmedAt, v))
+}
+
+// RollbackTokenHash applies equality check predicate on the "rollback_token_hash" field. It's identical to RollbackTokenHashEQ.
+func RollbackTokenHash(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldRollbackTokenHash, v))
+}
+
+// RollbackExpiresAt applies equality check predicate on the "rollback_expires_at" field. It's identical to RollbackExpiresAtEQ.
+func RollbackExpiresAt(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldRollbackExpiresAt, v))
+}
+
+// RolledBackAt applies equality check predicate on the "rolled_back_at" field. It's identical to RolledBackAtEQ.
+func RolledBackAt(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldRolledBackAt, v))
+}
+
+// RequestedIP applies equality check predicate on the "requested_ip" field. It's identical to RequestedIPEQ.
+func RequestedIP(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldRequestedIP, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// CreatedByEQ applies the EQ predicate on the "created_by" field.
+func CreatedByEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldCreatedBy, v))
+}
+
+// CreatedByNEQ applies the NEQ predicate on the "created_by" field.
+func CreatedByNEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldCreatedBy, v))
+}
+
+// CreatedByIn applies the In predicate on the "created_by" field.
+func CreatedByIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldCreatedBy, vs...))
+}
+
+// CreatedByNotIn applies the NotIn predicate on the "created_by" field.
+func CreatedByNotIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldCreatedBy, vs...))
+}
+
+// CreatedByGT applies the GT predicate on the "created_by" field.
+func CreatedByGT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldCreatedBy, v))
+}
+
+// CreatedByGTE applies the GTE predicate on the "created_by" field.
+func CreatedByGTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldCreatedBy, v))
+}
+
+// CreatedByLT applies the LT predicate on the "created_by" field.
+func CreatedByLT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldCreatedBy, v))
+}
+
+// CreatedByLTE applies the LTE predicate on the "created_by" field.
+func CreatedByLTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldCreatedBy, v))
+}
+
+// CreatedByContains applies the Contains predicate on the "created_by" field.
+func CreatedByContains(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContains(FieldCreatedBy, v))
+}
+
+// CreatedByHasPrefix applies the HasPrefix predicate on the "created_by" field.
+func CreatedByHasPrefix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasPrefix(FieldCreatedBy, v))
+}
+
+// CreatedByHasSuffix applies the HasSuffix predicate on the "created_by" field.
+func CreatedByHasSuffix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasSuffix(FieldCreatedBy, v))
+}
+
+// CreatedByIsNil applies the IsNil predicate on the "created_by" field.
+func CreatedByIsNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIsNull(FieldCreatedBy))
+}
+
+// CreatedByNotNil applies the NotNil predicate on the "created_by" field.
+func CreatedByNotNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotNull(FieldCreatedBy))
+}
+
+// CreatedByEqualFold applies the EqualFold predicate on the "created_by" field.
+func CreatedByEqualFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldCreatedBy, v))
+}
+
+// CreatedByContainsFold applies the ContainsFold predicate on the "created_by" field.
+func CreatedByContainsFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldCreatedBy, v))
+}
+
+// UpdatedByEQ applies the EQ predicate on the "updated_by" field.
+func UpdatedByEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldUpdatedBy, v))
+}
+
+// UpdatedByNEQ applies the NEQ predicate on the "updated_by" field.
+func UpdatedByNEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldUpdatedBy, v))
+}
+
+// UpdatedByIn applies the In predicate on the "updated_by" field.
+func UpdatedByIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldUpdatedBy, vs...))
+}
+
+// UpdatedByNotIn applies the NotIn predicate on the "updated_by" field.
+func UpdatedByNotIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldUpdatedBy, vs...))
+}
+
+// UpdatedByGT applies the GT predicate on the "updated_by" field.
+func UpdatedByGT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldUpdatedBy, v))
+}
+
+// UpdatedByGTE applies the GTE predicate on the "updated_by" field.
+func UpdatedByGTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldUpdatedBy, v))
+}
+
+// UpdatedByLT applies the LT predicate on the "updated_by" field.
+func UpdatedByLT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldUpdatedBy, v))
+}
+
+// UpdatedByLTE applies the LTE predicate on the "updated_by" field.
+func UpdatedByLTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldUpdatedBy, v))
+}
+
+// UpdatedByContains applies the Contains predicate on the "updated_by" field.
+func UpdatedByContains(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContains(FieldUpdatedBy, v))
+}
+
+// UpdatedByHasPrefix applies the HasPrefix predicate on the "updated_by" field.
+func UpdatedByHasPrefix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasPrefix(FieldUpdatedBy, v))
+}
+
+// UpdatedByHasSuffix applies the HasSuffix predicate on the "updated_by" field.
+func UpdatedByHasSuffix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasSuffix(FieldUpdatedBy, v))
+}
+
+// UpdatedByIsNil applies the IsNil predicate on the "updated_by" field.
+func UpdatedByIsNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIsNull(FieldUpdatedBy))
+}
+
+// UpdatedByNotNil applies the NotNil predicate on the "updated_by" field.
+func UpdatedByNotNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotNull(FieldUpdatedBy))
+}
+
+// UpdatedByEqualFold applies the EqualFold predicate on the "updated_by" field.
+func UpdatedByEqualFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldUpdatedBy, v))
+}
+
+// UpdatedByContainsFold applies the ContainsFold predicate on the "updated_by" field.
+func UpdatedByContainsFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldUpdatedBy, v))
+}
+
+// UserIDEQ applies the EQ predicate on the "user_id" field.
+func UserIDEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldUserID, v))
+}
+
+// UserIDNEQ applies the NEQ predicate on the "user_id" field.
+func UserIDNEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldUserID, v))
+}
+
+// UserIDIn applies the In predicate on the "user_id" field.
+func UserIDIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldUserID, vs...))
+}
+
+// UserIDNotIn applies the NotIn predicate on the "user_id" field.
+func UserIDNotIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldUserID, vs...))
+}
+
+// UserIDGT applies the GT predicate on the "user_id" field.
+func UserIDGT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldUserID, v))
+}
+
+// UserIDGTE applies the GTE predicate on the "user_id" field.
+func UserIDGTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldUserID, v))
+}
+
+// UserIDLT applies the LT predicate on the "user_id" field.
+func UserIDLT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldUserID, v))
+}
+
+// UserIDLTE applies the LTE predicate on the "user_id" field.
+func UserIDLTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldUserID, v))
+}
+
+// UserIDContains applies the Contains predicate on the "user_id" field.
+func UserIDContains(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContains(FieldUserID, v))
+}
+
+// UserIDHasPrefix applies the HasPrefix predicate on the "user_id" field.
+func UserIDHasPrefix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasPrefix(FieldUserID, v))
+}
+
+// UserIDHasSuffix applies the HasSuffix predicate on the "user_id" field.
+func UserIDHasSuffix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasSuffix(FieldUserID, v))
+}
+
+// UserIDEqualFold applies the EqualFold predicate on the "user_id" field.
+func UserIDEqualFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldUserID, v))
+}
+
+// UserIDContainsFold applies the ContainsFold predicate on the "user_id" field.
+func UserIDContainsFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldUserID, v))
+}
+
+// OldEmailEQ applies the EQ predicate on the "old_email" field.
+func OldEmailEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldOldEmail, v))
+}
+
+// OldEmailNEQ applies the NEQ predicate on the "old_email" field.
+func OldEmailNEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldOldEmail, v))
+}
+
+// OldEmailIn applies the In predicate on the "old_email" field.
+func OldEmailIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldOldEmail, vs...))
+}
+
+// OldEmailNotIn applies the NotIn predicate on the "old_email" field.
+func OldEmailNotIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldOldEmail, vs...))
+}
+
+// OldEmailGT applies the GT predicate on the "old_email" field.
+func OldEmailGT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldOldEmail, v))
+}
+
+// OldEmailGTE applies the GTE predicate on the "old_email" field.
+func OldEmailGTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldOldEmail, v))
+}
+
+// OldEmailLT applies the LT predicate on the "old_email" field.
+func OldEmailLT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldOldEmail, v))
+}
+
+// OldEmailLTE applies the LTE predicate on the "old_email" field.
+func OldEmailLTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldOldEmail, v))
+}
+
+// OldEmailContains applies the Contains predicate on the "old_email" field.
+func OldEmailContains(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContains(FieldOldEmail, v))
+}
+
+// OldEmailHasPrefix applies the HasPrefix predicate on the "old_email" field.
+func OldEmailHasPrefix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasPrefix(FieldOldEmail, v))
+}
+
+// OldEmailHasSuffix applies the HasSuffix predicate on the "old_email" field.
+func OldEmailHasSuffix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasSuffix(FieldOldEmail, v))
+}
+
+// OldEmailEqualFold applies the EqualFold predicate on the "old_email" field.
+func OldEmailEqualFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldOldEmail, v))
+}
+
+// OldEmailContainsFold applies the ContainsFold predicate on the "old_email" field.
+func OldEmailContainsFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldOldEmail, v))
+}
+
+// NewEmailEQ applies the EQ predicate on the "new_email" field.
+func NewEmailEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldNewEmail, v))
+}
+
+// NewEmailNEQ applies the NEQ predicate on the "new_email" field.
+func NewEmailNEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldNewEmail, v))
+}
+
+// NewEmailIn applies the In predicate on the "new_email" field.
+func NewEmailIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldNewEmail, vs...))
+}
+
+// NewEmailNotIn applies the NotIn predicate on the "new_email" field.
+func NewEmailNotIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldNewEmail, vs...))
+}
+
+// NewEmailGT applies the GT predicate on the "new_email" field.
+func NewEmailGT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldNewEmail, v))
+}
+
+// NewEmailGTE applies the GTE predicate on the "new_email" field.
+func NewEmailGTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldNewEmail, v))
+}
+
+// NewEmailLT applies the LT predicate on the "new_email" field.
+func NewEmailLT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldNewEmail, v))
+}
+
+// NewEmailLTE applies the LTE predicate on the "new_email" field.
+func NewEmailLTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldNewEmail, v))
+}
+
+// NewEmailContains applies the Contains predicate on the "new_email" field.
+func NewEmailContains(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContains(FieldNewEmail, v))
+}
+
+// NewEmailHasPrefix applies the HasPrefix predicate on the "new_email" field.
+func NewEmailHasPrefix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasPrefix(FieldNewEmail, v))
+}
+
+// NewEmailHasSuffix applies the HasSuffix predicate on the "new_email" field.
+func NewEmailHasSuffix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasSuffix(FieldNewEmail, v))
+}
+
+// NewEmailEqualFold applies the EqualFold predicate on the "new_email" field.
+func NewEmailEqualFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldNewEmail, v))
+}
+
+// NewEmailContainsFold applies the ContainsFold predicate on the "new_email" field.
+func NewEmailContainsFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldNewEmail, v))
+}
+
+// StatusEQ applies the EQ predicate on the "status" field.
+func StatusEQ(v Status) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldStatus, v))
+}
+
+// StatusNEQ applies the NEQ predicate on the "status" field.
+func StatusNEQ(v Status) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldStatus, v))
+}
+
+// StatusIn applies the In predicate on the "status" field.
+func StatusIn(vs ...Status) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldStatus, vs...))
+}
+
+// StatusNotIn applies the NotIn predicate on the "status" field.
+func StatusNotIn(vs ...Status) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldStatus, vs...))
+}
+
+// ConfirmTokenHashEQ applies the EQ predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashNEQ applies the NEQ predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashNEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashIn applies the In predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldConfirmTokenHash, vs...))
+}
+
+// ConfirmTokenHashNotIn applies the NotIn predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashNotIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldConfirmTokenHash, vs...))
+}
+
+// ConfirmTokenHashGT applies the GT predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashGT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashGTE applies the GTE predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashGTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashLT applies the LT predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashLT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashLTE applies the LTE predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashLTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashContains applies the Contains predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashContains(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContains(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashHasPrefix applies the HasPrefix predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashHasPrefix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasPrefix(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashHasSuffix applies the HasSuffix predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashHasSuffix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasSuffix(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashEqualFold applies the EqualFold predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashEqualFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldConfirmTokenHash, v))
+}
+
+// ConfirmTokenHashContainsFold applies the ContainsFold predicate on the "confirm_token_hash" field.
+func ConfirmTokenHashContainsFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldConfirmTokenHash, v))
+}
+
+// ConfirmExpiresAtEQ applies the EQ predicate on the "confirm_expires_at" field.
+func ConfirmExpiresAtEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldConfirmExpiresAt, v))
+}
+
+// ConfirmExpiresAtNEQ applies the NEQ predicate on the "confirm_expires_at" field.
+func ConfirmExpiresAtNEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldConfirmExpiresAt, v))
+}
+
+// ConfirmExpiresAtIn applies the In predicate on the "confirm_expires_at" field.
+func ConfirmExpiresAtIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldConfirmExpiresAt, vs...))
+}
+
+// ConfirmExpiresAtNotIn applies the NotIn predicate on the "confirm_expires_at" field.
+func ConfirmExpiresAtNotIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldConfirmExpiresAt, vs...))
+}
+
+// ConfirmExpiresAtGT applies the GT predicate on the "confirm_expires_at" field.
+func ConfirmExpiresAtGT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldConfirmExpiresAt, v))
+}
+
+// ConfirmExpiresAtGTE applies the GTE predicate on the "confirm_expires_at" field.
+func ConfirmExpiresAtGTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldConfirmExpiresAt, v))
+}
+
+// ConfirmExpiresAtLT applies the LT predicate on the "confirm_expires_at" field.
+func ConfirmExpiresAtLT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldConfirmExpiresAt, v))
+}
+
+// ConfirmExpiresAtLTE applies the LTE predicate on the "confirm_expires_at" field.
+func ConfirmExpiresAtLTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldConfirmExpiresAt, v))
+}
+
+// ConfirmedAtEQ applies the EQ predicate on the "confirmed_at" field.
+func ConfirmedAtEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldConfirmedAt, v))
+}
+
+// ConfirmedAtNEQ applies the NEQ predicate on the "confirmed_at" field.
+func ConfirmedAtNEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldConfirmedAt, v))
+}
+
+// ConfirmedAtIn applies the In predicate on the "confirmed_at" field.
+func ConfirmedAtIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldConfirmedAt, vs...))
+}
+
+// ConfirmedAtNotIn applies the NotIn predicate on the "confirmed_at" field.
+func ConfirmedAtNotIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldConfirmedAt, vs...))
+}
+
+// ConfirmedAtGT applies the GT predicate on the "confirmed_at" field.
+func ConfirmedAtGT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldConfirmedAt, v))
+}
+
+// ConfirmedAtGTE applies the GTE predicate on the "confirmed_at" field.
+func ConfirmedAtGTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldConfirmedAt, v))
+}
+
+// ConfirmedAtLT applies the LT predicate on the "confirmed_at" field.
+func ConfirmedAtLT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldConfirmedAt, v))
+}
+
+// ConfirmedAtLTE applies the LTE predicate on the "confirmed_at" field.
+func ConfirmedAtLTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldConfirmedAt, v))
+}
+
+// ConfirmedAtIsNil applies the IsNil predicate on the "confirmed_at" field.
+func ConfirmedAtIsNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIsNull(FieldConfirmedAt))
+}
+
+// ConfirmedAtNotNil applies the NotNil predicate on the "confirmed_at" field.
+func ConfirmedAtNotNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotNull(FieldConfirmedAt))
+}
+
+// RollbackTokenHashEQ applies the EQ predicate on the "rollback_token_hash" field.
+func RollbackTokenHashEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashNEQ applies the NEQ predicate on the "rollback_token_hash" field.
+func RollbackTokenHashNEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashIn applies the In predicate on the "rollback_token_hash" field.
+func RollbackTokenHashIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldRollbackTokenHash, vs...))
+}
+
+// RollbackTokenHashNotIn applies the NotIn predicate on the "rollback_token_hash" field.
+func RollbackTokenHashNotIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldRollbackTokenHash, vs...))
+}
+
+// RollbackTokenHashGT applies the GT predicate on the "rollback_token_hash" field.
+func RollbackTokenHashGT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashGTE applies the GTE predicate on the "rollback_token_hash" field.
+func RollbackTokenHashGTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashLT applies the LT predicate on the "rollback_token_hash" field.
+func RollbackTokenHashLT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashLTE applies the LTE predicate on the "rollback_token_hash" field.
+func RollbackTokenHashLTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashContains applies the Contains predicate on the "rollback_token_hash" field.
+func RollbackTokenHashContains(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContains(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashHasPrefix applies the HasPrefix predicate on the "rollback_token_hash" field.
+func RollbackTokenHashHasPrefix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasPrefix(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashHasSuffix applies the HasSuffix predicate on the "rollback_token_hash" field.
+func RollbackTokenHashHasSuffix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasSuffix(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashIsNil applies the IsNil predicate on the "rollback_token_hash" field.
+func RollbackTokenHashIsNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIsNull(FieldRollbackTokenHash))
+}
+
+// RollbackTokenHashNotNil applies the NotNil predicate on the "rollback_token_hash" field.
+func RollbackTokenHashNotNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotNull(FieldRollbackTokenHash))
+}
+
+// RollbackTokenHashEqualFold applies the EqualFold predicate on the "rollback_token_hash" field.
+func RollbackTokenHashEqualFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldRollbackTokenHash, v))
+}
+
+// RollbackTokenHashContainsFold applies the ContainsFold predicate on the "rollback_token_hash" field.
+func RollbackTokenHashContainsFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldRollbackTokenHash, v))
+}
+
+// RollbackExpiresAtEQ applies the EQ predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldRollbackExpiresAt, v))
+}
+
+// RollbackExpiresAtNEQ applies the NEQ predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtNEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldRollbackExpiresAt, v))
+}
+
+// RollbackExpiresAtIn applies the In predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldRollbackExpiresAt, vs...))
+}
+
+// RollbackExpiresAtNotIn applies the NotIn predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtNotIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldRollbackExpiresAt, vs...))
+}
+
+// RollbackExpiresAtGT applies the GT predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtGT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldRollbackExpiresAt, v))
+}
+
+// RollbackExpiresAtGTE applies the GTE predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtGTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldRollbackExpiresAt, v))
+}
+
+// RollbackExpiresAtLT applies the LT predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtLT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldRollbackExpiresAt, v))
+}
+
+// RollbackExpiresAtLTE applies the LTE predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtLTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldRollbackExpiresAt, v))
+}
+
+// RollbackExpiresAtIsNil applies the IsNil predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtIsNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIsNull(FieldRollbackExpiresAt))
+}
+
+// RollbackExpiresAtNotNil applies the NotNil predicate on the "rollback_expires_at" field.
+func RollbackExpiresAtNotNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotNull(FieldRollbackExpiresAt))
+}
+
+// RolledBackAtEQ applies the EQ predicate on the "rolled_back_at" field.
+func RolledBackAtEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldRolledBackAt, v))
+}
+
+// RolledBackAtNEQ applies the NEQ predicate on the "rolled_back_at" field.
+func RolledBackAtNEQ(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldRolledBackAt, v))
+}
+
+// RolledBackAtIn applies the In predicate on the "rolled_back_at" field.
+func RolledBackAtIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldRolledBackAt, vs...))
+}
+
+// RolledBackAtNotIn applies the NotIn predicate on the "rolled_back_at" field.
+func RolledBackAtNotIn(vs ...time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldRolledBackAt, vs...))
+}
+
+// RolledBackAtGT applies the GT predicate on the "rolled_back_at" field.
+func RolledBackAtGT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldRolledBackAt, v))
+}
+
+// RolledBackAtGTE applies the GTE predicate on the "rolled_back_at" field.
+func RolledBackAtGTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldRolledBackAt, v))
+}
+
+// RolledBackAtLT applies the LT predicate on the "rolled_back_at" field.
+func RolledBackAtLT(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldRolledBackAt, v))
+}
+
+// RolledBackAtLTE applies the LTE predicate on the "rolled_back_at" field.
+func RolledBackAtLTE(v time.Time) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldRolledBackAt, v))
+}
+
+// RolledBackAtIsNil applies the IsNil predicate on the "rolled_back_at" field.
+func RolledBackAtIsNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIsNull(FieldRolledBackAt))
+}
+
+// RolledBackAtNotNil applies the NotNil predicate on the "rolled_back_at" field.
+func RolledBackAtNotNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotNull(FieldRolledBackAt))
+}
+
+// RequestedIPEQ applies the EQ predicate on the "requested_ip" field.
+func RequestedIPEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEQ(FieldRequestedIP, v))
+}
+
+// RequestedIPNEQ applies the NEQ predicate on the "requested_ip" field.
+func RequestedIPNEQ(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNEQ(FieldRequestedIP, v))
+}
+
+// RequestedIPIn applies the In predicate on the "requested_ip" field.
+func RequestedIPIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIn(FieldRequestedIP, vs...))
+}
+
+// RequestedIPNotIn applies the NotIn predicate on the "requested_ip" field.
+func RequestedIPNotIn(vs ...string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotIn(FieldRequestedIP, vs...))
+}
+
+// RequestedIPGT applies the GT predicate on the "requested_ip" field.
+func RequestedIPGT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGT(FieldRequestedIP, v))
+}
+
+// RequestedIPGTE applies the GTE predicate on the "requested_ip" field.
+func RequestedIPGTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldGTE(FieldRequestedIP, v))
+}
+
+// RequestedIPLT applies the LT predicate on the "requested_ip" field.
+func RequestedIPLT(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLT(FieldRequestedIP, v))
+}
+
+// RequestedIPLTE applies the LTE predicate on the "requested_ip" field.
+func RequestedIPLTE(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldLTE(FieldRequestedIP, v))
+}
+
+// RequestedIPContains applies the Contains predicate on the "requested_ip" field.
+func RequestedIPContains(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContains(FieldRequestedIP, v))
+}
+
+// RequestedIPHasPrefix applies the HasPrefix predicate on the "requested_ip" field.
+func RequestedIPHasPrefix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasPrefix(FieldRequestedIP, v))
+}
+
+// RequestedIPHasSuffix applies the HasSuffix predicate on the "requested_ip" field.
+func RequestedIPHasSuffix(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldHasSuffix(FieldRequestedIP, v))
+}
+
+// RequestedIPIsNil applies the IsNil predicate on the "requested_ip" field.
+func RequestedIPIsNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldIsNull(FieldRequestedIP))
+}
+
+// RequestedIPNotNil applies the NotNil predicate on the "requested_ip" field.
+func RequestedIPNotNil() predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldNotNull(FieldRequestedIP))
+}
+
+// RequestedIPEqualFold applies the EqualFold predicate on the "requested_ip" field.
+func RequestedIPEqualFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldEqualFold(FieldRequestedIP, v))
+}
+
+// RequestedIPContainsFold applies the ContainsFold predicate on the "requested_ip" field.
+func RequestedIPContainsFold(v string) predicate.EmailChange {
+	return predicate.EmailChange(sql.FieldContainsFold(FieldRequestedIP, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.EmailChange) predicate.EmailChange {
+	return predicate.EmailChange(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.EmailChange) predicate.EmailChange {
+	return predicate.EmailChange(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.EmailChange) predicate.EmailChange {
+	return predicate.EmailChange(sql.NotPredicates(p))
+}
@@ -0,0 +1,508 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/emailchange"
+)
+
+// EmailChangeCreate is the builder for creating a EmailChange entity.
+type EmailChangeCreate struct {
+	config
+	mutation *EmailChangeMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (ecc *EmailChangeCreate) SetCreatedAt(t time.Time) *EmailChangeCreate {
+	ecc.mutation.SetCreatedAt(t)
+	return ecc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableCreatedAt(t *time.Time) *EmailChangeCreate {
+	if t != nil {
+		ecc.SetCreatedAt(*t)
+	}
+	return ecc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ecc *EmailChangeCreate) SetUpdatedAt(t time.Time) *EmailChangeCreate {
+	ecc.mutation.SetUpdatedAt(t)
+	return ecc
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableUpdatedAt(t *time.Time) *EmailChangeCreate {
+	if t != nil {
+		ecc.SetUpdatedAt(*t)
+	}
+	return ecc
+}
+
+// SetCreatedBy sets the "created_by" field.
+func (ecc *EmailChangeCreate) SetCreatedBy(s string) *EmailChangeCreate {
+	ecc.mutation.SetCreatedBy(s)
+	return ecc
+}
+
+// SetNillableCreatedBy sets the "created_by" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableCreatedBy(s *string) *EmailChangeCreate {
+	if s != nil {
+		ecc.SetCreatedBy(*s)
+	}
+	return ecc
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (ecc *EmailChangeCreate) SetUpdatedBy(s string) *EmailChangeCreate {
+	ecc.mutation.SetUpdatedBy(s)
+	return ecc
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableUpdatedBy(s *string) *EmailChangeCreate {
+	if s != nil {
+		ecc.SetUpdatedBy(*s)
+	}
+	return ecc
+}
+
+// SetUserID sets the "user_id" field.
+func (ecc *EmailChangeCreate) SetUserID(s string) *EmailChangeCreate {
+	ecc.mutation.SetUserID(s)
+	return ecc
+}
+
+// SetOldEmail sets the "old_email" field.
+func (ecc *EmailChangeCreate) SetOldEmail(s string) *EmailChangeCreate {
+	ecc.mutation.SetOldEmail(s)
+	return ecc
+}
+
+// SetNewEmail sets the "new_email" field.
+func (ecc *EmailChangeCreate) SetNewEmail(s string) *EmailChangeCreate {
+	ecc.mutation.SetNewEmail(s)
+	return ecc
+}
+
+// SetStatus sets the "status" field.
+func (ecc *EmailChangeCreate) SetStatus(e emailchange.Status) *EmailChangeCreate {
+	ecc.mutation.SetStatus(e)
+	return ecc
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableStatus(e *emailchange.Status) *EmailChangeCreate {
+	if e != nil {
+		ecc.SetStatus(*e)
+	}
+	return ecc
+}
+
+// SetConfirmTokenHash sets the "confirm_token_hash" field.
+func (ecc *EmailChangeCreate) SetConfirmTokenHash(s string) *EmailChangeCreate {
+	ecc.mutation.SetConfirmTokenHash(s)
+	return ecc
+}
+
+// SetConfirmExpiresAt sets the "confirm_expires_at" field.
+func (ecc *EmailChangeCreate) SetConfirmExpiresAt(t time.Time) *EmailChangeCreate {
+	ecc.mutation.SetConfirmExpiresAt(t)
+	return ecc
+}
+
+// SetConfirmedAt sets the "confirmed_at" field.
+func (ecc *EmailChangeCreate) SetConfirmedAt(t time.Time) *EmailChangeCreate {
+	ecc.mutation.SetConfirmedAt(t)
+	return ecc
+}
+
+// SetNillableConfirmedAt sets the "confirmed_at" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableConfirmedAt(t *time.Time) *EmailChangeCreate {
+	if t != nil {
+		ecc.SetConfirmedAt(*t)
+	}
+	return ecc
+}
+
+// SetRollbackTokenHash sets the "rollback_token_hash" field.
+func (ecc *EmailChangeCreate) SetRollbackTokenHash(s string) *EmailChangeCreate {
+	ecc.mutation.SetRollbackTokenHash(s)
+	return ecc
+}
+
+// SetNillableRollbackTokenHash sets the "rollback_token_hash" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableRollbackTokenHash(s *string) *EmailChangeCreate {
+	if s != nil {
+		ecc.SetRollbackTokenHash(*s)
+	}
+	return ecc
+}
+
+// SetRollbackExpiresAt sets the "rollback_expires_at" field.
+func (ecc *EmailChangeCreate) SetRollbackExpiresAt(t time.Time) *EmailChangeCreate {
+	ecc.mutation.SetRollbackExpiresAt(t)
+	return ecc
+}
+
+// SetNillableRollbackExpiresAt sets the "rollback_expires_at" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableRollbackExpiresAt(t *time.Time) *EmailChangeCreate {
+	if t != nil {
+		ecc.SetRollbackExpiresAt(*t)
+	}
+	return ecc
+}
+
+// SetRolledBackAt sets the "rolled_back_at" field.
+func (ecc *EmailChangeCreate) SetRolledBackAt(t time.Time) *EmailChangeCreate {
+	ecc.mutation.SetRolledBackAt(t)
+	return ecc
+}
+
+// SetNillableRolledBackAt sets the "rolled_back_at" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableRolledBackAt(t *time.Time) *EmailChangeCreate {
+	if t != nil {
+		ecc.SetRolledBackAt(*t)
+	}
+	return ecc
+}
+
+// SetRequestedIP sets the "requested_ip" field.
+func (ecc *EmailChangeCreate) SetRequestedIP(s string) *EmailChangeCreate {
+	ecc.mutation.SetRequestedIP(s)
+	return ecc
+}
+
+// SetNillableRequestedIP sets the "requested_ip" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableRequestedIP(s *string) *EmailChangeCreate {
+	if s != nil {
+		ecc.SetRequestedIP(*s)
+	}
+	return ecc
+}
+
+// SetID sets the "id" field.
+func (ecc *EmailChangeCreate) SetID(s string) *EmailChangeCreate {
+	ecc.mutation.SetID(s)
+	return ecc
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (ecc *EmailChangeCreate) SetNillableID(s *string) *EmailChangeCreate {
+	if s != nil {
+		ecc.SetID(*s)
+	}
+	return ecc
+}
+
+// Mutation returns the EmailChangeMutation object of the builder.
+func (ecc *EmailChangeCreate) Mutation() *EmailChangeMutation {
+	return ecc.mutation
+}
+
+// Save creates the EmailChange in the database.
+func (ecc *EmailChangeCreate) Save(ctx context.Context) (*EmailChange, error) {
+	if err := ecc.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, ecc.sqlSave, ecc.mutation, ecc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ecc *EmailChangeCreate) SaveX(ctx context.Context) *EmailChange {
+	v, err := ecc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ecc *EmailChangeCreate) Exec(ctx context.Context) error {
+	_, err := ecc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ecc *EmailChangeCreate) ExecX(ctx context.Context) {
+	if err := ecc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ecc *EmailChangeCreate) defaults() error {
+	if _, ok := ecc.mutation.CreatedAt(); !ok {
+		if emailchange.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized emailchange.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
+		v := emailchange.DefaultCreatedAt()
+		ecc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := ecc.mutation.UpdatedAt(); !ok {
+		if emailchange.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized emailchange.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := emailchange.DefaultUpdatedAt()
+		ecc.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := ecc.mutation.Status(); !ok {
+		v := emailchange.DefaultStatus
+		ecc.mutation.SetStatus(v)
+	}
+	if _, ok := ecc.mutation.ID(); !ok {
+		if emailchange.DefaultID == nil {
+			return fmt.Errorf("ent: uninitialized emailchange.DefaultID (forgotten import ent/runtime?)")
+		}
+		v := emailchange.DefaultID()
+		ecc.mutation.SetID(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ecc *EmailChangeCreate) check() error {
+	if _, ok := ecc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "EmailChange.created_at"`)}
+	}
+	if _, ok := ecc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "EmailChange.updated_at"`)}
+	}
+	if _, ok := ecc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "EmailChange.user_id"`)}
+	}
+	if v, ok := ecc.mutation.UserID(); ok {
+		if err := emailchange.UserIDValidator(v); err != nil {
+			return &ValidationError{Name: "user_id", err: fmt.Errorf(`ent: validator failed for field "EmailChange.user_id": %w`, err)}
+		}
+	}
+	if _, ok := ecc.mutation.OldEmail(); !ok {
+		return &ValidationError{Name: "old_email", err: errors.New(`ent: missing required field "EmailChange.old_email"`)}
+	}
+	if v, ok := ecc.mutation.OldEmail(); ok {
+		if err := emailchange.OldEmailValidator(v); err != nil {
+			return &ValidationError{Name: "old_email", err: fmt.Errorf(`ent: validator failed for field "EmailChange.old_email": %w`, err)}
+		}
+	}
+	if _, ok := ecc.mutation.NewEmail(); !ok {
+		return &ValidationError{Name: "new_email", err: errors.New(`ent: missing required field "EmailChange.new_email"`)}
+	}
+	if v, ok := ecc.mutation.NewEmail(); ok {
+		if err := emailchange.NewEmailValidator(v); err != nil {
+			return &ValidationError{Name: "new_email", err: fmt.Errorf(`ent: validator failed for field "EmailChange.new_email": %w`, err)}
+		}
+	}
+	if _, ok := ecc.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "EmailChange.status"`)}
+	}
+	if v, ok := ecc.mutation.Status(); ok {
+		if err := emailchange.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "EmailChange.status": %w`, err)}
+		}
+	}
+	if _, ok := ecc.mutation.ConfirmTokenHash(); !ok {
+		return &ValidationError{Name: "confirm_token_hash", err: errors.New(`ent: missing required field "EmailChange.confirm_token_hash"`)}
+	}
+	if v, ok := ecc.mutation.ConfirmTokenHash(); ok {
+		if err := emailchange.ConfirmTokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "confirm_token_hash", err: fmt.Errorf(`ent: validator failed for field "EmailChange.confirm_token_hash": %w`, err)}
+		}
+	}
+	if _, ok := ecc.mutation.ConfirmExpiresAt(); !ok {
+		return &ValidationError{Name: "confirm_expires_at", err: errors.New(`ent: missing required field "EmailChange.confirm_expires_at"`)}
+	}
+	if v, ok := ecc.mutation.ID(); ok {
+		if err := emailchange.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`ent: validator failed for field "EmailChange.id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (ecc *EmailChangeCreate) sqlSave(ctx context.Context) (*EmailChange, error) {
+	if err := ecc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ecc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ecc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected EmailChange.ID type: %T", _spec.ID.Value)
+		}
+	}
+	ecc.mutation.id = &_node.ID
+	ecc.mutation.done = true
+	return _node, nil
+}
+
+func (ecc *EmailChangeCreate) createSpec() (*EmailChange, *sqlgraph.CreateSpec) {
+	var (
+		_node = &EmailChange{config: ecc.config}
+		_spec = sqlgraph.NewCreateSpec(emailchange.Table, sqlgraph.NewFieldSpec(emailchange.FieldID, field.TypeString))
+	)
+	if id, ok := ecc.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := ecc.mutation.CreatedAt(); ok {
+		_spec.SetField(emailchange.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := ecc.mutation.UpdatedAt(); ok {
+		_spec.SetField(emailchange.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := ecc.mutation.CreatedBy(); ok {
+		_spec.SetField(emailchange.FieldCreatedBy, field.TypeString, value)
+		_node.CreatedBy = value
+	}
+	if value, ok := ecc.mutation.UpdatedBy(); ok {
+		_spec.SetField(emailchange.FieldUpdatedBy, field.TypeString, value)
+		_node.UpdatedBy = value
+	}
+	if value, ok := ecc.mutation.UserID(); ok {
+		_spec.SetField(emailchange.FieldUserID, field.TypeString, value)
+		_node.UserID = value
+	}
+	if value, ok := ecc.mutation.OldEmail(); ok {
+		_spec.SetField(emailchange.FieldOldEmail, field.TypeString, value)
+		_node.OldEmail = value
+	}
+	if value, ok := ecc.mutation.NewEmail(); ok {
+		_spec.SetField(emailchange.FieldNewEmail, field.TypeString, value)
+		_node.NewEmail = value
+	}
+	if value, ok := ecc.mutation.Status(); ok {
+		_spec.SetField(emailchange.FieldStatus, field.TypeEnum, value)
+		_node.Status = value
+	}
+	if value, ok := ecc.mutation.ConfirmTokenHash(); ok {
+		_spec.SetField(emailchange.FieldConfirmTokenHash, field.TypeString, value)
+		_node.ConfirmTokenHash = value
+	}
+	if value, ok := ecc.mutation.ConfirmExpiresAt(); ok {
+		_spec.SetField(emailchange.FieldConfirmExpiresAt, field.TypeTime, value)
+		_node.ConfirmExpiresAt = value
+	}
+	if value, ok := ecc.mutation.ConfirmedAt(); ok {
+		_spec.SetField(emailchange.FieldConfirmedAt, field.TypeTime, value)
+		_node.ConfirmedAt = &value
+	}
+	if value, ok := ecc.mutation.RollbackTokenHash(); ok {
+		_spec.SetField(emailchange.FieldRollbackTokenHash, field.TypeString, value)
+		_node.RollbackTokenHash = &value
+	}
+	if value, ok := ecc.mutation.RollbackExpiresAt(); ok {
+		_spec.SetField(emailchange.FieldRollbackExpiresAt, field.TypeTime, value)
+		_node.RollbackExpiresAt = &value
+	}
+	if value, ok := ecc.mutation.RolledBackAt(); ok {
+		_spec.SetField(emailchange.FieldRolledBackAt, field.TypeTime, value)
+		_node.RolledBackAt = &value
+	}
+	if value, ok := ecc.mutation.RequestedIP(); ok {
+		_spec.SetField(emailchange.FieldRequestedIP, field.TypeString, value)
+		_node.RequestedIP = value
+	}
+	return _node, _spec
+}
+
+// EmailChangeCreateBulk is the builder for creating many EmailChange entities in bulk.
+type EmailChangeCreateBulk struct {
+	config
+	err      error
+	builders []*EmailChangeCreate
+}
+
+// Save creates the EmailChange entities in the database.
+func (eccb *EmailChangeCreateBulk) Save(ctx context.Context) ([]*EmailChange, error) {
+	if eccb.err != nil {
+		return nil, eccb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(eccb.builders))
+	nodes := make([]*EmailChange, len(eccb.builders))
+	mutators := make([]Mutator, len(eccb.builders))
+	for i := range eccb.builders {
+		func(i int, root context.Context) {
+			builder := eccb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*EmailChangeMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, eccb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, eccb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, eccb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (eccb *EmailChangeCreateBulk) SaveX(ctx context.Context) []*EmailChange {
+	v, err := eccb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (eccb *EmailChangeCreateBulk) Exec(ctx context.Context) error {
+	_, err := eccb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (eccb *EmailChangeCreateBulk) ExecX(ctx context.Context) {
+	if err := eccb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,550 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/emailchange"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+)
+
+// EmailChangeQuery is the builder for querying EmailChange entities.
+type EmailChangeQuery struct {
+	config
+	ctx        *QueryContext
+	order      []emailchange.OrderOption
+	inters     []Interceptor
+	predicates []predicate.EmailChange
+	modifiers  []func(*sql.Selector)
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the EmailChangeQuery builder.
+func (ecq *EmailChangeQuery) Where(ps ...predicate.EmailChange) *EmailChangeQuery {
+	ecq.predicates = append(ecq.predicates, ps...)
+	return ecq
+}
+
+// Limit the number of records to be returned by this query.
+func (ecq *EmailChangeQuery) Limit(limit int) *EmailChangeQuery {
+	ecq.ctx.Limit = &limit
+	return ecq
+}
+
+// Offset to start from.
+func (ecq *EmailChangeQuery) Offset(offset int) *EmailChangeQuery {
+	ecq.ctx.Offset = &offset
+	return ecq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (ecq *EmailChangeQuery) Unique(unique bool) *EmailChangeQuery {
+	ecq.ctx.Unique = &unique
+	return ecq
+}
+
+// Order specifies how the records should be ordered.
+func (ecq *EmailChangeQuery) Order(o ...emailchange.OrderOption) *EmailChangeQuery {
+	ecq.order = append(ecq.order, o...)
+	return ecq
+}
+
+// First returns the first EmailChange entity from the query.
+// Returns a *NotFoundError when no EmailChange was found.
+func (ecq *EmailChangeQuery) First(ctx context.Context) (*EmailChange, error) {
+	nodes, err := ecq.Limit(1).All(setContextOp(ctx, ecq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{emailchange.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (ecq *EmailChangeQuery) FirstX(ctx context.Context) *EmailChange {
+	node, err := ecq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first EmailChange ID from the query.
+// Returns a *NotFoundError when no EmailChange ID was found.
+func (ecq *EmailChangeQuery) FirstID(ctx context.Context) (id string, err error) {
+	var ids []string
+	if ids, err = ecq.Limit(1).IDs(setContextOp(ctx, ecq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{emailchange.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (ecq *EmailChangeQuery) FirstIDX(ctx context.Context) string {
+	id, err := ecq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single EmailChange entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one EmailChange entity is found.
+// Returns a *NotFoundError when no EmailChange entities are found.
+func (ecq *EmailChangeQuery) Only(ctx context.Context) (*EmailChange, error) {
+	nodes, err := ecq.Limit(2).All(setContextOp(ctx, ecq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{emailchange.Label}
+	default:
+		return nil, &NotSingularError{emailchange.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (ecq *EmailChangeQuery) OnlyX(ctx context.Context) *EmailChange {
+	node, err := ecq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only EmailChange ID in the query.
+// Returns a *NotSingularError when more than one EmailChange ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (ecq *EmailChangeQuery) OnlyID(ctx context.Context) (id string, err error) {
+	var ids []string
+	if ids, err = ecq.Limit(2).IDs(setContextOp(ctx, ecq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{emailchange.Label}
+	default:
+		err = &NotSingularError{emailchange.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (ecq *EmailChangeQuery) OnlyIDX(ctx context.Context) string {
+	id, err := ecq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of EmailChanges.
+func (ecq *EmailChangeQuery) All(ctx context.Context) ([]*EmailChange, error) {
+	ctx = setContextOp(ctx, ecq.ctx, ent.OpQueryAll)
+	if err := ecq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*EmailChange, *EmailChangeQuery]()
+	return withInterceptors[[]*EmailChange](ctx, ecq, qr, ecq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (ecq *EmailChangeQuery) AllX(ctx context.Context) []*EmailChange {
+	nodes, err := ecq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of EmailChange IDs.
+func (ecq *EmailChangeQuery) IDs(ctx context.Context) (ids []string, err error) {
+	if ecq.ctx.Unique == nil && ecq.path != nil {
+		ecq.Unique(true)
+	}
+	ctx = setContextOp(ctx, ecq.ctx, ent.OpQueryIDs)
+	if err = ecq.Select(emailchange.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (ecq *EmailChangeQuery) IDsX(ctx context.Context) []string {
+	ids, err := ecq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (ecq *EmailChangeQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, ecq.ctx, ent.OpQueryCount)
+	if err := ecq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, ecq, querierCount[*EmailChangeQuery](), ecq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (ecq *EmailChangeQuery) CountX(ctx context.Context) int {
+	count, err := ecq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (ecq *EmailChangeQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, ecq.ctx, ent.OpQueryExist)
+	switch _, err := ecq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (ecq *EmailChangeQuery) ExistX(ctx context.Context) bool {
+	exist, err := ecq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the EmailChangeQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (ecq *EmailChangeQuery) Clone() *EmailChangeQuery {
+	if ecq == nil {
+		return nil
+	}
+	return &EmailChangeQuery{
+		config:     ecq.config,
+		ctx:        ecq.ctx.Clone(),
+		order:      append([]emailchange.OrderOption{}, ecq.order...),
+		inters:     append([]Interceptor{}, ecq.inters...),
+		predicates: append([]predicate.EmailChange{}, ecq.predicates...),
+		// clone intermediate query.
+		sql:       ecq.sql.Clone(),
+		path:      ecq.path,
+		modifiers: append([]func(*sql.Selector){}, ecq.modifiers...),
+	}
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		CreatedAt time.Time `json:"created_at,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.EmailChange.Query().
+//		GroupBy(emailchange.FieldCreatedAt).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (ecq *EmailChangeQuery) GroupBy(field string, fields ...string) *EmailChangeGroupBy {
+	ecq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &EmailChangeGroupBy{build: ecq}
+	grbuild.flds = &ecq.ctx.Fields
+	grbuild.label = emailchange.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		CreatedAt time.Time `json:"created_at,omitempty"`
+//	}
+//
+//	client.EmailChange.Query().
+//		Select(emailchange.FieldCreatedAt).
+//		Scan(ctx, &v)
+func (ecq *EmailChangeQuery) Select(fields ...string) *EmailChangeSelect {
+	ecq.ctx.Fields = append(ecq.ctx.Fields, fields...)
+	sbuild := &EmailChangeSelect{EmailChangeQuery: ecq}
+	sbuild.label = emailchange.Label
+	sbuild.flds, sbuild.scan = &ecq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a EmailChangeSelect configured with the given aggregations.
+func (ecq *EmailChangeQuery) Aggregate(fns ...AggregateFunc) *EmailChangeSelect {
+	return ecq.Select().Aggregate(fns...)
+}
+
+func (ecq *EmailChangeQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range ecq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, ecq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range ecq.ctx.Fields {
+		if !emailchange.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if ecq.path != nil {
+		prev, err := ecq.path(ctx)
+		if err != nil {
+			return err
+		}
+		ecq.sql = prev
+	}
+	return nil
+}
+
+func (ecq *EmailChangeQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*EmailChange, error) {
+	var (
+		nodes = []*EmailChange{}
+		_spec = ecq.querySpec()
+	)
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*EmailChange).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &EmailChange{config: ecq.config}
+		nodes = append(nodes, node)
+		return node.assignValues(columns, values)
+	}
+	if len(ecq.modifiers) > 0 {
+		_spec.Modifiers = ecq.modifiers
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, ecq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	return nodes, nil
+}
+
+func (ecq *EmailChangeQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := ecq.querySpec()
+	if len(ecq.modifiers) > 0 {
+		_spec.Modifiers = ecq.modifiers
+	}
+	_spec.Node.Columns = ecq.ctx.Fields
+	if len(ecq.ctx.Fields) > 0 {
+		_spec.Unique = ecq.ctx.Unique != nil && *ecq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, ecq.driver, _spec)
+}
+
+func (ecq *EmailChangeQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(emailchange.Table, emailchange.Columns, sqlgraph.NewFieldSpec(emailchange.FieldID, field.TypeString))
+	_spec.From = ecq.sql
+	if unique := ecq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if ecq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := ecq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, emailchange.FieldID)
+		for i := range fields {
+			if fields[i] != emailchange.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := ecq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := ecq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := ecq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := ecq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (ecq *EmailChangeQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(ecq.driver.Dialect())
+	t1 := builder.Table(emailchange.Table)
+	columns := ecq.ctx.Fields
+	if len(columns) == 0 {
+		columns = emailchange.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if ecq.sql != nil {
+		selector = ecq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if ecq.ctx.Unique != nil && *ecq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, m := range ecq.modifiers {
+		m(selector)
+	}
+	for _, p := range ecq.predicates {
+		p(selector)
+	}
+	for _, p := range ecq.order {
+		p(selector)
+	}
+	if offset := ecq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := ecq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (ecq *EmailChangeQuery) Modify(modifiers ...func(s *sql.Selector)) *EmailChangeSelect {
+	ecq.modifiers = append(ecq.modifiers, modifiers...)
+	return ecq.Select()
+}
+
+// EmailChangeGroupBy is the group-by builder for EmailChange entities.
+type EmailChangeGroupBy struct {
+	selector
+	build *EmailChangeQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (ecgb *EmailChangeGroupBy) Aggregate(fns ...AggregateFunc) *EmailChangeGroupBy {
+	ecgb.fns = append(ecgb.fns, fns...)
+	return ecgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ecgb *EmailChangeGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ecgb.build.ctx, ent.OpQueryGroupBy)
+	if err := ecgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*EmailChangeQuery, *EmailChangeGroupBy](ctx, ecgb.build, ecgb, ecgb.build.inters, v)
+}
+
+func (ecgb *EmailChangeGroupBy) sqlScan(ctx context.Context, root *EmailChangeQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(ecgb.fns))
+	for _, fn := range ecgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*ecgb.flds)+len(ecgb.fns))
+		for _, f := range *ecgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*ecgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ecgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// EmailChangeSelect is the builder for selecting fields of EmailChange entities.
+type EmailChangeSelect struct {
+	*EmailChangeQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (ecs *EmailChangeSelect) Aggregate(fns ...AggregateFunc) *EmailChangeSelect {
+	ecs.fns = append(ecs.fns, fns...)
+	return ecs
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ecs *EmailChangeSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ecs.ctx, ent.OpQuerySelect)
+	if err := ecs.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*EmailChangeQuery, *EmailChangeSelect](ctx, ecs.EmailChangeQuery, ecs, ecs.inters, v)
+}
+
+func (ecs *EmailChangeSelect) sqlScan(ctx context.Context, root *EmailChangeQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(ecs.fns))
+	for _, fn := range ecs.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*ecs.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ecs.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (ecs *EmailChangeSelect) Modify(modifiers ...func(s *sql.Selector)) *EmailChangeSelect {
+	ecs.modifiers = append(ecs.modifiers, modifiers...)
+	return ecs
+}
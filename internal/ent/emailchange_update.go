@@ -0,0 +1,828 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/emailchange"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+)
+
+// EmailChangeUpdate is the builder for updating EmailChange entities.
+type EmailChangeUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *EmailChangeMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the EmailChangeUpdate builder.
+func (ecu *EmailChangeUpdate) Where(ps ...predicate.EmailChange) *EmailChangeUpdate {
+	ecu.mutation.Where(ps...)
+	return ecu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ecu *EmailChangeUpdate) SetUpdatedAt(t time.Time) *EmailChangeUpdate {
+	ecu.mutation.SetUpdatedAt(t)
+	return ecu
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (ecu *EmailChangeUpdate) SetUpdatedBy(s string) *EmailChangeUpdate {
+	ecu.mutation.SetUpdatedBy(s)
+	return ecu
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableUpdatedBy(s *string) *EmailChangeUpdate {
+	if s != nil {
+		ecu.SetUpdatedBy(*s)
+	}
+	return ecu
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (ecu *EmailChangeUpdate) ClearUpdatedBy() *EmailChangeUpdate {
+	ecu.mutation.ClearUpdatedBy()
+	return ecu
+}
+
+// SetUserID sets the "user_id" field.
+func (ecu *EmailChangeUpdate) SetUserID(s string) *EmailChangeUpdate {
+	ecu.mutation.SetUserID(s)
+	return ecu
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableUserID(s *string) *EmailChangeUpdate {
+	if s != nil {
+		ecu.SetUserID(*s)
+	}
+	return ecu
+}
+
+// SetOldEmail sets the "old_email" field.
+func (ecu *EmailChangeUpdate) SetOldEmail(s string) *EmailChangeUpdate {
+	ecu.mutation.SetOldEmail(s)
+	return ecu
+}
+
+// SetNillableOldEmail sets the "old_email" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableOldEmail(s *string) *EmailChangeUpdate {
+	if s != nil {
+		ecu.SetOldEmail(*s)
+	}
+	return ecu
+}
+
+// SetNewEmail sets the "new_email" field.
+func (ecu *EmailChangeUpdate) SetNewEmail(s string) *EmailChangeUpdate {
+	ecu.mutation.SetNewEmail(s)
+	return ecu
+}
+
+// SetNillableNewEmail sets the "new_email" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableNewEmail(s *string) *EmailChangeUpdate {
+	if s != nil {
+		ecu.SetNewEmail(*s)
+	}
+	return ecu
+}
+
+// SetStatus sets the "status" field.
+func (ecu *EmailChangeUpdate) SetStatus(e emailchange.Status) *EmailChangeUpdate {
+	ecu.mutation.SetStatus(e)
+	return ecu
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableStatus(e *emailchange.Status) *EmailChangeUpdate {
+	if e != nil {
+		ecu.SetStatus(*e)
+	}
+	return ecu
+}
+
+// SetConfirmTokenHash sets the "confirm_token_hash" field.
+func (ecu *EmailChangeUpdate) SetConfirmTokenHash(s string) *EmailChangeUpdate {
+	ecu.mutation.SetConfirmTokenHash(s)
+	return ecu
+}
+
+// SetNillableConfirmTokenHash sets the "confirm_token_hash" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableConfirmTokenHash(s *string) *EmailChangeUpdate {
+	if s != nil {
+		ecu.SetConfirmTokenHash(*s)
+	}
+	return ecu
+}
+
+// SetConfirmExpiresAt sets the "confirm_expires_at" field.
+func (ecu *EmailChangeUpdate) SetConfirmExpiresAt(t time.Time) *EmailChangeUpdate {
+	ecu.mutation.SetConfirmExpiresAt(t)
+	return ecu
+}
+
+// SetNillableConfirmExpiresAt sets the "confirm_expires_at" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableConfirmExpiresAt(t *time.Time) *EmailChangeUpdate {
+	if t != nil {
+		ecu.SetConfirmExpiresAt(*t)
+	}
+	return ecu
+}
+
+// SetConfirmedAt sets the "confirmed_at" field.
+func (ecu *EmailChangeUpdate) SetConfirmedAt(t time.Time) *EmailChangeUpdate {
+	ecu.mutation.SetConfirmedAt(t)
+	return ecu
+}
+
+// SetNillableConfirmedAt sets the "confirmed_at" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableConfirmedAt(t *time.Time) *EmailChangeUpdate {
+	if t != nil {
+		ecu.SetConfirmedAt(*t)
+	}
+	return ecu
+}
+
+// ClearConfirmedAt clears the value of the "confirmed_at" field.
+func (ecu *EmailChangeUpdate) ClearConfirmedAt() *EmailChangeUpdate {
+	ecu.mutation.ClearConfirmedAt()
+	return ecu
+}
+
+// SetRollbackTokenHash sets the "rollback_token_hash" field.
+func (ecu *EmailChangeUpdate) SetRollbackTokenHash(s string) *EmailChangeUpdate {
+	ecu.mutation.SetRollbackTokenHash(s)
+	return ecu
+}
+
+// SetNillableRollbackTokenHash sets the "rollback_token_hash" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableRollbackTokenHash(s *string) *EmailChangeUpdate {
+	if s != nil {
+		ecu.SetRollbackTokenHash(*s)
+	}
+	return ecu
+}
+
+// ClearRollbackTokenHash clears the value of the "rollback_token_hash" field.
+func (ecu *EmailChangeUpdate) ClearRollbackTokenHash() *EmailChangeUpdate {
+	ecu.mutation.ClearRollbackTokenHash()
+	return ecu
+}
+
+// SetRollbackExpiresAt sets the "rollback_expires_at" field.
+func (ecu *EmailChangeUpdate) SetRollbackExpiresAt(t time.Time) *EmailChangeUpdate {
+	ecu.mutation.SetRollbackExpiresAt(t)
+	return ecu
+}
+
+// SetNillableRollbackExpiresAt sets the "rollback_expires_at" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableRollbackExpiresAt(t *time.Time) *EmailChangeUpdate {
+	if t != nil {
+		ecu.SetRollbackExpiresAt(*t)
+	}
+	return ecu
+}
+
+// ClearRollbackExpiresAt clears the value of the "rollback_expires_at" field.
+func (ecu *EmailChangeUpdate) ClearRollbackExpiresAt() *EmailChangeUpdate {
+	ecu.mutation.ClearRollbackExpiresAt()
+	return ecu
+}
+
+// SetRolledBackAt sets the "rolled_back_at" field.
+func (ecu *EmailChangeUpdate) SetRolledBackAt(t time.Time) *EmailChangeUpdate {
+	ecu.mutation.SetRolledBackAt(t)
+	return ecu
+}
+
+// SetNillableRolledBackAt sets the "rolled_back_at" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableRolledBackAt(t *time.Time) *EmailChangeUpdate {
+	if t != nil {
+		ecu.SetRolledBackAt(*t)
+	}
+	return ecu
+}
+
+// ClearRolledBackAt clears the value of the "rolled_back_at" field.
+func (ecu *EmailChangeUpdate) ClearRolledBackAt() *EmailChangeUpdate {
+	ecu.mutation.ClearRolledBackAt()
+	return ecu
+}
+
+// SetRequestedIP sets the "requested_ip" field.
+func (ecu *EmailChangeUpdate) SetRequestedIP(s string) *EmailChangeUpdate {
+	ecu.mutation.SetRequestedIP(s)
+	return ecu
+}
+
+// SetNillableRequestedIP sets the "requested_ip" field if the given value is not nil.
+func (ecu *EmailChangeUpdate) SetNillableRequestedIP(s *string) *EmailChangeUpdate {
+	if s != nil {
+		ecu.SetRequestedIP(*s)
+	}
+	return ecu
+}
+
+// ClearRequestedIP clears the value of the "requested_ip" field.
+func (ecu *EmailChangeUpdate) ClearRequestedIP() *EmailChangeUpdate {
+	ecu.mutation.ClearRequestedIP()
+	return ecu
+}
+
+// Mutation returns the EmailChangeMutation object of the builder.
+func (ecu *EmailChangeUpdate) Mutation() *EmailChangeMutation {
+	return ecu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (ecu *EmailChangeUpdate) Save(ctx context.Context) (int, error) {
+	if err := ecu.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks(ctx, ecu.sqlSave, ecu.mutation, ecu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ecu *EmailChangeUpdate) SaveX(ctx context.Context) int {
+	affected, err := ecu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (ecu *EmailChangeUpdate) Exec(ctx context.Context) error {
+	_, err := ecu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ecu *EmailChangeUpdate) ExecX(ctx context.Context) {
+	if err := ecu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ecu *EmailChangeUpdate) defaults() error {
+	if _, ok := ecu.mutation.UpdatedAt(); !ok {
+		if emailchange.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized emailchange.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := emailchange.UpdateDefaultUpdatedAt()
+		ecu.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ecu *EmailChangeUpdate) check() error {
+	if v, ok := ecu.mutation.UserID(); ok {
+		if err := emailchange.UserIDValidator(v); err != nil {
+			return &ValidationError{Name: "user_id", err: fmt.Errorf(`ent: validator failed for field "EmailChange.user_id": %w`, err)}
+		}
+	}
+	if v, ok := ecu.mutation.OldEmail(); ok {
+		if err := emailchange.OldEmailValidator(v); err != nil {
+			return &ValidationError{Name: "old_email", err: fmt.Errorf(`ent: validator failed for field "EmailChange.old_email": %w`, err)}
+		}
+	}
+	if v, ok := ecu.mutation.NewEmail(); ok {
+		if err := emailchange.NewEmailValidator(v); err != nil {
+			return &ValidationError{Name: "new_email", err: fmt.Errorf(`ent: validator failed for field "EmailChange.new_email": %w`, err)}
+		}
+	}
+	if v, ok := ecu.mutation.Status(); ok {
+		if err := emailchange.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "EmailChange.status": %w`, err)}
+		}
+	}
+	if v, ok := ecu.mutation.ConfirmTokenHash(); ok {
+		if err := emailchange.ConfirmTokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "confirm_token_hash", err: fmt.Errorf(`ent: validator failed for field "EmailChange.confirm_token_hash": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (ecu *EmailChangeUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *EmailChangeUpdate {
+	ecu.modifiers = append(ecu.modifiers, modifiers...)
+	return ecu
+}
+
+func (ecu *EmailChangeUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := ecu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(emailchange.Table, emailchange.Columns, sqlgraph.NewFieldSpec(emailchange.FieldID, field.TypeString))
+	if ps := ecu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ecu.mutation.UpdatedAt(); ok {
+		_spec.SetField(emailchange.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if ecu.mutation.CreatedByCleared() {
+		_spec.ClearField(emailchange.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := ecu.mutation.UpdatedBy(); ok {
+		_spec.SetField(emailchange.FieldUpdatedBy, field.TypeString, value)
+	}
+	if ecu.mutation.UpdatedByCleared() {
+		_spec.ClearField(emailchange.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := ecu.mutation.UserID(); ok {
+		_spec.SetField(emailchange.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := ecu.mutation.OldEmail(); ok {
+		_spec.SetField(emailchange.FieldOldEmail, field.TypeString, value)
+	}
+	if value, ok := ecu.mutation.NewEmail(); ok {
+		_spec.SetField(emailchange.FieldNewEmail, field.TypeString, value)
+	}
+	if value, ok := ecu.mutation.Status(); ok {
+		_spec.SetField(emailchange.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := ecu.mutation.ConfirmTokenHash(); ok {
+		_spec.SetField(emailchange.FieldConfirmTokenHash, field.TypeString, value)
+	}
+	if value, ok := ecu.mutation.ConfirmExpiresAt(); ok {
+		_spec.SetField(emailchange.FieldConfirmExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := ecu.mutation.ConfirmedAt(); ok {
+		_spec.SetField(emailchange.FieldConfirmedAt, field.TypeTime, value)
+	}
+	if ecu.mutation.ConfirmedAtCleared() {
+		_spec.ClearField(emailchange.FieldConfirmedAt, field.TypeTime)
+	}
+	if value, ok := ecu.mutation.RollbackTokenHash(); ok {
+		_spec.SetField(emailchange.FieldRollbackTokenHash, field.TypeString, value)
+	}
+	if ecu.mutation.RollbackTokenHashCleared() {
+		_spec.ClearField(emailchange.FieldRollbackTokenHash, field.TypeString)
+	}
+	if value, ok := ecu.mutation.RollbackExpiresAt(); ok {
+		_spec.SetField(emailchange.FieldRollbackExpiresAt, field.TypeTime, value)
+	}
+	if ecu.mutation.RollbackExpiresAtCleared() {
+		_spec.ClearField(emailchange.FieldRollbackExpiresAt, field.TypeTime)
+	}
+	if value, ok := ecu.mutation.RolledBackAt(); ok {
+		_spec.SetField(emailchange.FieldRolledBackAt, field.TypeTime, value)
+	}
+	if ecu.mutation.RolledBackAtCleared() {
+		_spec.ClearField(emailchange.FieldRolledBackAt, field.TypeTime)
+	}
+	if value, ok := ecu.mutation.RequestedIP(); ok {
+		_spec.SetField(emailchange.FieldRequestedIP, field.TypeString, value)
+	}
+	if ecu.mutation.RequestedIPCleared() {
+		_spec.ClearField(emailchange.FieldRequestedIP, field.TypeString)
+	}
+	_spec.AddModifiers(ecu.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, ecu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{emailchange.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	ecu.mutation.done = true
+	return n, nil
+}
+
+// EmailChangeUpdateOne is the builder for updating a single EmailChange entity.
+type EmailChangeUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *EmailChangeMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ecuo *EmailChangeUpdateOne) SetUpdatedAt(t time.Time) *EmailChangeUpdateOne {
+	ecuo.mutation.SetUpdatedAt(t)
+	return ecuo
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (ecuo *EmailChangeUpdateOne) SetUpdatedBy(s string) *EmailChangeUpdateOne {
+	ecuo.mutation.SetUpdatedBy(s)
+	return ecuo
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableUpdatedBy(s *string) *EmailChangeUpdateOne {
+	if s != nil {
+		ecuo.SetUpdatedBy(*s)
+	}
+	return ecuo
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (ecuo *EmailChangeUpdateOne) ClearUpdatedBy() *EmailChangeUpdateOne {
+	ecuo.mutation.ClearUpdatedBy()
+	return ecuo
+}
+
+// SetUserID sets the "user_id" field.
+func (ecuo *EmailChangeUpdateOne) SetUserID(s string) *EmailChangeUpdateOne {
+	ecuo.mutation.SetUserID(s)
+	return ecuo
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableUserID(s *string) *EmailChangeUpdateOne {
+	if s != nil {
+		ecuo.SetUserID(*s)
+	}
+	return ecuo
+}
+
+// SetOldEmail sets the "old_email" field.
+func (ecuo *EmailChangeUpdateOne) SetOldEmail(s string) *EmailChangeUpdateOne {
+	ecuo.mutation.SetOldEmail(s)
+	return ecuo
+}
+
+// SetNillableOldEmail sets the "old_email" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableOldEmail(s *string) *EmailChangeUpdateOne {
+	if s != nil {
+		ecuo.SetOldEmail(*s)
+	}
+	return ecuo
+}
+
+// SetNewEmail sets the "new_email" field.
+func (ecuo *EmailChangeUpdateOne) SetNewEmail(s string) *EmailChangeUpdateOne {
+	ecuo.mutation.SetNewEmail(s)
+	return ecuo
+}
+
+// SetNillableNewEmail sets the "new_email" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableNewEmail(s *string) *EmailChangeUpdateOne {
+	if s != nil {
+		ecuo.SetNewEmail(*s)
+	}
+	return ecuo
+}
+
+// SetStatus sets the "status" field.
+func (ecuo *EmailChangeUpdateOne) SetStatus(e emailchange.Status) *EmailChangeUpdateOne {
+	ecuo.mutation.SetStatus(e)
+	return ecuo
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableStatus(e *emailchange.Status) *EmailChangeUpdateOne {
+	if e != nil {
+		ecuo.SetStatus(*e)
+	}
+	return ecuo
+}
+
+// SetConfirmTokenHash sets the "confirm_token_hash" field.
+func (ecuo *EmailChangeUpdateOne) SetConfirmTokenHash(s string) *EmailChangeUpdateOne {
+	ecuo.mutation.SetConfirmTokenHash(s)
+	return ecuo
+}
+
+// SetNillableConfirmTokenHash sets the "confirm_token_hash" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableConfirmTokenHash(s *string) *EmailChangeUpdateOne {
+	if s != nil {
+		ecuo.SetConfirmTokenHash(*s)
+	}
+	return ecuo
+}
+
+// SetConfirmExpiresAt sets the "confirm_expires_at" field.
+func (ecuo *EmailChangeUpdateOne) SetConfirmExpiresAt(t time.Time) *EmailChangeUpdateOne {
+	ecuo.mutation.SetConfirmExpiresAt(t)
+	return ecuo
+}
+
+// SetNillableConfirmExpiresAt sets the "confirm_expires_at" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableConfirmExpiresAt(t *time.Time) *EmailChangeUpdateOne {
+	if t != nil {
+		ecuo.SetConfirmExpiresAt(*t)
+	}
+	return ecuo
+}
+
+// SetConfirmedAt sets the "confirmed_at" field.
+func (ecuo *EmailChangeUpdateOne) SetConfirmedAt(t time.Time) *EmailChangeUpdateOne {
+	ecuo.mutation.SetConfirmedAt(t)
+	return ecuo
+}
+
+// SetNillableConfirmedAt sets the "confirmed_at" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableConfirmedAt(t *time.Time) *EmailChangeUpdateOne {
+	if t != nil {
+		ecuo.SetConfirmedAt(*t)
+	}
+	return ecuo
+}
+
+// ClearConfirmedAt clears the value of the "confirmed_at" field.
+func (ecuo *EmailChangeUpdateOne) ClearConfirmedAt() *EmailChangeUpdateOne {
+	ecuo.mutation.ClearConfirmedAt()
+	return ecuo
+}
+
+// SetRollbackTokenHash sets the "rollback_token_hash" field.
+func (ecuo *EmailChangeUpdateOne) SetRollbackTokenHash(s string) *EmailChangeUpdateOne {
+	ecuo.mutation.SetRollbackTokenHash(s)
+	return ecuo
+}
+
+// SetNillableRollbackTokenHash sets the "rollback_token_hash" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableRollbackTokenHash(s *string) *EmailChangeUpdateOne {
+	if s != nil {
+		ecuo.SetRollbackTokenHash(*s)
+	}
+	return ecuo
+}
+
+// ClearRollbackTokenHash clears the value of the "rollback_token_hash" field.
+func (ecuo *EmailChangeUpdateOne) ClearRollbackTokenHash() *EmailChangeUpdateOne {
+	ecuo.mutation.ClearRollbackTokenHash()
+	return ecuo
+}
+
+// SetRollbackExpiresAt sets the "rollback_expires_at" field.
+func (ecuo *EmailChangeUpdateOne) SetRollbackExpiresAt(t time.Time) *EmailChangeUpdateOne {
+	ecuo.mutation.SetRollbackExpiresAt(t)
+	return ecuo
+}
+
+// SetNillableRollbackExpiresAt sets the "rollback_expires_at" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableRollbackExpiresAt(t *time.Time) *EmailChangeUpdateOne {
+	if t != nil {
+		ecuo.SetRollbackExpiresAt(*t)
+	}
+	return ecuo
+}
+
+// ClearRollbackExpiresAt clears the value of the "rollback_expires_at" field.
+func (ecuo *EmailChangeUpdateOne) ClearRollbackExpiresAt() *EmailChangeUpdateOne {
+	ecuo.mutation.ClearRollbackExpiresAt()
+	return ecuo
+}
+
+// SetRolledBackAt sets the "rolled_back_at" field.
+func (ecuo *EmailChangeUpdateOne) SetRolledBackAt(t time.Time) *EmailChangeUpdateOne {
+	ecuo.mutation.SetRolledBackAt(t)
+	return ecuo
+}
+
+// SetNillableRolledBackAt sets the "rolled_back_at" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableRolledBackAt(t *time.Time) *EmailChangeUpdateOne {
+	if t != nil {
+		ecuo.SetRolledBackAt(*t)
+	}
+	return ecuo
+}
+
+// ClearRolledBackAt clears the value of the "rolled_back_at" field.
+func (ecuo *EmailChangeUpdateOne) ClearRolledBackAt() *EmailChangeUpdateOne {
+	ecuo.mutation.ClearRolledBackAt()
+	return ecuo
+}
+
+// SetRequestedIP sets the "requested_ip" field.
+func (ecuo *EmailChangeUpdateOne) SetRequestedIP(s string) *EmailChangeUpdateOne {
+	ecuo.mutation.SetRequestedIP(s)
+	return ecuo
+}
+
+// SetNillableRequestedIP sets the "requested_ip" field if the given value is not nil.
+func (ecuo *EmailChangeUpdateOne) SetNillableRequestedIP(s *string) *EmailChangeUpdateOne {
+	if s != nil {
+		ecuo.SetRequestedIP(*s)
+	}
+	return ecuo
+}
+
+// ClearRequestedIP clears the value of the "requested_ip" field.
+func (ecuo *EmailChangeUpdateOne) ClearRequestedIP() *EmailChangeUpdateOne {
+	ecuo.mutation.ClearRequestedIP()
+	return ecuo
+}
+
+// Mutation returns the EmailChangeMutation object of the builder.
+func (ecuo *EmailChangeUpdateOne) Mutation() *EmailChangeMutation {
+	return ecuo.mutation
+}
+
+// Where appends a list predicates to the EmailChangeUpdate builder.
+func (ecuo *EmailChangeUpdateOne) Where(ps ...predicate.EmailChange) *EmailChangeUpdateOne {
+	ecuo.mutation.Where(ps...)
+	return ecuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (ecuo *EmailChangeUpdateOne) Select(field string, fields ...string) *EmailChangeUpdateOne {
+	ecuo.fields = append([]string{field}, fields...)
+	return ecuo
+}
+
+// Save executes the query and returns the updated EmailChange entity.
+func (ecuo *EmailChangeUpdateOne) Save(ctx context.Context) (*EmailChange, error) {
+	if err := ecuo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, ecuo.sqlSave, ecuo.mutation, ecuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ecuo *EmailChangeUpdateOne) SaveX(ctx context.Context) *EmailChange {
+	node, err := ecuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (ecuo *EmailChangeUpdateOne) Exec(ctx context.Context) error {
+	_, err := ecuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ecuo *EmailChangeUpdateOne) ExecX(ctx context.Context) {
+	if err := ecuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ecuo *EmailChangeUpdateOne) defaults() error {
+	if _, ok := ecuo.mutation.UpdatedAt(); !ok {
+		if emailchange.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized emailchange.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := emailchange.UpdateDefaultUpdatedAt()
+		ecuo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ecuo *EmailChangeUpdateOne) check() error {
+	if v, ok := ecuo.mutation.UserID(); ok {
+		if err := emailchange.UserIDValidator(v); err != nil {
+			return &ValidationError{Name: "user_id", err: fmt.Errorf(`ent: validator failed for field "EmailChange.user_id": %w`, err)}
+		}
+	}
+	if v, ok := ecuo.mutation.OldEmail(); ok {
+		if err := emailchange.OldEmailValidator(v); err != nil {
+			return &ValidationError{Name: "old_email", err: fmt.Errorf(`ent: validator failed for field "EmailChange.old_email": %w`, err)}
+		}
+	}
+	if v, ok := ecuo.mutation.NewEmail(); ok {
+		if err := emailchange.NewEmailValidator(v); err != nil {
+			return &ValidationError{Name: "new_email", err: fmt.Errorf(`ent: validator failed for field "EmailChange.new_email": %w`, err)}
+		}
+	}
+	if v, ok := ecuo.mutation.Status(); ok {
+		if err := emailchange.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "EmailChange.status": %w`, err)}
+		}
+	}
+	if v, ok := ecuo.mutation.ConfirmTokenHash(); ok {
+		if err := emailchange.ConfirmTokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "confirm_token_hash", err: fmt.Errorf(`ent: validator failed for field "EmailChange.confirm_token_hash": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (ecuo *EmailChangeUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *EmailChangeUpdateOne {
+	ecuo.modifiers = append(ecuo.modifiers, modifiers...)
+	return ecuo
+}
+
+func (ecuo *EmailChangeUpdateOne) sqlSave(ctx context.Context) (_node *EmailChange, err error) {
+	if err := ecuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(emailchange.Table, emailchange.Columns, sqlgraph.NewFieldSpec(emailchange.FieldID, field.TypeString))
+	id, ok := ecuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "EmailChange.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := ecuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, emailchange.FieldID)
+		for _, f := range fields {
+			if !emailchange.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != emailchange.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := ecuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ecuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(emailchange.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if ecuo.mutation.CreatedByCleared() {
+		_spec.ClearField(emailchange.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := ecuo.mutation.UpdatedBy(); ok {
+		_spec.SetField(emailchange.FieldUpdatedBy, field.TypeString, value)
+	}
+	if ecuo.mutation.UpdatedByCleared() {
+		_spec.ClearField(emailchange.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := ecuo.mutation.UserID(); ok {
+		_spec.SetField(emailchange.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := ecuo.mutation.OldEmail(); ok {
+		_spec.SetField(emailchange.FieldOldEmail, field.TypeString, value)
+	}
+	if value, ok := ecuo.mutation.NewEmail(); ok {
+		_spec.SetField(emailchange.FieldNewEmail, field.TypeString, value)
+	}
+	if value, ok := ecuo.mutation.Status(); ok {
+		_spec.SetField(emailchange.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := ecuo.mutation.ConfirmTokenHash(); ok {
+		_spec.SetField(emailchange.FieldConfirmTokenHash, field.TypeString, value)
+	}
+	if value, ok := ecuo.mutation.ConfirmExpiresAt(); ok {
+		_spec.SetField(emailchange.FieldConfirmExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := ecuo.mutation.ConfirmedAt(); ok {
+		_spec.SetField(emailchange.FieldConfirmedAt, field.TypeTime, value)
+	}
+	if ecuo.mutation.ConfirmedAtCleared() {
+		_spec.ClearField(emailchange.FieldConfirmedAt, field.TypeTime)
+	}
+	if value, ok := ecuo.mutation.RollbackTokenHash(); ok {
+		_spec.SetField(emailchange.FieldRollbackTokenHash, field.TypeString, value)
+	}
+	if ecuo.mutation.RollbackTokenHashCleared() {
+		_spec.ClearField(emailchange.FieldRollbackTokenHash, field.TypeString)
+	}
+	if value, ok := ecuo.mutation.RollbackExpiresAt(); ok {
+		_spec.SetField(emailchange.FieldRollbackExpiresAt, field.TypeTime, value)
+	}
+	if ecuo.mutation.RollbackExpiresAtCleared() {
+		_spec.ClearField(emailchange.FieldRollbackExpiresAt, field.TypeTime)
+	}
+	if value, ok := ecuo.mutation.RolledBackAt(); ok {
+		_spec.SetField(emailchange.FieldRolledBackAt, field.TypeTime, value)
+	}
+	if ecuo.mutation.RolledBackAtCleared() {
+		_spec.ClearField(emailchange.FieldRolledBackAt, field.TypeTime)
+	}
+	if value, ok := ecuo.mutation.RequestedIP(); ok {
+		_spec.SetField(emailchange.FieldRequestedIP, field.TypeString, value)
+	}
+	if ecuo.mutation.RequestedIPCleared() {
+		_spec.ClearField(emailchange.FieldRequestedIP, field.TypeString)
+	}
+	_spec.AddModifiers(ecuo.modifiers...)
+	_node = &EmailChange{config: ecuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, ecuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{emailchange.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	ecuo.mutation.done = true
+	return _node, nil
+}
@@ -12,7 +12,22 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/hewenyu/gin-pkg/internal/ent/activity"
+	"github.com/hewenyu/gin-pkg/internal/ent/appcredential"
+	"github.com/hewenyu/gin-pkg/internal/ent/auditevent"
+	"github.com/hewenyu/gin-pkg/internal/ent/emailchange"
+	"github.com/hewenyu/gin-pkg/internal/ent/group"
+	"github.com/hewenyu/gin-pkg/internal/ent/invitation"
+	"github.com/hewenyu/gin-pkg/internal/ent/membership"
+	"github.com/hewenyu/gin-pkg/internal/ent/oauthclient"
+	"github.com/hewenyu/gin-pkg/internal/ent/organization"
+	"github.com/hewenyu/gin-pkg/internal/ent/permission"
+	"github.com/hewenyu/gin-pkg/internal/ent/refreshtoken"
+	"github.com/hewenyu/gin-pkg/internal/ent/role"
+	"github.com/hewenyu/gin-pkg/internal/ent/serviceaccount"
+	"github.com/hewenyu/gin-pkg/internal/ent/setting"
 	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/ent/userpreference"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -73,7 +88,22 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			user.Table: user.ValidColumn,
+			activity.Table:       activity.ValidColumn,
+			appcredential.Table:  appcredential.ValidColumn,
+			auditevent.Table:     auditevent.ValidColumn,
+			emailchange.Table:    emailchange.ValidColumn,
+			group.Table:          group.ValidColumn,
+			invitation.Table:     invitation.ValidColumn,
+			membership.Table:     membership.ValidColumn,
+			oauthclient.Table:    oauthclient.ValidColumn,
+			organization.Table:   organization.ValidColumn,
+			permission.Table:     permission.ValidColumn,
+			refreshtoken.Table:   refreshtoken.ValidColumn,
+			role.Table:           role.ValidColumn,
+			serviceaccount.Table: serviceaccount.ValidColumn,
+			setting.Table:        setting.ValidColumn,
+			user.Table:           user.ValidColumn,
+			userpreference.Table: userpreference.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
@@ -0,0 +1,574 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/invitation"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+)
+
+// InvitationUpdate is the builder for updating Invitation entities.
+type InvitationUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *InvitationMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the InvitationUpdate builder.
+func (iu *InvitationUpdate) Where(ps ...predicate.Invitation) *InvitationUpdate {
+	iu.mutation.Where(ps...)
+	return iu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (iu *InvitationUpdate) SetUpdatedAt(t time.Time) *InvitationUpdate {
+	iu.mutation.SetUpdatedAt(t)
+	return iu
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (iu *InvitationUpdate) SetUpdatedBy(s string) *InvitationUpdate {
+	iu.mutation.SetUpdatedBy(s)
+	return iu
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableUpdatedBy(s *string) *InvitationUpdate {
+	if s != nil {
+		iu.SetUpdatedBy(*s)
+	}
+	return iu
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (iu *InvitationUpdate) ClearUpdatedBy() *InvitationUpdate {
+	iu.mutation.ClearUpdatedBy()
+	return iu
+}
+
+// SetEmail sets the "email" field.
+func (iu *InvitationUpdate) SetEmail(s string) *InvitationUpdate {
+	iu.mutation.SetEmail(s)
+	return iu
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableEmail(s *string) *InvitationUpdate {
+	if s != nil {
+		iu.SetEmail(*s)
+	}
+	return iu
+}
+
+// SetRole sets the "role" field.
+func (iu *InvitationUpdate) SetRole(s string) *InvitationUpdate {
+	iu.mutation.SetRole(s)
+	return iu
+}
+
+// SetNillableRole sets the "role" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableRole(s *string) *InvitationUpdate {
+	if s != nil {
+		iu.SetRole(*s)
+	}
+	return iu
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (iu *InvitationUpdate) SetTokenHash(s string) *InvitationUpdate {
+	iu.mutation.SetTokenHash(s)
+	return iu
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableTokenHash(s *string) *InvitationUpdate {
+	if s != nil {
+		iu.SetTokenHash(*s)
+	}
+	return iu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (iu *InvitationUpdate) SetExpiresAt(t time.Time) *InvitationUpdate {
+	iu.mutation.SetExpiresAt(t)
+	return iu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableExpiresAt(t *time.Time) *InvitationUpdate {
+	if t != nil {
+		iu.SetExpiresAt(*t)
+	}
+	return iu
+}
+
+// SetAcceptedAt sets the "accepted_at" field.
+func (iu *InvitationUpdate) SetAcceptedAt(t time.Time) *InvitationUpdate {
+	iu.mutation.SetAcceptedAt(t)
+	return iu
+}
+
+// SetNillableAcceptedAt sets the "accepted_at" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableAcceptedAt(t *time.Time) *InvitationUpdate {
+	if t != nil {
+		iu.SetAcceptedAt(*t)
+	}
+	return iu
+}
+
+// ClearAcceptedAt clears the value of the "accepted_at" field.
+func (iu *InvitationUpdate) ClearAcceptedAt() *InvitationUpdate {
+	iu.mutation.ClearAcceptedAt()
+	return iu
+}
+
+// SetAcceptedUserID sets the "accepted_user_id" field.
+func (iu *InvitationUpdate) SetAcceptedUserID(s string) *InvitationUpdate {
+	iu.mutation.SetAcceptedUserID(s)
+	return iu
+}
+
+// SetNillableAcceptedUserID sets the "accepted_user_id" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableAcceptedUserID(s *string) *InvitationUpdate {
+	if s != nil {
+		iu.SetAcceptedUserID(*s)
+	}
+	return iu
+}
+
+// ClearAcceptedUserID clears the value of the "accepted_user_id" field.
+func (iu *InvitationUpdate) ClearAcceptedUserID() *InvitationUpdate {
+	iu.mutation.ClearAcceptedUserID()
+	return iu
+}
+
+// Mutation returns the InvitationMutation object of the builder.
+func (iu *InvitationUpdate) Mutation() *InvitationMutation {
+	return iu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (iu *InvitationUpdate) Save(ctx context.Context) (int, error) {
+	if err := iu.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks(ctx, iu.sqlSave, iu.mutation, iu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iu *InvitationUpdate) SaveX(ctx context.Context) int {
+	affected, err := iu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (iu *InvitationUpdate) Exec(ctx context.Context) error {
+	_, err := iu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iu *InvitationUpdate) ExecX(ctx context.Context) {
+	if err := iu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (iu *InvitationUpdate) defaults() error {
+	if _, ok := iu.mutation.UpdatedAt(); !ok {
+		if invitation.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized invitation.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := invitation.UpdateDefaultUpdatedAt()
+		iu.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (iu *InvitationUpdate) check() error {
+	if v, ok := iu.mutation.Email(); ok {
+		if err := invitation.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "Invitation.email": %w`, err)}
+		}
+	}
+	if v, ok := iu.mutation.TokenHash(); ok {
+		if err := invitation.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "Invitation.token_hash": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (iu *InvitationUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *InvitationUpdate {
+	iu.modifiers = append(iu.modifiers, modifiers...)
+	return iu
+}
+
+func (iu *InvitationUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := iu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(invitation.Table, invitation.Columns, sqlgraph.NewFieldSpec(invitation.FieldID, field.TypeString))
+	if ps := iu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := iu.mutation.UpdatedAt(); ok {
+		_spec.SetField(invitation.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if iu.mutation.CreatedByCleared() {
+		_spec.ClearField(invitation.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := iu.mutation.UpdatedBy(); ok {
+		_spec.SetField(invitation.FieldUpdatedBy, field.TypeString, value)
+	}
+	if iu.mutation.UpdatedByCleared() {
+		_spec.ClearField(invitation.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := iu.mutation.Email(); ok {
+		_spec.SetField(invitation.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := iu.mutation.Role(); ok {
+		_spec.SetField(invitation.FieldRole, field.TypeString, value)
+	}
+	if value, ok := iu.mutation.TokenHash(); ok {
+		_spec.SetField(invitation.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := iu.mutation.ExpiresAt(); ok {
+		_spec.SetField(invitation.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := iu.mutation.AcceptedAt(); ok {
+		_spec.SetField(invitation.FieldAcceptedAt, field.TypeTime, value)
+	}
+	if iu.mutation.AcceptedAtCleared() {
+		_spec.ClearField(invitation.FieldAcceptedAt, field.TypeTime)
+	}
+	if value, ok := iu.mutation.AcceptedUserID(); ok {
+		_spec.SetField(invitation.FieldAcceptedUserID, field.TypeString, value)
+	}
+	if iu.mutation.AcceptedUserIDCleared() {
+		_spec.ClearField(invitation.FieldAcceptedUserID, field.TypeString)
+	}
+	_spec.AddModifiers(iu.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, iu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{invitation.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	iu.mutation.done = true
+	return n, nil
+}
+
+// InvitationUpdateOne is the builder for updating a single Invitation entity.
+type InvitationUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *InvitationMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (iuo *InvitationUpdateOne) SetUpdatedAt(t time.Time) *InvitationUpdateOne {
+	iuo.mutation.SetUpdatedAt(t)
+	return iuo
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (iuo *InvitationUpdateOne) SetUpdatedBy(s string) *InvitationUpdateOne {
+	iuo.mutation.SetUpdatedBy(s)
+	return iuo
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableUpdatedBy(s *string) *InvitationUpdateOne {
+	if s != nil {
+		iuo.SetUpdatedBy(*s)
+	}
+	return iuo
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (iuo *InvitationUpdateOne) ClearUpdatedBy() *InvitationUpdateOne {
+	iuo.mutation.ClearUpdatedBy()
+	return iuo
+}
+
+// SetEmail sets the "email" field.
+func (iuo *InvitationUpdateOne) SetEmail(s string) *InvitationUpdateOne {
+	iuo.mutation.SetEmail(s)
+	return iuo
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableEmail(s *string) *InvitationUpdateOne {
+	if s != nil {
+		iuo.SetEmail(*s)
+	}
+	return iuo
+}
+
+// SetRole sets the "role" field.
+func (iuo *InvitationUpdateOne) SetRole(s string) *InvitationUpdateOne {
+	iuo.mutation.SetRole(s)
+	return iuo
+}
+
+// SetNillableRole sets the "role" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableRole(s *string) *InvitationUpdateOne {
+	if s != nil {
+		iuo.SetRole(*s)
+	}
+	return iuo
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (iuo *InvitationUpdateOne) SetTokenHash(s string) *InvitationUpdateOne {
+	iuo.mutation.SetTokenHash(s)
+	return iuo
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableTokenHash(s *string) *InvitationUpdateOne {
+	if s != nil {
+		iuo.SetTokenHash(*s)
+	}
+	return iuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (iuo *InvitationUpdateOne) SetExpiresAt(t time.Time) *InvitationUpdateOne {
+	iuo.mutation.SetExpiresAt(t)
+	return iuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableExpiresAt(t *time.Time) *InvitationUpdateOne {
+	if t != nil {
+		iuo.SetExpiresAt(*t)
+	}
+	return iuo
+}
+
+// SetAcceptedAt sets the "accepted_at" field.
+func (iuo *InvitationUpdateOne) SetAcceptedAt(t time.Time) *InvitationUpdateOne {
+	iuo.mutation.SetAcceptedAt(t)
+	return iuo
+}
+
+// SetNillableAcceptedAt sets the "accepted_at" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableAcceptedAt(t *time.Time) *InvitationUpdateOne {
+	if t != nil {
+		iuo.SetAcceptedAt(*t)
+	}
+	return iuo
+}
+
+// ClearAcceptedAt clears the value of the "accepted_at" field.
+func (iuo *InvitationUpdateOne) ClearAcceptedAt() *InvitationUpdateOne {
+	iuo.mutation.ClearAcceptedAt()
+	return iuo
+}
+
+// SetAcceptedUserID sets the "accepted_user_id" field.
+func (iuo *InvitationUpdateOne) SetAcceptedUserID(s string) *InvitationUpdateOne {
+	iuo.mutation.SetAcceptedUserID(s)
+	return iuo
+}
+
+// SetNillableAcceptedUserID sets the "accepted_user_id" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableAcceptedUserID(s *string) *InvitationUpdateOne {
+	if s != nil {
+		iuo.SetAcceptedUserID(*s)
+	}
+	return iuo
+}
+
+// ClearAcceptedUserID clears the value of the "accepted_user_id" field.
+func (iuo *InvitationUpdateOne) ClearAcceptedUserID() *InvitationUpdateOne {
+	iuo.mutation.ClearAcceptedUserID()
+	return iuo
+}
+
+// Mutation returns the InvitationMutation object of the builder.
+func (iuo *InvitationUpdateOne) Mutation() *InvitationMutation {
+	return iuo.mutation
+}
+
+// Where appends a list predicates to the InvitationUpdate builder.
+func (iuo *InvitationUpdateOne) Where(ps ...predicate.Invitation) *InvitationUpdateOne {
+	iuo.mutation.Where(ps...)
+	return iuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (iuo *InvitationUpdateOne) Select(field string, fields ...string) *InvitationUpdateOne {
+	iuo.fields = append([]string{field}, fields...)
+	return iuo
+}
+
+// Save executes the query and returns the updated Invitation entity.
+func (iuo *InvitationUpdateOne) Save(ctx context.Context) (*Invitation, error) {
+	if err := iuo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, iuo.sqlSave, iuo.mutation, iuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iuo *InvitationUpdateOne) SaveX(ctx context.Context) *Invitation {
+	node, err := iuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (iuo *InvitationUpdateOne) Exec(ctx context.Context) error {
+	_, err := iuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iuo *InvitationUpdateOne) ExecX(ctx context.Context) {
+	if err := iuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (iuo *InvitationUpdateOne) defaults() error {
+	if _, ok := iuo.mutation.UpdatedAt(); !ok {
+		if invitation.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized invitation.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := invitation.UpdateDefaultUpdatedAt()
+		iuo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (iuo *InvitationUpdateOne) check() error {
+	if v, ok := iuo.mutation.Email(); ok {
+		if err := invitation.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "Invitation.email": %w`, err)}
+		}
+	}
+	if v, ok := iuo.mutation.TokenHash(); ok {
+		if err := invitation.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "Invitation.token_hash": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (iuo *InvitationUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *InvitationUpdateOne {
+	iuo.modifiers = append(iuo.modifiers, modifiers...)
+	return iuo
+}
+
+func (iuo *InvitationUpdateOne) sqlSave(ctx context.Context) (_node *Invitation, err error) {
+	if err := iuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(invitation.Table, invitation.Columns, sqlgraph.NewFieldSpec(invitation.FieldID, field.TypeString))
+	id, ok := iuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Invitation.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := iuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, invitation.FieldID)
+		for _, f := range fields {
+			if !invitation.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != invitation.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := iuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := iuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(invitation.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if iuo.mutation.CreatedByCleared() {
+		_spec.ClearField(invitation.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := iuo.mutation.UpdatedBy(); ok {
+		_spec.SetField(invitation.FieldUpdatedBy, field.TypeString, value)
+	}
+	if iuo.mutation.UpdatedByCleared() {
+		_spec.ClearField(invitation.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := iuo.mutation.Email(); ok {
+		_spec.SetField(invitation.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := iuo.mutation.Role(); ok {
+		_spec.SetField(invitation.FieldRole, field.TypeString, value)
+	}
+	if value, ok := iuo.mutation.TokenHash(); ok {
+		_spec.SetField(invitation.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := iuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(invitation.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := iuo.mutation.AcceptedAt(); ok {
+		_spec.SetField(invitation.FieldAcceptedAt, field.TypeTime, value)
+	}
+	if iuo.mutation.AcceptedAtCleared() {
+		_spec.ClearField(invitation.FieldAcceptedAt, field.TypeTime)
+	}
+	if value, ok := iuo.mutation.AcceptedUserID(); ok {
+		_spec.SetField(invitation.FieldAcceptedUserID, field.TypeString, value)
+	}
+	if iuo.mutation.AcceptedUserIDCleared() {
+		_spec.ClearField(invitation.FieldAcceptedUserID, field.TypeString)
+	}
+	_spec.AddModifiers(iuo.modifiers...)
+	_node = &Invitation{config: iuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, iuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{invitation.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	iuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,387 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/oauthclient"
+)
+
+// OAuthClientCreate is the builder for creating a OAuthClient entity.
+type OAuthClientCreate struct {
+	config
+	mutation *OAuthClientMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (occ *OAuthClientCreate) SetCreatedAt(t time.Time) *OAuthClientCreate {
+	occ.mutation.SetCreatedAt(t)
+	return occ
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (occ *OAuthClientCreate) SetNillableCreatedAt(t *time.Time) *OAuthClientCreate {
+	if t != nil {
+		occ.SetCreatedAt(*t)
+	}
+	return occ
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (occ *OAuthClientCreate) SetUpdatedAt(t time.Time) *OAuthClientCreate {
+	occ.mutation.SetUpdatedAt(t)
+	return occ
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (occ *OAuthClientCreate) SetNillableUpdatedAt(t *time.Time) *OAuthClientCreate {
+	if t != nil {
+		occ.SetUpdatedAt(*t)
+	}
+	return occ
+}
+
+// SetCreatedBy sets the "created_by" field.
+func (occ *OAuthClientCreate) SetCreatedBy(s string) *OAuthClientCreate {
+	occ.mutation.SetCreatedBy(s)
+	return occ
+}
+
+// SetNillableCreatedBy sets the "created_by" field if the given value is not nil.
+func (occ *OAuthClientCreate) SetNillableCreatedBy(s *string) *OAuthClientCreate {
+	if s != nil {
+		occ.SetCreatedBy(*s)
+	}
+	return occ
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (occ *OAuthClientCreate) SetUpdatedBy(s string) *OAuthClientCreate {
+	occ.mutation.SetUpdatedBy(s)
+	return occ
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (occ *OAuthClientCreate) SetNillableUpdatedBy(s *string) *OAuthClientCreate {
+	if s != nil {
+		occ.SetUpdatedBy(*s)
+	}
+	return occ
+}
+
+// SetName sets the "name" field.
+func (occ *OAuthClientCreate) SetName(s string) *OAuthClientCreate {
+	occ.mutation.SetName(s)
+	return occ
+}
+
+// SetSecretHash sets the "secret_hash" field.
+func (occ *OAuthClientCreate) SetSecretHash(s string) *OAuthClientCreate {
+	occ.mutation.SetSecretHash(s)
+	return occ
+}
+
+// SetNillableSecretHash sets the "secret_hash" field if the given value is not nil.
+func (occ *OAuthClientCreate) SetNillableSecretHash(s *string) *OAuthClientCreate {
+	if s != nil {
+		occ.SetSecretHash(*s)
+	}
+	return occ
+}
+
+// SetRedirectUris sets the "redirect_uris" field.
+func (occ *OAuthClientCreate) SetRedirectUris(s []string) *OAuthClientCreate {
+	occ.mutation.SetRedirectUris(s)
+	return occ
+}
+
+// SetScopes sets the "scopes" field.
+func (occ *OAuthClientCreate) SetScopes(s []string) *OAuthClientCreate {
+	occ.mutation.SetScopes(s)
+	return occ
+}
+
+// SetFirstParty sets the "first_party" field.
+func (occ *OAuthClientCreate) SetFirstParty(b bool) *OAuthClientCreate {
+	occ.mutation.SetFirstParty(b)
+	return occ
+}
+
+// SetNillableFirstParty sets the "first_party" field if the given value is not nil.
+func (occ *OAuthClientCreate) SetNillableFirstParty(b *bool) *OAuthClientCreate {
+	if b != nil {
+		occ.SetFirstParty(*b)
+	}
+	return occ
+}
+
+// SetID sets the "id" field.
+func (occ *OAuthClientCreate) SetID(s string) *OAuthClientCreate {
+	occ.mutation.SetID(s)
+	return occ
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (occ *OAuthClientCreate) SetNillableID(s *string) *OAuthClientCreate {
+	if s != nil {
+		occ.SetID(*s)
+	}
+	return occ
+}
+
+// Mutation returns the OAuthClientMutation object of the builder.
+func (occ *OAuthClientCreate) Mutation() *OAuthClientMutation {
+	return occ.mutation
+}
+
+// Save creates the OAuthClient in the database.
+func (occ *OAuthClientCreate) Save(ctx context.Context) (*OAuthClient, error) {
+	if err := occ.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, occ.sqlSave, occ.mutation, occ.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (occ *OAuthClientCreate) SaveX(ctx context.Context) *OAuthClient {
+	v, err := occ.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (occ *OAuthClientCreate) Exec(ctx context.Context) error {
+	_, err := occ.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (occ *OAuthClientCreate) ExecX(ctx context.Context) {
+	if err := occ.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (occ *OAuthClientCreate) defaults() error {
+	if _, ok := occ.mutation.CreatedAt(); !ok {
+		if oauthclient.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized oauthclient.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
+		v := oauthclient.DefaultCreatedAt()
+		occ.mutation.SetCreatedAt(v)
+	}
+	if _, ok := occ.mutation.UpdatedAt(); !ok {
+		if oauthclient.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized oauthclient.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := oauthclient.DefaultUpdatedAt()
+		occ.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := occ.mutation.FirstParty(); !ok {
+		v := oauthclient.DefaultFirstParty
+		occ.mutation.SetFirstParty(v)
+	}
+	if _, ok := occ.mutation.ID(); !ok {
+		if oauthclient.DefaultID == nil {
+			return fmt.Errorf("ent: uninitialized oauthclient.DefaultID (forgotten import ent/runtime?)")
+		}
+		v := oauthclient.DefaultID()
+		occ.mutation.SetID(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (occ *OAuthClientCreate) check() error {
+	if _, ok := occ.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "OAuthClient.created_at"`)}
+	}
+	if _, ok := occ.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "OAuthClient.updated_at"`)}
+	}
+	if _, ok := occ.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "OAuthClient.name"`)}
+	}
+	if v, ok := occ.mutation.Name(); ok {
+		if err := oauthclient.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "OAuthClient.name": %w`, err)}
+		}
+	}
+	if _, ok := occ.mutation.RedirectUris(); !ok {
+		return &ValidationError{Name: "redirect_uris", err: errors.New(`ent: missing required field "OAuthClient.redirect_uris"`)}
+	}
+	if _, ok := occ.mutation.FirstParty(); !ok {
+		return &ValidationError{Name: "first_party", err: errors.New(`ent: missing required field "OAuthClient.first_party"`)}
+	}
+	if v, ok := occ.mutation.ID(); ok {
+		if err := oauthclient.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`ent: validator failed for field "OAuthClient.id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (occ *OAuthClientCreate) sqlSave(ctx context.Context) (*OAuthClient, error) {
+	if err := occ.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := occ.createSpec()
+	if err := sqlgraph.CreateNode(ctx, occ.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected OAuthClient.ID type: %T", _spec.ID.Value)
+		}
+	}
+	occ.mutation.id = &_node.ID
+	occ.mutation.done = true
+	return _node, nil
+}
+
+func (occ *OAuthClientCreate) createSpec() (*OAuthClient, *sqlgraph.CreateSpec) {
+	var (
+		_node = &OAuthClient{config: occ.config}
+		_spec = sqlgraph.NewCreateSpec(oauthclient.Table, sqlgraph.NewFieldSpec(oauthclient.FieldID, field.TypeString))
+	)
+	if id, ok := occ.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := occ.mutation.CreatedAt(); ok {
+		_spec.SetField(oauthclient.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := occ.mutation.UpdatedAt(); ok {
+		_spec.SetField(oauthclient.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := occ.mutation.CreatedBy(); ok {
+		_spec.SetField(oauthclient.FieldCreatedBy, field.TypeString, value)
+		_node.CreatedBy = value
+	}
+	if value, ok := occ.mutation.UpdatedBy(); ok {
+		_spec.SetField(oauthclient.FieldUpdatedBy, field.TypeString, value)
+		_node.UpdatedBy = value
+	}
+	if value, ok := occ.mutation.Name(); ok {
+		_spec.SetField(oauthclient.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := occ.mutation.SecretHash(); ok {
+		_spec.SetField(oauthclient.FieldSecretHash, field.TypeString, value)
+		_node.SecretHash = value
+	}
+	if value, ok := occ.mutation.RedirectUris(); ok {
+		_spec.SetField(oauthclient.FieldRedirectUris, field.TypeJSON, value)
+		_node.RedirectUris = value
+	}
+	if value, ok := occ.mutation.Scopes(); ok {
+		_spec.SetField(oauthclient.FieldScopes, field.TypeJSON, value)
+		_node.Scopes = value
+	}
+	if value, ok := occ.mutation.FirstParty(); ok {
+		_spec.SetField(oauthclient.FieldFirstParty, field.TypeBool, value)
+		_node.FirstParty = value
+	}
+	return _node, _spec
+}
+
+// OAuthClientCreateBulk is the builder for creating many OAuthClient entities in bulk.
+type OAuthClientCreateBulk struct {
+	config
+	err      error
+	builders []*OAuthClientCreate
+}
+
+// Save creates the OAuthClient entities in the database.
+func (occb *OAuthClientCreateBulk) Save(ctx context.Context) ([]*OAuthClient, error) {
+	if occb.err != nil {
+		return nil, occb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(occb.builders))
+	nodes := make([]*OAuthClient, len(occb.builders))
+	mutators := make([]Mutator, len(occb.builders))
+	for i := range occb.builders {
+		func(i int, root context.Context) {
+			builder := occb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*OAuthClientMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, occb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, occb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, occb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (occb *OAuthClientCreateBulk) SaveX(ctx context.Context) []*OAuthClient {
+	v, err := occb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (occb *OAuthClientCreateBulk) Exec(ctx context.Context) error {
+	_, err := occb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (occb *OAuthClientCreateBulk) ExecX(ctx context.Context) {
+	if err := occb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
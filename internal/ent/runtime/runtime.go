@@ -2,7 +2,587 @@
 
 package runtime
 
-// The schema-stitching logic is generated in github.com/hewenyu/gin-pkg/internal/ent/runtime.go
+import (
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent/activity"
+	"github.com/hewenyu/gin-pkg/internal/ent/appcredential"
+	"github.com/hewenyu/gin-pkg/internal/ent/auditevent"
+	"github.com/hewenyu/gin-pkg/internal/ent/emailchange"
+	"github.com/hewenyu/gin-pkg/internal/ent/group"
+	"github.com/hewenyu/gin-pkg/internal/ent/invitation"
+	"github.com/hewenyu/gin-pkg/internal/ent/membership"
+	"github.com/hewenyu/gin-pkg/internal/ent/oauthclient"
+	"github.com/hewenyu/gin-pkg/internal/ent/organization"
+	"github.com/hewenyu/gin-pkg/internal/ent/permission"
+	"github.com/hewenyu/gin-pkg/internal/ent/refreshtoken"
+	"github.com/hewenyu/gin-pkg/internal/ent/role"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
+	"github.com/hewenyu/gin-pkg/internal/ent/serviceaccount"
+	"github.com/hewenyu/gin-pkg/internal/ent/setting"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/ent/userpreference"
+)
+
+// The init function reads all schema descriptors with runtime code
+// (default values, validators, hooks and policies) and stitches it
+// to their package variables.
+func init() {
+	activityMixin := schema.Activity{}.Mixin()
+	activityMixinFields0 := activityMixin[0].Fields()
+	_ = activityMixinFields0
+	activityFields := schema.Activity{}.Fields()
+	_ = activityFields
+	// activityDescCreatedAt is the schema descriptor for created_at field.
+	activityDescCreatedAt := activityMixinFields0[0].Descriptor()
+	// activity.DefaultCreatedAt holds the default value on creation for the created_at field.
+	activity.DefaultCreatedAt = activityDescCreatedAt.Default.(func() time.Time)
+	// activityDescUpdatedAt is the schema descriptor for updated_at field.
+	activityDescUpdatedAt := activityMixinFields0[1].Descriptor()
+	// activity.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	activity.DefaultUpdatedAt = activityDescUpdatedAt.Default.(func() time.Time)
+	// activity.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	activity.UpdateDefaultUpdatedAt = activityDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// activityDescAction is the schema descriptor for action field.
+	activityDescAction := activityFields[2].Descriptor()
+	// activity.ActionValidator is a validator for the "action" field. It is called by the builders before save.
+	activity.ActionValidator = activityDescAction.Validators[0].(func(string) error)
+	// activityDescID is the schema descriptor for id field.
+	activityDescID := activityFields[0].Descriptor()
+	// activity.DefaultID holds the default value on creation for the id field.
+	activity.DefaultID = activityDescID.Default.(func() string)
+	// activity.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	activity.IDValidator = activityDescID.Validators[0].(func(string) error)
+	appcredentialMixin := schema.AppCredential{}.Mixin()
+	appcredentialMixinHooks1 := appcredentialMixin[1].Hooks()
+	appcredential.Hooks[0] = appcredentialMixinHooks1[0]
+	appcredentialMixinFields0 := appcredentialMixin[0].Fields()
+	_ = appcredentialMixinFields0
+	appcredentialFields := schema.AppCredential{}.Fields()
+	_ = appcredentialFields
+	// appcredentialDescCreatedAt is the schema descriptor for created_at field.
+	appcredentialDescCreatedAt := appcredentialMixinFields0[0].Descriptor()
+	// appcredential.DefaultCreatedAt holds the default value on creation for the created_at field.
+	appcredential.DefaultCreatedAt = appcredentialDescCreatedAt.Default.(func() time.Time)
+	// appcredentialDescUpdatedAt is the schema descriptor for updated_at field.
+	appcredentialDescUpdatedAt := appcredentialMixinFields0[1].Descriptor()
+	// appcredential.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	appcredential.DefaultUpdatedAt = appcredentialDescUpdatedAt.Default.(func() time.Time)
+	// appcredential.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	appcredential.UpdateDefaultUpdatedAt = appcredentialDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// appcredentialDescName is the schema descriptor for name field.
+	appcredentialDescName := appcredentialFields[1].Descriptor()
+	// appcredential.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	appcredential.NameValidator = appcredentialDescName.Validators[0].(func(string) error)
+	// appcredentialDescAppKey is the schema descriptor for app_key field.
+	appcredentialDescAppKey := appcredentialFields[2].Descriptor()
+	// appcredential.AppKeyValidator is a validator for the "app_key" field. It is called by the builders before save.
+	appcredential.AppKeyValidator = appcredentialDescAppKey.Validators[0].(func(string) error)
+	// appcredentialDescSecretCiphertext is the schema descriptor for secret_ciphertext field.
+	appcredentialDescSecretCiphertext := appcredentialFields[3].Descriptor()
+	// appcredential.SecretCiphertextValidator is a validator for the "secret_ciphertext" field. It is called by the builders before save.
+	appcredential.SecretCiphertextValidator = appcredentialDescSecretCiphertext.Validators[0].(func(string) error)
+	// appcredentialDescRateLimit is the schema descriptor for rate_limit field.
+	appcredentialDescRateLimit := appcredentialFields[5].Descriptor()
+	// appcredential.DefaultRateLimit holds the default value on creation for the rate_limit field.
+	appcredential.DefaultRateLimit = appcredentialDescRateLimit.Default.(int)
+	// appcredential.RateLimitValidator is a validator for the "rate_limit" field. It is called by the builders before save.
+	appcredential.RateLimitValidator = appcredentialDescRateLimit.Validators[0].(func(int) error)
+	// appcredentialDescRateWindow is the schema descriptor for rate_window field.
+	appcredentialDescRateWindow := appcredentialFields[6].Descriptor()
+	// appcredential.DefaultRateWindow holds the default value on creation for the rate_window field.
+	appcredential.DefaultRateWindow = appcredentialDescRateWindow.Default.(int)
+	// appcredential.RateWindowValidator is a validator for the "rate_window" field. It is called by the builders before save.
+	appcredential.RateWindowValidator = appcredentialDescRateWindow.Validators[0].(func(int) error)
+	// appcredentialDescID is the schema descriptor for id field.
+	appcredentialDescID := appcredentialFields[0].Descriptor()
+	// appcredential.DefaultID holds the default value on creation for the id field.
+	appcredential.DefaultID = appcredentialDescID.Default.(func() string)
+	// appcredential.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	appcredential.IDValidator = appcredentialDescID.Validators[0].(func(string) error)
+	auditeventMixin := schema.AuditEvent{}.Mixin()
+	auditeventMixinFields0 := auditeventMixin[0].Fields()
+	_ = auditeventMixinFields0
+	auditeventFields := schema.AuditEvent{}.Fields()
+	_ = auditeventFields
+	// auditeventDescCreatedAt is the schema descriptor for created_at field.
+	auditeventDescCreatedAt := auditeventMixinFields0[0].Descriptor()
+	// auditevent.DefaultCreatedAt holds the default value on creation for the created_at field.
+	auditevent.DefaultCreatedAt = auditeventDescCreatedAt.Default.(func() time.Time)
+	// auditeventDescUpdatedAt is the schema descriptor for updated_at field.
+	auditeventDescUpdatedAt := auditeventMixinFields0[1].Descriptor()
+	// auditevent.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	auditevent.DefaultUpdatedAt = auditeventDescUpdatedAt.Default.(func() time.Time)
+	// auditevent.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	auditevent.UpdateDefaultUpdatedAt = auditeventDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// auditeventDescAction is the schema descriptor for action field.
+	auditeventDescAction := auditeventFields[1].Descriptor()
+	// auditevent.ActionValidator is a validator for the "action" field. It is called by the builders before save.
+	auditevent.ActionValidator = auditeventDescAction.Validators[0].(func(string) error)
+	// auditeventDescID is the schema descriptor for id field.
+	auditeventDescID := auditeventFields[0].Descriptor()
+	// auditevent.DefaultID holds the default value on creation for the id field.
+	auditevent.DefaultID = auditeventDescID.Default.(func() string)
+	// auditevent.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	auditevent.IDValidator = auditeventDescID.Validators[0].(func(string) error)
+	emailchangeMixin := schema.EmailChange{}.Mixin()
+	emailchangeMixinHooks1 := emailchangeMixin[1].Hooks()
+	emailchange.Hooks[0] = emailchangeMixinHooks1[0]
+	emailchangeMixinFields0 := emailchangeMixin[0].Fields()
+	_ = emailchangeMixinFields0
+	emailchangeFields := schema.EmailChange{}.Fields()
+	_ = emailchangeFields
+	// emailchangeDescCreatedAt is the schema descriptor for created_at field.
+	emailchangeDescCreatedAt := emailchangeMixinFields0[0].Descriptor()
+	// emailchange.DefaultCreatedAt holds the default value on creation for the created_at field.
+	emailchange.DefaultCreatedAt = emailchangeDescCreatedAt.Default.(func() time.Time)
+	// emailchangeDescUpdatedAt is the schema descriptor for updated_at field.
+	emailchangeDescUpdatedAt := emailchangeMixinFields0[1].Descriptor()
+	// emailchange.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	emailchange.DefaultUpdatedAt = emailchangeDescUpdatedAt.Default.(func() time.Time)
+	// emailchange.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	emailchange.UpdateDefaultUpdatedAt = emailchangeDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// emailchangeDescUserID is the schema descriptor for user_id field.
+	emailchangeDescUserID := emailchangeFields[1].Descriptor()
+	// emailchange.UserIDValidator is a validator for the "user_id" field. It is called by the builders before save.
+	emailchange.UserIDValidator = emailchangeDescUserID.Validators[0].(func(string) error)
+	// emailchangeDescOldEmail is the schema descriptor for old_email field.
+	emailchangeDescOldEmail := emailchangeFields[2].Descriptor()
+	// emailchange.OldEmailValidator is a validator for the "old_email" field. It is called by the builders before save.
+	emailchange.OldEmailValidator = emailchangeDescOldEmail.Validators[0].(func(string) error)
+	// emailchangeDescNewEmail is the schema descriptor for new_email field.
+	emailchangeDescNewEmail := emailchangeFields[3].Descriptor()
+	// emailchange.NewEmailValidator is a validator for the "new_email" field. It is called by the builders before save.
+	emailchange.NewEmailValidator = emailchangeDescNewEmail.Validators[0].(func(string) error)
+	// emailchangeDescConfirmTokenHash is the schema descriptor for confirm_token_hash field.
+	emailchangeDescConfirmTokenHash := emailchangeFields[5].Descriptor()
+	// emailchange.ConfirmTokenHashValidator is a validator for the "confirm_token_hash" field. It is called by the builders before save.
+	emailchange.ConfirmTokenHashValidator = emailchangeDescConfirmTokenHash.Validators[0].(func(string) error)
+	// emailchangeDescID is the schema descriptor for id field.
+	emailchangeDescID := emailchangeFields[0].Descriptor()
+	// emailchange.DefaultID holds the default value on creation for the id field.
+	emailchange.DefaultID = emailchangeDescID.Default.(func() string)
+	// emailchange.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	emailchange.IDValidator = emailchangeDescID.Validators[0].(func(string) error)
+	groupMixin := schema.Group{}.Mixin()
+	groupMixinHooks1 := groupMixin[1].Hooks()
+	groupMixinHooks2 := groupMixin[2].Hooks()
+	group.Hooks[0] = groupMixinHooks1[0]
+	group.Hooks[1] = groupMixinHooks2[0]
+	groupMixinInters2 := groupMixin[2].Interceptors()
+	group.Interceptors[0] = groupMixinInters2[0]
+	groupMixinFields0 := groupMixin[0].Fields()
+	_ = groupMixinFields0
+	groupFields := schema.Group{}.Fields()
+	_ = groupFields
+	// groupDescCreatedAt is the schema descriptor for created_at field.
+	groupDescCreatedAt := groupMixinFields0[0].Descriptor()
+	// group.DefaultCreatedAt holds the default value on creation for the created_at field.
+	group.DefaultCreatedAt = groupDescCreatedAt.Default.(func() time.Time)
+	// groupDescUpdatedAt is the schema descriptor for updated_at field.
+	groupDescUpdatedAt := groupMixinFields0[1].Descriptor()
+	// group.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	group.DefaultUpdatedAt = groupDescUpdatedAt.Default.(func() time.Time)
+	// group.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	group.UpdateDefaultUpdatedAt = groupDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// groupDescName is the schema descriptor for name field.
+	groupDescName := groupFields[1].Descriptor()
+	// group.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	group.NameValidator = groupDescName.Validators[0].(func(string) error)
+	// groupDescID is the schema descriptor for id field.
+	groupDescID := groupFields[0].Descriptor()
+	// group.DefaultID holds the default value on creation for the id field.
+	group.DefaultID = groupDescID.Default.(func() string)
+	// group.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	group.IDValidator = groupDescID.Validators[0].(func(string) error)
+	invitationMixin := schema.Invitation{}.Mixin()
+	invitationMixinHooks1 := invitationMixin[1].Hooks()
+	invitation.Hooks[0] = invitationMixinHooks1[0]
+	invitationMixinFields0 := invitationMixin[0].Fields()
+	_ = invitationMixinFields0
+	invitationFields := schema.Invitation{}.Fields()
+	_ = invitationFields
+	// invitationDescCreatedAt is the schema descriptor for created_at field.
+	invitationDescCreatedAt := invitationMixinFields0[0].Descriptor()
+	// invitation.DefaultCreatedAt holds the default value on creation for the created_at field.
+	invitation.DefaultCreatedAt = invitationDescCreatedAt.Default.(func() time.Time)
+	// invitationDescUpdatedAt is the schema descriptor for updated_at field.
+	invitationDescUpdatedAt := invitationMixinFields0[1].Descriptor()
+	// invitation.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	invitation.DefaultUpdatedAt = invitationDescUpdatedAt.Default.(func() time.Time)
+	// invitation.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	invitation.UpdateDefaultUpdatedAt = invitationDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// invitationDescEmail is the schema descriptor for email field.
+	invitationDescEmail := invitationFields[1].Descriptor()
+	// invitation.EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	invitation.EmailValidator = invitationDescEmail.Validators[0].(func(string) error)
+	// invitationDescRole is the schema descriptor for role field.
+	invitationDescRole := invitationFields[2].Descriptor()
+	// invitation.DefaultRole holds the default value on creation for the role field.
+	invitation.DefaultRole = invitationDescRole.Default.(string)
+	// invitationDescTokenHash is the schema descriptor for token_hash field.
+	invitationDescTokenHash := invitationFields[3].Descriptor()
+	// invitation.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	invitation.TokenHashValidator = invitationDescTokenHash.Validators[0].(func(string) error)
+	// invitationDescID is the schema descriptor for id field.
+	invitationDescID := invitationFields[0].Descriptor()
+	// invitation.DefaultID holds the default value on creation for the id field.
+	invitation.DefaultID = invitationDescID.Default.(func() string)
+	// invitation.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	invitation.IDValidator = invitationDescID.Validators[0].(func(string) error)
+	membershipMixin := schema.Membership{}.Mixin()
+	membershipMixinHooks1 := membershipMixin[1].Hooks()
+	membership.Hooks[0] = membershipMixinHooks1[0]
+	membershipMixinFields0 := membershipMixin[0].Fields()
+	_ = membershipMixinFields0
+	membershipFields := schema.Membership{}.Fields()
+	_ = membershipFields
+	// membershipDescCreatedAt is the schema descriptor for created_at field.
+	membershipDescCreatedAt := membershipMixinFields0[0].Descriptor()
+	// membership.DefaultCreatedAt holds the default value on creation for the created_at field.
+	membership.DefaultCreatedAt = membershipDescCreatedAt.Default.(func() time.Time)
+	// membershipDescUpdatedAt is the schema descriptor for updated_at field.
+	membershipDescUpdatedAt := membershipMixinFields0[1].Descriptor()
+	// membership.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	membership.DefaultUpdatedAt = membershipDescUpdatedAt.Default.(func() time.Time)
+	// membership.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	membership.UpdateDefaultUpdatedAt = membershipDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// membershipDescID is the schema descriptor for id field.
+	membershipDescID := membershipFields[0].Descriptor()
+	// membership.DefaultID holds the default value on creation for the id field.
+	membership.DefaultID = membershipDescID.Default.(func() string)
+	// membership.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	membership.IDValidator = membershipDescID.Validators[0].(func(string) error)
+	oauthclientMixin := schema.OAuthClient{}.Mixin()
+	oauthclientMixinHooks1 := oauthclientMixin[1].Hooks()
+	oauthclient.Hooks[0] = oauthclientMixinHooks1[0]
+	oauthclientMixinFields0 := oauthclientMixin[0].Fields()
+	_ = oauthclientMixinFields0
+	oauthclientFields := schema.OAuthClient{}.Fields()
+	_ = oauthclientFields
+	// oauthclientDescCreatedAt is the schema descriptor for created_at field.
+	oauthclientDescCreatedAt := oauthclientMixinFields0[0].Descriptor()
+	// oauthclient.DefaultCreatedAt holds the default value on creation for the created_at field.
+	oauthclient.DefaultCreatedAt = oauthclientDescCreatedAt.Default.(func() time.Time)
+	// oauthclientDescUpdatedAt is the schema descriptor for updated_at field.
+	oauthclientDescUpdatedAt := oauthclientMixinFields0[1].Descriptor()
+	// oauthclient.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	oauthclient.DefaultUpdatedAt = oauthclientDescUpdatedAt.Default.(func() time.Time)
+	// oauthclient.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	oauthclient.UpdateDefaultUpdatedAt = oauthclientDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// oauthclientDescName is the schema descriptor for name field.
+	oauthclientDescName := oauthclientFields[1].Descriptor()
+	// oauthclient.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	oauthclient.NameValidator = oauthclientDescName.Validators[0].(func(string) error)
+	// oauthclientDescFirstParty is the schema descriptor for first_party field.
+	oauthclientDescFirstParty := oauthclientFields[5].Descriptor()
+	// oauthclient.DefaultFirstParty holds the default value on creation for the first_party field.
+	oauthclient.DefaultFirstParty = oauthclientDescFirstParty.Default.(bool)
+	// oauthclientDescID is the schema descriptor for id field.
+	oauthclientDescID := oauthclientFields[0].Descriptor()
+	// oauthclient.DefaultID holds the default value on creation for the id field.
+	oauthclient.DefaultID = oauthclientDescID.Default.(func() string)
+	// oauthclient.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	oauthclient.IDValidator = oauthclientDescID.Validators[0].(func(string) error)
+	organizationMixin := schema.Organization{}.Mixin()
+	organizationMixinHooks1 := organizationMixin[1].Hooks()
+	organization.Hooks[0] = organizationMixinHooks1[0]
+	organizationMixinFields0 := organizationMixin[0].Fields()
+	_ = organizationMixinFields0
+	organizationFields := schema.Organization{}.Fields()
+	_ = organizationFields
+	// organizationDescCreatedAt is the schema descriptor for created_at field.
+	organizationDescCreatedAt := organizationMixinFields0[0].Descriptor()
+	// organization.DefaultCreatedAt holds the default value on creation for the created_at field.
+	organization.DefaultCreatedAt = organizationDescCreatedAt.Default.(func() time.Time)
+	// organizationDescUpdatedAt is the schema descriptor for updated_at field.
+	organizationDescUpdatedAt := organizationMixinFields0[1].Descriptor()
+	// organization.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	organization.DefaultUpdatedAt = organizationDescUpdatedAt.Default.(func() time.Time)
+	// organization.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	organization.UpdateDefaultUpdatedAt = organizationDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// organizationDescName is the schema descriptor for name field.
+	organizationDescName := organizationFields[1].Descriptor()
+	// organization.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	organization.NameValidator = organizationDescName.Validators[0].(func(string) error)
+	// organizationDescSlug is the schema descriptor for slug field.
+	organizationDescSlug := organizationFields[2].Descriptor()
+	// organization.SlugValidator is a validator for the "slug" field. It is called by the builders before save.
+	organization.SlugValidator = organizationDescSlug.Validators[0].(func(string) error)
+	// organizationDescActive is the schema descriptor for active field.
+	organizationDescActive := organizationFields[3].Descriptor()
+	// organization.DefaultActive holds the default value on creation for the active field.
+	organization.DefaultActive = organizationDescActive.Default.(bool)
+	// organizationDescID is the schema descriptor for id field.
+	organizationDescID := organizationFields[0].Descriptor()
+	// organization.DefaultID holds the default value on creation for the id field.
+	organization.DefaultID = organizationDescID.Default.(func() string)
+	// organization.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	organization.IDValidator = organizationDescID.Validators[0].(func(string) error)
+	permissionMixin := schema.Permission{}.Mixin()
+	permissionMixinHooks1 := permissionMixin[1].Hooks()
+	permission.Hooks[0] = permissionMixinHooks1[0]
+	permissionMixinFields0 := permissionMixin[0].Fields()
+	_ = permissionMixinFields0
+	permissionFields := schema.Permission{}.Fields()
+	_ = permissionFields
+	// permissionDescCreatedAt is the schema descriptor for created_at field.
+	permissionDescCreatedAt := permissionMixinFields0[0].Descriptor()
+	// permission.DefaultCreatedAt holds the default value on creation for the created_at field.
+	permission.DefaultCreatedAt = permissionDescCreatedAt.Default.(func() time.Time)
+	// permissionDescUpdatedAt is the schema descriptor for updated_at field.
+	permissionDescUpdatedAt := permissionMixinFields0[1].Descriptor()
+	// permission.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	permission.DefaultUpdatedAt = permissionDescUpdatedAt.Default.(func() time.Time)
+	// permission.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	permission.UpdateDefaultUpdatedAt = permissionDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// permissionDescName is the schema descriptor for name field.
+	permissionDescName := permissionFields[1].Descriptor()
+	// permission.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	permission.NameValidator = permissionDescName.Validators[0].(func(string) error)
+	// permissionDescID is the schema descriptor for id field.
+	permissionDescID := permissionFields[0].Descriptor()
+	// permission.DefaultID holds the default value on creation for the id field.
+	permission.DefaultID = permissionDescID.Default.(func() string)
+	// permission.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	permission.IDValidator = permissionDescID.Validators[0].(func(string) error)
+	refreshtokenMixin := schema.RefreshToken{}.Mixin()
+	refreshtokenMixinFields0 := refreshtokenMixin[0].Fields()
+	_ = refreshtokenMixinFields0
+	refreshtokenFields := schema.RefreshToken{}.Fields()
+	_ = refreshtokenFields
+	// refreshtokenDescCreatedAt is the schema descriptor for created_at field.
+	refreshtokenDescCreatedAt := refreshtokenMixinFields0[0].Descriptor()
+	// refreshtoken.DefaultCreatedAt holds the default value on creation for the created_at field.
+	refreshtoken.DefaultCreatedAt = refreshtokenDescCreatedAt.Default.(func() time.Time)
+	// refreshtokenDescUpdatedAt is the schema descriptor for updated_at field.
+	refreshtokenDescUpdatedAt := refreshtokenMixinFields0[1].Descriptor()
+	// refreshtoken.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	refreshtoken.DefaultUpdatedAt = refreshtokenDescUpdatedAt.Default.(func() time.Time)
+	// refreshtoken.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	refreshtoken.UpdateDefaultUpdatedAt = refreshtokenDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// refreshtokenDescSessionID is the schema descriptor for session_id field.
+	refreshtokenDescSessionID := refreshtokenFields[1].Descriptor()
+	// refreshtoken.SessionIDValidator is a validator for the "session_id" field. It is called by the builders before save.
+	refreshtoken.SessionIDValidator = refreshtokenDescSessionID.Validators[0].(func(string) error)
+	// refreshtokenDescTokenHash is the schema descriptor for token_hash field.
+	refreshtokenDescTokenHash := refreshtokenFields[3].Descriptor()
+	// refreshtoken.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	refreshtoken.TokenHashValidator = refreshtokenDescTokenHash.Validators[0].(func(string) error)
+	// refreshtokenDescID is the schema descriptor for id field.
+	refreshtokenDescID := refreshtokenFields[0].Descriptor()
+	// refreshtoken.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	refreshtoken.IDValidator = refreshtokenDescID.Validators[0].(func(string) error)
+	roleMixin := schema.Role{}.Mixin()
+	roleMixinHooks1 := roleMixin[1].Hooks()
+	role.Hooks[0] = roleMixinHooks1[0]
+	roleMixinFields0 := roleMixin[0].Fields()
+	_ = roleMixinFields0
+	roleFields := schema.Role{}.Fields()
+	_ = roleFields
+	// roleDescCreatedAt is the schema descriptor for created_at field.
+	roleDescCreatedAt := roleMixinFields0[0].Descriptor()
+	// role.DefaultCreatedAt holds the default value on creation for the created_at field.
+	role.DefaultCreatedAt = roleDescCreatedAt.Default.(func() time.Time)
+	// roleDescUpdatedAt is the schema descriptor for updated_at field.
+	roleDescUpdatedAt := roleMixinFields0[1].Descriptor()
+	// role.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	role.DefaultUpdatedAt = roleDescUpdatedAt.Default.(func() time.Time)
+	// role.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	role.UpdateDefaultUpdatedAt = roleDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// roleDescName is the schema descriptor for name field.
+	roleDescName := roleFields[1].Descriptor()
+	// role.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	role.NameValidator = roleDescName.Validators[0].(func(string) error)
+	// roleDescBuiltin is the schema descriptor for builtin field.
+	roleDescBuiltin := roleFields[3].Descriptor()
+	// role.DefaultBuiltin holds the default value on creation for the builtin field.
+	role.DefaultBuiltin = roleDescBuiltin.Default.(bool)
+	// roleDescID is the schema descriptor for id field.
+	roleDescID := roleFields[0].Descriptor()
+	// role.DefaultID holds the default value on creation for the id field.
+	role.DefaultID = roleDescID.Default.(func() string)
+	// role.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	role.IDValidator = roleDescID.Validators[0].(func(string) error)
+	serviceaccountMixin := schema.ServiceAccount{}.Mixin()
+	serviceaccountMixinHooks1 := serviceaccountMixin[1].Hooks()
+	serviceaccount.Hooks[0] = serviceaccountMixinHooks1[0]
+	serviceaccountMixinFields0 := serviceaccountMixin[0].Fields()
+	_ = serviceaccountMixinFields0
+	serviceaccountFields := schema.ServiceAccount{}.Fields()
+	_ = serviceaccountFields
+	// serviceaccountDescCreatedAt is the schema descriptor for created_at field.
+	serviceaccountDescCreatedAt := serviceaccountMixinFields0[0].Descriptor()
+	// serviceaccount.DefaultCreatedAt holds the default value on creation for the created_at field.
+	serviceaccount.DefaultCreatedAt = serviceaccountDescCreatedAt.Default.(func() time.Time)
+	// serviceaccountDescUpdatedAt is the schema descriptor for updated_at field.
+	serviceaccountDescUpdatedAt := serviceaccountMixinFields0[1].Descriptor()
+	// serviceaccount.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	serviceaccount.DefaultUpdatedAt = serviceaccountDescUpdatedAt.Default.(func() time.Time)
+	// serviceaccount.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	serviceaccount.UpdateDefaultUpdatedAt = serviceaccountDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// serviceaccountDescName is the schema descriptor for name field.
+	serviceaccountDescName := serviceaccountFields[1].Descriptor()
+	// serviceaccount.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	serviceaccount.NameValidator = serviceaccountDescName.Validators[0].(func(string) error)
+	// serviceaccountDescTokenHash is the schema descriptor for token_hash field.
+	serviceaccountDescTokenHash := serviceaccountFields[3].Descriptor()
+	// serviceaccount.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	serviceaccount.TokenHashValidator = serviceaccountDescTokenHash.Validators[0].(func(string) error)
+	// serviceaccountDescTokenPrefix is the schema descriptor for token_prefix field.
+	serviceaccountDescTokenPrefix := serviceaccountFields[4].Descriptor()
+	// serviceaccount.TokenPrefixValidator is a validator for the "token_prefix" field. It is called by the builders before save.
+	serviceaccount.TokenPrefixValidator = serviceaccountDescTokenPrefix.Validators[0].(func(string) error)
+	// serviceaccountDescScopes is the schema descriptor for scopes field.
+	serviceaccountDescScopes := serviceaccountFields[5].Descriptor()
+	// serviceaccount.DefaultScopes holds the default value on creation for the scopes field.
+	serviceaccount.DefaultScopes = serviceaccountDescScopes.Default.([]string)
+	// serviceaccountDescAllowedCidrs is the schema descriptor for allowed_cidrs field.
+	serviceaccountDescAllowedCidrs := serviceaccountFields[6].Descriptor()
+	// serviceaccount.DefaultAllowedCidrs holds the default value on creation for the allowed_cidrs field.
+	serviceaccount.DefaultAllowedCidrs = serviceaccountDescAllowedCidrs.Default.([]string)
+	// serviceaccountDescUsageCount is the schema descriptor for usage_count field.
+	serviceaccountDescUsageCount := serviceaccountFields[11].Descriptor()
+	// serviceaccount.DefaultUsageCount holds the default value on creation for the usage_count field.
+	serviceaccount.DefaultUsageCount = serviceaccountDescUsageCount.Default.(int64)
+	// serviceaccountDescID is the schema descriptor for id field.
+	serviceaccountDescID := serviceaccountFields[0].Descriptor()
+	// serviceaccount.DefaultID holds the default value on creation for the id field.
+	serviceaccount.DefaultID = serviceaccountDescID.Default.(func() string)
+	// serviceaccount.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	serviceaccount.IDValidator = serviceaccountDescID.Validators[0].(func(string) error)
+	settingMixin := schema.Setting{}.Mixin()
+	settingMixinHooks1 := settingMixin[1].Hooks()
+	setting.Hooks[0] = settingMixinHooks1[0]
+	settingMixinFields0 := settingMixin[0].Fields()
+	_ = settingMixinFields0
+	settingFields := schema.Setting{}.Fields()
+	_ = settingFields
+	// settingDescCreatedAt is the schema descriptor for created_at field.
+	settingDescCreatedAt := settingMixinFields0[0].Descriptor()
+	// setting.DefaultCreatedAt holds the default value on creation for the created_at field.
+	setting.DefaultCreatedAt = settingDescCreatedAt.Default.(func() time.Time)
+	// settingDescUpdatedAt is the schema descriptor for updated_at field.
+	settingDescUpdatedAt := settingMixinFields0[1].Descriptor()
+	// setting.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	setting.DefaultUpdatedAt = settingDescUpdatedAt.Default.(func() time.Time)
+	// setting.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	setting.UpdateDefaultUpdatedAt = settingDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// settingDescKey is the schema descriptor for key field.
+	settingDescKey := settingFields[1].Descriptor()
+	// setting.KeyValidator is a validator for the "key" field. It is called by the builders before save.
+	setting.KeyValidator = settingDescKey.Validators[0].(func(string) error)
+	// settingDescID is the schema descriptor for id field.
+	settingDescID := settingFields[0].Descriptor()
+	// setting.DefaultID holds the default value on creation for the id field.
+	setting.DefaultID = settingDescID.Default.(func() string)
+	// setting.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	setting.IDValidator = settingDescID.Validators[0].(func(string) error)
+	userMixin := schema.User{}.Mixin()
+	userMixinHooks1 := userMixin[1].Hooks()
+	userMixinHooks2 := userMixin[2].Hooks()
+	userMixinHooks3 := userMixin[3].Hooks()
+	userMixinHooks4 := userMixin[4].Hooks()
+	userHooks := schema.User{}.Hooks()
+	user.Hooks[0] = userMixinHooks1[0]
+	user.Hooks[1] = userMixinHooks2[0]
+	user.Hooks[2] = userMixinHooks3[0]
+	user.Hooks[3] = userMixinHooks4[0]
+	user.Hooks[4] = userHooks[0]
+	userMixinInters2 := userMixin[2].Interceptors()
+	userMixinInters3 := userMixin[3].Interceptors()
+	userMixinInters4 := userMixin[4].Interceptors()
+	user.Interceptors[0] = userMixinInters2[0]
+	user.Interceptors[1] = userMixinInters3[0]
+	user.Interceptors[2] = userMixinInters4[0]
+	userMixinFields0 := userMixin[0].Fields()
+	_ = userMixinFields0
+	userFields := schema.User{}.Fields()
+	_ = userFields
+	// userDescCreatedAt is the schema descriptor for created_at field.
+	userDescCreatedAt := userMixinFields0[0].Descriptor()
+	// user.DefaultCreatedAt holds the default value on creation for the created_at field.
+	user.DefaultCreatedAt = userDescCreatedAt.Default.(func() time.Time)
+	// userDescUpdatedAt is the schema descriptor for updated_at field.
+	userDescUpdatedAt := userMixinFields0[1].Descriptor()
+	// user.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	user.DefaultUpdatedAt = userDescUpdatedAt.Default.(func() time.Time)
+	// user.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	user.UpdateDefaultUpdatedAt = userDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// userDescEmail is the schema descriptor for email field.
+	userDescEmail := userFields[1].Descriptor()
+	// user.EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	user.EmailValidator = userDescEmail.Validators[0].(func(string) error)
+	// userDescUsername is the schema descriptor for username field.
+	userDescUsername := userFields[2].Descriptor()
+	// user.UsernameValidator is a validator for the "username" field. It is called by the builders before save.
+	user.UsernameValidator = userDescUsername.Validators[0].(func(string) error)
+	// userDescPasswordHash is the schema descriptor for password_hash field.
+	userDescPasswordHash := userFields[3].Descriptor()
+	// user.PasswordHashValidator is a validator for the "password_hash" field. It is called by the builders before save.
+	user.PasswordHashValidator = userDescPasswordHash.Validators[0].(func(string) error)
+	// userDescRoles is the schema descriptor for roles field.
+	userDescRoles := userFields[5].Descriptor()
+	// user.DefaultRoles holds the default value on creation for the roles field.
+	user.DefaultRoles = userDescRoles.Default.([]string)
+	// userDescActive is the schema descriptor for active field.
+	userDescActive := userFields[6].Descriptor()
+	// user.DefaultActive holds the default value on creation for the active field.
+	user.DefaultActive = userDescActive.Default.(bool)
+	// userDescMustChangePassword is the schema descriptor for must_change_password field.
+	userDescMustChangePassword := userFields[7].Descriptor()
+	// user.DefaultMustChangePassword holds the default value on creation for the must_change_password field.
+	user.DefaultMustChangePassword = userDescMustChangePassword.Default.(bool)
+	// userDescPhone is the schema descriptor for phone field.
+	userDescPhone := userFields[8].Descriptor()
+	// user.PhoneValidator is a validator for the "phone" field. It is called by the builders before save.
+	user.PhoneValidator = userDescPhone.Validators[0].(func(string) error)
+	// userDescPhoneVerified is the schema descriptor for phone_verified field.
+	userDescPhoneVerified := userFields[9].Descriptor()
+	// user.DefaultPhoneVerified holds the default value on creation for the phone_verified field.
+	user.DefaultPhoneVerified = userDescPhoneVerified.Default.(bool)
+	// userDescID is the schema descriptor for id field.
+	userDescID := userFields[0].Descriptor()
+	// user.DefaultID holds the default value on creation for the id field.
+	user.DefaultID = userDescID.Default.(func() string)
+	// user.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	user.IDValidator = userDescID.Validators[0].(func(string) error)
+	userpreferenceMixin := schema.UserPreference{}.Mixin()
+	userpreferenceMixinHooks1 := userpreferenceMixin[1].Hooks()
+	userpreference.Hooks[0] = userpreferenceMixinHooks1[0]
+	userpreferenceMixinFields0 := userpreferenceMixin[0].Fields()
+	_ = userpreferenceMixinFields0
+	userpreferenceFields := schema.UserPreference{}.Fields()
+	_ = userpreferenceFields
+	// userpreferenceDescCreatedAt is the schema descriptor for created_at field.
+	userpreferenceDescCreatedAt := userpreferenceMixinFields0[0].Descriptor()
+	// userpreference.DefaultCreatedAt holds the default value on creation for the created_at field.
+	userpreference.DefaultCreatedAt = userpreferenceDescCreatedAt.Default.(func() time.Time)
+	// userpreferenceDescUpdatedAt is the schema descriptor for updated_at field.
+	userpreferenceDescUpdatedAt := userpreferenceMixinFields0[1].Descriptor()
+	// userpreference.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	userpreference.DefaultUpdatedAt = userpreferenceDescUpdatedAt.Default.(func() time.Time)
+	// userpreference.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	userpreference.UpdateDefaultUpdatedAt = userpreferenceDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// userpreferenceDescNotifyEmail is the schema descriptor for notify_email field.
+	userpreferenceDescNotifyEmail := userpreferenceFields[2].Descriptor()
+	// userpreference.DefaultNotifyEmail holds the default value on creation for the notify_email field.
+	userpreference.DefaultNotifyEmail = userpreferenceDescNotifyEmail.Default.(bool)
+	// userpreferenceDescNotifySms is the schema descriptor for notify_sms field.
+	userpreferenceDescNotifySms := userpreferenceFields[3].Descriptor()
+	// userpreference.DefaultNotifySms holds the default value on creation for the notify_sms field.
+	userpreference.DefaultNotifySms = userpreferenceDescNotifySms.Default.(bool)
+	// userpreferenceDescNotifyMarketing is the schema descriptor for notify_marketing field.
+	userpreferenceDescNotifyMarketing := userpreferenceFields[4].Descriptor()
+	// userpreference.DefaultNotifyMarketing holds the default value on creation for the notify_marketing field.
+	userpreference.DefaultNotifyMarketing = userpreferenceDescNotifyMarketing.Default.(bool)
+	// userpreferenceDescID is the schema descriptor for id field.
+	userpreferenceDescID := userpreferenceFields[0].Descriptor()
+	// userpreference.DefaultID holds the default value on creation for the id field.
+	userpreference.DefaultID = userpreferenceDescID.Default.(func() string)
+	// userpreference.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	userpreference.IDValidator = userpreferenceDescID.Validators[0].(func(string) error)
+}
 
 const (
 	Version = "v0.14.4"                                         // Version of ent codegen.
@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// Activity holds the schema definition for the Activity entity, an entry in the timeline
+// of significant actions of a user such as logins and password changes.
+type Activity struct {
+	ent.Schema
+}
+
+// Fields of the Activity.
+func (Activity) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("user_id").
+			Immutable().
+			Comment("用户ID"),
+		field.String("action").
+			NotEmpty().
+			Immutable().
+			Comment("操作，如 login、password.change"),
+		field.String("impersonated_by").
+			Optional().
+			Immutable().
+			Comment("模拟登录时实际操作的管理员ID"),
+		field.String("ip").
+			Optional().
+			Immutable().
+			Comment("客户端IP"),
+		field.String("user_agent").
+			Optional().
+			Immutable().
+			SchemaType(longText).
+			Comment("客户端 User-Agent"),
+	}
+}
+
+// Edges of the Activity.
+func (Activity) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("activities").
+			Field("user_id").
+			Unique().
+			Required().
+			Immutable(),
+	}
+}
+
+// Mixin of the Activity schema.
+func (Activity) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
+// Indexes of the Activity.
+func (Activity) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id", "created_at"),
+	}
+}
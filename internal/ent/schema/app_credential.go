@@ -0,0 +1,65 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// AppCredential holds the schema definition for the AppCredential entity.
+type AppCredential struct {
+	ent.Schema
+}
+
+// Fields of the AppCredential.
+func (AppCredential) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("name").
+			Unique().
+			NotEmpty().
+			Comment("接入方名称"),
+		field.String("app_key").
+			Unique().
+			Immutable().
+			NotEmpty().
+			Comment("应用标识，客户端通过请求头发送"),
+		field.String("secret_ciphertext").
+			NotEmpty().
+			Sensitive().
+			Comment("加密后的签名密钥，签名校验需要明文密钥，因此不能只保存哈希"),
+		field.Enum("status").
+			Values("active", "revoked").
+			Default("active").
+			Comment("状态"),
+		field.Int("rate_limit").
+			Default(0).
+			NonNegative().
+			Comment("限流窗口内的最大请求数，0表示不限制"),
+		field.Int("rate_window").
+			Default(60).
+			Positive().
+			Comment("限流窗口（秒）"),
+		field.Time("revoked_at").
+			Optional().
+			Nillable().
+			Comment("吊销时间"),
+	}
+}
+
+// Edges of the AppCredential.
+func (AppCredential) Edges() []ent.Edge {
+	return nil
+}
+
+// Mixin of the AppCredential schema.
+func (AppCredential) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
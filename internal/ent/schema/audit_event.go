@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// AuditEvent holds the schema definition for the AuditEvent entity.
+type AuditEvent struct {
+	ent.Schema
+}
+
+// Fields of the AuditEvent.
+func (AuditEvent) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("action").
+			NotEmpty().
+			Immutable().
+			Comment("操作，如 auth.login、admin.user.delete"),
+		field.String("actor_id").
+			Optional().
+			Immutable().
+			Comment("操作人ID，登录失败等未认证请求为空"),
+		field.String("impersonated_by").
+			Optional().
+			Immutable().
+			Comment("模拟登录时实际操作的管理员ID"),
+		field.String("subject").
+			Optional().
+			Immutable().
+			Comment("操作对象，如登录邮箱或被修改的资源ID"),
+		field.String("details").
+			Optional().
+			Immutable().
+			SchemaType(longText).
+			Comment("补充说明，如授予和撤销的角色"),
+		field.Enum("outcome").
+			Values("success", "failure").
+			Immutable().
+			Comment("结果"),
+		field.Int("status").
+			Optional().
+			Immutable().
+			Comment("HTTP 状态码"),
+		field.String("ip").
+			Optional().
+			Immutable().
+			Comment("客户端IP"),
+		field.String("user_agent").
+			Optional().
+			Immutable().
+			SchemaType(longText).
+			Comment("客户端 User-Agent"),
+	}
+}
+
+// Edges of the AuditEvent.
+func (AuditEvent) Edges() []ent.Edge {
+	return nil
+}
+
+// Mixin of the AuditEvent schema.
+func (AuditEvent) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
+// Indexes of the AuditEvent.
+func (AuditEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("created_at"),
+		index.Fields("actor_id", "created_at"),
+		index.Fields("action", "created_at"),
+	}
+}
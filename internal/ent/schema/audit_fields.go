@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"context"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/hewenyu/gin-pkg/internal/ent/hook"
+	"github.com/hewenyu/gin-pkg/pkg/actorctx"
+)
+
+// AuditFieldsMixin records who created a row and who changed it last. Mutations stamp the
+// actor of their context (see actorctx.From) unless they set the field themselves; mutations
+// without an actor, such as those of background jobs, leave the fields alone.
+type AuditFieldsMixin struct {
+	mixin.Schema
+}
+
+// Fields of the AuditFieldsMixin.
+func (AuditFieldsMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("created_by").
+			Optional().
+			Immutable().
+			Comment("创建人，用户ID或 service:<服务账号ID>"),
+		field.String("updated_by").
+			Optional().
+			Comment("最后修改人"),
+	}
+}
+
+// Hooks of the AuditFieldsMixin.
+func (AuditFieldsMixin) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hook.On(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+					actorID := actorctx.From(ctx)
+					if actorID == "" {
+						return next.Mutate(ctx, m)
+					}
+					fields := []string{"updated_by"}
+					if m.Op().Is(ent.OpCreate) {
+						fields = append(fields, "created_by")
+					}
+					for _, name := range fields {
+						if _, ok := m.Field(name); ok {
+							continue
+						}
+						if err := m.SetField(name, actorID); err != nil {
+							return nil, err
+						}
+					}
+					return next.Mutate(ctx, m)
+				})
+			},
+			ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne,
+		),
+	}
+}
@@ -0,0 +1,85 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// EmailChange holds the schema definition for the EmailChange entity.
+type EmailChange struct {
+	ent.Schema
+}
+
+// Fields of the EmailChange.
+func (EmailChange) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("user_id").
+			NotEmpty().
+			Comment("用户ID"),
+		field.String("old_email").
+			NotEmpty().
+			Comment("原邮箱"),
+		field.String("new_email").
+			NotEmpty().
+			Comment("新邮箱"),
+		field.Enum("status").
+			Values("pending", "confirmed", "rolled_back", "cancelled").
+			Default("pending").
+			Comment("状态"),
+		field.String("confirm_token_hash").
+			Unique().
+			NotEmpty().
+			Sensitive().
+			Comment("新邮箱确认令牌哈希"),
+		field.Time("confirm_expires_at").
+			Comment("确认链接过期时间"),
+		field.Time("confirmed_at").
+			Optional().
+			Nillable().
+			Comment("确认时间"),
+		field.String("rollback_token_hash").
+			Optional().
+			Nillable().
+			Unique().
+			Sensitive().
+			Comment("回滚令牌哈希"),
+		field.Time("rollback_expires_at").
+			Optional().
+			Nillable().
+			Comment("回滚链接过期时间"),
+		field.Time("rolled_back_at").
+			Optional().
+			Nillable().
+			Comment("回滚时间"),
+		field.String("requested_ip").
+			Optional().
+			Comment("发起请求的IP"),
+	}
+}
+
+// Edges of the EmailChange.
+func (EmailChange) Edges() []ent.Edge {
+	return nil
+}
+
+// Mixin of the EmailChange schema.
+func (EmailChange) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
+
+// Indexes of the EmailChange.
+func (EmailChange) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id", "status"),
+	}
+}
@@ -0,0 +1,187 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/hewenyu/gin-pkg/internal/ent/hook"
+	"github.com/hewenyu/gin-pkg/pkg/fieldcrypt"
+)
+
+var fieldEncryptor atomic.Pointer[fieldcrypt.Encryptor]
+
+// SetFieldEncryptor sets the encryptor of the fields of EncryptedFieldsMixin; nil stores
+// new values in plaintext
+func SetFieldEncryptor(e *fieldcrypt.Encryptor) {
+	fieldEncryptor.Store(e)
+}
+
+// EncryptedFieldsMixin encrypts string fields of a schema at rest with the encryptor set by
+// SetFieldEncryptor. Mutations encrypt the values they set, and queries and mutations
+// decrypt the entities they return. Values are bound to their type and field, e.g.
+// "User.phone", so they cannot be copied to another column. Without an encryptor, values
+// are stored in plaintext, and plaintext values written before encryption was configured
+// are read as is.
+type EncryptedFieldsMixin struct {
+	mixin.Schema
+	// Encrypted fields are encrypted with a random nonce
+	Encrypted []string
+	// Searchable fields are encrypted deterministically, which keeps unique indexes working
+	// and allows lookups by equality with EncryptedValues but reveals which rows are equal
+	Searchable []string
+	// Validators check the plaintext of fields before it is encrypted. The builders run the
+	// validators of the fields after the hooks, on the encrypted values, so those must accept
+	// values for which fieldcrypt.IsEncrypted reports true.
+	Validators map[string]func(string) error
+}
+
+// EncryptedValues returns the values a searchable encrypted field may hold for value, for
+// lookups such as user.PhoneIn(EncryptedValues("User", "phone", phone)...)
+func EncryptedValues(typ, field, value string) []string {
+	e := fieldEncryptor.Load()
+	if e == nil {
+		return []string{value}
+	}
+	// 明文用于查找配置加密之前写入的行
+	return append(e.SearchValues(value, typ+"."+field), value)
+}
+
+// Hooks of the EncryptedFieldsMixin.
+func (m EncryptedFieldsMixin) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hook.On(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, mu ent.Mutation) (ent.Value, error) {
+					if e := fieldEncryptor.Load(); e != nil {
+						if err := m.encrypt(e, mu); err != nil {
+							return nil, err
+						}
+					}
+					value, err := next.Mutate(ctx, mu)
+					if err != nil {
+						return value, err
+					}
+					return value, m.decrypt(value)
+				})
+			},
+			ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne,
+		),
+	}
+}
+
+// Interceptors of the EncryptedFieldsMixin.
+func (m EncryptedFieldsMixin) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		ent.InterceptFunc(func(next ent.Querier) ent.Querier {
+			return ent.QuerierFunc(func(ctx context.Context, q ent.Query) (ent.Value, error) {
+				value, err := next.Query(ctx, q)
+				if err != nil {
+					return value, err
+				}
+				return value, m.decrypt(value)
+			})
+		}),
+	}
+}
+
+// encrypt encrypts the values a mutation sets
+func (m EncryptedFieldsMixin) encrypt(e *fieldcrypt.Encryptor, mu ent.Mutation) error {
+	for _, name := range m.names() {
+		value, ok := mu.Field(name)
+		plaintext, isString := value.(string)
+		if !ok || !isString || plaintext == "" || fieldcrypt.IsEncrypted(plaintext) {
+			continue
+		}
+		column := mu.Type() + "." + name
+		if validate := m.Validators[name]; validate != nil {
+			if err := validate(plaintext); err != nil {
+				return fmt.Errorf(`validator failed for field "%s": %w`, column, err)
+			}
+		}
+		var encrypted string
+		if m.searchable(name) {
+			encrypted = e.EncryptSearchable(plaintext, column)
+		} else {
+			var err error
+			if encrypted, err = e.Encrypt(plaintext, column); err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", column, err)
+			}
+		}
+		if err := mu.SetField(name, encrypted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decrypt decrypts the fields of the entities in value, which holds an entity or a list of
+// them; other values, such as counts and IDs, are left alone
+func (m EncryptedFieldsMixin) decrypt(value ent.Value) error {
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Pointer:
+		for i := 0; i < rv.Len(); i++ {
+			if err := m.decryptEntity(rv.Index(i)); err != nil {
+				return err
+			}
+		}
+	case rv.Kind() == reflect.Pointer:
+		return m.decryptEntity(rv)
+	}
+	return nil
+}
+
+// decryptEntity decrypts the fields of the entity ptr points to. Generated entities name
+// their fields in their json tags.
+func (m EncryptedFieldsMixin) decryptEntity(ptr reflect.Value) error {
+	if ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	entity := ptr.Elem()
+	for i := 0; i < entity.NumField(); i++ {
+		name, _, _ := strings.Cut(entity.Type().Field(i).Tag.Get("json"), ",")
+		if !m.encrypted(name) {
+			continue
+		}
+		field := entity.Field(i)
+		if field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.String || !fieldcrypt.IsEncrypted(field.String()) {
+			continue
+		}
+		column := entity.Type().Name() + "." + name
+		e := fieldEncryptor.Load()
+		if e == nil {
+			return fmt.Errorf("%s is encrypted but no encryption keys are configured", column)
+		}
+		plaintext, err := e.Decrypt(field.String(), column)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", column, err)
+		}
+		field.SetString(plaintext)
+	}
+	return nil
+}
+
+func (m EncryptedFieldsMixin) names() []string {
+	return append(append([]string{}, m.Encrypted...), m.Searchable...)
+}
+
+func (m EncryptedFieldsMixin) encrypted(name string) bool {
+	return name != "" && (slices.Contains(m.Encrypted, name) || m.searchable(name))
+}
+
+func (m EncryptedFieldsMixin) searchable(name string) bool {
+	return slices.Contains(m.Searchable, name)
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// Group holds the schema definition for the Group entity.
+type Group struct {
+	ent.Schema
+}
+
+// Fields of the Group.
+func (Group) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("name").
+			NotEmpty().
+			Comment("团队名称"),
+		field.String("description").
+			Optional().
+			Comment("描述"),
+	}
+}
+
+// Edges of the Group.
+func (Group) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("memberships", Membership.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("团队成员关系"),
+	}
+}
+
+// Mixin of the Group schema.
+func (Group) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+		OrgScopeMixin{},
+	}
+}
+
+// Indexes of the Group.
+func (Group) Indexes() []ent.Index {
+	return []ent.Index{
+		// 团队名称在组织内唯一
+		index.Fields("org_id", "name").Unique(),
+	}
+}
@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// Invitation holds the schema definition for the Invitation entity.
+type Invitation struct {
+	ent.Schema
+}
+
+// Fields of the Invitation.
+func (Invitation) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("email").
+			NotEmpty().
+			Comment("受邀邮箱"),
+		field.String("role").
+			Default("user").
+			Comment("注册后的角色"),
+		field.String("token_hash").
+			Unique().
+			NotEmpty().
+			Sensitive().
+			Comment("邀请令牌哈希"),
+		field.Time("expires_at").
+			Comment("过期时间"),
+		field.Time("accepted_at").
+			Optional().
+			Nillable().
+			Comment("接受时间"),
+		field.String("accepted_user_id").
+			Optional().
+			Comment("通过邀请注册的用户ID"),
+	}
+}
+
+// Edges of the Invitation.
+func (Invitation) Edges() []ent.Edge {
+	return nil
+}
+
+// Mixin of the Invitation schema.
+func (Invitation) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
+
+// Indexes of the Invitation.
+func (Invitation) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("email"),
+	}
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// Membership holds the schema definition for the Membership entity, linking a user to a
+// group with a role within that group.
+type Membership struct {
+	ent.Schema
+}
+
+// Fields of the Membership.
+func (Membership) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("group_id").
+			Immutable().
+			Comment("团队ID"),
+		field.String("user_id").
+			Immutable().
+			Comment("用户ID"),
+		field.Enum("role").
+			Values("owner", "admin", "member").
+			Default("member").
+			Comment("团队内角色"),
+	}
+}
+
+// Edges of the Membership.
+func (Membership) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("group", Group.Type).
+			Ref("memberships").
+			Field("group_id").
+			Unique().
+			Required().
+			Immutable(),
+		edge.From("user", User.Type).
+			Ref("memberships").
+			Field("user_id").
+			Unique().
+			Required().
+			Immutable(),
+	}
+}
+
+// Mixin of the Membership schema.
+func (Membership) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
+
+// Indexes of the Membership.
+func (Membership) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("group_id", "user_id").Unique(),
+		index.Fields("user_id"),
+	}
+}
@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// OAuthClient holds the schema definition for the OAuthClient entity.
+type OAuthClient struct {
+	ent.Schema
+}
+
+// Fields of the OAuthClient.
+func (OAuthClient) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键，即 client_id"),
+		field.String("name").
+			NotEmpty().
+			Comment("应用名称，展示在授权确认页"),
+		field.String("secret_hash").
+			Optional().
+			Sensitive().
+			Comment("客户端密钥哈希，公开客户端（如单页应用、移动端）为空"),
+		field.Strings("redirect_uris").
+			Comment("允许的回调地址，需完全匹配"),
+		field.Strings("scopes").
+			Optional().
+			Comment("允许申请的权限范围"),
+		field.Bool("first_party").
+			Default(false).
+			Comment("第一方应用，跳过用户授权确认"),
+	}
+}
+
+// Edges of the OAuthClient.
+func (OAuthClient) Edges() []ent.Edge {
+	return nil
+}
+
+// Mixin of the OAuthClient schema.
+func (OAuthClient) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
@@ -0,0 +1,109 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/hewenyu/gin-pkg/internal/ent/hook"
+	"github.com/hewenyu/gin-pkg/internal/ent/intercept"
+	"github.com/hewenyu/gin-pkg/pkg/orgctx"
+)
+
+// OrgScopeMixin assigns rows to an organization. When the context acts in an organization
+// (see orgctx.With), queries, updates and deletes only see the organization's rows and
+// created rows are assigned to it. Contexts without an organization see every row.
+type OrgScopeMixin struct {
+	mixin.Schema
+}
+
+// Fields of the OrgScopeMixin.
+func (OrgScopeMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("org_id").
+			Optional().
+			Immutable().
+			Comment("所属组织，为空表示不属于任何组织"),
+	}
+}
+
+// Indexes of the OrgScopeMixin.
+func (OrgScopeMixin) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("org_id"),
+	}
+}
+
+type skipOrgScopeKey struct{}
+
+// SkipOrgScope returns a context in which queries and mutations see the rows of every
+// organization, e.g. to check values that are unique across the deployment
+func SkipOrgScope(parent context.Context) context.Context {
+	return context.WithValue(parent, skipOrgScopeKey{}, true)
+}
+
+// ScopedOrg returns the organization ctx is scoped to, or "" for unscoped contexts, e.g. to
+// apply the scope to users served from a cache
+func ScopedOrg(ctx context.Context) string {
+	if skip, _ := ctx.Value(skipOrgScopeKey{}).(bool); skip {
+		return ""
+	}
+	return orgctx.From(ctx)
+}
+
+// Interceptors of the OrgScopeMixin.
+func (o OrgScopeMixin) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		intercept.TraverseFunc(func(ctx context.Context, q intercept.Query) error {
+			if orgID := ScopedOrg(ctx); orgID != "" {
+				o.P(q, orgID)
+			}
+			return nil
+		}),
+	}
+}
+
+// Hooks of the OrgScopeMixin.
+func (o OrgScopeMixin) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hook.On(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+					orgID := ScopedOrg(ctx)
+					if orgID == "" {
+						return next.Mutate(ctx, m)
+					}
+					if m.Op().Is(ent.OpCreate) {
+						// 在组织内创建的行归属该组织，不能指定其他组织
+						if value, ok := m.Field("org_id"); ok && value != orgID {
+							return nil, fmt.Errorf("cannot create rows of organization %v in organization %s", value, orgID)
+						}
+						if err := m.SetField("org_id", orgID); err != nil {
+							return nil, err
+						}
+						return next.Mutate(ctx, m)
+					}
+					mx, ok := m.(interface {
+						WhereP(...func(*sql.Selector))
+					})
+					if !ok {
+						return nil, fmt.Errorf("unexpected mutation type %T", m)
+					}
+					o.P(mx, orgID)
+					return next.Mutate(ctx, m)
+				})
+			},
+			ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne|ent.OpDelete|ent.OpDeleteOne,
+		),
+	}
+}
+
+// P restricts a query or mutation to the rows of an organization
+func (o OrgScopeMixin) P(w interface{ WhereP(...func(*sql.Selector)) }, orgID string) {
+	w.WhereP(sql.FieldEQ(o.Fields()[0].Descriptor().Name, orgID))
+}
@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"regexp"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// Organization holds the schema definition for the Organization entity. Each organization
+// is an isolated tenant: its users and groups are only visible to requests acting in it.
+type Organization struct {
+	ent.Schema
+}
+
+// Fields of the Organization.
+func (Organization) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("name").
+			NotEmpty().
+			Comment("组织名称"),
+		field.String("slug").
+			Unique().
+			Match(regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)).
+			Comment("组织标识，小写字母、数字和连字符"),
+		field.Bool("active").
+			Default(true).
+			Comment("是否启用，停用后组织内的令牌全部失效"),
+	}
+}
+
+// Mixin of the Organization schema.
+func (Organization) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
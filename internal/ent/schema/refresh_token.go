@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// RefreshToken holds the schema definition for the RefreshToken entity, a refresh token
+// recorded when auth.refreshTokenStore is enabled. The tokens issued by refreshing share the
+// session of the login that issued the first one.
+type RefreshToken struct {
+	ent.Schema
+}
+
+// Fields of the RefreshToken.
+func (RefreshToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			Comment("令牌ID（jti）"),
+		field.String("session_id").
+			Immutable().
+			NotEmpty().
+			Comment("会话ID，即登录时签发的第一个刷新令牌的ID"),
+		field.String("user_id").
+			Immutable().
+			Comment("用户ID"),
+		field.String("token_hash").
+			Unique().
+			Immutable().
+			NotEmpty().
+			Sensitive().
+			Comment("令牌的 SHA-256 哈希，不保存令牌本身"),
+		field.String("ip").
+			Optional().
+			Immutable().
+			Comment("登录时的客户端IP"),
+		field.String("user_agent").
+			Optional().
+			Immutable().
+			SchemaType(longText).
+			Comment("登录时的客户端 User-Agent"),
+		field.String("impersonated_by").
+			Optional().
+			Immutable().
+			Comment("模拟登录时实际操作的管理员ID"),
+		field.String("client_id").
+			Optional().
+			Immutable().
+			Comment("签发给的 OAuth 客户端"),
+		field.Time("expires_at").
+			Immutable().
+			Comment("过期时间"),
+		field.Time("revoked_at").
+			Optional().
+			Nillable().
+			Comment("吊销时间，轮换后的令牌同样记录"),
+		field.String("revoked_reason").
+			Optional().
+			Comment("吊销原因：rotated、logout、user_revoked、reused 或 session_revoked"),
+		field.String("revoked_by").
+			Optional().
+			Comment("吊销会话的用户ID，管理员吊销他人会话时为管理员ID"),
+	}
+}
+
+// Edges of the RefreshToken.
+func (RefreshToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("refresh_tokens").
+			Field("user_id").
+			Unique().
+			Required().
+			Immutable(),
+	}
+}
+
+// Mixin of the RefreshToken schema.
+func (RefreshToken) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
+// Indexes of the RefreshToken.
+func (RefreshToken) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id", "revoked_at"),
+		index.Fields("session_id"),
+		index.Fields("expires_at"),
+	}
+}
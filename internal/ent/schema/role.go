@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// Role holds the schema definition for the Role entity.
+type Role struct {
+	ent.Schema
+}
+
+// Fields of the Role.
+func (Role) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("name").
+			Unique().
+			NotEmpty().
+			Immutable().
+			Comment("角色名，对应用户的 role 字段"),
+		field.String("description").
+			Optional().
+			Comment("描述"),
+		field.Bool("builtin").
+			Default(false).
+			Comment("内置角色，不可删除"),
+	}
+}
+
+// Edges of the Role.
+func (Role) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("permissions", Permission.Type).
+			Comment("角色拥有的权限"),
+	}
+}
+
+// Mixin of the Role schema.
+func (Role) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
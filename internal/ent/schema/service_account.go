@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// ServiceAccount holds the schema definition for the ServiceAccount entity.
+type ServiceAccount struct {
+	ent.Schema
+}
+
+// Fields of the ServiceAccount.
+func (ServiceAccount) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("name").
+			Unique().
+			NotEmpty().
+			Comment("名称"),
+		field.String("description").
+			Optional().
+			Comment("描述"),
+		field.String("token_hash").
+			Unique().
+			NotEmpty().
+			Sensitive().
+			Comment("令牌哈希"),
+		field.String("token_prefix").
+			NotEmpty().
+			Comment("令牌前缀"),
+		field.Strings("scopes").
+			Default([]string{}).
+			Comment("授权范围"),
+		field.Strings("allowed_cidrs").
+			Default([]string{}).
+			Comment("允许访问的IP段"),
+		field.Time("expires_at").
+			Comment("过期时间"),
+		field.Time("revoked_at").
+			Optional().
+			Nillable().
+			Comment("吊销时间"),
+		field.Time("last_used_at").
+			Optional().
+			Nillable().
+			Comment("最后使用时间"),
+		field.String("last_used_ip").
+			Optional().
+			Comment("最后使用IP"),
+		field.Int64("usage_count").
+			Default(0).
+			Comment("使用次数"),
+	}
+}
+
+// Edges of the ServiceAccount.
+func (ServiceAccount) Edges() []ent.Edge {
+	return nil
+}
+
+// Mixin of the ServiceAccount schema.
+func (ServiceAccount) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
+
+// Indexes of the ServiceAccount.
+func (ServiceAccount) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("token_hash"),
+		index.Fields("expires_at"),
+	}
+}
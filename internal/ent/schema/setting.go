@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// Setting holds the schema definition for the Setting entity.
+type Setting struct {
+	ent.Schema
+}
+
+// Fields of the Setting.
+func (Setting) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("key").
+			Unique().
+			NotEmpty().
+			Comment("配置键，功能开关以 feature. 为前缀"),
+		field.String("value").
+			SchemaType(longText).
+			Comment("配置值"),
+		field.String("description").
+			Optional().
+			Comment("描述"),
+	}
+}
+
+// Edges of the Setting.
+func (Setting) Edges() []ent.Edge {
+	return nil
+}
+
+// Mixin of the Setting schema.
+func (Setting) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
@@ -0,0 +1,9 @@
+package schema
+
+import "entgo.io/ent/dialect"
+
+// longText is the column type of strings that may exceed 255 characters, the length of the
+// VARCHAR columns ent creates on MySQL. PostgreSQL columns have no length limit.
+var longText = map[string]string{
+	dialect.MySQL: "text",
+}
@@ -1,10 +1,22 @@
 package schema
 
 import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
-	"github.com/google/uuid"
+
+	gen "github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/hook"
+	"github.com/hewenyu/gin-pkg/pkg/fieldcrypt"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
 )
 
 // User holds the schema definition for the User entity.
@@ -19,13 +31,15 @@ func (User) Fields() []ent.Field {
 			Immutable().
 			Unique().
 			NotEmpty().
-			DefaultFunc(func() string {
-				return uuid.New().String()
-			}).Comment("主键"),
+			DefaultFunc(idgen.New).Comment("主键"),
 		field.String("email").
 			Unique().
 			NotEmpty().
-			Comment("邮箱"),
+			SchemaType(map[string]string{
+				// citext 使唯一约束与查询不区分大小写
+				dialect.Postgres: "citext",
+			}).
+			Comment("邮箱，保存为小写"),
 		field.String("username").
 			Unique().
 			NotEmpty().
@@ -34,31 +48,108 @@ func (User) Fields() []ent.Field {
 			NotEmpty().
 			Sensitive().
 			Comment("密码"),
-		field.String("role").
-			Default("user").
-			Comment("角色"),
+		field.Strings("password_history").
+			Optional().
+			Sensitive().
+			Comment("历史密码哈希，最近的在前"),
+		field.Strings("roles").
+			Default([]string{"user"}).
+			Comment("角色列表"),
 		field.Bool("active").
 			Default(true).
 			Comment("是否激活"),
+		field.Bool("must_change_password").
+			Default(false).
+			Comment("下次登录后必须修改密码，由管理员设置"),
+		field.String("phone").
+			Optional().
+			Nillable().
+			Unique().
+			Validate(validatePhone).
+			Comment("手机号（E.164 格式），用于短信验证码登录；配置加密密钥时加密存储"),
+		field.Bool("phone_verified").
+			Default(false).
+			Comment("手机号是否已通过短信验证，更换手机号后重置"),
 		field.String("avatar_url").
 			Optional().
+			SchemaType(longText).
 			Comment("头像"),
 		field.Time("last_login").
 			Optional().
 			Nillable().
 			Comment("最后登录时间"),
+		field.Time("last_seen").
+			Optional().
+			Nillable().
+			Comment("最后活跃时间，由访问记录定期写入"),
 	}
 }
 
 // Edges of the User.
 func (User) Edges() []ent.Edge {
-	return nil
+	return []ent.Edge{
+		edge.To("memberships", Membership.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("所属团队"),
+		edge.To("activities", Activity.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("活动记录"),
+		edge.To("preferences", UserPreference.Type).
+			Unique().
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("偏好设置"),
+		edge.To("refresh_tokens", RefreshToken.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("记录的刷新令牌"),
+	}
+}
+
+// Hooks of the User.
+func (User) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hook.On(
+			func(next ent.Mutator) ent.Mutator {
+				return hook.UserFunc(func(ctx context.Context, m *gen.UserMutation) (ent.Value, error) {
+					if email, ok := m.Email(); ok {
+						m.SetEmail(NormalizeEmail(email))
+					}
+					return next.Mutate(ctx, m)
+				})
+			},
+			ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne,
+		),
+	}
+}
+
+// PhonePattern matches phone numbers in E.164 format, e.g. +14155552671
+var PhonePattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// validatePhone checks that a phone number is in E.164 format. Encrypted numbers are
+// accepted, as EncryptedFieldsMixin validated them before encrypting.
+func validatePhone(phone string) error {
+	if fieldcrypt.IsEncrypted(phone) || PhonePattern.MatchString(phone) {
+		return nil
+	}
+	return fmt.Errorf("value does not match validation %q", PhonePattern)
+}
+
+// NormalizeEmail returns the form emails are stored and looked up in, so addresses
+// differing only in case belong to the same user
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 // Mixin of the User schema.
 func (User) Mixin() []ent.Mixin {
 	return []ent.Mixin{
 		TimeMixin{},
+		AuditFieldsMixin{},
+		SoftDeleteMixin{},
+		OrgScopeMixin{},
+		EncryptedFieldsMixin{
+			Searchable: []string{"phone"},
+			Validators: map[string]func(string) error{"phone": validatePhone},
+		},
 	}
 }
 
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/pkg/idgen"
+)
+
+// UserPreference holds the schema definition for the UserPreference entity, the typed
+// preferences of a user. Users without a row have the default preferences.
+type UserPreference struct {
+	ent.Schema
+}
+
+// Fields of the UserPreference.
+func (UserPreference) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(idgen.New).Comment("主键"),
+		field.String("user_id").
+			Unique().
+			Immutable().
+			Comment("用户ID"),
+		field.Bool("notify_email").
+			Default(true).
+			Comment("接收邮件通知"),
+		field.Bool("notify_sms").
+			Default(false).
+			Comment("接收短信通知"),
+		field.Bool("notify_marketing").
+			Default(false).
+			Comment("接收产品动态与营销消息"),
+		field.Enum("theme").
+			Values("system", "light", "dark").
+			Default("system").
+			Comment("界面主题"),
+		field.String("locale").
+			Optional().
+			Comment("语言区域，BCP 47 格式，为空表示跟随客户端"),
+		field.String("timezone").
+			Optional().
+			Comment("时区，IANA 名称如 Asia/Shanghai，为空表示 UTC；供客户端显示时间"),
+	}
+}
+
+// Edges of the UserPreference.
+func (UserPreference) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("preferences").
+			Field("user_id").
+			Unique().
+			Required().
+			Immutable(),
+	}
+}
+
+// Mixin of the UserPreference schema.
+func (UserPreference) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		AuditFieldsMixin{},
+	}
+}
@@ -0,0 +1,273 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/internal/ent/serviceaccount"
+)
+
+// ServiceAccount is the model entity for the ServiceAccount schema.
+type ServiceAccount struct {
+	config `json:"-"`
+	// ID of the ent.
+	// 主键
+	ID string `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// 创建人，用户ID或 service:<服务账号ID>
+	CreatedBy string `json:"created_by,omitempty"`
+	// 最后修改人
+	UpdatedBy string `json:"updated_by,omitempty"`
+	// 名称
+	Name string `json:"name,omitempty"`
+	// 描述
+	Description string `json:"description,omitempty"`
+	// 令牌哈希
+	TokenHash string `json:"-"`
+	// 令牌前缀
+	TokenPrefix string `json:"token_prefix,omitempty"`
+	// 授权范围
+	Scopes []string `json:"scopes,omitempty"`
+	// 允许访问的IP段
+	AllowedCidrs []string `json:"allowed_cidrs,omitempty"`
+	// 过期时间
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	// 吊销时间
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// 最后使用时间
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// 最后使用IP
+	LastUsedIP string `json:"last_used_ip,omitempty"`
+	// 使用次数
+	UsageCount   int64 `json:"usage_count,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*ServiceAccount) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case serviceaccount.FieldScopes, serviceaccount.FieldAllowedCidrs:
+			values[i] = new([]byte)
+		case serviceaccount.FieldUsageCount:
+			values[i] = new(sql.NullInt64)
+		case serviceaccount.FieldID, serviceaccount.FieldCreatedBy, serviceaccount.FieldUpdatedBy, serviceaccount.FieldName, serviceaccount.FieldDescription, serviceaccount.FieldTokenHash, serviceaccount.FieldTokenPrefix, serviceaccount.FieldLastUsedIP:
+			values[i] = new(sql.NullString)
+		case serviceaccount.FieldCreatedAt, serviceaccount.FieldUpdatedAt, serviceaccount.FieldExpiresAt, serviceaccount.FieldRevokedAt, serviceaccount.FieldLastUsedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the ServiceAccount fields.
+func (sa *ServiceAccount) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case serviceaccount.FieldID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value.Valid {
+				sa.ID = value.String
+			}
+		case serviceaccount.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				sa.CreatedAt = value.Time
+			}
+		case serviceaccount.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				sa.UpdatedAt = value.Time
+			}
+		case serviceaccount.FieldCreatedBy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field created_by", values[i])
+			} else if value.Valid {
+				sa.CreatedBy = value.String
+			}
+		case serviceaccount.FieldUpdatedBy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_by", values[i])
+			} else if value.Valid {
+				sa.UpdatedBy = value.String
+			}
+		case serviceaccount.FieldName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field name", values[i])
+			} else if value.Valid {
+				sa.Name = value.String
+			}
+		case serviceaccount.FieldDescription:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field description", values[i])
+			} else if value.Valid {
+				sa.Description = value.String
+			}
+		case serviceaccount.FieldTokenHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field token_hash", values[i])
+			} else if value.Valid {
+				sa.TokenHash = value.String
+			}
+		case serviceaccount.FieldTokenPrefix:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field token_prefix", values[i])
+			} else if value.Valid {
+				sa.TokenPrefix = value.String
+			}
+		case serviceaccount.FieldScopes:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field scopes", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &sa.Scopes); err != nil {
+					return fmt.Errorf("unmarshal field scopes: %w", err)
+				}
+			}
+		case serviceaccount.FieldAllowedCidrs:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field allowed_cidrs", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &sa.AllowedCidrs); err != nil {
+					return fmt.Errorf("unmarshal field allowed_cidrs: %w", err)
+				}
+			}
+		case serviceaccount.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				sa.ExpiresAt = value.Time
+			}
+		case serviceaccount.FieldRevokedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field revoked_at", values[i])
+			} else if value.Valid {
+				sa.RevokedAt = new(time.Time)
+				*sa.RevokedAt = value.Time
+			}
+		case serviceaccount.FieldLastUsedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field last_used_at", values[i])
+			} else if value.Valid {
+				sa.LastUsedAt = new(time.Time)
+				*sa.LastUsedAt = value.Time
+			}
+		case serviceaccount.FieldLastUsedIP:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field last_used_ip", values[i])
+			} else if value.Valid {
+				sa.LastUsedIP = value.String
+			}
+		case serviceaccount.FieldUsageCount:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field usage_count", values[i])
+			} else if value.Valid {
+				sa.UsageCount = value.Int64
+			}
+		default:
+			sa.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the ServiceAccount.
+// This includes values selected through modifiers, order, etc.
+func (sa *ServiceAccount) Value(name string) (ent.Value, error) {
+	return sa.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this ServiceAccount.
+// Note that you need to call ServiceAccount.Unwrap() before calling this method if this ServiceAccount
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (sa *ServiceAccount) Update() *ServiceAccountUpdateOne {
+	return NewServiceAccountClient(sa.config).UpdateOne(sa)
+}
+
+// Unwrap unwraps the ServiceAccount entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (sa *ServiceAccount) Unwrap() *ServiceAccount {
+	_tx, ok := sa.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: ServiceAccount is not a transactional entity")
+	}
+	sa.config.driver = _tx.drv
+	return sa
+}
+
+// String implements the fmt.Stringer.
+func (sa *ServiceAccount) String() string {
+	var builder strings.Builder
+	builder.WriteString("ServiceAccount(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", sa.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(sa.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(sa.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("created_by=")
+	builder.WriteString(sa.CreatedBy)
+	builder.WriteString(", ")
+	builder.WriteString("updated_by=")
+	builder.WriteString(sa.UpdatedBy)
+	builder.WriteString(", ")
+	builder.WriteString("name=")
+	builder.WriteString(sa.Name)
+	builder.WriteString(", ")
+	builder.WriteString("description=")
+	builder.WriteString(sa.Description)
+	builder.WriteString(", ")
+	builder.WriteString("token_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("token_prefix=")
+	builder.WriteString(sa.TokenPrefix)
+	builder.WriteString(", ")
+	builder.WriteString("scopes=")
+	builder.WriteString(fmt.Sprintf("%v", sa.Scopes))
+	builder.WriteString(", ")
+	builder.WriteString("allowed_cidrs=")
+	builder.WriteString(fmt.Sprintf("%v", sa.AllowedCidrs))
+	builder.WriteString(", ")
+	builder.WriteString("expires_at=")
+	builder.WriteString(sa.ExpiresAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := sa.RevokedAt; v != nil {
+		builder.WriteString("revoked_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := sa.LastUsedAt; v != nil {
+		builder.WriteString("last_used_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("last_used_ip=")
+	builder.WriteString(sa.LastUsedIP)
+	builder.WriteString(", ")
+	builder.WriteString("usage_count=")
+	builder.WriteString(fmt.Sprintf("%v", sa.UsageCount))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// ServiceAccounts is a parsable slice of ServiceAccount.
+type ServiceAccounts []*ServiceAccount
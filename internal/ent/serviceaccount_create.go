@@ -0,0 +1,501 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/serviceaccount"
+)
+
+// ServiceAccountCreate is the builder for creating a ServiceAccount entity.
+type ServiceAccountCreate struct {
+	config
+	mutation *ServiceAccountMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (sac *ServiceAccountCreate) SetCreatedAt(t time.Time) *ServiceAccountCreate {
+	sac.mutation.SetCreatedAt(t)
+	return sac
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableCreatedAt(t *time.Time) *ServiceAccountCreate {
+	if t != nil {
+		sac.SetCreatedAt(*t)
+	}
+	return sac
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (sac *ServiceAccountCreate) SetUpdatedAt(t time.Time) *ServiceAccountCreate {
+	sac.mutation.SetUpdatedAt(t)
+	return sac
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableUpdatedAt(t *time.Time) *ServiceAccountCreate {
+	if t != nil {
+		sac.SetUpdatedAt(*t)
+	}
+	return sac
+}
+
+// SetCreatedBy sets the "created_by" field.
+func (sac *ServiceAccountCreate) SetCreatedBy(s string) *ServiceAccountCreate {
+	sac.mutation.SetCreatedBy(s)
+	return sac
+}
+
+// SetNillableCreatedBy sets the "created_by" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableCreatedBy(s *string) *ServiceAccountCreate {
+	if s != nil {
+		sac.SetCreatedBy(*s)
+	}
+	return sac
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (sac *ServiceAccountCreate) SetUpdatedBy(s string) *ServiceAccountCreate {
+	sac.mutation.SetUpdatedBy(s)
+	return sac
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableUpdatedBy(s *string) *ServiceAccountCreate {
+	if s != nil {
+		sac.SetUpdatedBy(*s)
+	}
+	return sac
+}
+
+// SetName sets the "name" field.
+func (sac *ServiceAccountCreate) SetName(s string) *ServiceAccountCreate {
+	sac.mutation.SetName(s)
+	return sac
+}
+
+// SetDescription sets the "description" field.
+func (sac *ServiceAccountCreate) SetDescription(s string) *ServiceAccountCreate {
+	sac.mutation.SetDescription(s)
+	return sac
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableDescription(s *string) *ServiceAccountCreate {
+	if s != nil {
+		sac.SetDescription(*s)
+	}
+	return sac
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (sac *ServiceAccountCreate) SetTokenHash(s string) *ServiceAccountCreate {
+	sac.mutation.SetTokenHash(s)
+	return sac
+}
+
+// SetTokenPrefix sets the "token_prefix" field.
+func (sac *ServiceAccountCreate) SetTokenPrefix(s string) *ServiceAccountCreate {
+	sac.mutation.SetTokenPrefix(s)
+	return sac
+}
+
+// SetScopes sets the "scopes" field.
+func (sac *ServiceAccountCreate) SetScopes(s []string) *ServiceAccountCreate {
+	sac.mutation.SetScopes(s)
+	return sac
+}
+
+// SetAllowedCidrs sets the "allowed_cidrs" field.
+func (sac *ServiceAccountCreate) SetAllowedCidrs(s []string) *ServiceAccountCreate {
+	sac.mutation.SetAllowedCidrs(s)
+	return sac
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (sac *ServiceAccountCreate) SetExpiresAt(t time.Time) *ServiceAccountCreate {
+	sac.mutation.SetExpiresAt(t)
+	return sac
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (sac *ServiceAccountCreate) SetRevokedAt(t time.Time) *ServiceAccountCreate {
+	sac.mutation.SetRevokedAt(t)
+	return sac
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableRevokedAt(t *time.Time) *ServiceAccountCreate {
+	if t != nil {
+		sac.SetRevokedAt(*t)
+	}
+	return sac
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (sac *ServiceAccountCreate) SetLastUsedAt(t time.Time) *ServiceAccountCreate {
+	sac.mutation.SetLastUsedAt(t)
+	return sac
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableLastUsedAt(t *time.Time) *ServiceAccountCreate {
+	if t != nil {
+		sac.SetLastUsedAt(*t)
+	}
+	return sac
+}
+
+// SetLastUsedIP sets the "last_used_ip" field.
+func (sac *ServiceAccountCreate) SetLastUsedIP(s string) *ServiceAccountCreate {
+	sac.mutation.SetLastUsedIP(s)
+	return sac
+}
+
+// SetNillableLastUsedIP sets the "last_used_ip" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableLastUsedIP(s *string) *ServiceAccountCreate {
+	if s != nil {
+		sac.SetLastUsedIP(*s)
+	}
+	return sac
+}
+
+// SetUsageCount sets the "usage_count" field.
+func (sac *ServiceAccountCreate) SetUsageCount(i int64) *ServiceAccountCreate {
+	sac.mutation.SetUsageCount(i)
+	return sac
+}
+
+// SetNillableUsageCount sets the "usage_count" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableUsageCount(i *int64) *ServiceAccountCreate {
+	if i != nil {
+		sac.SetUsageCount(*i)
+	}
+	return sac
+}
+
+// SetID sets the "id" field.
+func (sac *ServiceAccountCreate) SetID(s string) *ServiceAccountCreate {
+	sac.mutation.SetID(s)
+	return sac
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (sac *ServiceAccountCreate) SetNillableID(s *string) *ServiceAccountCreate {
+	if s != nil {
+		sac.SetID(*s)
+	}
+	return sac
+}
+
+// Mutation returns the ServiceAccountMutation object of the builder.
+func (sac *ServiceAccountCreate) Mutation() *ServiceAccountMutation {
+	return sac.mutation
+}
+
+// Save creates the ServiceAccount in the database.
+func (sac *ServiceAccountCreate) Save(ctx context.Context) (*ServiceAccount, error) {
+	if err := sac.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, sac.sqlSave, sac.mutation, sac.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (sac *ServiceAccountCreate) SaveX(ctx context.Context) *ServiceAccount {
+	v, err := sac.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (sac *ServiceAccountCreate) Exec(ctx context.Context) error {
+	_, err := sac.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sac *ServiceAccountCreate) ExecX(ctx context.Context) {
+	if err := sac.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (sac *ServiceAccountCreate) defaults() error {
+	if _, ok := sac.mutation.CreatedAt(); !ok {
+		if serviceaccount.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized serviceaccount.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
+		v := serviceaccount.DefaultCreatedAt()
+		sac.mutation.SetCreatedAt(v)
+	}
+	if _, ok := sac.mutation.UpdatedAt(); !ok {
+		if serviceaccount.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized serviceaccount.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := serviceaccount.DefaultUpdatedAt()
+		sac.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := sac.mutation.Scopes(); !ok {
+		v := serviceaccount.DefaultScopes
+		sac.mutation.SetScopes(v)
+	}
+	if _, ok := sac.mutation.AllowedCidrs(); !ok {
+		v := serviceaccount.DefaultAllowedCidrs
+		sac.mutation.SetAllowedCidrs(v)
+	}
+	if _, ok := sac.mutation.UsageCount(); !ok {
+		v := serviceaccount.DefaultUsageCount
+		sac.mutation.SetUsageCount(v)
+	}
+	if _, ok := sac.mutation.ID(); !ok {
+		if serviceaccount.DefaultID == nil {
+			return fmt.Errorf("ent: uninitialized serviceaccount.DefaultID (forgotten import ent/runtime?)")
+		}
+		v := serviceaccount.DefaultID()
+		sac.mutation.SetID(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (sac *ServiceAccountCreate) check() error {
+	if _, ok := sac.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ServiceAccount.created_at"`)}
+	}
+	if _, ok := sac.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "ServiceAccount.updated_at"`)}
+	}
+	if _, ok := sac.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "ServiceAccount.name"`)}
+	}
+	if v, ok := sac.mutation.Name(); ok {
+		if err := serviceaccount.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.name": %w`, err)}
+		}
+	}
+	if _, ok := sac.mutation.TokenHash(); !ok {
+		return &ValidationError{Name: "token_hash", err: errors.New(`ent: missing required field "ServiceAccount.token_hash"`)}
+	}
+	if v, ok := sac.mutation.TokenHash(); ok {
+		if err := serviceaccount.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.token_hash": %w`, err)}
+		}
+	}
+	if _, ok := sac.mutation.TokenPrefix(); !ok {
+		return &ValidationError{Name: "token_prefix", err: errors.New(`ent: missing required field "ServiceAccount.token_prefix"`)}
+	}
+	if v, ok := sac.mutation.TokenPrefix(); ok {
+		if err := serviceaccount.TokenPrefixValidator(v); err != nil {
+			return &ValidationError{Name: "token_prefix", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.token_prefix": %w`, err)}
+		}
+	}
+	if _, ok := sac.mutation.Scopes(); !ok {
+		return &ValidationError{Name: "scopes", err: errors.New(`ent: missing required field "ServiceAccount.scopes"`)}
+	}
+	if _, ok := sac.mutation.AllowedCidrs(); !ok {
+		return &ValidationError{Name: "allowed_cidrs", err: errors.New(`ent: missing required field "ServiceAccount.allowed_cidrs"`)}
+	}
+	if _, ok := sac.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "ServiceAccount.expires_at"`)}
+	}
+	if _, ok := sac.mutation.UsageCount(); !ok {
+		return &ValidationError{Name: "usage_count", err: errors.New(`ent: missing required field "ServiceAccount.usage_count"`)}
+	}
+	if v, ok := sac.mutation.ID(); ok {
+		if err := serviceaccount.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (sac *ServiceAccountCreate) sqlSave(ctx context.Context) (*ServiceAccount, error) {
+	if err := sac.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := sac.createSpec()
+	if err := sqlgraph.CreateNode(ctx, sac.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected ServiceAccount.ID type: %T", _spec.ID.Value)
+		}
+	}
+	sac.mutation.id = &_node.ID
+	sac.mutation.done = true
+	return _node, nil
+}
+
+func (sac *ServiceAccountCreate) createSpec() (*ServiceAccount, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ServiceAccount{config: sac.config}
+		_spec = sqlgraph.NewCreateSpec(serviceaccount.Table, sqlgraph.NewFieldSpec(serviceaccount.FieldID, field.TypeString))
+	)
+	if id, ok := sac.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := sac.mutation.CreatedAt(); ok {
+		_spec.SetField(serviceaccount.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := sac.mutation.UpdatedAt(); ok {
+		_spec.SetField(serviceaccount.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := sac.mutation.CreatedBy(); ok {
+		_spec.SetField(serviceaccount.FieldCreatedBy, field.TypeString, value)
+		_node.CreatedBy = value
+	}
+	if value, ok := sac.mutation.UpdatedBy(); ok {
+		_spec.SetField(serviceaccount.FieldUpdatedBy, field.TypeString, value)
+		_node.UpdatedBy = value
+	}
+	if value, ok := sac.mutation.Name(); ok {
+		_spec.SetField(serviceaccount.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := sac.mutation.Description(); ok {
+		_spec.SetField(serviceaccount.FieldDescription, field.TypeString, value)
+		_node.Description = value
+	}
+	if value, ok := sac.mutation.TokenHash(); ok {
+		_spec.SetField(serviceaccount.FieldTokenHash, field.TypeString, value)
+		_node.TokenHash = value
+	}
+	if value, ok := sac.mutation.TokenPrefix(); ok {
+		_spec.SetField(serviceaccount.FieldTokenPrefix, field.TypeString, value)
+		_node.TokenPrefix = value
+	}
+	if value, ok := sac.mutation.Scopes(); ok {
+		_spec.SetField(serviceaccount.FieldScopes, field.TypeJSON, value)
+		_node.Scopes = value
+	}
+	if value, ok := sac.mutation.AllowedCidrs(); ok {
+		_spec.SetField(serviceaccount.FieldAllowedCidrs, field.TypeJSON, value)
+		_node.AllowedCidrs = value
+	}
+	if value, ok := sac.mutation.ExpiresAt(); ok {
+		_spec.SetField(serviceaccount.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	if value, ok := sac.mutation.RevokedAt(); ok {
+		_spec.SetField(serviceaccount.FieldRevokedAt, field.TypeTime, value)
+		_node.RevokedAt = &value
+	}
+	if value, ok := sac.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceaccount.FieldLastUsedAt, field.TypeTime, value)
+		_node.LastUsedAt = &value
+	}
+	if value, ok := sac.mutation.LastUsedIP(); ok {
+		_spec.SetField(serviceaccount.FieldLastUsedIP, field.TypeString, value)
+		_node.LastUsedIP = value
+	}
+	if value, ok := sac.mutation.UsageCount(); ok {
+		_spec.SetField(serviceaccount.FieldUsageCount, field.TypeInt64, value)
+		_node.UsageCount = value
+	}
+	return _node, _spec
+}
+
+// ServiceAccountCreateBulk is the builder for creating many ServiceAccount entities in bulk.
+type ServiceAccountCreateBulk struct {
+	config
+	err      error
+	builders []*ServiceAccountCreate
+}
+
+// Save creates the ServiceAccount entities in the database.
+func (sacb *ServiceAccountCreateBulk) Save(ctx context.Context) ([]*ServiceAccount, error) {
+	if sacb.err != nil {
+		return nil, sacb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(sacb.builders))
+	nodes := make([]*ServiceAccount, len(sacb.builders))
+	mutators := make([]Mutator, len(sacb.builders))
+	for i := range sacb.builders {
+		func(i int, root context.Context) {
+			builder := sacb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ServiceAccountMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, sacb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, sacb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, sacb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (sacb *ServiceAccountCreateBulk) SaveX(ctx context.Context) []*ServiceAccount {
+	v, err := sacb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (sacb *ServiceAccountCreateBulk) Exec(ctx context.Context) error {
+	_, err := sacb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sacb *ServiceAccountCreateBulk) ExecX(ctx context.Context) {
+	if err := sacb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
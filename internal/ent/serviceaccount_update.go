@@ -0,0 +1,823 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+	"github.com/hewenyu/gin-pkg/internal/ent/serviceaccount"
+)
+
+// ServiceAccountUpdate is the builder for updating ServiceAccount entities.
+type ServiceAccountUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *ServiceAccountMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the ServiceAccountUpdate builder.
+func (sau *ServiceAccountUpdate) Where(ps ...predicate.ServiceAccount) *ServiceAccountUpdate {
+	sau.mutation.Where(ps...)
+	return sau
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (sau *ServiceAccountUpdate) SetUpdatedAt(t time.Time) *ServiceAccountUpdate {
+	sau.mutation.SetUpdatedAt(t)
+	return sau
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (sau *ServiceAccountUpdate) SetUpdatedBy(s string) *ServiceAccountUpdate {
+	sau.mutation.SetUpdatedBy(s)
+	return sau
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableUpdatedBy(s *string) *ServiceAccountUpdate {
+	if s != nil {
+		sau.SetUpdatedBy(*s)
+	}
+	return sau
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (sau *ServiceAccountUpdate) ClearUpdatedBy() *ServiceAccountUpdate {
+	sau.mutation.ClearUpdatedBy()
+	return sau
+}
+
+// SetName sets the "name" field.
+func (sau *ServiceAccountUpdate) SetName(s string) *ServiceAccountUpdate {
+	sau.mutation.SetName(s)
+	return sau
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableName(s *string) *ServiceAccountUpdate {
+	if s != nil {
+		sau.SetName(*s)
+	}
+	return sau
+}
+
+// SetDescription sets the "description" field.
+func (sau *ServiceAccountUpdate) SetDescription(s string) *ServiceAccountUpdate {
+	sau.mutation.SetDescription(s)
+	return sau
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableDescription(s *string) *ServiceAccountUpdate {
+	if s != nil {
+		sau.SetDescription(*s)
+	}
+	return sau
+}
+
+// ClearDescription clears the value of the "description" field.
+func (sau *ServiceAccountUpdate) ClearDescription() *ServiceAccountUpdate {
+	sau.mutation.ClearDescription()
+	return sau
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (sau *ServiceAccountUpdate) SetTokenHash(s string) *ServiceAccountUpdate {
+	sau.mutation.SetTokenHash(s)
+	return sau
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableTokenHash(s *string) *ServiceAccountUpdate {
+	if s != nil {
+		sau.SetTokenHash(*s)
+	}
+	return sau
+}
+
+// SetTokenPrefix sets the "token_prefix" field.
+func (sau *ServiceAccountUpdate) SetTokenPrefix(s string) *ServiceAccountUpdate {
+	sau.mutation.SetTokenPrefix(s)
+	return sau
+}
+
+// SetNillableTokenPrefix sets the "token_prefix" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableTokenPrefix(s *string) *ServiceAccountUpdate {
+	if s != nil {
+		sau.SetTokenPrefix(*s)
+	}
+	return sau
+}
+
+// SetScopes sets the "scopes" field.
+func (sau *ServiceAccountUpdate) SetScopes(s []string) *ServiceAccountUpdate {
+	sau.mutation.SetScopes(s)
+	return sau
+}
+
+// AppendScopes appends s to the "scopes" field.
+func (sau *ServiceAccountUpdate) AppendScopes(s []string) *ServiceAccountUpdate {
+	sau.mutation.AppendScopes(s)
+	return sau
+}
+
+// SetAllowedCidrs sets the "allowed_cidrs" field.
+func (sau *ServiceAccountUpdate) SetAllowedCidrs(s []string) *ServiceAccountUpdate {
+	sau.mutation.SetAllowedCidrs(s)
+	return sau
+}
+
+// AppendAllowedCidrs appends s to the "allowed_cidrs" field.
+func (sau *ServiceAccountUpdate) AppendAllowedCidrs(s []string) *ServiceAccountUpdate {
+	sau.mutation.AppendAllowedCidrs(s)
+	return sau
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (sau *ServiceAccountUpdate) SetExpiresAt(t time.Time) *ServiceAccountUpdate {
+	sau.mutation.SetExpiresAt(t)
+	return sau
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableExpiresAt(t *time.Time) *ServiceAccountUpdate {
+	if t != nil {
+		sau.SetExpiresAt(*t)
+	}
+	return sau
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (sau *ServiceAccountUpdate) SetRevokedAt(t time.Time) *ServiceAccountUpdate {
+	sau.mutation.SetRevokedAt(t)
+	return sau
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableRevokedAt(t *time.Time) *ServiceAccountUpdate {
+	if t != nil {
+		sau.SetRevokedAt(*t)
+	}
+	return sau
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (sau *ServiceAccountUpdate) ClearRevokedAt() *ServiceAccountUpdate {
+	sau.mutation.ClearRevokedAt()
+	return sau
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (sau *ServiceAccountUpdate) SetLastUsedAt(t time.Time) *ServiceAccountUpdate {
+	sau.mutation.SetLastUsedAt(t)
+	return sau
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableLastUsedAt(t *time.Time) *ServiceAccountUpdate {
+	if t != nil {
+		sau.SetLastUsedAt(*t)
+	}
+	return sau
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (sau *ServiceAccountUpdate) ClearLastUsedAt() *ServiceAccountUpdate {
+	sau.mutation.ClearLastUsedAt()
+	return sau
+}
+
+// SetLastUsedIP sets the "last_used_ip" field.
+func (sau *ServiceAccountUpdate) SetLastUsedIP(s string) *ServiceAccountUpdate {
+	sau.mutation.SetLastUsedIP(s)
+	return sau
+}
+
+// SetNillableLastUsedIP sets the "last_used_ip" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableLastUsedIP(s *string) *ServiceAccountUpdate {
+	if s != nil {
+		sau.SetLastUsedIP(*s)
+	}
+	return sau
+}
+
+// ClearLastUsedIP clears the value of the "last_used_ip" field.
+func (sau *ServiceAccountUpdate) ClearLastUsedIP() *ServiceAccountUpdate {
+	sau.mutation.ClearLastUsedIP()
+	return sau
+}
+
+// SetUsageCount sets the "usage_count" field.
+func (sau *ServiceAccountUpdate) SetUsageCount(i int64) *ServiceAccountUpdate {
+	sau.mutation.ResetUsageCount()
+	sau.mutation.SetUsageCount(i)
+	return sau
+}
+
+// SetNillableUsageCount sets the "usage_count" field if the given value is not nil.
+func (sau *ServiceAccountUpdate) SetNillableUsageCount(i *int64) *ServiceAccountUpdate {
+	if i != nil {
+		sau.SetUsageCount(*i)
+	}
+	return sau
+}
+
+// AddUsageCount adds i to the "usage_count" field.
+func (sau *ServiceAccountUpdate) AddUsageCount(i int64) *ServiceAccountUpdate {
+	sau.mutation.AddUsageCount(i)
+	return sau
+}
+
+// Mutation returns the ServiceAccountMutation object of the builder.
+func (sau *ServiceAccountUpdate) Mutation() *ServiceAccountMutation {
+	return sau.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (sau *ServiceAccountUpdate) Save(ctx context.Context) (int, error) {
+	if err := sau.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks(ctx, sau.sqlSave, sau.mutation, sau.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (sau *ServiceAccountUpdate) SaveX(ctx context.Context) int {
+	affected, err := sau.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (sau *ServiceAccountUpdate) Exec(ctx context.Context) error {
+	_, err := sau.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sau *ServiceAccountUpdate) ExecX(ctx context.Context) {
+	if err := sau.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (sau *ServiceAccountUpdate) defaults() error {
+	if _, ok := sau.mutation.UpdatedAt(); !ok {
+		if serviceaccount.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized serviceaccount.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := serviceaccount.UpdateDefaultUpdatedAt()
+		sau.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (sau *ServiceAccountUpdate) check() error {
+	if v, ok := sau.mutation.Name(); ok {
+		if err := serviceaccount.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.name": %w`, err)}
+		}
+	}
+	if v, ok := sau.mutation.TokenHash(); ok {
+		if err := serviceaccount.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := sau.mutation.TokenPrefix(); ok {
+		if err := serviceaccount.TokenPrefixValidator(v); err != nil {
+			return &ValidationError{Name: "token_prefix", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.token_prefix": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (sau *ServiceAccountUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ServiceAccountUpdate {
+	sau.modifiers = append(sau.modifiers, modifiers...)
+	return sau
+}
+
+func (sau *ServiceAccountUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := sau.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(serviceaccount.Table, serviceaccount.Columns, sqlgraph.NewFieldSpec(serviceaccount.FieldID, field.TypeString))
+	if ps := sau.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := sau.mutation.UpdatedAt(); ok {
+		_spec.SetField(serviceaccount.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if sau.mutation.CreatedByCleared() {
+		_spec.ClearField(serviceaccount.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := sau.mutation.UpdatedBy(); ok {
+		_spec.SetField(serviceaccount.FieldUpdatedBy, field.TypeString, value)
+	}
+	if sau.mutation.UpdatedByCleared() {
+		_spec.ClearField(serviceaccount.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := sau.mutation.Name(); ok {
+		_spec.SetField(serviceaccount.FieldName, field.TypeString, value)
+	}
+	if value, ok := sau.mutation.Description(); ok {
+		_spec.SetField(serviceaccount.FieldDescription, field.TypeString, value)
+	}
+	if sau.mutation.DescriptionCleared() {
+		_spec.ClearField(serviceaccount.FieldDescription, field.TypeString)
+	}
+	if value, ok := sau.mutation.TokenHash(); ok {
+		_spec.SetField(serviceaccount.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := sau.mutation.TokenPrefix(); ok {
+		_spec.SetField(serviceaccount.FieldTokenPrefix, field.TypeString, value)
+	}
+	if value, ok := sau.mutation.Scopes(); ok {
+		_spec.SetField(serviceaccount.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := sau.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, serviceaccount.FieldScopes, value)
+		})
+	}
+	if value, ok := sau.mutation.AllowedCidrs(); ok {
+		_spec.SetField(serviceaccount.FieldAllowedCidrs, field.TypeJSON, value)
+	}
+	if value, ok := sau.mutation.AppendedAllowedCidrs(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, serviceaccount.FieldAllowedCidrs, value)
+		})
+	}
+	if value, ok := sau.mutation.ExpiresAt(); ok {
+		_spec.SetField(serviceaccount.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := sau.mutation.RevokedAt(); ok {
+		_spec.SetField(serviceaccount.FieldRevokedAt, field.TypeTime, value)
+	}
+	if sau.mutation.RevokedAtCleared() {
+		_spec.ClearField(serviceaccount.FieldRevokedAt, field.TypeTime)
+	}
+	if value, ok := sau.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceaccount.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if sau.mutation.LastUsedAtCleared() {
+		_spec.ClearField(serviceaccount.FieldLastUsedAt, field.TypeTime)
+	}
+	if value, ok := sau.mutation.LastUsedIP(); ok {
+		_spec.SetField(serviceaccount.FieldLastUsedIP, field.TypeString, value)
+	}
+	if sau.mutation.LastUsedIPCleared() {
+		_spec.ClearField(serviceaccount.FieldLastUsedIP, field.TypeString)
+	}
+	if value, ok := sau.mutation.UsageCount(); ok {
+		_spec.SetField(serviceaccount.FieldUsageCount, field.TypeInt64, value)
+	}
+	if value, ok := sau.mutation.AddedUsageCount(); ok {
+		_spec.AddField(serviceaccount.FieldUsageCount, field.TypeInt64, value)
+	}
+	_spec.AddModifiers(sau.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, sau.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{serviceaccount.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	sau.mutation.done = true
+	return n, nil
+}
+
+// ServiceAccountUpdateOne is the builder for updating a single ServiceAccount entity.
+type ServiceAccountUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *ServiceAccountMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (sauo *ServiceAccountUpdateOne) SetUpdatedAt(t time.Time) *ServiceAccountUpdateOne {
+	sauo.mutation.SetUpdatedAt(t)
+	return sauo
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (sauo *ServiceAccountUpdateOne) SetUpdatedBy(s string) *ServiceAccountUpdateOne {
+	sauo.mutation.SetUpdatedBy(s)
+	return sauo
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableUpdatedBy(s *string) *ServiceAccountUpdateOne {
+	if s != nil {
+		sauo.SetUpdatedBy(*s)
+	}
+	return sauo
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (sauo *ServiceAccountUpdateOne) ClearUpdatedBy() *ServiceAccountUpdateOne {
+	sauo.mutation.ClearUpdatedBy()
+	return sauo
+}
+
+// SetName sets the "name" field.
+func (sauo *ServiceAccountUpdateOne) SetName(s string) *ServiceAccountUpdateOne {
+	sauo.mutation.SetName(s)
+	return sauo
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableName(s *string) *ServiceAccountUpdateOne {
+	if s != nil {
+		sauo.SetName(*s)
+	}
+	return sauo
+}
+
+// SetDescription sets the "description" field.
+func (sauo *ServiceAccountUpdateOne) SetDescription(s string) *ServiceAccountUpdateOne {
+	sauo.mutation.SetDescription(s)
+	return sauo
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableDescription(s *string) *ServiceAccountUpdateOne {
+	if s != nil {
+		sauo.SetDescription(*s)
+	}
+	return sauo
+}
+
+// ClearDescription clears the value of the "description" field.
+func (sauo *ServiceAccountUpdateOne) ClearDescription() *ServiceAccountUpdateOne {
+	sauo.mutation.ClearDescription()
+	return sauo
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (sauo *ServiceAccountUpdateOne) SetTokenHash(s string) *ServiceAccountUpdateOne {
+	sauo.mutation.SetTokenHash(s)
+	return sauo
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableTokenHash(s *string) *ServiceAccountUpdateOne {
+	if s != nil {
+		sauo.SetTokenHash(*s)
+	}
+	return sauo
+}
+
+// SetTokenPrefix sets the "token_prefix" field.
+func (sauo *ServiceAccountUpdateOne) SetTokenPrefix(s string) *ServiceAccountUpdateOne {
+	sauo.mutation.SetTokenPrefix(s)
+	return sauo
+}
+
+// SetNillableTokenPrefix sets the "token_prefix" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableTokenPrefix(s *string) *ServiceAccountUpdateOne {
+	if s != nil {
+		sauo.SetTokenPrefix(*s)
+	}
+	return sauo
+}
+
+// SetScopes sets the "scopes" field.
+func (sauo *ServiceAccountUpdateOne) SetScopes(s []string) *ServiceAccountUpdateOne {
+	sauo.mutation.SetScopes(s)
+	return sauo
+}
+
+// AppendScopes appends s to the "scopes" field.
+func (sauo *ServiceAccountUpdateOne) AppendScopes(s []string) *ServiceAccountUpdateOne {
+	sauo.mutation.AppendScopes(s)
+	return sauo
+}
+
+// SetAllowedCidrs sets the "allowed_cidrs" field.
+func (sauo *ServiceAccountUpdateOne) SetAllowedCidrs(s []string) *ServiceAccountUpdateOne {
+	sauo.mutation.SetAllowedCidrs(s)
+	return sauo
+}
+
+// AppendAllowedCidrs appends s to the "allowed_cidrs" field.
+func (sauo *ServiceAccountUpdateOne) AppendAllowedCidrs(s []string) *ServiceAccountUpdateOne {
+	sauo.mutation.AppendAllowedCidrs(s)
+	return sauo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (sauo *ServiceAccountUpdateOne) SetExpiresAt(t time.Time) *ServiceAccountUpdateOne {
+	sauo.mutation.SetExpiresAt(t)
+	return sauo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableExpiresAt(t *time.Time) *ServiceAccountUpdateOne {
+	if t != nil {
+		sauo.SetExpiresAt(*t)
+	}
+	return sauo
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (sauo *ServiceAccountUpdateOne) SetRevokedAt(t time.Time) *ServiceAccountUpdateOne {
+	sauo.mutation.SetRevokedAt(t)
+	return sauo
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableRevokedAt(t *time.Time) *ServiceAccountUpdateOne {
+	if t != nil {
+		sauo.SetRevokedAt(*t)
+	}
+	return sauo
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (sauo *ServiceAccountUpdateOne) ClearRevokedAt() *ServiceAccountUpdateOne {
+	sauo.mutation.ClearRevokedAt()
+	return sauo
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (sauo *ServiceAccountUpdateOne) SetLastUsedAt(t time.Time) *ServiceAccountUpdateOne {
+	sauo.mutation.SetLastUsedAt(t)
+	return sauo
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableLastUsedAt(t *time.Time) *ServiceAccountUpdateOne {
+	if t != nil {
+		sauo.SetLastUsedAt(*t)
+	}
+	return sauo
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (sauo *ServiceAccountUpdateOne) ClearLastUsedAt() *ServiceAccountUpdateOne {
+	sauo.mutation.ClearLastUsedAt()
+	return sauo
+}
+
+// SetLastUsedIP sets the "last_used_ip" field.
+func (sauo *ServiceAccountUpdateOne) SetLastUsedIP(s string) *ServiceAccountUpdateOne {
+	sauo.mutation.SetLastUsedIP(s)
+	return sauo
+}
+
+// SetNillableLastUsedIP sets the "last_used_ip" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableLastUsedIP(s *string) *ServiceAccountUpdateOne {
+	if s != nil {
+		sauo.SetLastUsedIP(*s)
+	}
+	return sauo
+}
+
+// ClearLastUsedIP clears the value of the "last_used_ip" field.
+func (sauo *ServiceAccountUpdateOne) ClearLastUsedIP() *ServiceAccountUpdateOne {
+	sauo.mutation.ClearLastUsedIP()
+	return sauo
+}
+
+// SetUsageCount sets the "usage_count" field.
+func (sauo *ServiceAccountUpdateOne) SetUsageCount(i int64) *ServiceAccountUpdateOne {
+	sauo.mutation.ResetUsageCount()
+	sauo.mutation.SetUsageCount(i)
+	return sauo
+}
+
+// SetNillableUsageCount sets the "usage_count" field if the given value is not nil.
+func (sauo *ServiceAccountUpdateOne) SetNillableUsageCount(i *int64) *ServiceAccountUpdateOne {
+	if i != nil {
+		sauo.SetUsageCount(*i)
+	}
+	return sauo
+}
+
+// AddUsageCount adds i to the "usage_count" field.
+func (sauo *ServiceAccountUpdateOne) AddUsageCount(i int64) *ServiceAccountUpdateOne {
+	sauo.mutation.AddUsageCount(i)
+	return sauo
+}
+
+// Mutation returns the ServiceAccountMutation object of the builder.
+func (sauo *ServiceAccountUpdateOne) Mutation() *ServiceAccountMutation {
+	return sauo.mutation
+}
+
+// Where appends a list predicates to the ServiceAccountUpdate builder.
+func (sauo *ServiceAccountUpdateOne) Where(ps ...predicate.ServiceAccount) *ServiceAccountUpdateOne {
+	sauo.mutation.Where(ps...)
+	return sauo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (sauo *ServiceAccountUpdateOne) Select(field string, fields ...string) *ServiceAccountUpdateOne {
+	sauo.fields = append([]string{field}, fields...)
+	return sauo
+}
+
+// Save executes the query and returns the updated ServiceAccount entity.
+func (sauo *ServiceAccountUpdateOne) Save(ctx context.Context) (*ServiceAccount, error) {
+	if err := sauo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, sauo.sqlSave, sauo.mutation, sauo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (sauo *ServiceAccountUpdateOne) SaveX(ctx context.Context) *ServiceAccount {
+	node, err := sauo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (sauo *ServiceAccountUpdateOne) Exec(ctx context.Context) error {
+	_, err := sauo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (sauo *ServiceAccountUpdateOne) ExecX(ctx context.Context) {
+	if err := sauo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (sauo *ServiceAccountUpdateOne) defaults() error {
+	if _, ok := sauo.mutation.UpdatedAt(); !ok {
+		if serviceaccount.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized serviceaccount.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := serviceaccount.UpdateDefaultUpdatedAt()
+		sauo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (sauo *ServiceAccountUpdateOne) check() error {
+	if v, ok := sauo.mutation.Name(); ok {
+		if err := serviceaccount.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.name": %w`, err)}
+		}
+	}
+	if v, ok := sauo.mutation.TokenHash(); ok {
+		if err := serviceaccount.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := sauo.mutation.TokenPrefix(); ok {
+		if err := serviceaccount.TokenPrefixValidator(v); err != nil {
+			return &ValidationError{Name: "token_prefix", err: fmt.Errorf(`ent: validator failed for field "ServiceAccount.token_prefix": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (sauo *ServiceAccountUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ServiceAccountUpdateOne {
+	sauo.modifiers = append(sauo.modifiers, modifiers...)
+	return sauo
+}
+
+func (sauo *ServiceAccountUpdateOne) sqlSave(ctx context.Context) (_node *ServiceAccount, err error) {
+	if err := sauo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(serviceaccount.Table, serviceaccount.Columns, sqlgraph.NewFieldSpec(serviceaccount.FieldID, field.TypeString))
+	id, ok := sauo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ServiceAccount.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := sauo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, serviceaccount.FieldID)
+		for _, f := range fields {
+			if !serviceaccount.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != serviceaccount.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := sauo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := sauo.mutation.UpdatedAt(); ok {
+		_spec.SetField(serviceaccount.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if sauo.mutation.CreatedByCleared() {
+		_spec.ClearField(serviceaccount.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := sauo.mutation.UpdatedBy(); ok {
+		_spec.SetField(serviceaccount.FieldUpdatedBy, field.TypeString, value)
+	}
+	if sauo.mutation.UpdatedByCleared() {
+		_spec.ClearField(serviceaccount.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := sauo.mutation.Name(); ok {
+		_spec.SetField(serviceaccount.FieldName, field.TypeString, value)
+	}
+	if value, ok := sauo.mutation.Description(); ok {
+		_spec.SetField(serviceaccount.FieldDescription, field.TypeString, value)
+	}
+	if sauo.mutation.DescriptionCleared() {
+		_spec.ClearField(serviceaccount.FieldDescription, field.TypeString)
+	}
+	if value, ok := sauo.mutation.TokenHash(); ok {
+		_spec.SetField(serviceaccount.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := sauo.mutation.TokenPrefix(); ok {
+		_spec.SetField(serviceaccount.FieldTokenPrefix, field.TypeString, value)
+	}
+	if value, ok := sauo.mutation.Scopes(); ok {
+		_spec.SetField(serviceaccount.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := sauo.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, serviceaccount.FieldScopes, value)
+		})
+	}
+	if value, ok := sauo.mutation.AllowedCidrs(); ok {
+		_spec.SetField(serviceaccount.FieldAllowedCidrs, field.TypeJSON, value)
+	}
+	if value, ok := sauo.mutation.AppendedAllowedCidrs(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, serviceaccount.FieldAllowedCidrs, value)
+		})
+	}
+	if value, ok := sauo.mutation.ExpiresAt(); ok {
+		_spec.SetField(serviceaccount.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := sauo.mutation.RevokedAt(); ok {
+		_spec.SetField(serviceaccount.FieldRevokedAt, field.TypeTime, value)
+	}
+	if sauo.mutation.RevokedAtCleared() {
+		_spec.ClearField(serviceaccount.FieldRevokedAt, field.TypeTime)
+	}
+	if value, ok := sauo.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceaccount.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if sauo.mutation.LastUsedAtCleared() {
+		_spec.ClearField(serviceaccount.FieldLastUsedAt, field.TypeTime)
+	}
+	if value, ok := sauo.mutation.LastUsedIP(); ok {
+		_spec.SetField(serviceaccount.FieldLastUsedIP, field.TypeString, value)
+	}
+	if sauo.mutation.LastUsedIPCleared() {
+		_spec.ClearField(serviceaccount.FieldLastUsedIP, field.TypeString)
+	}
+	if value, ok := sauo.mutation.UsageCount(); ok {
+		_spec.SetField(serviceaccount.FieldUsageCount, field.TypeInt64, value)
+	}
+	if value, ok := sauo.mutation.AddedUsageCount(); ok {
+		_spec.AddField(serviceaccount.FieldUsageCount, field.TypeInt64, value)
+	}
+	_spec.AddModifiers(sauo.modifiers...)
+	_node = &ServiceAccount{config: sauo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, sauo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{serviceaccount.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	sauo.mutation.done = true
+	return _node, nil
+}
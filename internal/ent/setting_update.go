@@ -0,0 +1,444 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+	"github.com/hewenyu/gin-pkg/internal/ent/setting"
+)
+
+// SettingUpdate is the builder for updating Setting entities.
+type SettingUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *SettingMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the SettingUpdate builder.
+func (su *SettingUpdate) Where(ps ...predicate.Setting) *SettingUpdate {
+	su.mutation.Where(ps...)
+	return su
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (su *SettingUpdate) SetUpdatedAt(t time.Time) *SettingUpdate {
+	su.mutation.SetUpdatedAt(t)
+	return su
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (su *SettingUpdate) SetUpdatedBy(s string) *SettingUpdate {
+	su.mutation.SetUpdatedBy(s)
+	return su
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (su *SettingUpdate) SetNillableUpdatedBy(s *string) *SettingUpdate {
+	if s != nil {
+		su.SetUpdatedBy(*s)
+	}
+	return su
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (su *SettingUpdate) ClearUpdatedBy() *SettingUpdate {
+	su.mutation.ClearUpdatedBy()
+	return su
+}
+
+// SetKey sets the "key" field.
+func (su *SettingUpdate) SetKey(s string) *SettingUpdate {
+	su.mutation.SetKey(s)
+	return su
+}
+
+// SetNillableKey sets the "key" field if the given value is not nil.
+func (su *SettingUpdate) SetNillableKey(s *string) *SettingUpdate {
+	if s != nil {
+		su.SetKey(*s)
+	}
+	return su
+}
+
+// SetValue sets the "value" field.
+func (su *SettingUpdate) SetValue(s string) *SettingUpdate {
+	su.mutation.SetValue(s)
+	return su
+}
+
+// SetNillableValue sets the "value" field if the given value is not nil.
+func (su *SettingUpdate) SetNillableValue(s *string) *SettingUpdate {
+	if s != nil {
+		su.SetValue(*s)
+	}
+	return su
+}
+
+// SetDescription sets the "description" field.
+func (su *SettingUpdate) SetDescription(s string) *SettingUpdate {
+	su.mutation.SetDescription(s)
+	return su
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (su *SettingUpdate) SetNillableDescription(s *string) *SettingUpdate {
+	if s != nil {
+		su.SetDescription(*s)
+	}
+	return su
+}
+
+// ClearDescription clears the value of the "description" field.
+func (su *SettingUpdate) ClearDescription() *SettingUpdate {
+	su.mutation.ClearDescription()
+	return su
+}
+
+// Mutation returns the SettingMutation object of the builder.
+func (su *SettingUpdate) Mutation() *SettingMutation {
+	return su.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (su *SettingUpdate) Save(ctx context.Context) (int, error) {
+	if err := su.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks(ctx, su.sqlSave, su.mutation, su.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (su *SettingUpdate) SaveX(ctx context.Context) int {
+	affected, err := su.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (su *SettingUpdate) Exec(ctx context.Context) error {
+	_, err := su.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (su *SettingUpdate) ExecX(ctx context.Context) {
+	if err := su.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (su *SettingUpdate) defaults() error {
+	if _, ok := su.mutation.UpdatedAt(); !ok {
+		if setting.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized setting.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := setting.UpdateDefaultUpdatedAt()
+		su.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (su *SettingUpdate) check() error {
+	if v, ok := su.mutation.Key(); ok {
+		if err := setting.KeyValidator(v); err != nil {
+			return &ValidationError{Name: "key", err: fmt.Errorf(`ent: validator failed for field "Setting.key": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (su *SettingUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *SettingUpdate {
+	su.modifiers = append(su.modifiers, modifiers...)
+	return su
+}
+
+func (su *SettingUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := su.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(setting.Table, setting.Columns, sqlgraph.NewFieldSpec(setting.FieldID, field.TypeString))
+	if ps := su.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := su.mutation.UpdatedAt(); ok {
+		_spec.SetField(setting.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if su.mutation.CreatedByCleared() {
+		_spec.ClearField(setting.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := su.mutation.UpdatedBy(); ok {
+		_spec.SetField(setting.FieldUpdatedBy, field.TypeString, value)
+	}
+	if su.mutation.UpdatedByCleared() {
+		_spec.ClearField(setting.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := su.mutation.Key(); ok {
+		_spec.SetField(setting.FieldKey, field.TypeString, value)
+	}
+	if value, ok := su.mutation.Value(); ok {
+		_spec.SetField(setting.FieldValue, field.TypeString, value)
+	}
+	if value, ok := su.mutation.Description(); ok {
+		_spec.SetField(setting.FieldDescription, field.TypeString, value)
+	}
+	if su.mutation.DescriptionCleared() {
+		_spec.ClearField(setting.FieldDescription, field.TypeString)
+	}
+	_spec.AddModifiers(su.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, su.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{setting.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	su.mutation.done = true
+	return n, nil
+}
+
+// SettingUpdateOne is the builder for updating a single Setting entity.
+type SettingUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *SettingMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (suo *SettingUpdateOne) SetUpdatedAt(t time.Time) *SettingUpdateOne {
+	suo.mutation.SetUpdatedAt(t)
+	return suo
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (suo *SettingUpdateOne) SetUpdatedBy(s string) *SettingUpdateOne {
+	suo.mutation.SetUpdatedBy(s)
+	return suo
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (suo *SettingUpdateOne) SetNillableUpdatedBy(s *string) *SettingUpdateOne {
+	if s != nil {
+		suo.SetUpdatedBy(*s)
+	}
+	return suo
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (suo *SettingUpdateOne) ClearUpdatedBy() *SettingUpdateOne {
+	suo.mutation.ClearUpdatedBy()
+	return suo
+}
+
+// SetKey sets the "key" field.
+func (suo *SettingUpdateOne) SetKey(s string) *SettingUpdateOne {
+	suo.mutation.SetKey(s)
+	return suo
+}
+
+// SetNillableKey sets the "key" field if the given value is not nil.
+func (suo *SettingUpdateOne) SetNillableKey(s *string) *SettingUpdateOne {
+	if s != nil {
+		suo.SetKey(*s)
+	}
+	return suo
+}
+
+// SetValue sets the "value" field.
+func (suo *SettingUpdateOne) SetValue(s string) *SettingUpdateOne {
+	suo.mutation.SetValue(s)
+	return suo
+}
+
+// SetNillableValue sets the "value" field if the given value is not nil.
+func (suo *SettingUpdateOne) SetNillableValue(s *string) *SettingUpdateOne {
+	if s != nil {
+		suo.SetValue(*s)
+	}
+	return suo
+}
+
+// SetDescription sets the "description" field.
+func (suo *SettingUpdateOne) SetDescription(s string) *SettingUpdateOne {
+	suo.mutation.SetDescription(s)
+	return suo
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (suo *SettingUpdateOne) SetNillableDescription(s *string) *SettingUpdateOne {
+	if s != nil {
+		suo.SetDescription(*s)
+	}
+	return suo
+}
+
+// ClearDescription clears the value of the "description" field.
+func (suo *SettingUpdateOne) ClearDescription() *SettingUpdateOne {
+	suo.mutation.ClearDescription()
+	return suo
+}
+
+// Mutation returns the SettingMutation object of the builder.
+func (suo *SettingUpdateOne) Mutation() *SettingMutation {
+	return suo.mutation
+}
+
+// Where appends a list predicates to the SettingUpdate builder.
+func (suo *SettingUpdateOne) Where(ps ...predicate.Setting) *SettingUpdateOne {
+	suo.mutation.Where(ps...)
+	return suo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (suo *SettingUpdateOne) Select(field string, fields ...string) *SettingUpdateOne {
+	suo.fields = append([]string{field}, fields...)
+	return suo
+}
+
+// Save executes the query and returns the updated Setting entity.
+func (suo *SettingUpdateOne) Save(ctx context.Context) (*Setting, error) {
+	if err := suo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, suo.sqlSave, suo.mutation, suo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (suo *SettingUpdateOne) SaveX(ctx context.Context) *Setting {
+	node, err := suo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (suo *SettingUpdateOne) Exec(ctx context.Context) error {
+	_, err := suo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (suo *SettingUpdateOne) ExecX(ctx context.Context) {
+	if err := suo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (suo *SettingUpdateOne) defaults() error {
+	if _, ok := suo.mutation.UpdatedAt(); !ok {
+		if setting.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized setting.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := setting.UpdateDefaultUpdatedAt()
+		suo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (suo *SettingUpdateOne) check() error {
+	if v, ok := suo.mutation.Key(); ok {
+		if err := setting.KeyValidator(v); err != nil {
+			return &ValidationError{Name: "key", err: fmt.Errorf(`ent: validator failed for field "Setting.key": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (suo *SettingUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *SettingUpdateOne {
+	suo.modifiers = append(suo.modifiers, modifiers...)
+	return suo
+}
+
+func (suo *SettingUpdateOne) sqlSave(ctx context.Context) (_node *Setting, err error) {
+	if err := suo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(setting.Table, setting.Columns, sqlgraph.NewFieldSpec(setting.FieldID, field.TypeString))
+	id, ok := suo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Setting.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := suo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, setting.FieldID)
+		for _, f := range fields {
+			if !setting.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != setting.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := suo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := suo.mutation.UpdatedAt(); ok {
+		_spec.SetField(setting.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if suo.mutation.CreatedByCleared() {
+		_spec.ClearField(setting.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := suo.mutation.UpdatedBy(); ok {
+		_spec.SetField(setting.FieldUpdatedBy, field.TypeString, value)
+	}
+	if suo.mutation.UpdatedByCleared() {
+		_spec.ClearField(setting.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := suo.mutation.Key(); ok {
+		_spec.SetField(setting.FieldKey, field.TypeString, value)
+	}
+	if value, ok := suo.mutation.Value(); ok {
+		_spec.SetField(setting.FieldValue, field.TypeString, value)
+	}
+	if value, ok := suo.mutation.Description(); ok {
+		_spec.SetField(setting.FieldDescription, field.TypeString, value)
+	}
+	if suo.mutation.DescriptionCleared() {
+		_spec.ClearField(setting.FieldDescription, field.TypeString)
+	}
+	_spec.AddModifiers(suo.modifiers...)
+	_node = &Setting{config: suo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, suo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{setting.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	suo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,246 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/ent/userpreference"
+)
+
+// UserPreference is the model entity for the UserPreference schema.
+type UserPreference struct {
+	config `json:"-"`
+	// ID of the ent.
+	// 主键
+	ID string `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// 创建人，用户ID或 service:<服务账号ID>
+	CreatedBy string `json:"created_by,omitempty"`
+	// 最后修改人
+	UpdatedBy string `json:"updated_by,omitempty"`
+	// 用户ID
+	UserID string `json:"user_id,omitempty"`
+	// 接收邮件通知
+	NotifyEmail bool `json:"notify_email,omitempty"`
+	// 接收短信通知
+	NotifySms bool `json:"notify_sms,omitempty"`
+	// 接收产品动态与营销消息
+	NotifyMarketing bool `json:"notify_marketing,omitempty"`
+	// 界面主题
+	Theme userpreference.Theme `json:"theme,omitempty"`
+	// 语言区域，BCP 47 格式，为空表示跟随客户端
+	Locale string `json:"locale,omitempty"`
+	// 时区，IANA 名称如 Asia/Shanghai，为空表示 UTC；供客户端显示时间
+	Timezone string `json:"timezone,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the UserPreferenceQuery when eager-loading is set.
+	Edges        UserPreferenceEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// UserPreferenceEdges holds the relations/edges for other nodes in the graph.
+type UserPreferenceEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e UserPreferenceEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*UserPreference) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case userpreference.FieldNotifyEmail, userpreference.FieldNotifySms, userpreference.FieldNotifyMarketing:
+			values[i] = new(sql.NullBool)
+		case userpreference.FieldID, userpreference.FieldCreatedBy, userpreference.FieldUpdatedBy, userpreference.FieldUserID, userpreference.FieldTheme, userpreference.FieldLocale, userpreference.FieldTimezone:
+			values[i] = new(sql.NullString)
+		case userpreference.FieldCreatedAt, userpreference.FieldUpdatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the UserPreference fields.
+func (up *UserPreference) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case userpreference.FieldID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value.Valid {
+				up.ID = value.String
+			}
+		case userpreference.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				up.CreatedAt = value.Time
+			}
+		case userpreference.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				up.UpdatedAt = value.Time
+			}
+		case userpreference.FieldCreatedBy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field created_by", values[i])
+			} else if value.Valid {
+				up.CreatedBy = value.String
+			}
+		case userpreference.FieldUpdatedBy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_by", values[i])
+			} else if value.Valid {
+				up.UpdatedBy = value.String
+			}
+		case userpreference.FieldUserID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				up.UserID = value.String
+			}
+		case userpreference.FieldNotifyEmail:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field notify_email", values[i])
+			} else if value.Valid {
+				up.NotifyEmail = value.Bool
+			}
+		case userpreference.FieldNotifySms:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field notify_sms", values[i])
+			} else if value.Valid {
+				up.NotifySms = value.Bool
+			}
+		case userpreference.FieldNotifyMarketing:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field notify_marketing", values[i])
+			} else if value.Valid {
+				up.NotifyMarketing = value.Bool
+			}
+		case userpreference.FieldTheme:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field theme", values[i])
+			} else if value.Valid {
+				up.Theme = userpreference.Theme(value.String)
+			}
+		case userpreference.FieldLocale:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field locale", values[i])
+			} else if value.Valid {
+				up.Locale = value.String
+			}
+		case userpreference.FieldTimezone:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field timezone", values[i])
+			} else if value.Valid {
+				up.Timezone = value.String
+			}
+		default:
+			up.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the UserPreference.
+// This includes values selected through modifiers, order, etc.
+func (up *UserPreference) Value(name string) (ent.Value, error) {
+	return up.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the UserPreference entity.
+func (up *UserPreference) QueryUser() *UserQuery {
+	return NewUserPreferenceClient(up.config).QueryUser(up)
+}
+
+// Update returns a builder for updating this UserPreference.
+// Note that you need to call UserPreference.Unwrap() before calling this method if this UserPreference
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (up *UserPreference) Update() *UserPreferenceUpdateOne {
+	return NewUserPreferenceClient(up.config).UpdateOne(up)
+}
+
+// Unwrap unwraps the UserPreference entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (up *UserPreference) Unwrap() *UserPreference {
+	_tx, ok := up.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: UserPreference is not a transactional entity")
+	}
+	up.config.driver = _tx.drv
+	return up
+}
+
+// String implements the fmt.Stringer.
+func (up *UserPreference) String() string {
+	var builder strings.Builder
+	builder.WriteString("UserPreference(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", up.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(up.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(up.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("created_by=")
+	builder.WriteString(up.CreatedBy)
+	builder.WriteString(", ")
+	builder.WriteString("updated_by=")
+	builder.WriteString(up.UpdatedBy)
+	builder.WriteString(", ")
+	builder.WriteString("user_id=")
+	builder.WriteString(up.UserID)
+	builder.WriteString(", ")
+	builder.WriteString("notify_email=")
+	builder.WriteString(fmt.Sprintf("%v", up.NotifyEmail))
+	builder.WriteString(", ")
+	builder.WriteString("notify_sms=")
+	builder.WriteString(fmt.Sprintf("%v", up.NotifySms))
+	builder.WriteString(", ")
+	builder.WriteString("notify_marketing=")
+	builder.WriteString(fmt.Sprintf("%v", up.NotifyMarketing))
+	builder.WriteString(", ")
+	builder.WriteString("theme=")
+	builder.WriteString(fmt.Sprintf("%v", up.Theme))
+	builder.WriteString(", ")
+	builder.WriteString("locale=")
+	builder.WriteString(up.Locale)
+	builder.WriteString(", ")
+	builder.WriteString("timezone=")
+	builder.WriteString(up.Timezone)
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// UserPreferences is a parsable slice of UserPreference.
+type UserPreferences []*UserPreference
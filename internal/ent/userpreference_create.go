@@ -0,0 +1,479 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/ent/userpreference"
+)
+
+// UserPreferenceCreate is the builder for creating a UserPreference entity.
+type UserPreferenceCreate struct {
+	config
+	mutation *UserPreferenceMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (upc *UserPreferenceCreate) SetCreatedAt(t time.Time) *UserPreferenceCreate {
+	upc.mutation.SetCreatedAt(t)
+	return upc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableCreatedAt(t *time.Time) *UserPreferenceCreate {
+	if t != nil {
+		upc.SetCreatedAt(*t)
+	}
+	return upc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (upc *UserPreferenceCreate) SetUpdatedAt(t time.Time) *UserPreferenceCreate {
+	upc.mutation.SetUpdatedAt(t)
+	return upc
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableUpdatedAt(t *time.Time) *UserPreferenceCreate {
+	if t != nil {
+		upc.SetUpdatedAt(*t)
+	}
+	return upc
+}
+
+// SetCreatedBy sets the "created_by" field.
+func (upc *UserPreferenceCreate) SetCreatedBy(s string) *UserPreferenceCreate {
+	upc.mutation.SetCreatedBy(s)
+	return upc
+}
+
+// SetNillableCreatedBy sets the "created_by" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableCreatedBy(s *string) *UserPreferenceCreate {
+	if s != nil {
+		upc.SetCreatedBy(*s)
+	}
+	return upc
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (upc *UserPreferenceCreate) SetUpdatedBy(s string) *UserPreferenceCreate {
+	upc.mutation.SetUpdatedBy(s)
+	return upc
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableUpdatedBy(s *string) *UserPreferenceCreate {
+	if s != nil {
+		upc.SetUpdatedBy(*s)
+	}
+	return upc
+}
+
+// SetUserID sets the "user_id" field.
+func (upc *UserPreferenceCreate) SetUserID(s string) *UserPreferenceCreate {
+	upc.mutation.SetUserID(s)
+	return upc
+}
+
+// SetNotifyEmail sets the "notify_email" field.
+func (upc *UserPreferenceCreate) SetNotifyEmail(b bool) *UserPreferenceCreate {
+	upc.mutation.SetNotifyEmail(b)
+	return upc
+}
+
+// SetNillableNotifyEmail sets the "notify_email" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableNotifyEmail(b *bool) *UserPreferenceCreate {
+	if b != nil {
+		upc.SetNotifyEmail(*b)
+	}
+	return upc
+}
+
+// SetNotifySms sets the "notify_sms" field.
+func (upc *UserPreferenceCreate) SetNotifySms(b bool) *UserPreferenceCreate {
+	upc.mutation.SetNotifySms(b)
+	return upc
+}
+
+// SetNillableNotifySms sets the "notify_sms" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableNotifySms(b *bool) *UserPreferenceCreate {
+	if b != nil {
+		upc.SetNotifySms(*b)
+	}
+	return upc
+}
+
+// SetNotifyMarketing sets the "notify_marketing" field.
+func (upc *UserPreferenceCreate) SetNotifyMarketing(b bool) *UserPreferenceCreate {
+	upc.mutation.SetNotifyMarketing(b)
+	return upc
+}
+
+// SetNillableNotifyMarketing sets the "notify_marketing" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableNotifyMarketing(b *bool) *UserPreferenceCreate {
+	if b != nil {
+		upc.SetNotifyMarketing(*b)
+	}
+	return upc
+}
+
+// SetTheme sets the "theme" field.
+func (upc *UserPreferenceCreate) SetTheme(u userpreference.Theme) *UserPreferenceCreate {
+	upc.mutation.SetTheme(u)
+	return upc
+}
+
+// SetNillableTheme sets the "theme" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableTheme(u *userpreference.Theme) *UserPreferenceCreate {
+	if u != nil {
+		upc.SetTheme(*u)
+	}
+	return upc
+}
+
+// SetLocale sets the "locale" field.
+func (upc *UserPreferenceCreate) SetLocale(s string) *UserPreferenceCreate {
+	upc.mutation.SetLocale(s)
+	return upc
+}
+
+// SetNillableLocale sets the "locale" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableLocale(s *string) *UserPreferenceCreate {
+	if s != nil {
+		upc.SetLocale(*s)
+	}
+	return upc
+}
+
+// SetTimezone sets the "timezone" field.
+func (upc *UserPreferenceCreate) SetTimezone(s string) *UserPreferenceCreate {
+	upc.mutation.SetTimezone(s)
+	return upc
+}
+
+// SetNillableTimezone sets the "timezone" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableTimezone(s *string) *UserPreferenceCreate {
+	if s != nil {
+		upc.SetTimezone(*s)
+	}
+	return upc
+}
+
+// SetID sets the "id" field.
+func (upc *UserPreferenceCreate) SetID(s string) *UserPreferenceCreate {
+	upc.mutation.SetID(s)
+	return upc
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (upc *UserPreferenceCreate) SetNillableID(s *string) *UserPreferenceCreate {
+	if s != nil {
+		upc.SetID(*s)
+	}
+	return upc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (upc *UserPreferenceCreate) SetUser(u *User) *UserPreferenceCreate {
+	return upc.SetUserID(u.ID)
+}
+
+// Mutation returns the UserPreferenceMutation object of the builder.
+func (upc *UserPreferenceCreate) Mutation() *UserPreferenceMutation {
+	return upc.mutation
+}
+
+// Save creates the UserPreference in the database.
+func (upc *UserPreferenceCreate) Save(ctx context.Context) (*UserPreference, error) {
+	if err := upc.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, upc.sqlSave, upc.mutation, upc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (upc *UserPreferenceCreate) SaveX(ctx context.Context) *UserPreference {
+	v, err := upc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (upc *UserPreferenceCreate) Exec(ctx context.Context) error {
+	_, err := upc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (upc *UserPreferenceCreate) ExecX(ctx context.Context) {
+	if err := upc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (upc *UserPreferenceCreate) defaults() error {
+	if _, ok := upc.mutation.CreatedAt(); !ok {
+		if userpreference.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized userpreference.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
+		v := userpreference.DefaultCreatedAt()
+		upc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := upc.mutation.UpdatedAt(); !ok {
+		if userpreference.DefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized userpreference.DefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := userpreference.DefaultUpdatedAt()
+		upc.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := upc.mutation.NotifyEmail(); !ok {
+		v := userpreference.DefaultNotifyEmail
+		upc.mutation.SetNotifyEmail(v)
+	}
+	if _, ok := upc.mutation.NotifySms(); !ok {
+		v := userpreference.DefaultNotifySms
+		upc.mutation.SetNotifySms(v)
+	}
+	if _, ok := upc.mutation.NotifyMarketing(); !ok {
+		v := userpreference.DefaultNotifyMarketing
+		upc.mutation.SetNotifyMarketing(v)
+	}
+	if _, ok := upc.mutation.Theme(); !ok {
+		v := userpreference.DefaultTheme
+		upc.mutation.SetTheme(v)
+	}
+	if _, ok := upc.mutation.ID(); !ok {
+		if userpreference.DefaultID == nil {
+			return fmt.Errorf("ent: uninitialized userpreference.DefaultID (forgotten import ent/runtime?)")
+		}
+		v := userpreference.DefaultID()
+		upc.mutation.SetID(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (upc *UserPreferenceCreate) check() error {
+	if _, ok := upc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "UserPreference.created_at"`)}
+	}
+	if _, ok := upc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "UserPreference.updated_at"`)}
+	}
+	if _, ok := upc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "UserPreference.user_id"`)}
+	}
+	if _, ok := upc.mutation.NotifyEmail(); !ok {
+		return &ValidationError{Name: "notify_email", err: errors.New(`ent: missing required field "UserPreference.notify_email"`)}
+	}
+	if _, ok := upc.mutation.NotifySms(); !ok {
+		return &ValidationError{Name: "notify_sms", err: errors.New(`ent: missing required field "UserPreference.notify_sms"`)}
+	}
+	if _, ok := upc.mutation.NotifyMarketing(); !ok {
+		return &ValidationError{Name: "notify_marketing", err: errors.New(`ent: missing required field "UserPreference.notify_marketing"`)}
+	}
+	if _, ok := upc.mutation.Theme(); !ok {
+		return &ValidationError{Name: "theme", err: errors.New(`ent: missing required field "UserPreference.theme"`)}
+	}
+	if v, ok := upc.mutation.Theme(); ok {
+		if err := userpreference.ThemeValidator(v); err != nil {
+			return &ValidationError{Name: "theme", err: fmt.Errorf(`ent: validator failed for field "UserPreference.theme": %w`, err)}
+		}
+	}
+	if v, ok := upc.mutation.ID(); ok {
+		if err := userpreference.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`ent: validator failed for field "UserPreference.id": %w`, err)}
+		}
+	}
+	if len(upc.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "UserPreference.user"`)}
+	}
+	return nil
+}
+
+func (upc *UserPreferenceCreate) sqlSave(ctx context.Context) (*UserPreference, error) {
+	if err := upc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := upc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, upc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected UserPreference.ID type: %T", _spec.ID.Value)
+		}
+	}
+	upc.mutation.id = &_node.ID
+	upc.mutation.done = true
+	return _node, nil
+}
+
+func (upc *UserPreferenceCreate) createSpec() (*UserPreference, *sqlgraph.CreateSpec) {
+	var (
+		_node = &UserPreference{config: upc.config}
+		_spec = sqlgraph.NewCreateSpec(userpreference.Table, sqlgraph.NewFieldSpec(userpreference.FieldID, field.TypeString))
+	)
+	if id, ok := upc.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := upc.mutation.CreatedAt(); ok {
+		_spec.SetField(userpreference.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := upc.mutation.UpdatedAt(); ok {
+		_spec.SetField(userpreference.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := upc.mutation.CreatedBy(); ok {
+		_spec.SetField(userpreference.FieldCreatedBy, field.TypeString, value)
+		_node.CreatedBy = value
+	}
+	if value, ok := upc.mutation.UpdatedBy(); ok {
+		_spec.SetField(userpreference.FieldUpdatedBy, field.TypeString, value)
+		_node.UpdatedBy = value
+	}
+	if value, ok := upc.mutation.NotifyEmail(); ok {
+		_spec.SetField(userpreference.FieldNotifyEmail, field.TypeBool, value)
+		_node.NotifyEmail = value
+	}
+	if value, ok := upc.mutation.NotifySms(); ok {
+		_spec.SetField(userpreference.FieldNotifySms, field.TypeBool, value)
+		_node.NotifySms = value
+	}
+	if value, ok := upc.mutation.NotifyMarketing(); ok {
+		_spec.SetField(userpreference.FieldNotifyMarketing, field.TypeBool, value)
+		_node.NotifyMarketing = value
+	}
+	if value, ok := upc.mutation.Theme(); ok {
+		_spec.SetField(userpreference.FieldTheme, field.TypeEnum, value)
+		_node.Theme = value
+	}
+	if value, ok := upc.mutation.Locale(); ok {
+		_spec.SetField(userpreference.FieldLocale, field.TypeString, value)
+		_node.Locale = value
+	}
+	if value, ok := upc.mutation.Timezone(); ok {
+		_spec.SetField(userpreference.FieldTimezone, field.TypeString, value)
+		_node.Timezone = value
+	}
+	if nodes := upc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2O,
+			Inverse: true,
+			Table:   userpreference.UserTable,
+			Columns: []string{userpreference.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeString),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// UserPreferenceCreateBulk is the builder for creating many UserPreference entities in bulk.
+type UserPreferenceCreateBulk struct {
+	config
+	err      error
+	builders []*UserPreferenceCreate
+}
+
+// Save creates the UserPreference entities in the database.
+func (upcb *UserPreferenceCreateBulk) Save(ctx context.Context) ([]*UserPreference, error) {
+	if upcb.err != nil {
+		return nil, upcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(upcb.builders))
+	nodes := make([]*UserPreference, len(upcb.builders))
+	mutators := make([]Mutator, len(upcb.builders))
+	for i := range upcb.builders {
+		func(i int, root context.Context) {
+			builder := upcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*UserPreferenceMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, upcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, upcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, upcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (upcb *UserPreferenceCreateBulk) SaveX(ctx context.Context) []*UserPreference {
+	v, err := upcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (upcb *UserPreferenceCreateBulk) Exec(ctx context.Context) error {
+	_, err := upcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (upcb *UserPreferenceCreateBulk) ExecX(ctx context.Context) {
+	if err := upcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,629 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/ent/userpreference"
+)
+
+// UserPreferenceQuery is the builder for querying UserPreference entities.
+type UserPreferenceQuery struct {
+	config
+	ctx        *QueryContext
+	order      []userpreference.OrderOption
+	inters     []Interceptor
+	predicates []predicate.UserPreference
+	withUser   *UserQuery
+	modifiers  []func(*sql.Selector)
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the UserPreferenceQuery builder.
+func (upq *UserPreferenceQuery) Where(ps ...predicate.UserPreference) *UserPreferenceQuery {
+	upq.predicates = append(upq.predicates, ps...)
+	return upq
+}
+
+// Limit the number of records to be returned by this query.
+func (upq *UserPreferenceQuery) Limit(limit int) *UserPreferenceQuery {
+	upq.ctx.Limit = &limit
+	return upq
+}
+
+// Offset to start from.
+func (upq *UserPreferenceQuery) Offset(offset int) *UserPreferenceQuery {
+	upq.ctx.Offset = &offset
+	return upq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (upq *UserPreferenceQuery) Unique(unique bool) *UserPreferenceQuery {
+	upq.ctx.Unique = &unique
+	return upq
+}
+
+// Order specifies how the records should be ordered.
+func (upq *UserPreferenceQuery) Order(o ...userpreference.OrderOption) *UserPreferenceQuery {
+	upq.order = append(upq.order, o...)
+	return upq
+}
+
+// QueryUser chains the current query on the "user" edge.
+func (upq *UserPreferenceQuery) QueryUser() *UserQuery {
+	query := (&UserClient{config: upq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := upq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := upq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(userpreference.Table, userpreference.FieldID, selector),
+			sqlgraph.To(user.Table, user.FieldID),
+			sqlgraph.Edge(sqlgraph.O2O, true, userpreference.UserTable, userpreference.UserColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(upq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first UserPreference entity from the query.
+// Returns a *NotFoundError when no UserPreference was found.
+func (upq *UserPreferenceQuery) First(ctx context.Context) (*UserPreference, error) {
+	nodes, err := upq.Limit(1).All(setContextOp(ctx, upq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{userpreference.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (upq *UserPreferenceQuery) FirstX(ctx context.Context) *UserPreference {
+	node, err := upq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first UserPreference ID from the query.
+// Returns a *NotFoundError when no UserPreference ID was found.
+func (upq *UserPreferenceQuery) FirstID(ctx context.Context) (id string, err error) {
+	var ids []string
+	if ids, err = upq.Limit(1).IDs(setContextOp(ctx, upq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{userpreference.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (upq *UserPreferenceQuery) FirstIDX(ctx context.Context) string {
+	id, err := upq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single UserPreference entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one UserPreference entity is found.
+// Returns a *NotFoundError when no UserPreference entities are found.
+func (upq *UserPreferenceQuery) Only(ctx context.Context) (*UserPreference, error) {
+	nodes, err := upq.Limit(2).All(setContextOp(ctx, upq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{userpreference.Label}
+	default:
+		return nil, &NotSingularError{userpreference.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (upq *UserPreferenceQuery) OnlyX(ctx context.Context) *UserPreference {
+	node, err := upq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only UserPreference ID in the query.
+// Returns a *NotSingularError when more than one UserPreference ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (upq *UserPreferenceQuery) OnlyID(ctx context.Context) (id string, err error) {
+	var ids []string
+	if ids, err = upq.Limit(2).IDs(setContextOp(ctx, upq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{userpreference.Label}
+	default:
+		err = &NotSingularError{userpreference.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (upq *UserPreferenceQuery) OnlyIDX(ctx context.Context) string {
+	id, err := upq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of UserPreferences.
+func (upq *UserPreferenceQuery) All(ctx context.Context) ([]*UserPreference, error) {
+	ctx = setContextOp(ctx, upq.ctx, ent.OpQueryAll)
+	if err := upq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*UserPreference, *UserPreferenceQuery]()
+	return withInterceptors[[]*UserPreference](ctx, upq, qr, upq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (upq *UserPreferenceQuery) AllX(ctx context.Context) []*UserPreference {
+	nodes, err := upq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of UserPreference IDs.
+func (upq *UserPreferenceQuery) IDs(ctx context.Context) (ids []string, err error) {
+	if upq.ctx.Unique == nil && upq.path != nil {
+		upq.Unique(true)
+	}
+	ctx = setContextOp(ctx, upq.ctx, ent.OpQueryIDs)
+	if err = upq.Select(userpreference.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (upq *UserPreferenceQuery) IDsX(ctx context.Context) []string {
+	ids, err := upq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (upq *UserPreferenceQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, upq.ctx, ent.OpQueryCount)
+	if err := upq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, upq, querierCount[*UserPreferenceQuery](), upq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (upq *UserPreferenceQuery) CountX(ctx context.Context) int {
+	count, err := upq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (upq *UserPreferenceQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, upq.ctx, ent.OpQueryExist)
+	switch _, err := upq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (upq *UserPreferenceQuery) ExistX(ctx context.Context) bool {
+	exist, err := upq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the UserPreferenceQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (upq *UserPreferenceQuery) Clone() *UserPreferenceQuery {
+	if upq == nil {
+		return nil
+	}
+	return &UserPreferenceQuery{
+		config:     upq.config,
+		ctx:        upq.ctx.Clone(),
+		order:      append([]userpreference.OrderOption{}, upq.order...),
+		inters:     append([]Interceptor{}, upq.inters...),
+		predicates: append([]predicate.UserPreference{}, upq.predicates...),
+		withUser:   upq.withUser.Clone(),
+		// clone intermediate query.
+		sql:       upq.sql.Clone(),
+		path:      upq.path,
+		modifiers: append([]func(*sql.Selector){}, upq.modifiers...),
+	}
+}
+
+// WithUser tells the query-builder to eager-load the nodes that are connected to
+// the "user" edge. The optional arguments are used to configure the query builder of the edge.
+func (upq *UserPreferenceQuery) WithUser(opts ...func(*UserQuery)) *UserPreferenceQuery {
+	query := (&UserClient{config: upq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	upq.withUser = query
+	return upq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		CreatedAt time.Time `json:"created_at,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.UserPreference.Query().
+//		GroupBy(userpreference.FieldCreatedAt).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (upq *UserPreferenceQuery) GroupBy(field string, fields ...string) *UserPreferenceGroupBy {
+	upq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &UserPreferenceGroupBy{build: upq}
+	grbuild.flds = &upq.ctx.Fields
+	grbuild.label = userpreference.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		CreatedAt time.Time `json:"created_at,omitempty"`
+//	}
+//
+//	client.UserPreference.Query().
+//		Select(userpreference.FieldCreatedAt).
+//		Scan(ctx, &v)
+func (upq *UserPreferenceQuery) Select(fields ...string) *UserPreferenceSelect {
+	upq.ctx.Fields = append(upq.ctx.Fields, fields...)
+	sbuild := &UserPreferenceSelect{UserPreferenceQuery: upq}
+	sbuild.label = userpreference.Label
+	sbuild.flds, sbuild.scan = &upq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a UserPreferenceSelect configured with the given aggregations.
+func (upq *UserPreferenceQuery) Aggregate(fns ...AggregateFunc) *UserPreferenceSelect {
+	return upq.Select().Aggregate(fns...)
+}
+
+func (upq *UserPreferenceQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range upq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, upq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range upq.ctx.Fields {
+		if !userpreference.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if upq.path != nil {
+		prev, err := upq.path(ctx)
+		if err != nil {
+			return err
+		}
+		upq.sql = prev
+	}
+	return nil
+}
+
+func (upq *UserPreferenceQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*UserPreference, error) {
+	var (
+		nodes       = []*UserPreference{}
+		_spec       = upq.querySpec()
+		loadedTypes = [1]bool{
+			upq.withUser != nil,
+		}
+	)
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*UserPreference).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &UserPreference{config: upq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	if len(upq.modifiers) > 0 {
+		_spec.Modifiers = upq.modifiers
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, upq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := upq.withUser; query != nil {
+		if err := upq.loadUser(ctx, query, nodes, nil,
+			func(n *UserPreference, e *User) { n.Edges.User = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (upq *UserPreferenceQuery) loadUser(ctx context.Context, query *UserQuery, nodes []*UserPreference, init func(*UserPreference), assign func(*UserPreference, *User)) error {
+	ids := make([]string, 0, len(nodes))
+	nodeids := make(map[string][]*UserPreference)
+	for i := range nodes {
+		fk := nodes[i].UserID
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(user.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "user_id" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (upq *UserPreferenceQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := upq.querySpec()
+	if len(upq.modifiers) > 0 {
+		_spec.Modifiers = upq.modifiers
+	}
+	_spec.Node.Columns = upq.ctx.Fields
+	if len(upq.ctx.Fields) > 0 {
+		_spec.Unique = upq.ctx.Unique != nil && *upq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, upq.driver, _spec)
+}
+
+func (upq *UserPreferenceQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(userpreference.Table, userpreference.Columns, sqlgraph.NewFieldSpec(userpreference.FieldID, field.TypeString))
+	_spec.From = upq.sql
+	if unique := upq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if upq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := upq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, userpreference.FieldID)
+		for i := range fields {
+			if fields[i] != userpreference.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+		if upq.withUser != nil {
+			_spec.Node.AddColumnOnce(userpreference.FieldUserID)
+		}
+	}
+	if ps := upq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := upq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := upq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := upq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (upq *UserPreferenceQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(upq.driver.Dialect())
+	t1 := builder.Table(userpreference.Table)
+	columns := upq.ctx.Fields
+	if len(columns) == 0 {
+		columns = userpreference.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if upq.sql != nil {
+		selector = upq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if upq.ctx.Unique != nil && *upq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, m := range upq.modifiers {
+		m(selector)
+	}
+	for _, p := range upq.predicates {
+		p(selector)
+	}
+	for _, p := range upq.order {
+		p(selector)
+	}
+	if offset := upq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := upq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (upq *UserPreferenceQuery) Modify(modifiers ...func(s *sql.Selector)) *UserPreferenceSelect {
+	upq.modifiers = append(upq.modifiers, modifiers...)
+	return upq.Select()
+}
+
+// UserPreferenceGroupBy is the group-by builder for UserPreference entities.
+type UserPreferenceGroupBy struct {
+	selector
+	build *UserPreferenceQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (upgb *UserPreferenceGroupBy) Aggregate(fns ...AggregateFunc) *UserPreferenceGroupBy {
+	upgb.fns = append(upgb.fns, fns...)
+	return upgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (upgb *UserPreferenceGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, upgb.build.ctx, ent.OpQueryGroupBy)
+	if err := upgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*UserPreferenceQuery, *UserPreferenceGroupBy](ctx, upgb.build, upgb, upgb.build.inters, v)
+}
+
+func (upgb *UserPreferenceGroupBy) sqlScan(ctx context.Context, root *UserPreferenceQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(upgb.fns))
+	for _, fn := range upgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*upgb.flds)+len(upgb.fns))
+		for _, f := range *upgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*upgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := upgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// UserPreferenceSelect is the builder for selecting fields of UserPreference entities.
+type UserPreferenceSelect struct {
+	*UserPreferenceQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (ups *UserPreferenceSelect) Aggregate(fns ...AggregateFunc) *UserPreferenceSelect {
+	ups.fns = append(ups.fns, fns...)
+	return ups
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ups *UserPreferenceSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ups.ctx, ent.OpQuerySelect)
+	if err := ups.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*UserPreferenceQuery, *UserPreferenceSelect](ctx, ups.UserPreferenceQuery, ups, ups.inters, v)
+}
+
+func (ups *UserPreferenceSelect) sqlScan(ctx context.Context, root *UserPreferenceQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(ups.fns))
+	for _, fn := range ups.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*ups.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ups.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (ups *UserPreferenceSelect) Modify(modifiers ...func(s *sql.Selector)) *UserPreferenceSelect {
+	ups.modifiers = append(ups.modifiers, modifiers...)
+	return ups
+}
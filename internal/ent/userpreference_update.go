@@ -0,0 +1,570 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/hewenyu/gin-pkg/internal/ent/predicate"
+	"github.com/hewenyu/gin-pkg/internal/ent/userpreference"
+)
+
+// UserPreferenceUpdate is the builder for updating UserPreference entities.
+type UserPreferenceUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *UserPreferenceMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the UserPreferenceUpdate builder.
+func (upu *UserPreferenceUpdate) Where(ps ...predicate.UserPreference) *UserPreferenceUpdate {
+	upu.mutation.Where(ps...)
+	return upu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (upu *UserPreferenceUpdate) SetUpdatedAt(t time.Time) *UserPreferenceUpdate {
+	upu.mutation.SetUpdatedAt(t)
+	return upu
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (upu *UserPreferenceUpdate) SetUpdatedBy(s string) *UserPreferenceUpdate {
+	upu.mutation.SetUpdatedBy(s)
+	return upu
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (upu *UserPreferenceUpdate) SetNillableUpdatedBy(s *string) *UserPreferenceUpdate {
+	if s != nil {
+		upu.SetUpdatedBy(*s)
+	}
+	return upu
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (upu *UserPreferenceUpdate) ClearUpdatedBy() *UserPreferenceUpdate {
+	upu.mutation.ClearUpdatedBy()
+	return upu
+}
+
+// SetNotifyEmail sets the "notify_email" field.
+func (upu *UserPreferenceUpdate) SetNotifyEmail(b bool) *UserPreferenceUpdate {
+	upu.mutation.SetNotifyEmail(b)
+	return upu
+}
+
+// SetNillableNotifyEmail sets the "notify_email" field if the given value is not nil.
+func (upu *UserPreferenceUpdate) SetNillableNotifyEmail(b *bool) *UserPreferenceUpdate {
+	if b != nil {
+		upu.SetNotifyEmail(*b)
+	}
+	return upu
+}
+
+// SetNotifySms sets the "notify_sms" field.
+func (upu *UserPreferenceUpdate) SetNotifySms(b bool) *UserPreferenceUpdate {
+	upu.mutation.SetNotifySms(b)
+	return upu
+}
+
+// SetNillableNotifySms sets the "notify_sms" field if the given value is not nil.
+func (upu *UserPreferenceUpdate) SetNillableNotifySms(b *bool) *UserPreferenceUpdate {
+	if b != nil {
+		upu.SetNotifySms(*b)
+	}
+	return upu
+}
+
+// SetNotifyMarketing sets the "notify_marketing" field.
+func (upu *UserPreferenceUpdate) SetNotifyMarketing(b bool) *UserPreferenceUpdate {
+	upu.mutation.SetNotifyMarketing(b)
+	return upu
+}
+
+// SetNillableNotifyMarketing sets the "notify_marketing" field if the given value is not nil.
+func (upu *UserPreferenceUpdate) SetNillableNotifyMarketing(b *bool) *UserPreferenceUpdate {
+	if b != nil {
+		upu.SetNotifyMarketing(*b)
+	}
+	return upu
+}
+
+// SetTheme sets the "theme" field.
+func (upu *UserPreferenceUpdate) SetTheme(u userpreference.Theme) *UserPreferenceUpdate {
+	upu.mutation.SetTheme(u)
+	return upu
+}
+
+// SetNillableTheme sets the "theme" field if the given value is not nil.
+func (upu *UserPreferenceUpdate) SetNillableTheme(u *userpreference.Theme) *UserPreferenceUpdate {
+	if u != nil {
+		upu.SetTheme(*u)
+	}
+	return upu
+}
+
+// SetLocale sets the "locale" field.
+func (upu *UserPreferenceUpdate) SetLocale(s string) *UserPreferenceUpdate {
+	upu.mutation.SetLocale(s)
+	return upu
+}
+
+// SetNillableLocale sets the "locale" field if the given value is not nil.
+func (upu *UserPreferenceUpdate) SetNillableLocale(s *string) *UserPreferenceUpdate {
+	if s != nil {
+		upu.SetLocale(*s)
+	}
+	return upu
+}
+
+// ClearLocale clears the value of the "locale" field.
+func (upu *UserPreferenceUpdate) ClearLocale() *UserPreferenceUpdate {
+	upu.mutation.ClearLocale()
+	return upu
+}
+
+// SetTimezone sets the "timezone" field.
+func (upu *UserPreferenceUpdate) SetTimezone(s string) *UserPreferenceUpdate {
+	upu.mutation.SetTimezone(s)
+	return upu
+}
+
+// SetNillableTimezone sets the "timezone" field if the given value is not nil.
+func (upu *UserPreferenceUpdate) SetNillableTimezone(s *string) *UserPreferenceUpdate {
+	if s != nil {
+		upu.SetTimezone(*s)
+	}
+	return upu
+}
+
+// ClearTimezone clears the value of the "timezone" field.
+func (upu *UserPreferenceUpdate) ClearTimezone() *UserPreferenceUpdate {
+	upu.mutation.ClearTimezone()
+	return upu
+}
+
+// Mutation returns the UserPreferenceMutation object of the builder.
+func (upu *UserPreferenceUpdate) Mutation() *UserPreferenceMutation {
+	return upu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (upu *UserPreferenceUpdate) Save(ctx context.Context) (int, error) {
+	if err := upu.defaults(); err != nil {
+		return 0, err
+	}
+	return withHooks(ctx, upu.sqlSave, upu.mutation, upu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (upu *UserPreferenceUpdate) SaveX(ctx context.Context) int {
+	affected, err := upu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (upu *UserPreferenceUpdate) Exec(ctx context.Context) error {
+	_, err := upu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (upu *UserPreferenceUpdate) ExecX(ctx context.Context) {
+	if err := upu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (upu *UserPreferenceUpdate) defaults() error {
+	if _, ok := upu.mutation.UpdatedAt(); !ok {
+		if userpreference.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized userpreference.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := userpreference.UpdateDefaultUpdatedAt()
+		upu.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (upu *UserPreferenceUpdate) check() error {
+	if v, ok := upu.mutation.Theme(); ok {
+		if err := userpreference.ThemeValidator(v); err != nil {
+			return &ValidationError{Name: "theme", err: fmt.Errorf(`ent: validator failed for field "UserPreference.theme": %w`, err)}
+		}
+	}
+	if upu.mutation.UserCleared() && len(upu.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "UserPreference.user"`)
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (upu *UserPreferenceUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *UserPreferenceUpdate {
+	upu.modifiers = append(upu.modifiers, modifiers...)
+	return upu
+}
+
+func (upu *UserPreferenceUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := upu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(userpreference.Table, userpreference.Columns, sqlgraph.NewFieldSpec(userpreference.FieldID, field.TypeString))
+	if ps := upu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := upu.mutation.UpdatedAt(); ok {
+		_spec.SetField(userpreference.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if upu.mutation.CreatedByCleared() {
+		_spec.ClearField(userpreference.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := upu.mutation.UpdatedBy(); ok {
+		_spec.SetField(userpreference.FieldUpdatedBy, field.TypeString, value)
+	}
+	if upu.mutation.UpdatedByCleared() {
+		_spec.ClearField(userpreference.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := upu.mutation.NotifyEmail(); ok {
+		_spec.SetField(userpreference.FieldNotifyEmail, field.TypeBool, value)
+	}
+	if value, ok := upu.mutation.NotifySms(); ok {
+		_spec.SetField(userpreference.FieldNotifySms, field.TypeBool, value)
+	}
+	if value, ok := upu.mutation.NotifyMarketing(); ok {
+		_spec.SetField(userpreference.FieldNotifyMarketing, field.TypeBool, value)
+	}
+	if value, ok := upu.mutation.Theme(); ok {
+		_spec.SetField(userpreference.FieldTheme, field.TypeEnum, value)
+	}
+	if value, ok := upu.mutation.Locale(); ok {
+		_spec.SetField(userpreference.FieldLocale, field.TypeString, value)
+	}
+	if upu.mutation.LocaleCleared() {
+		_spec.ClearField(userpreference.FieldLocale, field.TypeString)
+	}
+	if value, ok := upu.mutation.Timezone(); ok {
+		_spec.SetField(userpreference.FieldTimezone, field.TypeString, value)
+	}
+	if upu.mutation.TimezoneCleared() {
+		_spec.ClearField(userpreference.FieldTimezone, field.TypeString)
+	}
+	_spec.AddModifiers(upu.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, upu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{userpreference.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	upu.mutation.done = true
+	return n, nil
+}
+
+// UserPreferenceUpdateOne is the builder for updating a single UserPreference entity.
+type UserPreferenceUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *UserPreferenceMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (upuo *UserPreferenceUpdateOne) SetUpdatedAt(t time.Time) *UserPreferenceUpdateOne {
+	upuo.mutation.SetUpdatedAt(t)
+	return upuo
+}
+
+// SetUpdatedBy sets the "updated_by" field.
+func (upuo *UserPreferenceUpdateOne) SetUpdatedBy(s string) *UserPreferenceUpdateOne {
+	upuo.mutation.SetUpdatedBy(s)
+	return upuo
+}
+
+// SetNillableUpdatedBy sets the "updated_by" field if the given value is not nil.
+func (upuo *UserPreferenceUpdateOne) SetNillableUpdatedBy(s *string) *UserPreferenceUpdateOne {
+	if s != nil {
+		upuo.SetUpdatedBy(*s)
+	}
+	return upuo
+}
+
+// ClearUpdatedBy clears the value of the "updated_by" field.
+func (upuo *UserPreferenceUpdateOne) ClearUpdatedBy() *UserPreferenceUpdateOne {
+	upuo.mutation.ClearUpdatedBy()
+	return upuo
+}
+
+// SetNotifyEmail sets the "notify_email" field.
+func (upuo *UserPreferenceUpdateOne) SetNotifyEmail(b bool) *UserPreferenceUpdateOne {
+	upuo.mutation.SetNotifyEmail(b)
+	return upuo
+}
+
+// SetNillableNotifyEmail sets the "notify_email" field if the given value is not nil.
+func (upuo *UserPreferenceUpdateOne) SetNillableNotifyEmail(b *bool) *UserPreferenceUpdateOne {
+	if b != nil {
+		upuo.SetNotifyEmail(*b)
+	}
+	return upuo
+}
+
+// SetNotifySms sets the "notify_sms" field.
+func (upuo *UserPreferenceUpdateOne) SetNotifySms(b bool) *UserPreferenceUpdateOne {
+	upuo.mutation.SetNotifySms(b)
+	return upuo
+}
+
+// SetNillableNotifySms sets the "notify_sms" field if the given value is not nil.
+func (upuo *UserPreferenceUpdateOne) SetNillableNotifySms(b *bool) *UserPreferenceUpdateOne {
+	if b != nil {
+		upuo.SetNotifySms(*b)
+	}
+	return upuo
+}
+
+// SetNotifyMarketing sets the "notify_marketing" field.
+func (upuo *UserPreferenceUpdateOne) SetNotifyMarketing(b bool) *UserPreferenceUpdateOne {
+	upuo.mutation.SetNotifyMarketing(b)
+	return upuo
+}
+
+// SetNillableNotifyMarketing sets the "notify_marketing" field if the given value is not nil.
+func (upuo *UserPreferenceUpdateOne) SetNillableNotifyMarketing(b *bool) *UserPreferenceUpdateOne {
+	if b != nil {
+		upuo.SetNotifyMarketing(*b)
+	}
+	return upuo
+}
+
+// SetTheme sets the "theme" field.
+func (upuo *UserPreferenceUpdateOne) SetTheme(u userpreference.Theme) *UserPreferenceUpdateOne {
+	upuo.mutation.SetTheme(u)
+	return upuo
+}
+
+// SetNillableTheme sets the "theme" field if the given value is not nil.
+func (upuo *UserPreferenceUpdateOne) SetNillableTheme(u *userpreference.Theme) *UserPreferenceUpdateOne {
+	if u != nil {
+		upuo.SetTheme(*u)
+	}
+	return upuo
+}
+
+// SetLocale sets the "locale" field.
+func (upuo *UserPreferenceUpdateOne) SetLocale(s string) *UserPreferenceUpdateOne {
+	upuo.mutation.SetLocale(s)
+	return upuo
+}
+
+// SetNillableLocale sets the "locale" field if the given value is not nil.
+func (upuo *UserPreferenceUpdateOne) SetNillableLocale(s *string) *UserPreferenceUpdateOne {
+	if s != nil {
+		upuo.SetLocale(*s)
+	}
+	return upuo
+}
+
+// ClearLocale clears the value of the "locale" field.
+func (upuo *UserPreferenceUpdateOne) ClearLocale() *UserPreferenceUpdateOne {
+	upuo.mutation.ClearLocale()
+	return upuo
+}
+
+// SetTimezone sets the "timezone" field.
+func (upuo *UserPreferenceUpdateOne) SetTimezone(s string) *UserPreferenceUpdateOne {
+	upuo.mutation.SetTimezone(s)
+	return upuo
+}
+
+// SetNillableTimezone sets the "timezone" field if the given value is not nil.
+func (upuo *UserPreferenceUpdateOne) SetNillableTimezone(s *string) *UserPreferenceUpdateOne {
+	if s != nil {
+		upuo.SetTimezone(*s)
+	}
+	return upuo
+}
+
+// ClearTimezone clears the value of the "timezone" field.
+func (upuo *UserPreferenceUpdateOne) ClearTimezone() *UserPreferenceUpdateOne {
+	upuo.mutation.ClearTimezone()
+	return upuo
+}
+
+// Mutation returns the UserPreferenceMutation object of the builder.
+func (upuo *UserPreferenceUpdateOne) Mutation() *UserPreferenceMutation {
+	return upuo.mutation
+}
+
+// Where appends a list predicates to the UserPreferenceUpdate builder.
+func (upuo *UserPreferenceUpdateOne) Where(ps ...predicate.UserPreference) *UserPreferenceUpdateOne {
+	upuo.mutation.Where(ps...)
+	return upuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (upuo *UserPreferenceUpdateOne) Select(field string, fields ...string) *UserPreferenceUpdateOne {
+	upuo.fields = append([]string{field}, fields...)
+	return upuo
+}
+
+// Save executes the query and returns the updated UserPreference entity.
+func (upuo *UserPreferenceUpdateOne) Save(ctx context.Context) (*UserPreference, error) {
+	if err := upuo.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, upuo.sqlSave, upuo.mutation, upuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (upuo *UserPreferenceUpdateOne) SaveX(ctx context.Context) *UserPreference {
+	node, err := upuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (upuo *UserPreferenceUpdateOne) Exec(ctx context.Context) error {
+	_, err := upuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (upuo *UserPreferenceUpdateOne) ExecX(ctx context.Context) {
+	if err := upuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (upuo *UserPreferenceUpdateOne) defaults() error {
+	if _, ok := upuo.mutation.UpdatedAt(); !ok {
+		if userpreference.UpdateDefaultUpdatedAt == nil {
+			return fmt.Errorf("ent: uninitialized userpreference.UpdateDefaultUpdatedAt (forgotten import ent/runtime?)")
+		}
+		v := userpreference.UpdateDefaultUpdatedAt()
+		upuo.mutation.SetUpdatedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (upuo *UserPreferenceUpdateOne) check() error {
+	if v, ok := upuo.mutation.Theme(); ok {
+		if err := userpreference.ThemeValidator(v); err != nil {
+			return &ValidationError{Name: "theme", err: fmt.Errorf(`ent: validator failed for field "UserPreference.theme": %w`, err)}
+		}
+	}
+	if upuo.mutation.UserCleared() && len(upuo.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "UserPreference.user"`)
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (upuo *UserPreferenceUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *UserPreferenceUpdateOne {
+	upuo.modifiers = append(upuo.modifiers, modifiers...)
+	return upuo
+}
+
+func (upuo *UserPreferenceUpdateOne) sqlSave(ctx context.Context) (_node *UserPreference, err error) {
+	if err := upuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(userpreference.Table, userpreference.Columns, sqlgraph.NewFieldSpec(userpreference.FieldID, field.TypeString))
+	id, ok := upuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "UserPreference.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := upuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, userpreference.FieldID)
+		for _, f := range fields {
+			if !userpreference.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != userpreference.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := upuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := upuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(userpreference.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if upuo.mutation.CreatedByCleared() {
+		_spec.ClearField(userpreference.FieldCreatedBy, field.TypeString)
+	}
+	if value, ok := upuo.mutation.UpdatedBy(); ok {
+		_spec.SetField(userpreference.FieldUpdatedBy, field.TypeString, value)
+	}
+	if upuo.mutation.UpdatedByCleared() {
+		_spec.ClearField(userpreference.FieldUpdatedBy, field.TypeString)
+	}
+	if value, ok := upuo.mutation.NotifyEmail(); ok {
+		_spec.SetField(userpreference.FieldNotifyEmail, field.TypeBool, value)
+	}
+	if value, ok := upuo.mutation.NotifySms(); ok {
+		_spec.SetField(userpreference.FieldNotifySms, field.TypeBool, value)
+	}
+	if value, ok := upuo.mutation.NotifyMarketing(); ok {
+		_spec.SetField(userpreference.FieldNotifyMarketing, field.TypeBool, value)
+	}
+	if value, ok := upuo.mutation.Theme(); ok {
+		_spec.SetField(userpreference.FieldTheme, field.TypeEnum, value)
+	}
+	if value, ok := upuo.mutation.Locale(); ok {
+		_spec.SetField(userpreference.FieldLocale, field.TypeString, value)
+	}
+	if upuo.mutation.LocaleCleared() {
+		_spec.ClearField(userpreference.FieldLocale, field.TypeString)
+	}
+	if value, ok := upuo.mutation.Timezone(); ok {
+		_spec.SetField(userpreference.FieldTimezone, field.TypeString, value)
+	}
+	if upuo.mutation.TimezoneCleared() {
+		_spec.ClearField(userpreference.FieldTimezone, field.TypeString)
+	}
+	_spec.AddModifiers(upuo.modifiers...)
+	_node = &UserPreference{config: upuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, upuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{userpreference.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	upuo.mutation.done = true
+	return _node, nil
+}
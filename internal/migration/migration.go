@@ -0,0 +1,432 @@
+// Package migration applies and reverts versioned migrations, the schema and data changes
+// the automatic ent schema migration cannot make on its own. Applied versions are recorded in
+// the schema_migrations table. Each migration runs in a transaction; on MySQL, where DDL
+// cannot be rolled back, a migration is marked dirty while it runs, so one interrupted half
+// way stops further migrations until an operator has checked the database and recorded its
+// actual version with Force.
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Table is the table recording the applied migrations
+const Table = "schema_migrations"
+
+var (
+	// ErrDirty is returned when a migration was interrupted and the database must be checked
+	ErrDirty = errors.New("database is dirty")
+	// ErrIrreversible is returned when reverting a migration that has no Down function
+	ErrIrreversible = errors.New("migration cannot be reverted")
+	// ErrUnknownVersion is returned for a version no migration has
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+// Migration is a versioned change to the database
+type Migration struct {
+	// Version orders the migrations; versions must be unique and increasing
+	Version int
+	// Name describes the migration in status listings
+	Name string
+	// Dialects restricts the migration to these database dialects. On other databases the
+	// migration is recorded as applied without running. Empty means every dialect.
+	Dialects []string
+	// Up applies the migration
+	Up func(ctx context.Context, tx *sql.Tx) error
+	// Down reverts the migration; nil if it cannot be reverted
+	Down func(ctx context.Context, tx *sql.Tx) error
+}
+
+// Status is the state of a migration in the database
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Dirty is set when the migration was interrupted while being applied or reverted
+	Dirty bool
+	// Reversible is set when the migration has a Down function
+	Reversible bool
+}
+
+// Migrator applies and reverts migrations on a database
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+// New creates a migrator for a database of the given ent dialect. The migrations must be
+// sorted by version.
+func New(db *sql.DB, dialect string, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}
+}
+
+// Latest returns the version of the last migration, or 0 when there are none
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status lists every migration with its state, oldest first
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{
+			Version:    migration.Version,
+			Name:       migration.Name,
+			Reversible: migration.Down != nil,
+		}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = !row.dirty
+			status.AppliedAt = row.appliedAt.Time
+			status.Dirty = row.dirty
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Check returns ErrDirty if a migration was interrupted, and otherwise the number of
+// migrations not applied yet
+func (m *Migrator) Check(ctx context.Context) (pending int, err error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, migration := range m.migrations {
+		row, ok := applied[migration.Version]
+		if ok && row.dirty {
+			return 0, fmt.Errorf("%w: migration %d (%s) was interrupted", ErrDirty, migration.Version, migration.Name)
+		}
+		if !ok {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the migrations not applied yet up to and including version to, oldest first.
+// A to of 0 applies every migration.
+func (m *Migrator) Up(ctx context.Context, to int) error {
+	if to == 0 {
+		to = m.Latest()
+	}
+	if _, err := m.migration(to); err != nil && to != 0 {
+		return err
+	}
+	if _, err := m.Check(ctx); err != nil {
+		return err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > to {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Down reverts the applied migrations newer than version to, newest first. A to of 0
+// reverts every migration. Nothing is reverted if one of them cannot be.
+func (m *Migrator) Down(ctx context.Context, to int) error {
+	if _, err := m.migration(to); err != nil && to != 0 {
+		return err
+	}
+	if _, err := m.Check(ctx); err != nil {
+		return err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reverts []Migration
+	for i := len(m.migrations) - 1; i >= 0 && m.migrations[i].Version > to; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil && m.runs(migration) {
+			return fmt.Errorf("%w: %d (%s)", ErrIrreversible, migration.Version, migration.Name)
+		}
+		reverts = append(reverts, migration)
+	}
+
+	for _, migration := range reverts {
+		if err := m.revert(ctx, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Force records the database as migrated to version and clears the dirty state, without
+// running any migration. Operators use it after repairing an interrupted migration by hand.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	if _, err := m.migration(version); err != nil && version != 0 {
+		return err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, migration := range m.migrations {
+		row, ok := applied[migration.Version]
+		switch {
+		case migration.Version > version && ok:
+			err = m.unrecord(ctx, tx, migration.Version)
+		case migration.Version > version:
+		case !ok:
+			err = m.record(ctx, tx, migration, false)
+		case row.dirty:
+			err = m.setDirty(ctx, tx, migration.Version, false)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration versions: %w", err)
+	}
+	return nil
+}
+
+// apply runs a migration and records it as applied
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	if !m.runs(migration) {
+		return m.record(ctx, m.db, migration, false)
+	}
+
+	if m.transactionalDDL() {
+		err := m.inTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			if err := migration.Up(ctx, tx); err != nil {
+				return err
+			}
+			return m.record(ctx, tx, migration, false)
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+		}
+	} else {
+		// MySQL 的 DDL 会隐式提交，中途失败时无法回滚，先标记为 dirty
+		if err := m.record(ctx, m.db, migration, true); err != nil {
+			return err
+		}
+		if err := m.inTx(ctx, migration.Up); err != nil {
+			return fmt.Errorf("migration %d (%s) failed, check the database and run migrate force: %w",
+				migration.Version, migration.Name, err)
+		}
+		if err := m.setDirty(ctx, m.db, migration.Version, false); err != nil {
+			return err
+		}
+	}
+	logger.Infof("Applied migration %d (%s)", migration.Version, migration.Name)
+	return nil
+}
+
+// revert runs the Down function of a migration and records it as not applied
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	switch {
+	case !m.runs(migration):
+		if err := m.unrecord(ctx, m.db, migration.Version); err != nil {
+			return err
+		}
+	case m.transactionalDDL():
+		err := m.inTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			if err := migration.Down(ctx, tx); err != nil {
+				return err
+			}
+			return m.unrecord(ctx, tx, migration.Version)
+		})
+		if err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+		}
+	default:
+		if err := m.setDirty(ctx, m.db, migration.Version, true); err != nil {
+			return err
+		}
+		if err := m.inTx(ctx, migration.Down); err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed, check the database and run migrate force: %w",
+				migration.Version, migration.Name, err)
+		}
+		if err := m.unrecord(ctx, m.db, migration.Version); err != nil {
+			return err
+		}
+	}
+	logger.Infof("Reverted migration %d (%s)", migration.Version, migration.Name)
+	return nil
+}
+
+// inTx runs fn in a transaction
+func (m *Migrator) inTx(ctx context.Context, fn func(context.Context, *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// transactionalDDL reports whether schema changes roll back with the transaction they run in,
+// so a failed migration leaves nothing behind. MySQL commits each DDL statement at once.
+func (m *Migrator) transactionalDDL() bool {
+	return m.dialect != dialect.MySQL
+}
+
+// runs reports whether a migration runs on the database's dialect
+func (m *Migrator) runs(migration Migration) bool {
+	if len(migration.Dialects) == 0 {
+		return true
+	}
+	for _, dialect := range migration.Dialects {
+		if dialect == m.dialect {
+			return true
+		}
+	}
+	return false
+}
+
+// migration returns the migration with a version
+func (m *Migrator) migration(version int) (Migration, error) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, nil
+		}
+	}
+	return Migration{}, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+}
+
+// appliedRow is a row of the migrations table
+type appliedRow struct {
+	dirty     bool
+	appliedAt sql.NullTime
+}
+
+// applied returns the recorded migrations by version
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedRow, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	query, args := entsql.Dialect(m.dialect).
+		Select("version", "dirty", "applied_at").
+		From(entsql.Table(Table)).
+		Query()
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedRow)
+	for rows.Next() {
+		var version int
+		var row appliedRow
+		if err := rows.Scan(&version, &row.dirty, &row.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied[version] = row
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// record inserts the row of a migration
+func (m *Migrator) record(ctx context.Context, db execer, migration Migration, dirty bool) error {
+	query, args := entsql.Dialect(m.dialect).
+		Insert(Table).
+		Columns("version", "name", "dirty", "applied_at").
+		Values(migration.Version, migration.Name, dirty, time.Now().UTC()).
+		Query()
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+// unrecord deletes the row of a migration
+func (m *Migrator) unrecord(ctx context.Context, db execer, version int) error {
+	query, args := entsql.Dialect(m.dialect).
+		Delete(Table).
+		Where(entsql.EQ("version", version)).
+		Query()
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to record reverted migration %d: %w", version, err)
+	}
+	return nil
+}
+
+// setDirty updates the dirty state of a recorded migration
+func (m *Migrator) setDirty(ctx context.Context, db execer, version int, dirty bool) error {
+	query, args := entsql.Dialect(m.dialect).
+		Update(Table).
+		Set("dirty", dirty).
+		Where(entsql.EQ("version", version)).
+		Query()
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", version, err)
+	}
+	return nil
+}
+
+// ensureTable creates the migrations table if it does not exist
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+Table+` (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		dirty BOOLEAN NOT NULL,
+		applied_at TIMESTAMP NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create the %s table: %w", Table, err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package model
+
+// ActivityFilter holds the query parameters for listing the activities of a user; Page starts at 1
+type ActivityFilter struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ActivityResponse is the activity model returned to clients
+type ActivityResponse struct {
+	ID             string `json:"id"`
+	Action         string `json:"action"`
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	IP             string `json:"ip,omitempty"`
+	UserAgent      string `json:"user_agent,omitempty"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// ActivityListResponse is a page of the activities of a user, newest first
+type ActivityListResponse struct {
+	Activities []ActivityResponse `json:"activities"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+}
@@ -0,0 +1,36 @@
+package model
+
+// CreateAppCredentialInput represents the data required to register an app that signs
+// requests with its own secret
+type CreateAppCredentialInput struct {
+	Name string `json:"name" binding:"required"`
+	// RateLimit is the maximum number of requests per RateWindow seconds, 0 for no limit
+	RateLimit  int `json:"rate_limit" binding:"omitempty,min=0"`
+	RateWindow int `json:"rate_window" binding:"omitempty,min=1"`
+}
+
+// UpdateAppCredentialInput represents the rate limit changes of an app credential
+type UpdateAppCredentialInput struct {
+	RateLimit  *int `json:"rate_limit" binding:"omitempty,min=0"`
+	RateWindow *int `json:"rate_window" binding:"omitempty,min=1"`
+}
+
+// AppCredentialResponse is the app credential model returned to clients
+type AppCredentialResponse struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	AppKey     string  `json:"app_key"`
+	Status     string  `json:"status"`
+	RateLimit  int     `json:"rate_limit"`
+	RateWindow int     `json:"rate_window"`
+	RevokedAt  *string `json:"revoked_at,omitempty"`
+	CreatedBy  string  `json:"created_by,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// AppCredentialSecretResponse contains a newly issued app secret.
+// The secret is only returned once and cannot be recovered later.
+type AppCredentialSecretResponse struct {
+	AppCredential AppCredentialResponse `json:"app_credential"`
+	AppSecret     string                `json:"app_secret"`
+}
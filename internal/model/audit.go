@@ -0,0 +1,38 @@
+package model
+
+// AuditEventFilter holds the query parameters for listing audit events.
+// From and To are RFC 3339 timestamps; Page starts at 1.
+type AuditEventFilter struct {
+	Action   string `form:"action" binding:"omitempty"`
+	ActorID  string `form:"actor_id" binding:"omitempty"`
+	Subject  string `form:"subject" binding:"omitempty"`
+	Outcome  string `form:"outcome" binding:"omitempty,oneof=success failure"`
+	IP       string `form:"ip" binding:"omitempty"`
+	From     string `form:"from" binding:"omitempty"`
+	To       string `form:"to" binding:"omitempty"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=200"`
+}
+
+// AuditEventResponse is the audit event model returned to clients
+type AuditEventResponse struct {
+	ID             string `json:"id"`
+	Action         string `json:"action"`
+	ActorID        string `json:"actor_id,omitempty"`
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	Subject        string `json:"subject,omitempty"`
+	Details        string `json:"details,omitempty"`
+	Outcome        string `json:"outcome"`
+	Status         int    `json:"status,omitempty"`
+	IP             string `json:"ip,omitempty"`
+	UserAgent      string `json:"user_agent,omitempty"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// AuditEventListResponse is a page of audit events, newest first
+type AuditEventListResponse struct {
+	Events   []AuditEventResponse `json:"events"`
+	Total    int                  `json:"total"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+}
@@ -0,0 +1,45 @@
+package model
+
+// CreateGroupInput represents the data required to create a group
+type CreateGroupInput struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description" binding:"omitempty"`
+}
+
+// UpdateGroupInput represents the data allowed to update a group
+type UpdateGroupInput struct {
+	Name        string  `json:"name" binding:"omitempty"`
+	Description *string `json:"description" binding:"omitempty"`
+}
+
+// AddGroupMemberInput represents the data required to add a user to a group.
+// Role defaults to member.
+type AddGroupMemberInput struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"omitempty,oneof=owner admin member"`
+}
+
+// UpdateGroupMemberInput represents the data required to change a member's role
+type UpdateGroupMemberInput struct {
+	Role string `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+// GroupResponse is the group model returned to clients. Role is the requesting user's
+// role in the group, when they are a member.
+type GroupResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Role        string `json:"role,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// GroupMemberResponse is a member of a group returned to clients
+type GroupMemberResponse struct {
+	UserID   string `json:"user_id"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joined_at"`
+}
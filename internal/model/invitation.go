@@ -0,0 +1,28 @@
+package model
+
+// CreateInvitationInput represents the data required to invite a user
+type CreateInvitationInput struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"omitempty"`
+	// ExpiresAt is an RFC 3339 timestamp; the configured invitation TTL is used when empty
+	ExpiresAt string `json:"expires_at" binding:"omitempty"`
+}
+
+// InvitationResponse is the invitation model returned to clients
+type InvitationResponse struct {
+	ID             string  `json:"id"`
+	Email          string  `json:"email"`
+	Role           string  `json:"role"`
+	ExpiresAt      string  `json:"expires_at"`
+	AcceptedAt     *string `json:"accepted_at,omitempty"`
+	AcceptedUserID string  `json:"accepted_user_id,omitempty"`
+	CreatedBy      string  `json:"created_by,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// InvitationTokenResponse contains a newly created invitation.
+// The plaintext token is only returned once and cannot be recovered later.
+type InvitationTokenResponse struct {
+	Invitation InvitationResponse `json:"invitation"`
+	Token      string             `json:"token"`
+}
@@ -0,0 +1,82 @@
+package model
+
+// CreateOAuthClientInput represents the data required to register an OAuth client.
+// Public clients (single-page and mobile apps) get no secret and rely on PKCE alone.
+type CreateOAuthClientInput struct {
+	Name         string   `json:"name" binding:"required"`
+	RedirectURIs []string `json:"redirect_uris" binding:"required,min=1,dive,url"`
+	Scopes       []string `json:"scopes" binding:"omitempty"`
+	Public       bool     `json:"public"`
+	// FirstParty skips the consent screen for this client
+	FirstParty bool `json:"first_party"`
+}
+
+// OAuthClientResponse is the OAuth client model returned to clients
+type OAuthClientResponse struct {
+	ClientID     string   `json:"client_id"`
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+	Public       bool     `json:"public"`
+	FirstParty   bool     `json:"first_party"`
+	CreatedBy    string   `json:"created_by,omitempty"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+// OAuthClientSecretResponse contains a newly registered client.
+// The plaintext secret is only returned once and cannot be recovered later.
+type OAuthClientSecretResponse struct {
+	Client       OAuthClientResponse `json:"client"`
+	ClientSecret string              `json:"client_secret,omitempty"`
+}
+
+// OAuthAuthorizeInput holds the parameters of an authorization request (RFC 6749 section 4.1.1, RFC 7636)
+type OAuthAuthorizeInput struct {
+	ResponseType        string `form:"response_type" json:"response_type"`
+	ClientID            string `form:"client_id" json:"client_id"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+// OAuthConsentInput is the user's answer on the consent screen for an authorization request
+type OAuthConsentInput struct {
+	OAuthAuthorizeInput
+	Approve bool `json:"approve"`
+}
+
+// OAuthConsentResponse asks the user to approve an authorization request.
+// The consent screen shows the client and scopes and posts the answer back.
+type OAuthConsentResponse struct {
+	ConsentRequired bool                `json:"consent_required"`
+	Client          OAuthClientResponse `json:"client"`
+	Scopes          []string            `json:"scopes"`
+	Request         OAuthAuthorizeInput `json:"request"`
+}
+
+// OAuthRedirectResponse tells the client where to send the user agent to finish the authorization
+type OAuthRedirectResponse struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// OAuthTokenInput holds the form parameters of a token request (RFC 6749 sections 4.1.3 and 6)
+type OAuthTokenInput struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// OAuthTokenResponse is the successful token response (RFC 6749 section 5.1)
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
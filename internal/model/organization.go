@@ -0,0 +1,23 @@
+package model
+
+// CreateOrganizationInput represents the data required to create an organization
+type CreateOrganizationInput struct {
+	Name string `json:"name" binding:"required"`
+	Slug string `json:"slug" binding:"required"`
+}
+
+// UpdateOrganizationInput represents the data allowed to update an organization
+type UpdateOrganizationInput struct {
+	Name   string `json:"name" binding:"omitempty"`
+	Active *bool  `json:"active" binding:"omitempty"`
+}
+
+// OrganizationResponse is the organization model returned to clients
+type OrganizationResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Active    bool   `json:"active"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
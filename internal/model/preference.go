@@ -0,0 +1,33 @@
+package model
+
+// NotificationPreferences are the channels a user receives notifications on
+type NotificationPreferences struct {
+	Email     bool `json:"email"`
+	SMS       bool `json:"sms"`
+	Marketing bool `json:"marketing"`
+}
+
+// UpdateNotificationPreferencesInput changes the notification channels; omitted channels are kept
+type UpdateNotificationPreferencesInput struct {
+	Email     *bool `json:"email" binding:"omitempty"`
+	SMS       *bool `json:"sms" binding:"omitempty"`
+	Marketing *bool `json:"marketing" binding:"omitempty"`
+}
+
+// UpdatePreferencesInput represents the preferences a user can change; omitted fields are
+// kept. An empty locale follows the client again, and an empty timezone means UTC.
+type UpdatePreferencesInput struct {
+	Notifications *UpdateNotificationPreferencesInput `json:"notifications" binding:"omitempty"`
+	Theme         *string                             `json:"theme" binding:"omitempty,oneof=system light dark"`
+	Locale        *string                             `json:"locale" binding:"omitempty"`
+	Timezone      *string                             `json:"timezone" binding:"omitempty"`
+}
+
+// PreferencesResponse is the preferences model returned to clients
+type PreferencesResponse struct {
+	Notifications NotificationPreferences `json:"notifications"`
+	Theme         string                  `json:"theme"`
+	Locale        string                  `json:"locale,omitempty"`
+	Timezone      string                  `json:"timezone,omitempty"`
+	UpdatedAt     string                  `json:"updated_at,omitempty"`
+}
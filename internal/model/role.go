@@ -0,0 +1,32 @@
+package model
+
+// CreateRoleInput represents the data required to create a role
+type CreateRoleInput struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description" binding:"omitempty"`
+	Permissions []string `json:"permissions" binding:"omitempty"`
+}
+
+// UpdateRoleInput represents the data allowed to update a role.
+// Permissions replaces the role's permissions when set.
+type UpdateRoleInput struct {
+	Description *string  `json:"description" binding:"omitempty"`
+	Permissions []string `json:"permissions" binding:"omitempty"`
+}
+
+// RoleResponse is the role model returned to clients
+type RoleResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Builtin     bool     `json:"builtin"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// PermissionResponse is the permission model returned to clients
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
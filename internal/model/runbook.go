@@ -0,0 +1,19 @@
+package model
+
+// RunRunbookActionInput holds the parameters of a runbook action
+type RunRunbookActionInput struct {
+	Params map[string]string `json:"params"`
+}
+
+// RunbookActionResponse describes an available runbook action
+type RunbookActionResponse struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Params      map[string]string `json:"params,omitempty"`
+}
+
+// RunbookResultResponse is the outcome of a runbook action
+type RunbookResultResponse struct {
+	Action string `json:"action"`
+	Result string `json:"result"`
+}
@@ -0,0 +1,39 @@
+package model
+
+// CreateServiceAccountInput represents the data required to create a service account
+type CreateServiceAccountInput struct {
+	Name         string   `json:"name" binding:"required"`
+	Description  string   `json:"description" binding:"omitempty"`
+	Scopes       []string `json:"scopes" binding:"required,min=1"`
+	AllowedCIDRs []string `json:"allowed_cidrs" binding:"required,min=1"`
+	ExpiresAt    string   `json:"expires_at" binding:"required"`
+}
+
+// RotateServiceAccountInput represents the data required to rotate a service account token
+type RotateServiceAccountInput struct {
+	ExpiresAt string `json:"expires_at" binding:"required"`
+}
+
+// ServiceAccountResponse is the service account model returned to clients
+type ServiceAccountResponse struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	TokenPrefix  string   `json:"token_prefix"`
+	Scopes       []string `json:"scopes"`
+	AllowedCIDRs []string `json:"allowed_cidrs"`
+	ExpiresAt    string   `json:"expires_at"`
+	RevokedAt    *string  `json:"revoked_at,omitempty"`
+	LastUsedAt   *string  `json:"last_used_at,omitempty"`
+	LastUsedIP   string   `json:"last_used_ip,omitempty"`
+	UsageCount   int64    `json:"usage_count"`
+	CreatedBy    string   `json:"created_by,omitempty"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+// ServiceAccountTokenResponse contains a newly issued service account token.
+// The plaintext token is only returned once and cannot be recovered later.
+type ServiceAccountTokenResponse struct {
+	ServiceAccount ServiceAccountResponse `json:"service_account"`
+	Token          string                 `json:"token"`
+}
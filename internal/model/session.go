@@ -0,0 +1,20 @@
+package model
+
+// SessionResponse is a login session of a user returned to clients
+type SessionResponse struct {
+	ID             string `json:"id"`
+	IP             string `json:"ip,omitempty"`
+	UserAgent      string `json:"user_agent,omitempty"`
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	ClientID       string `json:"client_id,omitempty"`
+	// Current marks the session of the token making the request
+	Current     bool   `json:"current"`
+	SignedInAt  string `json:"signed_in_at,omitempty"`
+	RefreshedAt string `json:"refreshed_at"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// SessionListResponse lists the active sessions of a user, most recently refreshed first
+type SessionListResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
@@ -0,0 +1,16 @@
+package model
+
+// UpdateSettingInput represents the data required to create or update a setting
+type UpdateSettingInput struct {
+	Value       string `json:"value"`
+	Description string `json:"description" binding:"omitempty"`
+}
+
+// SettingResponse is the setting model returned to clients
+type SettingResponse struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	UpdatedBy   string `json:"updated_by,omitempty"`
+	UpdatedAt   string `json:"updated_at"`
+}
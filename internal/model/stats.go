@@ -0,0 +1,37 @@
+package model
+
+// UserStatsFilter holds the query parameters of the user statistics; Days is the number of
+// days, including today, to count signups over
+type UserStatsFilter struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=365"`
+}
+
+// DailyCount is the number of events on a day, formatted as YYYY-MM-DD in UTC
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// LastLoginDistribution counts users by when they last logged in. Each user is counted once,
+// in the most recent period their last login falls in.
+type LastLoginDistribution struct {
+	LastDay     int `json:"last_day"`
+	LastWeek    int `json:"last_week"`
+	LastMonth   int `json:"last_month"`
+	LastQuarter int `json:"last_quarter"`
+	Older       int `json:"older"`
+	Never       int `json:"never"`
+}
+
+// UserStatsResponse summarizes the users. Users holding several roles are counted under each.
+type UserStatsResponse struct {
+	Total     int                   `json:"total"`
+	Active    int                   `json:"active"`
+	Inactive  int                   `json:"inactive"`
+	ByRole    map[string]int        `json:"by_role"`
+	Days      int                   `json:"days"`
+	Signups   []DailyCount          `json:"signups"`
+	LastLogin LastLoginDistribution `json:"last_login"`
+	// GeneratedAt is when the statistics were computed; they may be served from a cache
+	GeneratedAt string `json:"generated_at"`
+}
@@ -2,24 +2,66 @@ package model
 
 // CreateUserInput represents the data required to create a new user
 type CreateUserInput struct {
-	Email    string `json:"email" binding:"required,email"`
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required,min=8"`
-	Role     string `json:"role" binding:"omitempty"`
+	Email    string   `json:"email" binding:"required,email"`
+	Username string   `json:"username" binding:"required"`
+	Password string   `json:"password" binding:"required"`
+	Roles    []string `json:"roles" binding:"omitempty"`
+	// InviteToken registers through an invitation, which also sets the roles
+	InviteToken string `json:"invite_token" binding:"omitempty"`
+	// MustChangePassword is only set for users provisioned by an admin
+	MustChangePassword bool `json:"-"`
+}
+
+// ChangeRolesInput lists the roles to grant to and revoke from a user; at least one is required
+type ChangeRolesInput struct {
+	Grant  []string `json:"grant" binding:"omitempty"`
+	Revoke []string `json:"revoke" binding:"omitempty"`
+}
+
+// AdminCreateUserInput represents the data an admin provisions a user with. Password is a
+// temporary password handed to the user; with MustChangePassword set, the user has to
+// replace it before doing anything else.
+type AdminCreateUserInput struct {
+	Email              string   `json:"email" binding:"required,email"`
+	Username           string   `json:"username" binding:"required"`
+	Password           string   `json:"password" binding:"required"`
+	Roles              []string `json:"roles" binding:"omitempty"`
+	MustChangePassword bool     `json:"must_change_password"`
 }
 
 // UpdateUserInput represents the data that can be updated for a user
 type UpdateUserInput struct {
 	Username  string  `json:"username" binding:"omitempty"`
 	AvatarURL *string `json:"avatar_url" binding:"omitempty"`
-	Active    *bool   `json:"active" binding:"omitempty"`
-	Role      string  `json:"role" binding:"omitempty"`
+	Phone     *string `json:"phone" binding:"omitempty"`
+	// Roles replaces the user's roles when set
+	Roles []string `json:"roles" binding:"omitempty"`
 }
 
 // LoginInput represents the data required for user login
 type LoginInput struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	RememberMe bool   `json:"remember_me"`
+}
+
+// RequestLoginCodeInput represents the data required to request an SMS login code
+type RequestLoginCodeInput struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// VerifyLoginCodeInput represents the data required to log in with an SMS code
+type VerifyLoginCodeInput struct {
+	Phone      string `json:"phone" binding:"required"`
+	Code       string `json:"code" binding:"required"`
+	RememberMe bool   `json:"remember_me"`
+}
+
+// TokenExchangeInput represents an external token to exchange for a token pair, following
+// the naming of RFC 8693. Only JWTs are accepted, so the token type may be omitted.
+type TokenExchangeInput struct {
+	SubjectToken     string `json:"subject_token" binding:"required"`
+	SubjectTokenType string `json:"subject_token_type" binding:"omitempty,oneof=urn:ietf:params:oauth:token-type:jwt urn:ietf:params:oauth:token-type:id_token urn:ietf:params:oauth:token-type:access_token"`
 }
 
 // RefreshTokenInput represents the data required to refresh a token
@@ -27,33 +69,99 @@ type RefreshTokenInput struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// LogoutInput represents the optional data for logging out.
+// The refresh token of the session is revoked along with the access token when given.
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token" binding:"omitempty"`
+}
+
 // ChangePasswordInput represents the data required to change a password
 type ChangePasswordInput struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
-	NewPassword     string `json:"new_password" binding:"required,min=8"`
+	NewPassword     string `json:"new_password" binding:"required"`
 }
 
 // UserResponse is the model returned to clients
 type UserResponse struct {
-	ID        string  `json:"id"`
-	Email     string  `json:"email"`
-	Username  string  `json:"username"`
-	Role      string  `json:"role"`
-	Active    bool    `json:"active"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	ID        string   `json:"id"`
+	Email     string   `json:"email"`
+	Username  string   `json:"username"`
+	Roles     []string `json:"roles"`
+	Active    bool     `json:"active"`
+	AvatarURL *string  `json:"avatar_url,omitempty"`
+	Phone     *string  `json:"phone,omitempty"`
+	// PhoneVerified means the user proved they receive SMS at Phone
+	PhoneVerified bool   `json:"phone_verified"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+	// LastLogin, LastSeen, Online and MustChangePassword are only included in admin responses
+	LastLogin          *string `json:"last_login,omitempty"`
+	LastSeen           *string `json:"last_seen,omitempty"`
+	Online             *bool   `json:"online,omitempty"`
+	MustChangePassword *bool   `json:"must_change_password,omitempty"`
+}
+
+// UserFilter holds the query parameters for listing users; Page starts at 1.
+// Active and PhoneVerified filter only when set.
+type UserFilter struct {
+	Email         string `form:"email" binding:"omitempty"`
+	Role          string `form:"role" binding:"omitempty"`
+	Active        *bool  `form:"active" binding:"omitempty"`
+	Phone         string `form:"phone" binding:"omitempty"`
+	PhoneVerified *bool  `form:"phone_verified" binding:"omitempty"`
+	Page          int    `form:"page" binding:"omitempty,min=1"`
+	PageSize      int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// UserListResponse is a page of users, oldest first
+type UserListResponse struct {
+	Users    []UserResponse `json:"users"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
+
+// VerifyPhoneInput represents the code sent to verify the current user's phone number
+type VerifyPhoneInput struct {
+	Code string `json:"code" binding:"required"`
 }
 
 // AuthResponse contains authentication response data
 type AuthResponse struct {
 	User         UserResponse `json:"user"`
-	AccessToken  string       `json:"access_token"`
-	RefreshToken string       `json:"refresh_token"`
+	AccessToken  string       `json:"access_token,omitempty"`
+	RefreshToken string       `json:"refresh_token,omitempty"`
 	ExpiresIn    int64        `json:"expires_in"`
+	// MustChangePassword means the tokens only allow changing the password until it is changed
+	MustChangePassword bool `json:"must_change_password,omitempty"`
+}
+
+// GuestTokenResponse contains a guest access token. Guests get no refresh token and
+// request a new token once it expires.
+type GuestTokenResponse struct {
+	GuestID     string `json:"guest_id"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
 }
 
 // NonceResponse contains the nonce generated by the server
 type NonceResponse struct {
 	Nonce string `json:"nonce"`
 }
+
+// CSRFTokenResponse contains the CSRF token and the header to send it in
+type CSRFTokenResponse struct {
+	CSRFToken  string `json:"csrf_token"`
+	HeaderName string `json:"header_name"`
+}
+
+// ChangeEmailInput represents the data required to request an email change
+type ChangeEmailInput struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// EmailChangeTokenInput carries a confirmation or rollback token from an email link
+type EmailChangeTokenInput struct {
+	Token string `json:"token" binding:"required"`
+}
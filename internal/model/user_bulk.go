@@ -0,0 +1,54 @@
+package model
+
+// ImportUserRow is a user read from an import file. Password is required for new users
+// and ignored when an existing user is updated.
+type ImportUserRow struct {
+	Email    string   `json:"email"`
+	Username string   `json:"username"`
+	Password string   `json:"password,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	Active   *bool    `json:"active,omitempty"`
+	Phone    *string  `json:"phone,omitempty"`
+}
+
+// ExportUserRow is a user written to an export file. Exported files can be imported
+// again once a password column is added for the users to create.
+type ExportUserRow struct {
+	ID        string   `json:"id"`
+	Email     string   `json:"email"`
+	Username  string   `json:"username"`
+	Roles     []string `json:"roles"`
+	Active    bool     `json:"active"`
+	Phone     *string  `json:"phone,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// ImportRowError describes why a row of an import was not applied.
+// Rows are numbered from 1, not counting the CSV header.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportUsersReport summarizes a user import
+type ImportUsersReport struct {
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Skipped int              `json:"skipped"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors,omitempty"`
+}
+
+// ImportJobResponse describes an asynchronous user import
+type ImportJobResponse struct {
+	ID         string             `json:"id"`
+	Status     string             `json:"status"`
+	Rows       int                `json:"rows"`
+	CreatedAt  string             `json:"created_at"`
+	FinishedAt string             `json:"finished_at,omitempty"`
+	Report     *ImportUsersReport `json:"report,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
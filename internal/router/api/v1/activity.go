@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/activity"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type ActivityController struct {
+	activityService activity.ActivityService
+}
+
+func NewActivityController(activityService activity.ActivityService) *ActivityController {
+	return &ActivityController{
+		activityService: activityService,
+	}
+}
+
+// ListMyActivity lists the activities of the current user, newest first
+func (c *ActivityController) ListMyActivity(ctx *gin.Context) {
+	c.listActivities(ctx, ctx.GetString("userID"))
+}
+
+// ListUserActivity lists the activities of a user, newest first (admin only)
+func (c *ActivityController) ListUserActivity(ctx *gin.Context) {
+	c.listActivities(ctx, ctx.Param("id"))
+}
+
+// listActivities writes a page of the activities of a user
+func (c *ActivityController) listActivities(ctx *gin.Context, userID string) {
+	var filter model.ActivityFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, bindErrorResponse(err))
+		return
+	}
+
+	activities, total, err := c.activityService.ListActivities(ctx, userID, filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, activity.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, pageSize := activity.Pagination(filter)
+	responses := make([]model.ActivityResponse, 0, len(activities))
+	for _, a := range activities {
+		responses = append(responses, toActivityResponse(a))
+	}
+
+	ctx.JSON(http.StatusOK, model.ActivityListResponse{
+		Activities: responses,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+	})
+}
+
+// RegisterRoutes registers the activity routes. requirePermission builds the middleware
+// that checks the caller's role grants a permission.
+func (c *ActivityController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, requirePermission func(string) gin.HandlerFunc) {
+	router.GET("/users/me/activity", authMiddleware, c.ListMyActivity)
+	router.GET("/admin/users/:id/activity", authMiddleware, requirePermission(rbac.PermUserRead), middleware.RequireScopes("users:read"), c.ListUserActivity)
+}
+
+// toActivityResponse converts an activity to its response model
+func toActivityResponse(a *ent.Activity) model.ActivityResponse {
+	return model.ActivityResponse{
+		ID:             a.ID,
+		Action:         a.Action,
+		ImpersonatedBy: a.ImpersonatedBy,
+		IP:             a.IP,
+		UserAgent:      a.UserAgent,
+		CreatedAt:      a.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/appcredential"
+)
+
+type AppCredentialController struct {
+	appCredentialService appcredential.AppCredentialService
+}
+
+func NewAppCredentialController(appCredentialService appcredential.AppCredentialService) *AppCredentialController {
+	return &AppCredentialController{
+		appCredentialService: appCredentialService,
+	}
+}
+
+// CreateAppCredential registers an app and returns its secret once (admin only)
+func (c *AppCredentialController) CreateAppCredential(ctx *gin.Context) {
+	var input model.CreateAppCredentialInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	credential, secret, err := c.appCredentialService.CreateAppCredential(ctx, input, ctx.GetString("userID"))
+	if err != nil {
+		ctx.JSON(appCredentialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.AppCredentialSecretResponse{
+		AppCredential: toAppCredentialResponse(credential),
+		AppSecret:     secret,
+	})
+}
+
+// ListAppCredentials lists all app credentials (admin only)
+func (c *AppCredentialController) ListAppCredentials(ctx *gin.Context) {
+	credentials, err := c.appCredentialService.ListAppCredentials(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.AppCredentialResponse, 0, len(credentials))
+	for _, credential := range credentials {
+		responses = append(responses, toAppCredentialResponse(credential))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// GetAppCredential retrieves an app credential by ID (admin only)
+func (c *AppCredentialController) GetAppCredential(ctx *gin.Context) {
+	credential, err := c.appCredentialService.GetAppCredential(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(appCredentialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toAppCredentialResponse(credential))
+}
+
+// UpdateAppCredential changes the rate limit of an app (admin only)
+func (c *AppCredentialController) UpdateAppCredential(ctx *gin.Context) {
+	var input model.UpdateAppCredentialInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	credential, err := c.appCredentialService.UpdateAppCredential(ctx, ctx.Param("id"), input)
+	if err != nil {
+		ctx.JSON(appCredentialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toAppCredentialResponse(credential))
+}
+
+// RotateAppCredential issues a new secret for an app, invalidating the old one (admin only)
+func (c *AppCredentialController) RotateAppCredential(ctx *gin.Context) {
+	credential, secret, err := c.appCredentialService.RotateAppCredential(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(appCredentialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.AppCredentialSecretResponse{
+		AppCredential: toAppCredentialResponse(credential),
+		AppSecret:     secret,
+	})
+}
+
+// RevokeAppCredential revokes an app (admin only)
+func (c *AppCredentialController) RevokeAppCredential(ctx *gin.Context) {
+	if err := c.appCredentialService.RevokeAppCredential(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(appCredentialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "app credential revoked successfully"})
+}
+
+// RegisterRoutes registers the app credential routes
+func (c *AppCredentialController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/app-credentials")
+	adminRoutes.Use(authMiddleware, adminMiddleware)
+	{
+		adminRoutes.POST("", c.CreateAppCredential)
+		adminRoutes.GET("", c.ListAppCredentials)
+		adminRoutes.GET("/:id", c.GetAppCredential)
+		adminRoutes.PUT("/:id", c.UpdateAppCredential)
+		adminRoutes.POST("/:id/rotate", c.RotateAppCredential)
+		adminRoutes.DELETE("/:id", c.RevokeAppCredential)
+	}
+}
+
+// appCredentialErrorStatus maps app credential service errors to HTTP status codes
+func appCredentialErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, appcredential.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, appcredential.ErrNameExists), errors.Is(err, appcredential.ErrRevoked):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// toAppCredentialResponse converts an app credential entity to its response model
+func toAppCredentialResponse(credential *ent.AppCredential) model.AppCredentialResponse {
+	response := model.AppCredentialResponse{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		AppKey:     credential.AppKey,
+		Status:     string(credential.Status),
+		RateLimit:  credential.RateLimit,
+		RateWindow: credential.RateWindow,
+		CreatedBy:  credential.CreatedBy,
+		CreatedAt:  credential.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if credential.RevokedAt != nil {
+		revokedAt := credential.RevokedAt.UTC().Format(time.RFC3339)
+		response.RevokedAt = &revokedAt
+	}
+	return response
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/audit"
+)
+
+type AuditController struct {
+	auditService audit.AuditService
+}
+
+func NewAuditController(auditService audit.AuditService) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+	}
+}
+
+// ListEvents lists audit events matching the query filters, newest first (admin only)
+func (c *AuditController) ListEvents(ctx *gin.Context) {
+	var filter model.AuditEventFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, bindErrorResponse(err))
+		return
+	}
+
+	events, total, err := c.auditService.ListEvents(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, pageSize := audit.Pagination(filter)
+	responses := make([]model.AuditEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, toAuditEventResponse(event))
+	}
+
+	ctx.JSON(http.StatusOK, model.AuditEventListResponse{
+		Events:   responses,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// RegisterRoutes registers the audit log routes
+func (c *AuditController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/audit-events")
+	adminRoutes.Use(authMiddleware, adminMiddleware)
+	{
+		adminRoutes.GET("", c.ListEvents)
+	}
+}
+
+// toAuditEventResponse converts an audit event to its response model
+func toAuditEventResponse(event *ent.AuditEvent) model.AuditEventResponse {
+	return model.AuditEventResponse{
+		ID:             event.ID,
+		Action:         event.Action,
+		ActorID:        event.ActorID,
+		ImpersonatedBy: event.ImpersonatedBy,
+		Subject:        event.Subject,
+		Details:        event.Details,
+		Outcome:        event.Outcome.String(),
+		Status:         event.Status,
+		IP:             event.IP,
+		UserAgent:      event.UserAgent,
+		CreatedAt:      event.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
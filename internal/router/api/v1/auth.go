@@ -5,59 +5,76 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
 	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/invitation"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
 	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
 	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
 )
 
 type AuthController struct {
 	userService        user.UserService
 	securityService    security.SecurityService
+	invitationService  invitation.InvitationService
 	enableRegistration bool
+	cookies            *middleware.TokenCookies
 }
 
-func NewAuthController(userService user.UserService, securityService security.SecurityService, enableRegistration bool) *AuthController {
+// NewAuthController creates the auth controller. With cookies set, tokens are delivered
+// in HttpOnly cookies instead of the response body.
+func NewAuthController(userService user.UserService, securityService security.SecurityService, invitationService invitation.InvitationService, enableRegistration bool, cookies *middleware.TokenCookies) *AuthController {
 	return &AuthController{
 		userService:        userService,
 		securityService:    securityService,
+		invitationService:  invitationService,
 		enableRegistration: enableRegistration,
+		cookies:            cookies,
 	}
 }
 
-// Register handles user registration
+// Register handles user registration. With an invite token the invitation decides the
+// role and registration works even when open registration is disabled.
 func (c *AuthController) Register(ctx *gin.Context) {
-	if !c.enableRegistration {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "registration is disabled"})
+	var input model.CreateUserInput
+	if !bindJSON(ctx, &input) {
 		return
 	}
 
-	var input model.CreateUserInput
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if input.InviteToken == "" && !c.enableRegistration {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "registration is disabled"})
 		return
 	}
 
-	// Set default role if not provided
-	if input.Role == "" {
-		input.Role = "user"
-	}
+	// Self-registered users get the default role; only admins and invitations grant others
+	input.Roles = []string{rbac.RoleUser}
 
-	user, err := c.userService.CreateUser(ctx, input)
+	var user *ent.User
+	var err error
+	if input.InviteToken != "" {
+		user, err = c.invitationService.Register(ctx, input.InviteToken, input)
+	} else {
+		user, err = c.userService.CreateUser(ctx, input)
+	}
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
 	// Convert to response model
 	userResponse := model.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		AvatarURL: &user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 
 	ctx.JSON(http.StatusCreated, userResponse)
@@ -66,44 +83,56 @@ func (c *AuthController) Register(ctx *gin.Context) {
 // Login handles user authentication and returns JWT tokens
 func (c *AuthController) Login(ctx *gin.Context) {
 	var input model.LoginInput
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(ctx, &input) {
 		return
 	}
 
-	tokens, user, err := c.userService.Login(ctx, input.Email, input.Password)
+	ctx.Set(middleware.AuditSubjectKey, input.Email)
+	tokens, user, err := c.userService.Login(ctx, input.Email, input.Password, input.RememberMe)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
+	ctx.Set(middleware.AuditActorKey, user.ID)
+
 	// Convert to response model
 	userResponse := model.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		AvatarURL: &user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 
+	tokens = deliverTokens(ctx, c.cookies, tokens)
 	authResponse := model.AuthResponse{
-		User:         userResponse,
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		ExpiresIn:    tokens.ExpiresIn,
+		User:               userResponse,
+		AccessToken:        tokens.AccessToken,
+		RefreshToken:       tokens.RefreshToken,
+		ExpiresIn:          tokens.ExpiresIn,
+		MustChangePassword: user.MustChangePassword,
 	}
 
 	ctx.JSON(http.StatusOK, authResponse)
 }
 
-// RefreshToken handles token refresh
+// RefreshToken handles token refresh. Cookie clients send no body and refresh with the
+// refresh token cookie.
 func (c *AuthController) RefreshToken(ctx *gin.Context) {
 	var input model.RefreshTokenInput
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if c.cookies != nil && ctx.Request.ContentLength == 0 {
+		input.RefreshToken = c.cookies.RefreshToken(ctx)
+		if input.RefreshToken == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token required"})
+			return
+		}
+	} else if !bindJSON(ctx, &input) {
 		return
 	}
 
@@ -113,16 +142,35 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"access_token":  tokens.AccessToken,
-		"refresh_token": tokens.RefreshToken,
-		"expires_in":    tokens.ExpiresIn,
-	})
+	ctx.JSON(http.StatusOK, deliverTokens(ctx, c.cookies, tokens))
+}
+
+// Logout revokes the current access token and, when given, the session's refresh token
+func (c *AuthController) Logout(ctx *gin.Context) {
+	var input model.LogoutInput
+	// 请求体可省略
+	if ctx.Request.ContentLength != 0 && !bindJSON(ctx, &input) {
+		return
+	}
+	if input.RefreshToken == "" && c.cookies != nil {
+		input.RefreshToken = c.cookies.RefreshToken(ctx)
+	}
+
+	err := c.userService.Logout(ctx, ctx.GetString("userID"), ctx.GetString("tokenID"), ctx.GetTime("tokenExpiresAt"), input.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.cookies != nil {
+		c.cookies.Clear(ctx)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
 
 // GetNonce generates and returns a new nonce for request signing
 func (c *AuthController) GetNonce(ctx *gin.Context) {
-	nonce, err := c.securityService.GenerateNonce()
+	nonce, err := c.securityService.GenerateNonce(ctx)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate nonce"})
 		return
@@ -131,13 +179,25 @@ func (c *AuthController) GetNonce(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, model.NonceResponse{Nonce: nonce})
 }
 
-// RegisterRoutes registers the auth routes
-func (c *AuthController) RegisterRoutes(router *gin.RouterGroup) {
+// deliverTokens writes the tokens to cookies when cookie delivery is enabled and returns
+// what goes into the response body, which then only carries the access token lifetime
+func deliverTokens(ctx *gin.Context, cookies *middleware.TokenCookies, tokens *jwt.TokenPair) *jwt.TokenPair {
+	if cookies == nil {
+		return tokens
+	}
+	cookies.Set(ctx, tokens)
+	return &jwt.TokenPair{ExpiresIn: tokens.ExpiresIn}
+}
+
+// RegisterRoutes registers the auth routes. loginGuards and registerGuards run before the
+// login and register handlers, e.g. rate limiting and captcha verification.
+func (c *AuthController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, loginGuards, registerGuards gin.HandlersChain) {
 	authRoutes := router.Group("/auth")
 	{
-		authRoutes.POST("/register", c.Register)
-		authRoutes.POST("/login", c.Login)
+		authRoutes.POST("/register", append(registerGuards, c.Register)...)
+		authRoutes.POST("/login", append(loginGuards, c.Login)...)
 		authRoutes.POST("/refresh", c.RefreshToken)
+		authRoutes.POST("/logout", authMiddleware, c.Logout)
 		authRoutes.GET("/nonce", c.GetNonce)
 	}
 }
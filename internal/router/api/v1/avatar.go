@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/pkg/avatar"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/storage"
+)
+
+type AvatarController struct {
+	userService    user.UserService
+	avatarProvider avatar.Provider
+	avatarUploader *avatar.Uploader
+	maxUploadBytes int64
+}
+
+func NewAvatarController(userService user.UserService, avatarProvider avatar.Provider, avatarUploader *avatar.Uploader, maxUploadBytes int64) *AvatarController {
+	return &AvatarController{
+		userService:    userService,
+		avatarProvider: avatarProvider,
+		avatarUploader: avatarUploader,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// UploadAvatar sets the current user's avatar from the image in the "avatar" field of a
+// multipart form. The previously uploaded avatar, if any, is deleted.
+func (c *AvatarController) UploadAvatar(ctx *gin.Context) {
+	userID := ctx.GetString("userID")
+	current, err := c.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.maxUploadBytes)
+	file, _, err := ctx.Request.FormFile("avatar")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("avatar exceeds %d bytes", c.maxUploadBytes)})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "avatar file is required"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read avatar"})
+		return
+	}
+
+	avatarURL, err := c.avatarUploader.Upload(ctx, userID, data)
+	if err != nil {
+		if errors.Is(err, avatar.ErrInvalidImage) {
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := c.userService.UpdateUser(ctx, userID, model.UpdateUserInput{AvatarURL: &avatarURL}); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if current.AvatarURL != avatarURL {
+		c.removeUploadedAvatar(ctx, current.AvatarURL)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"avatar_url": avatarURL})
+}
+
+// DeleteAvatar clears the current user's avatar, so a generated one is served again
+func (c *AvatarController) DeleteAvatar(ctx *gin.Context) {
+	userID := ctx.GetString("userID")
+	current, err := c.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	empty := ""
+	if _, err := c.userService.UpdateUser(ctx, userID, model.UpdateUserInput{AvatarURL: &empty}); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.removeUploadedAvatar(ctx, current.AvatarURL)
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "avatar removed"})
+}
+
+// GetUploadedAvatar serves an uploaded avatar from storage. File names are content hashes,
+// so the response can be cached forever.
+func (c *AvatarController) GetUploadedAvatar(ctx *gin.Context) {
+	obj, err := c.avatarUploader.Get(ctx, ctx.Param("id")+"/"+ctx.Param("file"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "avatar not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read avatar"})
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=31536000, immutable")
+	ctx.Data(http.StatusOK, obj.ContentType, obj.Data)
+}
+
+// removeUploadedAvatar deletes a replaced avatar; a failure only leaves an unused file behind
+func (c *AvatarController) removeUploadedAvatar(ctx *gin.Context, avatarURL string) {
+	if err := c.avatarUploader.Remove(ctx, avatarURL); err != nil {
+		logger.Warnf("Failed to delete replaced avatar %s: %v", avatarURL, err)
+	}
+}
+
+// GetAvatar serves a user's avatar: the uploaded one if set, otherwise a generated image
+func (c *AvatarController) GetAvatar(ctx *gin.Context) {
+	user, err := c.userService.GetUserByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if user.AvatarURL != "" {
+		ctx.Redirect(http.StatusFound, user.AvatarURL)
+		return
+	}
+
+	image, err := c.avatarProvider.Avatar(ctx, avatar.Subject{
+		ID:    user.ID,
+		Name:  user.Username,
+		Email: user.Email,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate avatar"})
+		return
+	}
+	if image.RedirectURL != "" {
+		ctx.Redirect(http.StatusFound, image.RedirectURL)
+		return
+	}
+
+	// Generated images are deterministic, so the content hash is a stable ETag
+	sum := sha256.Sum256(image.Data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "private, max-age=86400")
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, image.ContentType, image.Data)
+}
+
+// RegisterRoutes registers the avatar routes
+func (c *AvatarController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/users/:id/avatar", authMiddleware, c.GetAvatar)
+	router.POST("/users/me/avatar", authMiddleware, c.UploadAvatar)
+	router.DELETE("/users/me/avatar", authMiddleware, c.DeleteAvatar)
+	router.GET("/avatars/:id/:file", authMiddleware, c.GetUploadedAvatar)
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type CSRFController struct {
+	options middleware.CSRFOptions
+}
+
+func NewCSRFController(options middleware.CSRFOptions) *CSRFController {
+	return &CSRFController{
+		options: options,
+	}
+}
+
+// IssueToken returns the client's CSRF token, setting the CSRF cookie when it has none.
+// Clients send the token back in the CSRF header on state-changing requests.
+func (c *CSRFController) IssueToken(ctx *gin.Context) {
+	token, err := middleware.IssueCSRFToken(ctx, c.options)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to issue CSRF token: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue CSRF token"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.CSRFTokenResponse{
+		CSRFToken:  token,
+		HeaderName: c.options.HeaderName,
+	})
+}
+
+// RegisterRoutes registers the CSRF token route
+func (c *CSRFController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/auth/csrf", c.IssueToken)
+}
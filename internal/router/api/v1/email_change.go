@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/emailchange"
+)
+
+type EmailChangeController struct {
+	emailChangeService emailchange.EmailChangeService
+}
+
+func NewEmailChangeController(emailChangeService emailchange.EmailChangeService) *EmailChangeController {
+	return &EmailChangeController{
+		emailChangeService: emailChangeService,
+	}
+}
+
+// RequestEmailChange starts changing the current user's email address
+func (c *EmailChangeController) RequestEmailChange(ctx *gin.Context) {
+	userID := ctx.GetString("userID")
+	if userID == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+	// 模拟登录时禁止修改登录凭据
+	if ctx.GetString("impersonatedBy") != "" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "not allowed while impersonating"})
+		return
+	}
+
+	var input model.ChangeEmailInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	change, err := c.emailChangeService.RequestEmailChange(ctx, userID, input, ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"message":    "confirmation email sent to the new address",
+		"expires_at": change.ConfirmExpiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// ConfirmEmailChange applies an email change using the token sent to the new address
+func (c *EmailChangeController) ConfirmEmailChange(ctx *gin.Context) {
+	var input model.EmailChangeTokenInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	user, err := c.emailChangeService.ConfirmEmailChange(ctx, input.Token)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toEmailChangeUserResponse(user))
+}
+
+// RollbackEmailChange restores the previous email using the token sent to the old address
+func (c *EmailChangeController) RollbackEmailChange(ctx *gin.Context) {
+	var input model.EmailChangeTokenInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	user, err := c.emailChangeService.RollbackEmailChange(ctx, input.Token)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toEmailChangeUserResponse(user))
+}
+
+// RegisterRoutes registers the email change routes
+func (c *EmailChangeController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.PATCH("/users/me/email", authMiddleware, c.RequestEmailChange)
+
+	// Links from emails are opened without a session
+	emailRoutes := router.Group("/auth/email")
+	{
+		emailRoutes.POST("/confirm", c.ConfirmEmailChange)
+		emailRoutes.POST("/rollback", c.RollbackEmailChange)
+	}
+}
+
+// toEmailChangeUserResponse converts the updated user to its response model
+func toEmailChangeUserResponse(user *ent.User) model.UserResponse {
+	return model.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/hewenyu/gin-pkg/pkg/auth/password"
+)
+
+// errorResponse builds the JSON error body for err, exposing structured details
+// such as password policy violations when they are available
+func errorResponse(err error) gin.H {
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		return gin.H{
+			"error":      "password does not meet policy",
+			"violations": policyErr.Violations,
+		}
+	}
+	return gin.H{"error": err.Error()}
+}
+
+// bindJSON binds the JSON request body into obj and writes a 400 response on failure
+func bindJSON(ctx *gin.Context, obj any) bool {
+	if err := ctx.ShouldBindJSON(obj); err != nil {
+		ctx.JSON(http.StatusBadRequest, bindErrorResponse(err))
+		return false
+	}
+	return true
+}
+
+// bindErrorResponse builds the JSON error body for a failed bind, with a machine-readable
+// code and the offending field(s) so clients can tell what was wrong with the payload
+func bindErrorResponse(err error) gin.H {
+	resp := gin.H{"error": err.Error()}
+
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]gin.H, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, gin.H{"field": fe.Field(), "rule": fe.Tag()})
+		}
+		resp["code"] = "validation_failed"
+		resp["fields"] = fields
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// Only reported when strict JSON mode is on
+		resp["code"] = "unknown_field"
+		resp["field"] = strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+	case errors.As(err, &typeErr):
+		resp["code"] = "invalid_type"
+		resp["field"] = typeErr.Field
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		resp["code"] = "invalid_json"
+	default:
+		resp["code"] = "invalid_request"
+	}
+	return resp
+}
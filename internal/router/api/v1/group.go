@@ -0,0 +1,264 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/group"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type GroupController struct {
+	groupService group.GroupService
+}
+
+func NewGroupController(groupService group.GroupService) *GroupController {
+	return &GroupController{
+		groupService: groupService,
+	}
+}
+
+// CreateGroup creates a group owned by the current user
+func (c *GroupController) CreateGroup(ctx *gin.Context) {
+	var input model.CreateGroupInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	g, err := c.groupService.CreateGroup(ctx, input, ctx.GetString("userID"))
+	if err != nil {
+		ctx.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toGroupResponse(g, group.RoleOwner))
+}
+
+// ListMyGroups lists the groups of the current user with their role in each
+func (c *GroupController) ListMyGroups(ctx *gin.Context) {
+	memberships, err := c.groupService.ListUserGroups(ctx, ctx.GetString("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.GroupResponse, 0, len(memberships))
+	for _, m := range memberships {
+		responses = append(responses, toGroupResponse(m.Edges.Group, m.Role.String()))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// ListGroups lists every group (admin only)
+func (c *GroupController) ListGroups(ctx *gin.Context) {
+	groups, err := c.groupService.ListGroups(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.GroupResponse, 0, len(groups))
+	for _, g := range groups {
+		responses = append(responses, toGroupResponse(g, ""))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// GetGroup retrieves a group (members only)
+func (c *GroupController) GetGroup(ctx *gin.Context) {
+	g, err := c.groupService.GetGroup(ctx, ctx.GetString("groupID"))
+	if err != nil {
+		ctx.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toGroupResponse(g, ctx.GetString("groupRole")))
+}
+
+// UpdateGroup updates a group's name or description (group owners and admins)
+func (c *GroupController) UpdateGroup(ctx *gin.Context) {
+	var input model.UpdateGroupInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	g, err := c.groupService.UpdateGroup(ctx, ctx.GetString("groupID"), input)
+	if err != nil {
+		ctx.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toGroupResponse(g, ctx.GetString("groupRole")))
+}
+
+// DeleteGroup deletes a group and its memberships (group owners)
+func (c *GroupController) DeleteGroup(ctx *gin.Context) {
+	if err := c.groupService.DeleteGroup(ctx, ctx.GetString("groupID")); err != nil {
+		ctx.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "group deleted successfully"})
+}
+
+// ListMembers lists the members of a group (members only)
+func (c *GroupController) ListMembers(ctx *gin.Context) {
+	memberships, err := c.groupService.ListMembers(ctx, ctx.GetString("groupID"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.GroupMemberResponse, 0, len(memberships))
+	for _, m := range memberships {
+		responses = append(responses, toGroupMemberResponse(m))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// AddMember adds a user to a group. Admins can only add plain members.
+func (c *GroupController) AddMember(ctx *gin.Context) {
+	var input model.AddGroupMemberInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	if input.Role == "" {
+		input.Role = group.RoleMember
+	}
+	if !group.CanManage(ctx.GetString("groupRole"), input.Role) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient group role"})
+		return
+	}
+
+	m, err := c.groupService.AddMember(ctx, ctx.GetString("groupID"), input.UserID, input.Role)
+	if err != nil {
+		ctx.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toGroupMemberResponse(m))
+}
+
+// UpdateMember changes a member's role. Admins can only manage plain members, and only
+// owners can promote to admin or owner.
+func (c *GroupController) UpdateMember(ctx *gin.Context) {
+	var input model.UpdateGroupMemberInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	groupID, userID := ctx.GetString("groupID"), ctx.Param("userId")
+	if !c.canManageMember(ctx, groupID, userID, input.Role) {
+		return
+	}
+
+	m, err := c.groupService.UpdateMemberRole(ctx, groupID, userID, input.Role)
+	if err != nil {
+		ctx.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toGroupMemberResponse(m))
+}
+
+// RemoveMember removes a member from a group. Any member can leave; removing someone
+// else follows the same rules as changing their role.
+func (c *GroupController) RemoveMember(ctx *gin.Context) {
+	groupID, userID := ctx.GetString("groupID"), ctx.Param("userId")
+	if userID != ctx.GetString("userID") && !c.canManageMember(ctx, groupID, userID, group.RoleMember) {
+		return
+	}
+
+	if err := c.groupService.RemoveMember(ctx, groupID, userID); err != nil {
+		ctx.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "member removed successfully"})
+}
+
+// canManageMember checks that the current user may manage the member userID and give
+// them role, writing an error response when not
+func (c *GroupController) canManageMember(ctx *gin.Context, groupID, userID, role string) bool {
+	current, err := c.groupService.MemberRole(ctx, groupID, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if current == "" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": group.ErrNotMember.Error()})
+		return false
+	}
+
+	actor := ctx.GetString("groupRole")
+	if !group.CanManage(actor, current) || !group.CanManage(actor, role) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient group role"})
+		return false
+	}
+	return true
+}
+
+// groupErrorStatus maps group service errors to HTTP status codes
+func groupErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, group.ErrGroupNotFound), errors.Is(err, group.ErrNotMember), errors.Is(err, group.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, group.ErrGroupExists), errors.Is(err, group.ErrAlreadyMember), errors.Is(err, group.ErrLastOwner):
+		return http.StatusConflict
+	case errors.Is(err, group.ErrUnknownRole):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toGroupResponse(g *ent.Group, role string) model.GroupResponse {
+	return model.GroupResponse{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		Role:        role,
+		CreatedAt:   g.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   g.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toGroupMemberResponse(m *ent.Membership) model.GroupMemberResponse {
+	resp := model.GroupMemberResponse{
+		UserID:   m.UserID,
+		Role:     m.Role.String(),
+		JoinedAt: m.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if u := m.Edges.User; u != nil {
+		resp.Email = u.Email
+		resp.Username = u.Username
+	}
+	return resp
+}
+
+// RegisterRoutes registers the group routes. access authorizes by group membership, and
+// adminMiddleware guards the list of every group.
+func (c *GroupController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, access middleware.GroupAccess, adminMiddleware gin.HandlerFunc) {
+	groupRoutes := router.Group("/groups")
+	groupRoutes.Use(authMiddleware)
+	{
+		groupRoutes.POST("", c.CreateGroup)
+		groupRoutes.GET("", c.ListMyGroups)
+		groupRoutes.GET("/:id", access.Require(), c.GetGroup)
+		groupRoutes.PUT("/:id", access.Require(group.RoleOwner, group.RoleAdmin), c.UpdateGroup)
+		groupRoutes.DELETE("/:id", access.Require(group.RoleOwner), c.DeleteGroup)
+		groupRoutes.GET("/:id/members", access.Require(), c.ListMembers)
+		groupRoutes.POST("/:id/members", access.Require(group.RoleOwner, group.RoleAdmin), c.AddMember)
+		groupRoutes.PUT("/:id/members/:userId", access.Require(group.RoleOwner, group.RoleAdmin), c.UpdateMember)
+		groupRoutes.DELETE("/:id/members/:userId", access.Require(), c.RemoveMember)
+	}
+
+	router.GET("/admin/groups", authMiddleware, adminMiddleware, c.ListGroups)
+}
@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+type GuestController struct {
+	tokenService jwt.TokenService
+	ttl          time.Duration
+}
+
+func NewGuestController(tokenService jwt.TokenService, ttl time.Duration) *GuestController {
+	return &GuestController{
+		tokenService: tokenService,
+		ttl:          ttl,
+	}
+}
+
+// IssueToken issues a short-lived guest access token. No user record is created; the
+// guest ID only identifies the token holder while the token is valid.
+func (c *GuestController) IssueToken(ctx *gin.Context) {
+	guestID := "guest:" + uuid.New().String()
+	tokens, err := c.tokenService.GenerateTokenPairWithOptions(ctx, guestID, "", []string{jwt.GuestRole}, jwt.TokenOptions{
+		Guest:       true,
+		MaxLifetime: c.ttl,
+		Fingerprint: jwt.FingerprintFromContext(ctx),
+		TenantID:    dbtenant.From(ctx),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to issue guest token: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue guest token"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.GuestTokenResponse{
+		GuestID:     guestID,
+		AccessToken: tokens.AccessToken,
+		ExpiresIn:   tokens.ExpiresIn,
+	})
+}
+
+// RegisterRoutes registers the guest token route. guards run before the handler, e.g. rate limiting.
+func (c *GuestController) RegisterRoutes(router *gin.RouterGroup, guards gin.HandlersChain) {
+	router.POST("/auth/guest", append(guards, c.IssueToken)...)
+}
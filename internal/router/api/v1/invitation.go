@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/invitation"
+)
+
+type InvitationController struct {
+	invitationService invitation.InvitationService
+}
+
+func NewInvitationController(invitationService invitation.InvitationService) *InvitationController {
+	return &InvitationController{
+		invitationService: invitationService,
+	}
+}
+
+// CreateInvitation invites a user and returns the invite token (admin only)
+func (c *InvitationController) CreateInvitation(ctx *gin.Context) {
+	var input model.CreateInvitationInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	inv, token, err := c.invitationService.CreateInvitation(ctx, input, ctx.GetString("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.InvitationTokenResponse{
+		Invitation: toInvitationResponse(inv),
+		Token:      token,
+	})
+}
+
+// ListInvitations lists all invitations (admin only)
+func (c *InvitationController) ListInvitations(ctx *gin.Context) {
+	invitations, err := c.invitationService.ListInvitations(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.InvitationResponse, 0, len(invitations))
+	for _, inv := range invitations {
+		responses = append(responses, toInvitationResponse(inv))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// RevokeInvitation deletes a pending invitation (admin only)
+func (c *InvitationController) RevokeInvitation(ctx *gin.Context) {
+	if err := c.invitationService.RevokeInvitation(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "invitation revoked successfully"})
+}
+
+// RegisterRoutes registers the invitation routes
+func (c *InvitationController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/invitations")
+	adminRoutes.Use(authMiddleware, adminMiddleware)
+	{
+		adminRoutes.POST("", c.CreateInvitation)
+		adminRoutes.GET("", c.ListInvitations)
+		adminRoutes.DELETE("/:id", c.RevokeInvitation)
+	}
+}
+
+// toInvitationResponse converts an invitation to its response model
+func toInvitationResponse(inv *ent.Invitation) model.InvitationResponse {
+	resp := model.InvitationResponse{
+		ID:             inv.ID,
+		Email:          inv.Email,
+		Role:           inv.Role,
+		ExpiresAt:      inv.ExpiresAt.UTC().Format(time.RFC3339),
+		AcceptedUserID: inv.AcceptedUserID,
+		CreatedBy:      inv.CreatedBy,
+		CreatedAt:      inv.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if inv.AcceptedAt != nil {
+		acceptedAt := inv.AcceptedAt.UTC().Format(time.RFC3339)
+		resp.AcceptedAt = &acceptedAt
+	}
+	return resp
+}
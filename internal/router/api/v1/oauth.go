@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/oauth"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+type OAuthController struct {
+	oauthService oauth.OAuthService
+}
+
+func NewOAuthController(oauthService oauth.OAuthService) *OAuthController {
+	return &OAuthController{
+		oauthService: oauthService,
+	}
+}
+
+// Authorize starts an authorization request for the signed-in user. It answers with the
+// consent prompt, or with the redirect URI carrying the code when consent can be skipped.
+func (c *OAuthController) Authorize(ctx *gin.Context) {
+	// 模拟登录时禁止授权第三方应用，否则授权码换来的令牌不受模拟登录的时长和日志约束
+	if ctx.GetString("impersonatedBy") != "" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "not allowed while impersonating"})
+		return
+	}
+
+	var input model.OAuthAuthorizeInput
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, bindErrorResponse(err))
+		return
+	}
+
+	result, err := c.oauthService.Authorize(ctx, ctx.GetString("userID"), input, nil)
+	c.respondAuthorize(ctx, input, result, err)
+}
+
+// Consent finishes an authorization request with the user's answer from the consent screen
+func (c *OAuthController) Consent(ctx *gin.Context) {
+	// 模拟登录时禁止授权第三方应用
+	if ctx.GetString("impersonatedBy") != "" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "not allowed while impersonating"})
+		return
+	}
+
+	var input model.OAuthConsentInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	result, err := c.oauthService.Authorize(ctx, ctx.GetString("userID"), input.OAuthAuthorizeInput, &input.Approve)
+	c.respondAuthorize(ctx, input.OAuthAuthorizeInput, result, err)
+}
+
+// Token issues tokens for the authorization_code and refresh_token grants. Clients send
+// their credentials as form parameters or with HTTP Basic authentication.
+func (c *OAuthController) Token(ctx *gin.Context) {
+	var input model.OAuthTokenInput
+	if err := ctx.ShouldBind(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": oauth.ErrCodeInvalidRequest, "error_description": err.Error()})
+		return
+	}
+	if clientID, secret, ok := ctx.Request.BasicAuth(); ok {
+		input.ClientID, input.ClientSecret = clientID, secret
+	}
+
+	// Token responses must not be cached (RFC 6749 section 5.1)
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	result, err := c.oauthService.Token(ctx, input)
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.OAuthTokenResponse{
+		AccessToken:  result.Tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    result.Tokens.ExpiresIn,
+		RefreshToken: result.Tokens.RefreshToken,
+		Scope:        strings.Join(result.Scopes, " "),
+	})
+}
+
+// CreateClient registers an OAuth client and returns its secret once (admin only)
+func (c *OAuthController) CreateClient(ctx *gin.Context) {
+	var input model.CreateOAuthClientInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	client, secret, err := c.oauthService.CreateClient(ctx, input, ctx.GetString("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.OAuthClientSecretResponse{
+		Client:       toOAuthClientResponse(client),
+		ClientSecret: secret,
+	})
+}
+
+// ListClients lists all OAuth clients (admin only)
+func (c *OAuthController) ListClients(ctx *gin.Context) {
+	clients, err := c.oauthService.ListClients(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.OAuthClientResponse, 0, len(clients))
+	for _, client := range clients {
+		responses = append(responses, toOAuthClientResponse(client))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// DeleteClient removes an OAuth client (admin only)
+func (c *OAuthController) DeleteClient(ctx *gin.Context) {
+	if err := c.oauthService.DeleteClient(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "OAuth client deleted successfully"})
+}
+
+// RegisterRoutes registers the admin routes for managing OAuth clients
+func (c *OAuthController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/oauth-clients")
+	adminRoutes.Use(authMiddleware, adminMiddleware)
+	{
+		adminRoutes.POST("", c.CreateClient)
+		adminRoutes.GET("", c.ListClients)
+		adminRoutes.DELETE("/:id", c.DeleteClient)
+	}
+}
+
+// RegisterProtocolRoutes registers the OAuth2 endpoints. They live outside /api/v1 because
+// OAuth clients cannot sign requests with the server's signature secret.
+func (c *OAuthController) RegisterProtocolRoutes(router gin.IRouter, authMiddleware gin.HandlerFunc) {
+	oauthRoutes := router.Group("/oauth")
+	{
+		oauthRoutes.GET("/authorize", authMiddleware, c.Authorize)
+		oauthRoutes.POST("/authorize", authMiddleware, c.Consent)
+		oauthRoutes.POST("/token", c.Token)
+	}
+}
+
+// respondAuthorize writes the response of an authorization request
+func (c *OAuthController) respondAuthorize(ctx *gin.Context, input model.OAuthAuthorizeInput, result *oauth.AuthorizeResult, err error) {
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	if result.ConsentRequired {
+		ctx.JSON(http.StatusOK, model.OAuthConsentResponse{
+			ConsentRequired: true,
+			Client:          toOAuthClientResponse(result.Client),
+			Scopes:          result.Scopes,
+			Request:         input,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.OAuthRedirectResponse{RedirectURI: result.RedirectURI})
+}
+
+// respondOAuthError writes an error in the format of RFC 6749 section 5.2
+func respondOAuthError(ctx *gin.Context, err error) {
+	var oauthErr *oauth.Error
+	if !errors.As(err, &oauthErr) {
+		logger.FromContext(ctx).Errorf("OAuth request failed: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": oauth.ErrCodeServerError})
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oauthErr.Code == oauth.ErrCodeInvalidClient {
+		status = http.StatusUnauthorized
+	}
+	ctx.JSON(status, gin.H{"error": oauthErr.Code, "error_description": oauthErr.Description})
+}
+
+// toOAuthClientResponse converts an OAuth client to its response model
+func toOAuthClientResponse(client *ent.OAuthClient) model.OAuthClientResponse {
+	return model.OAuthClientResponse{
+		ClientID:     client.ID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectUris,
+		Scopes:       client.Scopes,
+		Public:       client.SecretHash == "",
+		FirstParty:   client.FirstParty,
+		CreatedBy:    client.CreatedBy,
+		CreatedAt:    client.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
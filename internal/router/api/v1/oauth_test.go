@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/oauth"
+)
+
+// fakeOAuthService issues a code for every authorization request and counts them; the
+// other methods are not used
+type fakeOAuthService struct {
+	oauth.OAuthService
+	authorized int
+}
+
+func (s *fakeOAuthService) Authorize(ctx context.Context, userID string, input model.OAuthAuthorizeInput, consent *bool) (*oauth.AuthorizeResult, error) {
+	s.authorized++
+	return &oauth.AuthorizeResult{RedirectURI: input.RedirectURI + "?code=c"}, nil
+}
+
+func TestAuthorizeImpersonation(t *testing.T) {
+	query := "response_type=code&client_id=c1&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcallback" +
+		"&code_challenge=abc&code_challenge_method=S256"
+	body := `{"response_type":"code","client_id":"c1","redirect_uri":"https://app.example.com/callback",` +
+		`"code_challenge":"abc","code_challenge_method":"S256","approve":true}`
+
+	tests := []struct {
+		name           string
+		method         string
+		impersonatedBy string
+		wantCode       int
+	}{
+		{"authorize", http.MethodGet, "", http.StatusOK},
+		{"authorize while impersonating", http.MethodGet, "admin", http.StatusForbidden},
+		{"consent", http.MethodPost, "", http.StatusOK},
+		{"consent while impersonating", http.MethodPost, "admin", http.StatusForbidden},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeOAuthService{}
+			router := gin.New()
+			auth := func(c *gin.Context) {
+				c.Set("userID", "u1")
+				if tt.impersonatedBy != "" {
+					c.Set("impersonatedBy", tt.impersonatedBy)
+				}
+			}
+			NewOAuthController(service).RegisterProtocolRoutes(router, auth)
+
+			req := httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query, nil)
+			if tt.method == http.MethodPost {
+				req = httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if issued := service.authorized > 0; issued != (tt.wantCode == http.StatusOK) {
+				t.Errorf("code issued = %v with status %d", issued, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/organization"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type OrganizationController struct {
+	orgService organization.OrganizationService
+}
+
+func NewOrganizationController(orgService organization.OrganizationService) *OrganizationController {
+	return &OrganizationController{
+		orgService: orgService,
+	}
+}
+
+// CreateOrganization creates an organization (admin only)
+func (c *OrganizationController) CreateOrganization(ctx *gin.Context) {
+	var input model.CreateOrganizationInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	o, err := c.orgService.CreateOrganization(ctx, input)
+	if err != nil {
+		ctx.JSON(orgErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toOrganizationResponse(o))
+}
+
+// ListOrganizations lists every organization (admin only)
+func (c *OrganizationController) ListOrganizations(ctx *gin.Context) {
+	orgs, err := c.orgService.ListOrganizations(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.OrganizationResponse, 0, len(orgs))
+	for _, o := range orgs {
+		responses = append(responses, toOrganizationResponse(o))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// GetOrganization retrieves an organization (admin only)
+func (c *OrganizationController) GetOrganization(ctx *gin.Context) {
+	o, err := c.orgService.GetOrganization(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(orgErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrganizationResponse(o))
+}
+
+// UpdateOrganization renames, activates or deactivates an organization (admin only)
+func (c *OrganizationController) UpdateOrganization(ctx *gin.Context) {
+	var input model.UpdateOrganizationInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	o, err := c.orgService.UpdateOrganization(ctx, ctx.Param("id"), input)
+	if err != nil {
+		ctx.JSON(orgErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toOrganizationResponse(o))
+}
+
+// DeleteOrganization deletes an organization without users (admin only)
+func (c *OrganizationController) DeleteOrganization(ctx *gin.Context) {
+	if err := c.orgService.DeleteOrganization(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(orgErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "organization deleted successfully"})
+}
+
+// orgErrorStatus maps organization service errors to HTTP status codes
+func orgErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, organization.ErrOrganizationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, organization.ErrOrganizationExists), errors.Is(err, organization.ErrOrganizationNotEmpty):
+		return http.StatusConflict
+	case errors.Is(err, organization.ErrInvalidSlug):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toOrganizationResponse(o *ent.Organization) model.OrganizationResponse {
+	return model.OrganizationResponse{
+		ID:        o.ID,
+		Name:      o.Name,
+		Slug:      o.Slug,
+		Active:    o.Active,
+		CreatedAt: o.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: o.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// RegisterRoutes registers the organization routes. Users of an organization cannot
+// manage organizations, whatever their roles.
+func (c *OrganizationController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/orgs")
+	adminRoutes.Use(authMiddleware, middleware.PlatformOnlyMiddleware(), adminMiddleware)
+	{
+		adminRoutes.POST("", c.CreateOrganization)
+		adminRoutes.GET("", c.ListOrganizations)
+		adminRoutes.GET("/:id", c.GetOrganization)
+		adminRoutes.PUT("/:id", c.UpdateOrganization)
+		adminRoutes.DELETE("/:id", c.DeleteOrganization)
+	}
+}
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/otp"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type OTPController struct {
+	otpService otp.OTPService
+	cookies    *middleware.TokenCookies
+}
+
+// NewOTPController creates the SMS login controller. With cookies set, tokens are
+// delivered in HttpOnly cookies instead of the response body.
+func NewOTPController(otpService otp.OTPService, cookies *middleware.TokenCookies) *OTPController {
+	return &OTPController{
+		otpService: otpService,
+		cookies:    cookies,
+	}
+}
+
+// RequestLoginCode sends a login code to the given phone number
+func (c *OTPController) RequestLoginCode(ctx *gin.Context) {
+	var input model.RequestLoginCodeInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	if err := c.otpService.RequestLoginCode(ctx, input.Phone); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send code"})
+		return
+	}
+
+	// Same response whether or not the number is registered
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "if the phone number is registered, a login code has been sent"})
+}
+
+// VerifyLoginCode logs the user in with the code sent to their phone
+func (c *OTPController) VerifyLoginCode(ctx *gin.Context) {
+	var input model.VerifyLoginCodeInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	ctx.Set(middleware.AuditSubjectKey, input.Phone)
+	tokens, user, err := c.otpService.VerifyLoginCode(ctx, input.Phone, input.Code, input.RememberMe)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Set(middleware.AuditActorKey, user.ID)
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+
+	tokens = deliverTokens(ctx, c.cookies, tokens)
+	ctx.JSON(http.StatusOK, model.AuthResponse{
+		User:               userResponse,
+		AccessToken:        tokens.AccessToken,
+		RefreshToken:       tokens.RefreshToken,
+		ExpiresIn:          tokens.ExpiresIn,
+		MustChangePassword: user.MustChangePassword,
+	})
+}
+
+// RequestPhoneVerification sends a verification code to the current user's phone number
+func (c *OTPController) RequestPhoneVerification(ctx *gin.Context) {
+	err := c.otpService.RequestPhoneVerification(ctx, ctx.GetString("userID"))
+	if err != nil {
+		if errors.Is(err, otp.ErrNoPhone) || errors.Is(err, otp.ErrPhoneVerified) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send code"})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "a verification code has been sent"})
+}
+
+// VerifyPhone marks the current user's phone number as verified with the code sent to it
+func (c *OTPController) VerifyPhone(ctx *gin.Context) {
+	var input model.VerifyPhoneInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	user, err := c.otpService.VerifyPhone(ctx, ctx.GetString("userID"), input.Code)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+
+	ctx.JSON(http.StatusOK, userResponse)
+}
+
+// RegisterRoutes registers the SMS login and phone verification routes. requestGuards
+// protect the routes sending a code, verifyGuards the routes checking one.
+func (c *OTPController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, requestGuards, verifyGuards gin.HandlersChain) {
+	otpRoutes := router.Group("/auth/otp")
+	{
+		otpRoutes.POST("/request", append(requestGuards, c.RequestLoginCode)...)
+		otpRoutes.POST("/verify", append(verifyGuards, c.VerifyLoginCode)...)
+	}
+
+	phoneRoutes := router.Group("/users/me/phone")
+	phoneRoutes.Use(authMiddleware)
+	{
+		phoneRoutes.POST("/verification", append(requestGuards, c.RequestPhoneVerification)...)
+		phoneRoutes.POST("/verify", append(verifyGuards, c.VerifyPhone)...)
+	}
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/preference"
+)
+
+type PreferenceController struct {
+	preferenceService preference.PreferenceService
+}
+
+func NewPreferenceController(preferenceService preference.PreferenceService) *PreferenceController {
+	return &PreferenceController{
+		preferenceService: preferenceService,
+	}
+}
+
+// GetPreferences returns the preferences of the current user
+func (c *PreferenceController) GetPreferences(ctx *gin.Context) {
+	p, err := c.preferenceService.GetPreferences(ctx, ctx.GetString("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPreferencesResponse(p))
+}
+
+// UpdatePreferences changes the preferences of the current user
+func (c *PreferenceController) UpdatePreferences(ctx *gin.Context) {
+	var input model.UpdatePreferencesInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	p, err := c.preferenceService.UpdatePreferences(ctx, ctx.GetString("userID"), input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, preference.ErrInvalidLocale) || errors.Is(err, preference.ErrInvalidTimezone) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPreferencesResponse(p))
+}
+
+func toPreferencesResponse(p *ent.UserPreference) model.PreferencesResponse {
+	resp := model.PreferencesResponse{
+		Notifications: model.NotificationPreferences{
+			Email:     p.NotifyEmail,
+			SMS:       p.NotifySms,
+			Marketing: p.NotifyMarketing,
+		},
+		Theme:    p.Theme.String(),
+		Locale:   p.Locale,
+		Timezone: p.Timezone,
+	}
+	// 未保存过的默认偏好没有更新时间
+	if !p.UpdatedAt.IsZero() {
+		resp.UpdatedAt = p.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
+
+// RegisterRoutes registers the preference routes
+func (c *PreferenceController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/users/me/preferences", authMiddleware, c.GetPreferences)
+	router.PUT("/users/me/preferences", authMiddleware, c.UpdatePreferences)
+}
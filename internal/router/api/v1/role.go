@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+)
+
+type RoleController struct {
+	rbacService rbac.RBACService
+}
+
+func NewRoleController(rbacService rbac.RBACService) *RoleController {
+	return &RoleController{
+		rbacService: rbacService,
+	}
+}
+
+// ListRoles lists all roles with their permissions (admin only)
+func (c *RoleController) ListRoles(ctx *gin.Context) {
+	roles, err := c.rbacService.ListRoles(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.RoleResponse, 0, len(roles))
+	for _, r := range roles {
+		responses = append(responses, toRoleResponse(r))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// GetRole retrieves a role by name (admin only)
+func (c *RoleController) GetRole(ctx *gin.Context) {
+	r, err := c.rbacService.GetRole(ctx, ctx.Param("name"))
+	if err != nil {
+		ctx.JSON(roleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toRoleResponse(r))
+}
+
+// CreateRole creates a role (admin only)
+func (c *RoleController) CreateRole(ctx *gin.Context) {
+	var input model.CreateRoleInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	r, err := c.rbacService.CreateRole(ctx, input)
+	if err != nil {
+		ctx.JSON(roleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toRoleResponse(r))
+}
+
+// UpdateRole updates a role's description or permissions (admin only)
+func (c *RoleController) UpdateRole(ctx *gin.Context) {
+	var input model.UpdateRoleInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	r, err := c.rbacService.UpdateRole(ctx, ctx.Param("name"), input)
+	if err != nil {
+		ctx.JSON(roleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toRoleResponse(r))
+}
+
+// DeleteRole deletes a role that is not built in or assigned (admin only)
+func (c *RoleController) DeleteRole(ctx *gin.Context) {
+	if err := c.rbacService.DeleteRole(ctx, ctx.Param("name")); err != nil {
+		ctx.JSON(roleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "role deleted successfully"})
+}
+
+// ListPermissions lists all permissions that can be granted to roles (admin only)
+func (c *RoleController) ListPermissions(ctx *gin.Context) {
+	perms, err := c.rbacService.ListPermissions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.PermissionResponse, 0, len(perms))
+	for _, p := range perms {
+		responses = append(responses, model.PermissionResponse{
+			Name:        p.Name,
+			Description: p.Description,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// RegisterRoutes registers the role and permission routes
+func (c *RoleController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin")
+	adminRoutes.Use(authMiddleware, adminMiddleware)
+	{
+		adminRoutes.GET("/roles", c.ListRoles)
+		adminRoutes.POST("/roles", c.CreateRole)
+		adminRoutes.GET("/roles/:name", c.GetRole)
+		adminRoutes.PUT("/roles/:name", c.UpdateRole)
+		adminRoutes.DELETE("/roles/:name", c.DeleteRole)
+		adminRoutes.GET("/permissions", c.ListPermissions)
+	}
+}
+
+// roleErrorStatus maps RBAC service errors to HTTP status codes
+func roleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, rbac.ErrRoleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, rbac.ErrRoleExists), errors.Is(err, rbac.ErrBuiltinRole), errors.Is(err, rbac.ErrRoleInUse):
+		return http.StatusConflict
+	case errors.Is(err, rbac.ErrUnknownPermission):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// toRoleResponse converts a role with loaded permissions to its response model
+func toRoleResponse(r *ent.Role) model.RoleResponse {
+	perms := make([]string, 0, len(r.Edges.Permissions))
+	for _, p := range r.Edges.Permissions {
+		perms = append(perms, p.Name)
+	}
+	return model.RoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Builtin:     r.Builtin,
+		Permissions: perms,
+		CreatedAt:   r.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   r.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
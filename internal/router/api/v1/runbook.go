@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/runbook"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+type RunbookController struct {
+	runbookService runbook.RunbookService
+}
+
+func NewRunbookController(runbookService runbook.RunbookService) *RunbookController {
+	return &RunbookController{
+		runbookService: runbookService,
+	}
+}
+
+// ListActions lists the available runbook actions (admin only)
+func (c *RunbookController) ListActions(ctx *gin.Context) {
+	actions := c.runbookService.ListActions()
+	responses := make([]model.RunbookActionResponse, 0, len(actions))
+	for _, action := range actions {
+		responses = append(responses, model.RunbookActionResponse{
+			Name:        action.Name,
+			Description: action.Description,
+			Params:      action.Params,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// RunAction runs a runbook action (admin only). Every attempt is written to the audit log.
+func (c *RunbookController) RunAction(ctx *gin.Context) {
+	var input model.RunRunbookActionInput
+	// The body is optional for actions without parameters
+	if err := ctx.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, bindErrorResponse(err))
+		return
+	}
+
+	name := ctx.Param("name")
+	adminID := ctx.GetString("userID")
+	result, err := c.runbookService.Run(ctx, name, input.Params)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("【运维操作】管理员 %s (%s) 执行 %s 失败，参数: %v，错误: %v", adminID, ctx.ClientIP(), name, input.Params, err)
+		if errors.Is(err, runbook.ErrUnknownAction) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	logger.FromContext(ctx).Infof("【运维操作】管理员 %s (%s) 执行 %s，参数: %v，结果: %s", adminID, ctx.ClientIP(), name, input.Params, result)
+
+	ctx.JSON(http.StatusOK, model.RunbookResultResponse{Action: name, Result: result})
+}
+
+// RegisterRoutes registers the runbook routes
+func (c *RunbookController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware, rateLimit gin.HandlerFunc) {
+	runbookRoutes := router.Group("/admin/runbook")
+	runbookRoutes.Use(authMiddleware, adminMiddleware)
+	{
+		runbookRoutes.GET("/actions", c.ListActions)
+		runbookRoutes.POST("/actions/:name", rateLimit, c.RunAction)
+	}
+}
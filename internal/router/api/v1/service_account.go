@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/serviceaccount"
+)
+
+type ServiceAccountController struct {
+	serviceAccountService serviceaccount.ServiceAccountService
+}
+
+func NewServiceAccountController(serviceAccountService serviceaccount.ServiceAccountService) *ServiceAccountController {
+	return &ServiceAccountController{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// CreateServiceAccount creates a service account and returns its token (admin only)
+func (c *ServiceAccountController) CreateServiceAccount(ctx *gin.Context) {
+	var input model.CreateServiceAccountInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	account, token, err := c.serviceAccountService.CreateServiceAccount(ctx, input, ctx.GetString("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.ServiceAccountTokenResponse{
+		ServiceAccount: toServiceAccountResponse(account),
+		Token:          token,
+	})
+}
+
+// ListServiceAccounts lists all service accounts (admin only)
+func (c *ServiceAccountController) ListServiceAccounts(ctx *gin.Context) {
+	accounts, err := c.serviceAccountService.ListServiceAccounts(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.ServiceAccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		responses = append(responses, toServiceAccountResponse(account))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// GetServiceAccount retrieves a service account by ID (admin only)
+func (c *ServiceAccountController) GetServiceAccount(ctx *gin.Context) {
+	account, err := c.serviceAccountService.GetServiceAccount(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toServiceAccountResponse(account))
+}
+
+// RotateServiceAccount issues a new token for a service account (admin only)
+func (c *ServiceAccountController) RotateServiceAccount(ctx *gin.Context) {
+	var input model.RotateServiceAccountInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	account, token, err := c.serviceAccountService.RotateServiceAccount(ctx, ctx.Param("id"), input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.ServiceAccountTokenResponse{
+		ServiceAccount: toServiceAccountResponse(account),
+		Token:          token,
+	})
+}
+
+// RevokeServiceAccount revokes a service account (admin only)
+func (c *ServiceAccountController) RevokeServiceAccount(ctx *gin.Context) {
+	if err := c.serviceAccountService.RevokeServiceAccount(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "service account revoked successfully"})
+}
+
+// RegisterRoutes registers the service account routes
+func (c *ServiceAccountController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/service-accounts")
+	adminRoutes.Use(authMiddleware, adminMiddleware)
+	{
+		adminRoutes.POST("", c.CreateServiceAccount)
+		adminRoutes.GET("", c.ListServiceAccounts)
+		adminRoutes.GET("/:id", c.GetServiceAccount)
+		adminRoutes.POST("/:id/rotate", c.RotateServiceAccount)
+		adminRoutes.DELETE("/:id", c.RevokeServiceAccount)
+	}
+}
+
+// toServiceAccountResponse converts a service account entity to its response model
+func toServiceAccountResponse(account *ent.ServiceAccount) model.ServiceAccountResponse {
+	response := model.ServiceAccountResponse{
+		ID:           account.ID,
+		Name:         account.Name,
+		Description:  account.Description,
+		TokenPrefix:  account.TokenPrefix,
+		Scopes:       account.Scopes,
+		AllowedCIDRs: account.AllowedCidrs,
+		ExpiresAt:    account.ExpiresAt.UTC().Format(time.RFC3339),
+		LastUsedIP:   account.LastUsedIP,
+		UsageCount:   account.UsageCount,
+		CreatedBy:    account.CreatedBy,
+		CreatedAt:    account.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if account.RevokedAt != nil {
+		revokedAt := account.RevokedAt.UTC().Format(time.RFC3339)
+		response.RevokedAt = &revokedAt
+	}
+	if account.LastUsedAt != nil {
+		lastUsedAt := account.LastUsedAt.UTC().Format(time.RFC3339)
+		response.LastUsedAt = &lastUsedAt
+	}
+	return response
+}
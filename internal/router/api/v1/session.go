@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/internal/service/refreshtoken"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type SessionController struct {
+	refreshTokenService refreshtoken.RefreshTokenService
+}
+
+func NewSessionController(refreshTokenService refreshtoken.RefreshTokenService) *SessionController {
+	return &SessionController{
+		refreshTokenService: refreshTokenService,
+	}
+}
+
+// ListMySessions lists the active sessions of the current user
+func (c *SessionController) ListMySessions(ctx *gin.Context) {
+	c.listSessions(ctx, ctx.GetString("userID"))
+}
+
+// RevokeMySession signs the current user out of one of their sessions
+func (c *SessionController) RevokeMySession(ctx *gin.Context) {
+	c.revokeSession(ctx, ctx.GetString("userID"), ctx.Param("id"))
+}
+
+// ListUserSessions lists the active sessions of a user (admin only)
+func (c *SessionController) ListUserSessions(ctx *gin.Context) {
+	c.listSessions(ctx, ctx.Param("id"))
+}
+
+// RevokeUserSession signs a user out of one of their sessions (admin only)
+func (c *SessionController) RevokeUserSession(ctx *gin.Context) {
+	c.revokeSession(ctx, ctx.Param("id"), ctx.Param("sessionId"))
+}
+
+// listSessions writes the active sessions of a user
+func (c *SessionController) listSessions(ctx *gin.Context, userID string) {
+	sessions, err := c.refreshTokenService.ListSessions(ctx, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, refreshtoken.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	currentSessionID := ""
+	if claims, ok := middleware.ClaimsFromContext(ctx); ok {
+		currentSessionID = claims.SessionID
+	}
+	responses := make([]model.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response := toSessionResponse(session)
+		response.Current = session.ID == currentSessionID
+		responses = append(responses, response)
+	}
+	ctx.JSON(http.StatusOK, model.SessionListResponse{Sessions: responses})
+}
+
+// revokeSession revokes a session of a user on behalf of the current user
+func (c *SessionController) revokeSession(ctx *gin.Context, userID, sessionID string) {
+	err := c.refreshTokenService.RevokeSession(ctx, userID, sessionID, ctx.GetString("userID"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, refreshtoken.ErrSessionNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "session revoked"})
+}
+
+// RegisterRoutes registers the session routes. requirePermission builds the middleware
+// that checks the caller's role grants a permission.
+func (c *SessionController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, requirePermission func(string) gin.HandlerFunc) {
+	router.GET("/users/me/sessions", authMiddleware, c.ListMySessions)
+	router.DELETE("/users/me/sessions/:id", authMiddleware, c.RevokeMySession)
+	router.GET("/admin/users/:id/sessions", authMiddleware, requirePermission(rbac.PermUserRead), middleware.RequireScopes("users:read"), c.ListUserSessions)
+	router.DELETE("/admin/users/:id/sessions/:sessionId", authMiddleware, requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.RevokeUserSession)
+}
+
+// toSessionResponse converts a session to its response model
+func toSessionResponse(session refreshtoken.Session) model.SessionResponse {
+	response := model.SessionResponse{
+		ID:             session.ID,
+		IP:             session.IP,
+		UserAgent:      session.UserAgent,
+		ImpersonatedBy: session.ImpersonatedBy,
+		ClientID:       session.ClientID,
+		RefreshedAt:    session.RefreshedAt.UTC().Format(time.RFC3339),
+		ExpiresAt:      session.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+	if !session.SignedInAt.IsZero() {
+		response.SignedInAt = session.SignedInAt.UTC().Format(time.RFC3339)
+	}
+	return response
+}
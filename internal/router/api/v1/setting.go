@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/setting"
+)
+
+type SettingController struct {
+	settingService setting.SettingService
+}
+
+func NewSettingController(settingService setting.SettingService) *SettingController {
+	return &SettingController{
+		settingService: settingService,
+	}
+}
+
+// ListSettings lists all settings and feature flags (admin only)
+func (c *SettingController) ListSettings(ctx *gin.Context) {
+	settings, err := c.settingService.ListSettings(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]model.SettingResponse, 0, len(settings))
+	for _, st := range settings {
+		responses = append(responses, toSettingResponse(st))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// GetSetting retrieves a setting by key (admin only)
+func (c *SettingController) GetSetting(ctx *gin.Context) {
+	st, err := c.settingService.GetSetting(ctx, ctx.Param("key"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSettingResponse(st))
+}
+
+// UpdateSetting creates or updates a setting (admin only)
+func (c *SettingController) UpdateSetting(ctx *gin.Context) {
+	var input model.UpdateSettingInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	st, err := c.settingService.UpsertSetting(ctx, ctx.Param("key"), input, ctx.GetString("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSettingResponse(st))
+}
+
+// DeleteSetting deletes a setting (admin only)
+func (c *SettingController) DeleteSetting(ctx *gin.Context) {
+	if err := c.settingService.DeleteSetting(ctx, ctx.Param("key")); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "setting deleted successfully"})
+}
+
+// RegisterRoutes registers the setting routes
+func (c *SettingController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/settings")
+	adminRoutes.Use(authMiddleware, adminMiddleware)
+	{
+		adminRoutes.GET("", c.ListSettings)
+		adminRoutes.GET("/:key", c.GetSetting)
+		adminRoutes.PUT("/:key", c.UpdateSetting)
+		adminRoutes.DELETE("/:key", c.DeleteSetting)
+	}
+}
+
+// toSettingResponse converts a setting entity to its response model
+func toSettingResponse(st *ent.Setting) model.SettingResponse {
+	return model.SettingResponse{
+		Key:         st.Key,
+		Value:       st.Value,
+		Description: st.Description,
+		UpdatedBy:   st.UpdatedBy,
+		UpdatedAt:   st.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/stats"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type StatsController struct {
+	statsService stats.StatsService
+}
+
+func NewStatsController(statsService stats.StatsService) *StatsController {
+	return &StatsController{
+		statsService: statsService,
+	}
+}
+
+// UserStats summarizes the users by role and status, signups per day and last logins (admin only)
+func (c *StatsController) UserStats(ctx *gin.Context) {
+	var filter model.UserStatsFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, bindErrorResponse(err))
+		return
+	}
+
+	response, err := c.statsService.UserStats(ctx, filter.Days)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RegisterRoutes registers the statistics routes
+func (c *StatsController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	router.GET("/admin/stats/users", authMiddleware, adminMiddleware, middleware.RequireScopes("users:read"), c.UserStats)
+}
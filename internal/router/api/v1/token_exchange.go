@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/tokenexchange"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type TokenExchangeController struct {
+	tokenExchangeService tokenexchange.TokenExchangeService
+	cookies              *middleware.TokenCookies
+}
+
+// NewTokenExchangeController creates the token exchange controller. With cookies set, tokens
+// are delivered in HttpOnly cookies instead of the response body.
+func NewTokenExchangeController(tokenExchangeService tokenexchange.TokenExchangeService, cookies *middleware.TokenCookies) *TokenExchangeController {
+	return &TokenExchangeController{
+		tokenExchangeService: tokenExchangeService,
+		cookies:              cookies,
+	}
+}
+
+// Exchange issues a token pair for the user identified by a trusted external token
+func (c *TokenExchangeController) Exchange(ctx *gin.Context) {
+	var input model.TokenExchangeInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+
+	tokens, user, err := c.tokenExchangeService.Exchange(ctx, input.SubjectToken)
+	if err != nil {
+		if tokenexchange.IsDenied(err) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		logger.FromContext(ctx).Errorf("Failed to exchange token: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to exchange token"})
+		return
+	}
+	ctx.Set(middleware.AuditActorKey, user.ID)
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+
+	tokens = deliverTokens(ctx, c.cookies, tokens)
+	ctx.JSON(http.StatusOK, model.AuthResponse{
+		User:               userResponse,
+		AccessToken:        tokens.AccessToken,
+		RefreshToken:       tokens.RefreshToken,
+		ExpiresIn:          tokens.ExpiresIn,
+		MustChangePassword: user.MustChangePassword,
+	})
+}
+
+// RegisterRoutes registers the token exchange route. guards run before the handler, e.g. rate limiting.
+func (c *TokenExchangeController) RegisterRoutes(router *gin.RouterGroup, guards gin.HandlersChain) {
+	router.POST("/auth/token/exchange", append(guards, c.Exchange)...)
+}
@@ -1,21 +1,30 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
 	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/presence"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
 	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
 )
 
 type UserController struct {
-	userService user.UserService
+	userService     user.UserService
+	presenceService presence.PresenceService
 }
 
-func NewUserController(userService user.UserService) *UserController {
+func NewUserController(userService user.UserService, presenceService presence.PresenceService) *UserController {
 	return &UserController{
-		userService: userService,
+		userService:     userService,
+		presenceService: presenceService,
 	}
 }
 
@@ -35,14 +44,16 @@ func (c *UserController) GetCurrentUser(ctx *gin.Context) {
 
 	// Convert to response model
 	userResponse := model.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		AvatarURL: &user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 
 	ctx.JSON(http.StatusOK, userResponse)
@@ -57,8 +68,11 @@ func (c *UserController) UpdateCurrentUser(ctx *gin.Context) {
 	}
 
 	var input model.UpdateUserInput
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	if len(input.Roles) > 0 {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "roles can only be changed by an admin"})
 		return
 	}
 
@@ -70,14 +84,16 @@ func (c *UserController) UpdateCurrentUser(ctx *gin.Context) {
 
 	// Convert to response model
 	userResponse := model.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		AvatarURL: &user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 
 	ctx.JSON(http.StatusOK, userResponse)
@@ -90,21 +106,119 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
 		return
 	}
+	// 模拟登录时禁止修改登录凭据
+	if ctx.GetString("impersonatedBy") != "" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "not allowed while impersonating"})
+		return
+	}
 
 	var input model.ChangePasswordInput
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(ctx, &input) {
 		return
 	}
 
 	if err := c.userService.UpdatePassword(ctx, userID, input.CurrentPassword, input.NewPassword); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
+	// 强制修改密码时旧令牌已被吊销，需要重新登录
+	if claims, ok := middleware.ClaimsFromContext(ctx); ok && claims.PasswordChangeRequired {
+		ctx.JSON(http.StatusOK, gin.H{"message": "password updated successfully, log in again"})
+		return
+	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "password updated successfully"})
 }
 
+// ListUsers lists the users matching the query filters, oldest first (admin only)
+func (c *UserController) ListUsers(ctx *gin.Context) {
+	var filter model.UserFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, bindErrorResponse(err))
+		return
+	}
+
+	users, total, err := c.userService.ListUsers(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, pageSize := user.Pagination(filter)
+	responses := make([]model.UserResponse, 0, len(users))
+	for _, u := range users {
+		resp := model.UserResponse{
+			ID:            u.ID,
+			Email:         u.Email,
+			Username:      u.Username,
+			Roles:         u.Roles,
+			Active:        u.Active,
+			AvatarURL:     &u.AvatarURL,
+			Phone:         u.Phone,
+			PhoneVerified: u.PhoneVerified,
+			CreatedAt:     u.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt:     u.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		c.addActivity(ctx, &resp, u)
+		responses = append(responses, resp)
+	}
+
+	ctx.JSON(http.StatusOK, model.UserListResponse{
+		Users:    responses,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// CreateUser provisions a user with a temporary password (admin only). It works whether or
+// not public registration is enabled. Only admins can create users with the admin role.
+func (c *UserController) CreateUser(ctx *gin.Context) {
+	var input model.AdminCreateUserInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	if len(input.Roles) == 0 {
+		input.Roles = []string{rbac.RoleUser}
+	}
+
+	newUser, err := c.userService.CreateUser(ctx, model.CreateUserInput{
+		Email:              input.Email,
+		Username:           input.Username,
+		Password:           input.Password,
+		Roles:              input.Roles,
+		MustChangePassword: input.MustChangePassword,
+	})
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, user.ErrEmailExists) || errors.Is(err, user.ErrUsernameExists):
+			status = http.StatusConflict
+		case errors.Is(err, user.ErrAdminRequired):
+			status = http.StatusForbidden
+		}
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            newUser.ID,
+		Email:         newUser.Email,
+		Username:      newUser.Username,
+		Roles:         newUser.Roles,
+		Active:        newUser.Active,
+		AvatarURL:     &newUser.AvatarURL,
+		Phone:         newUser.Phone,
+		PhoneVerified: newUser.PhoneVerified,
+		CreatedAt:     newUser.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     newUser.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	c.addActivity(ctx, &userResponse, newUser)
+
+	ctx.JSON(http.StatusCreated, userResponse)
+}
+
 // GetUser retrieves a user by ID (admin only)
 func (c *UserController) GetUser(ctx *gin.Context) {
 	userIDStr := ctx.Param("id")
@@ -121,15 +235,18 @@ func (c *UserController) GetUser(ctx *gin.Context) {
 
 	// Convert to response model
 	userResponse := model.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		AvatarURL: &user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
 	}
+	c.addActivity(ctx, &userResponse, user)
 
 	ctx.JSON(http.StatusOK, userResponse)
 }
@@ -143,28 +260,92 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 	}
 
 	var input model.UpdateUserInput
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	updated, err := c.userService.UpdateUser(ctx, userIDStr, input)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, user.ErrAdminRequired):
+			status = http.StatusForbidden
+		case errors.Is(err, user.ErrLastAdmin):
+			status = http.StatusConflict
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            updated.ID,
+		Email:         updated.Email,
+		Username:      updated.Username,
+		Roles:         updated.Roles,
+		Active:        updated.Active,
+		AvatarURL:     &updated.AvatarURL,
+		Phone:         updated.Phone,
+		PhoneVerified: updated.PhoneVerified,
+		CreatedAt:     updated.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     updated.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	c.addActivity(ctx, &userResponse, updated)
+
+	ctx.JSON(http.StatusOK, userResponse)
+}
+
+// ChangeRoles grants and revokes roles of a user (admin only). Only admins can grant or
+// revoke the admin role, the last active admin cannot lose it, and the user's tokens are
+// revoked so the change applies at once.
+func (c *UserController) ChangeRoles(ctx *gin.Context) {
+	userIDStr := ctx.Param("id")
+	if userIDStr == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	var input model.ChangeRolesInput
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	if len(input.Grant) == 0 && len(input.Revoke) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "grant or revoke is required"})
 		return
 	}
+	ctx.Set(middleware.AuditDetailsKey, fmt.Sprintf("grant=%s revoke=%s",
+		strings.Join(input.Grant, ","), strings.Join(input.Revoke, ",")))
 
-	user, err := c.userService.UpdateUser(ctx, userIDStr, input)
+	updated, err := c.userService.ChangeRoles(ctx, userIDStr, input.Grant, input.Revoke)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, user.ErrAdminRequired):
+			status = http.StatusForbidden
+		case errors.Is(err, user.ErrLastAdmin):
+			status = http.StatusConflict
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Convert to response model
 	userResponse := model.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		AvatarURL: &user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+		ID:            updated.ID,
+		Email:         updated.Email,
+		Username:      updated.Username,
+		Roles:         updated.Roles,
+		Active:        updated.Active,
+		AvatarURL:     &updated.AvatarURL,
+		Phone:         updated.Phone,
+		PhoneVerified: updated.PhoneVerified,
+		CreatedAt:     updated.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     updated.UpdatedAt.UTC().Format(time.RFC3339),
 	}
+	c.addActivity(ctx, &userResponse, updated)
 
 	ctx.JSON(http.StatusOK, userResponse)
 }
@@ -178,15 +359,209 @@ func (c *UserController) DeleteUser(ctx *gin.Context) {
 	}
 
 	if err := c.userService.DeleteUser(ctx, userIDStr); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(removalErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
 }
 
-// RegisterRoutes registers the user routes
-func (c *UserController) RegisterRoutes(router *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+// RestoreUser restores a soft-deleted user (admin only)
+func (c *UserController) RestoreUser(ctx *gin.Context) {
+	userIDStr := ctx.Param("id")
+	if userIDStr == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	user, err := c.userService.RestoreUser(ctx, userIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	c.addActivity(ctx, &userResponse, user)
+
+	ctx.JSON(http.StatusOK, userResponse)
+}
+
+// ActivateUser lets a deactivated user log in again (admin only)
+func (c *UserController) ActivateUser(ctx *gin.Context) {
+	c.setActive(ctx, true)
+}
+
+// DeactivateUser locks a user out and ends their sessions (admin only)
+func (c *UserController) DeactivateUser(ctx *gin.Context) {
+	c.setActive(ctx, false)
+}
+
+// setActive activates or deactivates the user in the path; either way the user's
+// outstanding tokens are revoked
+func (c *UserController) setActive(ctx *gin.Context, active bool) {
+	userIDStr := ctx.Param("id")
+	if userIDStr == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID is required"})
+		return
+	}
+	user, err := c.userService.SetActive(ctx, userIDStr, active)
+	if err != nil {
+		ctx.JSON(removalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	c.addActivity(ctx, &userResponse, user)
+
+	ctx.JSON(http.StatusOK, userResponse)
+}
+
+// removalErrorStatus maps the errors of deactivating or deleting a user to a status code
+func removalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, user.ErrLastAdmin):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// RequirePasswordChange makes a user change their password before doing anything else
+// (admin only). The user's sessions end and their next login is restricted.
+func (c *UserController) RequirePasswordChange(ctx *gin.Context) {
+	c.setMustChangePassword(ctx, true)
+}
+
+// ClearPasswordChange lifts a password change requirement (admin only)
+func (c *UserController) ClearPasswordChange(ctx *gin.Context) {
+	c.setMustChangePassword(ctx, false)
+}
+
+// setMustChangePassword sets or clears the password change requirement of the user in the path
+func (c *UserController) setMustChangePassword(ctx *gin.Context, required bool) {
+	userIDStr := ctx.Param("id")
+	if userIDStr == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	user, err := c.userService.SetMustChangePassword(ctx, userIDStr, required)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	c.addActivity(ctx, &userResponse, user)
+
+	ctx.JSON(http.StatusOK, userResponse)
+}
+
+// ImpersonateUser issues a short-lived token pair acting as the target user (admin only)
+func (c *UserController) ImpersonateUser(ctx *gin.Context) {
+	userIDStr := ctx.Param("id")
+	if userIDStr == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user ID is required"})
+		return
+	}
+
+	// Impersonation needs a human admin behind it
+	adminID := ctx.GetString("userID")
+	if adminID == "" || ctx.GetBool("serviceAuthenticated") {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "impersonation requires an admin user"})
+		return
+	}
+	// 模拟登录时不能再次模拟其他用户
+	if ctx.GetString("impersonatedBy") != "" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "not allowed while impersonating"})
+		return
+	}
+
+	tokens, user, err := c.userService.Impersonate(ctx, adminID, userIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Convert to response model
+	userResponse := model.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         user.Roles,
+		Active:        user.Active,
+		AvatarURL:     &user.AvatarURL,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+
+	ctx.JSON(http.StatusOK, model.AuthResponse{
+		User:         userResponse,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	})
+}
+
+// addActivity adds when the user last logged in and was last seen to an admin response,
+// along with whether they must change their password
+func (c *UserController) addActivity(ctx *gin.Context, resp *model.UserResponse, u *ent.User) {
+	resp.MustChangePassword = &u.MustChangePassword
+	if u.LastLogin != nil {
+		lastLogin := u.LastLogin.UTC().Format(time.RFC3339)
+		resp.LastLogin = &lastLogin
+	}
+
+	p := c.presenceService.Presence(ctx, u)
+	if p.LastSeen != nil {
+		lastSeen := p.LastSeen.UTC().Format(time.RFC3339)
+		resp.LastSeen = &lastSeen
+	}
+	resp.Online = &p.Online
+}
+
+// RegisterRoutes registers the user routes. requirePermission builds the middleware
+// that checks the caller's role grants a permission.
+func (c *UserController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, requirePermission func(string) gin.HandlerFunc) {
 	// Routes for authenticated users
 	userRoutes := router.Group("/users")
 	userRoutes.Use(authMiddleware)
@@ -198,10 +573,19 @@ func (c *UserController) RegisterRoutes(router *gin.RouterGroup, authMiddleware,
 
 	// Routes for admin users
 	adminRoutes := router.Group("/admin/users")
-	adminRoutes.Use(authMiddleware, adminMiddleware)
+	adminRoutes.Use(authMiddleware)
 	{
-		adminRoutes.GET("/:id", c.GetUser)
-		adminRoutes.PUT("/:id", c.UpdateUser)
-		adminRoutes.DELETE("/:id", c.DeleteUser)
+		adminRoutes.GET("", requirePermission(rbac.PermUserRead), middleware.RequireScopes("users:read"), c.ListUsers)
+		adminRoutes.POST("", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.CreateUser)
+		adminRoutes.GET("/:id", requirePermission(rbac.PermUserRead), middleware.RequireScopes("users:read"), c.GetUser)
+		adminRoutes.PUT("/:id", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.UpdateUser)
+		adminRoutes.POST("/:id/roles", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.ChangeRoles)
+		adminRoutes.DELETE("/:id", requirePermission(rbac.PermUserDelete), middleware.RequireScopes("users:delete"), c.DeleteUser)
+		adminRoutes.POST("/:id/restore", requirePermission(rbac.PermUserDelete), middleware.RequireScopes("users:delete"), c.RestoreUser)
+		adminRoutes.POST("/:id/activate", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.ActivateUser)
+		adminRoutes.POST("/:id/deactivate", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.DeactivateUser)
+		adminRoutes.POST("/:id/password-change", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.RequirePasswordChange)
+		adminRoutes.DELETE("/:id/password-change", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.ClearPasswordChange)
+		adminRoutes.POST("/:id/impersonate", requirePermission(rbac.PermUserImpersonate), middleware.RequireScopes("users:impersonate"), c.ImpersonateUser)
 	}
 }
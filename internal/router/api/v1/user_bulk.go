@@ -0,0 +1,159 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/internal/service/userbulk"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+type UserBulkController struct {
+	bulkService userbulk.UserBulkService
+	maxBytes    int64
+	asyncRows   int
+}
+
+// NewUserBulkController creates a controller for importing and exporting users. Import files
+// are capped at maxBytes, and imports with more than asyncRows rows run in the background.
+func NewUserBulkController(bulkService userbulk.UserBulkService, maxBytes int64, asyncRows int) *UserBulkController {
+	return &UserBulkController{
+		bulkService: bulkService,
+		maxBytes:    maxBytes,
+		asyncRows:   asyncRows,
+	}
+}
+
+// ExportUsers streams every user as a CSV or JSON file (admin only)
+func (c *UserBulkController) ExportUsers(ctx *gin.Context) {
+	format, err := userbulk.ParseFormat(ctx.DefaultQuery("format", string(userbulk.FormatCSV)))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == userbulk.FormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	filename := fmt.Sprintf("users-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Status(http.StatusOK)
+
+	// The status is already sent, so a failure can only cut the file short
+	if count, err := c.bulkService.ExportUsers(ctx, ctx.Writer, format); err != nil {
+		logger.Warnf("User export failed after %d users: %v", count, err)
+	}
+}
+
+// ImportUsers imports users from a CSV or JSON file, sent as the "file" field of a
+// multipart form or as the request body (admin only). Small imports return the report;
+// large ones, or any with async=true, start a background job and return 202.
+func (c *UserBulkController) ImportUsers(ctx *gin.Context) {
+	onDuplicate, err := userbulk.ParseDuplicatePolicy(ctx.Query("on_duplicate"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.maxBytes)
+	var body io.Reader = ctx.Request.Body
+	formatName := ctx.Query("format")
+	if file, header, err := ctx.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		body = file
+		if formatName == "" {
+			formatName = header.Filename
+		}
+	} else if errors.Is(err, http.ErrNotMultipart) {
+		if formatName == "" && ctx.ContentType() == "application/json" {
+			formatName = string(userbulk.FormatJSON)
+		}
+	} else {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": importReadError(err, c.maxBytes)})
+		return
+	}
+	if formatName == "" {
+		formatName = string(userbulk.FormatCSV)
+	}
+	format, err := userbulk.ParseFormat(formatName)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := c.bulkService.ParseImport(body, format)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": importReadError(err, c.maxBytes)})
+		return
+	}
+
+	async, _ := strconv.ParseBool(ctx.Query("async"))
+	if async || len(rows) > c.asyncRows {
+		job := c.bulkService.StartImport(ctx, rows, onDuplicate)
+		ctx.Header("Location", "/api/v1/admin/users/import/"+job.ID)
+		ctx.JSON(http.StatusAccepted, toImportJobResponse(job))
+		return
+	}
+
+	report, err := c.bulkService.ImportUsers(ctx, rows, onDuplicate)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "report": report})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// GetImportJob returns the status of a background import (admin only)
+func (c *UserBulkController) GetImportJob(ctx *gin.Context) {
+	job, err := c.bulkService.GetImportJob(ctx, ctx.Param("jobId"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, toImportJobResponse(job))
+}
+
+// importReadError explains a failure to read an import file
+func importReadError(err error, maxBytes int64) string {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return fmt.Sprintf("import file exceeds %d bytes", maxBytes)
+	}
+	return err.Error()
+}
+
+func toImportJobResponse(job *userbulk.ImportJob) model.ImportJobResponse {
+	resp := model.ImportJobResponse{
+		ID:        job.ID,
+		Status:    string(job.Status),
+		Rows:      job.Rows,
+		CreatedAt: job.CreatedAt.UTC().Format(time.RFC3339),
+		Report:    job.Report,
+		Error:     job.Error,
+	}
+	if !job.FinishedAt.IsZero() {
+		resp.FinishedAt = job.FinishedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
+
+// RegisterRoutes registers the bulk user routes
+func (c *UserBulkController) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, requirePermission func(string) gin.HandlerFunc) {
+	adminRoutes := router.Group("/admin/users")
+	adminRoutes.Use(authMiddleware)
+	{
+		adminRoutes.GET("/export", requirePermission(rbac.PermUserRead), middleware.RequireScopes("users:read"), c.ExportUsers)
+		adminRoutes.POST("/import", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.ImportUsers)
+		adminRoutes.GET("/import/:jobId", requirePermission(rbac.PermUserUpdate), middleware.RequireScopes("users:write"), c.GetImportJob)
+	}
+}
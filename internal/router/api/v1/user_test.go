@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/presence"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/pkg/actorctx"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// fakeUserService records the users it is asked to create or impersonate and, like
+// DBUserService, refuses to create admins for callers who are not admins; the other
+// methods are not used
+type fakeUserService struct {
+	user.UserService
+	created      []model.CreateUserInput
+	impersonated []string
+}
+
+func (s *fakeUserService) CreateUser(ctx context.Context, input model.CreateUserInput) (*ent.User, error) {
+	if slices.Contains(input.Roles, rbac.RoleAdmin) && !slices.Contains(actorctx.Roles(ctx), rbac.RoleAdmin) {
+		return nil, user.ErrAdminRequired
+	}
+	s.created = append(s.created, input)
+	return &ent.User{ID: "u1", Email: input.Email, Username: input.Username, Roles: input.Roles}, nil
+}
+
+func (s *fakeUserService) Impersonate(ctx context.Context, adminID, targetID string) (*jwt.TokenPair, *ent.User, error) {
+	s.impersonated = append(s.impersonated, targetID)
+	return &jwt.TokenPair{AccessToken: "access"}, &ent.User{ID: targetID}, nil
+}
+
+// fakePresenceService reports every user as never seen
+type fakePresenceService struct {
+	presence.PresenceService
+}
+
+func (fakePresenceService) Presence(ctx context.Context, u *ent.User) presence.Presence {
+	return presence.Presence{}
+}
+
+func TestCreateUserAdminRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		callerRole string
+		roles      string
+		wantCode   int
+	}{
+		{"non-admin cannot create an admin", "user_manager", `["admin"]`, http.StatusForbidden},
+		{"service account cannot create an admin", "service", `["user","admin"]`, http.StatusForbidden},
+		{"non-admin creates a user", "user_manager", `["user"]`, http.StatusCreated},
+		{"admin creates an admin", "admin", `["admin"]`, http.StatusCreated},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeUserService{}
+			controller := NewUserController(service, fakePresenceService{})
+			router := gin.New()
+			router.POST("/admin/users", func(c *gin.Context) {
+				c.Set("userID", "caller")
+				c.Set("roles", []string{tt.callerRole})
+			}, controller.CreateUser)
+
+			body := `{"email":"new@example.com","username":"new","password":"Secret123!","roles":` + tt.roles + `}`
+			req := httptest.NewRequest(http.MethodPost, "/admin/users", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if created := len(service.created) > 0; created != (tt.wantCode == http.StatusCreated) {
+				t.Errorf("user created = %v with status %d", created, w.Code)
+			}
+		})
+	}
+}
+
+func TestImpersonateUserChain(t *testing.T) {
+	tests := []struct {
+		name           string
+		impersonatedBy string
+		wantCode       int
+	}{
+		{"admin impersonates a user", "", http.StatusOK},
+		{"impersonation session impersonates another user", "admin", http.StatusForbidden},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeUserService{}
+			controller := NewUserController(service, fakePresenceService{})
+			router := gin.New()
+			router.POST("/admin/users/:id/impersonate", func(c *gin.Context) {
+				c.Set("userID", "caller")
+				if tt.impersonatedBy != "" {
+					c.Set("impersonatedBy", tt.impersonatedBy)
+				}
+			}, controller.ImpersonateUser)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/users/u2/impersonate", nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if started := len(service.impersonated) > 0; started != (tt.wantCode == http.StatusOK) {
+				t.Errorf("impersonation started = %v with status %d", started, w.Code)
+			}
+		})
+	}
+}
@@ -1,39 +1,559 @@
 package router
 
 import (
-	"time"
+	"context"
+	"expvar"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/hewenyu/gin-pkg/config"
 	v1 "github.com/hewenyu/gin-pkg/internal/router/api/v1"
+	"github.com/hewenyu/gin-pkg/internal/service/activity"
+	"github.com/hewenyu/gin-pkg/internal/service/appcredential"
+	"github.com/hewenyu/gin-pkg/internal/service/audit"
+	"github.com/hewenyu/gin-pkg/internal/service/emailchange"
+	"github.com/hewenyu/gin-pkg/internal/service/group"
+	"github.com/hewenyu/gin-pkg/internal/service/invitation"
+	"github.com/hewenyu/gin-pkg/internal/service/oauth"
+	"github.com/hewenyu/gin-pkg/internal/service/organization"
+	"github.com/hewenyu/gin-pkg/internal/service/otp"
+	"github.com/hewenyu/gin-pkg/internal/service/preference"
+	"github.com/hewenyu/gin-pkg/internal/service/presence"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/internal/service/refreshtoken"
+	"github.com/hewenyu/gin-pkg/internal/service/runbook"
+	"github.com/hewenyu/gin-pkg/internal/service/serviceaccount"
+	"github.com/hewenyu/gin-pkg/internal/service/setting"
+	"github.com/hewenyu/gin-pkg/internal/service/stats"
+	"github.com/hewenyu/gin-pkg/internal/service/tokenexchange"
 	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/internal/service/userbulk"
+	"github.com/hewenyu/gin-pkg/pkg/auth/captcha"
 	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
 	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+	"github.com/hewenyu/gin-pkg/pkg/avatar"
+	"github.com/hewenyu/gin-pkg/pkg/health"
+	"github.com/hewenyu/gin-pkg/pkg/i18n"
 	"github.com/hewenyu/gin-pkg/pkg/middleware"
 )
 
+// Dependencies holds the configuration and services the API routes are built from
+type Dependencies struct {
+	Config                *config.Config
+	UserService           user.UserService
+	UserBulkService       userbulk.UserBulkService
+	TokenService          jwt.TokenService
+	SecurityService       security.SecurityService
+	ServiceAccountService serviceaccount.ServiceAccountService
+	SettingService        setting.SettingService
+	EmailChangeService    emailchange.EmailChangeService
+	PreferenceService     preference.PreferenceService
+	MessageCatalog        *i18n.Catalog
+	PresenceService       presence.PresenceService
+	ActivityService       activity.ActivityService
+	RefreshTokenService   refreshtoken.RefreshTokenService
+	StatsService          stats.StatsService
+	AvatarProvider        avatar.Provider
+	AvatarUploader        *avatar.Uploader
+	CaptchaVerifier       captcha.Verifier
+	RunbookService        runbook.RunbookService
+	OTPService            otp.OTPService
+	InvitationService     invitation.InvitationService
+	RBACService           rbac.RBACService
+	GroupService          group.GroupService
+	OrganizationService   organization.OrganizationService
+	AuditService          audit.AuditService
+	OAuthService          oauth.OAuthService
+	TokenExchangeService  tokenexchange.TokenExchangeService
+	AppCredentialService  appcredential.AppCredentialService
+	RateLimitCounter      middleware.RateLimitCounter
+	// SlidingRateLimitCounter counts requests for the rate limits with slidingWindow set
+	SlidingRateLimitCounter middleware.RateLimitCounter
+	// ReadinessChecks are the dependencies checked by the readiness probe
+	ReadinessChecks []health.Check
+}
+
+// serviceAccountRouteScopes lists the routes reachable with a service token and the scope each requires
+var serviceAccountRouteScopes = map[string]string{
+	"GET /api/v1/admin/users":                        "users:read",
+	"POST /api/v1/admin/users":                       "users:write",
+	"GET /api/v1/admin/stats/users":                  "users:read",
+	"GET /api/v1/admin/users/:id":                    "users:read",
+	"PUT /api/v1/admin/users/:id":                    "users:write",
+	"POST /api/v1/admin/users/:id/roles":             "users:write",
+	"DELETE /api/v1/admin/users/:id":                 "users:delete",
+	"POST /api/v1/admin/users/:id/restore":           "users:delete",
+	"POST /api/v1/admin/users/:id/activate":          "users:write",
+	"POST /api/v1/admin/users/:id/deactivate":        "users:write",
+	"POST /api/v1/admin/users/:id/password-change":   "users:write",
+	"DELETE /api/v1/admin/users/:id/password-change": "users:write",
+}
+
+// passwordChangeRoutes lists the routes still open to users who must change their password
+var passwordChangeRoutes = map[string]bool{
+	"POST /api/v1/users/change-password": true,
+	"GET /api/v1/users/me":               true,
+	"POST /api/v1/auth/logout":           true,
+}
+
+// activityRoutes lists the routes recorded in the activity timeline of the user making them,
+// and the action each is recorded as
+var activityRoutes = map[string]string{
+	"POST /api/v1/auth/login":              "login",
+	"POST /api/v1/auth/otp/verify":         "login.otp",
+	"POST /api/v1/auth/logout":             "logout",
+	"PUT /api/v1/users/me":                 "profile.update",
+	"POST /api/v1/users/change-password":   "password.change",
+	"PATCH /api/v1/users/me/email":         "email.change_request",
+	"PUT /api/v1/users/me/preferences":     "preferences.update",
+	"POST /api/v1/users/me/avatar":         "avatar.upload",
+	"DELETE /api/v1/users/me/avatar":       "avatar.delete",
+	"POST /api/v1/users/me/phone/verify":   "phone.verify",
+	"DELETE /api/v1/users/me/sessions/:id": "session.revoke",
+}
+
+// auditedRoutes lists the routes recorded in the audit log and the action each is recorded as
+var auditedRoutes = map[string]string{
+	"POST /api/v1/auth/register":                         "auth.register",
+	"POST /api/v1/auth/login":                            "auth.login",
+	"POST /api/v1/auth/otp/verify":                       "auth.login_otp",
+	"POST /api/v1/auth/token/exchange":                   "auth.token_exchange",
+	"POST /api/v1/auth/refresh":                          "auth.refresh",
+	"POST /api/v1/auth/logout":                           "auth.logout",
+	"DELETE /api/v1/users/me/sessions/:id":               "auth.session_revoke",
+	"POST /api/v1/users/change-password":                 "auth.password_change",
+	"POST /api/v1/users/me/avatar":                       "user.avatar.upload",
+	"DELETE /api/v1/users/me/avatar":                     "user.avatar.delete",
+	"POST /api/v1/groups":                                "group.create",
+	"PUT /api/v1/groups/:id":                             "group.update",
+	"DELETE /api/v1/groups/:id":                          "group.delete",
+	"POST /api/v1/groups/:id/members":                    "group.member.add",
+	"PUT /api/v1/groups/:id/members/:userId":             "group.member.update",
+	"DELETE /api/v1/groups/:id/members/:userId":          "group.member.remove",
+	"POST /api/v1/admin/users":                           "admin.user.create",
+	"PUT /api/v1/admin/users/:id":                        "admin.user.update",
+	"POST /api/v1/admin/users/:id/roles":                 "admin.user.roles",
+	"DELETE /api/v1/admin/users/:id":                     "admin.user.delete",
+	"POST /api/v1/admin/users/:id/restore":               "admin.user.restore",
+	"POST /api/v1/admin/users/:id/activate":              "admin.user.activate",
+	"POST /api/v1/admin/users/:id/deactivate":            "admin.user.deactivate",
+	"POST /api/v1/admin/users/:id/password-change":       "admin.user.password_change.require",
+	"DELETE /api/v1/admin/users/:id/password-change":     "admin.user.password_change.clear",
+	"GET /api/v1/admin/users/export":                     "admin.user.export",
+	"POST /api/v1/admin/users/import":                    "admin.user.import",
+	"POST /api/v1/admin/users/:id/impersonate":           "admin.user.impersonate",
+	"DELETE /api/v1/admin/users/:id/sessions/:sessionId": "admin.user.session_revoke",
+	"PUT /api/v1/admin/settings/:key":                    "admin.setting.update",
+	"DELETE /api/v1/admin/settings/:key":                 "admin.setting.delete",
+	"POST /api/v1/admin/invitations":                     "admin.invitation.create",
+	"DELETE /api/v1/admin/invitations/:id":               "admin.invitation.revoke",
+	"POST /api/v1/admin/orgs":                            "admin.org.create",
+	"PUT /api/v1/admin/orgs/:id":                         "admin.org.update",
+	"DELETE /api/v1/admin/orgs/:id":                      "admin.org.delete",
+	"POST /api/v1/admin/roles":                           "admin.role.create",
+	"PUT /api/v1/admin/roles/:name":                      "admin.role.update",
+	"DELETE /api/v1/admin/roles/:name":                   "admin.role.delete",
+	"POST /api/v1/admin/runbook/actions/:name":           "admin.runbook.run",
+	"POST /api/v1/admin/service-accounts":                "admin.service_account.create",
+	"POST /api/v1/admin/service-accounts/:id/rotate":     "admin.service_account.rotate",
+	"DELETE /api/v1/admin/service-accounts/:id":          "admin.service_account.revoke",
+	"POST /api/v1/admin/app-credentials":                 "admin.app_credential.create",
+	"PUT /api/v1/admin/app-credentials/:id":              "admin.app_credential.update",
+	"POST /api/v1/admin/app-credentials/:id/rotate":      "admin.app_credential.rotate",
+	"DELETE /api/v1/admin/app-credentials/:id":           "admin.app_credential.revoke",
+}
+
 // Setup configures the API routes
-func Setup(
-	router *gin.Engine,
-	userService user.UserService,
-	tokenService jwt.TokenService,
-	securityService security.SecurityService,
-	enableRegistration bool,
-	timestampValidityWindow time.Duration,
-) {
+func Setup(router *gin.Engine, deps Dependencies) {
+	cfg := deps.Config
+
 	// Set up middleware
-	authMiddleware := middleware.AuthMiddleware(tokenService)
-	securityMiddleware := middleware.SecurityMiddleware(securityService, timestampValidityWindow)
-	adminMiddleware := middleware.RoleMiddleware("admin")
+	// 访客令牌只能访问配置中列出的接口
+	var guestRoutes map[string]bool
+	if cfg.Guest.Enabled {
+		guestRoutes = make(map[string]bool, len(cfg.Guest.Routes))
+		for _, route := range cfg.Guest.Routes {
+			guestRoutes[route] = true
+		}
+	}
+	// 浏览器客户端可通过 HttpOnly Cookie 接收和携带令牌
+	var tokenCookies *middleware.TokenCookies
+	if cookieCfg := cfg.Auth.Cookies; cookieCfg.Enabled {
+		tokenCookies = &middleware.TokenCookies{
+			AccessName:    cookieCfg.AccessTokenName,
+			RefreshName:   cookieCfg.RefreshTokenName,
+			Domain:        cookieCfg.Domain,
+			Path:          cookieCfg.Path,
+			RefreshPath:   cookieCfg.RefreshPath,
+			RefreshMaxAge: max(cfg.Auth.RefreshTokenDuration, cfg.Auth.RememberMeDuration),
+			Secure:        cookieCfg.Secure,
+			SameSite:      sameSiteMode(cookieCfg.SameSite),
+		}
+	}
+	// 令牌绑定到签发时客户端的网段和 User-Agent
+	var tokenBinding *middleware.TokenBinding
+	if bindingCfg := cfg.Auth.TokenBinding; bindingCfg.Enabled {
+		tokenBinding = &middleware.TokenBinding{
+			Mode:             middleware.TokenBindingMode(bindingCfg.Mode),
+			IP:               bindingCfg.IP,
+			IPv4PrefixLength: bindingCfg.IPv4PrefixLength,
+			IPv6PrefixLength: bindingCfg.IPv6PrefixLength,
+			UserAgent:        bindingCfg.UserAgent,
+		}
+	}
+	// 组织内用户的令牌只能访问本组织的数据，停用的组织令牌失效
+	var orgResolver *middleware.OrgResolver
+	if cfg.Orgs.Enabled {
+		orgResolver = &middleware.OrgResolver{
+			Header: cfg.Orgs.HeaderName,
+			Lookup: deps.OrganizationService.IsActive,
+		}
+	}
+	authMiddleware := middleware.AuthMiddleware(deps.TokenService, guestRoutes, tokenCookies, tokenBinding, orgResolver, passwordChangeRoutes)
+	skipRules := make([]middleware.SkipRule, 0, len(cfg.Security.SkipPaths))
+	for _, skip := range cfg.Security.SkipPaths {
+		skipRules = append(skipRules, middleware.SkipRule{Method: skip.Method, Path: skip.Path})
+	}
+	securityOptions := middleware.SecurityOptions{
+		Mode:            middleware.SecurityMode(cfg.Security.Mode),
+		TimestampWindow: cfg.Security.TimestampValidityWindow,
+		SkipRules:       skipRules,
+		Names: middleware.SecurityNames{
+			TimestampHeader:   cfg.Security.Names.TimestampHeader,
+			NonceHeader:       cfg.Security.Names.NonceHeader,
+			SignHeader:        cfg.Security.Names.SignHeader,
+			SignVersionHeader: cfg.Security.Names.SignVersionHeader,
+			TimestampParam:    cfg.Security.Names.TimestampParam,
+			NonceParam:        cfg.Security.Names.NonceParam,
+			SignParam:         cfg.Security.Names.SignParam,
+		},
+		RateLimitCounter:     deps.RateLimitCounter,
+		ReplayProtection:     cfg.Security.ReplayProtection.Enabled,
+		NonceOptional:        cfg.Security.ReplayProtection.NonceOptional,
+		VerboseSignatureLogs: cfg.Security.VerboseSignatureLogs,
+	}
+	if appCfg := cfg.Security.AppCredentials; appCfg.Enabled {
+		// 携带应用标识的请求使用该应用的密钥签名，并按应用限流
+		securityOptions.AppKeyHeader = appCfg.HeaderName
+		securityOptions.RequireAppKey = appCfg.Required
+		securityOptions.ResolveApp = appResolver(deps.AppCredentialService)
+	}
+	securityMiddleware := middleware.SecurityMiddleware(deps.SecurityService, securityOptions)
+	// 管理接口按角色在数据库中配置的权限授权
+	requirePermission := func(permission string) gin.HandlerFunc {
+		return middleware.PermissionMiddleware(deps.RBACService.HasPermission, permission)
+	}
+	// 限流在人机验证之前执行，避免被刷爆验证服务的调用
+	loginGuards := gin.HandlersChain{authRateLimitMiddleware(deps.RateLimitCounter, deps.SlidingRateLimitCounter, "login", cfg.Security.LoginRateLimit)}
+	registerGuards := gin.HandlersChain{authRateLimitMiddleware(deps.RateLimitCounter, deps.SlidingRateLimitCounter, "register", cfg.Security.RegisterRateLimit)}
+	otpRequestGuards := gin.HandlersChain{authRateLimitMiddleware(deps.RateLimitCounter, deps.SlidingRateLimitCounter, "otp", cfg.Security.OTPRateLimit)}
+	// 校验验证码与密码登录共用限流计数
+	otpVerifyGuards := gin.HandlersChain{authRateLimitMiddleware(deps.RateLimitCounter, deps.SlidingRateLimitCounter, "login", cfg.Security.LoginRateLimit)}
+	if deps.CaptchaVerifier != nil {
+		captchaMiddleware := middleware.CaptchaMiddleware(deps.CaptchaVerifier, cfg.Security.Captcha.HeaderName)
+		if cfg.Security.Captcha.Login {
+			loginGuards = append(loginGuards, captchaMiddleware)
+			// 发送短信有成本，与登录共用人机验证开关
+			otpRequestGuards = append(otpRequestGuards, captchaMiddleware)
+		}
+		if cfg.Security.Captcha.Register {
+			registerGuards = append(registerGuards, captchaMiddleware)
+		}
+	}
+
+	// 最先执行，被其他中间件拒绝的请求也带有请求ID
+	router.Use(middleware.RequestIDMiddleware(cfg.Server.RequestIDHeader))
+	if cfg.Database.SchemaPerTenant {
+		// 先于所有读取数据库的中间件执行，请求在所选租户的 schema 中查询
+		tenants := make(map[string]bool, len(cfg.Auth.TenantSecrets))
+		for _, tenant := range cfg.Auth.TenantSecrets {
+			tenants[tenant.TenantID] = true
+		}
+		router.Use(middleware.TenantMiddleware(cfg.Database.TenantHeader, tenants))
+	}
+	if deps.MessageCatalog != nil {
+		// 注册在引擎上，被跨域、CSRF 等检查拒绝的请求同样翻译错误信息
+		router.Use(middleware.LocaleMiddleware(deps.MessageCatalog, userLocale(deps.PreferenceService)))
+	}
+	if len(cfg.Database.Replicas) > 0 {
+		router.Use(middleware.ReadPrimaryMiddleware(cfg.Database.ReadPrimaryHeader))
+	}
+	if cfg.Database.TagQueries {
+		router.Use(middleware.SQLRouteMiddleware())
+	}
+	if tokenBinding != nil {
+		// 注册在引擎上，/oauth 下签发的令牌同样绑定客户端
+		router.Use(tokenBinding.Middleware())
+	}
+	if cfg.Auth.RefreshTokenStore.Enabled {
+		// 同样注册在引擎上，记录 /oauth 下签发的刷新令牌的设备
+		router.Use(middleware.DeviceMiddleware())
+	}
+
+	if cfg.CORS.Enabled {
+		// 注册在引擎上，未注册 OPTIONS 路由的预检请求也能得到响应
+		router.Use(middleware.CORSMiddleware(middleware.CORSOptions{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   cfg.CORS.ExposedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		}))
+	}
+
+	csrfOptions := middleware.CSRFOptions{
+		CookieName: cfg.Security.CSRF.CookieName,
+		HeaderName: cfg.Security.CSRF.HeaderName,
+		Tokens:     tokenCookies,
+	}
+	if tokenCookies != nil {
+		// 注册在引擎上，同样保护 /oauth 下使用 Cookie 登录态的授权接口
+		router.Use(middleware.CSRFMiddleware(csrfOptions))
+	}
+
+	if cfg.Metrics.Enabled {
+		// 指标端点不经过签名校验，应仅在内网暴露
+		router.GET(cfg.Metrics.Path, gin.WrapH(expvar.Handler()))
+	}
+
+	// 探针不经过签名校验，供负载均衡与编排系统调用
+	router.GET(cfg.Health.LivenessPath, health.LivenessHandler())
+	router.GET(cfg.Health.ReadinessPath, health.ReadinessHandler(deps.ReadinessChecks, cfg.Health.Timeout))
 
 	// Set up API v1 routes
 	apiV1 := router.Group("/api/v1")
+	if cfg.Guardrails.MaxConcurrentRequests > 0 {
+		// 先于其他中间件执行，过载时尽早拒绝
+		apiV1.Use(middleware.ConcurrencyLimitMiddleware(cfg.Guardrails.MaxConcurrentRequests))
+	}
+	if cfg.Security.StrictJSON.Enabled {
+		// 未知字段由绑定时的解码器拒绝，大小、层级与重复字段在读取签名参数之前检查
+		binding.EnableDecoderDisallowUnknownFields = true
+		apiV1.Use(middleware.StrictJSONMiddleware(middleware.StrictJSONOptions{
+			MaxBodyBytes: cfg.Security.StrictJSON.MaxBodyBytes,
+			MaxDepth:     cfg.Security.StrictJSON.MaxDepth,
+		}))
+	}
+	if cfg.ServiceAccount.Enabled {
+		// Service tokens must be resolved before signature validation, which they bypass
+		apiV1.Use(middleware.ServiceTokenMiddleware(
+			cfg.ServiceAccount.HeaderName,
+			serviceTokenAuthenticator(deps.ServiceAccountService),
+			serviceAccountRouteScopes,
+		))
+	}
+	if orgResolver != nil {
+		// 先于各路由的认证中间件执行，由认证中间件拒绝其他组织的令牌
+		apiV1.Use(orgResolver.Middleware())
+	}
 	apiV1.Use(securityMiddleware)
+	// 记录在签名校验之后，未通过签名的请求不写入审计日志
+	apiV1.Use(middleware.AuditMiddleware(auditRecorder(deps.AuditService), auditedRoutes))
+	if cfg.Users.TrackActivity {
+		apiV1.Use(middleware.ActivityMiddleware(activityRecorder(deps.ActivityService), activityRoutes))
+	}
+	if cfg.Users.TrackLastSeen {
+		apiV1.Use(middleware.PresenceMiddleware(deps.PresenceService.Touch))
+	}
 
 	// Initialize controllers
-	authController := v1.NewAuthController(userService, securityService, enableRegistration)
-	userController := v1.NewUserController(userService)
+	authController := v1.NewAuthController(deps.UserService, deps.SecurityService, deps.InvitationService, cfg.Auth.EnableRegistration, tokenCookies)
+	userController := v1.NewUserController(deps.UserService, deps.PresenceService)
+	userBulkController := v1.NewUserBulkController(deps.UserBulkService, cfg.Users.ImportMaxBytes, cfg.Users.ImportAsyncRows)
+	settingController := v1.NewSettingController(deps.SettingService)
+	emailChangeController := v1.NewEmailChangeController(deps.EmailChangeService)
+	preferenceController := v1.NewPreferenceController(deps.PreferenceService)
+	activityController := v1.NewActivityController(deps.ActivityService)
+	statsController := v1.NewStatsController(deps.StatsService)
+	avatarController := v1.NewAvatarController(deps.UserService, deps.AvatarProvider, deps.AvatarUploader, cfg.Avatar.UploadMaxBytes)
+	invitationController := v1.NewInvitationController(deps.InvitationService)
+	roleController := v1.NewRoleController(deps.RBACService)
+	auditController := v1.NewAuditController(deps.AuditService)
+	groupController := v1.NewGroupController(deps.GroupService)
+	// 拥有 group.manage 权限的用户可以像组所有者一样管理任意组
+	groupAccess := middleware.GroupAccess{
+		Lookup:             deps.GroupService.MemberRole,
+		Checker:            deps.RBACService.HasPermission,
+		OverridePermission: rbac.PermGroupManage,
+		OverrideRole:       group.RoleOwner,
+	}
 
 	// Register routes
-	authController.RegisterRoutes(apiV1)
-	userController.RegisterRoutes(apiV1, authMiddleware, adminMiddleware)
+	authController.RegisterRoutes(apiV1, authMiddleware, loginGuards, registerGuards)
+	userController.RegisterRoutes(apiV1, authMiddleware, requirePermission)
+	userBulkController.RegisterRoutes(apiV1, authMiddleware, requirePermission)
+	settingController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermSettingManage))
+	emailChangeController.RegisterRoutes(apiV1, authMiddleware)
+	preferenceController.RegisterRoutes(apiV1, authMiddleware)
+	activityController.RegisterRoutes(apiV1, authMiddleware, requirePermission)
+	statsController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermUserRead))
+	avatarController.RegisterRoutes(apiV1, authMiddleware)
+	invitationController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermInvitationManage))
+	roleController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermRoleManage))
+	auditController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermAuditRead))
+	groupController.RegisterRoutes(apiV1, authMiddleware, groupAccess, requirePermission(rbac.PermGroupManage))
+
+	if cfg.Auth.RefreshTokenStore.Enabled {
+		sessionController := v1.NewSessionController(deps.RefreshTokenService)
+		sessionController.RegisterRoutes(apiV1, authMiddleware, requirePermission)
+	}
+
+	if cfg.Orgs.Enabled {
+		orgController := v1.NewOrganizationController(deps.OrganizationService)
+		orgController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermOrgManage))
+	}
+
+	if tokenCookies != nil {
+		csrfController := v1.NewCSRFController(csrfOptions)
+		csrfController.RegisterRoutes(apiV1)
+	}
+
+	if cfg.OTP.Enabled {
+		otpController := v1.NewOTPController(deps.OTPService, tokenCookies)
+		otpController.RegisterRoutes(apiV1, authMiddleware, otpRequestGuards, otpVerifyGuards)
+	}
+
+	if cfg.Runbook.Enabled {
+		runbookRateLimit := middleware.KeyedRateLimitMiddleware(
+			deps.RateLimitCounter,
+			"runbook",
+			middleware.RateLimitRule{Limit: cfg.Runbook.PerActionLimit, Window: cfg.Runbook.PerActionWindow},
+			func(c *gin.Context) string { return c.GetString("userID") + ":" + c.Param("name") },
+		)
+		runbookController := v1.NewRunbookController(deps.RunbookService)
+		runbookController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermRunbookExecute), runbookRateLimit)
+	}
+
+	if cfg.OAuth.Enabled {
+		oauthController := v1.NewOAuthController(deps.OAuthService)
+		oauthController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermOAuthClientManage))
+		oauthController.RegisterProtocolRoutes(router, authMiddleware)
+	}
+
+	if cfg.TokenExchange.Enabled {
+		tokenExchangeController := v1.NewTokenExchangeController(deps.TokenExchangeService, tokenCookies)
+		tokenExchangeController.RegisterRoutes(apiV1, gin.HandlersChain{authRateLimitMiddleware(deps.RateLimitCounter, deps.SlidingRateLimitCounter, "token-exchange", cfg.TokenExchange.RateLimit)})
+	}
+
+	if cfg.Guest.Enabled {
+		guestController := v1.NewGuestController(deps.TokenService, cfg.Guest.TTL)
+		guestController.RegisterRoutes(apiV1, gin.HandlersChain{authRateLimitMiddleware(deps.RateLimitCounter, deps.SlidingRateLimitCounter, "guest", cfg.Guest.RateLimit)})
+	}
+
+	if cfg.ServiceAccount.Enabled {
+		serviceAccountController := v1.NewServiceAccountController(deps.ServiceAccountService)
+		serviceAccountController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermServiceAccountMgmt))
+	}
+
+	if cfg.Security.AppCredentials.Enabled {
+		appCredentialController := v1.NewAppCredentialController(deps.AppCredentialService)
+		appCredentialController.RegisterRoutes(apiV1, authMiddleware, requirePermission(rbac.PermAppCredentialMgmt))
+	}
+}
+
+// sameSiteMode converts the configured SameSite mode of the token cookies
+func sameSiteMode(mode string) http.SameSite {
+	switch mode {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// authRateLimitMiddleware builds the rate limiter for an auth endpoint, counting with sliding
+// when cfg.SlidingWindow is set, or a pass-through when disabled
+func authRateLimitMiddleware(counter, sliding middleware.RateLimitCounter, name string, cfg config.RateLimitConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if cfg.SlidingWindow {
+		counter = sliding
+	}
+	return middleware.AuthRateLimitMiddleware(
+		counter,
+		name,
+		middleware.RateLimitRule{Limit: cfg.PerIPLimit, Window: cfg.PerIPWindow},
+		middleware.RateLimitRule{Limit: cfg.PerAccountLimit, Window: cfg.PerAccountWindow},
+	)
+}
+
+// serviceTokenAuthenticator adapts the service account service to the middleware authenticator
+func serviceTokenAuthenticator(service serviceaccount.ServiceAccountService) middleware.ServiceTokenAuthenticator {
+	return func(ctx context.Context, token, clientIP string) (*middleware.ServiceTokenIdentity, error) {
+		account, err := service.Authenticate(ctx, token, clientIP)
+		if err != nil {
+			return nil, err
+		}
+		return &middleware.ServiceTokenIdentity{
+			ID:     account.ID,
+			Name:   account.Name,
+			Scopes: account.Scopes,
+		}, nil
+	}
+}
+
+// appResolver adapts the app credential service to the middleware resolver
+func appResolver(service appcredential.AppCredentialService) middleware.AppResolver {
+	return func(ctx context.Context, appKey string) (*middleware.AppCredential, error) {
+		credential, err := service.Resolve(ctx, appKey)
+		if err != nil || credential == nil {
+			return nil, err
+		}
+		return &middleware.AppCredential{
+			ID:        credential.ID,
+			Secret:    credential.Secret,
+			RateLimit: middleware.RateLimitRule{Limit: credential.RateLimit, Window: credential.RateWindow},
+		}, nil
+	}
+}
+
+// userLocale adapts the preference service to the middleware locale resolver
+func userLocale(service preference.PreferenceService) middleware.LocaleResolver {
+	return func(c *gin.Context) string {
+		userID := c.GetString("userID")
+		if userID == "" {
+			return ""
+		}
+		p, err := service.GetPreferences(c, userID)
+		if err != nil {
+			return ""
+		}
+		return p.Locale
+	}
+}
+
+// activityRecorder adapts the activity service to the middleware recorder
+func activityRecorder(service activity.ActivityService) middleware.ActivityRecorder {
+	return func(entry middleware.ActivityEntry) {
+		service.Record(activity.Entry{
+			UserID:         entry.UserID,
+			Action:         entry.Action,
+			ImpersonatedBy: entry.ImpersonatedBy,
+			IP:             entry.IP,
+			UserAgent:      entry.UserAgent,
+			Tenant:         entry.Tenant,
+		})
+	}
+}
+
+// auditRecorder adapts the audit service to the middleware recorder
+func auditRecorder(service audit.AuditService) middleware.AuditRecorder {
+	return func(ctx context.Context, entry middleware.AuditEntry) {
+		service.Record(ctx, audit.Event{
+			Action:         entry.Action,
+			ActorID:        entry.ActorID,
+			ImpersonatedBy: entry.ImpersonatedBy,
+			Subject:        entry.Subject,
+			Details:        entry.Details,
+			Success:        entry.Success,
+			Status:         entry.Status,
+			IP:             entry.IP,
+			UserAgent:      entry.UserAgent,
+		})
+	}
 }
@@ -0,0 +1,36 @@
+package activity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// DefaultPageSize is the number of activities returned when the filter does not set a page size
+const DefaultPageSize = 20
+
+// ErrUserNotFound is returned when listing the activities of a user that does not exist
+var ErrUserNotFound = errors.New("user not found")
+
+// Entry is a significant action of a user to add to their timeline
+type Entry struct {
+	UserID string
+	Action string
+	// ImpersonatedBy is the admin who acted as the user, empty for the user's own actions
+	ImpersonatedBy string
+	IP             string
+	UserAgent      string
+	// Tenant is the tenant whose schema stores the entry, empty for the shared schema
+	Tenant string
+}
+
+// ActivityService defines the interface for the user activity timeline.
+// Record only queues the entry; a background writer started with Run stores it, so
+// recording never slows down or fails the request.
+type ActivityService interface {
+	Record(entry Entry)
+	Run(ctx context.Context)
+	ListActivities(ctx context.Context, userID string, filter model.ActivityFilter) ([]*ent.Activity, int, error)
+}
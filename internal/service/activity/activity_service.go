@@ -0,0 +1,178 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	entactivity "github.com/hewenyu/gin-pkg/internal/ent/activity"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// writeTimeout bounds how long writing a batch may take, including the final batch on shutdown
+const writeTimeout = 5 * time.Second
+
+// Options configures the activity writer
+type Options struct {
+	// QueueSize is the number of entries that can wait for the writer; entries recorded
+	// while the queue is full are dropped
+	QueueSize int
+	// BatchSize is the maximum number of entries written at once
+	BatchSize int
+}
+
+// DBActivityService implements ActivityService
+type DBActivityService struct {
+	client  *ent.Client
+	queue   chan Entry
+	options Options
+}
+
+// NewActivityService creates a new activity service
+func NewActivityService(client *ent.Client, options Options) ActivityService {
+	if options.QueueSize <= 0 {
+		options.QueueSize = 1000
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+
+	return &DBActivityService{
+		client:  client,
+		queue:   make(chan Entry, options.QueueSize),
+		options: options,
+	}
+}
+
+// Record queues an entry for the writer without blocking
+func (s *DBActivityService) Record(entry Entry) {
+	select {
+	case s.queue <- entry:
+	default:
+		logger.Warnf("Activity queue is full, dropping %s of user %s", entry.Action, entry.UserID)
+	}
+}
+
+// Run writes queued entries in batches until ctx is done, then writes what is left in the queue
+func (s *DBActivityService) Run(ctx context.Context) {
+	batch := make([]Entry, 0, s.options.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			// 退出前写入队列中剩余的记录
+			for {
+				select {
+				case entry := <-s.queue:
+					batch = append(batch, entry)
+					if len(batch) == s.options.BatchSize {
+						s.write(batch)
+						batch = batch[:0]
+					}
+				default:
+					s.write(batch)
+					return
+				}
+			}
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			// 一次取出已排队的记录，合并写入
+		fill:
+			for len(batch) < s.options.BatchSize {
+				select {
+				case entry := <-s.queue:
+					batch = append(batch, entry)
+				default:
+					break fill
+				}
+			}
+			s.write(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// write stores a batch of entries
+func (s *DBActivityService) write(batch []Entry) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	// 各租户的记录写入各自的 schema
+	tenants := make(map[string][]Entry)
+	for _, entry := range batch {
+		tenants[entry.Tenant] = append(tenants[entry.Tenant], entry)
+	}
+	for tenant, entries := range tenants {
+		s.writeTenant(dbtenant.With(ctx, tenant), entries)
+	}
+}
+
+// writeTenant stores entries in the schema of the tenant of ctx
+func (s *DBActivityService) writeTenant(ctx context.Context, batch []Entry) {
+	creates := make([]*ent.ActivityCreate, 0, len(batch))
+	for _, entry := range batch {
+		creates = append(creates, s.client.Activity.Create().
+			SetUserID(entry.UserID).
+			SetAction(entry.Action).
+			SetImpersonatedBy(entry.ImpersonatedBy).
+			SetIP(entry.IP).
+			SetUserAgent(entry.UserAgent))
+	}
+	if _, err := s.client.Activity.CreateBulk(creates...).Save(ctx); err == nil {
+		return
+	}
+
+	// 批量写入失败时（如某个用户已被清除）逐条重试，只丢弃出错的记录
+	for i, create := range creates {
+		if _, err := create.Save(ctx); err != nil {
+			logger.Errorf("Failed to write activity %s of user %s: %v", batch[i].Action, batch[i].UserID, err)
+		}
+	}
+}
+
+// ListActivities returns a page of the activities of a user, newest first, and the total
+// number of activities. Users hidden from ctx, e.g. of another organization, are not found.
+func (s *DBActivityService) ListActivities(ctx context.Context, userID string, filter model.ActivityFilter) ([]*ent.Activity, int, error) {
+	exists, err := s.client.User.Query().Where(user.ID(userID)).Exist(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !exists {
+		return nil, 0, ErrUserNotFound
+	}
+
+	query := s.client.Activity.Query().Where(entactivity.UserID(userID))
+	total, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count activities: %w", err)
+	}
+
+	page, pageSize := Pagination(filter)
+	activities, err := query.
+		Order(ent.Desc(entactivity.FieldCreatedAt), ent.Desc(entactivity.FieldID)).
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list activities: %w", err)
+	}
+	return activities, total, nil
+}
+
+// Pagination returns the page and page size of the filter with defaults applied
+func Pagination(filter model.ActivityFilter) (int, int) {
+	page, pageSize := filter.Page, filter.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,41 @@
+package appcredential
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+var (
+	// ErrNotFound is returned when no app credential matches
+	ErrNotFound = errors.New("app credential not found")
+	// ErrRevoked is returned when changing a revoked app credential
+	ErrRevoked = errors.New("app credential is revoked")
+	// ErrNameExists is returned when another app credential has the same name
+	ErrNameExists = errors.New("app credential with this name already exists")
+)
+
+// Credential is what request signing needs to know about an active app key
+type Credential struct {
+	ID         string
+	AppKey     string
+	Secret     string
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+// AppCredentialService defines the interface for per-client app keys and secrets.
+// Each integration signs requests with its own secret, so it can be revoked on its own.
+type AppCredentialService interface {
+	CreateAppCredential(ctx context.Context, input model.CreateAppCredentialInput, createdBy string) (*ent.AppCredential, string, error)
+	GetAppCredential(ctx context.Context, id string) (*ent.AppCredential, error)
+	ListAppCredentials(ctx context.Context) ([]*ent.AppCredential, error)
+	UpdateAppCredential(ctx context.Context, id string, input model.UpdateAppCredentialInput) (*ent.AppCredential, error)
+	RotateAppCredential(ctx context.Context, id string) (*ent.AppCredential, string, error)
+	RevokeAppCredential(ctx context.Context, id string) error
+	// Resolve returns the credential of an active app key, or nil if the key is unknown or revoked
+	Resolve(ctx context.Context, appKey string) (*Credential, error)
+}
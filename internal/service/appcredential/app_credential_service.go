@@ -0,0 +1,308 @@
+package appcredential
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/appcredential"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/util/cache"
+)
+
+// appKeyPrefix marks app keys so they are easy to recognise in logs
+const appKeyPrefix = "ak_"
+
+// Options configures app credentials
+type Options struct {
+	// EncryptionKey encrypts app secrets at rest. Changing it makes existing secrets unreadable.
+	EncryptionKey string
+	// CacheTTL is how long a resolved credential is cached in Redis
+	CacheTTL time.Duration
+}
+
+// cachedCredential is the cached form of a credential. The secret stays encrypted and unknown
+// or revoked keys are cached as inactive, so made-up keys do not reach the database either.
+type cachedCredential struct {
+	ID               string `json:"id,omitempty"`
+	Active           bool   `json:"active"`
+	SecretCiphertext string `json:"secret_ciphertext,omitempty"`
+	RateLimit        int    `json:"rate_limit,omitempty"`
+	RateWindow       int    `json:"rate_window,omitempty"`
+}
+
+// DBAppCredentialService implements AppCredentialService
+type DBAppCredentialService struct {
+	client  *ent.Client
+	cache   cache.Cache
+	aead    cipher.AEAD
+	options Options
+}
+
+// NewAppCredentialService creates a new app credential service. Resolved credentials are
+// kept in credentials so signed requests do not hit the database.
+func NewAppCredentialService(client *ent.Client, credentials cache.Cache, options Options) AppCredentialService {
+	if options.CacheTTL <= 0 {
+		options.CacheTTL = 5 * time.Minute
+	}
+	// 任意长度的配置密钥派生为 AES-256 密钥
+	key := sha256.Sum256([]byte(options.EncryptionKey))
+	block, _ := aes.NewCipher(key[:])
+	aead, _ := cipher.NewGCM(block)
+	return &DBAppCredentialService{
+		client:  client,
+		cache:   credentials,
+		aead:    aead,
+		options: options,
+	}
+}
+
+// CreateAppCredential registers an app and returns its plaintext secret
+func (s *DBAppCredentialService) CreateAppCredential(ctx context.Context, input model.CreateAppCredentialInput, createdBy string) (*ent.AppCredential, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", errors.New("name is required")
+	}
+
+	appKey, err := randomHex(16)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	ciphertext, err := s.seal(secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	create := s.client.AppCredential.Create().
+		SetName(name).
+		SetAppKey(appKeyPrefix + appKey).
+		SetSecretCiphertext(ciphertext).
+		SetRateLimit(input.RateLimit).
+		SetCreatedBy(createdBy)
+	if input.RateWindow > 0 {
+		create.SetRateWindow(input.RateWindow)
+	}
+	credential, err := create.Save(ctx)
+	if err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, "", ErrNameExists
+		}
+		return nil, "", fmt.Errorf("failed to create app credential: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("App credential %s (%s) created by %s", credential.Name, credential.AppKey, createdBy)
+	return credential, secret, nil
+}
+
+// GetAppCredential gets an app credential by ID
+func (s *DBAppCredentialService) GetAppCredential(ctx context.Context, id string) (*ent.AppCredential, error) {
+	credential, err := s.client.AppCredential.Get(ctx, id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get app credential: %w", err)
+	}
+	return credential, nil
+}
+
+// ListAppCredentials lists all app credentials
+func (s *DBAppCredentialService) ListAppCredentials(ctx context.Context) ([]*ent.AppCredential, error) {
+	credentials, err := s.client.AppCredential.Query().
+		Order(ent.Asc(appcredential.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list app credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+// UpdateAppCredential changes the rate limit of an app credential
+func (s *DBAppCredentialService) UpdateAppCredential(ctx context.Context, id string, input model.UpdateAppCredentialInput) (*ent.AppCredential, error) {
+	credential, err := s.GetAppCredential(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if credential.Status == appcredential.StatusRevoked {
+		return nil, ErrRevoked
+	}
+
+	update := s.client.AppCredential.UpdateOne(credential)
+	if input.RateLimit != nil {
+		update.SetRateLimit(*input.RateLimit)
+	}
+	if input.RateWindow != nil {
+		update.SetRateWindow(*input.RateWindow)
+	}
+	credential, err = update.Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update app credential: %w", err)
+	}
+
+	s.invalidate(ctx, credential.AppKey)
+	logger.FromContext(ctx).Infof("App credential %s (%s) rate limit set to %d per %ds",
+		credential.Name, credential.AppKey, credential.RateLimit, credential.RateWindow)
+	return credential, nil
+}
+
+// RotateAppCredential replaces the secret of an app, invalidating the old one immediately
+func (s *DBAppCredentialService) RotateAppCredential(ctx context.Context, id string) (*ent.AppCredential, string, error) {
+	credential, err := s.GetAppCredential(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if credential.Status == appcredential.StatusRevoked {
+		return nil, "", ErrRevoked
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	ciphertext, err := s.seal(secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	credential, err = s.client.AppCredential.UpdateOne(credential).
+		SetSecretCiphertext(ciphertext).
+		Save(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to rotate app secret: %w", err)
+	}
+
+	s.invalidate(ctx, credential.AppKey)
+	logger.FromContext(ctx).Infof("App credential %s (%s) secret rotated", credential.Name, credential.AppKey)
+	return credential, secret, nil
+}
+
+// RevokeAppCredential revokes an app so requests signed with its key are rejected
+func (s *DBAppCredentialService) RevokeAppCredential(ctx context.Context, id string) error {
+	credential, err := s.GetAppCredential(ctx, id)
+	if err != nil {
+		return err
+	}
+	if credential.Status == appcredential.StatusRevoked {
+		return ErrRevoked
+	}
+
+	_, err = s.client.AppCredential.UpdateOne(credential).
+		SetStatus(appcredential.StatusRevoked).
+		SetRevokedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to revoke app credential: %w", err)
+	}
+
+	s.invalidate(ctx, credential.AppKey)
+	logger.FromContext(ctx).Infof("App credential %s (%s) revoked", credential.Name, credential.AppKey)
+	return nil
+}
+
+// Resolve returns the credential of an active app key, or nil if the key is unknown or revoked
+func (s *DBAppCredentialService) Resolve(ctx context.Context, appKey string) (*Credential, error) {
+	if !strings.HasPrefix(appKey, appKeyPrefix) {
+		return nil, nil
+	}
+
+	cached, err := s.lookup(ctx, appKey)
+	if err != nil {
+		return nil, err
+	}
+	if !cached.Active {
+		return nil, nil
+	}
+
+	secret, err := s.open(cached.SecretCiphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret of app %s: %w", appKey, err)
+	}
+	return &Credential{
+		ID:         cached.ID,
+		AppKey:     appKey,
+		Secret:     secret,
+		RateLimit:  cached.RateLimit,
+		RateWindow: time.Duration(cached.RateWindow) * time.Second,
+	}, nil
+}
+
+// lookup reads a credential from the cache, loading and caching it on a miss.
+// Cache failures fall back to the database so Redis hiccups do not reject signed requests.
+func (s *DBAppCredentialService) lookup(ctx context.Context, appKey string) (*cachedCredential, error) {
+	return cache.GetOrLoadJSON(ctx, s.cache, dbtenant.ScopedKey(ctx, appKey), s.options.CacheTTL, func(ctx context.Context, _ string) (*cachedCredential, error) {
+		credential, err := s.client.AppCredential.Query().
+			Where(appcredential.AppKey(appKey)).
+			Only(ctx)
+		switch {
+		case ent.IsNotFound(err):
+			return &cachedCredential{}, nil
+		case err != nil:
+			return nil, fmt.Errorf("failed to look up app credential: %w", err)
+		}
+		return &cachedCredential{
+			ID:               credential.ID,
+			Active:           credential.Status == appcredential.StatusActive,
+			SecretCiphertext: credential.SecretCiphertext,
+			RateLimit:        credential.RateLimit,
+			RateWindow:       credential.RateWindow,
+		}, nil
+	})
+}
+
+// invalidate drops the cached credential so changes take effect on the next request
+func (s *DBAppCredentialService) invalidate(ctx context.Context, appKey string) {
+	if err := s.cache.Delete(ctx, dbtenant.ScopedKey(ctx, appKey)); err != nil {
+		logger.FromContext(ctx).Warnf("Failed to invalidate cached app credential %s: %v", appKey, err)
+	}
+}
+
+// seal encrypts a secret with AES-GCM and returns the nonce and ciphertext in base64
+func (s *DBAppCredentialService) seal(secret string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts a secret sealed by seal
+func (s *DBAppCredentialService) open(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < s.aead.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce, sealed := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	secret, err := s.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// randomHex returns n random bytes encoded as hex
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
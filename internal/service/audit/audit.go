@@ -0,0 +1,31 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// DefaultPageSize is the number of events returned when the filter does not set a page size
+const DefaultPageSize = 50
+
+// Event is a security-relevant action to record
+type Event struct {
+	Action         string
+	ActorID        string
+	ImpersonatedBy string
+	Subject        string
+	Details        string
+	Success        bool
+	Status         int
+	IP             string
+	UserAgent      string
+}
+
+// AuditService defines the interface for the audit log.
+// Record never fails the caller: write errors are logged instead.
+type AuditService interface {
+	Record(ctx context.Context, event Event)
+	ListEvents(ctx context.Context, filter model.AuditEventFilter) ([]*ent.AuditEvent, int, error)
+}
@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/dbtx"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/auditevent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// recordTimeout bounds how long writing an event may take, independent of the request
+const recordTimeout = 5 * time.Second
+
+// DBAuditService implements AuditService
+type DBAuditService struct {
+	client *ent.Client
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(client *ent.Client) AuditService {
+	return &DBAuditService{
+		client: client,
+	}
+}
+
+// Record stores an audit event, in the caller's transaction if ctx carries one
+func (s *DBAuditService) Record(ctx context.Context, event Event) {
+	// 请求结束或客户端断开后仍需写入审计记录
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+
+	outcome := auditevent.OutcomeFailure
+	if event.Success {
+		outcome = auditevent.OutcomeSuccess
+	}
+	_, err := s.db(ctx).AuditEvent.Create().
+		SetAction(event.Action).
+		SetActorID(event.ActorID).
+		SetImpersonatedBy(event.ImpersonatedBy).
+		SetSubject(event.Subject).
+		SetDetails(event.Details).
+		SetOutcome(outcome).
+		SetStatus(event.Status).
+		SetIP(event.IP).
+		SetUserAgent(event.UserAgent).
+		Save(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to record audit event %s (actor %s, outcome %s): %v", event.Action, event.ActorID, outcome, err)
+	}
+}
+
+// ListEvents returns a page of events matching the filter, newest first, and the total number of matches
+func (s *DBAuditService) ListEvents(ctx context.Context, filter model.AuditEventFilter) ([]*ent.AuditEvent, int, error) {
+	query := s.db(ctx).AuditEvent.Query()
+	if filter.Action != "" {
+		query.Where(auditevent.Action(filter.Action))
+	}
+	if filter.ActorID != "" {
+		query.Where(auditevent.ActorID(filter.ActorID))
+	}
+	if filter.Subject != "" {
+		query.Where(auditevent.Subject(filter.Subject))
+	}
+	if filter.Outcome != "" {
+		query.Where(auditevent.OutcomeEQ(auditevent.Outcome(filter.Outcome)))
+	}
+	if filter.IP != "" {
+		query.Where(auditevent.IP(filter.IP))
+	}
+	if filter.From != "" {
+		from, err := time.Parse(time.RFC3339, filter.From)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid from: %w", err)
+		}
+		query.Where(auditevent.CreatedAtGTE(from))
+	}
+	if filter.To != "" {
+		to, err := time.Parse(time.RFC3339, filter.To)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid to: %w", err)
+		}
+		query.Where(auditevent.CreatedAtLT(to))
+	}
+
+	total, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit events: %w", err)
+	}
+
+	page, pageSize := Pagination(filter)
+	events, err := query.
+		Order(ent.Desc(auditevent.FieldCreatedAt), ent.Desc(auditevent.FieldID)).
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	return events, total, nil
+}
+
+// Pagination returns the page and page size of the filter with defaults applied
+func Pagination(filter model.AuditEventFilter) (int, int) {
+	page, pageSize := filter.Page, filter.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	return page, pageSize
+}
+
+// db returns the client of the caller's transaction, if any, else that of the service
+func (s *DBAuditService) db(ctx context.Context) *ent.Client {
+	return dbtx.Client(ctx, s.client)
+}
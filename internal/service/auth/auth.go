@@ -11,12 +11,12 @@ import (
 
 // AuthService defines the interface for authentication and authorization operations
 type AuthService interface {
-	Login(ctx context.Context, email, password string) (*jwt.TokenPair, *ent.User, error)
+	Login(ctx context.Context, email, password string, rememberMe bool) (*jwt.TokenPair, *ent.User, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*jwt.TokenPair, error)
 	GetNonce(ctx context.Context) (string, error)
 	ValidateTimestamp(timestamp string) error
 	ValidateSignature(params map[string]string, signature string) error
-	ValidateNonce(nonce string) error
+	ValidateNonce(ctx context.Context, nonce string) error
 }
 
 // DefaultAuthService implements AuthService
@@ -40,8 +40,8 @@ func NewAuthService(
 }
 
 // Login authenticates a user and returns JWT tokens
-func (s *DefaultAuthService) Login(ctx context.Context, email, password string) (*jwt.TokenPair, *ent.User, error) {
-	return s.userService.Login(ctx, email, password)
+func (s *DefaultAuthService) Login(ctx context.Context, email, password string, rememberMe bool) (*jwt.TokenPair, *ent.User, error) {
+	return s.userService.Login(ctx, email, password, rememberMe)
 }
 
 // RefreshToken creates a new token pair using a refresh token
@@ -51,7 +51,7 @@ func (s *DefaultAuthService) RefreshToken(ctx context.Context, refreshToken stri
 
 // GetNonce generates a new nonce for request signing
 func (s *DefaultAuthService) GetNonce(ctx context.Context) (string, error) {
-	return s.securityService.GenerateNonce()
+	return s.securityService.GenerateNonce(ctx)
 }
 
 // ValidateTimestamp checks if the timestamp is within the valid window
@@ -65,6 +65,6 @@ func (s *DefaultAuthService) ValidateSignature(params map[string]string, signatu
 }
 
 // ValidateNonce checks if the nonce is valid and hasn't been used before
-func (s *DefaultAuthService) ValidateNonce(nonce string) error {
-	return s.securityService.ValidateNonce(nonce)
+func (s *DefaultAuthService) ValidateNonce(ctx context.Context, nonce string) error {
+	return s.securityService.ValidateNonce(ctx, nonce)
 }
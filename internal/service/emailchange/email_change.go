@@ -0,0 +1,16 @@
+package emailchange
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// EmailChangeService defines the interface for the email change flow:
+// the new address confirms the change, the old address is notified and can roll it back
+type EmailChangeService interface {
+	RequestEmailChange(ctx context.Context, userID string, input model.ChangeEmailInput, clientIP string) (*ent.EmailChange, error)
+	ConfirmEmailChange(ctx context.Context, token string) (*ent.User, error)
+	RollbackEmailChange(ctx context.Context, token string) (*ent.User, error)
+}
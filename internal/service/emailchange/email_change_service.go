@@ -0,0 +1,366 @@
+package emailchange
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/dbtx"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/emailchange"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/auth/password"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/mailer"
+)
+
+// Options configures the email change flow
+type Options struct {
+	ConfirmTTL     time.Duration
+	RollbackWindow time.Duration
+	ConfirmURL     string
+	RollbackURL    string
+}
+
+// DBEmailChangeService implements EmailChangeService
+type DBEmailChangeService struct {
+	client         *ent.Client
+	tokenService   jwt.TokenService
+	passwordHasher *password.Hasher
+	mailer         mailer.Mailer
+	options        Options
+}
+
+// NewEmailChangeService creates a new email change service
+func NewEmailChangeService(
+	client *ent.Client,
+	tokenService jwt.TokenService,
+	passwordHasher *password.Hasher,
+	mailer mailer.Mailer,
+	options Options,
+) EmailChangeService {
+	return &DBEmailChangeService{
+		client:         client,
+		tokenService:   tokenService,
+		passwordHasher: passwordHasher,
+		mailer:         mailer,
+		options:        options,
+	}
+}
+
+// RequestEmailChange starts an email change after re-checking the user's password.
+// A confirmation link goes to the new address and a notice to the old one; any earlier
+// pending request of the user is cancelled.
+func (s *DBEmailChangeService) RequestEmailChange(ctx context.Context, userID string, input model.ChangeEmailInput, clientIP string) (*ent.EmailChange, error) {
+	u, err := s.db(ctx).User.Get(ctx, userID)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	valid, err := s.passwordHasher.Verify(u.PasswordHash, input.Password)
+	if err != nil || !valid {
+		return nil, errors.New("invalid password")
+	}
+
+	newEmail := schema.NormalizeEmail(input.NewEmail)
+	if strings.EqualFold(newEmail, u.Email) {
+		return nil, errors.New("new email is the same as the current email")
+	}
+	exists, err := s.db(ctx).User.Query().Where(user.Email(newEmail)).Exist(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing user: %w", err)
+	}
+	if exists {
+		return nil, errors.New("user with this email already exists")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var change *ent.EmailChange
+	err = dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		_, err := tx.EmailChange.Update().
+			Where(emailchange.UserID(u.ID), emailchange.StatusEQ(emailchange.StatusPending)).
+			SetStatus(emailchange.StatusCancelled).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to cancel pending email changes: %w", err)
+		}
+		change, err = tx.EmailChange.Create().
+			SetUserID(u.ID).
+			SetOldEmail(u.Email).
+			SetNewEmail(newEmail).
+			SetConfirmTokenHash(hashToken(token)).
+			SetConfirmExpiresAt(time.Now().Add(s.options.ConfirmTTL)).
+			SetRequestedIP(clientIP).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create email change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Email change %s requested for user %s from %s", change.ID, u.ID, clientIP)
+
+	s.send(ctx, mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("A request was made to change the email address of your account to this address.\n\n"+
+			"Confirm the change within %s:\n%s\n\nIf you did not request this, ignore this email.",
+			s.options.ConfirmTTL, link(s.options.ConfirmURL, token)),
+	})
+	s.send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Email change requested",
+		Body: fmt.Sprintf("A request was made from %s to change the email address of your account to %s.\n\n"+
+			"If this was not you, change your password immediately. You will also receive a link to undo the change once it is confirmed.",
+			clientIP, maskEmail(newEmail)),
+	})
+
+	return change, nil
+}
+
+// ConfirmEmailChange applies a pending change, opens the rollback window for the old
+// address and revokes every token issued to the user
+func (s *DBEmailChangeService) ConfirmEmailChange(ctx context.Context, token string) (*ent.User, error) {
+	change, err := s.db(ctx).EmailChange.Query().
+		Where(emailchange.ConfirmTokenHash(hashToken(token))).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errors.New("invalid or expired token")
+		}
+		return nil, fmt.Errorf("failed to get email change: %w", err)
+	}
+	if change.Status != emailchange.StatusPending || time.Now().After(change.ConfirmExpiresAt) {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	rollbackToken, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rollbackExpiresAt := now.Add(s.options.RollbackWindow)
+	var u *ent.User
+	err = dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		// The address may have been taken since the request was made
+		exists, err := tx.User.Query().Where(user.Email(change.NewEmail)).Exist(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check for existing user: %w", err)
+		}
+		if exists {
+			return errors.New("user with this email already exists")
+		}
+
+		u, err = tx.User.UpdateOneID(change.UserID).
+			Where(user.Email(change.OldEmail)).
+			SetEmail(change.NewEmail).
+			Save(ctx)
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return errors.New("email change is no longer valid")
+			}
+			return fmt.Errorf("failed to update email: %w", err)
+		}
+
+		_, err = tx.EmailChange.UpdateOne(change).
+			SetStatus(emailchange.StatusConfirmed).
+			SetConfirmedAt(now).
+			SetRollbackTokenHash(hashToken(rollbackToken)).
+			SetRollbackExpiresAt(rollbackExpiresAt).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update email change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Email change %s confirmed for user %s", change.ID, change.UserID)
+	s.revokeTokens(ctx, change.UserID)
+
+	s.send(ctx, mailer.Message{
+		To:      change.OldEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf("The email address of your account was changed to %s.\n\n"+
+			"If you did not make this change, restore your previous address before %s:\n%s",
+			maskEmail(change.NewEmail), rollbackExpiresAt.Format(time.RFC1123), link(s.options.RollbackURL, rollbackToken)),
+	})
+
+	return u, nil
+}
+
+// RollbackEmailChange restores the old address from the link sent to it and revokes every
+// token issued to the user, locking out whoever made the change. Changes confirmed after
+// it are rolled back too, so their rollback links cannot take the account back.
+func (s *DBEmailChangeService) RollbackEmailChange(ctx context.Context, token string) (*ent.User, error) {
+	change, err := s.db(ctx).EmailChange.Query().
+		Where(emailchange.RollbackTokenHash(hashToken(token))).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errors.New("invalid or expired token")
+		}
+		return nil, fmt.Errorf("failed to get email change: %w", err)
+	}
+	if change.Status != emailchange.StatusConfirmed || change.ConfirmedAt == nil ||
+		change.RollbackExpiresAt == nil || time.Now().After(*change.RollbackExpiresAt) {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	var u *ent.User
+	err = dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		exists, err := tx.User.Query().
+			Where(user.Email(change.OldEmail), user.IDNEQ(change.UserID)).
+			Exist(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check for existing user: %w", err)
+		}
+		if exists {
+			return errors.New("previous email is now used by another account")
+		}
+
+		// Changes confirmed after this one are undone with it, so the current address is the
+		// new address of the latest of them
+		later, err := tx.EmailChange.Query().
+			Where(
+				emailchange.UserID(change.UserID),
+				emailchange.StatusEQ(emailchange.StatusConfirmed),
+				emailchange.ConfirmedAtGTE(*change.ConfirmedAt),
+			).
+			Order(ent.Desc(emailchange.FieldConfirmedAt)).
+			All(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get later email changes: %w", err)
+		}
+		currentEmail := change.NewEmail
+		if len(later) > 0 {
+			currentEmail = later[0].NewEmail
+		}
+
+		u, err = tx.User.UpdateOneID(change.UserID).
+			Where(user.Email(currentEmail)).
+			SetEmail(change.OldEmail).
+			Save(ctx)
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return errors.New("email change is no longer valid")
+			}
+			return fmt.Errorf("failed to restore email: %w", err)
+		}
+
+		// Pending changes started by the attacker must not survive the rollback, nor may
+		// the rollback links of later changes, which went to addresses the attacker controls
+		_, err = tx.EmailChange.Update().
+			Where(emailchange.UserID(change.UserID), emailchange.StatusEQ(emailchange.StatusPending)).
+			SetStatus(emailchange.StatusCancelled).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to cancel pending email changes: %w", err)
+		}
+		ids := []string{change.ID}
+		for _, c := range later {
+			ids = append(ids, c.ID)
+		}
+		_, err = tx.EmailChange.Update().
+			Where(emailchange.IDIn(ids...)).
+			SetStatus(emailchange.StatusRolledBack).
+			SetRolledBackAt(time.Now()).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update email changes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Warnf("Email change %s rolled back for user %s", change.ID, change.UserID)
+	s.revokeTokens(ctx, change.UserID)
+
+	s.send(ctx, mailer.Message{
+		To:      change.OldEmail,
+		Subject: "Your email address was restored",
+		Body: "The email address of your account was restored and all sessions were signed out.\n\n" +
+			"If you did not request the original change, change your password now.",
+	})
+
+	return u, nil
+}
+
+// revokeTokens signs the user out everywhere; failures are logged as the email change itself succeeded
+func (s *DBEmailChangeService) revokeTokens(ctx context.Context, userID string) {
+	if err := s.tokenService.RevokeUserTokens(ctx, userID); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to revoke tokens of user %s: %v", userID, err)
+	}
+}
+
+// send delivers a notification; failures are logged rather than failing the flow
+func (s *DBEmailChangeService) send(ctx context.Context, msg mailer.Message) {
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to send email %q to %s: %v", msg.Subject, maskEmail(msg.To), err)
+	}
+}
+
+// db returns the client of the caller's transaction, if any, else that of the service
+func (s *DBEmailChangeService) db(ctx context.Context) *ent.Client {
+	return dbtx.Client(ctx, s.client)
+}
+
+// generateToken creates a random URL-safe token
+func generateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken returns the SHA-256 hash of a token; only the hash is persisted
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// link appends the token to a base URL
+func link(baseURL, token string) string {
+	separator := "?"
+	if strings.Contains(baseURL, "?") {
+		separator = "&"
+	}
+	return baseURL + separator + "token=" + url.QueryEscape(token)
+}
+
+// maskEmail hides most of the local part, e.g. "jo***@example.com"
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return "***"
+	}
+	if len(local) > 2 {
+		local = local[:2]
+	}
+	return local + "***@" + domain
+}
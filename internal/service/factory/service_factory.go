@@ -1,14 +1,41 @@
 package factory
 
 import (
+	"context"
+	"database/sql"
 	"time"
 
 	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/service/activity"
+	"github.com/hewenyu/gin-pkg/internal/service/appcredential"
+	"github.com/hewenyu/gin-pkg/internal/service/audit"
 	"github.com/hewenyu/gin-pkg/internal/service/auth"
+	"github.com/hewenyu/gin-pkg/internal/service/emailchange"
+	"github.com/hewenyu/gin-pkg/internal/service/group"
+	"github.com/hewenyu/gin-pkg/internal/service/invitation"
+	"github.com/hewenyu/gin-pkg/internal/service/oauth"
+	"github.com/hewenyu/gin-pkg/internal/service/organization"
+	"github.com/hewenyu/gin-pkg/internal/service/otp"
+	"github.com/hewenyu/gin-pkg/internal/service/preference"
+	"github.com/hewenyu/gin-pkg/internal/service/presence"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/internal/service/refreshtoken"
+	"github.com/hewenyu/gin-pkg/internal/service/serviceaccount"
+	"github.com/hewenyu/gin-pkg/internal/service/setting"
+	"github.com/hewenyu/gin-pkg/internal/service/stats"
+	"github.com/hewenyu/gin-pkg/internal/service/tokenexchange"
 	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/internal/service/userbulk"
+	"github.com/hewenyu/gin-pkg/pkg/auth/actiontoken"
+	"github.com/hewenyu/gin-pkg/pkg/auth/exchange"
 	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/auth/password"
 	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+	"github.com/hewenyu/gin-pkg/pkg/mailer"
+	"github.com/hewenyu/gin-pkg/pkg/sms"
 	"github.com/hewenyu/gin-pkg/pkg/util"
+	"github.com/hewenyu/gin-pkg/pkg/util/cache"
+	"github.com/hewenyu/gin-pkg/pkg/util/nonce"
 )
 
 // ServiceFactory provides methods to create service instances
@@ -25,44 +52,168 @@ func NewServiceFactory(dbClient *ent.Client, redisClient *util.RedisClient) *Ser
 	}
 }
 
-// CreateTokenService creates a new JWT token service
+// CreateTokenService creates a new token service. With opaque set it issues opaque tokens
+// whose claims are kept in Redis sessions instead of JWTs. Tokens issued for a tenant are
+// signed with its entry in tenantSecrets, and JWTs of the types selected by encryption are encrypted.
+// Issued refresh tokens are recorded in refreshTokens unless it is nil.
 func (f *ServiceFactory) CreateTokenService(
 	accessSecret string,
 	refreshSecret string,
 	accessTokenDuration time.Duration,
 	refreshTokenDuration time.Duration,
+	rememberMeDuration time.Duration,
+	impersonationDuration time.Duration,
 	defaultAccessTokenExp int64,
 	defaultRefreshTokenExp int64,
+	roleScopes map[string][]string,
+	issuer string,
+	audience string,
+	leeway time.Duration,
+	tenantSecrets map[string]string,
+	encryption jwt.TokenEncryption,
+	opaque bool,
+	store jwt.TokenStore,
+	refreshTokens jwt.RefreshTokenStore,
 ) jwt.TokenService {
+	if opaque {
+		return jwt.NewOpaqueTokenService(
+			accessSecret,
+			refreshSecret,
+			accessTokenDuration,
+			refreshTokenDuration,
+			rememberMeDuration,
+			impersonationDuration,
+			defaultAccessTokenExp,
+			defaultRefreshTokenExp,
+			roleScopes,
+			issuer,
+			audience,
+			leeway,
+			tenantSecrets,
+			store,
+			jwt.NewRedisSessionStore(f.redisClient),
+			refreshTokens,
+		)
+	}
 	return jwt.NewJWTService(
 		accessSecret,
 		refreshSecret,
 		accessTokenDuration,
 		refreshTokenDuration,
+		rememberMeDuration,
+		impersonationDuration,
 		defaultAccessTokenExp,
 		defaultRefreshTokenExp,
-		f.redisClient.BlacklistToken,
-		f.redisClient.IsTokenBlacklisted,
+		roleScopes,
+		issuer,
+		audience,
+		leeway,
+		tenantSecrets,
+		encryption,
+		store,
+		refreshTokens,
 	)
 }
 
-// CreateSecurityService creates a new security service
+// CreateActionTokenManager creates the manager of one-time action tokens, kept in Redis
+func (f *ServiceFactory) CreateActionTokenManager() *actiontoken.Manager {
+	return actiontoken.NewManager(actiontoken.NewRedisStore(f.redisClient))
+}
+
+// CreateTokenStore creates the Redis store keeping token revocations
+func (f *ServiceFactory) CreateTokenStore() jwt.TokenStore {
+	return jwt.NewRedisTokenStore(f.redisClient)
+}
+
+// CreateCachedTokenStore wraps a token store with an in-process cache of its lookups.
+// With pubSub, revocations are published over Redis for SubscribeRevocations.
+func (f *ServiceFactory) CreateCachedTokenStore(store jwt.TokenStore, ttl time.Duration, pubSub bool) *jwt.CachedTokenStore {
+	options := jwt.CachedTokenStoreOptions{TTL: ttl}
+	if pubSub {
+		options.Publish = f.redisClient.PublishRevocation
+	}
+	return jwt.NewCachedTokenStore(store, options)
+}
+
+// CreateNonceService creates the nonce store selected by backend: "postgres" keeps nonces in
+// db, "memory" up to maxEntries nonces in process memory, and "redis" keeps them in Redis
+func (f *ServiceFactory) CreateNonceService(backend string, maxEntries int, db *sql.DB) nonce.NonceService {
+	switch backend {
+	case "postgres":
+		return nonce.NewNoncePostgresService(db)
+	case "memory":
+		return nonce.NewNonceMemoryService(maxEntries)
+	default:
+		return redisNonceService{client: f.redisClient}
+	}
+}
+
+// redisNonceService keeps nonces in Redis through the shared client, with its retries and
+// in-memory fallback
+type redisNonceService struct {
+	client *util.RedisClient
+}
+
+func (s redisNonceService) StoreNonce(ctx context.Context, nonce string, expiration time.Duration) error {
+	return s.client.StoreNonce(ctx, nonce, expiration)
+}
+
+func (s redisNonceService) GetNonce(ctx context.Context, nonce string) (bool, error) {
+	return s.client.ConsumeNonce(ctx, nonce)
+}
+
+// Close leaves the shared client open
+func (s redisNonceService) Close() error {
+	return nil
+}
+
+// CreateSecurityService creates a new security service keeping nonces in nonces
 func (f *ServiceFactory) CreateSecurityService(
 	signatureSecret string,
+	secondarySignatureSecret string,
 	nonceValidityDuration time.Duration,
+	signatures *security.SignatureRegistry,
+	nonces nonce.NonceService,
 ) security.SecurityService {
 	return security.NewSecurityService(
 		signatureSecret,
+		secondarySignatureSecret,
 		nonceValidityDuration,
-		f.redisClient.StoreNonce,
-		f.redisClient.GetNonce,
-		f.redisClient.InvalidateNonce,
+		signatures,
+		nonces.StoreNonce,
+		nonces.GetNonce,
+		f.redisClient.MarkRequestSeen,
 	)
 }
 
 // CreateUserService creates a new user service
-func (f *ServiceFactory) CreateUserService(tokenService jwt.TokenService) user.UserService {
-	return user.NewUserService(f.dbClient, tokenService)
+func (f *ServiceFactory) CreateUserService(
+	tokenService jwt.TokenService,
+	passwordPolicy password.Policy,
+	passwordHasher *password.Hasher,
+) user.UserService {
+	return user.NewUserService(f.dbClient, tokenService, passwordPolicy, passwordHasher)
+}
+
+// CreateCachedUserService wraps a user service with a cache of its user lookups
+func (f *ServiceFactory) CreateCachedUserService(service user.UserService, users cache.Cache, options user.CacheOptions) user.UserService {
+	return user.NewCachedUserService(service, f.dbClient, users, options)
+}
+
+// CreateCache creates a Redis cache whose entries are kept apart from other caches under namespace
+func (f *ServiceFactory) CreateCache(namespace string) cache.Cache {
+	return cache.NewRedisCache(f.redisClient, namespace)
+}
+
+// CreateTieredCache creates a Redis cache like CreateCache with an in-process LRU in front.
+// With pubSub, changes are published over Redis for SubscribeCacheInvalidations.
+func (f *ServiceFactory) CreateTieredCache(namespace string, options cache.TieredCacheOptions, pubSub bool) *cache.TieredCache {
+	if pubSub {
+		options.Publish = func(ctx context.Context, key string) error {
+			return f.redisClient.PublishCacheInvalidation(ctx, namespace, key)
+		}
+	}
+	return cache.NewTieredCache(f.CreateCache(namespace), options)
 }
 
 // CreateAuthService creates a new authentication service
@@ -73,3 +224,151 @@ func (f *ServiceFactory) CreateAuthService(
 ) auth.AuthService {
 	return auth.NewAuthService(userService, tokenService, securityService)
 }
+
+// CreateServiceAccountService creates a new service account service
+func (f *ServiceFactory) CreateServiceAccountService(maxLifetime time.Duration) serviceaccount.ServiceAccountService {
+	return serviceaccount.NewServiceAccountService(f.dbClient, maxLifetime)
+}
+
+// CreateSettingService creates a new setting service
+func (f *ServiceFactory) CreateSettingService(cacheOptions cache.SWROptions) setting.SettingService {
+	return setting.NewSettingService(f.dbClient, cacheOptions)
+}
+
+// CreateRBACService creates a new role and permission service
+func (f *ServiceFactory) CreateRBACService(cacheOptions cache.SWROptions) rbac.RBACService {
+	return rbac.NewRBACService(f.dbClient, cacheOptions)
+}
+
+// CreatePreferenceService creates a new user preference service
+func (f *ServiceFactory) CreatePreferenceService() preference.PreferenceService {
+	return preference.NewPreferenceService(f.dbClient)
+}
+
+// CreateActivityService creates a new user activity timeline service
+func (f *ServiceFactory) CreateActivityService(options activity.Options) activity.ActivityService {
+	return activity.NewActivityService(f.dbClient, options)
+}
+
+// CreateRefreshTokenService creates a new service recording refresh tokens in the database.
+// RevokeUser covers the schemas of tenants as well as the shared one.
+func (f *ServiceFactory) CreateRefreshTokenService(tenants []string) refreshtoken.RefreshTokenService {
+	return refreshtoken.NewRefreshTokenService(f.dbClient, tenants)
+}
+
+// CreatePresenceService creates a new service tracking when users were last active
+func (f *ServiceFactory) CreatePresenceService(options presence.Options) presence.PresenceService {
+	return presence.NewPresenceService(
+		f.dbClient,
+		presence.SeenStore{
+			Record: f.redisClient.RecordLastSeen,
+			Get:    f.redisClient.GetLastSeen,
+			Take:   f.redisClient.TakeLastSeen,
+		},
+		options,
+	)
+}
+
+// CreateOrganizationService creates a new organization service
+func (f *ServiceFactory) CreateOrganizationService(cacheOptions cache.SWROptions) organization.OrganizationService {
+	return organization.NewOrganizationService(f.dbClient, cacheOptions)
+}
+
+// CreateGroupService creates a new group service
+func (f *ServiceFactory) CreateGroupService() group.GroupService {
+	return group.NewGroupService(f.dbClient)
+}
+
+// CreateAuditService creates a new audit log service
+func (f *ServiceFactory) CreateAuditService() audit.AuditService {
+	return audit.NewAuditService(f.dbClient)
+}
+
+// CreateEmailChangeService creates a new email change service
+func (f *ServiceFactory) CreateEmailChangeService(
+	tokenService jwt.TokenService,
+	passwordHasher *password.Hasher,
+	mailer mailer.Mailer,
+	options emailchange.Options,
+) emailchange.EmailChangeService {
+	return emailchange.NewEmailChangeService(f.dbClient, tokenService, passwordHasher, mailer, options)
+}
+
+// CreateOTPService creates a new SMS login service
+func (f *ServiceFactory) CreateOTPService(
+	tokenService jwt.TokenService,
+	sender sms.Sender,
+	options otp.Options,
+) otp.OTPService {
+	return otp.NewOTPService(
+		f.dbClient,
+		tokenService,
+		sender,
+		otp.CodeStore{
+			Store:  f.redisClient.StoreOTP,
+			Get:    f.redisClient.GetOTP,
+			Delete: f.redisClient.DeleteOTP,
+		},
+		f.redisClient.IncrementCounter,
+		options,
+	)
+}
+
+// CreateUserBulkService creates a new service for importing and exporting users
+func (f *ServiceFactory) CreateUserBulkService(userService user.UserService, options userbulk.Options) userbulk.UserBulkService {
+	return userbulk.NewUserBulkService(f.dbClient, userService, options)
+}
+
+// CreateInvitationService creates a new invitation service
+func (f *ServiceFactory) CreateInvitationService(
+	userService user.UserService,
+	mailer mailer.Mailer,
+	options invitation.Options,
+) invitation.InvitationService {
+	return invitation.NewInvitationService(f.dbClient, userService, mailer, options)
+}
+
+// CreateOAuthService creates a new OAuth2 authorization server
+func (f *ServiceFactory) CreateOAuthService(
+	userService user.UserService,
+	tokenService jwt.TokenService,
+	options oauth.Options,
+) oauth.OAuthService {
+	return oauth.NewOAuthService(
+		f.dbClient,
+		userService,
+		tokenService,
+		oauth.CodeStore{
+			Store: f.redisClient.StoreAuthCode,
+			Take:  f.redisClient.TakeAuthCode,
+		},
+		options,
+	)
+}
+
+// CreateStatsService creates a new statistics service
+func (f *ServiceFactory) CreateStatsService(options stats.Options) stats.StatsService {
+	return stats.NewStatsService(
+		f.dbClient,
+		stats.Cache{
+			Set: f.redisClient.StoreStats,
+			Get: f.redisClient.GetStats,
+		},
+		options,
+	)
+}
+
+// CreateTokenExchangeService creates a new token exchange service
+func (f *ServiceFactory) CreateTokenExchangeService(
+	tokenService jwt.TokenService,
+	verifier *exchange.Verifier,
+	passwordHasher *password.Hasher,
+	rules []tokenexchange.Rule,
+) tokenexchange.TokenExchangeService {
+	return tokenexchange.NewTokenExchangeService(f.dbClient, tokenService, verifier, passwordHasher, rules)
+}
+
+// CreateAppCredentialService creates a new app credential service caching resolved credentials in credentials
+func (f *ServiceFactory) CreateAppCredentialService(options appcredential.Options, credentials cache.Cache) appcredential.AppCredentialService {
+	return appcredential.NewAppCredentialService(f.dbClient, credentials, options)
+}
@@ -0,0 +1,66 @@
+package group
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// Roles within a group, from most to least privileged. Owners manage the group and every
+// member; admins manage plain members; members can see the group and its members.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+var (
+	// ErrGroupNotFound is returned when a group does not exist
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrGroupExists is returned when creating or renaming a group to a name that is taken
+	ErrGroupExists = errors.New("group with this name already exists")
+	// ErrUserNotFound is returned when adding a user that does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrNotMember is returned when a user is not a member of the group
+	ErrNotMember = errors.New("user is not a member of the group")
+	// ErrAlreadyMember is returned when adding a user who is already a member
+	ErrAlreadyMember = errors.New("user is already a member of the group")
+	// ErrLastOwner is returned when removing or demoting the only owner of a group
+	ErrLastOwner = errors.New("a group must keep at least one owner")
+	// ErrUnknownRole is returned for roles other than owner, admin and member
+	ErrUnknownRole = errors.New("unknown group role")
+)
+
+// CanManage reports whether a member with role actor may add, change or remove a member
+// with role target: owners manage everyone, admins only plain members
+func CanManage(actor, target string) bool {
+	switch actor {
+	case RoleOwner:
+		return true
+	case RoleAdmin:
+		return target == RoleMember
+	default:
+		return false
+	}
+}
+
+// GroupService defines the interface for groups and their memberships
+type GroupService interface {
+	// CreateGroup creates a group with ownerID as its first owner
+	CreateGroup(ctx context.Context, input model.CreateGroupInput, ownerID string) (*ent.Group, error)
+	GetGroup(ctx context.Context, id string) (*ent.Group, error)
+	ListGroups(ctx context.Context) ([]*ent.Group, error)
+	// ListUserGroups returns the memberships of a user with their groups loaded
+	ListUserGroups(ctx context.Context, userID string) ([]*ent.Membership, error)
+	UpdateGroup(ctx context.Context, id string, input model.UpdateGroupInput) (*ent.Group, error)
+	DeleteGroup(ctx context.Context, id string) error
+	// ListMembers returns the memberships of a group with their users loaded
+	ListMembers(ctx context.Context, groupID string) ([]*ent.Membership, error)
+	// MemberRole returns the role of a user in a group, or "" when the user is not a member
+	MemberRole(ctx context.Context, groupID, userID string) (string, error)
+	AddMember(ctx context.Context, groupID, userID, role string) (*ent.Membership, error)
+	UpdateMemberRole(ctx context.Context, groupID, userID, role string) (*ent.Membership, error)
+	RemoveMember(ctx context.Context, groupID, userID string) error
+}
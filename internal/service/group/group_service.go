@@ -0,0 +1,280 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hewenyu/gin-pkg/internal/dbtx"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	entgroup "github.com/hewenyu/gin-pkg/internal/ent/group"
+	"github.com/hewenyu/gin-pkg/internal/ent/membership"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// DBGroupService implements GroupService
+type DBGroupService struct {
+	client *ent.Client
+}
+
+// NewGroupService creates a new group service
+func NewGroupService(client *ent.Client) GroupService {
+	return &DBGroupService{client: client}
+}
+
+// CreateGroup creates a group with ownerID as its first owner
+func (s *DBGroupService) CreateGroup(ctx context.Context, input model.CreateGroupInput, ownerID string) (*ent.Group, error) {
+	var g *ent.Group
+	err := dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		var err error
+		g, err = tx.Group.Create().
+			SetName(strings.TrimSpace(input.Name)).
+			SetDescription(input.Description).
+			SetCreatedBy(ownerID).
+			Save(ctx)
+		if err != nil {
+			if ent.IsConstraintError(err) {
+				return ErrGroupExists
+			}
+			return fmt.Errorf("failed to create group: %w", err)
+		}
+		_, err = tx.Membership.Create().
+			SetGroupID(g.ID).
+			SetUserID(ownerID).
+			SetRole(membership.RoleOwner).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to add group owner: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// GetGroup gets a group by ID
+func (s *DBGroupService) GetGroup(ctx context.Context, id string) (*ent.Group, error) {
+	g, err := s.db(ctx).Group.Get(ctx, id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	return g, nil
+}
+
+// ListGroups returns every group sorted by name
+func (s *DBGroupService) ListGroups(ctx context.Context) ([]*ent.Group, error) {
+	groups, err := s.db(ctx).Group.Query().Order(ent.Asc(entgroup.FieldName)).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	return groups, nil
+}
+
+// ListUserGroups returns the memberships of a user with their groups loaded
+func (s *DBGroupService) ListUserGroups(ctx context.Context, userID string) ([]*ent.Membership, error) {
+	memberships, err := s.db(ctx).Membership.Query().
+		Where(membership.UserID(userID)).
+		WithGroup().
+		Order(ent.Asc(membership.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	return memberships, nil
+}
+
+// UpdateGroup updates the name or description of a group
+func (s *DBGroupService) UpdateGroup(ctx context.Context, id string, input model.UpdateGroupInput) (*ent.Group, error) {
+	update := s.db(ctx).Group.UpdateOneID(id)
+	if name := strings.TrimSpace(input.Name); name != "" {
+		update.SetName(name)
+	}
+	if input.Description != nil {
+		update.SetDescription(*input.Description)
+	}
+
+	g, err := update.Save(ctx)
+	if err != nil {
+		switch {
+		case ent.IsNotFound(err):
+			return nil, ErrGroupNotFound
+		case ent.IsConstraintError(err):
+			return nil, ErrGroupExists
+		}
+		return nil, fmt.Errorf("failed to update group: %w", err)
+	}
+	return g, nil
+}
+
+// DeleteGroup deletes a group together with its memberships
+func (s *DBGroupService) DeleteGroup(ctx context.Context, id string) error {
+	return dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		if _, err := tx.Membership.Delete().Where(membership.GroupID(id)).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete group members: %w", err)
+		}
+		if err := tx.Group.DeleteOneID(id).Exec(ctx); err != nil {
+			if ent.IsNotFound(err) {
+				return ErrGroupNotFound
+			}
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListMembers returns the memberships of a group with their users loaded, oldest first.
+// Soft-deleted users are left out.
+func (s *DBGroupService) ListMembers(ctx context.Context, groupID string) ([]*ent.Membership, error) {
+	memberships, err := s.db(ctx).Membership.Query().
+		Where(membership.GroupID(groupID), membership.HasUserWith(user.DeletedAtIsNil())).
+		WithUser().
+		Order(ent.Asc(membership.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group members: %w", err)
+	}
+	return memberships, nil
+}
+
+// MemberRole returns the role of a user in a group, or "" when the user is not a member
+func (s *DBGroupService) MemberRole(ctx context.Context, groupID, userID string) (string, error) {
+	m, err := s.db(ctx).Membership.Query().
+		Where(membership.GroupID(groupID), membership.UserID(userID)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get group membership: %w", err)
+	}
+	return m.Role.String(), nil
+}
+
+// AddMember adds a user to a group; role defaults to member
+func (s *DBGroupService) AddMember(ctx context.Context, groupID, userID, role string) (*ent.Membership, error) {
+	if role == "" {
+		role = RoleMember
+	}
+	if err := membership.RoleValidator(membership.Role(role)); err != nil {
+		return nil, ErrUnknownRole
+	}
+
+	exists, err := s.db(ctx).Group.Query().Where(entgroup.ID(groupID)).Exist(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	if !exists {
+		return nil, ErrGroupNotFound
+	}
+	exists, err = s.db(ctx).User.Query().Where(user.ID(userID)).Exist(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	m, err := s.db(ctx).Membership.Create().
+		SetGroupID(groupID).
+		SetUserID(userID).
+		SetRole(membership.Role(role)).
+		Save(ctx)
+	if err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, fmt.Errorf("failed to add group member: %w", err)
+	}
+	return m, nil
+}
+
+// UpdateMemberRole changes the role of a member. The last owner cannot be demoted.
+func (s *DBGroupService) UpdateMemberRole(ctx context.Context, groupID, userID, role string) (*ent.Membership, error) {
+	if err := membership.RoleValidator(membership.Role(role)); err != nil {
+		return nil, ErrUnknownRole
+	}
+
+	var m *ent.Membership
+	err := dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		var err error
+		m, err = getMember(ctx, tx, groupID, userID)
+		if err != nil {
+			return err
+		}
+		if m.Role == membership.RoleOwner && role != RoleOwner {
+			if err := keepOwner(ctx, tx, groupID); err != nil {
+				return err
+			}
+		}
+
+		m, err = tx.Membership.UpdateOne(m).SetRole(membership.Role(role)).Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update group member: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RemoveMember removes a user from a group. The last owner cannot be removed.
+func (s *DBGroupService) RemoveMember(ctx context.Context, groupID, userID string) error {
+	return dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		m, err := getMember(ctx, tx, groupID, userID)
+		if err != nil {
+			return err
+		}
+		if m.Role == membership.RoleOwner {
+			if err := keepOwner(ctx, tx, groupID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Membership.DeleteOne(m).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to remove group member: %w", err)
+		}
+		return nil
+	})
+}
+
+// getMember loads the membership of a user in a group
+func getMember(ctx context.Context, tx *ent.Tx, groupID, userID string) (*ent.Membership, error) {
+	m, err := tx.Membership.Query().
+		Where(membership.GroupID(groupID), membership.UserID(userID)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrNotMember
+		}
+		return nil, fmt.Errorf("failed to get group membership: %w", err)
+	}
+	return m, nil
+}
+
+// keepOwner fails with ErrLastOwner unless the group has another owner besides the one
+// being removed or demoted
+func keepOwner(ctx context.Context, tx *ent.Tx, groupID string) error {
+	owners, err := tx.Membership.Query().
+		Where(membership.GroupID(groupID), membership.RoleEQ(membership.RoleOwner)).
+		Count(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count group owners: %w", err)
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// db returns the client of the caller's transaction, if any, else that of the service
+func (s *DBGroupService) db(ctx context.Context) *ent.Client {
+	return dbtx.Client(ctx, s.client)
+}
@@ -0,0 +1,17 @@
+package invitation
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// InvitationService defines the interface for invitation-based registration:
+// admins invite an email address, and the invitee registers with the invite token
+type InvitationService interface {
+	CreateInvitation(ctx context.Context, input model.CreateInvitationInput, createdBy string) (*ent.Invitation, string, error)
+	ListInvitations(ctx context.Context) ([]*ent.Invitation, error)
+	RevokeInvitation(ctx context.Context, id string) error
+	Register(ctx context.Context, token string, input model.CreateUserInput) (*ent.User, error)
+}
@@ -0,0 +1,233 @@
+package invitation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/dbtx"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/invitation"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
+	entuser "github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/mailer"
+)
+
+// errInvalidInvitation is returned for unknown, expired, used or mismatched invitations
+var errInvalidInvitation = errors.New("invalid or expired invitation")
+
+// Options configures invitations
+type Options struct {
+	TTL time.Duration
+	// AcceptURL is the frontend registration page; the token is appended as ?token=
+	AcceptURL string
+}
+
+// DBInvitationService implements InvitationService
+type DBInvitationService struct {
+	client      *ent.Client
+	userService user.UserService
+	mailer      mailer.Mailer
+	options     Options
+}
+
+// NewInvitationService creates a new invitation service
+func NewInvitationService(client *ent.Client, userService user.UserService, mailer mailer.Mailer, options Options) InvitationService {
+	return &DBInvitationService{
+		client:      client,
+		userService: userService,
+		mailer:      mailer,
+		options:     options,
+	}
+}
+
+// CreateInvitation invites an email address and emails it the invite link. Earlier pending
+// invitations for the same address are replaced. The plaintext token is returned only here.
+func (s *DBInvitationService) CreateInvitation(ctx context.Context, input model.CreateInvitationInput, createdBy string) (*ent.Invitation, string, error) {
+	email := schema.NormalizeEmail(input.Email)
+	exists, err := s.db(ctx).User.Query().Where(entuser.Email(email)).Exist(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check for existing user: %w", err)
+	}
+	if exists {
+		return nil, "", errors.New("user with this email already exists")
+	}
+
+	expiresAt := time.Now().Add(s.options.TTL)
+	if input.ExpiresAt != "" {
+		expiresAt, err = time.Parse(time.RFC3339, input.ExpiresAt)
+		if err != nil {
+			return nil, "", errors.New("expires_at must be an RFC 3339 timestamp")
+		}
+		if !expiresAt.After(time.Now()) {
+			return nil, "", errors.New("expires_at must be in the future")
+		}
+	}
+
+	role := input.Role
+	if role == "" {
+		role = "user"
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var inv *ent.Invitation
+	err = dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		_, err := tx.Invitation.Delete().
+			Where(invitation.Email(email), invitation.AcceptedAtIsNil()).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to replace pending invitations: %w", err)
+		}
+		inv, err = tx.Invitation.Create().
+			SetEmail(email).
+			SetRole(role).
+			SetTokenHash(hashToken(token)).
+			SetExpiresAt(expiresAt).
+			SetCreatedBy(createdBy).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create invitation: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	logger.FromContext(ctx).Infof("Invitation %s for %s (role %s) created by %s", inv.ID, email, role, createdBy)
+
+	if s.options.AcceptURL != "" {
+		err := s.mailer.Send(ctx, mailer.Message{
+			To:      email,
+			Subject: "You have been invited",
+			Body: fmt.Sprintf("You have been invited to create an account.\n\nRegister before %s:\n%s",
+				expiresAt.Format(time.RFC1123), link(s.options.AcceptURL, token)),
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Failed to send invitation %s: %v", inv.ID, err)
+		}
+	}
+
+	return inv, token, nil
+}
+
+// ListInvitations lists all invitations, newest first
+func (s *DBInvitationService) ListInvitations(ctx context.Context) ([]*ent.Invitation, error) {
+	invitations, err := s.db(ctx).Invitation.Query().
+		Order(ent.Desc(invitation.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// RevokeInvitation deletes a pending invitation
+func (s *DBInvitationService) RevokeInvitation(ctx context.Context, id string) error {
+	n, err := s.db(ctx).Invitation.Delete().
+		Where(invitation.ID(id), invitation.AcceptedAtIsNil()).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+	if n == 0 {
+		return errors.New("invitation not found or already accepted")
+	}
+	return nil
+}
+
+// Register creates the invited user. Claiming the invitation, creating the user and recording
+// it on the invitation run in one transaction, so an invitation is used at most once and
+// stays valid if creating the user fails.
+func (s *DBInvitationService) Register(ctx context.Context, token string, input model.CreateUserInput) (*ent.User, error) {
+	inv, err := s.db(ctx).Invitation.Query().
+		Where(invitation.TokenHash(hashToken(token))).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errInvalidInvitation
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if inv.AcceptedAt != nil || time.Now().After(inv.ExpiresAt) {
+		return nil, errInvalidInvitation
+	}
+	if !strings.EqualFold(strings.TrimSpace(input.Email), inv.Email) {
+		return nil, errors.New("email does not match the invitation")
+	}
+
+	var u *ent.User
+	err = dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		claimed, err := tx.Invitation.Update().
+			Where(invitation.ID(inv.ID), invitation.AcceptedAtIsNil()).
+			SetAcceptedAt(time.Now()).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to claim invitation: %w", err)
+		}
+		if claimed == 0 {
+			// Accepted concurrently
+			return errInvalidInvitation
+		}
+
+		// The user service joins the transaction through ctx
+		input.Roles = []string{inv.Role}
+		u, err = s.userService.CreateUser(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Invitation.UpdateOneID(inv.ID).SetAcceptedUserID(u.ID).Save(ctx); err != nil {
+			return fmt.Errorf("failed to record user of invitation: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).Infof("Invitation %s accepted by user %s", inv.ID, u.ID)
+
+	return u, nil
+}
+
+// db returns the client of the caller's transaction, if any, else that of the service
+func (s *DBInvitationService) db(ctx context.Context) *ent.Client {
+	return dbtx.Client(ctx, s.client)
+}
+
+// generateToken creates a random URL-safe token
+func generateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken returns the SHA-256 hash of a token; only the hash is persisted
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// link appends the token to a base URL
+func link(baseURL, token string) string {
+	separator := "?"
+	if strings.Contains(baseURL, "?") {
+		separator = "&"
+	}
+	return baseURL + separator + "token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// Error codes defined by RFC 6749
+const (
+	ErrCodeInvalidRequest          = "invalid_request"
+	ErrCodeInvalidClient           = "invalid_client"
+	ErrCodeInvalidGrant            = "invalid_grant"
+	ErrCodeInvalidScope            = "invalid_scope"
+	ErrCodeAccessDenied            = "access_denied"
+	ErrCodeUnsupportedGrantType    = "unsupported_grant_type"
+	ErrCodeUnsupportedResponseType = "unsupported_response_type"
+	ErrCodeServerError             = "server_error"
+)
+
+// Error is an OAuth error returned to the client as-is
+type Error struct {
+	Code        string
+	Description string
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// ConsentHook decides whether an authorization request may skip the consent screen,
+// e.g. because the user approved the client before. The default skips it for first-party clients.
+type ConsentHook func(ctx context.Context, user *ent.User, client *ent.OAuthClient, scopes []string) (bool, error)
+
+// AuthorizeResult is the outcome of an authorization request: either a consent prompt
+// for the user, or the redirect URI carrying the code or the error back to the client
+type AuthorizeResult struct {
+	ConsentRequired bool
+	Client          *ent.OAuthClient
+	Scopes          []string
+	RedirectURI     string
+}
+
+// TokenResult is a token pair issued to a client with the scopes it carries
+type TokenResult struct {
+	Tokens *jwt.TokenPair
+	Scopes []string
+}
+
+// OAuthService defines the interface for the built-in OAuth2 authorization server:
+// client registration, the authorization code flow with PKCE and the token endpoint.
+// Tokens use the same JWT format as direct logins.
+type OAuthService interface {
+	CreateClient(ctx context.Context, input model.CreateOAuthClientInput, createdBy string) (*ent.OAuthClient, string, error)
+	ListClients(ctx context.Context) ([]*ent.OAuthClient, error)
+	DeleteClient(ctx context.Context, id string) error
+	// Authorize handles an authorization request of the signed-in user. consent is nil when the
+	// user has not answered the consent screen yet, and their answer otherwise.
+	Authorize(ctx context.Context, userID string, input model.OAuthAuthorizeInput, consent *bool) (*AuthorizeResult, error)
+	Token(ctx context.Context, input model.OAuthTokenInput) (*TokenResult, error)
+}
@@ -0,0 +1,367 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/oauthclient"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Options configures the authorization server
+type Options struct {
+	// CodeTTL is how long an authorization code can be redeemed
+	CodeTTL time.Duration
+	// ConsentHook decides whether the consent screen can be skipped; nil skips it for first-party clients
+	ConsentHook ConsentHook
+}
+
+// CodeStore keeps pending authorization codes by their hash. Take returns and removes
+// a code's grant in one step, so a code cannot be redeemed twice.
+type CodeStore struct {
+	Store func(ctx context.Context, codeHash, grant string, expiration time.Duration) error
+	Take  func(ctx context.Context, codeHash string) (string, error)
+}
+
+// grant is what an authorization code stands for
+type grant struct {
+	ClientID      string   `json:"client_id"`
+	UserID        string   `json:"user_id"`
+	RedirectURI   string   `json:"redirect_uri"`
+	Scopes        []string `json:"scopes"`
+	CodeChallenge string   `json:"code_challenge"`
+}
+
+// DBOAuthService implements OAuthService
+type DBOAuthService struct {
+	client       *ent.Client
+	userService  user.UserService
+	tokenService jwt.TokenService
+	codes        CodeStore
+	options      Options
+}
+
+// NewOAuthService creates a new OAuth2 authorization server
+func NewOAuthService(
+	client *ent.Client,
+	userService user.UserService,
+	tokenService jwt.TokenService,
+	codes CodeStore,
+	options Options,
+) OAuthService {
+	if options.CodeTTL <= 0 {
+		options.CodeTTL = time.Minute
+	}
+	if options.ConsentHook == nil {
+		options.ConsentHook = func(ctx context.Context, _ *ent.User, c *ent.OAuthClient, _ []string) (bool, error) {
+			return c.FirstParty, nil
+		}
+	}
+	return &DBOAuthService{
+		client:       client,
+		userService:  userService,
+		tokenService: tokenService,
+		codes:        codes,
+		options:      options,
+	}
+}
+
+// CreateClient registers a client and returns its secret, which is empty for public clients
+func (s *DBOAuthService) CreateClient(ctx context.Context, input model.CreateOAuthClientInput, createdBy string) (*ent.OAuthClient, string, error) {
+	create := s.client.OAuthClient.Create().
+		SetName(input.Name).
+		SetRedirectUris(input.RedirectURIs).
+		SetScopes(input.Scopes).
+		SetFirstParty(input.FirstParty).
+		SetCreatedBy(createdBy)
+
+	var secret string
+	if !input.Public {
+		var err error
+		secret, err = generateToken()
+		if err != nil {
+			return nil, "", err
+		}
+		create.SetSecretHash(hashToken(secret))
+	}
+
+	c, err := create.Save(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create OAuth client: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OAuth client %s (%s) created by %s", c.ID, c.Name, createdBy)
+	return c, secret, nil
+}
+
+// ListClients lists all registered clients
+func (s *DBOAuthService) ListClients(ctx context.Context) ([]*ent.OAuthClient, error) {
+	clients, err := s.client.OAuthClient.Query().
+		Order(ent.Asc(oauthclient.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OAuth clients: %w", err)
+	}
+	return clients, nil
+}
+
+// DeleteClient removes a client. Tokens already issued to it stay valid until they expire,
+// but refreshing them is refused.
+func (s *DBOAuthService) DeleteClient(ctx context.Context, id string) error {
+	if err := s.client.OAuthClient.DeleteOneID(id).Exec(ctx); err != nil {
+		if ent.IsNotFound(err) {
+			return errors.New("OAuth client not found")
+		}
+		return fmt.Errorf("failed to delete OAuth client: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OAuth client %s deleted", id)
+	return nil
+}
+
+// Authorize validates an authorization request and issues a code once consent is given.
+// Errors about the client or redirect URI are returned directly, because the redirect
+// URI cannot be trusted; later errors are reported to the client through the redirect.
+func (s *DBOAuthService) Authorize(ctx context.Context, userID string, input model.OAuthAuthorizeInput, consent *bool) (*AuthorizeResult, error) {
+	c, err := s.client.OAuthClient.Get(ctx, input.ClientID)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, &Error{ErrCodeInvalidClient, "unknown client"}
+		}
+		return nil, fmt.Errorf("failed to get OAuth client: %w", err)
+	}
+	if input.RedirectURI == "" || !slices.Contains(c.RedirectUris, input.RedirectURI) {
+		return nil, &Error{ErrCodeInvalidRequest, "redirect_uri is not registered for this client"}
+	}
+
+	if input.ResponseType != "code" {
+		return redirectError(input, ErrCodeUnsupportedResponseType, "only the code response type is supported"), nil
+	}
+	// PKCE is mandatory for every client
+	if input.CodeChallenge == "" || input.CodeChallengeMethod != "S256" {
+		return redirectError(input, ErrCodeInvalidRequest, "code_challenge with code_challenge_method S256 is required"), nil
+	}
+
+	u, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	scopes, ok := s.grantedScopes(c, u, input.Scope)
+	if !ok {
+		return redirectError(input, ErrCodeInvalidScope, "requested scope is not allowed"), nil
+	}
+
+	if consent == nil {
+		skip, err := s.options.ConsentHook(ctx, u, c, scopes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check consent: %w", err)
+		}
+		if !skip {
+			return &AuthorizeResult{ConsentRequired: true, Client: c, Scopes: scopes}, nil
+		}
+	} else if !*consent {
+		return redirectError(input, ErrCodeAccessDenied, "the user denied the request"), nil
+	}
+
+	code, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(grant{
+		ClientID:      c.ID,
+		UserID:        u.ID,
+		RedirectURI:   input.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: input.CodeChallenge,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode authorization code: %w", err)
+	}
+	if err := s.codes.Store(ctx, hashToken(code), string(payload), s.options.CodeTTL); err != nil {
+		return nil, fmt.Errorf("failed to store authorization code: %w", err)
+	}
+
+	return &AuthorizeResult{
+		Client:      c,
+		Scopes:      scopes,
+		RedirectURI: redirectURI(input.RedirectURI, url.Values{"code": {code}, "state": {input.State}}),
+	}, nil
+}
+
+// Token handles the authorization_code and refresh_token grants
+func (s *DBOAuthService) Token(ctx context.Context, input model.OAuthTokenInput) (*TokenResult, error) {
+	c, err := s.authenticateClient(ctx, input.ClientID, input.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch input.GrantType {
+	case "authorization_code":
+		return s.exchangeCode(ctx, c, input)
+	case "refresh_token":
+		return s.refresh(ctx, c, input.RefreshToken)
+	default:
+		return nil, &Error{ErrCodeUnsupportedGrantType, "grant_type must be authorization_code or refresh_token"}
+	}
+}
+
+// exchangeCode redeems an authorization code for a token pair
+func (s *DBOAuthService) exchangeCode(ctx context.Context, c *ent.OAuthClient, input model.OAuthTokenInput) (*TokenResult, error) {
+	if input.Code == "" || input.CodeVerifier == "" {
+		return nil, &Error{ErrCodeInvalidRequest, "code and code_verifier are required"}
+	}
+
+	payload, err := s.codes.Take(ctx, hashToken(input.Code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization code: %w", err)
+	}
+	if payload == "" {
+		return nil, &Error{ErrCodeInvalidGrant, "invalid or expired code"}
+	}
+	var g grant
+	if err := json.Unmarshal([]byte(payload), &g); err != nil {
+		return nil, fmt.Errorf("failed to decode authorization code: %w", err)
+	}
+	if g.ClientID != c.ID || g.RedirectURI != input.RedirectURI {
+		return nil, &Error{ErrCodeInvalidGrant, "code was issued to another client or redirect_uri"}
+	}
+	if !verifyCodeChallenge(input.CodeVerifier, g.CodeChallenge) {
+		return nil, &Error{ErrCodeInvalidGrant, "code_verifier does not match the code challenge"}
+	}
+
+	u, err := s.userService.GetUserByID(ctx, g.UserID)
+	if err != nil || !u.Active {
+		return nil, &Error{ErrCodeInvalidGrant, "the user is no longer active"}
+	}
+
+	tokens, err := s.tokenService.GenerateTokenPairWithOptions(ctx, u.ID, u.Email, u.Roles, jwt.TokenOptions{
+		Scopes:                 g.Scopes,
+		ClientID:               c.ID,
+		Fingerprint:            jwt.FingerprintFromContext(ctx),
+		Device:                 jwt.DeviceFromContext(ctx),
+		TenantID:               dbtenant.From(ctx),
+		OrgID:                  u.OrgID,
+		PasswordChangeRequired: u.MustChangePassword,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OAuth client %s issued tokens for user %s with scopes %v", c.ID, u.ID, g.Scopes)
+	return &TokenResult{Tokens: tokens, Scopes: g.Scopes}, nil
+}
+
+// refresh rotates a refresh token that was issued to the client
+func (s *DBOAuthService) refresh(ctx context.Context, c *ent.OAuthClient, refreshToken string) (*TokenResult, error) {
+	if refreshToken == "" {
+		return nil, &Error{ErrCodeInvalidRequest, "refresh_token is required"}
+	}
+	claims, err := s.tokenService.ValidateToken(ctx, refreshToken, jwt.RefreshToken)
+	if err != nil || claims.ClientID != c.ID {
+		return nil, &Error{ErrCodeInvalidGrant, "invalid refresh token"}
+	}
+
+	tokens, err := s.tokenService.RefreshTokens(ctx, refreshToken)
+	if err != nil {
+		return nil, &Error{ErrCodeInvalidGrant, "invalid refresh token"}
+	}
+	return &TokenResult{Tokens: tokens, Scopes: claims.Scopes}, nil
+}
+
+// authenticateClient looks up the client and checks its secret. Public clients have
+// no secret and are identified by client_id alone; PKCE protects their codes.
+func (s *DBOAuthService) authenticateClient(ctx context.Context, clientID, secret string) (*ent.OAuthClient, error) {
+	if clientID == "" {
+		return nil, &Error{ErrCodeInvalidClient, "client_id is required"}
+	}
+	c, err := s.client.OAuthClient.Get(ctx, clientID)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, &Error{ErrCodeInvalidClient, "unknown client"}
+		}
+		return nil, fmt.Errorf("failed to get OAuth client: %w", err)
+	}
+	if c.SecretHash != "" && subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(c.SecretHash)) != 1 {
+		return nil, &Error{ErrCodeInvalidClient, "invalid client credentials"}
+	}
+	return c, nil
+}
+
+// grantedScopes resolves the requested space-separated scopes, defaulting to all scopes of
+// the client. Every scope must be allowed for the client and granted to the user's roles.
+func (s *DBOAuthService) grantedScopes(c *ent.OAuthClient, u *ent.User, requested string) ([]string, bool) {
+	scopes := strings.Fields(requested)
+	if len(scopes) == 0 {
+		scopes = c.Scopes
+	}
+	userScopes := s.tokenService.ScopesForRoles(u.Roles)
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) || !jwt.HasScopes(userScopes, scope) {
+			return nil, false
+		}
+	}
+	// An empty list grants nothing, while nil would fall back to the role scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return scopes, true
+}
+
+// verifyCodeChallenge checks an S256 PKCE code verifier against the challenge (RFC 7636 section 4.6)
+func verifyCodeChallenge(verifier, challenge string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// redirectError builds the result that reports an error to the client through its redirect URI
+func redirectError(input model.OAuthAuthorizeInput, code, description string) *AuthorizeResult {
+	params := url.Values{"error": {code}, "error_description": {description}}
+	if input.State != "" {
+		params.Set("state", input.State)
+	}
+	return &AuthorizeResult{RedirectURI: redirectURI(input.RedirectURI, params)}
+}
+
+// redirectURI appends params to the query of a registered redirect URI
+func redirectURI(base string, params url.Values) string {
+	if params.Get("state") == "" {
+		params.Del("state")
+	}
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + params.Encode()
+}
+
+// generateToken creates a random token for codes and client secrets
+func generateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken returns the SHA-256 hash of a token; only the hash is persisted
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
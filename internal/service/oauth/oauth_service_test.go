@@ -0,0 +1,200 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/user"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// memoryCodes is an in-process CodeStore whose codes expire on the time of now
+type memoryCodes struct {
+	mu    sync.Mutex
+	codes map[string]memoryCode
+	now   time.Time
+}
+
+type memoryCode struct {
+	grant     string
+	expiresAt time.Time
+}
+
+func (m *memoryCodes) store(ctx context.Context, codeHash, grant string, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.codes[codeHash] = memoryCode{grant: grant, expiresAt: m.now.Add(expiration)}
+	return nil
+}
+
+func (m *memoryCodes) take(ctx context.Context, codeHash string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code, ok := m.codes[codeHash]
+	delete(m.codes, codeHash)
+	if !ok || !m.now.Before(code.expiresAt) {
+		return "", nil
+	}
+	return code.grant, nil
+}
+
+// activeUsers is a UserService that knows every user as active; the other methods are not used
+type activeUsers struct {
+	user.UserService
+}
+
+func (activeUsers) GetUserByID(ctx context.Context, id string) (*ent.User, error) {
+	return &ent.User{ID: id, Email: id + "@example.com", Active: true}, nil
+}
+
+const (
+	testRedirectURI = "https://app.example.com/callback"
+	testVerifier    = "dBjftJeZ4CVP-mJ92ZXXdWBbvHfOIHFLY6xWDTLGPtUsZg"
+)
+
+func newTestOAuthService(t *testing.T) (*DBOAuthService, *memoryCodes) {
+	t.Helper()
+	codes := &memoryCodes{codes: make(map[string]memoryCode), now: time.Now()}
+	tokenService := jwt.NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, jwt.TokenEncryption{}, jwt.NewMemoryTokenStore(), nil)
+	s := NewOAuthService(nil, activeUsers{}, tokenService, CodeStore{Store: codes.store, Take: codes.take}, Options{})
+	return s.(*DBOAuthService), codes
+}
+
+// issueCode stores a code for the grant the way Authorize does, with the S256 challenge of
+// testVerifier
+func issueCode(t *testing.T, s *DBOAuthService, clientID string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(testVerifier))
+	payload, err := json.Marshal(grant{
+		ClientID:      clientID,
+		UserID:        "u1",
+		RedirectURI:   testRedirectURI,
+		Scopes:        []string{"profile"},
+		CodeChallenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := generateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.codes.Store(context.Background(), hashToken(code), string(payload), s.options.CodeTTL); err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestExchangeCode(t *testing.T) {
+	client := &ent.OAuthClient{ID: "c1"}
+	input := func(code string) model.OAuthTokenInput {
+		return model.OAuthTokenInput{
+			GrantType:    "authorization_code",
+			ClientID:     "c1",
+			Code:         code,
+			RedirectURI:  testRedirectURI,
+			CodeVerifier: testVerifier,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		client  *ent.OAuthClient
+		input   func(code string) model.OAuthTokenInput
+		expire  bool
+		wantErr bool
+	}{
+		{name: "valid code", client: client, input: input},
+		{
+			name:   "PKCE verifier mismatch",
+			client: client,
+			input: func(code string) model.OAuthTokenInput {
+				in := input(code)
+				in.CodeVerifier = "another-verifier-of-the-right-length-0123456789"
+				return in
+			},
+			wantErr: true,
+		},
+		{name: "client_id mismatch", client: &ent.OAuthClient{ID: "c2"}, input: input, wantErr: true},
+		{
+			name:   "redirect_uri mismatch",
+			client: client,
+			input: func(code string) model.OAuthTokenInput {
+				in := input(code)
+				in.RedirectURI = "https://evil.example.com/callback"
+				return in
+			},
+			wantErr: true,
+		},
+		{name: "expired code", client: client, input: input, expire: true, wantErr: true},
+		{
+			name:   "unknown code",
+			client: client,
+			input: func(string) model.OAuthTokenInput {
+				return input("0000")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, codes := newTestOAuthService(t)
+			code := issueCode(t, s, "c1")
+			if tt.expire {
+				codes.now = codes.now.Add(s.options.CodeTTL)
+			}
+
+			result, err := s.exchangeCode(context.Background(), tt.client, tt.input(code))
+			if !tt.wantErr {
+				if err != nil || result.Tokens.AccessToken == "" {
+					t.Fatalf("exchangeCode() = %+v, %v, want tokens", result, err)
+				}
+				return
+			}
+			var oauthErr *Error
+			if !errors.As(err, &oauthErr) || oauthErr.Code != ErrCodeInvalidGrant {
+				t.Errorf("exchangeCode() error = %v, want %s", err, ErrCodeInvalidGrant)
+			}
+		})
+	}
+}
+
+func TestExchangeCodeOnce(t *testing.T) {
+	s, _ := newTestOAuthService(t)
+	client := &ent.OAuthClient{ID: "c1"}
+	code := issueCode(t, s, "c1")
+	input := model.OAuthTokenInput{
+		GrantType:    "authorization_code",
+		ClientID:     "c1",
+		Code:         code,
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testVerifier,
+	}
+
+	if _, err := s.exchangeCode(context.Background(), client, input); err != nil {
+		t.Fatalf("first exchange: %v", err)
+	}
+	var oauthErr *Error
+	if _, err := s.exchangeCode(context.Background(), client, input); !errors.As(err, &oauthErr) || oauthErr.Code != ErrCodeInvalidGrant {
+		t.Errorf("second exchange error = %v, want %s", err, ErrCodeInvalidGrant)
+	}
+
+	// A failed exchange consumes the code too, so a wrong verifier cannot be retried
+	code = issueCode(t, s, "c1")
+	input.Code = code
+	input.CodeVerifier = "another-verifier-of-the-right-length-0123456789"
+	_, _ = s.exchangeCode(context.Background(), client, input)
+	input.CodeVerifier = testVerifier
+	if _, err := s.exchangeCode(context.Background(), client, input); !errors.As(err, &oauthErr) || oauthErr.Code != ErrCodeInvalidGrant {
+		t.Errorf("exchange after a failed one error = %v, want %s", err, ErrCodeInvalidGrant)
+	}
+}
@@ -0,0 +1,33 @@
+package organization
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+var (
+	// ErrOrganizationNotFound is returned when an organization does not exist
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrOrganizationExists is returned when creating an organization with a slug that is taken
+	ErrOrganizationExists = errors.New("organization with this slug already exists")
+	// ErrInvalidSlug is returned for slugs other than lowercase letters, digits and single hyphens
+	ErrInvalidSlug = errors.New("slug may only contain lowercase letters, digits and hyphens")
+	// ErrOrganizationNotEmpty is returned when deleting an organization that still has users
+	ErrOrganizationNotEmpty = errors.New("organization still has users")
+)
+
+// OrganizationService defines the interface for organizations, the tenants of the deployment
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, input model.CreateOrganizationInput) (*ent.Organization, error)
+	GetOrganization(ctx context.Context, id string) (*ent.Organization, error)
+	ListOrganizations(ctx context.Context) ([]*ent.Organization, error)
+	UpdateOrganization(ctx context.Context, id string, input model.UpdateOrganizationInput) (*ent.Organization, error)
+	// DeleteOrganization deletes an organization without users, together with its groups
+	DeleteOrganization(ctx context.Context, id string) error
+	// IsActive reports whether an organization exists and is active.
+	// It is meant for per-request use and is served from cache.
+	IsActive(ctx context.Context, id string) (bool, error)
+}
@@ -0,0 +1,17 @@
+package otp
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// OTPService defines the interface for one-time codes sent by SMS, used to log in and to
+// verify the phone number of a user
+type OTPService interface {
+	RequestLoginCode(ctx context.Context, phone string) error
+	VerifyLoginCode(ctx context.Context, phone, code string, rememberMe bool) (*jwt.TokenPair, *ent.User, error)
+	RequestPhoneVerification(ctx context.Context, userID string) error
+	VerifyPhone(ctx context.Context, userID, code string) (*ent.User, error)
+}
@@ -0,0 +1,300 @@
+package otp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/sms"
+)
+
+var (
+	// ErrInvalidCode is returned for a wrong, expired or already used code
+	ErrInvalidCode = errors.New("invalid or expired code")
+	// ErrNoPhone is returned when verifying the phone number of a user who has none
+	ErrNoPhone = errors.New("no phone number to verify")
+	// ErrPhoneVerified is returned when the phone number of a user is already verified
+	ErrPhoneVerified = errors.New("phone number is already verified")
+)
+
+// Options configures the SMS login flow
+type Options struct {
+	CodeLength int
+	CodeTTL    time.Duration
+	// MaxAttempts is the number of verification attempts allowed per code
+	MaxAttempts int
+}
+
+// CodeStore keeps the hash of the pending code of each phone number. Verification codes
+// are kept under verificationKey, so they never collide with login codes.
+type CodeStore struct {
+	Store  func(ctx context.Context, phone, codeHash string, expiration time.Duration) error
+	Get    func(ctx context.Context, phone string) (string, error)
+	Delete func(ctx context.Context, phone string) error
+}
+
+// DBOTPService implements OTPService
+type DBOTPService struct {
+	client       *ent.Client
+	tokenService jwt.TokenService
+	sender       sms.Sender
+	codes        CodeStore
+	// countAttempt counts a verification attempt for key within window
+	countAttempt func(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+	options      Options
+}
+
+// NewOTPService creates a new SMS login service
+func NewOTPService(
+	client *ent.Client,
+	tokenService jwt.TokenService,
+	sender sms.Sender,
+	codes CodeStore,
+	countAttempt func(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error),
+	options Options,
+) OTPService {
+	if options.CodeLength <= 0 {
+		options.CodeLength = 6
+	}
+	if options.CodeTTL <= 0 {
+		options.CodeTTL = 5 * time.Minute
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 5
+	}
+	return &DBOTPService{
+		client:       client,
+		tokenService: tokenService,
+		sender:       sender,
+		codes:        codes,
+		countAttempt: countAttempt,
+		options:      options,
+	}
+}
+
+// RequestLoginCode sends a login code to phone. Unknown or deactivated numbers get no
+// message but the same response, so the endpoint cannot be used to find registered numbers.
+func (s *DBOTPService) RequestLoginCode(ctx context.Context, phone string) error {
+	phone = strings.TrimSpace(phone)
+
+	u, err := s.client.User.Query().Where(user.PhoneIn(schema.EncryptedValues("User", user.FieldPhone, phone)...)).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			logger.FromContext(ctx).Infof("SMS login code requested for unknown phone number %s", maskPhone(phone))
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !u.Active {
+		logger.FromContext(ctx).Infof("SMS login code requested for deactivated user %s", u.ID)
+		return nil
+	}
+
+	code, err := generateCode(s.options.CodeLength)
+	if err != nil {
+		return fmt.Errorf("failed to generate code: %w", err)
+	}
+	// A new code replaces the previous one
+	if err := s.codes.Store(ctx, phone, hashCode(phone, code), s.options.CodeTTL); err != nil {
+		return fmt.Errorf("failed to store code: %w", err)
+	}
+
+	err = s.sender.Send(ctx, sms.Message{
+		To:   phone,
+		Body: fmt.Sprintf("Your login code is %s. It expires in %d minutes.", code, int(s.options.CodeTTL.Minutes())),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send code: %w", err)
+	}
+	return nil
+}
+
+// VerifyLoginCode checks the code sent to phone and logs the user in. Codes are single-use,
+// and a code is discarded once MaxAttempts wrong guesses have been made against it.
+func (s *DBOTPService) VerifyLoginCode(ctx context.Context, phone, code string, rememberMe bool) (*jwt.TokenPair, *ent.User, error) {
+	phone = strings.TrimSpace(phone)
+
+	attempts, _, err := s.countAttempt(ctx, "otp-verify:"+phone, s.options.CodeTTL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count attempts: %w", err)
+	}
+	if attempts > int64(s.options.MaxAttempts) {
+		if err := s.codes.Delete(ctx, phone); err != nil {
+			logger.FromContext(ctx).Warnf("Failed to discard SMS login code: %v", err)
+		}
+		return nil, nil, errors.New("too many attempts, please request a new code")
+	}
+
+	stored, err := s.codes.Get(ctx, phone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get code: %w", err)
+	}
+	if stored == "" || subtle.ConstantTimeCompare([]byte(stored), []byte(hashCode(phone, code))) != 1 {
+		return nil, nil, ErrInvalidCode
+	}
+	if err := s.codes.Delete(ctx, phone); err != nil {
+		return nil, nil, fmt.Errorf("failed to invalidate code: %w", err)
+	}
+
+	u, err := s.client.User.Query().Where(user.PhoneIn(schema.EncryptedValues("User", user.FieldPhone, phone)...)).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil, ErrInvalidCode
+		}
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !u.Active {
+		return nil, nil, errors.New("account is deactivated")
+	}
+
+	tokenPair, err := s.tokenService.GenerateTokenPairWithOptions(ctx, u.ID, u.Email, u.Roles, jwt.TokenOptions{
+		RememberMe:             rememberMe,
+		Fingerprint:            jwt.FingerprintFromContext(ctx),
+		Device:                 jwt.DeviceFromContext(ctx),
+		TenantID:               dbtenant.From(ctx),
+		OrgID:                  u.OrgID,
+		PasswordChangeRequired: u.MustChangePassword,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	// 收到登录验证码即证明手机号可用
+	update := s.client.User.UpdateOne(u).SetLastLogin(time.Now())
+	if !u.PhoneVerified {
+		update.SetPhoneVerified(true)
+	}
+	if updated, err := update.Save(ctx); err != nil {
+		logger.FromContext(ctx).Warnf("Failed to update last login time: %v", err)
+	} else {
+		u = updated
+	}
+
+	return tokenPair, u, nil
+}
+
+// RequestPhoneVerification sends a verification code to the phone number of a user. The
+// code is bound to that number, so it stops working if the number changes meanwhile.
+func (s *DBOTPService) RequestPhoneVerification(ctx context.Context, userID string) error {
+	u, err := s.client.User.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if u.Phone == nil {
+		return ErrNoPhone
+	}
+	if u.PhoneVerified {
+		return ErrPhoneVerified
+	}
+
+	code, err := generateCode(s.options.CodeLength)
+	if err != nil {
+		return fmt.Errorf("failed to generate code: %w", err)
+	}
+	if err := s.codes.Store(ctx, verificationKey(u.ID), hashCode(*u.Phone, code), s.options.CodeTTL); err != nil {
+		return fmt.Errorf("failed to store code: %w", err)
+	}
+
+	err = s.sender.Send(ctx, sms.Message{
+		To:   *u.Phone,
+		Body: fmt.Sprintf("Your verification code is %s. It expires in %d minutes.", code, int(s.options.CodeTTL.Minutes())),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send code: %w", err)
+	}
+	logger.FromContext(ctx).Infof("Phone verification code sent to %s for user %s", maskPhone(*u.Phone), u.ID)
+	return nil
+}
+
+// VerifyPhone checks the code sent by RequestPhoneVerification and marks the phone number
+// of the user as verified. Like login codes, a code allows MaxAttempts guesses.
+func (s *DBOTPService) VerifyPhone(ctx context.Context, userID, code string) (*ent.User, error) {
+	key := verificationKey(userID)
+	attempts, _, err := s.countAttempt(ctx, "otp-"+key, s.options.CodeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attempts: %w", err)
+	}
+	if attempts > int64(s.options.MaxAttempts) {
+		if err := s.codes.Delete(ctx, key); err != nil {
+			logger.FromContext(ctx).Warnf("Failed to discard phone verification code: %v", err)
+		}
+		return nil, errors.New("too many attempts, please request a new code")
+	}
+
+	u, err := s.client.User.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u.Phone == nil {
+		return nil, ErrNoPhone
+	}
+
+	stored, err := s.codes.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code: %w", err)
+	}
+	if stored == "" || subtle.ConstantTimeCompare([]byte(stored), []byte(hashCode(*u.Phone, code))) != 1 {
+		return nil, ErrInvalidCode
+	}
+	if err := s.codes.Delete(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to invalidate code: %w", err)
+	}
+
+	// 仅当号码未在此期间更换时才标记为已验证
+	n, err := s.client.User.Update().
+		Where(user.ID(u.ID), user.PhoneIn(schema.EncryptedValues("User", user.FieldPhone, *u.Phone)...)).
+		SetPhoneVerified(true).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify phone number: %w", err)
+	}
+	if n == 0 {
+		return nil, ErrInvalidCode
+	}
+	return s.client.User.Get(ctx, u.ID)
+}
+
+// verificationKey returns the code store key of the pending phone verification of a user
+func verificationKey(userID string) string {
+	return "verify:" + userID
+}
+
+// generateCode returns a random numeric code of the given length
+func generateCode(length int) (string, error) {
+	var b strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte('0' + n.Int64()))
+	}
+	return b.String(), nil
+}
+
+// hashCode binds a code to its phone number so only hashes are kept in Redis
+func hashCode(phone, code string) string {
+	sum := sha256.Sum256([]byte(phone + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// maskPhone keeps only the last four digits of a phone number for logging
+func maskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return "****"
+	}
+	return strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
+}
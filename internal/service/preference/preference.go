@@ -0,0 +1,25 @@
+package preference
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+var (
+	// ErrInvalidLocale is returned for locales that are not well-formed BCP 47 language tags
+	ErrInvalidLocale = errors.New("locale must be a BCP 47 language tag such as en or zh-CN")
+	// ErrInvalidTimezone is returned for timezones missing from the IANA time zone database
+	ErrInvalidTimezone = errors.New("timezone must be an IANA time zone such as UTC or Asia/Shanghai")
+)
+
+// PreferenceService defines the interface for the typed preferences of users
+type PreferenceService interface {
+	// GetPreferences returns the preferences of a user. Users who never saved any get an
+	// unsaved entity holding the defaults.
+	GetPreferences(ctx context.Context, userID string) (*ent.UserPreference, error)
+	// UpdatePreferences changes the given preferences, creating the user's row on first use
+	UpdatePreferences(ctx context.Context, userID string, input model.UpdatePreferencesInput) (*ent.UserPreference, error)
+}
@@ -0,0 +1,114 @@
+package preference
+
+import (
+	"context"
+	"fmt"
+	"time"
+	// 内置时区数据库，不依赖服务器安装的 tzdata
+	_ "time/tzdata"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/userpreference"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"golang.org/x/text/language"
+)
+
+// DBPreferenceService implements PreferenceService
+type DBPreferenceService struct {
+	client *ent.Client
+}
+
+// NewPreferenceService creates a new preference service
+func NewPreferenceService(client *ent.Client) PreferenceService {
+	return &DBPreferenceService{client: client}
+}
+
+// GetPreferences returns the preferences of a user, or the defaults when none are saved
+func (s *DBPreferenceService) GetPreferences(ctx context.Context, userID string) (*ent.UserPreference, error) {
+	p, err := s.client.UserPreference.Query().Where(userpreference.UserID(userID)).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return defaults(userID), nil
+		}
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	return p, nil
+}
+
+// UpdatePreferences changes the given preferences, creating the user's row on first use.
+// Locales are stored in canonical form, e.g. zh-cn becomes zh-CN, and timezones must be
+// IANA time zone names.
+func (s *DBPreferenceService) UpdatePreferences(ctx context.Context, userID string, input model.UpdatePreferencesInput) (*ent.UserPreference, error) {
+	locale := input.Locale
+	if locale != nil && *locale != "" {
+		tag, err := language.Parse(*locale)
+		if err != nil {
+			return nil, ErrInvalidLocale
+		}
+		canonical := tag.String()
+		locale = &canonical
+	}
+
+	if input.Timezone != nil && *input.Timezone != "" {
+		// Local 取决于服务器配置，不是有效的用户时区
+		if _, err := time.LoadLocation(*input.Timezone); err != nil || *input.Timezone == "Local" {
+			return nil, ErrInvalidTimezone
+		}
+	}
+
+	p, err := s.client.UserPreference.Query().Where(userpreference.UserID(userID)).Only(ctx)
+	switch {
+	case ent.IsNotFound(err):
+		create := s.client.UserPreference.Create().SetUserID(userID)
+		apply(create.Mutation(), input, locale)
+		p, err = create.Save(ctx)
+		if ent.IsConstraintError(err) {
+			// 并发的首次保存已创建记录，改为更新
+			return s.UpdatePreferences(ctx, userID, input)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	default:
+		update := s.client.UserPreference.UpdateOne(p)
+		apply(update.Mutation(), input, locale)
+		p, err = update.Save(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return p, nil
+}
+
+// apply sets the preferences given in input on a create or update mutation
+func apply(m *ent.UserPreferenceMutation, input model.UpdatePreferencesInput, locale *string) {
+	if n := input.Notifications; n != nil {
+		if n.Email != nil {
+			m.SetNotifyEmail(*n.Email)
+		}
+		if n.SMS != nil {
+			m.SetNotifySms(*n.SMS)
+		}
+		if n.Marketing != nil {
+			m.SetNotifyMarketing(*n.Marketing)
+		}
+	}
+	if input.Theme != nil {
+		m.SetTheme(userpreference.Theme(*input.Theme))
+	}
+	if locale != nil {
+		m.SetLocale(*locale)
+	}
+	if input.Timezone != nil {
+		m.SetTimezone(*input.Timezone)
+	}
+}
+
+// defaults returns the preferences of a user who never saved any
+func defaults(userID string) *ent.UserPreference {
+	return &ent.UserPreference{
+		UserID:      userID,
+		NotifyEmail: userpreference.DefaultNotifyEmail,
+		NotifySms:   userpreference.DefaultNotifySms,
+		Theme:       userpreference.DefaultTheme,
+	}
+}
@@ -0,0 +1,27 @@
+package presence
+
+import (
+	"context"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+)
+
+// Presence describes when a user was last active
+type Presence struct {
+	// LastSeen is the last authenticated request of the user, nil if they were never seen
+	LastSeen *time.Time
+	// Online reports whether LastSeen falls within the online window
+	Online bool
+}
+
+// PresenceService defines the interface for tracking when users were last active
+type PresenceService interface {
+	// Touch records that a user of the tenant of ctx made an authenticated request at the
+	// given time. Writes are buffered in Redis and reach the database with the next Flush.
+	Touch(ctx context.Context, userID string, at time.Time)
+	// Presence returns the presence of a user, including activity not flushed yet
+	Presence(ctx context.Context, u *ent.User) Presence
+	// Flush writes the buffered last-seen times to the database and returns how many users were updated
+	Flush(ctx context.Context) (int, error)
+}
@@ -0,0 +1,142 @@
+package presence
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Options configures last-seen tracking
+type Options struct {
+	// WriteInterval is the minimum time between two recorded requests of the same user
+	WriteInterval time.Duration
+	// OnlineWindow is how long after their last request a user counts as online
+	OnlineWindow time.Duration
+}
+
+// SeenStore buffers the last-seen times of users until they are flushed to the database.
+// Users of a tenant are keyed by dbtenant.ScopedKey.
+type SeenStore struct {
+	Record func(ctx context.Context, userID string, at time.Time) error
+	Get    func(ctx context.Context, userID string) (time.Time, error)
+	// Take returns and removes every buffered time
+	Take func(ctx context.Context) (map[string]time.Time, error)
+}
+
+// DBPresenceService implements PresenceService
+type DBPresenceService struct {
+	client  *ent.Client
+	store   SeenStore
+	options Options
+
+	mu sync.Mutex
+	// recorded holds when each user was last recorded by this instance, to throttle writes
+	recorded map[string]time.Time
+}
+
+// NewPresenceService creates a new presence service
+func NewPresenceService(client *ent.Client, store SeenStore, options Options) PresenceService {
+	if options.WriteInterval <= 0 {
+		options.WriteInterval = time.Minute
+	}
+	if options.OnlineWindow <= 0 {
+		options.OnlineWindow = 5 * time.Minute
+	}
+
+	return &DBPresenceService{
+		client:   client,
+		store:    store,
+		options:  options,
+		recorded: make(map[string]time.Time),
+	}
+}
+
+// Touch records a request of a user unless one was recorded less than WriteInterval ago
+func (s *DBPresenceService) Touch(ctx context.Context, userID string, at time.Time) {
+	key := dbtenant.ScopedKey(ctx, userID)
+	s.mu.Lock()
+	if last, ok := s.recorded[key]; ok && at.Sub(last) < s.options.WriteInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.recorded[key] = at
+	s.mu.Unlock()
+
+	if err := s.store.Record(ctx, key, at); err != nil {
+		logger.Warnf("Failed to record last seen of user %s: %v", key, err)
+		// 写入失败时允许下一个请求立即重试
+		s.mu.Lock()
+		delete(s.recorded, key)
+		s.mu.Unlock()
+	}
+}
+
+// Presence returns the later of the stored and the buffered last-seen time of a user
+func (s *DBPresenceService) Presence(ctx context.Context, u *ent.User) Presence {
+	lastSeen := u.LastSeen
+	pending, err := s.store.Get(ctx, dbtenant.ScopedKey(ctx, u.ID))
+	if err != nil {
+		logger.Warnf("Failed to get pending last seen of user %s: %v", u.ID, err)
+	} else if !pending.IsZero() && (lastSeen == nil || pending.After(*lastSeen)) {
+		lastSeen = &pending
+	}
+
+	return Presence{
+		LastSeen: lastSeen,
+		Online:   lastSeen != nil && time.Since(*lastSeen) < s.options.OnlineWindow,
+	}
+}
+
+// Flush writes the buffered last-seen times to the database. Times that fail to be written
+// are buffered again for the next flush.
+func (s *DBPresenceService) Flush(ctx context.Context) (int, error) {
+	s.pruneRecorded()
+
+	pending, err := s.store.Take(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to take pending last seen times: %w", err)
+	}
+
+	// 后台任务不属于任何组织，需要更新所有组织的用户
+	ctx = schema.SkipOrgScope(ctx)
+	updated := 0
+	var firstErr error
+	for key, at := range pending {
+		// 各租户的用户在各自的 schema 中更新
+		tenant, userID := dbtenant.SplitScopedKey(key)
+		n, err := s.client.User.Update().
+			Where(user.ID(userID), user.Or(user.LastSeenIsNil(), user.LastSeenLT(at))).
+			SetLastSeen(at).
+			Save(dbtenant.With(ctx, tenant))
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to update last seen: %w", err)
+			}
+			if rerr := s.store.Record(ctx, key, at); rerr != nil {
+				logger.Warnf("Failed to buffer last seen of user %s again: %v", key, rerr)
+			}
+			continue
+		}
+		updated += n
+	}
+	return updated, firstErr
+}
+
+// pruneRecorded forgets users whose throttle window has passed, so the map only holds
+// recently active users
+func (s *DBPresenceService) pruneRecorded() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for userID, at := range s.recorded {
+		if time.Since(at) >= s.options.WriteInterval {
+			delete(s.recorded, userID)
+		}
+	}
+}
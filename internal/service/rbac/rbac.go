@@ -0,0 +1,78 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// Permissions checked by the API routes
+const (
+	PermUserRead           = "user.read"
+	PermUserUpdate         = "user.update"
+	PermUserDelete         = "user.delete"
+	PermUserImpersonate    = "user.impersonate"
+	PermSettingManage      = "setting.manage"
+	PermInvitationManage   = "invitation.manage"
+	PermServiceAccountMgmt = "service_account.manage"
+	PermRunbookExecute     = "runbook.execute"
+	PermRoleManage         = "role.manage"
+	PermAuditRead          = "audit.read"
+	PermOAuthClientManage  = "oauth_client.manage"
+	PermAppCredentialMgmt  = "app_credential.manage"
+	PermGroupManage        = "group.manage"
+	PermOrgManage          = "org.manage"
+)
+
+// Built-in roles created on startup; they can be edited but not deleted
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Catalog lists every permission known to the application with its description.
+// Seed creates missing entries; the built-in admin role always holds all of them.
+var Catalog = map[string]string{
+	PermUserRead:           "View any user",
+	PermUserUpdate:         "Update any user",
+	PermUserDelete:         "Delete users",
+	PermUserImpersonate:    "Impersonate non-admin users",
+	PermSettingManage:      "Manage runtime settings and feature flags",
+	PermInvitationManage:   "Invite users and revoke invitations",
+	PermServiceAccountMgmt: "Manage service accounts",
+	PermRunbookExecute:     "Run operational runbook actions",
+	PermRoleManage:         "Manage roles and their permissions",
+	PermAuditRead:          "View the audit log",
+	PermOAuthClientManage:  "Register and remove OAuth clients",
+	PermAppCredentialMgmt:  "Manage app keys and secrets for request signing",
+	PermGroupManage:        "Manage every group, including groups the user does not belong to",
+	PermOrgManage:          "Create, deactivate and delete organizations",
+}
+
+var (
+	// ErrRoleNotFound is returned when a role does not exist
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrRoleExists is returned when creating a role whose name is taken
+	ErrRoleExists = errors.New("role already exists")
+	// ErrBuiltinRole is returned when deleting a built-in role
+	ErrBuiltinRole = errors.New("built-in roles cannot be deleted")
+	// ErrRoleInUse is returned when deleting a role that is still assigned to users
+	ErrRoleInUse = errors.New("role is assigned to users")
+	// ErrUnknownPermission is returned when a role references a permission outside the catalog
+	ErrUnknownPermission = errors.New("unknown permission")
+)
+
+// RBACService defines the interface for database-backed roles and permissions.
+// HasPermission is meant for per-request use and is served from cache.
+type RBACService interface {
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+	ListRoles(ctx context.Context) ([]*ent.Role, error)
+	GetRole(ctx context.Context, name string) (*ent.Role, error)
+	CreateRole(ctx context.Context, input model.CreateRoleInput) (*ent.Role, error)
+	UpdateRole(ctx context.Context, name string, input model.UpdateRoleInput) (*ent.Role, error)
+	DeleteRole(ctx context.Context, name string) error
+	ListPermissions(ctx context.Context) ([]*ent.Permission, error)
+	Seed(ctx context.Context) error
+}
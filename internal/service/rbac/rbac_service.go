@@ -0,0 +1,294 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"github.com/hewenyu/gin-pkg/internal/dbtx"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/permission"
+	"github.com/hewenyu/gin-pkg/internal/ent/role"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/util/cache"
+)
+
+// DBRBACService implements RBACService
+type DBRBACService struct {
+	client *ent.Client
+	// cache maps a role name to its permission set; unknown roles map to an empty set
+	cache *cache.SWRCache[map[string]bool]
+}
+
+// NewRBACService creates a new RBAC service
+func NewRBACService(client *ent.Client, cacheOptions cache.SWROptions) RBACService {
+	s := &DBRBACService{
+		client: client,
+	}
+	s.cache = cache.NewSWRCache("rbac", s.load, cacheOptions)
+	return s
+}
+
+// HasPermission reports whether the role grants the permission
+func (s *DBRBACService) HasPermission(ctx context.Context, roleName, perm string) (bool, error) {
+	perms, err := s.cache.Get(ctx, roleName)
+	if err != nil {
+		return false, err
+	}
+	return perms[perm], nil
+}
+
+// ListRoles lists all roles with their permissions
+func (s *DBRBACService) ListRoles(ctx context.Context) ([]*ent.Role, error) {
+	roles, err := s.db(ctx).Role.Query().
+		WithPermissions(func(q *ent.PermissionQuery) {
+			q.Order(ent.Asc(permission.FieldName))
+		}).
+		Order(ent.Asc(role.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// GetRole retrieves a role with its permissions by name
+func (s *DBRBACService) GetRole(ctx context.Context, name string) (*ent.Role, error) {
+	r, err := s.db(ctx).Role.Query().
+		Where(role.Name(name)).
+		WithPermissions(func(q *ent.PermissionQuery) {
+			q.Order(ent.Asc(permission.FieldName))
+		}).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return r, nil
+}
+
+// CreateRole creates a role with the given permissions
+func (s *DBRBACService) CreateRole(ctx context.Context, input model.CreateRoleInput) (*ent.Role, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("role name is required")
+	}
+	permIDs, err := s.permissionIDs(ctx, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db(ctx).Role.Create().
+		SetName(name).
+		SetDescription(input.Description).
+		AddPermissionIDs(permIDs...).
+		Save(ctx)
+	if err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, ErrRoleExists
+		}
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	s.cache.Invalidate(ctx, name)
+	logger.FromContext(ctx).Infof("Role %s created with permissions %v", name, input.Permissions)
+
+	return s.GetRole(ctx, name)
+}
+
+// UpdateRole updates a role's description and, when given, replaces its permissions
+func (s *DBRBACService) UpdateRole(ctx context.Context, name string, input model.UpdateRoleInput) (*ent.Role, error) {
+	r, err := s.GetRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	update := s.db(ctx).Role.UpdateOne(r)
+	if input.Description != nil {
+		update.SetDescription(*input.Description)
+	}
+	if input.Permissions != nil {
+		permIDs, err := s.permissionIDs(ctx, input.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		update.ClearPermissions().AddPermissionIDs(permIDs...)
+	}
+	if _, err := update.Save(ctx); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	// Other instances pick the change up once their cached copy goes stale
+	s.cache.Invalidate(ctx, name)
+	if input.Permissions != nil {
+		logger.FromContext(ctx).Infof("Role %s permissions set to %v", name, input.Permissions)
+	}
+
+	return s.GetRole(ctx, name)
+}
+
+// DeleteRole deletes a role that is neither built in nor assigned to any user
+func (s *DBRBACService) DeleteRole(ctx context.Context, name string) error {
+	r, err := s.GetRole(ctx, name)
+	if err != nil {
+		return err
+	}
+	if r.Builtin {
+		return ErrBuiltinRole
+	}
+
+	inUse, err := s.db(ctx).User.Query().
+		Where(func(s *sql.Selector) {
+			s.Where(sqljson.ValueContains(user.FieldRoles, name))
+		}).
+		Exist(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check role assignments: %w", err)
+	}
+	if inUse {
+		return ErrRoleInUse
+	}
+
+	if err := s.db(ctx).Role.DeleteOne(r).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	s.cache.Invalidate(ctx, name)
+	logger.FromContext(ctx).Infof("Role %s deleted", name)
+	return nil
+}
+
+// ListPermissions lists all known permissions
+func (s *DBRBACService) ListPermissions(ctx context.Context) ([]*ent.Permission, error) {
+	perms, err := s.db(ctx).Permission.Query().
+		Order(ent.Asc(permission.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+	return perms, nil
+}
+
+// Seed creates the permissions in Catalog and the built-in roles. The admin role
+// is granted every permission so it keeps full access when new permissions are added.
+func (s *DBRBACService) Seed(ctx context.Context) error {
+	names := make([]string, 0, len(Catalog))
+	for name := range Catalog {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	err := dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		existing, err := tx.Permission.Query().
+			Where(permission.NameIn(names...)).
+			All(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load permissions: %w", err)
+		}
+		known := make(map[string]bool, len(existing))
+		for _, p := range existing {
+			known[p.Name] = true
+		}
+		for _, name := range names {
+			if known[name] {
+				continue
+			}
+			if _, err := tx.Permission.Create().
+				SetName(name).
+				SetDescription(Catalog[name]).
+				Save(ctx); err != nil {
+				return fmt.Errorf("failed to create permission %s: %w", name, err)
+			}
+		}
+
+		for _, name := range []string{RoleAdmin, RoleUser} {
+			exists, err := tx.Role.Query().
+				Where(role.Name(name)).
+				Exist(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to check role %s: %w", name, err)
+			}
+			if exists {
+				continue
+			}
+			if _, err := tx.Role.Create().
+				SetName(name).
+				SetBuiltin(true).
+				Save(ctx); err != nil {
+				return fmt.Errorf("failed to create role %s: %w", name, err)
+			}
+		}
+
+		// 内置管理员角色始终拥有全部权限
+		missing, err := tx.Permission.Query().
+			Where(permission.Not(permission.HasRolesWith(role.Name(RoleAdmin)))).
+			IDs(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load admin permissions: %w", err)
+		}
+		if len(missing) > 0 {
+			if _, err := tx.Role.Update().
+				Where(role.Name(RoleAdmin)).
+				AddPermissionIDs(missing...).
+				Save(ctx); err != nil {
+				return fmt.Errorf("failed to grant admin permissions: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cache.Clear()
+	return nil
+}
+
+// permissionIDs resolves permission names to IDs, rejecting names outside the catalog
+func (s *DBRBACService) permissionIDs(ctx context.Context, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	for _, name := range names {
+		if _, ok := Catalog[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, name)
+		}
+	}
+	ids, err := s.db(ctx).Permission.Query().
+		Where(permission.NameIn(names...)).
+		IDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+	return ids, nil
+}
+
+// load reads a role's permission set from the database for the cache, outside any
+// transaction so the cache never holds uncommitted permissions
+func (s *DBRBACService) load(ctx context.Context, roleName string) (map[string]bool, error) {
+	names, err := s.client.Permission.Query().
+		Where(permission.HasRolesWith(role.Name(roleName))).
+		Select(permission.FieldName).
+		Strings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions of role %s: %w", roleName, err)
+	}
+	perms := make(map[string]bool, len(names))
+	for _, name := range names {
+		perms[name] = true
+	}
+	return perms, nil
+}
+
+// db returns the client of the caller's transaction, if any, else that of the service
+func (s *DBRBACService) db(ctx context.Context) *ent.Client {
+	return dbtx.Client(ctx, s.client)
+}
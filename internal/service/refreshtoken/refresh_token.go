@@ -0,0 +1,48 @@
+package refreshtoken
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// RevokedSession marks the refresh token of a session revoked with RevokeSession
+const RevokedSession = "session_revoked"
+
+var (
+	// ErrUserNotFound is returned when listing the sessions of a user that does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrSessionNotFound is returned when revoking a session that does not exist or has ended
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// Session is a login of a user that can still be refreshed, described by the refresh token
+// issued last in it
+type Session struct {
+	ID             string
+	IP             string
+	UserAgent      string
+	ImpersonatedBy string
+	ClientID       string
+	// SignedInAt is when the session started, zero once its first token has been purged
+	SignedInAt time.Time
+	// RefreshedAt is when the current refresh token was issued
+	RefreshedAt time.Time
+	ExpiresAt   time.Time
+}
+
+// RefreshTokenService records refresh tokens in the database for the token service, and
+// lists and revokes the sessions they make up. Revoked and rotated tokens are kept, with
+// when, why and by whom they were revoked, until PurgeExpired removes them.
+type RefreshTokenService interface {
+	jwt.RefreshTokenStore
+	// ListSessions returns the active sessions of a user, most recently refreshed first
+	ListSessions(ctx context.Context, userID string) ([]Session, error)
+	// RevokeSession revokes a session of a user; revokedBy is the user revoking it
+	RevokeSession(ctx context.Context, userID, sessionID, revokedBy string) error
+	// PurgeExpired removes the tokens that expired before the given time and returns how
+	// many were removed
+	PurgeExpired(ctx context.Context, expiredBefore time.Time) (int, error)
+}
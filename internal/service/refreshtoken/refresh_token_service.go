@@ -0,0 +1,207 @@
+package refreshtoken
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	entrefreshtoken "github.com/hewenyu/gin-pkg/internal/ent/refreshtoken"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// DBRefreshTokenService implements RefreshTokenService
+type DBRefreshTokenService struct {
+	client *ent.Client
+	// tenants are the tenants with their own schema, whose tokens RevokeUser revokes too
+	tenants []string
+}
+
+// NewRefreshTokenService creates a new refresh token service. tenants lists the tenants with
+// their own database schema, none unless database.schemaPerTenant is set.
+func NewRefreshTokenService(client *ent.Client, tenants []string) RefreshTokenService {
+	return &DBRefreshTokenService{
+		client:  client,
+		tenants: tenants,
+	}
+}
+
+// Save records a refresh token in the schema of its tenant
+func (s *DBRefreshTokenService) Save(ctx context.Context, record jwt.RefreshTokenRecord) error {
+	return s.client.RefreshToken.Create().
+		SetID(record.ID).
+		SetSessionID(record.SessionID).
+		SetUserID(record.UserID).
+		SetTokenHash(record.TokenHash).
+		SetIP(record.Device.IP).
+		SetUserAgent(record.Device.UserAgent).
+		SetImpersonatedBy(record.ImpersonatedBy).
+		SetClientID(record.ClientID).
+		SetExpiresAt(record.ExpiresAt).
+		Exec(dbtenant.With(ctx, record.TenantID))
+}
+
+// Rotate marks a refresh token used. A token that was already rotated is being replayed,
+// most likely by someone who stole it, so the whole session is revoked.
+func (s *DBRefreshTokenService) Rotate(ctx context.Context, tenantID, tokenID string) (jwt.Device, error) {
+	ctx = dbtenant.With(ctx, tenantID)
+
+	// 条件更新保证并发刷新时只有一个请求能轮换令牌
+	n, err := s.client.RefreshToken.Update().
+		Where(entrefreshtoken.ID(tokenID), entrefreshtoken.RevokedAtIsNil()).
+		SetRevokedAt(time.Now()).
+		SetRevokedReason(jwt.RevokedRotated).
+		Save(ctx)
+	if err != nil {
+		return jwt.Device{}, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	token, err := s.client.RefreshToken.Get(ctx, tokenID)
+	if ent.IsNotFound(err) {
+		return jwt.Device{}, jwt.ErrRefreshTokenRevoked
+	}
+	if err != nil {
+		return jwt.Device{}, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if n == 1 {
+		return jwt.Device{IP: token.IP, UserAgent: token.UserAgent}, nil
+	}
+
+	if token.RevokedReason == jwt.RevokedRotated {
+		revoked, err := s.client.RefreshToken.Update().
+			Where(entrefreshtoken.SessionID(token.SessionID), entrefreshtoken.RevokedAtIsNil()).
+			SetRevokedAt(time.Now()).
+			SetRevokedReason(jwt.RevokedReused).
+			Save(ctx)
+		if err != nil {
+			return jwt.Device{}, fmt.Errorf("failed to revoke session of reused refresh token: %w", err)
+		}
+		logger.FromContext(ctx).Warnf("Refresh token %s of user %s was used again after rotation, revoked %d tokens of session %s",
+			token.ID, token.UserID, revoked, token.SessionID)
+	}
+	return jwt.Device{}, jwt.ErrRefreshTokenRevoked
+}
+
+// Revoke marks a refresh token revoked, keeping the reason of an earlier revocation
+func (s *DBRefreshTokenService) Revoke(ctx context.Context, tenantID, tokenID, reason string) error {
+	err := s.client.RefreshToken.Update().
+		Where(entrefreshtoken.ID(tokenID), entrefreshtoken.RevokedAtIsNil()).
+		SetRevokedAt(time.Now()).
+		SetRevokedReason(reason).
+		Exec(dbtenant.With(ctx, tenantID))
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser marks the refresh tokens of a user revoked in every schema, as the token service
+// does not know which tenant the user belongs to
+func (s *DBRefreshTokenService) RevokeUser(ctx context.Context, userID, reason string) error {
+	for _, tenant := range append([]string{""}, s.tenants...) {
+		err := s.client.RefreshToken.Update().
+			Where(entrefreshtoken.UserID(userID), entrefreshtoken.RevokedAtIsNil()).
+			SetRevokedAt(time.Now()).
+			SetRevokedReason(reason).
+			Exec(dbtenant.With(ctx, tenant))
+		if err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens of user: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListSessions returns the active sessions of a user, most recently refreshed first. Each
+// session has a single token that is neither revoked nor expired, the last one issued in it.
+func (s *DBRefreshTokenService) ListSessions(ctx context.Context, userID string) ([]Session, error) {
+	exists, err := s.client.User.Query().Where(user.ID(userID)).Exist(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	tokens, err := s.client.RefreshToken.Query().
+		Where(
+			entrefreshtoken.UserID(userID),
+			entrefreshtoken.RevokedAtIsNil(),
+			entrefreshtoken.ExpiresAtGT(time.Now()),
+		).
+		Order(ent.Desc(entrefreshtoken.FieldCreatedAt), ent.Desc(entrefreshtoken.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	// 会话的开始时间取自登录时签发的第一个令牌
+	sessionIDs := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		sessionIDs = append(sessionIDs, token.SessionID)
+	}
+	firstTokens, err := s.client.RefreshToken.Query().
+		Where(entrefreshtoken.IDIn(sessionIDs...)).
+		Select(entrefreshtoken.FieldID, entrefreshtoken.FieldCreatedAt).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	signedInAt := make(map[string]time.Time, len(firstTokens))
+	for _, token := range firstTokens {
+		signedInAt[token.ID] = token.CreatedAt
+	}
+
+	sessions := make([]Session, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, Session{
+			ID:             token.SessionID,
+			IP:             token.IP,
+			UserAgent:      token.UserAgent,
+			ImpersonatedBy: token.ImpersonatedBy,
+			ClientID:       token.ClientID,
+			SignedInAt:     signedInAt[token.SessionID],
+			RefreshedAt:    token.CreatedAt,
+			ExpiresAt:      token.ExpiresAt,
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes the active refresh token of a session, so it cannot be refreshed
+// again. Access tokens already issued in it stay valid until they expire.
+func (s *DBRefreshTokenService) RevokeSession(ctx context.Context, userID, sessionID, revokedBy string) error {
+	n, err := s.client.RefreshToken.Update().
+		Where(
+			entrefreshtoken.SessionID(sessionID),
+			entrefreshtoken.UserID(userID),
+			entrefreshtoken.RevokedAtIsNil(),
+			entrefreshtoken.ExpiresAtGT(time.Now()),
+		).
+		SetRevokedAt(time.Now()).
+		SetRevokedReason(RevokedSession).
+		SetRevokedBy(revokedBy).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+
+	logger.FromContext(ctx).Infof("Session %s of user %s revoked by %s", sessionID, userID, revokedBy)
+	return nil
+}
+
+// PurgeExpired removes the tokens that expired before the given time
+func (s *DBRefreshTokenService) PurgeExpired(ctx context.Context, expiredBefore time.Time) (int, error) {
+	n, err := s.client.RefreshToken.Delete().
+		Where(entrefreshtoken.ExpiresAtLT(expiredBefore)).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge refresh tokens: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,112 @@
+package runbook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+)
+
+// CacheFlusher empties one cache namespace and returns how many entries were removed,
+// or -1 if the cache cannot tell
+type CacheFlusher func(ctx context.Context) (int64, error)
+
+// Seeder creates the data a fresh installation needs; it must be safe to run again
+type Seeder func(ctx context.Context) error
+
+// Backuper backs up the database and returns where the backup was stored
+type Backuper func(ctx context.Context) (string, error)
+
+// FlushCacheAction flushes the cache namespace given in the "namespace" parameter
+func FlushCacheAction(flushers map[string]CacheFlusher) Action {
+	return Action{
+		Name:        "flush-cache",
+		Description: "Flush a single cache namespace",
+		Params:      map[string]string{"namespace": "one of " + strings.Join(sortedKeys(flushers), ", ")},
+		Run: func(ctx context.Context, params map[string]string) (string, error) {
+			namespace := params["namespace"]
+			flush, ok := flushers[namespace]
+			if !ok {
+				return "", fmt.Errorf("unknown cache namespace %q, expected one of %s",
+					namespace, strings.Join(sortedKeys(flushers), ", "))
+			}
+			n, err := flush(ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to flush %s: %w", namespace, err)
+			}
+			if n < 0 {
+				return fmt.Sprintf("flushed %s", namespace), nil
+			}
+			return fmt.Sprintf("flushed %d entries from %s", n, namespace), nil
+		},
+	}
+}
+
+// RotateSignatureSecretAction switches request signing to the configured next secret,
+// accepting the old one for overlap
+func RotateSignatureSecretAction(securityService security.SecurityService, next string, overlap time.Duration) Action {
+	return Action{
+		Name:        "rotate-signature-secret",
+		Description: "Switch the request signature secret to security.nextSignatureSecret",
+		Run: func(ctx context.Context, params map[string]string) (string, error) {
+			if err := securityService.RotateSignatureSecret(next, overlap); err != nil {
+				return "", err
+			}
+			// 仅修改了内存中的密钥，重启后会恢复为配置文件中的值
+			return fmt.Sprintf("signature secret rotated, the old secret is accepted for %s; set security.signatureSecret "+
+				"to the new value and security.secondarySignatureSecret to the old one before the next restart", overlap), nil
+		},
+	}
+}
+
+// SeedAction re-runs the seeder named in the "seeder" parameter, or all of them
+func SeedAction(seeders map[string]Seeder) Action {
+	return Action{
+		Name:        "run-seeders",
+		Description: "Re-run the idempotent data seeders",
+		Params:      map[string]string{"seeder": "optional, one of " + strings.Join(sortedKeys(seeders), ", ")},
+		Run: func(ctx context.Context, params map[string]string) (string, error) {
+			names := sortedKeys(seeders)
+			if name := params["seeder"]; name != "" {
+				if _, ok := seeders[name]; !ok {
+					return "", fmt.Errorf("unknown seeder %q", name)
+				}
+				names = []string{name}
+			}
+			for _, name := range names {
+				if err := seeders[name](ctx); err != nil {
+					return "", fmt.Errorf("seeder %s failed: %w", name, err)
+				}
+			}
+			return "ran seeders: " + strings.Join(names, ", "), nil
+		},
+	}
+}
+
+// BackupDatabaseAction backs up the database; restoring is left to `server backup restore`
+func BackupDatabaseAction(backup Backuper) Action {
+	return Action{
+		Name:        "backup-database",
+		Description: "Dump the database to the backup storage",
+		Run: func(ctx context.Context, params map[string]string) (string, error) {
+			key, err := backup(ctx)
+			if err != nil {
+				return "", err
+			}
+			return "database backed up to " + key, nil
+		},
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
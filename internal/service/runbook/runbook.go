@@ -0,0 +1,25 @@
+package runbook
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnknownAction is returned when running an action that is not registered
+var ErrUnknownAction = errors.New("unknown runbook action")
+
+// Action is an operational task that can be triggered through the admin API instead of
+// running a script on the server. Run returns a short human-readable summary of what it did.
+type Action struct {
+	Name        string
+	Description string
+	// Params documents the parameters the action accepts
+	Params map[string]string
+	Run    func(ctx context.Context, params map[string]string) (string, error)
+}
+
+// RunbookService defines the interface for running operational actions
+type RunbookService interface {
+	ListActions() []Action
+	Run(ctx context.Context, name string, params map[string]string) (string, error)
+}
@@ -0,0 +1,40 @@
+package runbook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DefaultRunbookService implements RunbookService on a fixed set of actions
+type DefaultRunbookService struct {
+	actions map[string]Action
+}
+
+// NewRunbookService creates a new runbook service with the given actions
+func NewRunbookService(actions ...Action) RunbookService {
+	s := &DefaultRunbookService{actions: make(map[string]Action, len(actions))}
+	for _, action := range actions {
+		s.actions[action.Name] = action
+	}
+	return s
+}
+
+// ListActions returns the registered actions sorted by name
+func (s *DefaultRunbookService) ListActions() []Action {
+	actions := make([]Action, 0, len(s.actions))
+	for _, action := range s.actions {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+	return actions
+}
+
+// Run runs the named action
+func (s *DefaultRunbookService) Run(ctx context.Context, name string, params map[string]string) (string, error) {
+	action, ok := s.actions[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownAction, name)
+	}
+	return action.Run(ctx, params)
+}
@@ -0,0 +1,20 @@
+package serviceaccount
+
+import (
+	"context"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// ServiceAccountService defines the interface for service account operations
+type ServiceAccountService interface {
+	CreateServiceAccount(ctx context.Context, input model.CreateServiceAccountInput, createdBy string) (*ent.ServiceAccount, string, error)
+	GetServiceAccount(ctx context.Context, id string) (*ent.ServiceAccount, error)
+	ListServiceAccounts(ctx context.Context) ([]*ent.ServiceAccount, error)
+	RevokeServiceAccount(ctx context.Context, id string) error
+	RotateServiceAccount(ctx context.Context, id string, input model.RotateServiceAccountInput) (*ent.ServiceAccount, string, error)
+	Authenticate(ctx context.Context, token, clientIP string) (*ent.ServiceAccount, error)
+	ListExpiringServiceAccounts(ctx context.Context, within time.Duration) ([]*ent.ServiceAccount, error)
+}
@@ -0,0 +1,289 @@
+package serviceaccount
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/serviceaccount"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// tokenPrefix marks service account tokens so they are easy to recognise in logs and secret scanners
+const tokenPrefix = "sa_"
+
+// DBServiceAccountService implements ServiceAccountService
+type DBServiceAccountService struct {
+	client      *ent.Client
+	maxLifetime time.Duration
+}
+
+// NewServiceAccountService creates a new service account service
+func NewServiceAccountService(client *ent.Client, maxLifetime time.Duration) ServiceAccountService {
+	return &DBServiceAccountService{
+		client:      client,
+		maxLifetime: maxLifetime,
+	}
+}
+
+// CreateServiceAccount creates a new service account and returns its plaintext token
+func (s *DBServiceAccountService) CreateServiceAccount(ctx context.Context, input model.CreateServiceAccountInput, createdBy string) (*ent.ServiceAccount, string, error) {
+	expiresAt, err := s.parseExpiry(input.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cidrs, err := normalizeCIDRs(input.AllowedCIDRs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	exists, err := s.client.ServiceAccount.Query().Where(serviceaccount.Name(input.Name)).Exist(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check for existing service account: %w", err)
+	}
+	if exists {
+		return nil, "", errors.New("service account with this name already exists")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	account, err := s.client.ServiceAccount.Create().
+		SetName(input.Name).
+		SetDescription(input.Description).
+		SetTokenHash(hashToken(token)).
+		SetTokenPrefix(token[:len(tokenPrefix)+8]).
+		SetScopes(input.Scopes).
+		SetAllowedCidrs(cidrs).
+		SetExpiresAt(expiresAt).
+		SetCreatedBy(createdBy).
+		Save(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Service account %s (%s) created by %s, expires at %s",
+		account.Name, account.ID, createdBy, account.ExpiresAt.Format(time.RFC3339))
+
+	return account, token, nil
+}
+
+// GetServiceAccount gets a service account by ID
+func (s *DBServiceAccountService) GetServiceAccount(ctx context.Context, id string) (*ent.ServiceAccount, error) {
+	account, err := s.client.ServiceAccount.Get(ctx, id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errors.New("service account not found")
+		}
+		return nil, fmt.Errorf("failed to get service account: %w", err)
+	}
+	return account, nil
+}
+
+// ListServiceAccounts lists all service accounts
+func (s *DBServiceAccountService) ListServiceAccounts(ctx context.Context) ([]*ent.ServiceAccount, error) {
+	accounts, err := s.client.ServiceAccount.Query().
+		Order(ent.Asc(serviceaccount.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// RevokeServiceAccount revokes a service account so its token can no longer be used
+func (s *DBServiceAccountService) RevokeServiceAccount(ctx context.Context, id string) error {
+	account, err := s.GetServiceAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+	if account.RevokedAt != nil {
+		return errors.New("service account is already revoked")
+	}
+
+	_, err = s.client.ServiceAccount.UpdateOne(account).
+		SetRevokedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to revoke service account: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Service account %s (%s) revoked", account.Name, account.ID)
+	return nil
+}
+
+// RotateServiceAccount replaces the token of a service account, invalidating the old one immediately
+func (s *DBServiceAccountService) RotateServiceAccount(ctx context.Context, id string, input model.RotateServiceAccountInput) (*ent.ServiceAccount, string, error) {
+	account, err := s.GetServiceAccount(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if account.RevokedAt != nil {
+		return nil, "", errors.New("service account is revoked")
+	}
+
+	expiresAt, err := s.parseExpiry(input.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	account, err = s.client.ServiceAccount.UpdateOne(account).
+		SetTokenHash(hashToken(token)).
+		SetTokenPrefix(token[:len(tokenPrefix)+8]).
+		SetExpiresAt(expiresAt).
+		Save(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to rotate service account token: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Service account %s (%s) token rotated, expires at %s",
+		account.Name, account.ID, account.ExpiresAt.Format(time.RFC3339))
+
+	return account, token, nil
+}
+
+// Authenticate resolves a service account from its plaintext token and the caller's IP
+func (s *DBServiceAccountService) Authenticate(ctx context.Context, token, clientIP string) (*ent.ServiceAccount, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return nil, errors.New("invalid service token")
+	}
+
+	account, err := s.client.ServiceAccount.Query().
+		Where(serviceaccount.TokenHash(hashToken(token))).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errors.New("invalid service token")
+		}
+		return nil, fmt.Errorf("failed to look up service account: %w", err)
+	}
+
+	if account.RevokedAt != nil {
+		logger.FromContext(ctx).Warnf("Rejected revoked service account %s (%s) from %s", account.Name, account.ID, clientIP)
+		return nil, errors.New("service token has been revoked")
+	}
+	if time.Now().After(account.ExpiresAt) {
+		logger.FromContext(ctx).Warnf("Rejected expired service account %s (%s) from %s", account.Name, account.ID, clientIP)
+		return nil, errors.New("service token has expired")
+	}
+	if !ipAllowed(clientIP, account.AllowedCidrs) {
+		logger.FromContext(ctx).Warnf("Rejected service account %s (%s) from disallowed IP %s", account.Name, account.ID, clientIP)
+		return nil, errors.New("client IP is not allowed for this service token")
+	}
+
+	// Record usage; failures here must not block the request
+	_, err = s.client.ServiceAccount.UpdateOne(account).
+		SetLastUsedAt(time.Now()).
+		SetLastUsedIP(clientIP).
+		AddUsageCount(1).
+		Save(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("Failed to record usage of service account %s: %v", account.ID, err)
+	}
+
+	logger.FromContext(ctx).Infof("Service account %s (%s) authenticated from %s", account.Name, account.ID, clientIP)
+	return account, nil
+}
+
+// ListExpiringServiceAccounts lists active service accounts that expire within the given duration
+func (s *DBServiceAccountService) ListExpiringServiceAccounts(ctx context.Context, within time.Duration) ([]*ent.ServiceAccount, error) {
+	accounts, err := s.client.ServiceAccount.Query().
+		Where(
+			serviceaccount.RevokedAtIsNil(),
+			serviceaccount.ExpiresAtLTE(time.Now().Add(within)),
+		).
+		Order(ent.Asc(serviceaccount.FieldExpiresAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring service accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// parseExpiry parses an RFC3339 expiry and checks it against the configured maximum lifetime
+func (s *DBServiceAccountService) parseExpiry(value string) (time.Time, error) {
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("expires_at must be an RFC3339 timestamp")
+	}
+	if !expiresAt.After(time.Now()) {
+		return time.Time{}, errors.New("expires_at must be in the future")
+	}
+	if s.maxLifetime > 0 && expiresAt.After(time.Now().Add(s.maxLifetime)) {
+		return time.Time{}, fmt.Errorf("expires_at exceeds the maximum lifetime of %s", s.maxLifetime)
+	}
+	return expiresAt, nil
+}
+
+// generateToken creates a new random service account token
+func generateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate service token: %w", err)
+	}
+	return tokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashToken returns the SHA-256 hash of a token; only the hash is persisted
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeCIDRs validates the allowlist, turning bare IP addresses into single-host ranges
+func normalizeCIDRs(values []string) ([]string, error) {
+	cidrs := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if ip := net.ParseIP(value); ip != nil {
+			if ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP range %q", value)
+		}
+		cidrs = append(cidrs, network.String())
+	}
+	if len(cidrs) == 0 {
+		return nil, errors.New("at least one allowed IP range is required")
+	}
+	return cidrs, nil
+}
+
+// ipAllowed reports whether the IP falls within one of the allowed ranges
+func ipAllowed(clientIP string, cidrs []string) bool {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package setting
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// FeatureFlagPrefix marks settings that are boolean feature flags
+const FeatureFlagPrefix = "feature."
+
+// SettingService defines the interface for runtime settings and feature flags.
+// GetValue and IsFeatureEnabled are meant for per-request use and are served from cache.
+type SettingService interface {
+	GetValue(ctx context.Context, key string) (string, bool, error)
+	IsFeatureEnabled(ctx context.Context, name string) bool
+	ListSettings(ctx context.Context) ([]*ent.Setting, error)
+	GetSetting(ctx context.Context, key string) (*ent.Setting, error)
+	UpsertSetting(ctx context.Context, key string, input model.UpdateSettingInput, updatedBy string) (*ent.Setting, error)
+	DeleteSetting(ctx context.Context, key string) error
+	Warm(ctx context.Context) error
+	ClearCache()
+}
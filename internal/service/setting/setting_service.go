@@ -0,0 +1,179 @@
+package setting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/setting"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/util/cache"
+)
+
+// cachedSetting is the cached result of a lookup; missing keys are cached too
+type cachedSetting struct {
+	value string
+	found bool
+}
+
+// DBSettingService implements SettingService
+type DBSettingService struct {
+	client *ent.Client
+	cache  *cache.SWRCache[cachedSetting]
+}
+
+// NewSettingService creates a new setting service
+func NewSettingService(client *ent.Client, cacheOptions cache.SWROptions) SettingService {
+	s := &DBSettingService{
+		client: client,
+	}
+	s.cache = cache.NewSWRCache("settings", s.load, cacheOptions)
+	return s
+}
+
+// GetValue returns the value of a setting and whether it exists
+func (s *DBSettingService) GetValue(ctx context.Context, key string) (string, bool, error) {
+	cached, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return "", false, err
+	}
+	return cached.value, cached.found, nil
+}
+
+// IsFeatureEnabled reports whether the feature flag "feature.<name>" is on.
+// Missing, unparsable or unreadable flags count as off.
+func (s *DBSettingService) IsFeatureEnabled(ctx context.Context, name string) bool {
+	value, found, err := s.GetValue(ctx, FeatureFlagPrefix+name)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("Failed to read feature flag %s: %v", name, err)
+		return false
+	}
+	if !found {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("Feature flag %s has non-boolean value %q", name, value)
+		return false
+	}
+	return enabled
+}
+
+// ListSettings lists all settings from the database
+func (s *DBSettingService) ListSettings(ctx context.Context) ([]*ent.Setting, error) {
+	settings, err := s.client.Setting.Query().
+		Order(ent.Asc(setting.FieldKey)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+	return settings, nil
+}
+
+// GetSetting retrieves a setting from the database
+func (s *DBSettingService) GetSetting(ctx context.Context, key string) (*ent.Setting, error) {
+	st, err := s.client.Setting.Query().
+		Where(setting.Key(key)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errors.New("setting not found")
+		}
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	return st, nil
+}
+
+// UpsertSetting creates or updates a setting
+func (s *DBSettingService) UpsertSetting(ctx context.Context, key string, input model.UpdateSettingInput, updatedBy string) (*ent.Setting, error) {
+	if strings.HasPrefix(key, FeatureFlagPrefix) {
+		if _, err := strconv.ParseBool(input.Value); err != nil {
+			return nil, errors.New("feature flag value must be a boolean")
+		}
+	}
+
+	existing, err := s.client.Setting.Query().
+		Where(setting.Key(key)).
+		Only(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+
+	var st *ent.Setting
+	if existing == nil {
+		st, err = s.client.Setting.Create().
+			SetKey(key).
+			SetValue(input.Value).
+			SetDescription(input.Description).
+			SetUpdatedBy(updatedBy).
+			Save(ctx)
+	} else {
+		update := s.client.Setting.UpdateOne(existing).
+			SetValue(input.Value).
+			SetUpdatedBy(updatedBy)
+		if input.Description != "" {
+			update.SetDescription(input.Description)
+		}
+		st, err = update.Save(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save setting: %w", err)
+	}
+
+	// Other instances pick the change up once their cached copy goes stale
+	s.cache.Set(ctx, key, cachedSetting{value: st.Value, found: true})
+	logger.FromContext(ctx).Infof("Setting %s updated by %s", key, updatedBy)
+
+	return st, nil
+}
+
+// DeleteSetting deletes a setting
+func (s *DBSettingService) DeleteSetting(ctx context.Context, key string) error {
+	n, err := s.client.Setting.Delete().
+		Where(setting.Key(key)).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete setting: %w", err)
+	}
+	if n == 0 {
+		return errors.New("setting not found")
+	}
+
+	s.cache.Set(ctx, key, cachedSetting{})
+	return nil
+}
+
+// Warm loads all settings into the cache so first reads do not hit the database
+func (s *DBSettingService) Warm(ctx context.Context) error {
+	settings, err := s.ListSettings(ctx)
+	if err != nil {
+		return err
+	}
+	for _, st := range settings {
+		s.cache.Set(ctx, st.Key, cachedSetting{value: st.Value, found: true})
+	}
+	return nil
+}
+
+// load reads a single setting from the database for the cache
+func (s *DBSettingService) load(ctx context.Context, key string) (cachedSetting, error) {
+	st, err := s.client.Setting.Query().
+		Where(setting.Key(key)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return cachedSetting{}, nil
+		}
+		return cachedSetting{}, fmt.Errorf("failed to load setting %s: %w", key, err)
+	}
+	return cachedSetting{value: st.Value, found: true}, nil
+}
+
+// ClearCache drops all cached settings so the next reads go to the database
+func (s *DBSettingService) ClearCache() {
+	s.cache.Clear()
+}
@@ -0,0 +1,16 @@
+package stats
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+// DefaultDays is the number of days signups are counted over when the filter does not set one
+const DefaultDays = 30
+
+// StatsService defines the interface for the statistics shown to admins
+type StatsService interface {
+	// UserStats summarizes the users visible from ctx, counting signups over the last days
+	UserStats(ctx context.Context, days int) (*model.UserStatsResponse, error)
+}
@@ -0,0 +1,229 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/role"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/orgctx"
+)
+
+// Options configures the statistics service
+type Options struct {
+	// CacheTTL is how long computed statistics are cached in Redis; zero disables the cache
+	CacheTTL time.Duration
+}
+
+// Cache keeps computed statistics in Redis, so dashboards polling them do not repeat the
+// aggregate queries. Get returns an empty string when nothing is cached.
+type Cache struct {
+	Set func(ctx context.Context, key, stats string, expiration time.Duration) error
+	Get func(ctx context.Context, key string) (string, error)
+}
+
+// DBStatsService implements StatsService
+type DBStatsService struct {
+	client  *ent.Client
+	cache   Cache
+	options Options
+}
+
+// NewStatsService creates a new statistics service
+func NewStatsService(client *ent.Client, cache Cache, options Options) StatsService {
+	return &DBStatsService{
+		client:  client,
+		cache:   cache,
+		options: options,
+	}
+}
+
+// UserStats summarizes the users visible from ctx. Statistics are cached per tenant,
+// organization and number of days; cache failures fall back to computing them.
+func (s *DBStatsService) UserStats(ctx context.Context, days int) (*model.UserStatsResponse, error) {
+	if days <= 0 {
+		days = DefaultDays
+	}
+	key := dbtenant.ScopedKey(ctx, fmt.Sprintf("users:%s:%d", orgctx.From(ctx), days))
+
+	if s.options.CacheTTL > 0 {
+		if value, err := s.cache.Get(ctx, key); err != nil {
+			logger.FromContext(ctx).Warnf("Failed to read cached user statistics: %v", err)
+		} else if value != "" {
+			var stats model.UserStatsResponse
+			if err := json.Unmarshal([]byte(value), &stats); err == nil {
+				return &stats, nil
+			}
+		}
+	}
+
+	stats, err := s.userStats(ctx, days, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if s.options.CacheTTL > 0 {
+		value, _ := json.Marshal(stats)
+		if err := s.cache.Set(ctx, key, string(value), s.options.CacheTTL); err != nil {
+			logger.FromContext(ctx).Warnf("Failed to cache user statistics: %v", err)
+		}
+	}
+	return stats, nil
+}
+
+// userStats computes the user statistics as of now
+func (s *DBStatsService) userStats(ctx context.Context, days int, now time.Time) (*model.UserStatsResponse, error) {
+	stats := &model.UserStatsResponse{
+		ByRole:      make(map[string]int),
+		Days:        days,
+		GeneratedAt: now.Format(time.RFC3339),
+	}
+
+	var byActive []struct {
+		Active bool `json:"active"`
+		Count  int  `json:"count"`
+	}
+	err := s.client.User.Query().
+		GroupBy(user.FieldActive).
+		Aggregate(ent.Count()).
+		Scan(ctx, &byActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	for _, row := range byActive {
+		if row.Active {
+			stats.Active = row.Count
+		} else {
+			stats.Inactive = row.Count
+		}
+	}
+	stats.Total = stats.Active + stats.Inactive
+
+	// 角色保存在 JSON 数组中，按已定义的角色逐个计数
+	roles, err := s.client.Role.Query().Select(role.FieldName).Strings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	for _, name := range roles {
+		count, err := s.client.User.Query().
+			Where(func(s *sql.Selector) {
+				s.Where(sqljson.ValueContains(user.FieldRoles, name))
+			}).
+			Count(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count users with role %s: %w", name, err)
+		}
+		stats.ByRole[name] = count
+	}
+
+	if stats.Signups, err = s.signups(ctx, days, now); err != nil {
+		return nil, err
+	}
+	if stats.LastLogin, err = s.lastLogins(ctx, now); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// signups counts the users created on each of the last days, oldest first. Days without
+// signups are included with a zero count.
+func (s *DBStatsService) signups(ctx context.Context, days int, now time.Time) ([]model.DailyCount, error) {
+	today := now.Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -(days - 1))
+
+	var rows []struct {
+		Day   string `json:"day"`
+		Count int    `json:"count"`
+	}
+	err := s.client.User.Query().
+		Where(user.CreatedAtGTE(from)).
+		Modify(func(s *sql.Selector) {
+			s.Select().
+				AppendSelectExprAs(sql.Raw(utcDay(s.Dialect(), s.C(user.FieldCreatedAt))), "day").
+				AppendSelectExprAs(sql.Raw("COUNT(*)"), "count").
+				GroupBy("day")
+		}).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count signups: %w", err)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Day] = row.Count
+	}
+
+	signups := make([]model.DailyCount, 0, days)
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		signups = append(signups, model.DailyCount{Date: date, Count: counts[date]})
+	}
+	return signups, nil
+}
+
+// lastLogins counts the users by the period their last login falls in, in a single query
+func (s *DBStatsService) lastLogins(ctx context.Context, now time.Time) (model.LastLoginDistribution, error) {
+	var dist model.LastLoginDistribution
+	var rows []struct {
+		Period string `json:"period"`
+		Count  int    `json:"count"`
+	}
+	err := s.client.User.Query().
+		Modify(func(s *sql.Selector) {
+			column := s.C(user.FieldLastLogin)
+			period := sql.ExprFunc(func(b *sql.Builder) {
+				b.WriteString("CASE WHEN " + column + " IS NULL THEN 'never'")
+				b.WriteString(" WHEN " + column + " >= ").Arg(now.AddDate(0, 0, -1)).WriteString(" THEN 'day'")
+				b.WriteString(" WHEN " + column + " >= ").Arg(now.AddDate(0, 0, -7)).WriteString(" THEN 'week'")
+				b.WriteString(" WHEN " + column + " >= ").Arg(now.AddDate(0, 0, -30)).WriteString(" THEN 'month'")
+				b.WriteString(" WHEN " + column + " >= ").Arg(now.AddDate(0, 0, -90)).WriteString(" THEN 'quarter'")
+				b.WriteString(" ELSE 'older' END")
+			})
+			s.Select().
+				AppendSelectExprAs(period, "period").
+				AppendSelectExprAs(sql.Raw("COUNT(*)"), "count").
+				GroupBy("period")
+		}).
+		Scan(ctx, &rows)
+	if err != nil {
+		return dist, fmt.Errorf("failed to count last logins: %w", err)
+	}
+
+	for _, row := range rows {
+		switch row.Period {
+		case "day":
+			dist.LastDay = row.Count
+		case "week":
+			dist.LastWeek = row.Count
+		case "month":
+			dist.LastMonth = row.Count
+		case "quarter":
+			dist.LastQuarter = row.Count
+		case "older":
+			dist.Older = row.Count
+		case "never":
+			dist.Never = row.Count
+		}
+	}
+	return dist, nil
+}
+
+// utcDay returns the SQL expression formatting a timestamp column as its UTC day, YYYY-MM-DD
+func utcDay(driver, column string) string {
+	switch driver {
+	case dialect.Postgres:
+		return "to_char(" + column + " AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+	case dialect.MySQL:
+		return "DATE_FORMAT(" + column + ", '%Y-%m-%d')"
+	default:
+		return "strftime('%Y-%m-%d', " + column + ")"
+	}
+}
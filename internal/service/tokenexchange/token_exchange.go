@@ -0,0 +1,28 @@
+package tokenexchange
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+var (
+	// ErrInvalidSubjectToken is returned for external tokens that are not trusted or not valid
+	ErrInvalidSubjectToken = errors.New("invalid subject token")
+	// ErrExchangeDenied is returned when a valid external identity may not be exchanged,
+	// e.g. because it maps to no user or to a deactivated one
+	ErrExchangeDenied = errors.New("token exchange denied")
+)
+
+// TokenExchangeService exchanges identity tokens of trusted external issuers, such as an
+// OIDC provider or an internal service, for token pairs of this API
+type TokenExchangeService interface {
+	Exchange(ctx context.Context, subjectToken string) (*jwt.TokenPair, *ent.User, error)
+}
+
+// IsDenied reports whether err is a rejection the client should see rather than a server error
+func IsDenied(err error) bool {
+	return errors.Is(err, ErrInvalidSubjectToken) || errors.Is(err, ErrExchangeDenied)
+}
@@ -0,0 +1,209 @@
+package tokenexchange
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/dbtx"
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
+	"github.com/hewenyu/gin-pkg/internal/ent/user"
+	"github.com/hewenyu/gin-pkg/pkg/auth/exchange"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/auth/password"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Rule maps the identities of one issuer to local users and roles
+type Rule struct {
+	// Issuer is the Name of the exchange.Issuer the rule applies to
+	Issuer string
+	// EmailClaim names the claim holding the email address of the local user; "email" if empty
+	EmailClaim string
+	// AllowUnverifiedEmail accepts identities without email_verified set to true. Leave it
+	// off unless the issuer only asserts addresses it has verified, or anyone registering
+	// a local user's address with the issuer can take over the local account.
+	AllowUnverifiedEmail bool
+	// CreateUsers creates a local user for an unknown email address instead of rejecting it
+	CreateUsers bool
+	// RoleClaim names the claim holding the external roles or groups. When set, the issued
+	// tokens carry the roles RoleMapping assigns to them instead of the user's stored roles.
+	RoleClaim string
+	// RoleMapping maps lowercased external roles to local roles
+	RoleMapping map[string][]string
+	// DefaultRoles are granted to every identity of the issuer and to users it creates
+	DefaultRoles []string
+	// Scopes narrows the scopes of the issued tokens when set
+	Scopes []string
+}
+
+// DBTokenExchangeService implements TokenExchangeService
+type DBTokenExchangeService struct {
+	client         *ent.Client
+	tokenService   jwt.TokenService
+	verifier       *exchange.Verifier
+	passwordHasher *password.Hasher
+	rules          map[string]Rule
+}
+
+// NewTokenExchangeService creates a new token exchange service. Identities of issuers
+// without a rule are rejected.
+func NewTokenExchangeService(
+	client *ent.Client,
+	tokenService jwt.TokenService,
+	verifier *exchange.Verifier,
+	passwordHasher *password.Hasher,
+	rules []Rule,
+) TokenExchangeService {
+	byIssuer := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		if rule.EmailClaim == "" {
+			rule.EmailClaim = "email"
+		}
+		byIssuer[rule.Issuer] = rule
+	}
+	return &DBTokenExchangeService{
+		client:         client,
+		tokenService:   tokenService,
+		verifier:       verifier,
+		passwordHasher: passwordHasher,
+		rules:          byIssuer,
+	}
+}
+
+// Exchange verifies an external token, finds or creates the local user it maps to and
+// issues a token pair for that user
+func (s *DBTokenExchangeService) Exchange(ctx context.Context, subjectToken string) (*jwt.TokenPair, *ent.User, error) {
+	identity, err := s.verifier.Verify(ctx, subjectToken)
+	if err != nil {
+		logger.FromContext(ctx).Infof("Rejected subject token: %v", err)
+		return nil, nil, ErrInvalidSubjectToken
+	}
+	rule, ok := s.rules[identity.Issuer]
+	if !ok {
+		return nil, nil, ErrInvalidSubjectToken
+	}
+
+	email := schema.NormalizeEmail(identity.StringClaim(rule.EmailClaim))
+	if email == "" {
+		return nil, nil, fmt.Errorf("%w: the subject token carries no %s claim", ErrExchangeDenied, rule.EmailClaim)
+	}
+	if !rule.AllowUnverifiedEmail && identity.Claims["email_verified"] != true {
+		return nil, nil, fmt.Errorf("%w: the email address is not verified", ErrExchangeDenied)
+	}
+
+	var roles []string
+	if rule.RoleClaim != "" {
+		roles = mapRoles(rule, identity.StringsClaim(rule.RoleClaim))
+		if len(roles) == 0 {
+			return nil, nil, fmt.Errorf("%w: no role is mapped to the identity", ErrExchangeDenied)
+		}
+	}
+
+	u, err := s.findOrCreateUser(ctx, identity, email, roles, rule)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("%w: no user with this email address", ErrExchangeDenied)
+		}
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !u.Active {
+		return nil, nil, fmt.Errorf("%w: account is deactivated", ErrExchangeDenied)
+	}
+	if roles == nil {
+		roles = u.Roles
+	}
+
+	tokenPair, err := s.tokenService.GenerateTokenPairWithOptions(ctx, u.ID, u.Email, roles, jwt.TokenOptions{
+		Scopes:                 rule.Scopes,
+		Fingerprint:            jwt.FingerprintFromContext(ctx),
+		Device:                 jwt.DeviceFromContext(ctx),
+		TenantID:               dbtenant.From(ctx),
+		OrgID:                  u.OrgID,
+		PasswordChangeRequired: u.MustChangePassword,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	if _, err := s.db(ctx).User.UpdateOne(u).SetLastLogin(time.Now()).Save(ctx); err != nil {
+		logger.FromContext(ctx).Warnf("Failed to update last login time: %v", err)
+	}
+	logger.FromContext(ctx).Infof("Exchanged token of %s subject %s for user %s", identity.Issuer, identity.Subject, u.ID)
+
+	return tokenPair, u, nil
+}
+
+// findOrCreateUser returns the user with the email address, creating it when the rule
+// allows. Concurrent exchanges of a new identity race to create the user; the loser's
+// insert fails on the unique email, and it looks the user up again to find the winner's.
+func (s *DBTokenExchangeService) findOrCreateUser(ctx context.Context, identity *exchange.Identity, email string, roles []string, rule Rule) (*ent.User, error) {
+	var u *ent.User
+	for attempt := 0; ; attempt++ {
+		err := dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+			var err error
+			u, err = tx.User.Query().Where(user.Email(email)).Only(ctx)
+			if ent.IsNotFound(err) && rule.CreateUsers {
+				u, err = s.createUser(ctx, tx.Client(), identity, email, roles, rule)
+			}
+			return err
+		})
+		if err == nil || attempt > 0 || !ent.IsConstraintError(err) {
+			return u, err
+		}
+	}
+}
+
+// createUser creates the local user of an external identity. The user gets a random
+// password nobody knows, so it can only log in through the issuer until the password is reset.
+func (s *DBTokenExchangeService) createUser(ctx context.Context, client *ent.Client, identity *exchange.Identity, email string, roles []string, rule Rule) (*ent.User, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	hashedPassword, err := s.passwordHasher.Hash(base64.RawURLEncoding.EncodeToString(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if roles == nil {
+		roles = rule.DefaultRoles
+	}
+	create := client.User.Create().
+		SetEmail(email).
+		SetUsername(email).
+		SetPasswordHash(hashedPassword)
+	if len(roles) > 0 {
+		create.SetRoles(roles)
+	}
+	u, err := create.Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	logger.FromContext(ctx).Infof("Created user %s for %s subject %s", u.ID, identity.Issuer, identity.Subject)
+	return u, nil
+}
+
+// db returns the client of the caller's transaction, if any, else that of the service
+func (s *DBTokenExchangeService) db(ctx context.Context) *ent.Client {
+	return dbtx.Client(ctx, s.client)
+}
+
+// mapRoles returns the default roles of a rule plus the local roles mapped to the external ones
+func mapRoles(rule Rule, external []string) []string {
+	roles := slices.Clone(rule.DefaultRoles)
+	for _, name := range external {
+		for _, role := range rule.RoleMapping[strings.ToLower(name)] {
+			if !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
@@ -0,0 +1,41 @@
+package tokenexchange
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/hewenyu/gin-pkg/pkg/auth/exchange"
+)
+
+func TestExchangeRequiresVerifiedEmail(t *testing.T) {
+	verifier, err := exchange.NewVerifier([]exchange.Issuer{{Name: "sso", Issuer: "https://sso.example.com", Secret: "shared-secret"}}, exchange.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The identity is rejected before the user is looked up, so the service needs no database
+	s := NewTokenExchangeService(nil, nil, verifier, nil, []Rule{{Issuer: "sso", CreateUsers: true}})
+
+	for name, verified := range map[string]any{"missing": nil, "false": false, "string": "true"} {
+		t.Run(name, func(t *testing.T) {
+			claims := gojwt.MapClaims{
+				"iss":   "https://sso.example.com",
+				"sub":   "u1",
+				"email": "victim@example.com",
+				"exp":   time.Now().Add(time.Minute).Unix(),
+			}
+			if verified != nil {
+				claims["email_verified"] = verified
+			}
+			token, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, claims).SignedString([]byte("shared-secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := s.Exchange(context.Background(), token); !errors.Is(err, ErrExchangeDenied) {
+				t.Errorf("Exchange() error = %v, want %v", err, ErrExchangeDenied)
+			}
+		})
+	}
+}
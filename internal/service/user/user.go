@@ -2,20 +2,53 @@ package user
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/hewenyu/gin-pkg/internal/ent"
 	"github.com/hewenyu/gin-pkg/internal/model"
 	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
 )
 
+var (
+	// ErrEmailExists is returned when creating a user whose email is taken, including by a
+	// deleted user that has not been purged
+	ErrEmailExists = errors.New("user with this email already exists")
+	// ErrUsernameExists is returned when creating a user whose username is taken
+	ErrUsernameExists = errors.New("user with this username already exists")
+	// ErrUnknownRole is returned when giving a user a role that does not exist
+	ErrUnknownRole = errors.New("unknown role")
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrLastAdmin is returned when taking the admin role from the last active admin, or
+	// deactivating or deleting them
+	ErrLastAdmin = errors.New("the last active admin cannot lose the admin role, be deactivated or be deleted")
+	// ErrSelfLockout is returned when callers deactivate or delete their own account
+	ErrSelfLockout = errors.New("cannot deactivate or delete yourself")
+	// ErrAdminRequired is returned when a caller who is not an admin grants or revokes the
+	// admin role
+	ErrAdminRequired = errors.New("only admins can grant or revoke the admin role")
+)
+
+// DefaultPageSize is the number of users listed when the filter does not set a page size
+const DefaultPageSize = 20
+
 // UserService defines the interface for user operations
 type UserService interface {
 	CreateUser(ctx context.Context, input model.CreateUserInput) (*ent.User, error)
 	GetUserByID(ctx context.Context, id string) (*ent.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*ent.User, error)
+	ListUsers(ctx context.Context, filter model.UserFilter) ([]*ent.User, int, error)
 	UpdateUser(ctx context.Context, id string, input model.UpdateUserInput) (*ent.User, error)
+	ChangeRoles(ctx context.Context, id string, grant, revoke []string) (*ent.User, error)
+	SetActive(ctx context.Context, id string, active bool) (*ent.User, error)
+	SetMustChangePassword(ctx context.Context, id string, required bool) (*ent.User, error)
 	DeleteUser(ctx context.Context, id string) error
-	Login(ctx context.Context, email, password string) (*jwt.TokenPair, *ent.User, error)
+	RestoreUser(ctx context.Context, id string) (*ent.User, error)
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int, error)
+	Login(ctx context.Context, email, password string, rememberMe bool) (*jwt.TokenPair, *ent.User, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*jwt.TokenPair, error)
+	Logout(ctx context.Context, userID, accessTokenID string, accessExpiresAt time.Time, refreshToken string) error
 	UpdatePassword(ctx context.Context, userID string, currentPassword, newPassword string) error
+	Impersonate(ctx context.Context, adminID, targetID string) (*jwt.TokenPair, *ent.User, error)
 }
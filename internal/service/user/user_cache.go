@@ -0,0 +1,225 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/util/cache"
+)
+
+// CacheOptions configures the user cache
+type CacheOptions struct {
+	// TTL is how long a user is cached. Changes made through ent drop the cached user right
+	// away, so TTL only bounds how long a change made around a concurrent load goes unseen.
+	TTL time.Duration
+}
+
+// CachedUserService serves GetUserByID and GetUserByEmail from a read-through cache and
+// passes every other call to the wrapped service. Cached users carry no password hash or
+// history and cannot run ent queries themselves; Login and UpdatePassword look users up
+// through the wrapped service, so they always read the database.
+type CachedUserService struct {
+	UserService
+	cache   cache.Cache
+	options CacheOptions
+	metrics *cacheMetrics
+}
+
+// NewCachedUserService wraps service with a cache of its user lookups. Users are cached by ID,
+// and the ID of the user with an email under "email:" and the email. The IDs and emails of
+// tenant users are prefixed with the tenant's schema, see dbtenant.ScopedKey. It registers a
+// hook on client that drops the cached users changed or deleted by any mutation, whichever
+// service makes it.
+func NewCachedUserService(service UserService, client *ent.Client, users cache.Cache, options CacheOptions) UserService {
+	s := &CachedUserService{
+		UserService: service,
+		cache:       users,
+		options:     options,
+		metrics:     newCacheMetrics("users"),
+	}
+	client.User.Use(s.invalidationHook)
+	return s
+}
+
+// GetUserByID gets a user by ID, from the cache when possible. Concurrent lookups of a user
+// that is not cached share one query.
+func (s *CachedUserService) GetUserByID(ctx context.Context, id string) (*ent.User, error) {
+	loaded := false
+	u, err := cache.GetOrLoadJSON(ctx, s.cache, dbtenant.ScopedKey(ctx, id), s.options.TTL, func(ctx context.Context, _ string) (*ent.User, error) {
+		loaded = true
+		// Cached users are shared by every organization, so the scope is applied below
+		u, err := s.UserService.GetUserByID(schema.SkipOrgScope(ctx), id)
+		if err == nil {
+			s.storeEmail(ctx, u)
+		}
+		return u, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if loaded {
+		s.metrics.misses.Add(1)
+	} else {
+		s.metrics.hits.Add(1)
+	}
+	// 缓存不经过 ent 拦截器，需自行应用组织范围；查询数据库以返回相同的错误
+	if orgID := schema.ScopedOrg(ctx); orgID != "" && u.OrgID != orgID {
+		return s.UserService.GetUserByID(ctx, id)
+	}
+	return u, nil
+}
+
+// GetUserByEmail gets a user by email, from the cache when possible
+func (s *CachedUserService) GetUserByEmail(ctx context.Context, email string) (*ent.User, error) {
+	email = schema.NormalizeEmail(email)
+
+	userID, err := s.cache.Get(ctx, emailKey(ctx, email))
+	if err != nil && !errors.Is(err, cache.ErrNotFound) {
+		s.metrics.errors.Add(1)
+		logger.FromContext(ctx).Warnf("Failed to read cached user ID: %v", err)
+	}
+	if len(userID) > 0 {
+		// The email may have moved to another user since its ID was cached
+		if u := s.cachedUser(ctx, string(userID)); u != nil && u.Email == email {
+			s.metrics.hits.Add(1)
+			return u, nil
+		}
+	}
+	s.metrics.misses.Add(1)
+
+	u, err := s.UserService.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	s.store(ctx, u)
+	return u, nil
+}
+
+// cachedUser returns the cached user with an ID, or nil if it is not cached or is outside the
+// organization ctx is scoped to
+func (s *CachedUserService) cachedUser(ctx context.Context, id string) *ent.User {
+	value, err := s.cache.Get(ctx, dbtenant.ScopedKey(ctx, id))
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.metrics.errors.Add(1)
+		logger.FromContext(ctx).Warnf("Failed to read cached user: %v", err)
+		return nil
+	}
+
+	var u ent.User
+	if err := json.Unmarshal(value, &u); err != nil {
+		return nil
+	}
+	// 缓存不经过 ent 拦截器，需自行应用组织范围
+	if orgID := schema.ScopedOrg(ctx); orgID != "" && u.OrgID != orgID {
+		return nil
+	}
+	return &u
+}
+
+// store caches a user loaded from the database. Failures only cost a later cache miss.
+func (s *CachedUserService) store(ctx context.Context, u *ent.User) {
+	value, err := json.Marshal(u)
+	if err != nil {
+		return
+	}
+	if err := s.cache.Set(ctx, dbtenant.ScopedKey(ctx, u.ID), value, s.options.TTL); err != nil {
+		s.metrics.errors.Add(1)
+		logger.FromContext(ctx).Warnf("Failed to cache user %s: %v", u.ID, err)
+		return
+	}
+	s.storeEmail(ctx, u)
+}
+
+// storeEmail caches the ID of a user under its email
+func (s *CachedUserService) storeEmail(ctx context.Context, u *ent.User) {
+	if err := s.cache.Set(ctx, emailKey(ctx, u.Email), []byte(u.ID), s.options.TTL); err != nil {
+		s.metrics.errors.Add(1)
+		logger.FromContext(ctx).Warnf("Failed to cache the ID of user %s: %v", u.ID, err)
+	}
+}
+
+// invalidationHook drops the cached users a mutation changes once it succeeds. The email
+// index is left alone: lookups check the email of the user it points to.
+func (s *CachedUserService) invalidationHook(next ent.Mutator) ent.Mutator {
+	return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+		um, ok := m.(*ent.UserMutation)
+		if !ok || m.Op().Is(ent.OpCreate) {
+			return next.Mutate(ctx, m)
+		}
+
+		// The IDs must be known before the mutation, as deletes remove the rows
+		ids, err := um.IDs(ctx)
+		if err != nil {
+			s.metrics.errors.Add(1)
+			logger.FromContext(ctx).Warnf("Failed to find the cached users a mutation changes: %v", err)
+		}
+
+		value, err := next.Mutate(ctx, m)
+		if err != nil {
+			return value, err
+		}
+		for _, id := range ids {
+			if err := s.cache.Delete(ctx, dbtenant.ScopedKey(ctx, id)); err != nil {
+				s.metrics.errors.Add(1)
+				logger.FromContext(ctx).Warnf("Failed to drop cached user %s: %v", id, err)
+				continue
+			}
+			s.metrics.invalidations.Add(1)
+		}
+		return value, nil
+	})
+}
+
+// emailKey returns the key caching the ID of the user with an email
+func emailKey(ctx context.Context, email string) string {
+	return "email:" + dbtenant.ScopedKey(ctx, email)
+}
+
+// cacheMetrics are the counters published for the user cache
+type cacheMetrics struct {
+	hits          *expvar.Int
+	misses        *expvar.Int
+	invalidations *expvar.Int
+	errors        *expvar.Int
+}
+
+// newCacheMetrics publishes the metrics of a cache as the expvar map "cache.<name>"
+func newCacheMetrics(name string) *cacheMetrics {
+	m := &cacheMetrics{
+		hits:          new(expvar.Int),
+		misses:        new(expvar.Int),
+		invalidations: new(expvar.Int),
+		errors:        new(expvar.Int),
+	}
+
+	vars := new(expvar.Map).Init()
+	vars.Set("hits", m.hits)
+	vars.Set("misses", m.misses)
+	vars.Set("invalidations", m.invalidations)
+	vars.Set("errors", m.errors)
+	vars.Set("hit_rate", expvar.Func(func() any {
+		hits, misses := m.hits.Value(), m.misses.Value()
+		if hits+misses == 0 {
+			return 0.0
+		}
+		return float64(hits) / float64(hits+misses)
+	}))
+
+	// expvar panics on duplicate names; a cache re-created under the same name replaces the old one
+	if existing, ok := expvar.Get("cache." + name).(*expvar.Map); ok {
+		vars.Do(func(kv expvar.KeyValue) { existing.Set(kv.Key, kv.Value) })
+	} else {
+		expvar.Publish("cache."+name, vars)
+	}
+	return m
+}
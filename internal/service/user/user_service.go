@@ -4,75 +4,140 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"github.com/hewenyu/gin-pkg/internal/dbtx"
 	"github.com/hewenyu/gin-pkg/internal/ent"
+	"github.com/hewenyu/gin-pkg/internal/ent/role"
+	"github.com/hewenyu/gin-pkg/internal/ent/schema"
 	"github.com/hewenyu/gin-pkg/internal/ent/user"
 	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/internal/service/rbac"
+	"github.com/hewenyu/gin-pkg/pkg/actorctx"
 	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/hewenyu/gin-pkg/pkg/auth/password"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
 )
 
 // DefaultUserService implements UserService
 type DBUserService struct {
-	client       *ent.Client
-	tokenService jwt.TokenService
+	client         *ent.Client
+	tokenService   jwt.TokenService
+	passwordPolicy password.Policy
+	passwordHasher *password.Hasher
 }
 
 // NewUserService creates a new user service
-func NewUserService(client *ent.Client, tokenService jwt.TokenService) UserService {
+func NewUserService(client *ent.Client, tokenService jwt.TokenService, passwordPolicy password.Policy, passwordHasher *password.Hasher) UserService {
 	return &DBUserService{
-		client:       client,
-		tokenService: tokenService,
+		client:         client,
+		tokenService:   tokenService,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
 	}
 }
 
-// CreateUser creates a new user
+// CreateUser creates a new user. The uniqueness checks and the insert run in one
+// transaction; a concurrent registration that slips past the checks is caught by the
+// unique constraints on email and username and reported the same way. Only admins can
+// create admins.
 func (s *DBUserService) CreateUser(ctx context.Context, input model.CreateUserInput) (*ent.User, error) {
-	// Check if user with the same email already exists
-	exists, err := s.client.User.Query().Where(user.Email(input.Email)).Exist(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check for existing user: %w", err)
-	}
-	if exists {
-		return nil, errors.New("user with this email already exists")
+	if err := checkAdminGrant(ctx, nil, input.Roles); err != nil {
+		return nil, err
 	}
 
-	// Check if user with the same username already exists
-	exists, err = s.client.User.Query().Where(user.Username(input.Username)).Exist(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check for existing user: %w", err)
-	}
-	if exists {
-		return nil, errors.New("user with this username already exists")
+	// Enforce the password policy
+	if err := s.passwordPolicy.Validate(input.Password); err != nil {
+		return nil, err
 	}
 
-	// Hash the password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	input.Email = schema.NormalizeEmail(input.Email)
+
+	// Hash the password before the transaction, so it does not stay open while hashing
+	hashedPassword, err := s.passwordHasher.Hash(input.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Create the user
-	newUser, err := s.client.User.Create().
-		SetEmail(input.Email).
-		SetUsername(input.Username).
-		SetPasswordHash(string(hashedPassword)).
-		SetRole(input.Role).
-		Save(ctx)
+	// Deleted users keep their email and username until they are purged, so they can be
+	// restored. Both are unique across organizations, since users log in without naming
+	// their organization.
+	uniqueCtx := schema.SkipOrgScope(schema.SkipSoftDelete(ctx))
+	var newUser *ent.User
+	err = dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		// Check if user with the same email already exists
+		exists, err := tx.User.Query().Where(user.Email(input.Email)).Exist(uniqueCtx)
+		if err != nil {
+			return fmt.Errorf("failed to check for existing user: %w", err)
+		}
+		if exists {
+			return ErrEmailExists
+		}
+
+		// Check if user with the same username already exists
+		exists, err = tx.User.Query().Where(user.Username(input.Username)).Exist(uniqueCtx)
+		if err != nil {
+			return fmt.Errorf("failed to check for existing user: %w", err)
+		}
+		if exists {
+			return ErrUsernameExists
+		}
+
+		// Roles must exist, or the user would get no permissions from them
+		if err := checkRoles(ctx, tx.Client(), input.Roles); err != nil {
+			return err
+		}
+
+		// Create the user
+		create := tx.User.Create().
+			SetEmail(input.Email).
+			SetUsername(input.Username).
+			SetPasswordHash(hashedPassword).
+			SetMustChangePassword(input.MustChangePassword)
+		if len(input.Roles) > 0 {
+			create.SetRoles(input.Roles)
+		}
+		newUser, err = create.Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+		if ent.IsConstraintError(err) {
+			return nil, s.duplicateUserError(uniqueCtx, input.Email)
+		}
+		return nil, err
 	}
 
 	return newUser, nil
 }
 
+// duplicateUserError tells which unique field a user that failed to insert clashed on. The
+// constraint error names it in a driver-specific way, so the email is looked up instead,
+// outside the caller's transaction, which the failed insert aborts on PostgreSQL.
+func (s *DBUserService) duplicateUserError(ctx context.Context, email string) error {
+	exists, err := s.client.User.Query().Where(user.Email(email)).Exist(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing user: %w", err)
+	}
+	if exists {
+		return ErrEmailExists
+	}
+	return ErrUsernameExists
+}
+
 // GetUserByID gets a user by ID
 func (s *DBUserService) GetUserByID(ctx context.Context, id string) (*ent.User, error) {
-	user, err := s.client.User.Get(ctx, id)
+	user, err := s.db(ctx).User.Get(ctx, id)
 	if err != nil {
 		if ent.IsNotFound(err) {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -81,34 +146,85 @@ func (s *DBUserService) GetUserByID(ctx context.Context, id string) (*ent.User,
 
 // GetUserByEmail gets a user by email
 func (s *DBUserService) GetUserByEmail(ctx context.Context, email string) (*ent.User, error) {
-	user, err := s.client.User.Query().Where(user.Email(email)).Only(ctx)
+	user, err := s.db(ctx).User.Query().Where(user.Email(schema.NormalizeEmail(email))).Only(ctx)
 	if err != nil {
 		if ent.IsNotFound(err) {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	return user, nil
 }
 
+// ListUsers returns a page of the users matching the filter, oldest first, and the total
+// number of matches
+func (s *DBUserService) ListUsers(ctx context.Context, filter model.UserFilter) ([]*ent.User, int, error) {
+	query := s.db(ctx).User.Query()
+	if filter.Email != "" {
+		query.Where(user.Email(schema.NormalizeEmail(filter.Email)))
+	}
+	if filter.Role != "" {
+		query.Where(func(s *sql.Selector) {
+			s.Where(sqljson.ValueContains(user.FieldRoles, filter.Role))
+		})
+	}
+	if filter.Active != nil {
+		query.Where(user.Active(*filter.Active))
+	}
+	if filter.Phone != "" {
+		query.Where(user.PhoneIn(schema.EncryptedValues("User", user.FieldPhone, strings.TrimSpace(filter.Phone))...))
+	}
+	if filter.PhoneVerified != nil {
+		query.Where(user.PhoneVerified(*filter.PhoneVerified))
+	}
+
+	total, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	page, pageSize := Pagination(filter)
+	users, err := query.
+		Order(ent.Asc(user.FieldCreatedAt), ent.Asc(user.FieldID)).
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, total, nil
+}
+
+// Pagination returns the page and page size of the filter with defaults applied
+func Pagination(filter model.UserFilter) (int, int) {
+	page, pageSize := filter.Page, filter.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	return page, pageSize
+}
+
 // UpdateUser updates a user
 func (s *DBUserService) UpdateUser(ctx context.Context, id string, input model.UpdateUserInput) (*ent.User, error) {
 	// Get the user
-	userToUpdate, err := s.client.User.Get(ctx, id)
+	userToUpdate, err := s.db(ctx).User.Get(ctx, id)
 	if err != nil {
 		if ent.IsNotFound(err) {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	// Build the update query
-	updateQuery := s.client.User.UpdateOne(userToUpdate)
+	updateQuery := s.db(ctx).User.UpdateOne(userToUpdate)
 
 	if input.Username != "" {
 		// Check if username is already taken
 		if input.Username != userToUpdate.Username {
-			exists, err := s.client.User.Query().
+			exists, err := s.db(ctx).User.Query().
 				Where(user.Username(input.Username)).
 				Exist(ctx)
 			if err != nil {
@@ -125,12 +241,41 @@ func (s *DBUserService) UpdateUser(ctx context.Context, id string, input model.U
 		updateQuery = updateQuery.SetAvatarURL(*input.AvatarURL)
 	}
 
-	if input.Active != nil {
-		updateQuery = updateQuery.SetActive(*input.Active)
+	if input.Phone != nil {
+		phone := strings.TrimSpace(*input.Phone)
+		if phone == "" {
+			updateQuery = updateQuery.ClearPhone().SetPhoneVerified(false)
+		} else if userToUpdate.Phone == nil || *userToUpdate.Phone != phone {
+			if !schema.PhonePattern.MatchString(phone) {
+				return nil, errors.New("phone number must be in E.164 format, e.g. +14155552671")
+			}
+			// Check if phone number is already taken
+			exists, err := s.db(ctx).User.Query().
+				Where(user.PhoneIn(schema.EncryptedValues("User", user.FieldPhone, phone)...)).
+				Exist(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check for existing phone number: %w", err)
+			}
+			if exists {
+				return nil, errors.New("phone number is already in use")
+			}
+			// 新号码需要重新验证
+			updateQuery = updateQuery.SetPhone(phone).SetPhoneVerified(false)
+		}
 	}
 
-	if input.Role != "" {
-		updateQuery = updateQuery.SetRole(input.Role)
+	rolesChanged := len(input.Roles) > 0 && !slices.Equal(input.Roles, userToUpdate.Roles)
+	if rolesChanged {
+		if err := checkAdminGrant(ctx, userToUpdate.Roles, input.Roles); err != nil {
+			return nil, err
+		}
+		if err := checkRoles(ctx, s.db(ctx), input.Roles); err != nil {
+			return nil, err
+		}
+		if err := keepAdmin(ctx, s.db(ctx), userToUpdate, input.Roles); err != nil {
+			return nil, err
+		}
+		updateQuery = updateQuery.SetRoles(input.Roles)
 	}
 
 	// Execute the update
@@ -139,23 +284,206 @@ func (s *DBUserService) UpdateUser(ctx context.Context, id string, input model.U
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
+	// Tokens carry the roles they were issued with
+	if rolesChanged {
+		if err := s.tokenService.RevokeUserTokens(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to revoke user tokens: %w", err)
+		}
+	}
+
 	return updatedUser, nil
 }
 
-// DeleteUser deletes a user
-func (s *DBUserService) DeleteUser(ctx context.Context, id string) error {
-	err := s.client.User.DeleteOneID(id).Exec(ctx)
+// ChangeRoles grants and revokes roles of a user in one step, then revokes the user's tokens,
+// which carry the old roles. Granted roles must exist, only admins can grant or revoke the
+// admin role, and it cannot be taken from the last active admin.
+func (s *DBUserService) ChangeRoles(ctx context.Context, id string, grant, revoke []string) (*ent.User, error) {
+	for _, name := range grant {
+		if slices.Contains(revoke, name) {
+			return nil, fmt.Errorf("role %s cannot be both granted and revoked", name)
+		}
+	}
+
+	var (
+		u       *ent.User
+		roles   []string
+		changed bool
+	)
+	err := dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		var err error
+		u, err = tx.User.Get(ctx, id)
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if err := checkRoles(ctx, tx.Client(), grant); err != nil {
+			return err
+		}
+
+		roles = slices.DeleteFunc(slices.Clone(u.Roles), func(name string) bool {
+			return slices.Contains(revoke, name)
+		})
+		for _, name := range grant {
+			if !slices.Contains(roles, name) {
+				roles = append(roles, name)
+			}
+		}
+		if slices.Equal(roles, u.Roles) {
+			return nil
+		}
+		if err := checkAdminGrant(ctx, u.Roles, roles); err != nil {
+			return err
+		}
+		if err := keepAdmin(ctx, tx.Client(), u, roles); err != nil {
+			return err
+		}
+
+		u, err = tx.User.UpdateOne(u).SetRoles(roles).Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update roles: %w", err)
+		}
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		// Nothing changes, so the user keeps their tokens
+		return u, nil
+	}
+
+	if err := s.tokenService.RevokeUserTokens(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	logger.FromContext(ctx).Infof("Roles of user %s changed to %v", id, roles)
+	return u, nil
+}
+
+// SetActive activates or deactivates a user and revokes every token issued to it so far.
+// Deactivated users cannot log in; revoking their tokens also ends the sessions they have.
+// Callers cannot deactivate themselves, and the last active admin cannot be deactivated.
+func (s *DBUserService) SetActive(ctx context.Context, id string, active bool) (*ent.User, error) {
+	if !active && id == actorctx.From(ctx) {
+		return nil, ErrSelfLockout
+	}
+
+	var u *ent.User
+	err := dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		var err error
+		u, err = tx.User.Get(ctx, id)
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if !active {
+			if err := keepAdmin(ctx, tx.Client(), u, nil); err != nil {
+				return err
+			}
+		}
+		u, err = tx.User.UpdateOne(u).SetActive(active).Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenService.RevokeUserTokens(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return u, nil
+}
+
+// SetMustChangePassword sets or clears the requirement for a user to change their password.
+// Setting it revokes the user's tokens, so the next login issues tokens that only allow
+// changing the password. Changing the password clears it.
+func (s *DBUserService) SetMustChangePassword(ctx context.Context, id string, required bool) (*ent.User, error) {
+	u, err := s.db(ctx).User.UpdateOneID(id).SetMustChangePassword(required).Save(ctx)
 	if err != nil {
 		if ent.IsNotFound(err) {
-			return errors.New("user not found")
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	if required {
+		if err := s.tokenService.RevokeUserTokens(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to revoke user tokens: %w", err)
 		}
-		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return u, nil
+}
+
+// DeleteUser soft-deletes a user and revokes its tokens. The user disappears from queries
+// but can be restored until PurgeDeletedUsers removes it. Callers cannot delete themselves,
+// and the last active admin cannot be deleted.
+func (s *DBUserService) DeleteUser(ctx context.Context, id string) error {
+	if id == actorctx.From(ctx) {
+		return ErrSelfLockout
+	}
+
+	err := dbtx.WithTx(ctx, s.client, func(ctx context.Context, tx *ent.Tx) error {
+		u, err := tx.User.Get(ctx, id)
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if err := keepAdmin(ctx, tx.Client(), u, nil); err != nil {
+			return err
+		}
+		if err := tx.User.DeleteOne(u).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.tokenService.RevokeUserTokens(ctx, id); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to revoke tokens of deleted user %s: %v", id, err)
 	}
 	return nil
 }
 
+// RestoreUser undoes the soft deletion of a user. Tokens issued before the deletion stay revoked.
+func (s *DBUserService) RestoreUser(ctx context.Context, id string) (*ent.User, error) {
+	u, err := s.db(ctx).User.Query().
+		Where(user.ID(id), user.DeletedAtNotNil()).
+		Only(schema.SkipSoftDelete(ctx))
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errors.New("deleted user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	u, err = s.db(ctx).User.UpdateOne(u).ClearDeletedAt().Save(schema.SkipSoftDelete(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore user: %w", err)
+	}
+	return u, nil
+}
+
+// PurgeDeletedUsers permanently removes the users soft-deleted before the given time and
+// returns how many were removed
+func (s *DBUserService) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int, error) {
+	n, err := s.db(ctx).User.Delete().
+		Where(user.DeletedAtLT(deletedBefore)).
+		Exec(schema.SkipSoftDelete(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+	return n, nil
+}
+
 // Login authenticates a user and returns JWT tokens
-func (s *DBUserService) Login(ctx context.Context, email, password string) (*jwt.TokenPair, *ent.User, error) {
+func (s *DBUserService) Login(ctx context.Context, email, password string, rememberMe bool) (*jwt.TokenPair, *ent.User, error) {
 	// Get the user by email
 	user, err := s.GetUserByEmail(ctx, email)
 	if err != nil {
@@ -168,19 +496,33 @@ func (s *DBUserService) Login(ctx context.Context, email, password string) (*jwt
 	}
 
 	// Verify the password
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
-	if err != nil {
+	valid, err := s.passwordHasher.Verify(user.PasswordHash, password)
+	if err != nil || !valid {
 		return nil, nil, errors.New("invalid credentials")
 	}
 
+	// Upgrade hashes produced by an older algorithm or weaker parameters while the plaintext is at hand
+	if s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		if err := s.rehashPassword(ctx, user, password); err != nil {
+			logger.FromContext(ctx).Warnf("Failed to upgrade password hash for user %s: %v", user.ID, err)
+		}
+	}
+
 	// Generate JWT tokens
-	tokenPair, err := s.tokenService.GenerateTokenPair(user.ID, user.Email, user.Role)
+	tokenPair, err := s.tokenService.GenerateTokenPairWithOptions(ctx, user.ID, user.Email, user.Roles, jwt.TokenOptions{
+		RememberMe:             rememberMe,
+		Fingerprint:            jwt.FingerprintFromContext(ctx),
+		Device:                 jwt.DeviceFromContext(ctx),
+		TenantID:               dbtenant.From(ctx),
+		OrgID:                  user.OrgID,
+		PasswordChangeRequired: user.MustChangePassword,
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
 	// Update last login time
-	_, err = s.client.User.UpdateOne(user).
+	_, err = s.db(ctx).User.UpdateOne(user).
 		SetLastLogin(time.Now()).
 		Save(ctx)
 	if err != nil {
@@ -192,9 +534,66 @@ func (s *DBUserService) Login(ctx context.Context, email, password string) (*jwt
 	return tokenPair, user, nil
 }
 
+// Impersonate issues a short-lived token pair that lets an admin act as another user.
+// Admin accounts cannot be impersonated; callers already impersonating someone are
+// rejected by the handler, so impersonations cannot be chained.
+func (s *DBUserService) Impersonate(ctx context.Context, adminID, targetID string) (*jwt.TokenPair, *ent.User, error) {
+	if adminID == targetID {
+		return nil, nil, errors.New("cannot impersonate yourself")
+	}
+
+	target, err := s.GetUserByID(ctx, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if slices.Contains(target.Roles, rbac.RoleAdmin) {
+		return nil, nil, errors.New("cannot impersonate an admin")
+	}
+	if !target.Active {
+		return nil, nil, errors.New("account is deactivated")
+	}
+
+	tokenPair, err := s.tokenService.GenerateTokenPairWithOptions(ctx, target.ID, target.Email, target.Roles, jwt.TokenOptions{
+		ImpersonatedBy: adminID,
+		Fingerprint:    jwt.FingerprintFromContext(ctx),
+		Device:         jwt.DeviceFromContext(ctx),
+		TenantID:       dbtenant.From(ctx),
+		OrgID:          target.OrgID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	logger.FromContext(ctx).Warnw("impersonation started", "impersonated_by", adminID, "user_id", target.ID)
+	return tokenPair, target, nil
+}
+
 // RefreshToken refreshes an access token using a refresh token
 func (s *DBUserService) RefreshToken(ctx context.Context, refreshToken string) (*jwt.TokenPair, error) {
-	return s.tokenService.RefreshTokens(refreshToken)
+	return s.tokenService.RefreshTokens(ctx, refreshToken)
+}
+
+// Logout revokes the access token of the current session and, when given, its refresh token
+func (s *DBUserService) Logout(ctx context.Context, userID, accessTokenID string, accessExpiresAt time.Time, refreshToken string) error {
+	if err := s.tokenService.BlacklistToken(ctx, accessTokenID, time.Until(accessExpiresAt)); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	claims, err := s.tokenService.ValidateToken(ctx, refreshToken, jwt.RefreshToken)
+	if err != nil {
+		// Expired or already revoked, nothing left to do
+		return nil
+	}
+	if claims.UserID != userID {
+		return errors.New("refresh token belongs to another user")
+	}
+	if err := s.tokenService.RevokeRefreshToken(ctx, claims, jwt.RevokedLogout); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
 }
 
 // UpdatePassword updates a user's password
@@ -206,24 +605,142 @@ func (s *DBUserService) UpdatePassword(ctx context.Context, userID string, curre
 	}
 
 	// Verify the current password
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword))
-	if err != nil {
+	valid, err := s.passwordHasher.Verify(user.PasswordHash, currentPassword)
+	if err != nil || !valid {
 		return errors.New("invalid current password")
 	}
 
+	return s.setPassword(ctx, user, newPassword)
+}
+
+// setPassword validates and stores a new password, keeping the password history up to date.
+// Every flow that changes a password must go through it so reuse is rejected consistently.
+func (s *DBUserService) setPassword(ctx context.Context, u *ent.User, newPassword string) error {
+	// Enforce the password policy
+	if err := s.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	// Reject recently used passwords
+	history := s.recentPasswordHashes(u)
+	for _, hash := range history {
+		if reused, err := s.passwordHasher.Verify(hash, newPassword); err == nil && reused {
+			return s.passwordPolicy.ReusedPasswordError()
+		}
+	}
+
 	// Hash the new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// Update the password and push the old hash onto the history
+	update := s.db(ctx).User.UpdateOne(u).
+		SetPasswordHash(hashedPassword).
+		SetMustChangePassword(false)
+	if s.passwordPolicy.HistorySize > 1 {
+		update.SetPasswordHistory(history[:min(len(history), s.passwordPolicy.HistorySize-1)])
+	} else {
+		update.ClearPasswordHistory()
+	}
+	if _, err := update.Save(ctx); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// 强制修改密码后，吊销仍带有修改密码标记的令牌，用户以新密码重新登录
+	if u.MustChangePassword {
+		if err := s.tokenService.RevokeUserTokens(ctx, u.ID); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to revoke tokens of user %s after a required password change: %v", u.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// recentPasswordHashes returns the current hash followed by the stored history, newest first,
+// limited to the configured history size
+func (s *DBUserService) recentPasswordHashes(u *ent.User) []string {
+	if s.passwordPolicy.HistorySize <= 0 {
+		return nil
+	}
+	hashes := append([]string{u.PasswordHash}, u.PasswordHistory...)
+	return hashes[:min(len(hashes), s.passwordPolicy.HistorySize)]
+}
+
+// rehashPassword re-hashes a verified password with the currently configured algorithm
+func (s *DBUserService) rehashPassword(ctx context.Context, u *ent.User, plaintext string) error {
+	hashedPassword, err := s.passwordHasher.Hash(plaintext)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Update the password
-	_, err = s.client.User.UpdateOne(user).
-		SetPasswordHash(string(hashedPassword)).
+	_, err = s.db(ctx).User.UpdateOne(u).
+		SetPasswordHash(hashedPassword).
 		Save(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to update password: %w", err)
+		return fmt.Errorf("failed to update password hash: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Upgraded password hash for user %s", u.ID)
+	return nil
+}
+
+// checkRoles fails with ErrUnknownRole unless every role exists
+func checkRoles(ctx context.Context, client *ent.Client, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	known, err := client.Role.Query().Where(role.NameIn(roles...)).Select(role.FieldName).Strings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check roles: %w", err)
+	}
+	for _, name := range roles {
+		if !slices.Contains(known, name) {
+			return fmt.Errorf("%w: %s", ErrUnknownRole, name)
+		}
 	}
+	return nil
+}
+
+// checkAdminGrant fails with ErrAdminRequired if a caller who is not an admin would change
+// whether a user holds the admin role, from before to after. Work done without an actor,
+// such as creating the default admin at startup or console commands, is trusted.
+func checkAdminGrant(ctx context.Context, before, after []string) error {
+	if slices.Contains(before, rbac.RoleAdmin) == slices.Contains(after, rbac.RoleAdmin) {
+		return nil
+	}
+	if actorctx.From(ctx) == "" || slices.Contains(actorctx.Roles(ctx), rbac.RoleAdmin) {
+		return nil
+	}
+	return ErrAdminRequired
+}
 
+// keepAdmin fails with ErrLastAdmin if giving u the roles would leave no other active
+// admin. Admins are counted across organizations.
+func keepAdmin(ctx context.Context, client *ent.Client, u *ent.User, roles []string) error {
+	if !slices.Contains(u.Roles, rbac.RoleAdmin) || slices.Contains(roles, rbac.RoleAdmin) || !u.Active {
+		return nil
+	}
+	others, err := client.User.Query().
+		Where(
+			user.IDNEQ(u.ID),
+			user.Active(true),
+			func(s *sql.Selector) {
+				s.Where(sqljson.ValueContains(user.FieldRoles, rbac.RoleAdmin))
+			},
+		).
+		Exist(schema.SkipOrgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if !others {
+		return ErrLastAdmin
+	}
 	return nil
 }
+
+// db returns the client of the caller's transaction, if any, else that of the service
+func (s *DBUserService) db(ctx context.Context) *ent.Client {
+	return dbtx.Client(ctx, s.client)
+}
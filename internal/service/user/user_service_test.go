@@ -0,0 +1,67 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hewenyu/gin-pkg/internal/model"
+	"github.com/hewenyu/gin-pkg/pkg/actorctx"
+)
+
+func TestCheckAdminGrant(t *testing.T) {
+	admin := actorctx.WithRoles(actorctx.With(context.Background(), "a1"), []string{"admin"})
+	manager := actorctx.WithRoles(actorctx.With(context.Background(), "m1"), []string{"user_manager"})
+	service := actorctx.WithRoles(actorctx.With(context.Background(), "service:sa1"), []string{"service"})
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		before, after []string
+		wantErr       error
+	}{
+		{"non-admin grants admin", manager, []string{"user"}, []string{"user", "admin"}, ErrAdminRequired},
+		{"non-admin creates an admin", manager, nil, []string{"admin"}, ErrAdminRequired},
+		{"non-admin revokes admin", manager, []string{"admin"}, []string{"user"}, ErrAdminRequired},
+		{"service account grants admin", service, nil, []string{"admin"}, ErrAdminRequired},
+		{"non-admin changes other roles", manager, []string{"user"}, []string{"auditor"}, nil},
+		{"non-admin keeps admin", manager, []string{"admin"}, []string{"admin", "auditor"}, nil},
+		{"admin grants admin", admin, []string{"user"}, []string{"admin"}, nil},
+		{"admin revokes admin", admin, []string{"admin"}, []string{"user"}, nil},
+		{"no actor grants admin", context.Background(), nil, []string{"admin"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkAdminGrant(tt.ctx, tt.before, tt.after); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkAdminGrant() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateUserRequiresAdmin(t *testing.T) {
+	// The check runs before anything else, so the service needs no database
+	s := &DBUserService{}
+	ctx := actorctx.WithRoles(actorctx.With(context.Background(), "m1"), []string{"user_manager"})
+	_, err := s.CreateUser(ctx, model.CreateUserInput{
+		Email:    "new@example.com",
+		Username: "new",
+		Password: "Secret123!",
+		Roles:    []string{"admin"},
+	})
+	if !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("CreateUser() error = %v, want %v", err, ErrAdminRequired)
+	}
+}
+
+func TestSelfLockout(t *testing.T) {
+	// The check runs before anything else, so the service needs no database
+	s := &DBUserService{}
+	ctx := actorctx.WithRoles(actorctx.With(context.Background(), "a1"), []string{"admin"})
+	if _, err := s.SetActive(ctx, "a1", false); !errors.Is(err, ErrSelfLockout) {
+		t.Errorf("SetActive(self, false) error = %v, want %v", err, ErrSelfLockout)
+	}
+	if err := s.DeleteUser(ctx, "a1"); !errors.Is(err, ErrSelfLockout) {
+		t.Errorf("DeleteUser(self) error = %v, want %v", err, ErrSelfLockout)
+	}
+}
@@ -0,0 +1,74 @@
+package userbulk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/internal/model"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for file formats other than CSV and JSON
+	ErrUnsupportedFormat = errors.New("unsupported format, use csv or json")
+	// ErrImportJobNotFound is returned for unknown or expired import jobs
+	ErrImportJobNotFound = errors.New("import job not found")
+)
+
+// Format is the file format of an import or export
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// DuplicatePolicy decides what happens to an imported row whose email already belongs to a user
+type DuplicatePolicy string
+
+const (
+	// DuplicateSkip leaves the existing user unchanged
+	DuplicateSkip DuplicatePolicy = "skip"
+	// DuplicateUpdate updates the username, roles, active flag and phone of the existing user
+	DuplicateUpdate DuplicatePolicy = "update"
+	// DuplicateError reports the row as failed
+	DuplicateError DuplicatePolicy = "error"
+)
+
+// JobStatus is the state of an asynchronous import
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// ImportJob is an import running in the background. Jobs are kept in memory, so their
+// status is only available from the instance that started them.
+type ImportJob struct {
+	ID         string
+	Status     JobStatus
+	Rows       int
+	CreatedAt  time.Time
+	FinishedAt time.Time
+	Report     *model.ImportUsersReport
+	Error      string
+	// OrgID is the organization the import runs in, empty for unscoped imports
+	OrgID string
+}
+
+// UserBulkService defines the interface for exporting and importing users in bulk
+type UserBulkService interface {
+	// ExportUsers writes every user, oldest first, and returns how many were written
+	ExportUsers(ctx context.Context, w io.Writer, format Format) (int, error)
+	// ParseImport reads the rows of an import file without validating them
+	ParseImport(r io.Reader, format Format) ([]model.ImportUserRow, error)
+	// ImportUsers validates and applies rows one by one; failed rows are listed in the report
+	ImportUsers(ctx context.Context, rows []model.ImportUserRow, onDuplicate DuplicatePolicy) (*model.ImportUsersReport, error)
+	// StartImport runs ImportUsers in the background, in the organization ctx acts in
+	StartImport(ctx context.Context, rows []model.ImportUserRow, onDuplicate DuplicatePolicy) *ImportJob
+	// GetImportJob returns a job; jobs started in another organization are not found
+	GetImportJob(ctx context.Context, id string) (*ImportJob, error)
+}
@@ -0,0 +1,74 @@
+// Package actorctx carries who is acting in a request, the user or service account that
+// ent hooks record as the creator and last modifier of rows, and the roles it acts with.
+package actorctx
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/orgctx"
+)
+
+type (
+	actorContextKey struct{}
+	rolesContextKey struct{}
+)
+
+// With returns a copy of ctx acting as actorID
+func With(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actorID)
+}
+
+// From returns who ctx acts as, or "" when nobody does, e.g. in background jobs. It accepts
+// both request contexts and *gin.Context, where the authenticated user is kept under
+// "userID" and a service account under "serviceAccountID"; service accounts are returned
+// as "service:<id>", as in audit events.
+func From(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if actorID, ok := ctx.Value(actorContextKey{}).(string); ok {
+		return actorID
+	}
+	if userID, _ := ctx.Value("userID").(string); userID != "" {
+		return userID
+	}
+	if accountID, _ := ctx.Value("serviceAccountID").(string); accountID != "" {
+		return "service:" + accountID
+	}
+	return ""
+}
+
+// WithRoles returns a copy of ctx whose actor holds roles
+func WithRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, rolesContextKey{}, roles)
+}
+
+// Roles returns the roles the actor of ctx holds, or nil when nobody acts. Like From, it
+// accepts *gin.Context, where the roles of the authenticated caller are kept under "roles".
+func Roles(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	if roles, ok := ctx.Value(rolesContextKey{}).([]string); ok {
+		return roles
+	}
+	roles, _ := ctx.Value("roles").([]string)
+	return roles
+}
+
+// Detach returns a context for work that outlives the request, such as background jobs,
+// acting as the same actor with the same roles in the same organization and tenant schema as ctx
+func Detach(ctx context.Context) context.Context {
+	detached := orgctx.Detach(ctx)
+	if tenant := dbtenant.From(ctx); tenant != "" {
+		detached = dbtenant.With(detached, tenant)
+	}
+	if roles := Roles(ctx); roles != nil {
+		detached = WithRoles(detached, roles)
+	}
+	if actorID := From(ctx); actorID != "" {
+		return With(detached, actorID)
+	}
+	return detached
+}
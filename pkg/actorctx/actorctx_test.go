@@ -0,0 +1,63 @@
+package actorctx
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/orgctx"
+)
+
+func TestFrom(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginContext := func(keys map[string]any) context.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		for key, value := range keys {
+			c.Set(key, value)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "no actor", ctx: context.Background(), want: ""},
+		{name: "explicit actor", ctx: With(context.Background(), "u1"), want: "u1"},
+		{name: "authenticated user", ctx: ginContext(map[string]any{"userID": "u2"}), want: "u2"},
+		{name: "service account", ctx: ginContext(map[string]any{"serviceAccountID": "sa1"}), want: "service:sa1"},
+		{name: "explicit actor over request", ctx: With(ginContext(map[string]any{"userID": "u2"}), "u1"), want: "u1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := From(tt.ctx); got != tt.want {
+				t.Errorf("From() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetach(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set("userID", "u1")
+	c.Set("roles", []string{"admin"})
+	c.Set(orgctx.Key, "org1")
+	c.Set(dbtenant.Key, "acme")
+
+	ctx := Detach(c)
+	if From(ctx) != "u1" || orgctx.From(ctx) != "org1" {
+		t.Errorf("detached context acts as %q in %q, want u1 in org1", From(ctx), orgctx.From(ctx))
+	}
+	if roles := Roles(ctx); len(roles) != 1 || roles[0] != "admin" {
+		t.Errorf("detached context acts with roles %v, want [admin]", roles)
+	}
+	if tenant := dbtenant.From(ctx); tenant != "acme" {
+		t.Errorf("detached context uses the schema of tenant %q, want acme", tenant)
+	}
+	if _, ok := ctx.Value("userID").(string); ok {
+		t.Error("detached context still carries the request keys")
+	}
+}
@@ -0,0 +1,129 @@
+// Package actiontoken mints and redeems short-lived, single-use tokens authorizing one
+// action, such as verifying an email address, resetting a password or accepting an invite.
+package actiontoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Action names what a token authorizes. A token is only accepted for the action it was minted for.
+type Action string
+
+const (
+	// VerifyEmail confirms that the subject owns an email address
+	VerifyEmail Action = "verify_email"
+	// ResetPassword lets the subject set a new password without the old one
+	ResetPassword Action = "reset_password"
+	// AcceptInvite lets the subject accept an invitation
+	AcceptInvite Action = "accept_invite"
+)
+
+// ErrInvalidToken is returned for unknown, expired, already used and tampered tokens, and
+// for tokens of another action
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Token is what a redeemed token authorizes
+type Token struct {
+	Action Action `json:"action"`
+	// Subject is who the token was minted for, e.g. a user ID or an email address
+	Subject string `json:"subject"`
+	// Data holds whatever else the action needs, e.g. the new email address
+	Data      map[string]string `json:"data,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// Manager mints and redeems action tokens. Tokens are random strings; only the SHA-256
+// hash of the action and token is stored, so a leaked store does not leak usable tokens.
+type Manager struct {
+	store Store
+	// now returns the current time; tests replace it to expire tokens
+	now func() time.Time
+}
+
+// NewManager creates a manager keeping pending tokens in store
+func NewManager(store Store) *Manager {
+	return &Manager{store: store, now: time.Now}
+}
+
+// Mint creates a token authorizing action for subject that can be redeemed once within ttl
+func (m *Manager) Mint(ctx context.Context, action Action, subject string, ttl time.Duration, data map[string]string) (string, error) {
+	if action == "" || subject == "" {
+		return "", errors.New("action and subject are required")
+	}
+	if ttl <= 0 {
+		return "", errors.New("token lifetime must be positive")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	encoded, err := json.Marshal(Token{Action: action, Subject: subject, Data: data, ExpiresAt: m.now().Add(ttl)})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token: %w", err)
+	}
+	if err := m.store.Save(ctx, tokenHash(action, token), encoded, ttl); err != nil {
+		return "", fmt.Errorf("failed to store token: %w", err)
+	}
+	return token, nil
+}
+
+// Redeem validates a token for action and invalidates it, so it cannot be used again even
+// by concurrent requests. Call it once the rest of the request has been validated, e.g. the
+// new password against the policy, so a rejected attempt does not use up the token.
+func (m *Manager) Redeem(ctx context.Context, token string, action Action) (*Token, error) {
+	encoded, err := m.store.Take(ctx, tokenHash(action, token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeem token: %w", err)
+	}
+	return m.decode(encoded, action)
+}
+
+// Peek validates a token for action without invalidating it, e.g. to show a password reset
+// form before the new password is submitted
+func (m *Manager) Peek(ctx context.Context, token string, action Action) (*Token, error) {
+	encoded, err := m.store.Load(ctx, tokenHash(action, token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token: %w", err)
+	}
+	return m.decode(encoded, action)
+}
+
+// Revoke invalidates a token before it is used, e.g. when a newer one is sent
+func (m *Manager) Revoke(ctx context.Context, token string, action Action) error {
+	_, err := m.store.Take(ctx, tokenHash(action, token))
+	return err
+}
+
+// decode checks the stored token. The store expires tokens itself; the expiry is checked
+// again in case its expiration is imprecise.
+func (m *Manager) decode(encoded []byte, action Action) (*Token, error) {
+	if encoded == nil {
+		return nil, ErrInvalidToken
+	}
+	t := &Token{}
+	if err := json.Unmarshal(encoded, t); err != nil {
+		return nil, fmt.Errorf("invalid stored token: %w", err)
+	}
+	if t.Action != action || !m.now().Before(t.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+	return t, nil
+}
+
+// tokenHash returns the key of a token. Hashing the action in means a token looked up for
+// another action is simply not found, instead of being consumed.
+func tokenHash(action Action, token string) string {
+	sum := sha256.Sum256([]byte(string(action) + "\n" + token))
+	return hex.EncodeToString(sum[:])
+}
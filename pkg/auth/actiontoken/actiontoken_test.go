@@ -0,0 +1,80 @@
+package actiontoken
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMintAndRedeem(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	m := NewManager(store)
+	m.now = store.now
+
+	token, err := m.Mint(context.Background(), ResetPassword, "u1", 15*time.Minute, map[string]string{"email": "a@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他操作无法使用该令牌，也不会把它消耗掉
+	if _, err := m.Redeem(context.Background(), token, VerifyEmail); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token redeemed for another action: %v", err)
+	}
+	if peeked, err := m.Peek(context.Background(), token, ResetPassword); err != nil || peeked.Subject != "u1" {
+		t.Fatalf("Peek = %+v, %v", peeked, err)
+	}
+
+	redeemed, err := m.Redeem(context.Background(), token, ResetPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redeemed.Subject != "u1" || redeemed.Data["email"] != "a@example.com" {
+		t.Errorf("redeemed = %+v", redeemed)
+	}
+	if _, err := m.Redeem(context.Background(), token, ResetPassword); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token redeemed twice: %v", err)
+	}
+
+	expiring, _ := m.Mint(context.Background(), VerifyEmail, "u1", time.Minute, nil)
+	now = now.Add(time.Minute)
+	if _, err := m.Redeem(context.Background(), expiring, VerifyEmail); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expired token redeemed: %v", err)
+	}
+
+	revoked, _ := m.Mint(context.Background(), AcceptInvite, "b@example.com", time.Hour, nil)
+	if err := m.Revoke(context.Background(), revoked, AcceptInvite); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Peek(context.Background(), revoked, AcceptInvite); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("revoked token accepted: %v", err)
+	}
+}
+
+func TestRedeemIsSingleUseUnderConcurrency(t *testing.T) {
+	m := NewManager(NewMemoryStore())
+	token, err := m.Mint(context.Background(), ResetPassword, "u1", time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var redeemed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Redeem(context.Background(), token, ResetPassword); err == nil {
+				redeemed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := redeemed.Load(); n != 1 {
+		t.Errorf("token redeemed %d times, want once", n)
+	}
+}
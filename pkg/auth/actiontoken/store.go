@@ -0,0 +1,119 @@
+package actiontoken
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/util"
+)
+
+// Store keeps pending action tokens by hash
+type Store interface {
+	// Save stores an encoded token until expiration
+	Save(ctx context.Context, tokenHash string, token []byte, expiration time.Duration) error
+	// Load returns an encoded token, or nil if it does not exist or has expired
+	Load(ctx context.Context, tokenHash string) ([]byte, error)
+	// Take returns and deletes an encoded token in one step, so only one caller gets it,
+	// or returns nil if it does not exist or has expired
+	Take(ctx context.Context, tokenHash string) ([]byte, error)
+}
+
+// RedisStore keeps action tokens in Redis, shared by every instance of the service
+type RedisStore struct {
+	client *util.RedisClient
+}
+
+// NewRedisStore creates a store backed by Redis
+func NewRedisStore(client *util.RedisClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Save(ctx context.Context, tokenHash string, token []byte, expiration time.Duration) error {
+	return s.client.StoreActionToken(ctx, tokenHash, string(token), expiration)
+}
+
+func (s *RedisStore) Load(ctx context.Context, tokenHash string) ([]byte, error) {
+	token, err := s.client.GetActionToken(ctx, tokenHash)
+	if err != nil || token == "" {
+		return nil, err
+	}
+	return []byte(token), nil
+}
+
+func (s *RedisStore) Take(ctx context.Context, tokenHash string) ([]byte, error) {
+	token, err := s.client.TakeActionToken(ctx, tokenHash)
+	if err != nil || token == "" {
+		return nil, err
+	}
+	return []byte(token), nil
+}
+
+// MemoryStore keeps action tokens in process memory, for tests and single-instance
+// deployments without Redis. Every token is lost on restart.
+type MemoryStore struct {
+	mu        sync.Mutex
+	tokens    map[string]memoryToken
+	lastPurge time.Time
+	// now returns the current time; tests replace it to expire tokens
+	now func() time.Time
+}
+
+// memoryToken is an encoded token and the time it is forgotten
+type memoryToken struct {
+	token     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tokens: make(map[string]memoryToken),
+		now:    time.Now,
+	}
+}
+
+func (s *MemoryStore) Save(ctx context.Context, tokenHash string, token []byte, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge()
+	s.tokens[tokenHash] = memoryToken{token: token, expiresAt: s.now().Add(expiration)}
+	return nil
+}
+
+func (s *MemoryStore) Load(ctx context.Context, tokenHash string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(tokenHash), nil
+}
+
+func (s *MemoryStore) Take(ctx context.Context, tokenHash string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := s.load(tokenHash)
+	delete(s.tokens, tokenHash)
+	return token, nil
+}
+
+// load returns an unexpired token. Callers hold mu.
+func (s *MemoryStore) load(tokenHash string) []byte {
+	t, ok := s.tokens[tokenHash]
+	if !ok || !s.now().Before(t.expiresAt) {
+		return nil
+	}
+	return t.token
+}
+
+// purge drops expired tokens at most once a minute. Callers hold mu.
+func (s *MemoryStore) purge() {
+	now := s.now()
+	if now.Sub(s.lastPurge) < time.Minute {
+		return
+	}
+	s.lastPurge = now
+	for hash, t := range s.tokens {
+		if !now.Before(t.expiresAt) {
+			delete(s.tokens, hash)
+		}
+	}
+}
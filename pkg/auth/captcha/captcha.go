@@ -0,0 +1,156 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported captcha providers
+const (
+	ProviderReCaptcha = "recaptcha"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+// Verification endpoints of the supported providers
+const (
+	reCaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	hCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+var (
+	// ErrMissingToken is returned when the client did not send a captcha response
+	ErrMissingToken = errors.New("captcha token is required")
+	// ErrVerificationFailed is returned when the provider rejected the captcha response
+	ErrVerificationFailed = errors.New("captcha verification failed")
+)
+
+// Verifier checks a captcha response token produced by the client-side widget
+type Verifier interface {
+	// Verify returns nil if token is a valid, unused captcha response for the client at remoteIP
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// Options configures a siteverify based verifier
+type Options struct {
+	// Secret is the server-side secret key issued by the provider
+	Secret string
+	// MinScore rejects reCAPTCHA v3 responses scoring below it; 0 disables the check
+	MinScore float64
+	// Timeout bounds a single verification request
+	Timeout time.Duration
+	// VerifyURL overrides the provider's verification endpoint, e.g. for tests
+	VerifyURL string
+}
+
+// NewVerifier creates the verifier for the named provider
+func NewVerifier(provider string, options Options) (Verifier, error) {
+	switch provider {
+	case ProviderReCaptcha:
+		return NewReCaptchaVerifier(options), nil
+	case ProviderHCaptcha:
+		return NewHCaptchaVerifier(options), nil
+	case ProviderTurnstile:
+		return NewTurnstileVerifier(options), nil
+	default:
+		return nil, fmt.Errorf("unsupported captcha provider: %s", provider)
+	}
+}
+
+// NewReCaptchaVerifier creates a Google reCAPTCHA (v2 or v3) verifier
+func NewReCaptchaVerifier(options Options) Verifier {
+	return newSiteVerifier(ProviderReCaptcha, reCaptchaVerifyURL, options)
+}
+
+// NewHCaptchaVerifier creates an hCaptcha verifier
+func NewHCaptchaVerifier(options Options) Verifier {
+	return newSiteVerifier(ProviderHCaptcha, hCaptchaVerifyURL, options)
+}
+
+// NewTurnstileVerifier creates a Cloudflare Turnstile verifier
+func NewTurnstileVerifier(options Options) Verifier {
+	return newSiteVerifier(ProviderTurnstile, turnstileVerifyURL, options)
+}
+
+// siteVerifier implements the siteverify protocol shared by reCAPTCHA, hCaptcha and Turnstile:
+// the secret and response are POSTed as a form and the provider answers with a JSON verdict
+type siteVerifier struct {
+	provider  string
+	verifyURL string
+	options   Options
+	client    *http.Client
+}
+
+// siteVerifyResponse is the common subset of the providers' verification responses
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func newSiteVerifier(provider, verifyURL string, options Options) *siteVerifier {
+	if options.VerifyURL != "" {
+		verifyURL = options.VerifyURL
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	return &siteVerifier{
+		provider:  provider,
+		verifyURL: verifyURL,
+		options:   options,
+		client:    &http.Client{Timeout: options.Timeout},
+	}
+}
+
+// Verify asks the provider whether token is valid
+func (v *siteVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if token == "" {
+		return ErrMissingToken
+	}
+
+	form := url.Values{
+		"secret":   {v.options.Secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s verification request failed: %w", v.provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s verification returned status %d", v.provider, resp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode %s verification response: %w", v.provider, err)
+	}
+
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrVerificationFailed, strings.Join(result.ErrorCodes, ","))
+	}
+	// Only reCAPTCHA v3 reports a score; v2 and the other providers are pass/fail
+	if v.options.MinScore > 0 && result.Score != nil && *result.Score < v.options.MinScore {
+		return fmt.Errorf("%w: score %.2f below %.2f", ErrVerificationFailed, *result.Score, v.options.MinScore)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrUntrustedIssuer is returned for tokens whose issuer is not configured
+	ErrUntrustedIssuer = errors.New("token issuer is not trusted")
+	// ErrInvalidToken is returned for tokens that fail signature or claim validation
+	ErrInvalidToken = errors.New("invalid subject token")
+)
+
+// Issuer is an external token issuer whose tokens are accepted for exchange. Tokens are
+// verified either with the keys published at JWKSURL, as OIDC providers do, or with a
+// shared HS256 Secret, e.g. for internal services.
+type Issuer struct {
+	// Name identifies the issuer in mapping rules and logs
+	Name string
+	// Issuer is the expected iss claim
+	Issuer string
+	// Audience, when set, must be among the aud claim
+	Audience string
+	JWKSURL  string
+	Secret   string
+}
+
+// Identity is the verified identity carried by an external token
+type Identity struct {
+	// Issuer is the Name of the issuer that signed the token
+	Issuer  string
+	Subject string
+	Claims  jwt.MapClaims
+}
+
+// StringClaim returns a string claim of the identity, or an empty string if it is missing
+func (i *Identity) StringClaim(name string) string {
+	value, _ := i.Claims[name].(string)
+	return value
+}
+
+// StringsClaim returns a claim holding a string or a list of strings, such as groups
+func (i *Identity) StringsClaim(name string) []string {
+	switch value := i.Claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Options configures a Verifier
+type Options struct {
+	// Leeway tolerates clock skew on exp, nbf and iat
+	Leeway time.Duration
+	// Timeout bounds a single JWKS request
+	Timeout time.Duration
+	// JWKSRefreshInterval is how long fetched keys are used before they are fetched again
+	JWKSRefreshInterval time.Duration
+}
+
+// Verifier verifies tokens of the trusted issuers
+type Verifier struct {
+	issuers map[string]Issuer
+	keySets map[string]*keySet
+	leeway  time.Duration
+}
+
+// NewVerifier creates a verifier trusting the given issuers
+func NewVerifier(issuers []Issuer, options Options) (*Verifier, error) {
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	if options.JWKSRefreshInterval <= 0 {
+		options.JWKSRefreshInterval = time.Hour
+	}
+	client := &http.Client{Timeout: options.Timeout}
+
+	v := &Verifier{
+		issuers: make(map[string]Issuer, len(issuers)),
+		keySets: make(map[string]*keySet),
+		leeway:  options.Leeway,
+	}
+	for _, issuer := range issuers {
+		if issuer.Issuer == "" {
+			return nil, fmt.Errorf("issuer %q has no iss value", issuer.Name)
+		}
+		if (issuer.JWKSURL == "") == (issuer.Secret == "") {
+			return nil, fmt.Errorf("issuer %q needs exactly one of a JWKS URL and a secret", issuer.Name)
+		}
+		if _, ok := v.issuers[issuer.Issuer]; ok {
+			return nil, fmt.Errorf("issuer %q is configured twice", issuer.Issuer)
+		}
+		v.issuers[issuer.Issuer] = issuer
+		if issuer.JWKSURL != "" {
+			v.keySets[issuer.Issuer] = newKeySet(issuer.JWKSURL, client, options.JWKSRefreshInterval)
+		}
+	}
+	return v, nil
+}
+
+// Verify checks the signature, issuer, audience and lifetime of an external token and
+// returns the identity it carries. Tokens must expire.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	// The issuer selects the verification key, so it is read before the signature is checked
+	unverified, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	iss, _ := unverified.Claims.GetIssuer()
+	issuer, ok := v.issuers[iss]
+	if !ok {
+		return nil, ErrUntrustedIssuer
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithIssuer(issuer.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(v.leeway),
+	}
+	if issuer.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(issuer.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if issuer.Secret != "" {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+			}
+			return []byte(issuer.Secret), nil
+		}
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return v.keySets[issuer.Issuer].key(ctx, kid)
+	}, parserOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	return &Identity{Issuer: issuer.Name, Subject: subject, Claims: claims}, nil
+}
@@ -0,0 +1,127 @@
+package exchange
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestVerifySharedSecret(t *testing.T) {
+	v, err := NewVerifier([]Issuer{{Name: "billing", Issuer: "billing-service", Audience: "gin-pkg", Secret: "shared-secret"}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(claims jwt.MapClaims, secret string) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		return token
+	}
+	valid := jwt.MapClaims{"iss": "billing-service", "aud": "gin-pkg", "sub": "svc-1", "email": "svc@example.com", "groups": []string{"ops"},
+		"exp": time.Now().Add(time.Minute).Unix()}
+
+	identity, err := v.Verify(context.Background(), sign(valid, "shared-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Issuer != "billing" || identity.Subject != "svc-1" || identity.StringClaim("email") != "svc@example.com" ||
+		len(identity.StringsClaim("groups")) != 1 {
+		t.Errorf("identity = %+v", identity)
+	}
+
+	for name, tc := range map[string]struct {
+		claims jwt.MapClaims
+		secret string
+		want   error
+	}{
+		"wrong secret":     {valid, "other-secret", ErrInvalidToken},
+		"untrusted issuer": {jwt.MapClaims{"iss": "other", "exp": valid["exp"]}, "shared-secret", ErrUntrustedIssuer},
+		"wrong audience":   {jwt.MapClaims{"iss": "billing-service", "aud": "other", "exp": valid["exp"]}, "shared-secret", ErrInvalidToken},
+		"no expiry":        {jwt.MapClaims{"iss": "billing-service", "aud": "gin-pkg"}, "shared-secret", ErrInvalidToken},
+		"expired":          {jwt.MapClaims{"iss": "billing-service", "aud": "gin-pkg", "exp": time.Now().Add(-time.Minute).Unix()}, "shared-secret", ErrInvalidToken},
+	} {
+		if _, err := v.Verify(context.Background(), sign(tc.claims, tc.secret)); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", name, err, tc.want)
+		}
+	}
+}
+
+func TestVerifyJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid := "k1"
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	defer server.Close()
+
+	v, err := NewVerifier([]Issuer{{Name: "sso", Issuer: "https://sso.example.com", JWKSURL: server.URL}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(kid string, method jwt.SigningMethod, signingKey interface{}) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"iss": "https://sso.example.com", "sub": "alice",
+			"exp": time.Now().Add(time.Minute).Unix()})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(signingKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := v.Verify(context.Background(), sign("k1", jwt.SigningMethodRS256, key)); err != nil {
+			t.Fatalf("valid token rejected: %v", err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("key set fetched %d times, want once", n)
+	}
+
+	// 未知 kid 在一分钟内不会再次触发获取
+	if _, err := v.Verify(context.Background(), sign("k2", jwt.SigningMethodRS256, key)); err == nil {
+		t.Error("token with an unknown key ID accepted")
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("unknown key ID refetched the key set within a minute: %d fetches", n)
+	}
+
+	// 不能用 HS256 以公钥作为密钥伪造令牌
+	forged := sign("k1", jwt.SigningMethodHS256, []byte("anything"))
+	if _, err := v.Verify(context.Background(), forged); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("HS256 token against a JWKS issuer: got %v", err)
+	}
+}
+
+func TestNewVerifierValidatesIssuers(t *testing.T) {
+	for name, issuers := range map[string][]Issuer{
+		"no key":    {{Name: "a", Issuer: "a"}},
+		"both keys": {{Name: "a", Issuer: "a", Secret: "s", JWKSURL: "https://a"}},
+		"duplicate": {{Name: "a", Issuer: "a", Secret: "s"}, {Name: "b", Issuer: "a", Secret: "s"}},
+		"no iss":    {{Name: "a", Secret: "s"}},
+	} {
+		if _, err := NewVerifier(issuers, Options{}); err == nil {
+			t.Errorf("%s: NewVerifier accepted the issuers", name)
+		}
+	}
+}
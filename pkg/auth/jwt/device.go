@@ -0,0 +1,33 @@
+package jwt
+
+import "context"
+
+// DeviceKey is the gin context key holding the Device of the client making a request
+const DeviceKey = "tokenDevice"
+
+// Device describes the client a refresh token is issued to, as recorded by a RefreshTokenStore
+type Device struct {
+	IP        string
+	UserAgent string
+}
+
+type deviceContextKey struct{}
+
+// ContextWithDevice returns a copy of ctx carrying the device of the client
+func ContextWithDevice(ctx context.Context, device Device) context.Context {
+	return context.WithValue(ctx, deviceContextKey{}, device)
+}
+
+// DeviceFromContext returns the client device carried by ctx, or the zero Device outside
+// requests. Services pass it in TokenOptions.Device when issuing tokens. It accepts both
+// request contexts and *gin.Context, which keeps the device under DeviceKey.
+func DeviceFromContext(ctx context.Context) Device {
+	if ctx == nil {
+		return Device{}
+	}
+	if device, ok := ctx.Value(deviceContextKey{}).(Device); ok {
+		return device
+	}
+	device, _ := ctx.Value(DeviceKey).(Device)
+	return device
+}
@@ -0,0 +1,27 @@
+package jwt
+
+import "context"
+
+// FingerprintKey is the gin context key holding the fingerprint of the client making a request
+const FingerprintKey = "tokenFingerprint"
+
+type fingerprintContextKey struct{}
+
+// ContextWithFingerprint returns a copy of ctx carrying the fingerprint of the client
+func ContextWithFingerprint(ctx context.Context, fingerprint string) context.Context {
+	return context.WithValue(ctx, fingerprintContextKey{}, fingerprint)
+}
+
+// FingerprintFromContext returns the client fingerprint carried by ctx, or "" when tokens are
+// not bound. Services pass it in TokenOptions.Fingerprint when issuing tokens. It accepts both
+// request contexts and *gin.Context, which keeps the fingerprint under FingerprintKey.
+func FingerprintFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if fingerprint, ok := ctx.Value(fingerprintContextKey{}).(string); ok {
+		return fingerprint
+	}
+	fingerprint, _ := ctx.Value(FingerprintKey).(string)
+	return fingerprint
+}
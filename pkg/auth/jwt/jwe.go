@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// jweHeader is the protected header of encrypted tokens: direct encryption with a shared
+// AES-256-GCM key of a nested, signed JWT
+var jweHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"dir","enc":"A256GCM","cty":"JWT"}`))
+
+// TokenEncryption encrypts signed tokens as compact JWE, so clients and intermediaries
+// cannot read claims such as the email or roles. Tokens are signed first and the JWT is
+// then encrypted with Key, so they stay signed with the access, refresh or tenant secret.
+type TokenEncryption struct {
+	// Key is the 32-byte AES-256-GCM key
+	Key []byte
+	// AccessTokens and RefreshTokens select the token types that are encrypted
+	AccessTokens  bool
+	RefreshTokens bool
+}
+
+// encrypts reports whether tokens of the given type are encrypted
+func (e TokenEncryption) encrypts(tokenType TokenType) bool {
+	switch tokenType {
+	case AccessToken:
+		return e.AccessTokens
+	case RefreshToken:
+		return e.RefreshTokens
+	default:
+		return false
+	}
+}
+
+func (e TokenEncryption) aead() (cipher.AEAD, error) {
+	if len(e.Key) != 32 {
+		return nil, fmt.Errorf("token encryption key must be 32 bytes, got %d", len(e.Key))
+	}
+	block, err := aes.NewCipher(e.Key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt wraps a signed JWT into a compact JWE: header, empty encrypted key, IV,
+// ciphertext and authentication tag, with the encoded header as additional data
+func (e TokenEncryption) encrypt(token string) (string, error) {
+	aead, err := e.aead()
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", fmt.Errorf("failed to generate IV: %w", err)
+	}
+	sealed := aead.Seal(nil, iv, []byte(token), []byte(jweHeader))
+	tagStart := len(sealed) - aead.Overhead()
+
+	return strings.Join([]string{
+		jweHeader,
+		"",
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(sealed[:tagStart]),
+		base64.RawURLEncoding.EncodeToString(sealed[tagStart:]),
+	}, "."), nil
+}
+
+// decrypt returns the signed JWT inside a compact JWE produced by encrypt
+func (e TokenEncryption) decrypt(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 || parts[1] != "" {
+		return "", errors.New("invalid encrypted token")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", errors.New("invalid encrypted token header")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Enc string `json:"enc"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil || header.Alg != "dir" || header.Enc != "A256GCM" {
+		return "", errors.New("unsupported token encryption")
+	}
+
+	aead, err := e.aead()
+	if err != nil {
+		return "", err
+	}
+	iv, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(iv) != aead.NonceSize() {
+		return "", errors.New("invalid encrypted token")
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", errors.New("invalid encrypted token")
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(parts[4])
+	if err != nil || len(tag) != aead.Overhead() {
+		return "", errors.New("invalid encrypted token")
+	}
+
+	plaintext, err := aead.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return "", errors.New("failed to decrypt token")
+	}
+	return string(plaintext), nil
+}
@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"context"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,16 +18,57 @@ const (
 	RefreshToken TokenType = "refresh"
 )
 
+// GuestRole is the role of guest tokens, which are issued without a user record
+const GuestRole = "guest"
+
 // Claims represents the JWT claims
 type Claims struct {
-	UserID    string `json:"user_id"`
-	Email     string `json:"email"`
-	Role      string `json:"role"`
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	// Roles are the roles of the user when the token was issued
+	Roles []string `json:"roles,omitempty"`
+	// Role is the single role carried by tokens issued before users could hold several
+	// roles. ValidateToken moves it into Roles; new tokens leave it empty.
+	Role      string `json:"role,omitempty"`
 	TokenType string `json:"token_type"`
 	TokenID   string `json:"token_id"`
+	// RememberMe marks tokens from a "remember me" login, whose refresh tokens live longer
+	RememberMe bool `json:"remember_me,omitempty"`
+	// ImpersonatedBy is the ID of the admin acting as the user, empty for normal logins
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	// ClientID is the OAuth client the tokens were issued to, empty for direct logins
+	ClientID string `json:"client_id,omitempty"`
+	// Guest marks tokens issued to anonymous clients; UserID is then a random guest ID
+	Guest bool `json:"guest,omitempty"`
+	// TenantID is the tenant the token was issued for. Tokens of a tenant are signed with
+	// the tenant's own secret; empty for tokens signed with the default secrets.
+	TenantID string `json:"tenant_id,omitempty"`
+	// OrgID is the organization of the user; requests made with the token act in it.
+	// Empty for users that belong to no organization.
+	OrgID string `json:"org_id,omitempty"`
+	// PasswordChangeRequired marks tokens of a user who must change their password before
+	// using anything else
+	PasswordChangeRequired bool `json:"pwd_change,omitempty"`
+	// Scopes are the permissions granted to the token. Deliberately not omitempty: an empty
+	// list grants nothing, while a missing claim marks a token issued before scopes existed.
+	Scopes []string `json:"scopes"`
+	// Fingerprint is the hash of the client attributes the token is bound to, empty for unbound tokens
+	Fingerprint string `json:"fpt,omitempty"`
+	// SessionID identifies the login the token belongs to across refreshes. Only set when
+	// refresh tokens are recorded in a RefreshTokenStore.
+	SessionID string `json:"sid,omitempty"`
+	// Extra holds the application-specific claims added by ClaimsEnrichers. Values round-trip
+	// through JSON, so numbers come back as float64.
+	Extra map[string]interface{} `json:"ext,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ClaimsEnricher adds application-specific claims, such as a tenant ID or feature flags, to
+// the Extra map of a token pair being issued. claims is a copy of the standard claims of the
+// access token; returning an error fails the issuance. Enrichers also run when tokens are
+// refreshed, so the extra claims stay current.
+type ClaimsEnricher func(ctx context.Context, claims Claims, extra map[string]interface{}) error
+
 // TokenPair contains both access and refresh tokens
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
@@ -33,11 +76,87 @@ type TokenPair struct {
 	ExpiresIn    int64  `json:"expires_in"`
 }
 
+// TokenOptions adjusts how a token pair is issued
+type TokenOptions struct {
+	// RememberMe issues a refresh token with the extended remember-me lifetime
+	RememberMe bool
+	// ImpersonatedBy issues a short-lived impersonation pair on behalf of the given admin
+	ImpersonatedBy string
+	// MaxLifetime caps the lifetime of both tokens when set
+	MaxLifetime time.Duration
+	// Scopes overrides the scopes derived from the roles, e.g. to issue a narrower token
+	Scopes []string
+	// ClientID issues the tokens to an OAuth client
+	ClientID string
+	// Guest issues only an access token for an anonymous client without a user record
+	Guest bool
+	// Fingerprint binds the tokens to the client they are issued to; see FingerprintFromContext
+	Fingerprint string
+	// Device is recorded with the refresh token in the RefreshTokenStore; see DeviceFromContext
+	Device Device
+	// sessionID continues the recorded session of a refreshed token
+	sessionID string
+	// TenantID issues the tokens for a tenant, signed with the tenant's secret
+	TenantID string
+	// OrgID issues the tokens for a user of an organization
+	OrgID string
+	// PasswordChangeRequired issues tokens that only allow changing the password
+	PasswordChangeRequired bool
+}
+
+// HasAnyRole reports whether roles contains at least one of the wanted roles
+func HasAnyRole(roles []string, wanted ...string) bool {
+	for _, want := range wanted {
+		if slices.Contains(roles, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAllRoles reports whether roles contains every wanted role
+func HasAllRoles(roles []string, wanted ...string) bool {
+	for _, want := range wanted {
+		if !slices.Contains(roles, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasScopes reports whether the scopes grant every required scope ("*" grants everything)
+func HasScopes(scopes []string, required ...string) bool {
+	for _, req := range required {
+		granted := false
+		for _, scope := range scopes {
+			if scope == req || scope == "*" {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			return false
+		}
+	}
+	return true
+}
+
 // TokenService defines the interface for JWT token operations
 type TokenService interface {
-	GenerateTokenPair(userID string, email, role string) (*TokenPair, error)
-	ValidateToken(tokenString string, tokenType TokenType) (*Claims, error)
-	RefreshTokens(refreshToken string) (*TokenPair, error)
-	BlacklistToken(tokenID string, expiration time.Duration) error
-	IsTokenBlacklisted(tokenID string) (bool, error)
+	GenerateTokenPair(ctx context.Context, userID string, email string, roles []string) (*TokenPair, error)
+	GenerateTokenPairWithOptions(ctx context.Context, userID string, email string, roles []string, opts TokenOptions) (*TokenPair, error)
+	ValidateToken(ctx context.Context, tokenString string, tokenType TokenType) (*Claims, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error)
+	// RevokeRefreshToken revokes the refresh token with the given validated claims, recording
+	// reason with it in the RefreshTokenStore
+	RevokeRefreshToken(ctx context.Context, claims *Claims, reason string) error
+	ScopesForRoles(roles []string) []string
+	BlacklistToken(ctx context.Context, tokenID string, expiration time.Duration) error
+	IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error)
+	RevokeUserTokens(ctx context.Context, userID string) error
+	BlacklistUser(ctx context.Context, userID string, until time.Time) error
+	IsUserBlacklisted(ctx context.Context, userID string) (bool, error)
+	// AddClaimsEnricher registers an enricher run on every issued token pair, in order of
+	// registration. Enrichers must be added before tokens are issued.
+	AddClaimsEnricher(enricher ClaimsEnricher)
 }
@@ -1,8 +1,15 @@
 package jwt
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,96 +22,324 @@ type JWTService struct {
 	refreshSecret          string
 	accessTokenDuration    time.Duration
 	refreshTokenDuration   time.Duration
+	rememberMeDuration     time.Duration
+	impersonationDuration  time.Duration
 	defaultAccessTokenExp  int64
 	defaultRefreshTokenExp int64
-	blacklistToken         func(tokenID string, expiration time.Duration) error
-	isTokenBlacklisted     func(tokenID string) (bool, error)
+	roleScopes             map[string][]string
+	issuer                 string
+	audience               string
+	leeway                 time.Duration
+	// tenantSecrets signs the access and refresh tokens of each tenant
+	tenantSecrets map[string]string
+	// encryption encrypts the signed tokens of the selected types
+	encryption TokenEncryption
+	store      TokenStore
+	enrichers  []ClaimsEnricher
+	// sessions holds the claims of opaque tokens; nil issues signed JWTs
+	sessions SessionStore
+	// refreshTokens records issued refresh tokens; nil keeps them stateless
+	refreshTokens RefreshTokenStore
 }
 
-// NewJWTService creates a new JWT service
+// NewJWTService creates a new JWT service. Tokens carry the issuer and, when set, the
+// audience, and tokens with a different issuer or audience are rejected. The exp, nbf and
+// iat claims are checked with leeway for clock skew. Tokens issued for a tenant carry the
+// tenant_id claim and are signed and verified with the tenant's entry in tenantSecrets
+// instead of the access and refresh secrets. Tokens of the types selected by encryption are
+// encrypted after signing. Revocations are kept in store. When refreshTokens is not nil,
+// every issued refresh token is recorded in it and can only be used while its record is
+// active.
 func NewJWTService(
 	accessSecret string,
 	refreshSecret string,
 	accessTokenDuration time.Duration,
 	refreshTokenDuration time.Duration,
+	rememberMeDuration time.Duration,
+	impersonationDuration time.Duration,
 	defaultAccessTokenExp int64,
 	defaultRefreshTokenExp int64,
-	blacklistToken func(tokenID string, expiration time.Duration) error,
-	isTokenBlacklisted func(tokenID string) (bool, error),
+	roleScopes map[string][]string,
+	issuer string,
+	audience string,
+	leeway time.Duration,
+	tenantSecrets map[string]string,
+	encryption TokenEncryption,
+	store TokenStore,
+	refreshTokens RefreshTokenStore,
 ) TokenService {
+	return newJWTService(accessSecret, refreshSecret, accessTokenDuration, refreshTokenDuration, rememberMeDuration,
+		impersonationDuration, defaultAccessTokenExp, defaultRefreshTokenExp, roleScopes, issuer, audience, leeway,
+		tenantSecrets, encryption, store, refreshTokens)
+}
+
+// NewOpaqueTokenService creates a token service issuing opaque random tokens instead of JWTs.
+// The claims live in sessions, keyed by the token hash, so tokens stay small and ending a
+// session revokes its token at once. ValidateToken fills the same Claims as for JWTs.
+// Refresh tokens are recorded in refreshTokens as with NewJWTService.
+func NewOpaqueTokenService(
+	accessSecret string,
+	refreshSecret string,
+	accessTokenDuration time.Duration,
+	refreshTokenDuration time.Duration,
+	rememberMeDuration time.Duration,
+	impersonationDuration time.Duration,
+	defaultAccessTokenExp int64,
+	defaultRefreshTokenExp int64,
+	roleScopes map[string][]string,
+	issuer string,
+	audience string,
+	leeway time.Duration,
+	tenantSecrets map[string]string,
+	store TokenStore,
+	sessions SessionStore,
+	refreshTokens RefreshTokenStore,
+) TokenService {
+	s := newJWTService(accessSecret, refreshSecret, accessTokenDuration, refreshTokenDuration, rememberMeDuration,
+		impersonationDuration, defaultAccessTokenExp, defaultRefreshTokenExp, roleScopes, issuer, audience, leeway,
+		tenantSecrets, TokenEncryption{}, store, refreshTokens)
+	s.sessions = sessions
+	return s
+}
+
+func newJWTService(
+	accessSecret string,
+	refreshSecret string,
+	accessTokenDuration time.Duration,
+	refreshTokenDuration time.Duration,
+	rememberMeDuration time.Duration,
+	impersonationDuration time.Duration,
+	defaultAccessTokenExp int64,
+	defaultRefreshTokenExp int64,
+	roleScopes map[string][]string,
+	issuer string,
+	audience string,
+	leeway time.Duration,
+	tenantSecrets map[string]string,
+	encryption TokenEncryption,
+	store TokenStore,
+	refreshTokens RefreshTokenStore,
+) *JWTService {
 	return &JWTService{
 		accessSecret:           accessSecret,
 		refreshSecret:          refreshSecret,
 		accessTokenDuration:    accessTokenDuration,
 		refreshTokenDuration:   refreshTokenDuration,
+		rememberMeDuration:     rememberMeDuration,
+		impersonationDuration:  impersonationDuration,
 		defaultAccessTokenExp:  defaultAccessTokenExp,
 		defaultRefreshTokenExp: defaultRefreshTokenExp,
-		blacklistToken:         blacklistToken,
-		isTokenBlacklisted:     isTokenBlacklisted,
+		roleScopes:             roleScopes,
+		issuer:                 issuer,
+		audience:               audience,
+		leeway:                 leeway,
+		tenantSecrets:          tenantSecrets,
+		encryption:             encryption,
+		store:                  store,
+		refreshTokens:          refreshTokens,
 	}
 }
 
 // GenerateTokenPair creates a new pair of access and refresh tokens
-func (s *JWTService) GenerateTokenPair(userID string, email, role string) (*TokenPair, error) {
+func (s *JWTService) GenerateTokenPair(ctx context.Context, userID string, email string, roles []string) (*TokenPair, error) {
+	return s.GenerateTokenPairWithOptions(ctx, userID, email, roles, TokenOptions{})
+}
+
+// GenerateTokenPairWithOptions creates a new pair of access and refresh tokens.
+// With RememberMe the refresh token gets the extended lifetime and both tokens carry the remember_me claim.
+// With ImpersonatedBy both tokens are capped at the impersonation lifetime and carry the impersonated_by claim.
+// Both tokens carry the scopes granted to the roles unless opts.Scopes is set.
+// With Guest only the access token is issued, since guests cannot come back for a refresh.
+// With TenantID both tokens are signed with the tenant's secret; unknown tenants are rejected.
+// With a RefreshTokenStore the refresh token is recorded with opts.Device, and both tokens
+// carry the ID of the session it starts.
+func (s *JWTService) GenerateTokenPairWithOptions(ctx context.Context, userID string, email string, roles []string, opts TokenOptions) (*TokenPair, error) {
+	accessSecret, refreshSecret := s.accessSecret, s.refreshSecret
+	if opts.TenantID != "" {
+		secret, err := s.tenantSecret(opts.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		accessSecret, refreshSecret = secret, secret
+	}
+
+	scopes := opts.Scopes
+	if scopes == nil {
+		scopes = s.ScopesForRoles(roles)
+	}
+
+	accessTokenDuration := s.accessTokenDuration
+	refreshTokenDuration := s.refreshTokenDuration
+	if opts.RememberMe {
+		refreshTokenDuration = s.rememberMeDuration
+	}
+	maxLifetime := opts.MaxLifetime
+	if opts.ImpersonatedBy != "" && (maxLifetime <= 0 || maxLifetime > s.impersonationDuration) {
+		maxLifetime = s.impersonationDuration
+	}
+	if maxLifetime > 0 {
+		accessTokenDuration = min(accessTokenDuration, maxLifetime)
+		refreshTokenDuration = min(refreshTokenDuration, maxLifetime)
+	}
+
+	// Recorded sessions are identified by their first refresh token
+	refreshTokenID := uuid.New().String()
+	sessionID := ""
+	if s.refreshTokens != nil && !opts.Guest {
+		sessionID = opts.sessionID
+		if sessionID == "" {
+			sessionID = refreshTokenID
+		}
+	}
+
 	// Generate access token
 	accessTokenID := uuid.New().String()
-	accessTokenExpiration := time.Now().Add(s.accessTokenDuration)
+	accessTokenExpiration := time.Now().Add(accessTokenDuration)
 	accessClaims := Claims{
-		UserID:    userID,
-		Email:     email,
-		Role:      role,
-		TokenType: string(AccessToken),
-		TokenID:   accessTokenID,
+		UserID:                 userID,
+		Email:                  email,
+		Roles:                  roles,
+		TokenType:              string(AccessToken),
+		TokenID:                accessTokenID,
+		RememberMe:             opts.RememberMe,
+		ImpersonatedBy:         opts.ImpersonatedBy,
+		ClientID:               opts.ClientID,
+		Guest:                  opts.Guest,
+		TenantID:               opts.TenantID,
+		OrgID:                  opts.OrgID,
+		Scopes:                 scopes,
+		PasswordChangeRequired: opts.PasswordChangeRequired,
+		Fingerprint:            opts.Fingerprint,
+		SessionID:              sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(accessTokenExpiration),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "gin-pkg",
+			Issuer:    s.issuer,
+			Audience:  s.audienceClaim(),
 			Subject:   userID,
 			ID:        accessTokenID,
 		},
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(s.accessSecret))
+	extra, err := s.enrich(ctx, accessClaims)
+	if err != nil {
+		return nil, err
+	}
+	accessClaims.Extra = extra
+
+	accessTokenString, err := s.issue(ctx, accessClaims, accessSecret)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign access token: %w", err)
+		return nil, fmt.Errorf("failed to issue access token: %w", err)
+	}
+
+	expiresIn := s.defaultAccessTokenExp
+	if maxLifetime > 0 {
+		expiresIn = min(expiresIn, int64(accessTokenDuration.Seconds()))
+	}
+
+	if opts.Guest {
+		return &TokenPair{
+			AccessToken: accessTokenString,
+			ExpiresIn:   expiresIn,
+		}, nil
 	}
 
 	// Generate refresh token
-	refreshTokenID := uuid.New().String()
-	refreshTokenExpiration := time.Now().Add(s.refreshTokenDuration)
+	refreshTokenExpiration := time.Now().Add(refreshTokenDuration)
 	refreshClaims := Claims{
-		UserID:    userID,
-		Email:     email,
-		Role:      role,
-		TokenType: string(RefreshToken),
-		TokenID:   refreshTokenID,
+		UserID:                 userID,
+		Email:                  email,
+		Roles:                  roles,
+		TokenType:              string(RefreshToken),
+		TokenID:                refreshTokenID,
+		RememberMe:             opts.RememberMe,
+		ImpersonatedBy:         opts.ImpersonatedBy,
+		ClientID:               opts.ClientID,
+		TenantID:               opts.TenantID,
+		OrgID:                  opts.OrgID,
+		Scopes:                 scopes,
+		PasswordChangeRequired: opts.PasswordChangeRequired,
+		Fingerprint:            opts.Fingerprint,
+		SessionID:              sessionID,
+		Extra:                  extra,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(refreshTokenExpiration),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "gin-pkg",
+			Issuer:    s.issuer,
+			Audience:  s.audienceClaim(),
 			Subject:   userID,
 			ID:        refreshTokenID,
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(s.refreshSecret))
+	refreshTokenString, err := s.issue(ctx, refreshClaims, refreshSecret)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign refresh token: %w", err)
+		return nil, fmt.Errorf("failed to issue refresh token: %w", err)
+	}
+	if s.refreshTokens != nil {
+		err := s.refreshTokens.Save(ctx, RefreshTokenRecord{
+			ID:             refreshTokenID,
+			SessionID:      sessionID,
+			UserID:         userID,
+			TenantID:       opts.TenantID,
+			TokenHash:      tokenHash(refreshTokenString),
+			Device:         opts.Device,
+			ImpersonatedBy: opts.ImpersonatedBy,
+			ClientID:       opts.ClientID,
+			ExpiresAt:      refreshTokenExpiration,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record refresh token: %w", err)
+		}
 	}
 
 	return &TokenPair{
 		AccessToken:  accessTokenString,
 		RefreshToken: refreshTokenString,
-		ExpiresIn:    s.defaultAccessTokenExp,
+		ExpiresIn:    expiresIn,
 	}, nil
 }
 
-// ValidateToken validates a JWT token
-func (s *JWTService) ValidateToken(tokenString string, tokenType TokenType) (*Claims, error) {
+// issue signs claims into a JWT, encrypted if its type is, or in opaque mode stores them in
+// a new session and returns its token
+func (s *JWTService) issue(ctx context.Context, claims Claims, secret string) (string, error) {
+	if s.sessions == nil {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil || !s.encryption.encrypts(TokenType(claims.TokenType)) {
+			return token, err
+		}
+		return s.encryption.encrypt(token)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	encoded, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token claims: %w", err)
+	}
+	if err := s.sessions.Save(ctx, tokenHash(token), encoded, time.Until(claims.ExpiresAt.Time)); err != nil {
+		return "", fmt.Errorf("failed to store token session: %w", err)
+	}
+	return token, nil
+}
+
+// tokenHash returns the SHA-256 hash of a token, the key of its session in opaque mode and
+// the hash recorded in the RefreshTokenStore
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateToken validates a token and returns its claims. In opaque mode the claims are
+// read from the token's session; otherwise the token is decrypted if its type is encrypted
+// and parsed as a signed JWT.
+func (s *JWTService) ValidateToken(ctx context.Context, tokenString string, tokenType TokenType) (*Claims, error) {
 	var secret string
 	switch tokenType {
 	case AccessToken:
@@ -115,12 +350,100 @@ func (s *JWTService) ValidateToken(tokenString string, tokenType TokenType) (*Cl
 		return nil, errors.New("invalid token type")
 	}
 
+	var claims *Claims
+	var err error
+	switch {
+	case s.sessions != nil:
+		claims, err = s.loadSession(ctx, tokenString)
+	case s.encryption.encrypts(tokenType):
+		var signed string
+		if signed, err = s.encryption.decrypt(tokenString); err == nil {
+			claims, err = s.parse(signed, secret)
+		}
+	default:
+		claims, err = s.parse(tokenString, secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Tokens of a tenant removed from the configuration are rejected, including opaque tokens,
+	// whose signature is not checked
+	if claims.TenantID != "" {
+		if _, err := s.tenantSecret(claims.TenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check if the token is of the correct type
+	if TokenType(claims.TokenType) != tokenType {
+		return nil, errors.New("token type mismatch")
+	}
+
+	// Check if the token or its user has been revoked, in one lookup if the store supports it.
+	// Guests have no user whose tokens could be revoked.
+	userID := claims.UserID
+	if claims.Guest {
+		userID = ""
+	}
+	revocation, err := lookupRevocation(ctx, s.store, claims.TokenID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if revocation.Blacklisted {
+		return nil, errors.New("token has been revoked")
+	}
+
+	// Check if all tokens of the user have been revoked since this one was issued.
+	// iat has second precision, so a token issued in the same second as the revocation is rejected too.
+	revokedAt := revocation.UserRevokedAt
+	if !revokedAt.IsZero() && claims.IssuedAt != nil && !claims.IssuedAt.Time.After(revokedAt) {
+		return nil, errors.New("token has been revoked")
+	}
+
+	// A blacklisted user is rejected with every token, including ones issued during the blacklist
+	if time.Now().Before(revocation.UserBlacklistedUntil) {
+		return nil, errors.New("user has been blacklisted")
+	}
+
+	// Tokens issued before users could hold several roles carry a single role claim
+	if claims.Roles == nil && claims.Role != "" {
+		claims.Roles = []string{claims.Role}
+		claims.Role = ""
+	}
+
+	// Tokens issued before scopes were introduced get the scopes of their roles
+	if claims.Scopes == nil {
+		claims.Scopes = s.ScopesForRoles(claims.Roles)
+	}
+
+	return claims, nil
+}
+
+// parse verifies the signature, issuer and audience of a JWT and returns its claims. Tokens
+// carrying a tenant claim are verified with the tenant's secret instead of secret.
+func (s *JWTService) parse(tokenString string, secret string) (*Claims, error) {
+	// Tokens of other environments are signed by other services or for other audiences.
+	// Clients and instances with slightly wrong clocks are given the leeway on exp, nbf and iat.
+	parserOptions := []jwt.ParserOption{jwt.WithIssuer(s.issuer), jwt.WithIssuedAt(), jwt.WithLeeway(s.leeway)}
+	if s.audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(s.audience))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		// The claims are decoded before the signature is checked, but only the tenant is used,
+		// to pick the key; a forged tenant fails the check with that tenant's key
+		if claims, ok := token.Claims.(*Claims); ok && claims.TenantID != "" {
+			tenantSecret, err := s.tenantSecret(claims.TenantID)
+			if err != nil {
+				return nil, err
+			}
+			return []byte(tenantSecret), nil
+		}
 		return []byte(secret), nil
-	})
+	}, parserOptions...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -134,47 +457,177 @@ func (s *JWTService) ValidateToken(tokenString string, tokenType TokenType) (*Cl
 	if !ok {
 		return nil, errors.New("invalid token claims")
 	}
+	return claims, nil
+}
 
-	// Check if the token is of the correct type
-	if TokenType(claims.TokenType) != tokenType {
-		return nil, errors.New("token type mismatch")
-	}
-
-	// Check if the token is blacklisted
-	isBlacklisted, err := s.isTokenBlacklisted(claims.TokenID)
+// loadSession returns the claims stored for an opaque token
+func (s *JWTService) loadSession(ctx context.Context, token string) (*Claims, error) {
+	encoded, err := s.sessions.Load(ctx, tokenHash(token))
 	if err != nil {
-		return nil, fmt.Errorf("failed to check token blacklist: %w", err)
+		return nil, fmt.Errorf("failed to load token session: %w", err)
 	}
-	if isBlacklisted {
-		return nil, errors.New("token has been revoked")
+	if encoded == nil {
+		return nil, errors.New("invalid token")
 	}
 
+	claims := &Claims{}
+	if err := json.Unmarshal(encoded, claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	// Sessions expire with their token; check again in case the store's expiry is imprecise
+	if claims.ExpiresAt == nil || !time.Now().Before(claims.ExpiresAt.Time.Add(s.leeway)) {
+		return nil, errors.New("token has expired")
+	}
 	return claims, nil
 }
 
 // RefreshTokens generates a new token pair using a valid refresh token
-func (s *JWTService) RefreshTokens(refreshToken string) (*TokenPair, error) {
-	claims, err := s.ValidateToken(refreshToken, RefreshToken)
+func (s *JWTService) RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := s.ValidateToken(ctx, refreshToken, RefreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid refresh token: %w", err)
 	}
 
-	// Blacklist the used refresh token
+	// Recorded tokens are rotated in the store rather than blacklisted, so the store sees a
+	// used token presented again and can revoke its session. Tokens issued before recording
+	// was enabled carry no session; they are blacklisted and start one.
 	expiry := time.Until(claims.ExpiresAt.Time)
-	if err := s.BlacklistToken(claims.TokenID, expiry); err != nil {
+	var device Device
+	if s.refreshTokens != nil && claims.SessionID != "" {
+		if device, err = s.refreshTokens.Rotate(ctx, claims.TenantID, claims.TokenID); err != nil {
+			return nil, fmt.Errorf("invalid refresh token: %w", err)
+		}
+	} else if err := s.BlacklistToken(ctx, claims.TokenID, expiry); err != nil {
 		return nil, fmt.Errorf("failed to blacklist refresh token: %w", err)
 	}
+	// The token is already rejected; ending the session frees the stored claims early
+	if s.sessions != nil {
+		if err := s.sessions.Delete(ctx, tokenHash(refreshToken)); err != nil {
+			return nil, fmt.Errorf("failed to end refresh token session: %w", err)
+		}
+	}
+
+	// Generate new token pair, keeping the remember-me lifetime, the scopes and the OAuth client of the original login,
+	// so a narrowed token cannot be widened by refreshing it. A stolen refresh token only yields tokens bound to the
+	// original client.
+	// Impersonation sessions cannot be extended past the expiry of the original pair.
+	opts := TokenOptions{
+		RememberMe:             claims.RememberMe,
+		ImpersonatedBy:         claims.ImpersonatedBy,
+		Scopes:                 claims.Scopes,
+		ClientID:               claims.ClientID,
+		Fingerprint:            claims.Fingerprint,
+		TenantID:               claims.TenantID,
+		OrgID:                  claims.OrgID,
+		PasswordChangeRequired: claims.PasswordChangeRequired,
+		Device:                 device,
+		sessionID:              claims.SessionID,
+	}
+	if claims.ImpersonatedBy != "" {
+		opts.MaxLifetime = expiry
+	}
+	return s.GenerateTokenPairWithOptions(ctx, claims.UserID, claims.Email, claims.Roles, opts)
+}
+
+// ScopesForRoles returns the union of the scopes configured for roles; unknown roles add none
+func (s *JWTService) ScopesForRoles(roles []string) []string {
+	scopes := []string{}
+	for _, role := range roles {
+		for _, scope := range s.roleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// AddClaimsEnricher registers an enricher run on every issued token pair
+func (s *JWTService) AddClaimsEnricher(enricher ClaimsEnricher) {
+	s.enrichers = append(s.enrichers, enricher)
+}
+
+// enrich runs the enrichers on the claims of a new access token and returns the extra
+// claims, or nil when there are none
+func (s *JWTService) enrich(ctx context.Context, claims Claims) (map[string]interface{}, error) {
+	if len(s.enrichers) == 0 {
+		return nil, nil
+	}
+	extra := map[string]interface{}{}
+	for _, enricher := range s.enrichers {
+		if err := enricher(ctx, claims, extra); err != nil {
+			return nil, fmt.Errorf("failed to enrich token claims: %w", err)
+		}
+	}
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
+
+// tenantSecret returns the signing secret of a tenant
+func (s *JWTService) tenantSecret(tenantID string) (string, error) {
+	secret, ok := s.tenantSecrets[tenantID]
+	if !ok {
+		return "", fmt.Errorf("unknown tenant %q", tenantID)
+	}
+	return secret, nil
+}
 
-	// Generate new token pair
-	return s.GenerateTokenPair(claims.UserID, claims.Email, claims.Role)
+// audienceClaim returns the aud claim of new tokens, or nil when no audience is configured
+func (s *JWTService) audienceClaim() jwt.ClaimStrings {
+	if s.audience == "" {
+		return nil
+	}
+	return jwt.ClaimStrings{s.audience}
 }
 
 // BlacklistToken adds a token to the blacklist
-func (s *JWTService) BlacklistToken(tokenID string, expiration time.Duration) error {
-	return s.blacklistToken(tokenID, expiration)
+func (s *JWTService) BlacklistToken(ctx context.Context, tokenID string, expiration time.Duration) error {
+	return s.store.Blacklist(ctx, tokenID, expiration)
 }
 
 // IsTokenBlacklisted checks if a token is blacklisted
-func (s *JWTService) IsTokenBlacklisted(tokenID string) (bool, error) {
-	return s.isTokenBlacklisted(tokenID)
+func (s *JWTService) IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	return s.store.IsBlacklisted(ctx, tokenID)
+}
+
+// RevokeUserTokens revokes every access and refresh token issued to a user so far
+func (s *JWTService) RevokeUserTokens(ctx context.Context, userID string) error {
+	// Keep the marker until the longest-lived token issued before now has expired
+	expiration := max(s.accessTokenDuration, s.refreshTokenDuration, s.rememberMeDuration)
+	if err := s.store.RevokeUser(ctx, userID, time.Now(), expiration); err != nil {
+		return err
+	}
+	if s.refreshTokens != nil {
+		return s.refreshTokens.RevokeUser(ctx, userID, RevokedUser)
+	}
+	return nil
+}
+
+// RevokeRefreshToken blacklists the refresh token with the given validated claims and, when
+// it is recorded, marks it revoked for reason
+func (s *JWTService) RevokeRefreshToken(ctx context.Context, claims *Claims, reason string) error {
+	if err := s.store.Blacklist(ctx, claims.TokenID, time.Until(claims.ExpiresAt.Time)); err != nil {
+		return err
+	}
+	if s.refreshTokens != nil && claims.SessionID != "" {
+		return s.refreshTokens.Revoke(ctx, claims.TenantID, claims.TokenID, reason)
+	}
+	return nil
+}
+
+// BlacklistUser rejects every token of a user, including ones issued later, until the given
+// time, e.g. to ban the user at once. A zero or past time lifts the blacklist.
+func (s *JWTService) BlacklistUser(ctx context.Context, userID string, until time.Time) error {
+	return s.store.BlacklistUser(ctx, userID, until)
+}
+
+// IsUserBlacklisted checks if the tokens of a user are blacklisted
+func (s *JWTService) IsUserBlacklisted(ctx context.Context, userID string) (bool, error) {
+	until, err := s.store.UserBlacklistedUntil(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return time.Now().Before(until), nil
 }
@@ -0,0 +1,262 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestClaimsEnricher(t *testing.T) {
+	service := newTestService(NewMemoryTokenStore())
+	service.AddClaimsEnricher(func(ctx context.Context, claims Claims, extra map[string]interface{}) error {
+		extra["tenant_id"] = "tenant-" + claims.UserID
+		return nil
+	})
+	service.AddClaimsEnricher(func(ctx context.Context, claims Claims, extra map[string]interface{}) error {
+		if claims.TokenType != string(AccessToken) {
+			t.Errorf("enricher got token type %s", claims.TokenType)
+		}
+		extra["beta"] = true
+		return nil
+	})
+
+	pair, err := service.GenerateTokenPair(context.Background(), "u1", "a@example.com", []string{"user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := service.ValidateToken(context.Background(), pair.AccessToken, AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Extra["tenant_id"] != "tenant-u1" || claims.Extra["beta"] != true {
+		t.Errorf("access token extra claims = %v", claims.Extra)
+	}
+
+	refreshed, err := service.RefreshTokens(context.Background(), pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, _ = service.ValidateToken(context.Background(), refreshed.AccessToken, AccessToken)
+	if claims.Extra["tenant_id"] != "tenant-u1" {
+		t.Errorf("refreshed token extra claims = %v", claims.Extra)
+	}
+
+	failing := newTestService(NewMemoryTokenStore())
+	failing.AddClaimsEnricher(func(context.Context, Claims, map[string]interface{}) error { return errors.New("tenant lookup failed") })
+	if _, err := failing.GenerateTokenPair(context.Background(), "u1", "a@example.com", []string{"user"}); err == nil {
+		t.Error("token pair issued although an enricher failed")
+	}
+}
+
+func TestOpaqueTokens(t *testing.T) {
+	sessions := NewMemorySessionStore()
+	service := NewOpaqueTokenService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, map[string][]string{"user": {"profile:read"}}, "gin-pkg", "", 0, nil, NewMemoryTokenStore(), sessions, nil)
+
+	pair, err := service.GenerateTokenPairWithOptions(context.Background(), "u1", "a@example.com", []string{"user"}, TokenOptions{Fingerprint: "fp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(pair.AccessToken, ".") || len(pair.AccessToken) != 43 {
+		t.Errorf("access token %q is not opaque", pair.AccessToken)
+	}
+
+	claims, err := service.ValidateToken(context.Background(), pair.AccessToken, AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != "u1" || claims.Email != "a@example.com" || claims.Fingerprint != "fp" ||
+		!slices.Equal(claims.Scopes, []string{"profile:read"}) || claims.IssuedAt == nil {
+		t.Errorf("claims = %+v", claims)
+	}
+	if _, err := service.ValidateToken(context.Background(), pair.AccessToken, RefreshToken); err == nil {
+		t.Error("access token accepted as refresh token")
+	}
+	if _, err := service.ValidateToken(context.Background(), "not-a-session", AccessToken); err == nil {
+		t.Error("unknown token accepted")
+	}
+
+	refreshed, err := service.RefreshTokens(context.Background(), pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := sessions.Load(context.Background(), tokenHash(pair.RefreshToken)); stored != nil {
+		t.Error("session of the used refresh token kept")
+	}
+	if _, err := service.RefreshTokens(context.Background(), pair.RefreshToken); err == nil {
+		t.Error("refresh token accepted twice")
+	}
+
+	// 删除会话即吊销令牌
+	_ = sessions.Delete(context.Background(), tokenHash(refreshed.AccessToken))
+	if _, err := service.ValidateToken(context.Background(), refreshed.AccessToken, AccessToken); err == nil {
+		t.Error("token accepted after its session ended")
+	}
+
+	sessions.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if _, err := service.ValidateToken(context.Background(), refreshed.RefreshToken, RefreshToken); err == nil {
+		t.Error("expired session accepted")
+	}
+}
+
+func TestClockSkewLeeway(t *testing.T) {
+	sign := func(issuedAt, expiresAt time.Time) string {
+		claims := Claims{
+			UserID:    "u1",
+			TokenType: string(AccessToken),
+			TokenID:   "t-" + issuedAt.String(),
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "gin-pkg",
+				IssuedAt:  jwt.NewNumericDate(issuedAt),
+				NotBefore: jwt.NewNumericDate(issuedAt),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("access"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	now := time.Now()
+	// 签发方时钟快 10 秒，以及刚过期 10 秒的令牌
+	early := sign(now.Add(10*time.Second), now.Add(time.Hour))
+	expired := sign(now.Add(-time.Hour), now.Add(-10*time.Second))
+
+	for _, leeway := range []time.Duration{0, 30 * time.Second} {
+		service := NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+			900, 3600, nil, "gin-pkg", "", leeway, nil, TokenEncryption{}, NewMemoryTokenStore(), nil)
+		for name, token := range map[string]string{"early": early, "expired": expired} {
+			_, err := service.ValidateToken(context.Background(), token, AccessToken)
+			if leeway == 0 && err == nil {
+				t.Errorf("%s token accepted without leeway", name)
+			}
+			if leeway > 0 && err != nil {
+				t.Errorf("%s token rejected with %s leeway: %v", name, leeway, err)
+			}
+		}
+	}
+}
+
+func TestTenantSecrets(t *testing.T) {
+	tenantSecrets := map[string]string{"acme": "acme-secret", "globex": "globex-secret"}
+	service := NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, tenantSecrets, TokenEncryption{}, NewMemoryTokenStore(), nil)
+
+	pair, err := service.GenerateTokenPairWithOptions(context.Background(), "u1", "a@example.com", []string{"user"}, TokenOptions{TenantID: "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := service.ValidateToken(context.Background(), pair.AccessToken, AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.TenantID != "acme" {
+		t.Errorf("tenant = %q, want acme", claims.TenantID)
+	}
+	refreshed, err := service.RefreshTokens(context.Background(), pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, _ := service.ValidateToken(context.Background(), refreshed.AccessToken, AccessToken); claims == nil || claims.TenantID != "acme" {
+		t.Error("refreshed token lost the tenant")
+	}
+
+	if _, err := service.GenerateTokenPairWithOptions(context.Background(), "u1", "a@example.com", nil, TokenOptions{TenantID: "initech"}); err == nil {
+		t.Error("token issued for an unknown tenant")
+	}
+
+	// 一个租户的密钥不能签发其他租户或默认密钥的令牌
+	forge := func(tenantID, secret string) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+			UserID:    "u2",
+			TokenType: string(AccessToken),
+			TenantID:  tenantID,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "gin-pkg",
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}).SignedString([]byte(secret))
+		return token
+	}
+	for name, token := range map[string]string{
+		"other tenant's secret":        forge("globex", "acme-secret"),
+		"default secret for a tenant":  forge("acme", "access"),
+		"tenant secret without tenant": forge("", "acme-secret"),
+		"unknown tenant":               forge("initech", "acme-secret"),
+	} {
+		if _, err := service.ValidateToken(context.Background(), token, AccessToken); err == nil {
+			t.Errorf("%s: forged token accepted", name)
+		}
+	}
+	if _, err := service.ValidateToken(context.Background(), forge("globex", "globex-secret"), AccessToken); err != nil {
+		t.Errorf("token of another tenant rejected: %v", err)
+	}
+}
+
+func TestTokenEncryption(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	service := NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, TokenEncryption{Key: key, AccessTokens: true}, NewMemoryTokenStore(), nil)
+
+	pair, err := service.GenerateTokenPair(context.Background(), "u1", "secret@example.com", []string{"admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(pair.AccessToken, ".") != 4 {
+		t.Fatalf("access token is not a compact JWE: %s", pair.AccessToken)
+	}
+	// 刷新令牌未启用加密，仍是普通的签名令牌
+	if strings.Count(pair.RefreshToken, ".") != 2 {
+		t.Errorf("refresh token was encrypted: %s", pair.RefreshToken)
+	}
+	claims, err := service.ValidateToken(context.Background(), pair.AccessToken, AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Email != "secret@example.com" {
+		t.Errorf("email = %q", claims.Email)
+	}
+	if _, err := service.RefreshTokens(context.Background(), pair.RefreshToken); err != nil {
+		t.Errorf("refresh failed: %v", err)
+	}
+
+	parts := strings.Split(pair.AccessToken, ".")
+	if parts[3][0] == 'A' {
+		parts[3] = "B" + parts[3][1:]
+	} else {
+		parts[3] = "A" + parts[3][1:]
+	}
+	otherKey := NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, TokenEncryption{Key: []byte("fedcba9876543210fedcba9876543210"), AccessTokens: true}, NewMemoryTokenStore(), nil)
+	plain := newTestService(NewMemoryTokenStore())
+	plainPair, _ := plain.GenerateTokenPair(context.Background(), "u1", "a@example.com", nil)
+	for name, validate := range map[string]func() error{
+		"tampered": func() error {
+			_, err := service.ValidateToken(context.Background(), strings.Join(parts, "."), AccessToken)
+			return err
+		},
+		"other key": func() error {
+			_, err := otherKey.ValidateToken(context.Background(), pair.AccessToken, AccessToken)
+			return err
+		},
+		"unencrypted": func() error {
+			_, err := service.ValidateToken(context.Background(), plainPair.AccessToken, AccessToken)
+			return err
+		},
+		"as plain JWT": func() error {
+			_, err := plain.ValidateToken(context.Background(), pair.AccessToken, AccessToken)
+			return err
+		},
+	} {
+		if validate() == nil {
+			t.Errorf("%s: token accepted", name)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrRefreshTokenRevoked is returned by RefreshTokenStore.Rotate for refresh tokens that are
+// not recorded, or were revoked or already rotated
+var ErrRefreshTokenRevoked = errors.New("refresh token has been revoked")
+
+// Reasons recorded for revoked refresh tokens
+const (
+	// RevokedRotated marks a refresh token exchanged for a new pair
+	RevokedRotated = "rotated"
+	// RevokedLogout marks the refresh token of a session ended by logging out
+	RevokedLogout = "logout"
+	// RevokedUser marks the refresh tokens revoked by RevokeUserTokens, e.g. after a
+	// password change
+	RevokedUser = "user_revoked"
+	// RevokedReused marks the refresh tokens of a session in which a rotated token was used
+	// again, a sign that the token was stolen
+	RevokedReused = "reused"
+)
+
+// RefreshTokenRecord is a refresh token as it is issued
+type RefreshTokenRecord struct {
+	// ID is the token ID (jti)
+	ID string
+	// SessionID is the ID of the first refresh token of the login; the tokens issued by
+	// refreshing carry it on
+	SessionID string
+	UserID    string
+	// TenantID is the tenant the token was issued for, empty for the default secrets
+	TenantID string
+	// TokenHash is the SHA-256 hash of the token, never the token itself
+	TokenHash      string
+	Device         Device
+	ImpersonatedBy string
+	ClientID       string
+	ExpiresAt      time.Time
+}
+
+// RefreshTokenStore records refresh tokens, so the sessions of a user can be listed and
+// revoked one by one with the reason kept for auditing. JWTService rejects refreshing with a
+// token whose record is missing or revoked, in addition to its own revocation checks.
+type RefreshTokenStore interface {
+	// Save records a refresh token as it is issued
+	Save(ctx context.Context, record RefreshTokenRecord) error
+	// Rotate marks a refresh token as used to issue a new pair and returns the device it was
+	// issued to. It fails with ErrRefreshTokenRevoked unless the token is recorded and
+	// neither revoked nor rotated; a rotated token used again revokes its whole session.
+	Rotate(ctx context.Context, tenantID, tokenID string) (Device, error)
+	// Revoke marks a refresh token revoked for reason
+	Revoke(ctx context.Context, tenantID, tokenID, reason string) error
+	// RevokeUser marks every refresh token of a user revoked for reason
+	RevokeUser(ctx context.Context, userID, reason string) error
+}
@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeRefreshTokenStore keeps refresh token records in memory, revoking like the database store
+type fakeRefreshTokenStore struct {
+	records map[string]RefreshTokenRecord
+	revoked map[string]string
+}
+
+func newFakeRefreshTokenStore() *fakeRefreshTokenStore {
+	return &fakeRefreshTokenStore{records: map[string]RefreshTokenRecord{}, revoked: map[string]string{}}
+}
+
+func (s *fakeRefreshTokenStore) Save(ctx context.Context, record RefreshTokenRecord) error {
+	s.records[record.ID] = record
+	return nil
+}
+
+func (s *fakeRefreshTokenStore) Rotate(ctx context.Context, tenantID, tokenID string) (Device, error) {
+	record, ok := s.records[tokenID]
+	if !ok {
+		return Device{}, ErrRefreshTokenRevoked
+	}
+	if reason, revoked := s.revoked[tokenID]; revoked {
+		if reason == RevokedRotated {
+			for id, other := range s.records {
+				if _, revoked := s.revoked[id]; !revoked && other.SessionID == record.SessionID {
+					s.revoked[id] = RevokedReused
+				}
+			}
+		}
+		return Device{}, ErrRefreshTokenRevoked
+	}
+	s.revoked[tokenID] = RevokedRotated
+	return record.Device, nil
+}
+
+func (s *fakeRefreshTokenStore) Revoke(ctx context.Context, tenantID, tokenID, reason string) error {
+	if _, revoked := s.revoked[tokenID]; !revoked {
+		s.revoked[tokenID] = reason
+	}
+	return nil
+}
+
+func (s *fakeRefreshTokenStore) RevokeUser(ctx context.Context, userID, reason string) error {
+	for id, record := range s.records {
+		if _, revoked := s.revoked[id]; !revoked && record.UserID == userID {
+			s.revoked[id] = reason
+		}
+	}
+	return nil
+}
+
+func newRecordingTestService(store *fakeRefreshTokenStore) TokenService {
+	return NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, TokenEncryption{}, NewMemoryTokenStore(), store)
+}
+
+func TestRefreshTokenStoreRecordsSessions(t *testing.T) {
+	store := newFakeRefreshTokenStore()
+	service := newRecordingTestService(store)
+	device := Device{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"}
+
+	pair, err := service.GenerateTokenPairWithOptions(context.Background(), "u1", "a@example.com", nil, TokenOptions{Device: device})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := service.ValidateToken(context.Background(), pair.RefreshToken, RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := store.records[claims.TokenID]
+	if record.SessionID != claims.TokenID || claims.SessionID != claims.TokenID || record.Device != device ||
+		record.TokenHash != tokenHash(pair.RefreshToken) || record.UserID != "u1" {
+		t.Errorf("login recorded %+v for claims %+v", record, claims)
+	}
+	if access, _ := service.ValidateToken(context.Background(), pair.AccessToken, AccessToken); access.SessionID != claims.SessionID {
+		t.Errorf("access token session = %q, want %q", access.SessionID, claims.SessionID)
+	}
+
+	// 刷新后的令牌属于同一会话，并沿用登录时的设备
+	refreshed, err := service.RefreshTokens(context.Background(), pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _ := service.ValidateToken(context.Background(), refreshed.RefreshToken, RefreshToken)
+	if next.SessionID != claims.SessionID || store.records[next.TokenID].Device != device {
+		t.Errorf("refreshed token recorded %+v, want session %s", store.records[next.TokenID], claims.SessionID)
+	}
+	if store.revoked[claims.TokenID] != RevokedRotated {
+		t.Errorf("used token revoked for %q, want %q", store.revoked[claims.TokenID], RevokedRotated)
+	}
+
+	// 再次使用已轮换的令牌视为被盗用，整个会话被吊销
+	if _, err := service.RefreshTokens(context.Background(), pair.RefreshToken); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("reused refresh token error = %v, want ErrRefreshTokenRevoked", err)
+	}
+	if store.revoked[next.TokenID] != RevokedReused {
+		t.Errorf("token of reused session revoked for %q, want %q", store.revoked[next.TokenID], RevokedReused)
+	}
+	if _, err := service.RefreshTokens(context.Background(), refreshed.RefreshToken); err == nil {
+		t.Error("refresh token of a revoked session accepted")
+	}
+}
+
+func TestRefreshTokenStoreRevocations(t *testing.T) {
+	store := newFakeRefreshTokenStore()
+	service := newRecordingTestService(store)
+
+	first, _ := service.GenerateTokenPair(context.Background(), "u1", "a@example.com", nil)
+	second, _ := service.GenerateTokenPair(context.Background(), "u1", "a@example.com", nil)
+	firstClaims, _ := service.ValidateToken(context.Background(), first.RefreshToken, RefreshToken)
+	secondClaims, _ := service.ValidateToken(context.Background(), second.RefreshToken, RefreshToken)
+	if firstClaims.SessionID == secondClaims.SessionID {
+		t.Fatal("two logins share a session")
+	}
+
+	if err := service.RevokeRefreshToken(context.Background(), firstClaims, RevokedLogout); err != nil {
+		t.Fatal(err)
+	}
+	if store.revoked[firstClaims.TokenID] != RevokedLogout {
+		t.Errorf("logged out token revoked for %q", store.revoked[firstClaims.TokenID])
+	}
+	if _, err := service.RefreshTokens(context.Background(), first.RefreshToken); err == nil {
+		t.Error("refresh token accepted after logout")
+	}
+
+	if err := service.RevokeUserTokens(context.Background(), "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if store.revoked[secondClaims.TokenID] != RevokedUser {
+		t.Errorf("token revoked for %q after RevokeUserTokens, want %q", store.revoked[secondClaims.TokenID], RevokedUser)
+	}
+
+	// 启用记录前签发的令牌没有会话，刷新时开始新的会话
+	unrecorded, _ := newTestService(NewMemoryTokenStore()).GenerateTokenPair(context.Background(), "u2", "b@example.com", nil)
+	refreshed, err := service.RefreshTokens(context.Background(), unrecorded.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh token issued before recording rejected: %v", err)
+	}
+	claims, _ := service.ValidateToken(context.Background(), refreshed.RefreshToken, RefreshToken)
+	if claims.SessionID == "" || store.records[claims.TokenID].SessionID != claims.SessionID {
+		t.Errorf("refreshing an unrecorded token did not start a session: %+v", claims)
+	}
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/util"
+)
+
+// SessionStore keeps the claims of opaque tokens, keyed by the SHA-256 hash of the token so
+// a leaked store does not leak usable tokens
+type SessionStore interface {
+	// Save stores the encoded claims of a token until expiration
+	Save(ctx context.Context, tokenHash string, claims []byte, expiration time.Duration) error
+	// Load returns the encoded claims of a token, or nil if the session does not exist or has expired
+	Load(ctx context.Context, tokenHash string) ([]byte, error)
+	// Delete ends the session of a token
+	Delete(ctx context.Context, tokenHash string) error
+}
+
+// RedisSessionStore keeps opaque token sessions in Redis, shared by every instance of the service
+type RedisSessionStore struct {
+	client *util.RedisClient
+}
+
+// NewRedisSessionStore creates a session store backed by Redis
+func NewRedisSessionStore(client *util.RedisClient) *RedisSessionStore {
+	return &RedisSessionStore{client: client}
+}
+
+func (s *RedisSessionStore) Save(ctx context.Context, tokenHash string, claims []byte, expiration time.Duration) error {
+	return s.client.StoreSession(ctx, tokenHash, string(claims), expiration)
+}
+
+func (s *RedisSessionStore) Load(ctx context.Context, tokenHash string) ([]byte, error) {
+	claims, err := s.client.GetSession(ctx, tokenHash)
+	if err != nil || claims == "" {
+		return nil, err
+	}
+	return []byte(claims), nil
+}
+
+func (s *RedisSessionStore) Delete(ctx context.Context, tokenHash string) error {
+	return s.client.DeleteSession(ctx, tokenHash)
+}
+
+// MemorySessionStore keeps opaque token sessions in process memory, for tests and
+// single-instance deployments without Redis. Every session is lost on restart.
+type MemorySessionStore struct {
+	mu        sync.Mutex
+	sessions  map[string]memorySession
+	lastPurge time.Time
+	// now returns the current time; tests replace it to expire sessions
+	now func() time.Time
+}
+
+// memorySession is the encoded claims of a token and the time they are forgotten
+type memorySession struct {
+	claims    []byte
+	expiresAt time.Time
+}
+
+// NewMemorySessionStore creates an empty in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]memorySession),
+		now:      time.Now,
+	}
+}
+
+func (s *MemorySessionStore) Save(ctx context.Context, tokenHash string, claims []byte, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge()
+	s.sessions[tokenHash] = memorySession{claims: claims, expiresAt: s.now().Add(expiration)}
+	return nil
+}
+
+func (s *MemorySessionStore) Load(ctx context.Context, tokenHash string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[tokenHash]
+	if !ok || !s.now().Before(session.expiresAt) {
+		return nil, nil
+	}
+	return session.claims, nil
+}
+
+func (s *MemorySessionStore) Delete(ctx context.Context, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, tokenHash)
+	return nil
+}
+
+// purge drops expired sessions at most once a minute. Callers hold mu.
+func (s *MemorySessionStore) purge() {
+	now := s.now()
+	if now.Sub(s.lastPurge) < time.Minute {
+		return
+	}
+	s.lastPurge = now
+	for hash, session := range s.sessions {
+		if !now.Before(session.expiresAt) {
+			delete(s.sessions, hash)
+		}
+	}
+}
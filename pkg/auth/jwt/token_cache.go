@@ -0,0 +1,319 @@
+package jwt
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Revocation events published by CachedTokenStore, as "<kind>:<id>"
+const (
+	revocationEventToken = "token"
+	revocationEventUser  = "user"
+)
+
+// CachedTokenStore caches the lookups ValidateToken makes on every request, so hot paths do
+// not pay a round trip to the underlying store per call. Revocations made through the cache
+// take effect locally at once; other instances see them once their cached lookups expire
+// after TTL, or at once when Publish and HandleRevocationEvent are wired to a broadcast
+// channel such as Redis pub/sub.
+type CachedTokenStore struct {
+	store   TokenStore
+	ttl     time.Duration
+	publish func(ctx context.Context, event string) error
+
+	mu          sync.Mutex
+	blacklist   map[string]cachedBlacklisted
+	revocations map[string]cachedTime
+	bans        map[string]cachedTime
+	// generation counts the invalidations of cached lookups. Lookups note it before querying
+	// the store and only cache their result if it has not changed meanwhile, so a result read
+	// before a revocation is not cached after it.
+	generation uint64
+	lastPurge  time.Time
+	// now returns the current time; tests replace it to expire entries
+	now func() time.Time
+}
+
+// CachedTokenStoreOptions configures a CachedTokenStore
+type CachedTokenStoreOptions struct {
+	// TTL is how long a lookup is reused, and so the longest a revocation made on another
+	// instance can go unnoticed without Publish
+	TTL time.Duration
+	// Publish, when set, broadcasts revocation events to the other instances, which pass
+	// them to HandleRevocationEvent
+	Publish func(ctx context.Context, event string) error
+}
+
+// cachedBlacklisted is a cached IsBlacklisted result and the time it is looked up again
+type cachedBlacklisted struct {
+	blacklisted bool
+	expiresAt   time.Time
+}
+
+// cachedTime is a cached UserRevokedAt or UserBlacklistedUntil result and the time it is
+// looked up again
+type cachedTime struct {
+	at        time.Time
+	expiresAt time.Time
+}
+
+// NewCachedTokenStore wraps store with an in-process cache of its lookups
+func NewCachedTokenStore(store TokenStore, options CachedTokenStoreOptions) *CachedTokenStore {
+	if options.TTL <= 0 {
+		options.TTL = 5 * time.Second
+	}
+	return &CachedTokenStore{
+		store:       store,
+		ttl:         options.TTL,
+		publish:     options.Publish,
+		blacklist:   make(map[string]cachedBlacklisted),
+		revocations: make(map[string]cachedTime),
+		bans:        make(map[string]cachedTime),
+		now:         time.Now,
+	}
+}
+
+func (s *CachedTokenStore) Blacklist(ctx context.Context, tokenID string, expiration time.Duration) error {
+	if err := s.store.Blacklist(ctx, tokenID, expiration); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.purge()
+	s.blacklist[tokenID] = cachedBlacklisted{blacklisted: true, expiresAt: s.now().Add(s.ttl)}
+	s.mu.Unlock()
+	s.broadcast(ctx, revocationEventToken, tokenID)
+	return nil
+}
+
+func (s *CachedTokenStore) IsBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	s.mu.Lock()
+	cached, ok := s.blacklist[tokenID]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && s.now().Before(cached.expiresAt) {
+		return cached.blacklisted, nil
+	}
+
+	blacklisted, err := s.store.IsBlacklisted(ctx, tokenID)
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	s.purge()
+	// 查询期间令牌可能已被本实例拉黑，不能用查询前的结果覆盖
+	if s.generation == generation && !s.blacklist[tokenID].blacklisted {
+		s.blacklist[tokenID] = cachedBlacklisted{blacklisted: blacklisted, expiresAt: s.now().Add(s.ttl)}
+	}
+	s.mu.Unlock()
+	return blacklisted, nil
+}
+
+func (s *CachedTokenStore) RevokeUser(ctx context.Context, userID string, revokedAt time.Time, expiration time.Duration) error {
+	if err := s.store.RevokeUser(ctx, userID, revokedAt, expiration); err != nil {
+		return err
+	}
+	// 丢弃缓存而不是写入 revokedAt，下次查询时读取底层存储按其精度保存的值
+	s.forgetUser(userID)
+	s.broadcast(ctx, revocationEventUser, userID)
+	return nil
+}
+
+func (s *CachedTokenStore) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	s.mu.Lock()
+	cached, ok := s.revocations[userID]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && s.now().Before(cached.expiresAt) {
+		return cached.at, nil
+	}
+
+	revokedAt, err := s.store.UserRevokedAt(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	s.mu.Lock()
+	s.purge()
+	if s.generation == generation {
+		s.revocations[userID] = cachedTime{at: revokedAt, expiresAt: s.now().Add(s.ttl)}
+	}
+	s.mu.Unlock()
+	return revokedAt, nil
+}
+
+func (s *CachedTokenStore) BlacklistUser(ctx context.Context, userID string, until time.Time) error {
+	if err := s.store.BlacklistUser(ctx, userID, until); err != nil {
+		return err
+	}
+	s.forgetUser(userID)
+	s.broadcast(ctx, revocationEventUser, userID)
+	return nil
+}
+
+func (s *CachedTokenStore) UserBlacklistedUntil(ctx context.Context, userID string) (time.Time, error) {
+	s.mu.Lock()
+	cached, ok := s.bans[userID]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && s.now().Before(cached.expiresAt) {
+		return cached.at, nil
+	}
+
+	until, err := s.store.UserBlacklistedUntil(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	s.mu.Lock()
+	s.purge()
+	if s.generation == generation {
+		s.bans[userID] = cachedTime{at: until, expiresAt: s.now().Add(s.ttl)}
+	}
+	s.mu.Unlock()
+	return until, nil
+}
+
+// Revocation answers from the cached lookups when they are all fresh. Otherwise it looks up
+// the token and user in one call if the underlying store implements RevocationLookup, caching
+// every result, and falls back to the cached IsBlacklisted, UserRevokedAt and
+// UserBlacklistedUntil lookups if not.
+func (s *CachedTokenStore) Revocation(ctx context.Context, tokenID, userID string) (Revocation, error) {
+	revocation, generation, ok := s.cachedRevocation(tokenID, userID)
+	if ok {
+		return revocation, nil
+	}
+	lookup, ok := s.store.(RevocationLookup)
+	if !ok {
+		return lookupRevocationSeparately(ctx, s, tokenID, userID)
+	}
+
+	revocation, err := lookup.Revocation(ctx, tokenID, userID)
+	if err != nil {
+		return Revocation{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge()
+	// 查询期间令牌可能已被本实例拉黑，不能用查询前的结果覆盖
+	locallyBlacklisted := s.blacklist[tokenID].blacklisted
+	if locallyBlacklisted {
+		revocation.Blacklisted = true
+	}
+	// The result predates a revocation made during the lookup, so it is not cached
+	if s.generation != generation {
+		return revocation, nil
+	}
+	expiresAt := s.now().Add(s.ttl)
+	if !locallyBlacklisted {
+		s.blacklist[tokenID] = cachedBlacklisted{blacklisted: revocation.Blacklisted, expiresAt: expiresAt}
+	}
+	if userID != "" {
+		s.revocations[userID] = cachedTime{at: revocation.UserRevokedAt, expiresAt: expiresAt}
+		s.bans[userID] = cachedTime{at: revocation.UserBlacklistedUntil, expiresAt: expiresAt}
+	}
+	return revocation, nil
+}
+
+// cachedRevocation returns the revocation state of a token and its user if every lookup it
+// needs is cached and fresh, and the generation to look them up in otherwise
+func (s *CachedTokenStore) cachedRevocation(tokenID, userID string) (Revocation, uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	blacklisted, ok := s.blacklist[tokenID]
+	if !ok || !now.Before(blacklisted.expiresAt) {
+		return Revocation{}, s.generation, false
+	}
+	revocation := Revocation{Blacklisted: blacklisted.blacklisted}
+	if revocation.Blacklisted || userID == "" {
+		return revocation, s.generation, true
+	}
+	revokedAt, ok := s.revocations[userID]
+	if !ok || !now.Before(revokedAt.expiresAt) {
+		return Revocation{}, s.generation, false
+	}
+	until, ok := s.bans[userID]
+	if !ok || !now.Before(until.expiresAt) {
+		return Revocation{}, s.generation, false
+	}
+	revocation.UserRevokedAt = revokedAt.at
+	revocation.UserBlacklistedUntil = until.at
+	return revocation, s.generation, true
+}
+
+// HandleRevocationEvent drops the cached lookups affected by a revocation event published
+// by another instance. Unknown events are ignored.
+func (s *CachedTokenStore) HandleRevocationEvent(event string) {
+	kind, id, ok := strings.Cut(event, ":")
+	if !ok {
+		return
+	}
+	switch kind {
+	case revocationEventToken:
+		s.mu.Lock()
+		delete(s.blacklist, id)
+		s.generation++
+		s.mu.Unlock()
+	case revocationEventUser:
+		s.forgetUser(id)
+	}
+}
+
+// forgetUser drops the cached revocation and blacklist times of a user, and keeps lookups
+// running meanwhile from caching what they read before
+func (s *CachedTokenStore) forgetUser(userID string) {
+	s.mu.Lock()
+	delete(s.revocations, userID)
+	delete(s.bans, userID)
+	s.generation++
+	s.mu.Unlock()
+}
+
+// Flush drops every cached lookup. Subscribers call it when they may have missed revocation
+// events, e.g. after reconnecting to the broadcast channel.
+func (s *CachedTokenStore) Flush() {
+	s.mu.Lock()
+	clear(s.blacklist)
+	clear(s.revocations)
+	clear(s.bans)
+	s.generation++
+	s.mu.Unlock()
+}
+
+// broadcast publishes a revocation event; a failure only delays other instances until
+// their cached lookups expire, so it does not fail the revocation
+func (s *CachedTokenStore) broadcast(ctx context.Context, kind, id string) {
+	if s.publish == nil {
+		return
+	}
+	if err := s.publish(ctx, kind+":"+id); err != nil {
+		logger.FromContext(ctx).Warnf("Failed to publish %s revocation of %s, other instances notice it within %s: %v", kind, id, s.ttl, err)
+	}
+}
+
+// purge drops expired lookups at most once per TTL so the maps do not grow without bound.
+// Callers hold mu.
+func (s *CachedTokenStore) purge() {
+	now := s.now()
+	if now.Sub(s.lastPurge) < s.ttl {
+		return
+	}
+	s.lastPurge = now
+	for id, cached := range s.blacklist {
+		if !now.Before(cached.expiresAt) {
+			delete(s.blacklist, id)
+		}
+	}
+	for id, cached := range s.revocations {
+		if !now.Before(cached.expiresAt) {
+			delete(s.revocations, id)
+		}
+	}
+	for id, cached := range s.bans {
+		if !now.Before(cached.expiresAt) {
+			delete(s.bans, id)
+		}
+	}
+}
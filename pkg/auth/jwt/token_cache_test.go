@@ -0,0 +1,269 @@
+package jwt
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// remoteTokenStore counts lookups and adds a delay standing in for a Redis round trip
+type remoteTokenStore struct {
+	*MemoryTokenStore
+	latency time.Duration
+	lookups atomic.Int64
+}
+
+func newRemoteTokenStore(latency time.Duration) *remoteTokenStore {
+	return &remoteTokenStore{MemoryTokenStore: NewMemoryTokenStore(), latency: latency}
+}
+
+func (s *remoteTokenStore) IsBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	s.lookups.Add(1)
+	time.Sleep(s.latency)
+	return s.MemoryTokenStore.IsBlacklisted(ctx, tokenID)
+}
+
+func (s *remoteTokenStore) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	s.lookups.Add(1)
+	time.Sleep(s.latency)
+	return s.MemoryTokenStore.UserRevokedAt(ctx, userID)
+}
+
+func (s *remoteTokenStore) UserBlacklistedUntil(ctx context.Context, userID string) (time.Time, error) {
+	s.lookups.Add(1)
+	time.Sleep(s.latency)
+	return s.MemoryTokenStore.UserBlacklistedUntil(ctx, userID)
+}
+
+// batchedTokenStore is a remoteTokenStore that looks up revocations in a single round trip,
+// like RedisTokenStore
+type batchedTokenStore struct {
+	*remoteTokenStore
+}
+
+func (s batchedTokenStore) Revocation(ctx context.Context, tokenID, userID string) (Revocation, error) {
+	s.lookups.Add(1)
+	time.Sleep(s.latency)
+	return lookupRevocationSeparately(ctx, s.MemoryTokenStore, tokenID, userID)
+}
+
+func TestCachedTokenStore(t *testing.T) {
+	remote := newRemoteTokenStore(0)
+	var published []string
+	cache := NewCachedTokenStore(remote, CachedTokenStoreOptions{
+		TTL:     time.Second,
+		Publish: func(ctx context.Context, event string) error { published = append(published, event); return nil },
+	})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := cache.IsBlacklisted(context.Background(), "t1"); ok {
+			t.Fatal("unknown token reported as blacklisted")
+		}
+		if got, _ := cache.UserRevokedAt(context.Background(), "u1"); !got.IsZero() {
+			t.Fatalf("UserRevokedAt = %v", got)
+		}
+	}
+	if n := remote.lookups.Load(); n != 2 {
+		t.Errorf("%d lookups reached the store, want 2", n)
+	}
+
+	// 本实例的吊销立即生效
+	_ = cache.Blacklist(context.Background(), "t1", time.Minute)
+	if ok, _ := cache.IsBlacklisted(context.Background(), "t1"); !ok {
+		t.Error("token blacklisted through the cache accepted")
+	}
+	_ = cache.RevokeUser(context.Background(), "u1", now, time.Hour)
+	if got, _ := cache.UserRevokedAt(context.Background(), "u1"); got.IsZero() {
+		t.Error("revocation through the cache not visible")
+	}
+	if len(published) != 2 || published[0] != "token:t1" || published[1] != "user:u1" {
+		t.Errorf("published %v", published)
+	}
+
+	// 其他实例的吊销在收到事件或缓存过期后生效
+	_, _ = cache.IsBlacklisted(context.Background(), "t2")
+	_ = remote.Blacklist(context.Background(), "t2", time.Minute)
+	if ok, _ := cache.IsBlacklisted(context.Background(), "t2"); ok {
+		t.Fatal("cached lookup not used")
+	}
+	cache.HandleRevocationEvent("token:t2")
+	if ok, _ := cache.IsBlacklisted(context.Background(), "t2"); !ok {
+		t.Error("revocation event did not drop the cached lookup")
+	}
+
+	_, _ = cache.IsBlacklisted(context.Background(), "t3")
+	_ = remote.Blacklist(context.Background(), "t3", time.Minute)
+	now = now.Add(time.Second)
+	if ok, _ := cache.IsBlacklisted(context.Background(), "t3"); !ok {
+		t.Error("cached lookup outlived its TTL")
+	}
+}
+
+func TestCachedTokenStoreRevocation(t *testing.T) {
+	ctx := context.Background()
+	remote := newRemoteTokenStore(0)
+	cache := NewCachedTokenStore(batchedTokenStore{remote}, CachedTokenStoreOptions{TTL: time.Second})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if revocation, err := cache.Revocation(ctx, "t1", "u1"); err != nil || revocation != (Revocation{}) {
+			t.Fatalf("Revocation = %+v, %v, want none", revocation, err)
+		}
+	}
+	if n := remote.lookups.Load(); n != 1 {
+		t.Errorf("%d lookups reached the store, want 1", n)
+	}
+	// The batched lookup fills the caches of the single lookups too
+	_, _ = cache.IsBlacklisted(ctx, "t1")
+	_, _ = cache.UserBlacklistedUntil(ctx, "u1")
+	if n := remote.lookups.Load(); n != 1 {
+		t.Errorf("%d lookups reached the store, want the cached ones reused", n)
+	}
+
+	until := now.Add(time.Hour)
+	_ = cache.BlacklistUser(ctx, "u1", until)
+	if revocation, _ := cache.Revocation(ctx, "t1", "u1"); revocation.UserBlacklistedUntil.Unix() != until.Unix() {
+		t.Errorf("UserBlacklistedUntil = %v, want %v", revocation.UserBlacklistedUntil, until)
+	}
+	_ = cache.Blacklist(ctx, "t2", time.Minute)
+	if revocation, _ := cache.Revocation(ctx, "t2", "u2"); !revocation.Blacklisted {
+		t.Error("token blacklisted through the cache accepted")
+	}
+}
+
+func TestCachedTokenStoreFlush(t *testing.T) {
+	ctx := context.Background()
+	remote := newRemoteTokenStore(0)
+	cache := NewCachedTokenStore(remote, CachedTokenStoreOptions{TTL: time.Hour})
+
+	_, _ = cache.IsBlacklisted(ctx, "t1")
+	_, _ = cache.UserBlacklistedUntil(ctx, "u1")
+	// Revocations made elsewhere while the events were missed
+	_ = remote.Blacklist(ctx, "t1", time.Minute)
+	until := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	_ = remote.BlacklistUser(ctx, "u1", until)
+
+	cache.Flush()
+	if ok, _ := cache.IsBlacklisted(ctx, "t1"); !ok {
+		t.Error("Flush did not drop the cached token lookup")
+	}
+	if got, _ := cache.UserBlacklistedUntil(ctx, "u1"); !got.Equal(until) {
+		t.Errorf("UserBlacklistedUntil after Flush = %v, want %v", got, until)
+	}
+}
+
+// blockingTokenStore holds its lookups after reading the store until release is closed, so
+// revocations can be made while a lookup is in flight
+type blockingTokenStore struct {
+	*MemoryTokenStore
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingTokenStore) wait() {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	<-s.release
+}
+
+func (s *blockingTokenStore) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	revokedAt, err := s.MemoryTokenStore.UserRevokedAt(ctx, userID)
+	s.wait()
+	return revokedAt, err
+}
+
+func (s *blockingTokenStore) Revocation(ctx context.Context, tokenID, userID string) (Revocation, error) {
+	revocation, err := lookupRevocationSeparately(ctx, s.MemoryTokenStore, tokenID, userID)
+	s.wait()
+	return revocation, err
+}
+
+func TestCachedTokenStoreRevokeDuringLookup(t *testing.T) {
+	lookups := map[string]func(ctx context.Context, cache *CachedTokenStore) (time.Time, error){
+		"UserRevokedAt": func(ctx context.Context, cache *CachedTokenStore) (time.Time, error) {
+			return cache.UserRevokedAt(ctx, "u1")
+		},
+		"Revocation": func(ctx context.Context, cache *CachedTokenStore) (time.Time, error) {
+			revocation, err := cache.Revocation(ctx, "t1", "u1")
+			return revocation.UserRevokedAt, err
+		},
+	}
+	for name, lookup := range lookups {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := &blockingTokenStore{
+				MemoryTokenStore: NewMemoryTokenStore(),
+				started:          make(chan struct{}, 1),
+				release:          make(chan struct{}),
+			}
+			cache := NewCachedTokenStore(store, CachedTokenStoreOptions{TTL: time.Hour})
+
+			done := make(chan time.Time)
+			go func() {
+				revokedAt, _ := lookup(ctx, cache)
+				done <- revokedAt
+			}()
+			<-store.started
+			// The lookup has read the state before the revocation and not cached it yet
+			revokedAt := time.Unix(time.Now().Unix(), 0)
+			if err := cache.RevokeUser(ctx, "u1", revokedAt, time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			close(store.release)
+			if got := <-done; !got.IsZero() {
+				t.Fatalf("in-flight lookup = %v, want the state before the revocation", got)
+			}
+
+			if got, err := lookup(ctx, cache); err != nil || !got.Equal(revokedAt) {
+				t.Errorf("lookup after the revocation = %v, %v, want %v", got, err, revokedAt)
+			}
+		})
+	}
+}
+
+// BenchmarkValidateToken compares token validation against a store with a 100µs round trip,
+// roughly a Redis call within a data center, looking up each revocation separately, in one
+// batched round trip, and with the cache:
+//
+//	go test ./pkg/auth/jwt -bench ValidateToken -benchmem
+func BenchmarkValidateToken(b *testing.B) {
+	for _, bench := range []struct {
+		name    string
+		batched bool
+		cached  bool
+	}{
+		{"uncached", false, false},
+		{"batched", true, false},
+		{"cached", true, true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			remote := newRemoteTokenStore(100 * time.Microsecond)
+			var store TokenStore = remote
+			if bench.batched {
+				store = batchedTokenStore{remote}
+			}
+			if bench.cached {
+				store = NewCachedTokenStore(store, CachedTokenStoreOptions{TTL: 5 * time.Second})
+			}
+			service := newTestService(store)
+			pair, err := service.GenerateTokenPair(context.Background(), "u1", "a@example.com", []string{"user"})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := service.ValidateToken(context.Background(), pair.AccessToken, AccessToken); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(remote.lookups.Load())/float64(b.N), "lookups/op")
+		})
+	}
+}
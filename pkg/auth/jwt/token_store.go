@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/util"
+)
+
+// TokenStore keeps the revocation state JWTService checks on every validated token
+type TokenStore interface {
+	// Blacklist revokes a single token until expiration, when it would have expired anyway
+	Blacklist(ctx context.Context, tokenID string, expiration time.Duration) error
+	// IsBlacklisted reports whether a token was revoked with Blacklist
+	IsBlacklisted(ctx context.Context, tokenID string) (bool, error)
+	// RevokeUser revokes every token of a user issued at or before revokedAt,
+	// remembering it for expiration
+	RevokeUser(ctx context.Context, userID string, revokedAt time.Time, expiration time.Duration) error
+	// UserRevokedAt returns the time recorded by RevokeUser, or the zero time if none
+	UserRevokedAt(ctx context.Context, userID string) (time.Time, error)
+	// BlacklistUser rejects every token of a user, including ones issued later, until the
+	// given time. A zero or past time lifts the blacklist.
+	BlacklistUser(ctx context.Context, userID string, until time.Time) error
+	// UserBlacklistedUntil returns the time recorded by BlacklistUser, or the zero time if none
+	UserBlacklistedUntil(ctx context.Context, userID string) (time.Time, error)
+}
+
+// Revocation is the revocation state of a token and its user that ValidateToken checks
+type Revocation struct {
+	// Blacklisted reports whether the token was revoked with Blacklist
+	Blacklisted bool
+	// UserRevokedAt and UserBlacklistedUntil are the times recorded by RevokeUser and
+	// BlacklistUser, or the zero time if none
+	UserRevokedAt        time.Time
+	UserBlacklistedUntil time.Time
+}
+
+// RevocationLookup is implemented by token stores that can look up the whole revocation state
+// of a token at once, e.g. in a single round trip, instead of one IsBlacklisted, UserRevokedAt
+// and UserBlacklistedUntil call each
+type RevocationLookup interface {
+	// Revocation returns the revocation state of a token and of its user; an empty userID only
+	// looks up the token
+	Revocation(ctx context.Context, tokenID, userID string) (Revocation, error)
+}
+
+// lookupRevocation returns the revocation state of a token and its user, at once if store
+// implements RevocationLookup and otherwise call by call
+func lookupRevocation(ctx context.Context, store TokenStore, tokenID, userID string) (Revocation, error) {
+	if lookup, ok := store.(RevocationLookup); ok {
+		return lookup.Revocation(ctx, tokenID, userID)
+	}
+	return lookupRevocationSeparately(ctx, store, tokenID, userID)
+}
+
+// lookupRevocationSeparately returns the revocation state of a token and its user call by
+// call, skipping the user once the token is blacklisted
+func lookupRevocationSeparately(ctx context.Context, store TokenStore, tokenID, userID string) (Revocation, error) {
+	var revocation Revocation
+	var err error
+	if revocation.Blacklisted, err = store.IsBlacklisted(ctx, tokenID); err != nil || revocation.Blacklisted || userID == "" {
+		return revocation, err
+	}
+	if revocation.UserRevokedAt, err = store.UserRevokedAt(ctx, userID); err != nil {
+		return revocation, err
+	}
+	revocation.UserBlacklistedUntil, err = store.UserBlacklistedUntil(ctx, userID)
+	return revocation, err
+}
+
+// RedisTokenStore keeps the revocation state in Redis, shared by every instance of the service
+type RedisTokenStore struct {
+	client *util.RedisClient
+}
+
+// NewRedisTokenStore creates a token store backed by Redis
+func NewRedisTokenStore(client *util.RedisClient) *RedisTokenStore {
+	return &RedisTokenStore{client: client}
+}
+
+func (s *RedisTokenStore) Blacklist(ctx context.Context, tokenID string, expiration time.Duration) error {
+	return s.client.BlacklistToken(ctx, tokenID, expiration)
+}
+
+func (s *RedisTokenStore) IsBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	return s.client.IsTokenBlacklisted(ctx, tokenID)
+}
+
+func (s *RedisTokenStore) RevokeUser(ctx context.Context, userID string, revokedAt time.Time, expiration time.Duration) error {
+	return s.client.RevokeUserTokens(ctx, userID, revokedAt, expiration)
+}
+
+func (s *RedisTokenStore) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	return s.client.UserTokensRevokedAt(ctx, userID)
+}
+
+func (s *RedisTokenStore) BlacklistUser(ctx context.Context, userID string, until time.Time) error {
+	return s.client.BlacklistUser(ctx, userID, until)
+}
+
+func (s *RedisTokenStore) UserBlacklistedUntil(ctx context.Context, userID string) (time.Time, error) {
+	return s.client.UserBlacklistedUntil(ctx, userID)
+}
+
+// Revocation looks up the token and user in a single pipelined round trip
+func (s *RedisTokenStore) Revocation(ctx context.Context, tokenID, userID string) (Revocation, error) {
+	revocation, err := s.client.TokenRevocation(ctx, tokenID, userID)
+	if err != nil {
+		return Revocation{}, err
+	}
+	return Revocation(revocation), nil
+}
+
+// MemoryTokenStore keeps the revocation state in process memory, for tests and single-instance
+// deployments without Redis. Revocations are lost on restart and not shared between instances.
+type MemoryTokenStore struct {
+	mu          sync.Mutex
+	blacklist   map[string]time.Time
+	revocations map[string]memoryRevocation
+	// blacklistedUsers maps user IDs to the time their blacklist ends
+	blacklistedUsers map[string]time.Time
+	lastPurge        time.Time
+	// now returns the current time; tests replace it to expire entries
+	now func() time.Time
+}
+
+// memoryRevocation is a RevokeUser marker and the time it is forgotten
+type memoryRevocation struct {
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+// NewMemoryTokenStore creates an empty in-memory token store
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		blacklist:        make(map[string]time.Time),
+		revocations:      make(map[string]memoryRevocation),
+		blacklistedUsers: make(map[string]time.Time),
+		now:              time.Now,
+	}
+}
+
+func (s *MemoryTokenStore) Blacklist(ctx context.Context, tokenID string, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge()
+	s.blacklist[tokenID] = s.now().Add(expiration)
+	return nil
+}
+
+func (s *MemoryTokenStore) IsBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.blacklist[tokenID]
+	return ok && s.now().Before(expiresAt), nil
+}
+
+func (s *MemoryTokenStore) RevokeUser(ctx context.Context, userID string, revokedAt time.Time, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge()
+	s.revocations[userID] = memoryRevocation{revokedAt: revokedAt, expiresAt: s.now().Add(expiration)}
+	return nil
+}
+
+func (s *MemoryTokenStore) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revocation, ok := s.revocations[userID]
+	if !ok || !s.now().Before(revocation.expiresAt) {
+		return time.Time{}, nil
+	}
+	// Redis 中以秒为单位保存，保持相同精度
+	return time.Unix(revocation.revokedAt.Unix(), 0), nil
+}
+
+func (s *MemoryTokenStore) BlacklistUser(ctx context.Context, userID string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge()
+	if !s.now().Before(until) {
+		delete(s.blacklistedUsers, userID)
+		return nil
+	}
+	s.blacklistedUsers[userID] = until
+	return nil
+}
+
+func (s *MemoryTokenStore) UserBlacklistedUntil(ctx context.Context, userID string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	until, ok := s.blacklistedUsers[userID]
+	if !ok || !s.now().Before(until) {
+		return time.Time{}, nil
+	}
+	return time.Unix(until.Unix(), 0), nil
+}
+
+// purge drops expired entries at most once a minute so the maps do not grow without bound.
+// Callers hold mu.
+func (s *MemoryTokenStore) purge() {
+	now := s.now()
+	if now.Sub(s.lastPurge) < time.Minute {
+		return
+	}
+	s.lastPurge = now
+	for id, expiresAt := range s.blacklist {
+		if !now.Before(expiresAt) {
+			delete(s.blacklist, id)
+		}
+	}
+	for id, revocation := range s.revocations {
+		if !now.Before(revocation.expiresAt) {
+			delete(s.revocations, id)
+		}
+	}
+	for id, until := range s.blacklistedUsers {
+		if !now.Before(until) {
+			delete(s.blacklistedUsers, id)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestService(store TokenStore) TokenService {
+	return NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, map[string][]string{"user": {"profile:read"}}, "gin-pkg", "", 0, nil, TokenEncryption{}, store, nil)
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	store := NewMemoryTokenStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	if err := store.Blacklist(context.Background(), "t1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.IsBlacklisted(context.Background(), "t1"); !ok {
+		t.Error("blacklisted token not reported")
+	}
+	if ok, _ := store.IsBlacklisted(context.Background(), "t2"); ok {
+		t.Error("unknown token reported as blacklisted")
+	}
+
+	revokedAt := now.Add(-time.Second)
+	if err := store.RevokeUser(context.Background(), "u1", revokedAt, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.UserRevokedAt(context.Background(), "u1"); got.Unix() != revokedAt.Unix() {
+		t.Errorf("UserRevokedAt = %v, want %v", got, revokedAt)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if ok, _ := store.IsBlacklisted(context.Background(), "t1"); ok {
+		t.Error("blacklist entry outlived its expiration")
+	}
+	now = now.Add(time.Hour)
+	if got, _ := store.UserRevokedAt(context.Background(), "u1"); !got.IsZero() {
+		t.Errorf("revocation outlived its expiration: %v", got)
+	}
+
+	// 写入时清理过期条目
+	_ = store.Blacklist(context.Background(), "t3", time.Minute)
+	if len(store.blacklist) != 1 || len(store.revocations) != 0 {
+		t.Errorf("expired entries kept: %d blacklisted, %d revocations", len(store.blacklist), len(store.revocations))
+	}
+}
+
+func TestJWTServiceRevocation(t *testing.T) {
+	service := newTestService(NewMemoryTokenStore())
+
+	pair, err := service.GenerateTokenPair(context.Background(), "u1", "a@example.com", []string{"user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := service.ValidateToken(context.Background(), pair.AccessToken, AccessToken)
+	if err != nil {
+		t.Fatalf("fresh token rejected: %v", err)
+	}
+
+	if err := service.BlacklistToken(context.Background(), claims.TokenID, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ValidateToken(context.Background(), pair.AccessToken, AccessToken); err == nil {
+		t.Error("blacklisted token accepted")
+	}
+
+	if _, err := service.RefreshTokens(context.Background(), pair.RefreshToken); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if _, err := service.RefreshTokens(context.Background(), pair.RefreshToken); err == nil {
+		t.Error("refresh token accepted twice")
+	}
+
+	other, _ := service.GenerateTokenPair(context.Background(), "u1", "a@example.com", []string{"user"})
+	if err := service.RevokeUserTokens(context.Background(), "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ValidateToken(context.Background(), other.AccessToken, AccessToken); err == nil {
+		t.Error("token issued before RevokeUserTokens accepted")
+	}
+}
+
+func TestBlacklistUser(t *testing.T) {
+	store := NewMemoryTokenStore()
+	service := newTestService(NewCachedTokenStore(store, CachedTokenStoreOptions{}))
+
+	before, _ := service.GenerateTokenPair(context.Background(), "u1", "a@example.com", []string{"user"})
+	if err := service.BlacklistUser(context.Background(), "u1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := service.GenerateTokenPair(context.Background(), "u1", "a@example.com", []string{"user"})
+	for name, token := range map[string]string{"before": before.AccessToken, "after": after.AccessToken} {
+		if _, err := service.ValidateToken(context.Background(), token, AccessToken); err == nil {
+			t.Errorf("token issued %s the blacklist accepted", name)
+		}
+	}
+	if _, err := service.RefreshTokens(context.Background(), after.RefreshToken); err == nil {
+		t.Error("blacklisted user refreshed tokens")
+	}
+	other, _ := service.GenerateTokenPair(context.Background(), "u2", "b@example.com", []string{"user"})
+	if _, err := service.ValidateToken(context.Background(), other.AccessToken, AccessToken); err != nil {
+		t.Errorf("token of another user rejected: %v", err)
+	}
+
+	if err := service.BlacklistUser(context.Background(), "u1", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ValidateToken(context.Background(), after.AccessToken, AccessToken); err != nil {
+		t.Errorf("token rejected after the blacklist was lifted: %v", err)
+	}
+
+	// 到期后自动解除
+	_ = store.BlacklistUser(context.Background(), "u1", time.Now().Add(time.Minute))
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if until, _ := store.UserBlacklistedUntil(context.Background(), "u1"); !until.IsZero() {
+		t.Errorf("blacklist outlived its end: %v", until)
+	}
+}
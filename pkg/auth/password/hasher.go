@@ -0,0 +1,295 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Supported password hashing algorithms
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmScrypt   = "scrypt"
+)
+
+// saltLength is the length in bytes of the random salt used by argon2id and scrypt
+const saltLength = 16
+
+// ErrUnknownHashFormat is returned when a stored hash was not produced by a supported algorithm
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// Argon2Params holds the argon2id cost parameters
+type Argon2Params struct {
+	Time    uint32
+	Memory  uint32 // in KiB
+	Threads uint8
+	KeyLen  uint32
+}
+
+// ScryptParams holds the scrypt cost parameters
+type ScryptParams struct {
+	N      int
+	R      int
+	P      int
+	KeyLen int
+}
+
+// HasherConfig selects the algorithm used for new hashes and its parameters
+type HasherConfig struct {
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+	Scrypt     ScryptParams
+}
+
+// Hasher hashes passwords with the configured algorithm and verifies hashes produced
+// by any supported algorithm, so existing hashes keep working after a switch
+type Hasher struct {
+	config HasherConfig
+}
+
+// NewHasher creates a new password hasher, filling in recommended defaults for unset parameters
+func NewHasher(config HasherConfig) (*Hasher, error) {
+	if config.Algorithm == "" {
+		config.Algorithm = AlgorithmBcrypt
+	}
+	if config.BcryptCost == 0 {
+		config.BcryptCost = bcrypt.DefaultCost
+	}
+	if config.Argon2.Time == 0 {
+		config.Argon2.Time = 3
+	}
+	if config.Argon2.Memory == 0 {
+		config.Argon2.Memory = 64 * 1024
+	}
+	if config.Argon2.Threads == 0 {
+		config.Argon2.Threads = 2
+	}
+	if config.Argon2.KeyLen == 0 {
+		config.Argon2.KeyLen = 32
+	}
+	if config.Scrypt.N == 0 {
+		config.Scrypt.N = 32768
+	}
+	if config.Scrypt.R == 0 {
+		config.Scrypt.R = 8
+	}
+	if config.Scrypt.P == 0 {
+		config.Scrypt.P = 1
+	}
+	if config.Scrypt.KeyLen == 0 {
+		config.Scrypt.KeyLen = 32
+	}
+
+	switch config.Algorithm {
+	case AlgorithmBcrypt, AlgorithmArgon2id, AlgorithmScrypt:
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm: %s", config.Algorithm)
+	}
+	if config.BcryptCost < bcrypt.MinCost || config.BcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if config.Scrypt.N <= 1 || config.Scrypt.N&(config.Scrypt.N-1) != 0 {
+		return nil, errors.New("scrypt N must be a power of two greater than 1")
+	}
+
+	return &Hasher{config: config}, nil
+}
+
+// Hash hashes a password with the configured algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	switch h.config.Algorithm {
+	case AlgorithmArgon2id:
+		return h.hashArgon2id(password)
+	case AlgorithmScrypt:
+		return h.hashScrypt(password)
+	default:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.config.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+}
+
+// Verify checks a password against an encoded hash of any supported algorithm
+func (h *Hasher) Verify(encoded, password string) (bool, error) {
+	switch algorithmOf(encoded) {
+	case AlgorithmBcrypt:
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	case AlgorithmArgon2id:
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, err
+		}
+		derived := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(derived, key) == 1, nil
+	case AlgorithmScrypt:
+		params, salt, key, err := decodeScrypt(encoded)
+		if err != nil {
+			return false, err
+		}
+		derived, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, len(key))
+		if err != nil {
+			return false, err
+		}
+		return subtle.ConstantTimeCompare(derived, key) == 1, nil
+	default:
+		return false, ErrUnknownHashFormat
+	}
+}
+
+// NeedsRehash reports whether an encoded hash was produced with a different algorithm
+// or weaker parameters than currently configured
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	algorithm := algorithmOf(encoded)
+	if algorithm != h.config.Algorithm {
+		return true
+	}
+
+	switch algorithm {
+	case AlgorithmBcrypt:
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != h.config.BcryptCost
+	case AlgorithmArgon2id:
+		params, _, key, err := decodeArgon2id(encoded)
+		return err != nil ||
+			params.Time != h.config.Argon2.Time ||
+			params.Memory != h.config.Argon2.Memory ||
+			params.Threads != h.config.Argon2.Threads ||
+			uint32(len(key)) != h.config.Argon2.KeyLen
+	case AlgorithmScrypt:
+		params, _, key, err := decodeScrypt(encoded)
+		return err != nil ||
+			params.N != h.config.Scrypt.N ||
+			params.R != h.config.Scrypt.R ||
+			params.P != h.config.Scrypt.P ||
+			len(key) != h.config.Scrypt.KeyLen
+	}
+	return true
+}
+
+// hashArgon2id produces a PHC formatted argon2id hash:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func (h *Hasher) hashArgon2id(password string) (string, error) {
+	salt, err := randomSalt()
+	if err != nil {
+		return "", err
+	}
+	p := h.config.Argon2
+	key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// hashScrypt produces a scrypt hash in the same PHC style:
+// $scrypt$n=32768,r=8,p=1$<salt>$<key>
+func (h *Hasher) hashScrypt(password string) (string, error) {
+	salt, err := randomSalt()
+	if err != nil {
+		return "", err
+	}
+	p := h.config.Scrypt
+	key, err := scrypt.Key([]byte(password), salt, p.N, p.R, p.P, p.KeyLen)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$scrypt$n=%d,r=%d,p=%d$%s$%s",
+		p.N, p.R, p.P,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// algorithmOf detects the algorithm that produced an encoded hash
+func algorithmOf(encoded string) string {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return AlgorithmArgon2id
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		return AlgorithmScrypt
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return AlgorithmBcrypt
+	}
+	return ""
+}
+
+// decodeArgon2id parses a PHC formatted argon2id hash
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version: %s", parts[2])
+	}
+	// argon2 panics on zero rounds or threads
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil ||
+		params.Time == 0 || params.Threads == 0 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	// An empty key would match the empty key derived from any password
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	params.KeyLen = uint32(len(key))
+	return params, salt, key, nil
+}
+
+// decodeScrypt parses a PHC style scrypt hash
+func decodeScrypt(encoded string) (ScryptParams, []byte, []byte, error) {
+	var params ScryptParams
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "n=%d,r=%d,p=%d", &params.N, &params.R, &params.P); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	// An empty key would match the empty key derived from any password
+	key, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	params.KeyLen = len(key)
+	return params, salt, key, nil
+}
+
+// randomSalt generates a random salt
+func randomSalt() ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
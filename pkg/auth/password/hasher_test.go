@@ -0,0 +1,192 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap parameters keep the tests fast; they are not meant for production
+var (
+	testArgon2 = Argon2Params{Time: 1, Memory: 64, Threads: 1, KeyLen: 16}
+	testScrypt = ScryptParams{N: 16, R: 1, P: 1, KeyLen: 16}
+)
+
+func newTestHasher(t *testing.T, algorithm string) *Hasher {
+	t.Helper()
+	h, err := NewHasher(HasherConfig{
+		Algorithm:  algorithm,
+		BcryptCost: bcrypt.MinCost,
+		Argon2:     testArgon2,
+		Scrypt:     testScrypt,
+	})
+	if err != nil {
+		t.Fatalf("NewHasher(%s) error = %v", algorithm, err)
+	}
+	return h
+}
+
+func TestHasherRoundTrip(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		prefix    string
+	}{
+		{AlgorithmBcrypt, "$2a$04$"},
+		{AlgorithmArgon2id, "$argon2id$v=19$m=64,t=1,p=1$"},
+		{AlgorithmScrypt, "$scrypt$n=16,r=1,p=1$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			h := newTestHasher(t, tt.algorithm)
+			encoded, err := h.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Errorf("Hash() = %q, want prefix %q", encoded, tt.prefix)
+			}
+			if ok, err := h.Verify(encoded, "correct horse"); !ok || err != nil {
+				t.Errorf("Verify(correct password) = %v, %v, want true", ok, err)
+			}
+			if ok, err := h.Verify(encoded, "wrong horse"); ok || err != nil {
+				t.Errorf("Verify(wrong password) = %v, %v, want false", ok, err)
+			}
+			if h.NeedsRehash(encoded) {
+				t.Error("NeedsRehash() = true for a hash made with the current configuration")
+			}
+			if again, _ := h.Hash("correct horse"); again == encoded {
+				t.Error("two hashes of the same password are equal, the salt is not random")
+			}
+		})
+	}
+}
+
+func TestHasherVerifiesOtherAlgorithms(t *testing.T) {
+	algorithms := []string{AlgorithmBcrypt, AlgorithmArgon2id, AlgorithmScrypt}
+	for _, from := range algorithms {
+		encoded, err := newTestHasher(t, from).Hash("correct horse")
+		if err != nil {
+			t.Fatalf("Hash(%s) error = %v", from, err)
+		}
+		for _, to := range algorithms {
+			h := newTestHasher(t, to)
+			if ok, err := h.Verify(encoded, "correct horse"); !ok || err != nil {
+				t.Errorf("%s hasher verifying a %s hash = %v, %v, want true", to, from, ok, err)
+			}
+			if got := h.NeedsRehash(encoded); got != (from != to) {
+				t.Errorf("%s hasher: NeedsRehash(%s hash) = %v, want %v", to, from, got, from != to)
+			}
+		}
+	}
+}
+
+func TestHasherLegacyBcrypt(t *testing.T) {
+	// Hashes made by bcrypt.GenerateFromPassword before the hasher existed, including the
+	// $2y$ prefix written by other bcrypt implementations
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, encoded := range []string{string(legacy), "$2y$" + string(legacy[4:])} {
+		h := newTestHasher(t, AlgorithmArgon2id)
+		if ok, err := h.Verify(encoded, "correct horse"); !ok || err != nil {
+			t.Errorf("Verify(%q) = %v, %v, want true", encoded[:4], ok, err)
+		}
+		if ok, _ := h.Verify(encoded, "wrong horse"); ok {
+			t.Errorf("Verify(%q) accepted a wrong password", encoded[:4])
+		}
+		if !h.NeedsRehash(encoded) {
+			t.Errorf("NeedsRehash(%q) = false with argon2id configured", encoded[:4])
+		}
+	}
+
+	// A bcrypt hasher rehashes legacy hashes only when its cost differs
+	h, err := NewHasher(HasherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.NeedsRehash(string(legacy)) {
+		t.Error("NeedsRehash() = true for a bcrypt hash with the default cost")
+	}
+	if !newTestHasher(t, AlgorithmBcrypt).NeedsRehash(string(legacy)) {
+		t.Error("NeedsRehash() = false for a bcrypt hash with another cost")
+	}
+}
+
+func TestHasherNeedsRehashOnChangedParameters(t *testing.T) {
+	argon2Hash, _ := newTestHasher(t, AlgorithmArgon2id).Hash("correct horse")
+	scryptHash, _ := newTestHasher(t, AlgorithmScrypt).Hash("correct horse")
+
+	tests := []struct {
+		name    string
+		config  HasherConfig
+		encoded string
+	}{
+		{"argon2id time", HasherConfig{Algorithm: AlgorithmArgon2id, Argon2: Argon2Params{Time: 2, Memory: 64, Threads: 1, KeyLen: 16}}, argon2Hash},
+		{"argon2id memory", HasherConfig{Algorithm: AlgorithmArgon2id, Argon2: Argon2Params{Time: 1, Memory: 128, Threads: 1, KeyLen: 16}}, argon2Hash},
+		{"argon2id threads", HasherConfig{Algorithm: AlgorithmArgon2id, Argon2: Argon2Params{Time: 1, Memory: 64, Threads: 2, KeyLen: 16}}, argon2Hash},
+		{"argon2id key length", HasherConfig{Algorithm: AlgorithmArgon2id, Argon2: Argon2Params{Time: 1, Memory: 64, Threads: 1, KeyLen: 32}}, argon2Hash},
+		{"scrypt N", HasherConfig{Algorithm: AlgorithmScrypt, Scrypt: ScryptParams{N: 32, R: 1, P: 1, KeyLen: 16}}, scryptHash},
+		{"scrypt r", HasherConfig{Algorithm: AlgorithmScrypt, Scrypt: ScryptParams{N: 16, R: 2, P: 1, KeyLen: 16}}, scryptHash},
+		{"scrypt p", HasherConfig{Algorithm: AlgorithmScrypt, Scrypt: ScryptParams{N: 16, R: 1, P: 2, KeyLen: 16}}, scryptHash},
+		{"scrypt key length", HasherConfig{Algorithm: AlgorithmScrypt, Scrypt: ScryptParams{N: 16, R: 1, P: 1, KeyLen: 32}}, scryptHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHasher(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !h.NeedsRehash(tt.encoded) {
+				t.Error("NeedsRehash() = false after the parameters changed")
+			}
+			// The old hash still verifies until it is replaced
+			if ok, err := h.Verify(tt.encoded, "correct horse"); !ok || err != nil {
+				t.Errorf("Verify() = %v, %v, want true", ok, err)
+			}
+		})
+	}
+}
+
+func TestHasherMalformedHashes(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"empty", ""},
+		{"plain text", "correct horse"},
+		{"unknown algorithm", "$pbkdf2-sha256$29000$c2FsdA$a2V5"},
+		{"argon2id missing fields", "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA"},
+		{"argon2id unsupported version", "$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5aw"},
+		{"argon2id bad parameters", "$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5aw"},
+		{"argon2id zero time", "$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5aw"},
+		{"argon2id zero threads", "$argon2id$v=19$m=64,t=1,p=0$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5aw"},
+		{"argon2id bad salt", "$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5a2V5a2V5a2V5a2V5aw"},
+		{"argon2id bad key", "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$!!!"},
+		{"argon2id empty key", "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$"},
+		{"scrypt missing fields", "$scrypt$n=16,r=1,p=1$c2FsdHNhbHRzYWx0c2FsdA"},
+		{"scrypt bad parameters", "$scrypt$n=x,r=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5aw"},
+		{"scrypt N not a power of two", "$scrypt$n=15,r=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5aw"},
+		{"scrypt bad salt", "$scrypt$n=16,r=1,p=1$!!!$a2V5a2V5a2V5a2V5a2V5aw"},
+		{"scrypt empty key", "$scrypt$n=16,r=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$"},
+		{"bcrypt truncated", "$2a$04$short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHasher(t, AlgorithmArgon2id)
+			ok, err := h.Verify(tt.encoded, "correct horse")
+			if ok || err == nil {
+				t.Errorf("Verify() = %v, %v, want an error", ok, err)
+			}
+			if !h.NeedsRehash(tt.encoded) {
+				t.Error("NeedsRehash() = false for a malformed hash")
+			}
+		})
+	}
+
+	if _, err := newTestHasher(t, AlgorithmBcrypt).Verify("", "x"); !errors.Is(err, ErrUnknownHashFormat) {
+		t.Errorf("Verify(\"\") error = %v, want %v", err, ErrUnknownHashFormat)
+	}
+}
@@ -0,0 +1,153 @@
+package password
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// commonPasswords holds the built-in deny list, normalized to lower case
+var commonPasswords = parseCommonPasswords(commonPasswordList)
+
+// Violation describes a single password policy rule that was not satisfied
+type Violation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PolicyError is returned when a password violates one or more policy rules
+type PolicyError struct {
+	Violations []Violation
+}
+
+// Error implements the error interface
+func (e *PolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+	return "password does not meet policy: " + strings.Join(messages, "; ")
+}
+
+// Policy defines the password strength requirements
+type Policy struct {
+	MinLength           int
+	MaxLength           int
+	RequireUppercase    bool
+	RequireLowercase    bool
+	RequireDigit        bool
+	RequireSymbol       bool
+	MinCharacterClasses int
+	DenyCommon          bool
+	// DeniedPasswords extends the built-in common password list
+	DeniedPasswords []string
+	// HistorySize is how many of the most recent passwords, including the current one,
+	// may not be reused; 0 disables the check
+	HistorySize int
+}
+
+// ReusedPasswordError returns the policy error reported when a recently used password is chosen again
+func (p Policy) ReusedPasswordError() error {
+	return &PolicyError{Violations: []Violation{{
+		Code:    "recently_used",
+		Message: fmt.Sprintf("must not match any of the last %d passwords", p.HistorySize),
+	}}}
+}
+
+// Validate checks a password against the policy and returns a *PolicyError listing every violation
+func (p Policy) Validate(password string) error {
+	var violations []Violation
+
+	length := len([]rune(password))
+	if p.MinLength > 0 && length < p.MinLength {
+		violations = append(violations, Violation{
+			Code:    "too_short",
+			Message: fmt.Sprintf("must be at least %d characters long", p.MinLength),
+		})
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, Violation{
+			Code:    "too_long",
+			Message: fmt.Sprintf("must be at most %d characters long", p.MaxLength),
+		})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUppercase && !hasUpper {
+		violations = append(violations, Violation{Code: "missing_uppercase", Message: "must contain an uppercase letter"})
+	}
+	if p.RequireLowercase && !hasLower {
+		violations = append(violations, Violation{Code: "missing_lowercase", Message: "must contain a lowercase letter"})
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{Code: "missing_digit", Message: "must contain a digit"})
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{Code: "missing_symbol", Message: "must contain a symbol"})
+	}
+
+	classes := 0
+	for _, present := range []bool{hasUpper, hasLower, hasDigit, hasSymbol} {
+		if present {
+			classes++
+		}
+	}
+	if p.MinCharacterClasses > 0 && classes < p.MinCharacterClasses {
+		violations = append(violations, Violation{
+			Code:    "too_few_character_classes",
+			Message: fmt.Sprintf("must mix at least %d of uppercase, lowercase, digits and symbols", p.MinCharacterClasses),
+		})
+	}
+
+	if p.DenyCommon && p.isCommon(password) {
+		violations = append(violations, Violation{Code: "common_password", Message: "is too common"})
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// isCommon reports whether the password appears on the built-in or configured deny list
+func (p Policy) isCommon(password string) bool {
+	normalized := strings.ToLower(password)
+	if _, ok := commonPasswords[normalized]; ok {
+		return true
+	}
+	for _, denied := range p.DeniedPasswords {
+		if strings.ToLower(denied) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCommonPasswords turns the embedded newline separated list into a lookup set
+func parseCommonPasswords(list string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line != "" {
+			set[line] = struct{}{}
+		}
+	}
+	return set
+}
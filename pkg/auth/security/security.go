@@ -1,12 +1,45 @@
 package security
 
-import "time"
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// SignVersionHeader selects the signature scheme of a request. Requests without it use version 1.
+const SignVersionHeader = "X-Sign-Version"
+
+const (
+	// SignVersion1 signs the sorted query, form and top-level JSON string parameters with HMAC-SHA256
+	SignVersion1 = "1"
+	// SignVersion2 signs CanonicalRequest, which covers a SHA-256 hash of the raw body, with HMAC-SHA256
+	SignVersion2 = "2"
+	// SignVersion3 signs CanonicalRequest with HMAC-SHA512
+	SignVersion3 = "3"
+)
+
+// SignedRequest holds the parts of a request a signature can cover
+type SignedRequest struct {
+	// Params are the collected query, form and JSON string parameters covered by version 1
+	Params    map[string]string
+	Method    string
+	Path      string
+	Query     url.Values
+	Timestamp string
+	Nonce     string
+	Body      []byte
+}
 
 // SecurityService defines the interface for security operations
 type SecurityService interface {
-	GenerateNonce() (string, error)
+	GenerateNonce(ctx context.Context) (string, error)
 	ValidateTimestamp(timestamp string, validityWindow time.Duration) error
 	ValidateSignature(params map[string]string, signature string) error
-	ValidateNonce(nonce string) error
+	SignatureScheme(version string) (SignatureScheme, bool)
+	VerifySignature(version string, req SignedRequest, signature string) error
+	ValidateNonce(ctx context.Context, nonce string) error
+	CheckReplay(ctx context.Context, timestamp, nonce, signature string, validityWindow time.Duration) error
 	GetSignatureSecret() string
+	SignatureSecrets() []string
+	RotateSignatureSecret(next string, overlap time.Duration) error
 }
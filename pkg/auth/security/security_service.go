@@ -1,52 +1,71 @@
 package security
 
 import (
-	"crypto/hmac"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"sort"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
 )
 
+// ErrReplayed is returned by CheckReplay for a request that was already accepted
+var ErrReplayed = errors.New("request has already been processed")
+
 // DefaultSecurityService implements SecurityService
 type DefaultSecurityService struct {
-	signatureSecret   string
-	storeNonce        func(nonce string, expiration time.Duration) error
-	getNonce          func(nonce string) (bool, error)
-	invalidateNonce   func(nonce string) error
+	mu              sync.RWMutex
+	signatureSecret string
+	// secondarySecret is still accepted until secondaryUntil, or indefinitely when it is zero
+	secondarySecret   string
+	secondaryUntil    time.Time
+	storeNonce        func(ctx context.Context, nonce string, expiration time.Duration) error
+	consumeNonce      func(ctx context.Context, nonce string) (bool, error)
+	markRequestSeen   func(ctx context.Context, digest string, expiration time.Duration) (bool, error)
 	nonceValidityTime time.Duration
+	signatures        *SignatureRegistry
 }
 
-// NewSecurityService creates a new security service
+// NewSecurityService creates a new security service. Requests are accepted with the
+// signature versions in signatures, or with every built-in version when it is nil.
+// Signatures made with secondarySignatureSecret are accepted too while clients move
+// to a new secret; leave it empty outside of a rotation. consumeNonce removes a stored
+// nonce and reports whether it existed, in one atomic step.
 func NewSecurityService(
 	signatureSecret string,
+	secondarySignatureSecret string,
 	nonceValidityTime time.Duration,
-	storeNonce func(nonce string, expiration time.Duration) error,
-	getNonce func(nonce string) (bool, error),
-	invalidateNonce func(nonce string) error,
+	signatures *SignatureRegistry,
+	storeNonce func(ctx context.Context, nonce string, expiration time.Duration) error,
+	consumeNonce func(ctx context.Context, nonce string) (bool, error),
+	markRequestSeen func(ctx context.Context, digest string, expiration time.Duration) (bool, error),
 ) SecurityService {
+	if signatures == nil {
+		signatures, _ = NewSignatureRegistry()
+	}
 	return &DefaultSecurityService{
 		signatureSecret:   signatureSecret,
+		secondarySecret:   secondarySignatureSecret,
 		storeNonce:        storeNonce,
-		getNonce:          getNonce,
-		invalidateNonce:   invalidateNonce,
+		consumeNonce:      consumeNonce,
+		markRequestSeen:   markRequestSeen,
 		nonceValidityTime: nonceValidityTime,
+		signatures:        signatures,
 	}
 }
 
 // GenerateNonce creates a new nonce and stores it
-func (s *DefaultSecurityService) GenerateNonce() (string, error) {
+func (s *DefaultSecurityService) GenerateNonce(ctx context.Context) (string, error) {
 	// Generate random nonce
 	nonce := uuid.New().String()
 
 	// Store nonce in Redis with expiration
-	if err := s.storeNonce(nonce, s.nonceValidityTime); err != nil {
+	if err := s.storeNonce(ctx, nonce, s.nonceValidityTime); err != nil {
 		return "", fmt.Errorf("failed to store nonce: %w", err)
 	}
 
@@ -73,83 +92,110 @@ func (s *DefaultSecurityService) ValidateTimestamp(timestamp string, validityWin
 	return nil
 }
 
-// ValidateSignature verifies that the signature matches the request parameters
+// ValidateSignature verifies that the version 1 signature matches the request parameters,
+// made with either the primary or the secondary secret
 func (s *DefaultSecurityService) ValidateSignature(params map[string]string, signature string) error {
-	// Sort parameters by key
-	var keys []string
-	for k := range params {
-		if k != "sign" {
-			keys = append(keys, k)
-		}
-	}
-	sort.Strings(keys)
-
-	// Build parameter string
-	var paramPairs []string
-	for _, k := range keys {
-		paramPairs = append(paramPairs, fmt.Sprintf("%s=%s", k, params[k]))
-	}
-	paramString := strings.Join(paramPairs, "&")
+	return s.VerifySignature(SignVersion1, SignedRequest{Params: params}, signature)
+}
 
-	// Calculate HMAC-SHA256
-	h := hmac.New(sha256.New, []byte(s.signatureSecret))
-	h.Write([]byte(paramString))
-	expectedSign := hex.EncodeToString(h.Sum(nil))
+// SignatureScheme returns the scheme of an accepted signature version
+func (s *DefaultSecurityService) SignatureScheme(version string) (SignatureScheme, bool) {
+	return s.signatures.Lookup(version)
+}
 
-	// Compare with provided signature
-	if !hmac.Equal([]byte(expectedSign), []byte(signature)) {
-		return errors.New("invalid signature")
+// VerifySignature verifies a signature with the scheme of the given version, made with
+// either the primary or the secondary secret
+func (s *DefaultSecurityService) VerifySignature(version string, req SignedRequest, signature string) error {
+	var err error
+	for i, secret := range s.SignatureSecrets() {
+		if err = s.signatures.Verify(version, req, secret, signature); err == nil {
+			if i > 0 {
+				logger.Infow("request signed with the secondary signature secret", "sign_version", version)
+			} else {
+				logger.Debugw("request signed with the primary signature secret", "sign_version", version)
+			}
+			return nil
+		}
+		if errors.Is(err, ErrUnsupportedSignVersion) {
+			return err
+		}
 	}
-
-	return nil
+	return err
 }
 
 // ValidateNonce checks if the nonce is valid and hasn't been used before
-func (s *DefaultSecurityService) ValidateNonce(nonce string) error {
-	// Check if nonce exists in Redis
-	exists, err := s.getNonce(nonce)
+func (s *DefaultSecurityService) ValidateNonce(ctx context.Context, nonce string) error {
+	// Checking and invalidating the nonce in one step lets only one concurrent request use it
+	consumed, err := s.consumeNonce(ctx, nonce)
 	if err != nil {
 		return fmt.Errorf("failed to check nonce: %w", err)
 	}
 
-	if !exists {
+	if !consumed {
 		return errors.New("invalid or expired nonce")
 	}
 
-	// Invalidate nonce after use
-	if err := s.invalidateNonce(nonce); err != nil {
-		return fmt.Errorf("failed to invalidate nonce: %w", err)
-	}
+	return nil
+}
 
+// CheckReplay rejects a signed request that was already accepted. The timestamp, nonce and
+// signature are remembered for twice the validity window, the longest time the timestamp can
+// still pass ValidateTimestamp, so replays are caught even when no nonce was sent.
+func (s *DefaultSecurityService) CheckReplay(ctx context.Context, timestamp, nonce, signature string, validityWindow time.Duration) error {
+	digest := sha256.Sum256([]byte(timestamp + "\n" + nonce + "\n" + signature))
+	first, err := s.markRequestSeen(ctx, hex.EncodeToString(digest[:]), 2*validityWindow)
+	if err != nil {
+		return fmt.Errorf("failed to check replay: %w", err)
+	}
+	if !first {
+		return ErrReplayed
+	}
 	return nil
 }
 
 // GetSignatureSecret returns the signature secret key used for signing
 func (s *DefaultSecurityService) GetSignatureSecret() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.signatureSecret
 }
 
-// GenerateSignature creates a signature for the given parameters
-func GenerateSignature(params map[string]string, secret string) string {
-	// Sort parameters by key
-	var keys []string
-	for k := range params {
-		if k != "sign" {
-			keys = append(keys, k)
-		}
+// SignatureSecrets returns the secrets signatures are accepted with: the primary secret,
+// followed by the secondary one while its overlap window lasts
+func (s *DefaultSecurityService) SignatureSecrets() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	secrets := []string{s.signatureSecret}
+	if s.secondarySecret != "" && (s.secondaryUntil.IsZero() || time.Now().Before(s.secondaryUntil)) {
+		secrets = append(secrets, s.secondarySecret)
 	}
-	sort.Strings(keys)
+	return secrets
+}
 
-	// Build parameter string
-	var paramPairs []string
-	for _, k := range keys {
-		paramPairs = append(paramPairs, fmt.Sprintf("%s=%s", k, params[k]))
+// RotateSignatureSecret replaces the signature secret at runtime. The old secret becomes
+// the secondary one and is still accepted for overlap, so clients can switch in the meantime;
+// a zero overlap rejects it right away.
+func (s *DefaultSecurityService) RotateSignatureSecret(next string, overlap time.Duration) error {
+	if next == "" {
+		return errors.New("next signature secret is not configured")
 	}
-	paramString := strings.Join(paramPairs, "&")
 
-	// Calculate HMAC-SHA256
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(paramString))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next == s.signatureSecret {
+		return errors.New("next signature secret is already active")
+	}
+	s.secondarySecret = ""
+	if overlap > 0 {
+		s.secondarySecret = s.signatureSecret
+		s.secondaryUntil = time.Now().Add(overlap)
+	}
+	s.signatureSecret = next
+	return nil
+}
 
-	return hex.EncodeToString(h.Sum(nil))
+// GenerateSignature creates a version 1 signature for the given parameters
+func GenerateSignature(params map[string]string, secret string) string {
+	signature, _ := HMACSHA256.Sign([]byte(CanonicalParams(params)), secret)
+	return signature
 }
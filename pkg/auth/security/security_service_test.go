@@ -0,0 +1,78 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckReplay(t *testing.T) {
+	seen := map[string]time.Duration{}
+	service := NewSecurityService("secret", "", time.Minute, nil, nil, nil,
+		func(ctx context.Context, digest string, expiration time.Duration) (bool, error) {
+			if _, ok := seen[digest]; ok {
+				return false, nil
+			}
+			seen[digest] = expiration
+			return true, nil
+		})
+
+	if err := service.CheckReplay(context.Background(), "1", "", "sig", time.Minute); err != nil {
+		t.Fatalf("first request rejected: %v", err)
+	}
+	if err := service.CheckReplay(context.Background(), "1", "", "sig", time.Minute); !errors.Is(err, ErrReplayed) {
+		t.Errorf("replayed request: got %v, want ErrReplayed", err)
+	}
+	if err := service.CheckReplay(context.Background(), "1", "n", "sig", time.Minute); err != nil {
+		t.Errorf("request with another nonce rejected: %v", err)
+	}
+	for _, expiration := range seen {
+		if expiration != 2*time.Minute {
+			t.Errorf("replay marker expires after %v, want twice the validity window", expiration)
+		}
+	}
+
+	failing := NewSecurityService("secret", "", time.Minute, nil, nil, nil,
+		func(context.Context, string, time.Duration) (bool, error) { return false, errors.New("redis down") })
+	if err := failing.CheckReplay(context.Background(), "1", "", "sig", time.Minute); err == nil || errors.Is(err, ErrReplayed) {
+		t.Errorf("store failure: got %v, want a non-replay error", err)
+	}
+}
+
+func TestSignatureSecretRotation(t *testing.T) {
+	params := map[string]string{"email": "a@example.com", "timestamp": "1"}
+	oldSignature := GenerateSignature(params, "old")
+	newSignature := GenerateSignature(params, "new")
+
+	service := NewSecurityService("new", "old", time.Minute, nil, nil, nil, nil)
+	for _, signature := range []string{oldSignature, newSignature} {
+		if err := service.ValidateSignature(params, signature); err != nil {
+			t.Errorf("configured secondary secret: signature rejected: %v", err)
+		}
+	}
+	if err := service.ValidateSignature(params, GenerateSignature(params, "other")); err == nil {
+		t.Error("signature made with an unknown secret accepted")
+	}
+
+	service = NewSecurityService("old", "", time.Minute, nil, nil, nil, nil)
+	if err := service.RotateSignatureSecret("new", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if got := service.SignatureSecrets(); len(got) != 2 || got[0] != "new" || got[1] != "old" {
+		t.Errorf("secrets during overlap = %v, want [new old]", got)
+	}
+	if err := service.ValidateSignature(params, oldSignature); err != nil {
+		t.Errorf("old secret rejected during overlap: %v", err)
+	}
+
+	if err := service.RotateSignatureSecret("newer", 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := service.SignatureSecrets(); len(got) != 1 || got[0] != "newer" {
+		t.Errorf("secrets without overlap = %v, want [newer]", got)
+	}
+	if err := service.ValidateSignature(params, newSignature); err == nil {
+		t.Error("old secret accepted after a rotation without overlap")
+	}
+}
@@ -0,0 +1,186 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedSignVersion is returned for signature versions that are not registered
+var ErrUnsupportedSignVersion = errors.New("unsupported signature version")
+
+// Algorithm computes and verifies signatures over a canonical string. The key is the
+// shared secret for HMAC algorithms; asymmetric algorithms would sign with the private
+// key and verify with the public one.
+type Algorithm interface {
+	Name() string
+	Sign(message []byte, key string) (string, error)
+	Verify(message []byte, key, signature string) error
+}
+
+// hmacAlgorithm signs with an HMAC and encodes the signature as lower-case hex
+type hmacAlgorithm struct {
+	name    string
+	newHash func() hash.Hash
+}
+
+var (
+	// HMACSHA256 is the hex HMAC-SHA256 algorithm
+	HMACSHA256 Algorithm = hmacAlgorithm{name: "HMAC-SHA256", newHash: sha256.New}
+	// HMACSHA512 is the hex HMAC-SHA512 algorithm
+	HMACSHA512 Algorithm = hmacAlgorithm{name: "HMAC-SHA512", newHash: sha512.New}
+)
+
+func (a hmacAlgorithm) Name() string {
+	return a.name
+}
+
+func (a hmacAlgorithm) Sign(message []byte, key string) (string, error) {
+	h := hmac.New(a.newHash, []byte(key))
+	h.Write(message)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (a hmacAlgorithm) Verify(message []byte, key, signature string) error {
+	expected, err := a.Sign(message, key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// SignatureScheme describes what a signature version covers and how it is computed
+type SignatureScheme struct {
+	// RawBody reports whether Canonicalize reads SignedRequest.Body; otherwise it reads Params
+	RawBody bool
+	// Canonicalize builds the string that is signed
+	Canonicalize func(req SignedRequest) string
+	Algorithm    Algorithm
+}
+
+// builtinSchemes are the signature versions supported out of the box
+var builtinSchemes = map[string]SignatureScheme{
+	SignVersion1: {
+		Canonicalize: func(req SignedRequest) string { return CanonicalParams(req.Params) },
+		Algorithm:    HMACSHA256,
+	},
+	SignVersion2: {RawBody: true, Canonicalize: CanonicalRequest, Algorithm: HMACSHA256},
+	SignVersion3: {RawBody: true, Canonicalize: CanonicalRequest, Algorithm: HMACSHA512},
+}
+
+// SignatureRegistry maps the values of the X-Sign-Version header to signature schemes.
+// Schemes must be registered before the server starts handling requests.
+type SignatureRegistry struct {
+	schemes map[string]SignatureScheme
+}
+
+// NewSignatureRegistry creates a registry with the given built-in versions, or with all
+// of them when none are given. Leaving a version out stops accepting it, e.g. to retire version 1.
+func NewSignatureRegistry(versions ...string) (*SignatureRegistry, error) {
+	if len(versions) == 0 {
+		versions = make([]string, 0, len(builtinSchemes))
+		for version := range builtinSchemes {
+			versions = append(versions, version)
+		}
+	}
+
+	r := &SignatureRegistry{schemes: make(map[string]SignatureScheme, len(versions))}
+	for _, version := range versions {
+		scheme, ok := builtinSchemes[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedSignVersion, version)
+		}
+		r.schemes[version] = scheme
+	}
+	return r, nil
+}
+
+// Register adds or replaces a signature version
+func (r *SignatureRegistry) Register(version string, scheme SignatureScheme) {
+	r.schemes[version] = scheme
+}
+
+// Lookup returns the scheme of a signature version
+func (r *SignatureRegistry) Lookup(version string) (SignatureScheme, bool) {
+	scheme, ok := r.schemes[version]
+	return scheme, ok
+}
+
+// Versions returns the registered signature versions in sorted order
+func (r *SignatureRegistry) Versions() []string {
+	versions := make([]string, 0, len(r.schemes))
+	for version := range r.schemes {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Sign signs a request with the scheme of the given version, e.g. in clients and tests
+func (r *SignatureRegistry) Sign(version string, req SignedRequest, key string) (string, error) {
+	scheme, ok := r.Lookup(version)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedSignVersion, version)
+	}
+	return scheme.Algorithm.Sign([]byte(scheme.Canonicalize(req)), key)
+}
+
+// Verify checks a signature with the scheme of the given version
+func (r *SignatureRegistry) Verify(version string, req SignedRequest, key, signature string) error {
+	scheme, ok := r.Lookup(version)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedSignVersion, version)
+	}
+	return scheme.Algorithm.Verify([]byte(scheme.Canonicalize(req)), key, signature)
+}
+
+// CanonicalParams builds the string signed by version 1 signatures: the parameters
+// other than "sign" as key=value pairs sorted by key and joined by "&"
+func CanonicalParams(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "sign" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+params[k])
+	}
+	return strings.Join(pairs, "&")
+}
+
+// CanonicalRequest builds the string signed by version 2 and 3 signatures. It is the upper-case
+// method, the escaped path, the query without "sign" encoded with keys sorted (url.Values.Encode),
+// the timestamp, the nonce and the lower-case hex SHA-256 of the raw body, joined by "\n".
+// An empty body hashes to the SHA-256 of zero bytes.
+func CanonicalRequest(req SignedRequest) string {
+	query := url.Values{}
+	for k, v := range req.Query {
+		if k != "sign" {
+			query[k] = v
+		}
+	}
+	bodyHash := sha256.Sum256(req.Body)
+
+	return strings.Join([]string{
+		strings.ToUpper(req.Method),
+		req.Path,
+		query.Encode(),
+		req.Timestamp,
+		req.Nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
@@ -0,0 +1,59 @@
+package security
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestSignatureRegistry(t *testing.T) {
+	req := SignedRequest{
+		Params:    map[string]string{"email": "a@example.com", "timestamp": "1", "nonce": "n"},
+		Method:    "post",
+		Path:      "/api/v1/users",
+		Query:     url.Values{"b": {"2"}, "a": {"1"}, "sign": {"x"}},
+		Timestamp: "1",
+		Nonce:     "n",
+		Body:      []byte(`{"age": 30}`),
+	}
+
+	registry, err := NewSignatureRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range registry.Versions() {
+		signature, err := registry.Sign(version, req, "secret")
+		if err != nil {
+			t.Fatalf("Sign(%s) failed: %v", version, err)
+		}
+		if err := registry.Verify(version, req, "secret", signature); err != nil {
+			t.Errorf("Verify(%s) failed: %v", version, err)
+		}
+		if err := registry.Verify(version, req, "other", signature); err == nil {
+			t.Errorf("Verify(%s) accepted a signature made with another key", version)
+		}
+	}
+
+	// Version 1 must keep matching what existing clients compute
+	v1, _ := registry.Sign(SignVersion1, req, "secret")
+	if v1 != GenerateSignature(req.Params, "secret") {
+		t.Errorf("version 1 signature %s does not match GenerateSignature", v1)
+	}
+
+	want := "POST\n/api/v1/users\na=1&b=2\n1\nn\n" +
+		"ca3f5b37300e7ff18174196ed6663c107c2f622e85b22b8f91886bdb003bb494"
+	if got := CanonicalRequest(req); got != want {
+		t.Errorf("CanonicalRequest = %q, want %q", got, want)
+	}
+
+	restricted, err := NewSignatureRegistry(SignVersion2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := restricted.Lookup(SignVersion1); ok {
+		t.Error("restricted registry accepts version 1")
+	}
+	if _, err := NewSignatureRegistry("9"); !errors.Is(err, ErrUnsupportedSignVersion) {
+		t.Errorf("NewSignatureRegistry(9) error = %v, want ErrUnsupportedSignVersion", err)
+	}
+}
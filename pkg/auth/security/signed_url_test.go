@@ -0,0 +1,58 @@
+package security
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSignedURL(t *testing.T) {
+	signed, err := SignURL("https://api.example.com/files/report%20q1.pdf?download=1", time.Now().Add(time.Minute), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify := func(rawURL, secret string) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return VerifySignedURL(u, secret)
+	}
+
+	if err := verify(signed, "secret"); err != nil {
+		t.Errorf("valid URL rejected: %v", err)
+	}
+	if err := verify(signed, "other"); !errors.Is(err, ErrInvalidSignedURL) {
+		t.Errorf("URL checked with another secret: got %v, want ErrInvalidSignedURL", err)
+	}
+
+	u, _ := url.Parse(signed)
+	tampered := *u
+	tampered.Path = "/files/other.pdf"
+	if err := verify(tampered.String(), "secret"); !errors.Is(err, ErrInvalidSignedURL) {
+		t.Errorf("tampered path: got %v, want ErrInvalidSignedURL", err)
+	}
+	for _, change := range []func(url.Values){
+		func(q url.Values) { q.Set("download", "0") },
+		func(q url.Values) { q.Add("extra", "1") },
+		func(q url.Values) { q.Set(SignedURLExpiresParam, "99999999999") },
+		func(q url.Values) { q.Del(SignedURLSignatureParam) },
+	} {
+		query := u.Query()
+		change(query)
+		tampered.Path = u.Path
+		tampered.RawQuery = query.Encode()
+		if err := verify(tampered.String(), "secret"); !errors.Is(err, ErrInvalidSignedURL) {
+			t.Errorf("tampered query %s: got %v, want ErrInvalidSignedURL", tampered.RawQuery, err)
+		}
+	}
+
+	expired, err := SignURL("/files/report.pdf", time.Now().Add(-time.Minute), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(expired, "secret"); !errors.Is(err, ErrSignedURLExpired) {
+		t.Errorf("expired URL: got %v, want ErrSignedURLExpired", err)
+	}
+}
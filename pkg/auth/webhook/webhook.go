@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported webhook signature schemes
+const (
+	ProviderStripe = "stripe"
+	ProviderGitHub = "github"
+	ProviderSlack  = "slack"
+)
+
+// Default signature headers of the supported providers
+const (
+	stripeSignatureHeader = "Stripe-Signature"
+	githubSignatureHeader = "X-Hub-Signature-256"
+	slackSignatureHeader  = "X-Slack-Signature"
+	slackTimestampHeader  = "X-Slack-Request-Timestamp"
+)
+
+var (
+	// ErrMissingSignature is returned when a webhook carries no signature header
+	ErrMissingSignature = errors.New("webhook signature is missing")
+	// ErrInvalidSignature is returned when no signature of a webhook matches its body
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	// ErrTimestampOutOfTolerance is returned for correctly signed webhooks that are too old or
+	// too far in the future, which are likely replays
+	ErrTimestampOutOfTolerance = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// Verifier checks that a webhook was sent by the provider holding the shared secret
+type Verifier interface {
+	// Verify returns nil if header carries a valid signature of the raw request body
+	Verify(header http.Header, body []byte) error
+}
+
+// Options configures a webhook verifier
+type Options struct {
+	// Secrets are the signing secrets issued by the provider; several are accepted so a
+	// secret can be rotated without dropping webhooks
+	Secrets []string
+	// Tolerance bounds the age of timestamped signatures; 0 uses 5 minutes. GitHub
+	// signatures carry no timestamp and are not checked.
+	Tolerance time.Duration
+	// SignatureHeader overrides the provider's signature header
+	SignatureHeader string
+}
+
+// NewVerifier creates the verifier for the named provider
+func NewVerifier(provider string, options Options) (Verifier, error) {
+	if len(options.Secrets) == 0 {
+		return nil, errors.New("webhook secret is not configured")
+	}
+	if options.Tolerance <= 0 {
+		options.Tolerance = 5 * time.Minute
+	}
+
+	switch provider {
+	case ProviderStripe:
+		return &stripeVerifier{options: withHeader(options, stripeSignatureHeader)}, nil
+	case ProviderGitHub:
+		return &githubVerifier{options: withHeader(options, githubSignatureHeader)}, nil
+	case ProviderSlack:
+		return &slackVerifier{options: withHeader(options, slackSignatureHeader)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported webhook provider: %s", provider)
+	}
+}
+
+func withHeader(options Options, header string) Options {
+	if options.SignatureHeader == "" {
+		options.SignatureHeader = header
+	}
+	return options
+}
+
+// stripeVerifier checks "t=<unix>,v1=<hex>" headers signing "<t>.<body>" with HMAC-SHA256.
+// Several v1 entries may be present while Stripe rolls a secret.
+type stripeVerifier struct {
+	options Options
+}
+
+func (v *stripeVerifier) Verify(header http.Header, body []byte) error {
+	value := header.Get(v.options.SignatureHeader)
+	if value == "" {
+		return ErrMissingSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = val
+		case "v1":
+			signatures = append(signatures, val)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	payload := []byte(timestamp + "." + string(body))
+	if !matchAny(v.options.Secrets, payload, signatures) {
+		return ErrInvalidSignature
+	}
+	return checkTimestamp(timestamp, v.options.Tolerance)
+}
+
+// githubVerifier checks "sha256=<hex>" headers signing the body with HMAC-SHA256
+type githubVerifier struct {
+	options Options
+}
+
+func (v *githubVerifier) Verify(header http.Header, body []byte) error {
+	value := header.Get(v.options.SignatureHeader)
+	if value == "" {
+		return ErrMissingSignature
+	}
+	signature, ok := strings.CutPrefix(value, "sha256=")
+	if !ok || !matchAny(v.options.Secrets, body, []string{signature}) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// slackVerifier checks "v0=<hex>" headers signing "v0:<timestamp>:<body>" with HMAC-SHA256,
+// the timestamp being sent in its own header
+type slackVerifier struct {
+	options Options
+}
+
+func (v *slackVerifier) Verify(header http.Header, body []byte) error {
+	value := header.Get(v.options.SignatureHeader)
+	timestamp := header.Get(slackTimestampHeader)
+	if value == "" || timestamp == "" {
+		return ErrMissingSignature
+	}
+	signature, ok := strings.CutPrefix(value, "v0=")
+	if !ok || !matchAny(v.options.Secrets, []byte("v0:"+timestamp+":"+string(body)), []string{signature}) {
+		return ErrInvalidSignature
+	}
+	return checkTimestamp(timestamp, v.options.Tolerance)
+}
+
+// matchAny reports whether one of signatures is the hex HMAC-SHA256 of payload under one of secrets
+func matchAny(secrets []string, payload []byte, signatures []string) bool {
+	for _, secret := range secrets {
+		h := hmac.New(sha256.New, []byte(secret))
+		h.Write(payload)
+		expected := hex.EncodeToString(h.Sum(nil))
+		for _, signature := range signatures {
+			if hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkTimestamp rejects Unix timestamps further than tolerance from now
+func checkTimestamp(timestamp string, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if math.Abs(float64(time.Now().Unix()-ts)) > tolerance.Seconds() {
+		return ErrTimestampOutOfTolerance
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func sign(secret, payload string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestVerifiers(t *testing.T) {
+	body := `{"id":"evt_1"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	cases := []struct {
+		name     string
+		provider string
+		header   http.Header
+		want     error
+	}{
+		{"stripe", ProviderStripe, http.Header{"Stripe-Signature": {"t=" + now + ",v1=" + sign("whsec", now+"."+body)}}, nil},
+		{"stripe rolled secret", ProviderStripe, http.Header{"Stripe-Signature": {"t=" + now + ",v1=bad,v1=" + sign("old", now+"."+body)}}, nil},
+		{"stripe wrong secret", ProviderStripe, http.Header{"Stripe-Signature": {"t=" + now + ",v1=" + sign("other", now+"."+body)}}, ErrInvalidSignature},
+		{"stripe changed timestamp", ProviderStripe, http.Header{"Stripe-Signature": {"t=" + old + ",v1=" + sign("whsec", now+"."+body)}}, ErrInvalidSignature},
+		{"stripe old", ProviderStripe, http.Header{"Stripe-Signature": {"t=" + old + ",v1=" + sign("whsec", old+"."+body)}}, ErrTimestampOutOfTolerance},
+		{"stripe missing", ProviderStripe, http.Header{}, ErrMissingSignature},
+		{"github", ProviderGitHub, http.Header{"X-Hub-Signature-256": {"sha256=" + sign("whsec", body)}}, nil},
+		{"github without prefix", ProviderGitHub, http.Header{"X-Hub-Signature-256": {sign("whsec", body)}}, ErrInvalidSignature},
+		{"slack", ProviderSlack, http.Header{"X-Slack-Signature": {"v0=" + sign("whsec", "v0:"+now+":"+body)}, "X-Slack-Request-Timestamp": {now}}, nil},
+		{"slack old", ProviderSlack, http.Header{"X-Slack-Signature": {"v0=" + sign("whsec", "v0:"+old+":"+body)}, "X-Slack-Request-Timestamp": {old}}, ErrTimestampOutOfTolerance},
+		{"slack missing timestamp", ProviderSlack, http.Header{"X-Slack-Signature": {"v0=" + sign("whsec", "v0:"+now+":"+body)}}, ErrMissingSignature},
+	}
+
+	for _, tc := range cases {
+		verifier, err := NewVerifier(tc.provider, Options{Secrets: []string{"whsec", "old"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = verifier.Verify(tc.header, []byte(body))
+		if (tc.want == nil && err != nil) || (tc.want != nil && !errors.Is(err, tc.want)) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	if _, err := NewVerifier("paypal", Options{Secrets: []string{"whsec"}}); err == nil {
+		t.Error("unsupported provider accepted")
+	}
+	if _, err := NewVerifier(ProviderStripe, Options{}); err == nil {
+		t.Error("verifier without a secret accepted")
+	}
+}
@@ -0,0 +1,90 @@
+package avatar
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"image/color"
+)
+
+// Subject identifies whose avatar to produce
+type Subject struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+// Avatar is either image data or a URL the client should be redirected to
+type Avatar struct {
+	Data        []byte
+	ContentType string
+	RedirectURL string
+}
+
+// Provider produces avatars for users without an uploaded one.
+// Generators must be deterministic: the same subject always yields the same image.
+type Provider interface {
+	// Key identifies the generated image for caching; providers that redirect return ""
+	Key(subject Subject) string
+	Avatar(ctx context.Context, subject Subject) (*Avatar, error)
+}
+
+// NewProvider creates the provider with the given name: identicon, initials or gravatar
+func NewProvider(name string, size int, gravatarFallback string) (Provider, error) {
+	switch name {
+	case "", "identicon":
+		return NewIdenticonProvider(size), nil
+	case "initials":
+		return NewInitialsProvider(size), nil
+	case "gravatar":
+		return NewGravatarProvider(size, gravatarFallback), nil
+	}
+	return nil, fmt.Errorf("unknown avatar provider: %s", name)
+}
+
+// subjectHash is the stable seed for generated images
+func subjectHash(value string) [32]byte {
+	return sha256.Sum256([]byte(value))
+}
+
+// paletteColor picks a pleasant, saturated color from a hash
+func paletteColor(hash [32]byte) color.RGBA {
+	// HSL with fixed saturation/lightness keeps colors readable against white text
+	h := float64(int(hash[0])<<8|int(hash[1])) / 65535 * 360
+	return hslToRGB(h, 0.55, 0.5)
+}
+
+// hslToRGB converts HSL (h in degrees, s and l in [0,1]) to RGB
+func hslToRGB(h, s, l float64) color.RGBA {
+	c := (1 - abs(2*l-1)) * s
+	x := c * (1 - abs(mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return color.RGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 255}
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func mod(a, b float64) float64 {
+	return a - b*float64(int(a/b))
+}
@@ -0,0 +1,56 @@
+package avatar
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/hewenyu/gin-pkg/pkg/storage"
+)
+
+// storagePrefix is where generated avatars are kept
+const storagePrefix = "avatars/generated/"
+
+// CachedProvider stores generated avatars so each image is rendered only once
+type CachedProvider struct {
+	provider Provider
+	storage  storage.Storage
+}
+
+// NewCachedProvider wraps a provider with storage-backed caching
+func NewCachedProvider(provider Provider, store storage.Storage) Provider {
+	return &CachedProvider{provider: provider, storage: store}
+}
+
+// Key returns the key of the wrapped provider
+func (p *CachedProvider) Key(subject Subject) string {
+	return p.provider.Key(subject)
+}
+
+// Avatar returns the stored image, generating and storing it on first use
+func (p *CachedProvider) Avatar(ctx context.Context, subject Subject) (*Avatar, error) {
+	key := p.provider.Key(subject)
+	if key == "" {
+		return p.provider.Avatar(ctx, subject)
+	}
+
+	obj, err := p.storage.Get(ctx, storagePrefix+key)
+	if err == nil {
+		return &Avatar{Data: obj.Data, ContentType: obj.ContentType}, nil
+	}
+	if !errors.Is(err, storage.ErrNotExist) {
+		logger.Warnf("Failed to read cached avatar %s: %v", key, err)
+	}
+
+	avatar, err := p.provider.Avatar(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	if avatar.RedirectURL == "" {
+		// A failed write only costs a re-render next time
+		if err := p.storage.Put(ctx, storagePrefix+key, avatar.Data, avatar.ContentType); err != nil {
+			logger.Warnf("Failed to cache avatar %s: %v", key, err)
+		}
+	}
+	return avatar, nil
+}
@@ -0,0 +1,41 @@
+package avatar
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GravatarProvider redirects to the user's Gravatar image
+type GravatarProvider struct {
+	size     int
+	fallback string
+}
+
+// NewGravatarProvider creates a new Gravatar provider. fallback is Gravatar's "d" parameter
+// (e.g. identicon, retro, mp) used when the email has no Gravatar.
+func NewGravatarProvider(size int, fallback string) Provider {
+	if fallback == "" {
+		fallback = "identicon"
+	}
+	return &GravatarProvider{size: size, fallback: fallback}
+}
+
+// Key returns "" as Gravatar images are not stored locally
+func (p *GravatarProvider) Key(subject Subject) string {
+	return ""
+}
+
+// Avatar returns the Gravatar URL of a subject
+func (p *GravatarProvider) Avatar(ctx context.Context, subject Subject) (*Avatar, error) {
+	sum := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(subject.Email))))
+	query := url.Values{}
+	query.Set("s", fmt.Sprint(p.size))
+	query.Set("d", p.fallback)
+	return &Avatar{
+		RedirectURL: "https://www.gravatar.com/avatar/" + hex.EncodeToString(sum[:]) + "?" + query.Encode(),
+	}, nil
+}
@@ -0,0 +1,62 @@
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// identiconGrid is the number of cells per side; the left half is mirrored to the right
+const identiconGrid = 5
+
+// IdenticonProvider renders GitHub-style symmetric block patterns as PNG
+type IdenticonProvider struct {
+	size int
+}
+
+// NewIdenticonProvider creates a new identicon provider
+func NewIdenticonProvider(size int) Provider {
+	return &IdenticonProvider{size: size}
+}
+
+// Key identifies the identicon of a subject
+func (p *IdenticonProvider) Key(subject Subject) string {
+	return fmt.Sprintf("identicon/%d/%x.png", p.size, subjectHash(subject.ID))
+}
+
+// Avatar renders the identicon of a subject
+func (p *IdenticonProvider) Avatar(ctx context.Context, subject Subject) (*Avatar, error) {
+	hash := subjectHash(subject.ID)
+	fg := paletteColor(hash)
+	bg := color.RGBA{R: 240, G: 240, B: 240, A: 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, p.size, p.size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+
+	// A margin of half a cell on each side
+	cell := p.size / (identiconGrid + 1)
+	margin := (p.size - cell*identiconGrid) / 2
+	half := (identiconGrid + 1) / 2
+	for row := 0; row < identiconGrid; row++ {
+		for col := 0; col < half; col++ {
+			// Bytes after the two used for the color decide which cells are filled
+			if hash[2+row*half+col]%2 == 0 {
+				continue
+			}
+			for _, c := range []int{col, identiconGrid - 1 - col} {
+				rect := image.Rect(margin+c*cell, margin+row*cell, margin+(c+1)*cell, margin+(row+1)*cell)
+				draw.Draw(img, rect, &image.Uniform{C: fg}, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode identicon: %w", err)
+	}
+	return &Avatar{Data: buf.Bytes(), ContentType: "image/png"}, nil
+}
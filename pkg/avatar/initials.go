@@ -0,0 +1,60 @@
+package avatar
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// InitialsProvider renders the user's initials on a colored background as SVG
+type InitialsProvider struct {
+	size int
+}
+
+// NewInitialsProvider creates a new initials provider
+func NewInitialsProvider(size int) Provider {
+	return &InitialsProvider{size: size}
+}
+
+// Key identifies the initials image; it changes when the user is renamed
+func (p *InitialsProvider) Key(subject Subject) string {
+	return fmt.Sprintf("initials/%d/%x.svg", p.size, subjectHash(subject.ID+"\x00"+initials(subject)))
+}
+
+// Avatar renders the initials image of a subject
+func (p *InitialsProvider) Avatar(ctx context.Context, subject Subject) (*Avatar, error) {
+	bg := paletteColor(subjectHash(subject.ID))
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="%[1]d" viewBox="0 0 %[1]d %[1]d">`+
+		`<rect width="100%%" height="100%%" fill="#%02x%02x%02x"/>`+
+		`<text x="50%%" y="50%%" dy=".35em" text-anchor="middle" fill="#ffffff" `+
+		`font-family="Helvetica, Arial, sans-serif" font-size="%[5]d">%[6]s</text></svg>`,
+		p.size, bg.R, bg.G, bg.B, p.size*2/5, html.EscapeString(initials(subject)))
+	return &Avatar{Data: []byte(svg), ContentType: "image/svg+xml"}, nil
+}
+
+// initials returns up to two uppercase initials from the name, falling back to the email
+func initials(subject Subject) string {
+	name := strings.TrimSpace(subject.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(subject.Email, "@")
+	}
+
+	var letters []rune
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '.' || r == '_' || r == '-'
+	}) {
+		r := []rune(word)[0]
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			letters = append(letters, unicode.ToUpper(r))
+		}
+		if len(letters) == 2 {
+			break
+		}
+	}
+	if len(letters) == 0 {
+		return "?"
+	}
+	return string(letters)
+}
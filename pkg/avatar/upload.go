@@ -0,0 +1,158 @@
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // GIF decoder
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+
+	"github.com/hewenyu/gin-pkg/pkg/storage"
+)
+
+// uploadPrefix is where uploaded avatars are kept
+const uploadPrefix = "avatars/uploads/"
+
+// defaultMaxPixels bounds the decoded size of an upload, so a small file cannot expand
+// into a huge bitmap
+const defaultMaxPixels = 4096 * 4096
+
+// ErrInvalidImage is returned for uploads that are not a PNG, JPEG or GIF image
+var ErrInvalidImage = errors.New("invalid image, upload a PNG, JPEG or GIF file")
+
+// UploadOptions configures uploaded avatars
+type UploadOptions struct {
+	// Size is the edge of the stored square image; larger images are scaled down
+	Size int
+	// MaxPixels rejects images with more pixels; it defaults to 4096x4096
+	MaxPixels int
+	// BaseURL is the URL uploaded avatars are served from: the URL of an avatar is
+	// BaseURL/<user ID>/<file>, stored under avatars/uploads/<user ID>/<file>
+	BaseURL string
+}
+
+// Uploader validates, crops and resizes uploaded avatars and stores them
+type Uploader struct {
+	storage storage.Storage
+	options UploadOptions
+}
+
+// NewUploader creates a new avatar uploader
+func NewUploader(store storage.Storage, options UploadOptions) *Uploader {
+	if options.MaxPixels == 0 {
+		options.MaxPixels = defaultMaxPixels
+	}
+	options.BaseURL = strings.TrimRight(options.BaseURL, "/")
+	return &Uploader{storage: store, options: options}
+}
+
+// Upload stores an uploaded image as the avatar of a user and returns its URL. The image is
+// cropped to a square around its center and scaled down to Size. PNG and GIF images are
+// stored as PNG, JPEG images as JPEG. File names are content hashes, so URLs can be cached forever.
+func (u *Uploader) Upload(ctx context.Context, userID string, data []byte) (string, error) {
+	if userID == "" || strings.ContainsAny(userID, "/.") {
+		return "", fmt.Errorf("invalid user ID: %q", userID)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > u.options.MaxPixels {
+		return "", fmt.Errorf("image is too large: %dx%d", config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrInvalidImage
+	}
+
+	resized := resizeSquare(img, u.options.Size)
+
+	var buf bytes.Buffer
+	ext, contentType := ".png", "image/png"
+	if format == "jpeg" {
+		ext, contentType = ".jpg", "image/jpeg"
+		err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, resized)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode avatar: %w", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	name := fmt.Sprintf("%s/%x%s", userID, sum[:12], ext)
+	if err := u.storage.Put(ctx, uploadPrefix+name, buf.Bytes(), contentType); err != nil {
+		return "", err
+	}
+	return u.options.BaseURL + "/" + name, nil
+}
+
+// Get reads an uploaded avatar by the <user ID>/<file> part of its URL
+func (u *Uploader) Get(ctx context.Context, name string) (*storage.Object, error) {
+	if !validUploadName(name) {
+		return nil, storage.ErrNotExist
+	}
+	return u.storage.Get(ctx, uploadPrefix+name)
+}
+
+// Remove deletes an avatar stored by Upload, given its URL. URLs that were not produced
+// by Upload, such as external images, are ignored.
+func (u *Uploader) Remove(ctx context.Context, avatarURL string) error {
+	name, ok := strings.CutPrefix(avatarURL, u.options.BaseURL+"/")
+	if !ok || !validUploadName(name) {
+		return nil
+	}
+	return u.storage.Delete(ctx, uploadPrefix+name)
+}
+
+// validUploadName reports whether name has the <user ID>/<file> form of uploaded avatars
+func validUploadName(name string) bool {
+	dir, file := path.Split(name)
+	return dir != "" && file != "" && !strings.Contains(name, "..") && strings.Count(name, "/") == 1
+}
+
+// resizeSquare crops img to a centered square and scales it down to size by averaging
+// the source pixels covered by each target pixel. Smaller images are only cropped.
+func resizeSquare(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	edge := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, edge, edge).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-edge)/2,
+		bounds.Min.Y+(bounds.Dy()-edge)/2,
+	))
+
+	src := image.NewRGBA(image.Rect(0, 0, edge, edge))
+	draw.Draw(src, src.Bounds(), img, crop.Min, draw.Src)
+	if size <= 0 || edge <= size {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*edge/size, (y+1)*edge/size
+		for x := 0; x < size; x++ {
+			x0, x1 := x*edge/size, (x+1)*edge/size
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r, g, b, a = r+int(p[0]), g+int(p[1]), b+int(p[2]), a+int(p[3])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,129 @@
+package dbbackup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/storage"
+)
+
+// ContentType is the content type backups are stored with
+const ContentType = "application/vnd.postgresql.custom-dump"
+
+// Connection is the PostgreSQL database to back up and restore
+type Connection struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Database string
+	SSLMode  string
+}
+
+// Options configures a Service
+type Options struct {
+	// Prefix is prepended to the keys of the backups, e.g. "backups/"
+	Prefix string
+	// PgDumpPath and PgRestorePath are the executables to run, looked up in PATH when they
+	// contain no slash; they default to pg_dump and pg_restore
+	PgDumpPath    string
+	PgRestorePath string
+}
+
+// Service dumps a PostgreSQL database with pg_dump into a storage and restores it from there
+// with pg_restore. Dumps use the custom format and are held in memory while they are
+// transferred.
+type Service struct {
+	conn    Connection
+	storage storage.Storage
+	opts    Options
+	now     func() time.Time
+}
+
+// New creates a Service that backs up the database of conn to store
+func New(conn Connection, store storage.Storage, opts Options) *Service {
+	if opts.PgDumpPath == "" {
+		opts.PgDumpPath = "pg_dump"
+	}
+	if opts.PgRestorePath == "" {
+		opts.PgRestorePath = "pg_restore"
+	}
+	return &Service{conn: conn, storage: store, opts: opts, now: time.Now}
+}
+
+// Backup dumps the database and stores the dump, returning its key
+func (s *Service) Backup(ctx context.Context) (string, error) {
+	var stdout bytes.Buffer
+	cmd := s.command(ctx, s.opts.PgDumpPath, "--format=custom", "--no-owner", "--no-privileges")
+	cmd.Stdout = &stdout
+	if err := run(cmd); err != nil {
+		return "", fmt.Errorf("pg_dump failed: %w", err)
+	}
+
+	key := s.opts.Prefix + s.now().UTC().Format("20060102T150405Z") + ".dump"
+	if err := s.storage.Put(ctx, key, stdout.Bytes(), ContentType); err != nil {
+		return "", fmt.Errorf("failed to store backup %s: %w", key, err)
+	}
+	return key, nil
+}
+
+// Restore replaces the objects in the database with those of the backup stored under key.
+// Objects the backup does not contain are left alone. The restore runs in a single
+// transaction, so it either succeeds completely or leaves the database unchanged.
+func (s *Service) Restore(ctx context.Context, key string) error {
+	if !strings.HasPrefix(key, s.opts.Prefix) || !strings.HasSuffix(key, ".dump") {
+		return fmt.Errorf("%q is not a backup key", key)
+	}
+	object, err := s.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			return fmt.Errorf("backup %s does not exist", key)
+		}
+		return fmt.Errorf("failed to read backup %s: %w", key, err)
+	}
+
+	cmd := s.command(ctx, s.opts.PgRestorePath, "--clean", "--if-exists", "--no-owner", "--no-privileges",
+		"--single-transaction", "--exit-on-error", "--dbname="+s.conn.Database)
+	cmd.Stdin = bytes.NewReader(object.Data)
+	if err := run(cmd); err != nil {
+		return fmt.Errorf("pg_restore failed: %w", err)
+	}
+	return nil
+}
+
+// command returns a command connecting to the database. The connection is passed in the
+// environment, which keeps the password out of the process list.
+func (s *Service) command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(),
+		"PGHOST="+s.conn.Host,
+		"PGPORT="+strconv.Itoa(s.conn.Port),
+		"PGUSER="+s.conn.Username,
+		"PGPASSWORD="+s.conn.Password,
+		"PGDATABASE="+s.conn.Database,
+	)
+	if s.conn.SSLMode != "" {
+		cmd.Env = append(cmd.Env, "PGSSLMODE="+s.conn.SSLMode)
+	}
+	return cmd
+}
+
+// run runs cmd, adding its standard error output to the error it fails with
+func run(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package dbbackup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/storage"
+)
+
+// writeScript writes an executable shell script to dir
+func writeScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestService(t *testing.T, dumpScript, restoreScript string) (*Service, storage.Storage, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(filepath.Join(dir, "storage"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := New(Connection{
+		Host:     "db.internal",
+		Port:     5432,
+		Username: "app",
+		Password: "secret",
+		Database: "appdb",
+		SSLMode:  "require",
+	}, store, Options{
+		Prefix:        "backups/",
+		PgDumpPath:    writeScript(t, dir, "pg_dump", dumpScript),
+		PgRestorePath: writeScript(t, dir, "pg_restore", restoreScript),
+	})
+	svc.now = func() time.Time { return time.Date(2026, 10, 17, 3, 4, 5, 0, time.UTC) }
+	return svc, store, dir
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	svc, store, dir := newTestService(t,
+		`echo "dump of $PGDATABASE on $PGHOST:$PGPORT as $PGUSER/$PGPASSWORD sslmode=$PGSSLMODE $*"`,
+		`cat > "$(dirname "$0")/restored"; echo "$*" > "$(dirname "$0")/args"`,
+	)
+	ctx := context.Background()
+
+	key, err := svc.Backup(ctx)
+	if err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	if key != "backups/20261017T030405Z.dump" {
+		t.Fatalf("unexpected key %q", key)
+	}
+	object, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("backup not stored: %v", err)
+	}
+	want := "dump of appdb on db.internal:5432 as app/secret sslmode=require --format=custom --no-owner --no-privileges\n"
+	if string(object.Data) != want {
+		t.Fatalf("unexpected dump %q", object.Data)
+	}
+
+	if err := svc.Restore(ctx, key); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	restored, err := os.ReadFile(filepath.Join(dir, "restored"))
+	if err != nil || string(restored) != want {
+		t.Fatalf("pg_restore read %q, %v", restored, err)
+	}
+	args, _ := os.ReadFile(filepath.Join(dir, "args"))
+	for _, arg := range []string{"--single-transaction", "--clean", "--dbname=appdb"} {
+		if !strings.Contains(string(args), arg) {
+			t.Errorf("pg_restore was not passed %s: %q", arg, args)
+		}
+	}
+}
+
+func TestBackupFailure(t *testing.T) {
+	svc, _, _ := newTestService(t, `echo "connection refused" >&2; exit 1`, `exit 0`)
+
+	_, err := svc.Backup(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("expected the pg_dump error, got %v", err)
+	}
+}
+
+func TestRestoreRejectsOtherKeys(t *testing.T) {
+	svc, store, _ := newTestService(t, `exit 0`, `exit 0`)
+	ctx := context.Background()
+	if err := store.Put(ctx, "avatars/a.png", []byte("png"), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"avatars/a.png", "backups/missing.dump"} {
+		if err := svc.Restore(ctx, key); err == nil {
+			t.Errorf("Restore(%q) succeeded", key)
+		}
+	}
+}
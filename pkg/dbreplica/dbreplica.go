@@ -0,0 +1,254 @@
+// Package dbreplica routes the read-only queries of an ent client to read replicas, while
+// writes, transactions and locking reads go to the primary. Replicas that fail a health
+// check or lose their connection are left out until they pass a check again; without a
+// healthy replica every query goes to the primary.
+package dbreplica
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"expvar"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Key is the gin context key that, set to true, makes a request read from the primary
+const Key = "readPrimary"
+
+type primaryContextKey struct{}
+
+// Primary returns a copy of ctx whose queries read from the primary, e.g. to read back
+// what the request has just written
+func Primary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// UsesPrimary reports whether ctx reads from the primary. It accepts both request contexts
+// and *gin.Context, which keeps the override under Key.
+func UsesPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	if primary, ok := ctx.Value(primaryContextKey{}).(bool); ok {
+		return primary
+	}
+	primary, _ := ctx.Value(Key).(bool)
+	return primary
+}
+
+// Replica is a read replica of the primary database
+type Replica struct {
+	// Name identifies the replica in logs and metrics, e.g. its address
+	Name   string
+	Driver dialect.Driver
+	// Ping checks that the replica can serve queries
+	Ping func(ctx context.Context) error
+}
+
+// Options configures the replica routing
+type Options struct {
+	// HealthCheckInterval is how often Run pings the replicas (default 10s)
+	HealthCheckInterval time.Duration
+}
+
+// replica is a replica with its health and metrics
+type replica struct {
+	Replica
+	healthy atomic.Bool
+	metrics *expvar.Map
+	queries *expvar.Int
+	errors  *expvar.Int
+}
+
+// Driver is an ent dialect.Driver that sends read-only queries to healthy replicas in
+// turn and everything else to the primary
+type Driver struct {
+	primary  dialect.Driver
+	replicas []*replica
+	options  Options
+	next     atomic.Uint64
+}
+
+// metrics are published as the expvar map "db.replicas", with a map per replica
+var metrics = new(expvar.Map).Init()
+
+var publishMetrics sync.Once
+
+// New creates a driver routing reads to replicas. Replicas start out healthy.
+func New(primary dialect.Driver, replicas []Replica, options Options) *Driver {
+	if options.HealthCheckInterval <= 0 {
+		options.HealthCheckInterval = 10 * time.Second
+	}
+	publishMetrics.Do(func() { expvar.Publish("db.replicas", metrics) })
+
+	d := &Driver{
+		primary: primary,
+		options: options,
+	}
+	for _, r := range replicas {
+		state := &replica{
+			Replica: r,
+			metrics: new(expvar.Map).Init(),
+			queries: new(expvar.Int),
+			errors:  new(expvar.Int),
+		}
+		state.healthy.Store(true)
+		state.metrics.Set("queries", state.queries)
+		state.metrics.Set("errors", state.errors)
+		state.metrics.Set("healthy", expvar.Func(func() any { return state.healthy.Load() }))
+		metrics.Set(r.Name, state.metrics)
+		d.replicas = append(d.replicas, state)
+	}
+	return d
+}
+
+// Exec runs a statement on the primary
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) error {
+	return d.primary.Exec(ctx, query, args, v)
+}
+
+// Query runs read-only queries on a healthy replica, unless ctx reads from the primary,
+// and other queries, such as inserts returning their rows, on the primary. A query that
+// fails because the replica is unreachable is retried on the primary.
+func (d *Driver) Query(ctx context.Context, query string, args, v any) error {
+	if UsesPrimary(ctx) || !readOnly(query) {
+		return d.primary.Query(ctx, query, args, v)
+	}
+	r := d.pick()
+	if r == nil {
+		return d.primary.Query(ctx, query, args, v)
+	}
+
+	r.queries.Add(1)
+	err := r.Driver.Query(ctx, query, args, v)
+	if err != nil && connectionError(err) {
+		r.errors.Add(1)
+		d.markDown(r, err)
+		return d.primary.Query(ctx, query, args, v)
+	}
+	return err
+}
+
+// Tx starts a transaction on the primary; all its statements run there
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d.primary.Tx(ctx)
+}
+
+// BeginTx starts a transaction with options on the primary, for ent clients that set the
+// isolation level or start read-only transactions
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	if beginner, ok := d.primary.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	}); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return d.primary.Tx(ctx)
+}
+
+// Close closes the primary and the replicas
+func (d *Driver) Close() error {
+	err := d.primary.Close()
+	for _, r := range d.replicas {
+		if closeErr := r.Driver.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Dialect returns the dialect of the primary
+func (d *Driver) Dialect() string {
+	return d.primary.Dialect()
+}
+
+// Run pings the replicas every HealthCheckInterval until ctx is cancelled, taking those
+// that fail out of rotation and returning those that recover
+func (d *Driver) Run(ctx context.Context) {
+	if len(d.replicas) == 0 {
+		return
+	}
+	ticker := time.NewTicker(d.options.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		d.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth pings every replica once and updates its health
+func (d *Driver) CheckHealth(ctx context.Context) {
+	for _, r := range d.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, d.options.HealthCheckInterval)
+		err := r.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			d.markDown(r, err)
+			continue
+		}
+		if !r.healthy.Swap(true) {
+			logger.Infof("Database replica %s is healthy again", r.Name)
+		}
+	}
+}
+
+// Healthy returns the names of the replicas currently in rotation
+func (d *Driver) Healthy() []string {
+	var names []string
+	for _, r := range d.replicas {
+		if r.healthy.Load() {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// pick returns the next healthy replica, or nil when there is none
+func (d *Driver) pick() *replica {
+	n := uint64(len(d.replicas))
+	start := d.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if r := d.replicas[(start+i)%n]; r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// markDown takes a replica out of rotation until it passes a health check
+func (d *Driver) markDown(r *replica, err error) {
+	if r.healthy.Swap(false) {
+		logger.Warnf("Database replica %s is unavailable, reading from the primary: %v", r.Name, err)
+	}
+}
+
+// readOnly reports whether a query only reads and may run on a replica. Locking reads must
+// run on the primary.
+func readOnly(query string) bool {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	if !strings.HasPrefix(query, "SELECT") {
+		return false
+	}
+	return !strings.Contains(query, " FOR UPDATE") &&
+		!strings.Contains(query, " FOR SHARE") &&
+		!strings.Contains(query, " LOCK IN SHARE MODE")
+}
+
+// connectionError reports whether err means the database could not be reached, rather than
+// that the query failed
+func connectionError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr)
+}
@@ -0,0 +1,209 @@
+package dbreplica
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeDriver records the statements it runs
+type fakeDriver struct {
+	name string
+	err  error
+	log  *[]string
+}
+
+func (f *fakeDriver) Exec(ctx context.Context, query string, args, v any) error {
+	*f.log = append(*f.log, f.name+": "+query)
+	return f.err
+}
+
+func (f *fakeDriver) Query(ctx context.Context, query string, args, v any) error {
+	*f.log = append(*f.log, f.name+": "+query)
+	return f.err
+}
+
+func (f *fakeDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	*f.log = append(*f.log, f.name+": BEGIN")
+	return dialect.NopTx(f), nil
+}
+
+func (f *fakeDriver) Close() error    { return nil }
+func (f *fakeDriver) Dialect() string { return dialect.Postgres }
+
+func newTestDriver(replicaErrs ...error) (*Driver, *[]string) {
+	var log []string
+	var replicas []Replica
+	for i, err := range replicaErrs {
+		name := []string{"r1", "r2"}[i]
+		replicas = append(replicas, Replica{
+			Name:   name,
+			Driver: &fakeDriver{name: name, err: err, log: &log},
+			Ping:   func(ctx context.Context) error { return nil },
+		})
+	}
+	return New(&fakeDriver{name: "primary", log: &log}, replicas, Options{}), &log
+}
+
+func TestDriverRouting(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set(Key, true)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		run  func(ctx context.Context, d *Driver) error
+		want []string
+	}{
+		{
+			name: "select",
+			ctx:  context.Background(),
+			run: func(ctx context.Context, d *Driver) error {
+				return d.Query(ctx, `SELECT "id" FROM "users"`, nil, nil)
+			},
+			want: []string{`r1: SELECT "id" FROM "users"`},
+		},
+		{
+			name: "insert returning",
+			ctx:  context.Background(),
+			run: func(ctx context.Context, d *Driver) error {
+				return d.Query(ctx, `INSERT INTO "users" ("id") VALUES ($1) RETURNING "id"`, nil, nil)
+			},
+			want: []string{`primary: INSERT INTO "users" ("id") VALUES ($1) RETURNING "id"`},
+		},
+		{
+			name: "exec",
+			ctx:  context.Background(),
+			run: func(ctx context.Context, d *Driver) error {
+				return d.Exec(ctx, `UPDATE "users" SET "active" = $1`, nil, nil)
+			},
+			want: []string{`primary: UPDATE "users" SET "active" = $1`},
+		},
+		{
+			name: "locking read",
+			ctx:  context.Background(),
+			run: func(ctx context.Context, d *Driver) error {
+				return d.Query(ctx, `SELECT "id" FROM "users" FOR UPDATE`, nil, nil)
+			},
+			want: []string{`primary: SELECT "id" FROM "users" FOR UPDATE`},
+		},
+		{
+			name: "transaction",
+			ctx:  context.Background(),
+			run: func(ctx context.Context, d *Driver) error {
+				tx, err := d.Tx(ctx)
+				if err != nil {
+					return err
+				}
+				return tx.Query(ctx, `SELECT "id" FROM "users"`, nil, nil)
+			},
+			want: []string{"primary: BEGIN", `primary: SELECT "id" FROM "users"`},
+		},
+		{
+			name: "primary context",
+			ctx:  Primary(context.Background()),
+			run: func(ctx context.Context, d *Driver) error {
+				return d.Query(ctx, `SELECT "id" FROM "users"`, nil, nil)
+			},
+			want: []string{`primary: SELECT "id" FROM "users"`},
+		},
+		{
+			name: "primary gin context",
+			ctx:  c,
+			run: func(ctx context.Context, d *Driver) error {
+				return d.Query(ctx, `SELECT "id" FROM "users"`, nil, nil)
+			},
+			want: []string{`primary: SELECT "id" FROM "users"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, log := newTestDriver(nil)
+			if err := tt.run(tt.ctx, d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*log, tt.want) {
+				t.Errorf("ran %q, want %q", *log, tt.want)
+			}
+		})
+	}
+}
+
+func TestDriverRoundRobin(t *testing.T) {
+	d, log := newTestDriver(nil, nil)
+	for i := 0; i < 4; i++ {
+		d.Query(context.Background(), "SELECT 1", nil, nil)
+	}
+	want := []string{"r2: SELECT 1", "r1: SELECT 1", "r2: SELECT 1", "r1: SELECT 1"}
+	if !reflect.DeepEqual(*log, want) {
+		t.Errorf("ran %q, want %q", *log, want)
+	}
+}
+
+func TestDriverFailover(t *testing.T) {
+	d, log := newTestDriver(driver.ErrBadConn)
+
+	// The query is retried on the primary and the replica is left out from then on
+	if err := d.Query(context.Background(), "SELECT 1", nil, nil); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	d.Query(context.Background(), "SELECT 2", nil, nil)
+	want := []string{"r1: SELECT 1", "primary: SELECT 1", "primary: SELECT 2"}
+	if !reflect.DeepEqual(*log, want) {
+		t.Errorf("ran %q, want %q", *log, want)
+	}
+	if healthy := d.Healthy(); len(healthy) != 0 {
+		t.Errorf("Healthy() = %v, want none", healthy)
+	}
+
+	// A passing health check returns it to rotation
+	d.CheckHealth(context.Background())
+	if healthy := d.Healthy(); !reflect.DeepEqual(healthy, []string{"r1"}) {
+		t.Errorf("Healthy() after a passing check = %v, want [r1]", healthy)
+	}
+}
+
+func TestDriverQueryError(t *testing.T) {
+	queryErr := errors.New("syntax error")
+	d, log := newTestDriver(queryErr)
+
+	// Errors of the query itself are returned as they are
+	if err := d.Query(context.Background(), "SELECT", nil, nil); !errors.Is(err, queryErr) {
+		t.Errorf("Query() error = %v, want %v", err, queryErr)
+	}
+	if len(*log) != 1 || len(d.Healthy()) != 1 {
+		t.Errorf("ran %q with healthy replicas %v, want the replica kept in rotation", *log, d.Healthy())
+	}
+}
+
+func TestDriverHealthCheck(t *testing.T) {
+	var log []string
+	down := true
+	d := New(&fakeDriver{name: "primary", log: &log}, []Replica{{
+		Name:   "r1",
+		Driver: &fakeDriver{name: "r1", log: &log},
+		Ping: func(ctx context.Context) error {
+			if down {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	}}, Options{})
+
+	d.CheckHealth(context.Background())
+	d.Query(context.Background(), "SELECT 1", nil, nil)
+	down = false
+	d.CheckHealth(context.Background())
+	d.Query(context.Background(), "SELECT 2", nil, nil)
+
+	want := []string{"primary: SELECT 1", "r1: SELECT 2"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("ran %q, want %q", log, want)
+	}
+}
@@ -0,0 +1,161 @@
+// Package dbtenant gives every tenant of a multi-tenant deployment its own PostgreSQL schema.
+// A request's context carries its tenant, and Driver runs the statements of the context in
+// that tenant's schema; contexts without a tenant use the shared schema.
+package dbtenant
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"entgo.io/ent/dialect"
+)
+
+// Key is the gin context key holding the tenant whose schema a request uses. It is set,
+// possibly to "", on every request of a deployment with a schema per tenant.
+const Key = "dbTenant"
+
+// SchemaPrefix prefixes the tenant ID in the name of the tenant's schema
+const SchemaPrefix = "tenant_"
+
+type tenantContextKey struct{}
+
+// With returns a copy of ctx using the schema of tenant; "" uses the shared schema
+func With(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// From returns the tenant whose schema ctx uses, or "" for the shared schema. It accepts
+// both request contexts and *gin.Context, which keeps the tenant under Key.
+func From(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok {
+		return tenant
+	}
+	tenant, _ := ctx.Value(Key).(string)
+	return tenant
+}
+
+// Schema returns the name of the schema of tenant
+func Schema(tenant string) string {
+	return SchemaPrefix + tenant
+}
+
+// ScopedKey returns key prefixed with the tenant of ctx, for caches shared by all tenants.
+// Keys of the shared schema are returned unchanged.
+func ScopedKey(ctx context.Context, key string) string {
+	if tenant := From(ctx); tenant != "" {
+		return Schema(tenant) + ":" + key
+	}
+	return key
+}
+
+// SplitScopedKey returns the tenant and the key of a key returned by ScopedKey, for keys
+// whose tenant is only known from the key, e.g. when flushing buffered writes
+func SplitScopedKey(scoped string) (tenant, key string) {
+	if rest, ok := strings.CutPrefix(scoped, SchemaPrefix); ok {
+		if tenant, key, ok := strings.Cut(rest, ":"); ok {
+			return tenant, key
+		}
+	}
+	return "", scoped
+}
+
+// Driver is an ent dialect.Driver that runs the statements of a context on the driver of
+// its tenant, whose connections use the tenant's schema, and the statements of contexts
+// without a tenant on the shared driver. Tenant drivers are opened on first use.
+type Driver struct {
+	dialect.Driver
+	open func(tenant string) (dialect.Driver, error)
+
+	mu      sync.Mutex
+	tenants map[string]dialect.Driver
+}
+
+// New creates a driver running statements on shared or on the driver open returns for the
+// tenant of their context
+func New(shared dialect.Driver, open func(tenant string) (dialect.Driver, error)) *Driver {
+	return &Driver{
+		Driver:  shared,
+		open:    open,
+		tenants: make(map[string]dialect.Driver),
+	}
+}
+
+// Tenant returns the driver of tenant, opening it on first use; "" returns the shared driver
+func (d *Driver) Tenant(tenant string) (dialect.Driver, error) {
+	if tenant == "" {
+		return d.Driver, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if drv, ok := d.tenants[tenant]; ok {
+		return drv, nil
+	}
+	drv, err := d.open(tenant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the database of tenant %s: %w", tenant, err)
+	}
+	d.tenants[tenant] = drv
+	return drv, nil
+}
+
+// Exec runs a statement in the schema of the tenant of ctx
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) error {
+	drv, err := d.Tenant(From(ctx))
+	if err != nil {
+		return err
+	}
+	return drv.Exec(ctx, query, args, v)
+}
+
+// Query runs a query in the schema of the tenant of ctx
+func (d *Driver) Query(ctx context.Context, query string, args, v any) error {
+	drv, err := d.Tenant(From(ctx))
+	if err != nil {
+		return err
+	}
+	return drv.Query(ctx, query, args, v)
+}
+
+// Tx starts a transaction in the schema of the tenant of ctx; all its statements run there
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	drv, err := d.Tenant(From(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return drv.Tx(ctx)
+}
+
+// BeginTx starts a transaction with options in the schema of the tenant of ctx, for ent
+// clients that set the isolation level or start read-only transactions
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	drv, err := d.Tenant(From(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if beginner, ok := drv.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	}); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return drv.Tx(ctx)
+}
+
+// Close closes the shared driver and the drivers of the tenants
+func (d *Driver) Close() error {
+	err := d.Driver.Close()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for tenant, drv := range d.tenants {
+		if closeErr := drv.Close(); err == nil {
+			err = closeErr
+		}
+		delete(d.tenants, tenant)
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package dbtenant
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeDriver records the statements it runs
+type fakeDriver struct {
+	name   string
+	log    *[]string
+	closed bool
+}
+
+func (f *fakeDriver) Exec(ctx context.Context, query string, args, v any) error {
+	*f.log = append(*f.log, f.name+": "+query)
+	return nil
+}
+
+func (f *fakeDriver) Query(ctx context.Context, query string, args, v any) error {
+	*f.log = append(*f.log, f.name+": "+query)
+	return nil
+}
+
+func (f *fakeDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	*f.log = append(*f.log, f.name+": BEGIN")
+	return dialect.NopTx(f), nil
+}
+
+func (f *fakeDriver) Close() error    { f.closed = true; return nil }
+func (f *fakeDriver) Dialect() string { return dialect.Postgres }
+
+func TestDriverRouting(t *testing.T) {
+	var log []string
+	var opened []string
+	tenants := map[string]*fakeDriver{}
+	shared := &fakeDriver{name: "shared", log: &log}
+	d := New(shared, func(tenant string) (dialect.Driver, error) {
+		if tenant == "unknown" {
+			return nil, errors.New("no such tenant")
+		}
+		opened = append(opened, tenant)
+		tenants[tenant] = &fakeDriver{name: Schema(tenant), log: &log}
+		return tenants[tenant], nil
+	})
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set(Key, "globex")
+
+	ctx := context.Background()
+	d.Query(ctx, "SELECT 1", nil, nil)
+	d.Exec(With(ctx, "acme"), "UPDATE 2", nil, nil)
+	d.Query(With(ctx, "acme"), "SELECT 3", nil, nil)
+	tx, _ := d.Tx(c)
+	tx.Exec(ctx, "INSERT 4", nil, nil)
+
+	want := []string{"shared: SELECT 1", "tenant_acme: UPDATE 2", "tenant_acme: SELECT 3", "tenant_globex: BEGIN", "tenant_globex: INSERT 4"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("ran %q, want %q", log, want)
+	}
+	// Tenant drivers are opened once and reused
+	if !reflect.DeepEqual(opened, []string{"acme", "globex"}) {
+		t.Errorf("opened %q, want acme and globex once", opened)
+	}
+
+	if err := d.Query(With(ctx, "unknown"), "SELECT 5", nil, nil); err == nil {
+		t.Error("Query() for a tenant that fails to open succeeded, want an error")
+	}
+
+	d.Close()
+	if !shared.closed || !tenants["acme"].closed || !tenants["globex"].closed {
+		t.Error("Close() did not close the shared and tenant drivers")
+	}
+}
+
+func TestScopedKey(t *testing.T) {
+	ctx := context.Background()
+	if key := ScopedKey(ctx, "stats:users"); key != "stats:users" {
+		t.Errorf("ScopedKey() without a tenant = %q, want the key unchanged", key)
+	}
+	if key := ScopedKey(With(ctx, "acme"), "stats:users"); key != "tenant_acme:stats:users" {
+		t.Errorf("ScopedKey() = %q, want tenant_acme:stats:users", key)
+	}
+
+	for _, key := range []string{"stats:users", "tenant_acme:stats:users"} {
+		tenant, unscoped := SplitScopedKey(key)
+		if unscoped != "stats:users" || ScopedKey(With(ctx, tenant), unscoped) != key {
+			t.Errorf("SplitScopedKey(%q) = %q, %q, want the tenant and key it was scoped from", key, tenant, unscoped)
+		}
+	}
+}
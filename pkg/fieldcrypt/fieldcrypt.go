@@ -0,0 +1,269 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// prefix marks encrypted values: "enc:v1:<key id>:<base64 of nonce and ciphertext>"
+const prefix = "enc:v1:"
+
+var (
+	// ErrNotEncrypted is returned when decrypting a value that Encrypt did not produce
+	ErrNotEncrypted = errors.New("value is not encrypted")
+	// ErrUnknownKey is returned for values encrypted with a key that is no longer configured
+	ErrUnknownKey = errors.New("encryption key is not configured")
+	// ErrDecryptionFailed is returned for tampered values or values bound to another context
+	ErrDecryptionFailed = errors.New("failed to decrypt value")
+)
+
+// KeySource loads the data encryption keys by ID and the ID of the key new values are
+// encrypted with. StaticKeys serves keys from configuration; a KMS integration can fetch
+// or unwrap them instead.
+type KeySource func(ctx context.Context) (currentKeyID string, keys map[string][]byte, err error)
+
+// StaticKeys serves fixed keys, e.g. base64-decoded from configuration
+func StaticKeys(currentKeyID string, keys map[string][]byte) KeySource {
+	return func(context.Context) (string, map[string][]byte, error) {
+		return currentKeyID, keys, nil
+	}
+}
+
+// Encryptor encrypts sensitive fields with AES-256-GCM. Every value records the ID of its
+// key, so keys can be rotated: new values use the current key while values under older
+// keys stay readable as long as those keys are configured, and Rotate re-encrypts them.
+type Encryptor struct {
+	currentKeyID string
+	aeads        map[string]cipher.AEAD
+	// nonceKeys derive the nonces of searchable values, one per key
+	nonceKeys map[string][]byte
+}
+
+// New loads the keys from source. Keys must be 32 bytes and IDs must not contain ":".
+func New(ctx context.Context, source KeySource) (*Encryptor, error) {
+	currentKeyID, keys, err := source(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+	if _, ok := keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("current encryption key %q is not configured", currentKeyID)
+	}
+
+	e := &Encryptor{
+		currentKeyID: currentKeyID,
+		aeads:        make(map[string]cipher.AEAD, len(keys)),
+		nonceKeys:    make(map[string][]byte, len(keys)),
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid encryption key id %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %q must be 32 bytes, got %d", id, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		e.aeads[id] = aead
+		// 可检索加密的 nonce 使用派生密钥，不直接复用加密密钥
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte("fieldcrypt searchable nonce"))
+		e.nonceKeys[id] = mac.Sum(nil)
+	}
+	return e, nil
+}
+
+// Encrypt encrypts plaintext with the current key. The value can only be decrypted with
+// the same context, e.g. "users.phone:<user id>", so encrypted values cannot be copied
+// between fields or records.
+func (e *Encryptor) Encrypt(plaintext, context string) (string, error) {
+	aead := e.aeads[e.currentKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(context))
+	return prefix + e.currentKeyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// EncryptSearchable encrypts plaintext with the current key like Encrypt, but always to the
+// same value for the same plaintext, context and key, so encrypted columns can keep unique
+// indexes and be looked up by equality with SearchValues. The price is that equal values
+// can be recognized, so use it only for fields that need lookups.
+func (e *Encryptor) EncryptSearchable(plaintext, context string) string {
+	return e.encryptSearchable(e.currentKeyID, plaintext, context)
+}
+
+// SearchValues returns the values EncryptSearchable produces for plaintext under every
+// configured key, for lookups that also find values not yet rotated to the current key
+func (e *Encryptor) SearchValues(plaintext, context string) []string {
+	values := make([]string, 0, len(e.aeads))
+	values = append(values, e.EncryptSearchable(plaintext, context))
+	for keyID := range e.aeads {
+		if keyID != e.currentKeyID {
+			values = append(values, e.encryptSearchable(keyID, plaintext, context))
+		}
+	}
+	return values
+}
+
+// encryptSearchable encrypts plaintext with a key and a nonce derived from the plaintext
+// and context. The context is included so equal values of different fields differ.
+func (e *Encryptor) encryptSearchable(keyID, plaintext, context string) string {
+	aead := e.aeads[keyID]
+	mac := hmac.New(sha256.New, e.nonceKeys[keyID])
+	mac.Write([]byte(context))
+	mac.Write([]byte{0})
+	mac.Write([]byte(plaintext))
+	nonce := mac.Sum(nil)[:aead.NonceSize()]
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(context))
+	return prefix + keyID + ":" + base64.StdEncoding.EncodeToString(sealed)
+}
+
+// Decrypt decrypts a value produced by Encrypt or EncryptSearchable with the same context
+func (e *Encryptor) Decrypt(value, context string) (string, error) {
+	keyID, sealed, err := e.parse(value)
+	if err != nil {
+		return "", err
+	}
+	aead, ok := e.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", ErrDecryptionFailed
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, []byte(context))
+	if err != nil {
+		return "", ErrDecryptionFailed
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether value looks like a value produced by Encrypt, e.g. to
+// encrypt existing plaintext data in place
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// NeedsRotation reports whether value is encrypted with a key other than the current one
+func (e *Encryptor) NeedsRotation(value string) bool {
+	keyID, _, err := e.parse(value)
+	return err == nil && keyID != e.currentKeyID
+}
+
+// Rotate re-encrypts value with the current key; values already using it are returned as is
+func (e *Encryptor) Rotate(value, context string) (string, error) {
+	if !e.NeedsRotation(value) {
+		if !IsEncrypted(value) {
+			return "", ErrNotEncrypted
+		}
+		return value, nil
+	}
+	plaintext, err := e.Decrypt(value, context)
+	if err != nil {
+		return "", err
+	}
+	return e.Encrypt(plaintext, context)
+}
+
+// EncryptFields encrypts the string and *string fields of the struct v points to that are
+// tagged `encrypt:"true"`, binding each to context and its field name. Empty and already
+// encrypted values are left alone.
+func (e *Encryptor) EncryptFields(v any, context string) error {
+	return e.eachField(v, func(field string, value *string) error {
+		if *value == "" || IsEncrypted(*value) {
+			return nil
+		}
+		encrypted, err := e.Encrypt(*value, context+"."+field)
+		if err != nil {
+			return err
+		}
+		*value = encrypted
+		return nil
+	})
+}
+
+// DecryptFields reverses EncryptFields. Fields that are not encrypted are left alone, so
+// data written before a field was designated sensitive stays readable.
+func (e *Encryptor) DecryptFields(v any, context string) error {
+	return e.eachField(v, func(field string, value *string) error {
+		if !IsEncrypted(*value) {
+			return nil
+		}
+		plaintext, err := e.Decrypt(*value, context+"."+field)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field, err)
+		}
+		*value = plaintext
+		return nil
+	})
+}
+
+// eachField calls fn for every tagged string field of the struct v points to
+func (e *Encryptor) eachField(v any, fn func(field string, value *string) error) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("fieldcrypt: expected a pointer to a struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.Tag.Get("encrypt") != "true" {
+			continue
+		}
+		fv := rv.Field(i)
+		switch {
+		case fv.Kind() == reflect.String:
+		case fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.String:
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		default:
+			return fmt.Errorf("fieldcrypt: field %s tagged for encryption is not a string", sf.Name)
+		}
+		if !fv.CanSet() {
+			return fmt.Errorf("fieldcrypt: field %s tagged for encryption is not exported", sf.Name)
+		}
+		value := fv.String()
+		if err := fn(sf.Name, &value); err != nil {
+			return err
+		}
+		fv.SetString(value)
+	}
+	return nil
+}
+
+// parse splits an encrypted value into its key ID and the nonce and ciphertext
+func (e *Encryptor) parse(value string) (string, []byte, error) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return "", nil, ErrNotEncrypted
+	}
+	keyID, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", nil, ErrNotEncrypted
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, ErrDecryptionFailed
+	}
+	return keyID, sealed, nil
+}
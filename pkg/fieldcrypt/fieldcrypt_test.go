@@ -0,0 +1,140 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func newEncryptor(t *testing.T, current string, ids ...string) *Encryptor {
+	t.Helper()
+	keys := map[string][]byte{}
+	for _, id := range ids {
+		keys[id] = bytes.Repeat([]byte(id[:1]), 32)
+	}
+	e, err := New(context.Background(), StaticKeys(current, keys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	e := newEncryptor(t, "k1", "k1")
+
+	value, err := e.Encrypt("+8613800000000", "users.phone:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(value) {
+		t.Fatalf("Encrypt returned %q", value)
+	}
+	if got, err := e.Decrypt(value, "users.phone:1"); err != nil || got != "+8613800000000" {
+		t.Errorf("Decrypt = %q, %v", got, err)
+	}
+	if _, err := e.Decrypt(value, "users.phone:2"); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("value moved to another record: got %v, want ErrDecryptionFailed", err)
+	}
+	if _, err := e.Decrypt("+8613800000000", ""); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("plaintext: got %v, want ErrNotEncrypted", err)
+	}
+	if again, _ := e.Encrypt("+8613800000000", "users.phone:1"); again == value {
+		t.Error("encrypting the same value twice gave the same ciphertext")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old := newEncryptor(t, "k1", "k1")
+	value, _ := old.Encrypt("secret", "ctx")
+
+	rotated := newEncryptor(t, "k2", "k1", "k2")
+	if !rotated.NeedsRotation(value) {
+		t.Fatal("value under the old key does not need rotation")
+	}
+	if got, err := rotated.Decrypt(value, "ctx"); err != nil || got != "secret" {
+		t.Errorf("value under the old key: Decrypt = %q, %v", got, err)
+	}
+	value, err := rotated.Rotate(value, "ctx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.NeedsRotation(value) {
+		t.Error("rotated value still needs rotation")
+	}
+
+	retired := newEncryptor(t, "k2", "k2")
+	if got, err := retired.Decrypt(value, "ctx"); err != nil || got != "secret" {
+		t.Errorf("rotated value after retiring the old key: Decrypt = %q, %v", got, err)
+	}
+	stale, _ := old.Encrypt("secret", "ctx")
+	if _, err := retired.Decrypt(stale, "ctx"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("value under a retired key: got %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestEncryptSearchable(t *testing.T) {
+	old := newEncryptor(t, "k1", "k1")
+	stale := old.EncryptSearchable("+8613800000000", "User.phone")
+
+	e := newEncryptor(t, "k2", "k1", "k2")
+	value := e.EncryptSearchable("+8613800000000", "User.phone")
+	if value == stale || e.EncryptSearchable("+8613800000000", "User.phone") != value {
+		t.Fatal("EncryptSearchable is not deterministic per key")
+	}
+	if got, err := e.Decrypt(value, "User.phone"); err != nil || got != "+8613800000000" {
+		t.Errorf("Decrypt = %q, %v", got, err)
+	}
+	if e.EncryptSearchable("+8613800000000", "User.backup_phone") == value {
+		t.Error("equal values of different fields encrypt alike")
+	}
+
+	values := e.SearchValues("+8613800000000", "User.phone")
+	if len(values) != 2 || values[0] != value || values[1] != stale {
+		t.Errorf("SearchValues = %q, want the value under the current key, then under k1", values)
+	}
+}
+
+func TestEncryptFields(t *testing.T) {
+	type profile struct {
+		Name  string
+		Phone string  `encrypt:"true"`
+		IDNo  *string `encrypt:"true"`
+		Note  *string `encrypt:"true"`
+	}
+	e := newEncryptor(t, "k1", "k1")
+	idNo := "110101199001011234"
+	p := profile{Name: "Alice", Phone: "13800000000", IDNo: &idNo}
+
+	if err := e.EncryptFields(&p, "users:1"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Alice" || !IsEncrypted(p.Phone) || !IsEncrypted(*p.IDNo) || p.Note != nil {
+		t.Fatalf("EncryptFields = %+v", p)
+	}
+	if err := e.DecryptFields(&p, "users:2"); err == nil {
+		t.Error("fields decrypted for another record")
+	}
+	if err := e.DecryptFields(&p, "users:1"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Phone != "13800000000" || *p.IDNo != "110101199001011234" {
+		t.Errorf("DecryptFields = %+v", p)
+	}
+
+	if err := e.EncryptFields(p, "users:1"); err == nil {
+		t.Error("EncryptFields accepted a struct value")
+	}
+}
+
+func TestNewValidatesKeys(t *testing.T) {
+	for name, source := range map[string]KeySource{
+		"missing current": StaticKeys("k2", map[string][]byte{"k1": make([]byte, 32)}),
+		"short key":       StaticKeys("k1", map[string][]byte{"k1": make([]byte, 16)}),
+		"invalid id":      StaticKeys("a:b", map[string][]byte{"a:b": make([]byte, 32)}),
+	} {
+		if _, err := New(context.Background(), source); err == nil {
+			t.Errorf("%s: New accepted the keys", name)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package guardrail
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// MonitorConfig configures the runtime watermark monitor. A zero watermark disables that check.
+type MonitorConfig struct {
+	GoroutineWatermark int
+	HeapWatermark      uint64 // bytes of live heap
+	CheckInterval      time.Duration
+	DumpDir            string
+	DumpCooldown       time.Duration
+}
+
+// monitorMetrics are published as the expvar map "guardrail"
+var monitorMetrics = struct {
+	goroutines      *expvar.Int
+	heapAlloc       *expvar.Int
+	goroutineBreach *expvar.Int
+	heapBreach      *expvar.Int
+	dumps           *expvar.Int
+	dumpErrors      *expvar.Int
+}{
+	goroutines:      new(expvar.Int),
+	heapAlloc:       new(expvar.Int),
+	goroutineBreach: new(expvar.Int),
+	heapBreach:      new(expvar.Int),
+	dumps:           new(expvar.Int),
+	dumpErrors:      new(expvar.Int),
+}
+
+func init() {
+	vars := new(expvar.Map).Init()
+	vars.Set("goroutines", monitorMetrics.goroutines)
+	vars.Set("heap_alloc_bytes", monitorMetrics.heapAlloc)
+	vars.Set("goroutine_watermark_breaches", monitorMetrics.goroutineBreach)
+	vars.Set("heap_watermark_breaches", monitorMetrics.heapBreach)
+	vars.Set("dumps_captured", monitorMetrics.dumps)
+	vars.Set("dump_errors", monitorMetrics.dumpErrors)
+	expvar.Publish("guardrail", vars)
+}
+
+// Monitor periodically samples the goroutine count and heap size and captures
+// goroutine and heap profiles when a watermark is exceeded
+type Monitor struct {
+	config   MonitorConfig
+	lastDump time.Time
+}
+
+// NewMonitor creates a new runtime monitor
+func NewMonitor(config MonitorConfig) *Monitor {
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = 15 * time.Second
+	}
+	if config.DumpDir == "" {
+		config.DumpDir = os.TempDir()
+	}
+	if config.DumpCooldown <= 0 {
+		config.DumpCooldown = 10 * time.Minute
+	}
+	return &Monitor{config: config}
+}
+
+// Run samples until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		m.check()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check takes one sample and reacts to breached watermarks
+func (m *Monitor) check() {
+	goroutines := runtime.NumGoroutine()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	monitorMetrics.goroutines.Set(int64(goroutines))
+	monitorMetrics.heapAlloc.Set(int64(stats.HeapAlloc))
+
+	var reasons []string
+	if m.config.GoroutineWatermark > 0 && goroutines > m.config.GoroutineWatermark {
+		monitorMetrics.goroutineBreach.Add(1)
+		reasons = append(reasons, fmt.Sprintf("goroutines %d > %d", goroutines, m.config.GoroutineWatermark))
+	}
+	if m.config.HeapWatermark > 0 && stats.HeapAlloc > m.config.HeapWatermark {
+		monitorMetrics.heapBreach.Add(1)
+		reasons = append(reasons, fmt.Sprintf("heap %d bytes > %d", stats.HeapAlloc, m.config.HeapWatermark))
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	logger.Warnf("Runtime watermark exceeded: %v", reasons)
+
+	// Dumps are expensive, capture at most one per cooldown period
+	if time.Since(m.lastDump) < m.config.DumpCooldown {
+		return
+	}
+	m.lastDump = time.Now()
+
+	dir, err := m.captureDump()
+	if err != nil {
+		monitorMetrics.dumpErrors.Add(1)
+		logger.Errorf("Failed to capture debug dump: %v", err)
+		return
+	}
+	monitorMetrics.dumps.Add(1)
+	logger.Warnf("Debug dump captured in %s", dir)
+}
+
+// captureDump writes goroutine stacks and a heap profile to a new timestamped directory
+func (m *Monitor) captureDump() (string, error) {
+	dir := filepath.Join(m.config.DumpDir, "dump-"+time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create dump directory: %w", err)
+	}
+
+	profiles := []struct {
+		name  string
+		file  string
+		debug int
+	}{
+		{"goroutine", "goroutines.txt", 2},
+		{"heap", "heap.pprof", 0},
+	}
+	for _, p := range profiles {
+		if err := writeProfile(filepath.Join(dir, p.file), p.name, p.debug); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// writeProfile writes a runtime profile to path
+func writeProfile(path, name string, debug int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := pprof.Lookup(name).WriteTo(f, debug); err != nil {
+		return fmt.Errorf("failed to write %s profile: %w", name, err)
+	}
+	return nil
+}
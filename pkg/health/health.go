@@ -0,0 +1,81 @@
+// Package health serves the liveness and readiness probes of the service
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Check is a dependency the readiness probe checks
+type Check struct {
+	// Name identifies the dependency in the probe's response
+	Name string
+	// Check returns an error if the dependency is not usable
+	Check func(ctx context.Context) error
+	// Degradable marks dependencies the service can do without for a while, e.g. Redis with
+	// the in-process fallback enabled. Their failures are reported as "degraded" but leave the
+	// service ready.
+	Degradable bool
+}
+
+// Check results reported by ReadinessHandler
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFailed   = "failed"
+)
+
+// LivenessHandler answers 200 while the process serves requests
+func LivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+	}
+}
+
+// ReadinessHandler runs checks concurrently, each within timeout, and answers 200 if the
+// service is ready or 503 if a check that is not degradable failed. The response lists the
+// result of every check, e.g. {"status":"ok","checks":{"redis":"ok"}}.
+func ReadinessHandler(checks []Check, timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		results := make([]string, len(checks))
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = StatusOK
+				if err := check.Check(ctx); err != nil {
+					logger.FromContext(c).Warnf("Readiness check %s failed: %v", check.Name, err)
+					results[i] = StatusFailed
+					if check.Degradable {
+						results[i] = StatusDegraded
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		status, code := StatusOK, http.StatusOK
+		byName := make(map[string]string, len(checks))
+		for i, check := range checks {
+			byName[check.Name] = results[i]
+			switch results[i] {
+			case StatusFailed:
+				status, code = StatusFailed, http.StatusServiceUnavailable
+			case StatusDegraded:
+				if status == StatusOK {
+					status = StatusDegraded
+				}
+			}
+		}
+		c.JSON(code, gin.H{"status": status, "checks": byName})
+	}
+}
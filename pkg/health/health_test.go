@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ok := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("down") }
+	hangs := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	cases := []struct {
+		name     string
+		checks   []Check
+		wantCode int
+		want     string
+	}{
+		{"all ok", []Check{{Name: "db", Check: ok}, {Name: "redis", Check: ok}}, http.StatusOK, StatusOK},
+		{"failed", []Check{{Name: "db", Check: ok}, {Name: "redis", Check: down}}, http.StatusServiceUnavailable, StatusFailed},
+		{"degraded", []Check{{Name: "db", Check: ok}, {Name: "redis", Check: down, Degradable: true}}, http.StatusOK, StatusDegraded},
+		{"timed out", []Check{{Name: "redis", Check: hangs}}, http.StatusServiceUnavailable, StatusFailed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/readyz", ReadinessHandler(tc.checks, 50*time.Millisecond))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			var body struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("response is not JSON: %v", err)
+			}
+			if w.Code != tc.wantCode || body.Status != tc.want {
+				t.Fatalf("got %d %s, want %d %s", w.Code, body.Status, tc.wantCode, tc.want)
+			}
+			if len(body.Checks) != len(tc.checks) {
+				t.Fatalf("checks = %v, want one result per check", body.Checks)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Package i18n translates the messages of API error responses. Messages are keyed by their
+// English text, so a message without a translation is returned unchanged.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// builtin holds the translations shipped with the package, one file per language
+//
+//go:embed messages/*.json
+var builtin embed.FS
+
+// Catalog holds the translations of messages by language
+type Catalog struct {
+	fallback language.Tag
+	// tags lists the fallback first, then every language with translations
+	tags     []language.Tag
+	matcher  language.Matcher
+	messages map[language.Tag]map[string]string
+}
+
+// NewCatalog creates a catalog of the built-in translations and of the *.json files in dir,
+// if set. Each file is named after its language, e.g. zh-CN.json, and maps English messages
+// to their translation; files in dir take precedence over built-in translations. Requests
+// matching none of the languages get fallback, whose messages are not translated.
+func NewCatalog(fallback, dir string) (*Catalog, error) {
+	fallbackTag, err := language.Parse(fallback)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fallback language %q: %w", fallback, err)
+	}
+	c := &Catalog{
+		fallback: fallbackTag,
+		tags:     []language.Tag{fallbackTag},
+		messages: make(map[language.Tag]map[string]string),
+	}
+
+	files, err := builtin.ReadDir("messages")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		data, err := builtin.ReadFile("messages/" + f.Name())
+		if err != nil {
+			return nil, err
+		}
+		if err := c.add(f.Name(), data); err != nil {
+			return nil, err
+		}
+	}
+
+	if dir != "" {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read message catalog: %w", err)
+			}
+			if err := c.add(filepath.Base(path), data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	c.matcher = language.NewMatcher(c.tags)
+	return c, nil
+}
+
+// add merges the translations of a catalog file into c
+func (c *Catalog) add(name string, data []byte) error {
+	tag, err := language.Parse(strings.TrimSuffix(name, ".json"))
+	if err != nil {
+		return fmt.Errorf("message catalog %s is not named after a language: %w", name, err)
+	}
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("invalid message catalog %s: %w", name, err)
+	}
+
+	if c.messages[tag] == nil {
+		c.messages[tag] = make(map[string]string, len(messages))
+		if tag != c.fallback {
+			c.tags = append(c.tags, tag)
+		}
+	}
+	for message, translation := range messages {
+		c.messages[tag][message] = translation
+	}
+	return nil
+}
+
+// Match returns the language of the catalog best matching the preferences, in order of
+// priority. Each preference is a language tag or an Accept-Language header; empty or
+// malformed preferences are skipped.
+func (c *Catalog) Match(preferences ...string) language.Tag {
+	var desired []language.Tag
+	for _, preference := range preferences {
+		tags, _, err := language.ParseAcceptLanguage(preference)
+		if err != nil {
+			continue
+		}
+		desired = append(desired, tags...)
+	}
+	if len(desired) == 0 {
+		return c.fallback
+	}
+
+	_, index, confidence := c.matcher.Match(desired...)
+	if confidence == language.No {
+		return c.fallback
+	}
+	return c.tags[index]
+}
+
+// Translate returns the translation of message into tag, or message itself when it has none
+func (c *Catalog) Translate(tag language.Tag, message string) string {
+	if translation, ok := c.messages[tag][message]; ok {
+		return translation
+	}
+	return message
+}
@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestCatalog(t *testing.T) {
+	dir := t.TempDir()
+	custom := `{"user not found": "找不到用户", "group not found": "未找到团队"}`
+	if err := os.WriteFile(filepath.Join(dir, "zh-CN.json"), []byte(custom), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "fr.json"), []byte(`{"user not found": "utilisateur introuvable"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := NewCatalog("en", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches := []struct {
+		preferences []string
+		want        language.Tag
+	}{
+		{nil, language.English},
+		{[]string{"", "zh-CN,zh;q=0.9,en;q=0.8"}, language.MustParse("zh-CN")},
+		{[]string{"fr-FR", "zh-CN"}, language.French},
+		{[]string{"de"}, language.English},
+		{[]string{"not a tag"}, language.English},
+	}
+	for _, m := range matches {
+		if got := catalog.Match(m.preferences...); got != m.want {
+			t.Errorf("Match(%q) = %s, want %s", m.preferences, got, m.want)
+		}
+	}
+
+	translations := []struct {
+		tag     language.Tag
+		message string
+		want    string
+	}{
+		{language.MustParse("zh-CN"), "user not found", "找不到用户"},
+		{language.MustParse("zh-CN"), "account is deactivated", "账户已停用"},
+		{language.French, "account is deactivated", "account is deactivated"},
+		{language.English, "user not found", "user not found"},
+	}
+	for _, tr := range translations {
+		if got := catalog.Translate(tr.tag, tr.message); got != tr.want {
+			t.Errorf("Translate(%s, %q) = %q, want %q", tr.tag, tr.message, got, tr.want)
+		}
+	}
+}
+
+func TestCatalogRejectsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "messages.json"), []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCatalog("en", dir); err == nil {
+		t.Error("catalog file not named after a language was accepted")
+	}
+}
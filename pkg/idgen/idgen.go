@@ -0,0 +1,73 @@
+// Package idgen generates the IDs of new rows. The strategy is chosen once at startup with
+// Set; the ent schemas use New as the default of their id fields.
+package idgen
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+)
+
+// Supported strategies
+const (
+	// UUIDv4 generates random UUIDs, e.g. 0b5b7e8e-3f2a-4c56-9f0e-4b8f3c1d2a7e
+	UUIDv4 = "uuidv4"
+	// UUIDv7 generates UUIDs that start with a millisecond timestamp and sort by creation time
+	UUIDv7 = "uuidv7"
+	// ULID generates 26 character ULIDs, e.g. 01J9Z3Q4V5W6X7Y8Z9A0B1C2D3, that sort by
+	// creation time
+	ULID = "ulid"
+)
+
+var generators = map[string]func() string{
+	UUIDv4: func() string {
+		return uuid.New().String()
+	},
+	UUIDv7: func() string {
+		return uuid.Must(uuid.NewV7()).String()
+	},
+	// ulid.Make is safe for concurrent use and increases monotonically within a millisecond
+	ULID: func() string {
+		return ulid.Make().String()
+	},
+}
+
+type state struct {
+	strategy string
+	generate func() string
+}
+
+var current atomic.Pointer[state]
+
+func init() {
+	current.Store(&state{strategy: UUIDv4, generate: generators[UUIDv4]})
+}
+
+// Valid reports whether strategy is supported
+func Valid(strategy string) bool {
+	_, ok := generators[strategy]
+	return ok
+}
+
+// Set selects the strategy of the IDs generated from now on. IDs generated before keep
+// their format, so a database may hold IDs of several strategies.
+func Set(strategy string) error {
+	generate, ok := generators[strategy]
+	if !ok {
+		return fmt.Errorf("unknown ID strategy %q, must be uuidv4, uuidv7 or ulid", strategy)
+	}
+	current.Store(&state{strategy: strategy, generate: generate})
+	return nil
+}
+
+// Strategy returns the selected strategy
+func Strategy() string {
+	return current.Load().strategy
+}
+
+// New returns a new ID of the selected strategy
+func New() string {
+	return current.Load().generate()
+}
@@ -0,0 +1,74 @@
+package idgen
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+)
+
+func TestNew(t *testing.T) {
+	defer Set(UUIDv4)
+
+	tests := []struct {
+		strategy string
+		check    func(id string) bool
+		sorted   bool
+	}{
+		{
+			strategy: UUIDv4,
+			check: func(id string) bool {
+				u, err := uuid.Parse(id)
+				return err == nil && u.Version() == 4
+			},
+		},
+		{
+			strategy: UUIDv7,
+			check: func(id string) bool {
+				u, err := uuid.Parse(id)
+				return err == nil && u.Version() == 7
+			},
+			sorted: true,
+		},
+		{
+			strategy: ULID,
+			check: func(id string) bool {
+				_, err := ulid.ParseStrict(id)
+				return err == nil && len(id) == 26
+			},
+			sorted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			if err := Set(tt.strategy); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if Strategy() != tt.strategy {
+				t.Errorf("Strategy() = %q, want %q", Strategy(), tt.strategy)
+			}
+
+			previous := ""
+			for i := 0; i < 100; i++ {
+				id := New()
+				if !tt.check(id) {
+					t.Fatalf("New() = %q, not a valid %s", id, tt.strategy)
+				}
+				// Sortable IDs must sort in the order they were generated, also within a millisecond
+				if tt.sorted && id <= previous {
+					t.Fatalf("New() = %q after %q, want increasing IDs", id, previous)
+				}
+				previous = id
+			}
+		})
+	}
+}
+
+func TestSetUnknown(t *testing.T) {
+	if err := Set("snowflake"); err == nil {
+		t.Error("Set() of an unknown strategy succeeded, want an error")
+	}
+	if Strategy() != UUIDv4 || !Valid(UUIDv4) || Valid("snowflake") {
+		t.Errorf("unknown strategy changed the strategy to %q", Strategy())
+	}
+}
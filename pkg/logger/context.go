@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// RequestIDKey is the gin context key holding the ID of the current request
+const RequestIDKey = "requestID"
+
+type requestIDContextKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying a request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if there is none.
+// It accepts both request contexts and *gin.Context, which keeps the ID under RequestIDKey.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if requestID, ok := ctx.Value(requestIDContextKey{}).(string); ok {
+		return requestID
+	}
+	// gin.Context 默认不回退到 Request.Context()，从其 Keys 中读取
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
+
+// FromContext returns the default logger, annotated with the request ID of ctx if it has one
+func FromContext(ctx context.Context) Logger {
+	zl, ok := std.(*ZapLogger)
+	if !ok {
+		return std
+	}
+	// 直接调用方法比经由包级函数少一层调用栈
+	l := zl.logger.WithOptions(zap.AddCallerSkip(-1))
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		l = l.With(zap.String("request_id", requestID))
+	}
+	return &ZapLogger{
+		logger: l,
+		sugar:  l.Sugar(),
+		level:  zl.level,
+	}
+}
@@ -19,15 +19,19 @@ func GinLoggerMiddleware() gin.HandlerFunc {
 		method := c.Request.Method
 		// 客户端IP
 		clientIP := c.ClientIP()
-		// 请求ID（如果有）
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
-			requestID = c.GetHeader("Request-ID")
-		}
 
 		// 处理请求
 		c.Next()
 
+		// 请求ID：优先使用请求ID中间件生成或校验过的值，未注册该中间件时读取请求头
+		requestID := c.GetString(RequestIDKey)
+		if requestID == "" {
+			requestID = c.GetHeader("X-Request-ID")
+		}
+		if requestID == "" {
+			requestID = c.GetHeader("Request-ID")
+		}
+
 		// 结束时间
 		end := time.Now()
 		// 延迟时间
@@ -92,6 +96,8 @@ func WithZapLogger(r *gin.Engine) {
 func GetGinEngine() *gin.Engine {
 	// 创建一个默认的gin引擎但不包括默认的Logger和Recovery中间件
 	r := gin.New()
+	// 处理器把*gin.Context当作context.Context传给服务层，回退到请求的Context以携带截止时间和取消信号
+	r.ContextWithFallback = true
 
 	// 使用我们自己的Logger
 	r.Use(GinLoggerMiddleware())
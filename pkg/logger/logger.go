@@ -20,6 +20,11 @@ type Logger interface {
 	Warnf(format string, v ...interface{})
 	Errorf(format string, v ...interface{})
 	Fatalf(format string, v ...interface{})
+	// 结构化日志，键值对交替传入
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
 	// 增加sync方法用于刷新缓冲日志
 	Sync() error
 }
@@ -155,6 +160,26 @@ func (l *ZapLogger) Fatalf(format string, v ...interface{}) {
 	l.sugar.Fatalf(format, v...)
 }
 
+// Debugw logs a debug message with alternating keys and values as structured fields
+func (l *ZapLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.sugar.Debugw(msg, keysAndValues...)
+}
+
+// Infow logs an info message with structured fields
+func (l *ZapLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.sugar.Infow(msg, keysAndValues...)
+}
+
+// Warnw logs a warning message with structured fields
+func (l *ZapLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.sugar.Warnw(msg, keysAndValues...)
+}
+
+// Errorw logs an error message with structured fields
+func (l *ZapLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.sugar.Errorw(msg, keysAndValues...)
+}
+
 // Sync flushes any buffered log entries
 func (l *ZapLogger) Sync() error {
 	return l.logger.Sync()
@@ -263,6 +288,26 @@ func Fatalf(format string, v ...interface{}) {
 	std.Fatalf(format, v...)
 }
 
+// Debugw logs a debug message with structured fields using the default logger
+func Debugw(msg string, keysAndValues ...interface{}) {
+	std.Debugw(msg, keysAndValues...)
+}
+
+// Infow logs an info message with structured fields using the default logger
+func Infow(msg string, keysAndValues ...interface{}) {
+	std.Infow(msg, keysAndValues...)
+}
+
+// Warnw logs a warning message with structured fields using the default logger
+func Warnw(msg string, keysAndValues ...interface{}) {
+	std.Warnw(msg, keysAndValues...)
+}
+
+// Errorw logs an error message with structured fields using the default logger
+func Errorw(msg string, keysAndValues ...interface{}) {
+	std.Errorw(msg, keysAndValues...)
+}
+
 // Sync flushes any buffered log entries
 func Sync() error {
 	return std.Sync()
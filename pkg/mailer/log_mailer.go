@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// LogMailer writes emails to the log instead of sending them, for development and tests
+type LogMailer struct{}
+
+// NewLogMailer creates a new log mailer
+func NewLogMailer() Mailer {
+	return &LogMailer{}
+}
+
+// Send logs the message
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	logger.Infof("【邮件】To: %s, Subject: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
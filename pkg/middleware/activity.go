@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+)
+
+// ActivityEntry describes a significant action a user completed successfully
+type ActivityEntry struct {
+	UserID         string
+	Action         string
+	ImpersonatedBy string
+	IP             string
+	UserAgent      string
+	// Tenant is the tenant TenantMiddleware selected, empty for the shared schema
+	Tenant string
+}
+
+// ActivityRecorder adds an entry to the timeline of a user. It runs on the request path, so
+// it must not block, e.g. by queueing the entry for a background writer.
+type ActivityRecorder func(entry ActivityEntry)
+
+// ActivityMiddleware records the successful requests to the routes in routeActions, keyed by
+// "METHOD /full/path", as activities of the user who made them. Logins, which carry no token,
+// are attributed to the user the handler stored under AuditActorKey.
+func ActivityMiddleware(record ActivityRecorder, routeActions map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		action, ok := routeActions[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest || c.GetBool("guest") {
+			return
+		}
+		userID := c.GetString(AuditActorKey)
+		if userID == "" {
+			userID = c.GetString("userID")
+		}
+		if userID == "" {
+			return
+		}
+		record(ActivityEntry{
+			UserID:         userID,
+			Action:         action,
+			ImpersonatedBy: c.GetString("impersonatedBy"),
+			IP:             c.ClientIP(),
+			UserAgent:      c.Request.UserAgent(),
+			Tenant:         dbtenant.From(c),
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestActivityMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		path   string
+		keys   map[string]any
+		status int
+		want   *ActivityEntry
+	}{
+		{
+			name:   "own action",
+			path:   "/profile",
+			keys:   map[string]any{"userID": "u1"},
+			status: http.StatusOK,
+			want:   &ActivityEntry{UserID: "u1", Action: "profile.update"},
+		},
+		{
+			name:   "login attributed by the handler",
+			path:   "/login",
+			keys:   map[string]any{AuditActorKey: "u2"},
+			status: http.StatusOK,
+			want:   &ActivityEntry{UserID: "u2", Action: "login"},
+		},
+		{
+			name:   "impersonated action",
+			path:   "/profile",
+			keys:   map[string]any{"userID": "u1", "impersonatedBy": "admin"},
+			status: http.StatusOK,
+			want:   &ActivityEntry{UserID: "u1", Action: "profile.update", ImpersonatedBy: "admin"},
+		},
+		{
+			name:   "failed request",
+			path:   "/profile",
+			keys:   map[string]any{"userID": "u1"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "route not tracked",
+			path:   "/other",
+			keys:   map[string]any{"userID": "u1"},
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded []ActivityEntry
+			router := gin.New()
+			router.Use(ActivityMiddleware(func(entry ActivityEntry) {
+				recorded = append(recorded, entry)
+			}, map[string]string{
+				"POST /profile": "profile.update",
+				"POST /login":   "login",
+			}))
+			handler := func(c *gin.Context) {
+				for key, value := range tt.keys {
+					c.Set(key, value)
+				}
+				c.Status(tt.status)
+			}
+			router.POST("/profile", handler)
+			router.POST("/login", handler)
+			router.POST("/other", handler)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, tt.path, nil))
+
+			if tt.want == nil {
+				if len(recorded) > 0 {
+					t.Errorf("recorded %+v, want nothing", recorded)
+				}
+				return
+			}
+			if len(recorded) != 1 {
+				t.Fatalf("recorded %+v, want one entry", recorded)
+			}
+			got := recorded[0]
+			if got.UserID != tt.want.UserID || got.Action != tt.want.Action || got.ImpersonatedBy != tt.want.ImpersonatedBy {
+				t.Errorf("recorded %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Context keys handlers can set to complete the audit entry of their request
+const (
+	// AuditActorKey overrides the actor, e.g. the user that just logged in
+	AuditActorKey = "auditActorID"
+	// AuditSubjectKey overrides the subject, e.g. the account a login was attempted for
+	AuditSubjectKey = "auditSubject"
+	// AuditDetailsKey describes what changed, e.g. the roles granted to a user
+	AuditDetailsKey = "auditDetails"
+)
+
+// AuditEntry describes an audited request after its handler has run
+type AuditEntry struct {
+	Action         string
+	ActorID        string
+	ImpersonatedBy string
+	Subject        string
+	Details        string
+	Success        bool
+	Status         int
+	IP             string
+	UserAgent      string
+}
+
+// AuditRecorder stores an audit entry
+type AuditRecorder func(ctx context.Context, entry AuditEntry)
+
+// AuditMiddleware records the requests to the routes in routeActions, keyed by
+// "METHOD /full/path", under the mapped action once the handler chain has finished.
+// Requests answered with a status below 400 count as successful.
+func AuditMiddleware(record AuditRecorder, routeActions map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		action, ok := routeActions[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		record(c.Request.Context(), AuditEntry{
+			Action:         action,
+			ActorID:        auditActor(c),
+			ImpersonatedBy: c.GetString("impersonatedBy"),
+			Subject:        auditSubject(c),
+			Details:        c.GetString(AuditDetailsKey),
+			Success:        c.Writer.Status() < http.StatusBadRequest,
+			Status:         c.Writer.Status(),
+			IP:             c.ClientIP(),
+			UserAgent:      c.Request.UserAgent(),
+		})
+	}
+}
+
+// auditActor returns who performed the request: the handler's override, the
+// authenticated user or the service account
+func auditActor(c *gin.Context) string {
+	if actor := c.GetString(AuditActorKey); actor != "" {
+		return actor
+	}
+	if userID := c.GetString("userID"); userID != "" {
+		return userID
+	}
+	if accountID := c.GetString("serviceAccountID"); accountID != "" {
+		return "service:" + accountID
+	}
+	return ""
+}
+
+// auditSubject returns what the request acted on: the handler's override or the resource in the path
+func auditSubject(c *gin.Context) string {
+	if subject := c.GetString(AuditSubjectKey); subject != "" {
+		return subject
+	}
+	for _, param := range []string{"id", "name", "key"} {
+		if value := c.Param(param); value != "" {
+			return value
+		}
+	}
+	return ""
+}
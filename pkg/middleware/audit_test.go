@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		path   string
+		keys   map[string]any
+		status int
+		want   *AuditEntry
+	}{
+		{
+			name:   "own action",
+			path:   "/users/u2",
+			keys:   map[string]any{"userID": "u1"},
+			status: http.StatusOK,
+			want:   &AuditEntry{Action: "admin.user.delete", ActorID: "u1", Subject: "u2", Success: true, Status: http.StatusOK},
+		},
+		{
+			name:   "impersonated action",
+			path:   "/users/u2",
+			keys:   map[string]any{"userID": "u1", "impersonatedBy": "admin"},
+			status: http.StatusOK,
+			want:   &AuditEntry{Action: "admin.user.delete", ActorID: "u1", ImpersonatedBy: "admin", Subject: "u2", Success: true, Status: http.StatusOK},
+		},
+		{
+			name:   "service account",
+			path:   "/users/u2",
+			keys:   map[string]any{"serviceAccountID": "sa1"},
+			status: http.StatusForbidden,
+			want:   &AuditEntry{Action: "admin.user.delete", ActorID: "service:sa1", Subject: "u2", Status: http.StatusForbidden},
+		},
+		{
+			name:   "login attributed by the handler",
+			path:   "/login",
+			keys:   map[string]any{AuditActorKey: "u3", AuditSubjectKey: "u3@example.com"},
+			status: http.StatusOK,
+			want:   &AuditEntry{Action: "auth.login", ActorID: "u3", Subject: "u3@example.com", Success: true, Status: http.StatusOK},
+		},
+		{
+			name:   "route not audited",
+			path:   "/other",
+			keys:   map[string]any{"userID": "u1"},
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded []AuditEntry
+			router := gin.New()
+			router.Use(AuditMiddleware(func(ctx context.Context, entry AuditEntry) {
+				recorded = append(recorded, entry)
+			}, map[string]string{
+				"POST /users/:id": "admin.user.delete",
+				"POST /login":     "auth.login",
+			}))
+			handler := func(c *gin.Context) {
+				for key, value := range tt.keys {
+					c.Set(key, value)
+				}
+				c.Status(tt.status)
+			}
+			router.POST("/users/:id", handler)
+			router.POST("/login", handler)
+			router.POST("/other", handler)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, tt.path, nil))
+
+			if tt.want == nil {
+				if len(recorded) > 0 {
+					t.Errorf("recorded %+v, want nothing", recorded)
+				}
+				return
+			}
+			if len(recorded) != 1 {
+				t.Fatalf("recorded %+v, want one entry", recorded)
+			}
+			got := recorded[0]
+			got.IP, got.UserAgent = "", ""
+			if got != *tt.want {
+				t.Errorf("recorded %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
@@ -1,47 +1,110 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
 )
 
-// AuthMiddleware is middleware that validates JWT tokens
-func AuthMiddleware(tokenService jwt.TokenService) gin.HandlerFunc {
+// AuthMiddleware is middleware that validates JWT tokens. Guest tokens are only accepted
+// on guestRoutes, keyed by "METHOD /full/path"; a nil map rejects them everywhere.
+// With cookies set, requests without an Authorization header may send the access token cookie.
+// With binding set, tokens bound to another client are rejected, as are tokens issued for
+// another tenant than the one TenantMiddleware selected.
+// Tokens of an organization user make the request act in that organization; with orgs set,
+// the organization must also be active.
+// Tokens of users who must change their password are only accepted on passwordChangeRoutes,
+// keyed like guestRoutes.
+func AuthMiddleware(tokenService jwt.TokenService, guestRoutes map[string]bool, cookies *TokenCookies, binding *TokenBinding, orgs *OrgResolver, passwordChangeRoutes map[string]bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
-			c.Abort()
+		// Requests already authenticated by ServiceTokenMiddleware carry no JWT
+		if c.GetBool("serviceAuthenticated") {
+			c.Next()
 			return
 		}
 
-		// Check if the header starts with "Bearer "
-		parts := strings.SplitN(authHeader, " ", 2)
-		if !(len(parts) == 2 && parts[0] == "Bearer") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+		authHeader := c.GetHeader("Authorization")
+		var tokenString string
+		if authHeader == "" && cookies != nil {
+			tokenString = cookies.AccessToken(c)
+		}
+		if authHeader == "" && tokenString == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
 			c.Abort()
 			return
 		}
 
-		// Extract the token
-		tokenString := parts[1]
+		if tokenString == "" {
+			// Check if the header starts with "Bearer "
+			parts := strings.SplitN(authHeader, " ", 2)
+			if !(len(parts) == 2 && parts[0] == "Bearer") {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+				c.Abort()
+				return
+			}
+
+			// Extract the token
+			tokenString = parts[1]
+		}
 
 		// Validate the token
-		claims, err := tokenService.ValidateToken(tokenString, jwt.AccessToken)
+		claims, err := tokenService.ValidateToken(c.Request.Context(), tokenString, jwt.AccessToken)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
 			c.Abort()
 			return
 		}
 
+		if binding != nil && !binding.allows(c, claims) || !tokenTenantAllowed(c, claims.TenantID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
+			c.Abort()
+			return
+		}
+
+		if claims.Guest && !guestRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "route not available to guests"})
+			c.Abort()
+			return
+		}
+
+		if claims.PasswordChangeRequired && !passwordChangeRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "password change required", "code": "password_change_required"})
+			c.Abort()
+			return
+		}
+
+		if !orgs.applyToken(c, claims) {
+			return
+		}
+
 		// Store the claims in the context for later use
 		c.Set("userID", claims.UserID)
 		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
+		c.Set("roles", claims.Roles)
 		c.Set("tokenID", claims.TokenID)
+		c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+		c.Set("scopes", claims.Scopes)
+		c.Set(ClaimsKey, claims)
+		if claims.Guest {
+			c.Set("guest", true)
+		}
+		if claims.TenantID != "" {
+			c.Set("tenantID", claims.TenantID)
+		}
+		if claims.ImpersonatedBy != "" {
+			c.Set("impersonatedBy", claims.ImpersonatedBy)
+			// Every request made while impersonating is logged with the admin behind it
+			logger.FromContext(c).Infow("impersonated request",
+				"impersonated_by", claims.ImpersonatedBy,
+				"user_id", claims.UserID,
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+			)
+		}
 
 		c.Next()
 	}
@@ -67,8 +130,8 @@ func OptionalAuthMiddleware(tokenService jwt.TokenService) gin.HandlerFunc {
 		tokenString := parts[1]
 
 		// Validate the token
-		claims, err := tokenService.ValidateToken(tokenString, jwt.AccessToken)
-		if err != nil {
+		claims, err := tokenService.ValidateToken(c.Request.Context(), tokenString, jwt.AccessToken)
+		if err != nil || !tokenTenantAllowed(c, claims.TenantID) {
 			c.Next()
 			return
 		}
@@ -76,25 +139,83 @@ func OptionalAuthMiddleware(tokenService jwt.TokenService) gin.HandlerFunc {
 		// Store the claims in the context for later use
 		c.Set("userID", claims.UserID)
 		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
+		c.Set("roles", claims.Roles)
 		c.Set("tokenID", claims.TokenID)
+		c.Set("scopes", claims.Scopes)
+		c.Set(ClaimsKey, claims)
+		if claims.Guest {
+			c.Set("guest", true)
+		}
+		if claims.TenantID != "" {
+			c.Set("tenantID", claims.TenantID)
+		}
+		if claims.OrgID != "" {
+			setOrg(c, claims.OrgID)
+		}
+		if claims.ImpersonatedBy != "" {
+			c.Set("impersonatedBy", claims.ImpersonatedBy)
+		}
 		c.Set("authenticated", true)
 
 		c.Next()
 	}
 }
 
-// RoleMiddleware is middleware that checks if a user has the required role
-func RoleMiddleware(requiredRole string) gin.HandlerFunc {
+// ClaimsKey is the gin context key holding the *jwt.Claims of an authenticated request
+const ClaimsKey = "claims"
+
+// ClaimsFromContext returns the claims of the access token the request was authenticated
+// with, or false for unauthenticated requests and service tokens
+func ClaimsFromContext(c *gin.Context) (*jwt.Claims, bool) {
+	value, exists := c.Get(ClaimsKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*jwt.Claims)
+	return claims, ok
+}
+
+// ExtraClaim returns an application-specific claim added by a jwt.ClaimsEnricher
+func ExtraClaim(c *gin.Context, name string) (interface{}, bool) {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return nil, false
+	}
+	value, ok := claims.Extra[name]
+	return value, ok
+}
+
+// RoleMatch selects how RoleMiddleware matches the user's roles against the required ones
+type RoleMatch int
+
+const (
+	// AnyRole requires the user to hold at least one of the roles
+	AnyRole RoleMatch = iota
+	// AllRoles requires the user to hold every one of the roles
+	AllRoles
+)
+
+// RoleMiddleware is middleware that checks the user's roles against the required roles
+func RoleMiddleware(match RoleMatch, requiredRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists {
+		// Service accounts are authorized by route scope in ServiceTokenMiddleware
+		if c.GetBool("serviceAuthenticated") {
+			c.Next()
+			return
+		}
+
+		if _, exists := c.Get("roles"); !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 			c.Abort()
 			return
 		}
 
-		if role != requiredRole {
+		roles := c.GetStringSlice("roles")
+		allowed := jwt.HasAnyRole(roles, requiredRoles...)
+		if match == AllRoles {
+			allowed = jwt.HasAllRoles(roles, requiredRoles...)
+		}
+		if !allowed {
 			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
 			c.Abort()
 			return
@@ -103,3 +224,67 @@ func RoleMiddleware(requiredRole string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// PermissionChecker reports whether a role grants a permission
+type PermissionChecker func(ctx context.Context, role, permission string) (bool, error)
+
+// PermissionMiddleware is middleware that checks that one of the user's roles grants the permission
+func PermissionMiddleware(checker PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Service accounts are authorized by route scope in ServiceTokenMiddleware
+		if c.GetBool("serviceAuthenticated") {
+			c.Next()
+			return
+		}
+
+		if _, exists := c.Get("roles"); !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			c.Abort()
+			return
+		}
+
+		allowed := false
+		for _, role := range c.GetStringSlice("roles") {
+			ok, err := checker(c.Request.Context(), role, permission)
+			if err != nil {
+				logger.FromContext(c).Errorf("Failed to check permission %s for role %s: %v", permission, role, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check permissions"})
+				c.Abort()
+				return
+			}
+			if ok {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScopes is middleware that checks that the token grants all of the given scopes.
+// It works for both user tokens and service tokens, which set "scopes" in the context.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted, exists := c.Get("scopes")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			c.Abort()
+			return
+		}
+
+		grantedScopes, _ := granted.([]string)
+		if !jwt.HasScopes(grantedScopes, scopes...) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient scope"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
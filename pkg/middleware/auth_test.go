@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+func TestAuthMiddlewarePasswordChange(t *testing.T) {
+	tokenService := jwt.NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, jwt.TokenEncryption{}, jwt.NewMemoryTokenStore(), nil)
+	issue := func(required bool) string {
+		pair, err := tokenService.GenerateTokenPairWithOptions(context.Background(), "u1", "a@example.com", nil, jwt.TokenOptions{PasswordChangeRequired: required})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pair.AccessToken
+	}
+	flagged, normal := issue(true), issue(false)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	auth := AuthMiddleware(tokenService, nil, nil, nil, nil, map[string]bool{"POST /change-password": true})
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.GET("/me", auth, ok)
+	router.POST("/change-password", auth, ok)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"flagged token on other routes", http.MethodGet, "/me", flagged, http.StatusForbidden},
+		{"flagged token changes password", http.MethodPost, "/change-password", flagged, http.StatusNoContent},
+		{"normal token", http.MethodGet, "/me", normal, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+
+	// 刷新令牌时保留该标记，否则刷新即可绕过限制
+	pair, err := tokenService.GenerateTokenPairWithOptions(context.Background(), "u2", "b@example.com", nil, jwt.TokenOptions{PasswordChangeRequired: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed, err := tokenService.RefreshTokens(context.Background(), pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := tokenService.ValidateToken(context.Background(), refreshed.AccessToken, jwt.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.PasswordChangeRequired {
+		t.Error("refreshing dropped the password change requirement")
+	}
+}
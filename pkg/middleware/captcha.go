@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/captcha"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// CaptchaMiddleware requires a valid captcha response in headerName before the request is handled.
+// Unlike the rate limiter it fails closed: if the provider cannot be reached the request is
+// rejected, since the endpoints it guards are exactly the ones bots target.
+func CaptchaMiddleware(verifier captcha.Verifier, headerName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := verifier.Verify(c.Request.Context(), c.GetHeader(headerName), c.ClientIP())
+		switch {
+		case err == nil:
+			c.Next()
+		case errors.Is(err, captcha.ErrMissingToken), errors.Is(err, captcha.ErrVerificationFailed):
+			logger.FromContext(c).Warnf("Captcha rejected for %s %s from %s: %v", c.Request.Method, c.Request.URL.Path, c.ClientIP(), err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			logger.FromContext(c).Errorf("Captcha verification unavailable: %v", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "captcha verification unavailable"})
+		}
+	}
+}
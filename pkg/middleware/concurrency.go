@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"expvar"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// concurrencyMetrics are published as the expvar map "http.concurrency"
+var concurrencyMetrics = struct {
+	inFlight *expvar.Int
+	limit    *expvar.Int
+	rejected *expvar.Int
+}{
+	inFlight: new(expvar.Int),
+	limit:    new(expvar.Int),
+	rejected: new(expvar.Int),
+}
+
+func init() {
+	vars := new(expvar.Map).Init()
+	vars.Set("in_flight", concurrencyMetrics.inFlight)
+	vars.Set("limit", concurrencyMetrics.limit)
+	vars.Set("rejected", concurrencyMetrics.rejected)
+	expvar.Publish("http.concurrency", vars)
+}
+
+// ConcurrencyLimitMiddleware caps the number of requests handled at the same time.
+// Requests arriving while all slots are taken are rejected with 503 instead of queueing,
+// so an overloaded instance sheds load rather than piling up goroutines and memory.
+func ConcurrencyLimitMiddleware(maxConcurrent int) gin.HandlerFunc {
+	slots := make(chan struct{}, maxConcurrent)
+	concurrencyMetrics.limit.Set(int64(maxConcurrent))
+
+	return func(c *gin.Context) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			concurrencyMetrics.rejected.Add(1)
+			logger.FromContext(c).Warnf("Concurrency limit of %d reached, rejecting %s %s", maxConcurrent, c.Request.Method, c.Request.URL.Path)
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "server is busy, please retry later"})
+			return
+		}
+
+		concurrencyMetrics.inFlight.Add(1)
+		defer func() {
+			concurrencyMetrics.inFlight.Add(-1)
+			<-slots
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSOptions configures CORSMiddleware
+type CORSOptions struct {
+	// AllowedOrigins are exact origins such as "https://app.example.com", "*" for any origin,
+	// or a single leading wildcard label such as "https://*.example.com"
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// ExposedHeaders are response headers browsers let scripts read
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response; zero leaves it to the browser
+	MaxAge time.Duration
+}
+
+// CORSMiddleware answers preflight requests and adds the CORS headers to responses for
+// allowed origins. Requests from other origins are served without CORS headers, so browsers
+// block scripts from reading the response. It must be registered on the engine so that
+// preflight requests to routes without an OPTIONS handler reach it.
+func CORSMiddleware(options CORSOptions) gin.HandlerFunc {
+	allowMethods := strings.Join(options.AllowedMethods, ", ")
+	allowHeaders := strings.Join(options.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(options.ExposedHeaders, ", ")
+	maxAge := ""
+	if options.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(options.MaxAge.Seconds()))
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		// 响应随 Origin 变化，避免缓存把一个来源的响应返回给另一个来源
+		c.Writer.Header().Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !originAllowed(options.AllowedOrigins, origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		// 允许携带凭证时不能返回 *，回显请求来源
+		if slices.Contains(options.AllowedOrigins, "*") && !options.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if options.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", allowMethods)
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+			if maxAge != "" {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if exposeHeaders != "" {
+			header.Set("Access-Control-Expose-Headers", exposeHeaders)
+		}
+		c.Next()
+	}
+}
+
+// originAllowed reports whether an origin matches one of the allowed origins
+func originAllowed(allowed []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+		// https://*.example.com 匹配任意子域名，但不匹配 example.com 本身
+		if scheme, domain, ok := strings.Cut(pattern, "://*."); ok {
+			prefix := scheme + "://"
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, "."+domain) &&
+				len(origin) > len(prefix)+len(domain)+1 {
+				return true
+			}
+		}
+	}
+	return false
+}
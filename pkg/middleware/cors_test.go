@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://*.example.org"}
+	cases := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.com", true},
+		{"https://APP.example.com", true},
+		{"http://app.example.com", false},
+		{"https://evil.com", false},
+		{"https://a.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"https://evilexample.org", false},
+		{"http://a.example.org", false},
+	}
+	for _, tc := range cases {
+		if got := originAllowed(allowed, tc.origin); got != tc.want {
+			t.Errorf("originAllowed(%s) = %v, want %v", tc.origin, got, tc.want)
+		}
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORSMiddleware(CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Authorization", "X-Sign"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+	router.POST("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	send := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/users", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Preflight requests reach the middleware although no OPTIONS route is registered
+	w := send(http.MethodOptions, "https://app.example.com")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("preflight: got status %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("preflight Access-Control-Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "Authorization, X-Sign" {
+		t.Errorf("preflight Access-Control-Allow-Headers = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("preflight Access-Control-Max-Age = %q", got)
+	}
+
+	if w := send(http.MethodOptions, "https://evil.com"); w.Code != http.StatusForbidden {
+		t.Errorf("preflight from another origin: got status %d", w.Code)
+	}
+
+	w = send(http.MethodPost, "https://app.example.com")
+	if w.Code != http.StatusCreated || w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("allowed request: got status %d and headers %v", w.Code, w.Header())
+	}
+
+	w = send(http.MethodPost, "https://evil.com")
+	if w.Code != http.StatusCreated || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("request from another origin: got status %d and headers %v", w.Code, w.Header())
+	}
+}
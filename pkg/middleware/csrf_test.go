@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	options := CSRFOptions{
+		CookieName: "csrf_token",
+		HeaderName: "X-CSRF-Token",
+		Tokens:     &TokenCookies{AccessName: "access_token", RefreshName: "refresh_token"},
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CSRFMiddleware(options))
+	router.GET("/csrf", func(c *gin.Context) {
+		token, err := IssueCSRFToken(c, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.String(http.StatusOK, token)
+	})
+	router.POST("/users", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/csrf", nil))
+	token := w.Body.String()
+	if token == "" || len(w.Result().Cookies()) != 1 {
+		t.Fatalf("token endpoint returned %q with cookies %v", token, w.Result().Cookies())
+	}
+
+	cases := []struct {
+		name          string
+		authCookie    bool
+		authorization bool
+		csrfCookie    string
+		csrfHeader    string
+		want          int
+	}{
+		{"no token cookie", false, false, "", "", http.StatusNoContent},
+		{"authorization header", true, true, "", "", http.StatusNoContent},
+		{"missing header", true, false, token, "", http.StatusForbidden},
+		{"mismatched header", true, false, token, "other", http.StatusForbidden},
+		{"missing cookie", true, false, "", token, http.StatusForbidden},
+		{"matching header", true, false, token, token, http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		if tc.authCookie {
+			req.AddCookie(&http.Cookie{Name: "access_token", Value: "jwt"})
+		}
+		if tc.authorization {
+			req.Header.Set("Authorization", "Bearer jwt")
+		}
+		if tc.csrfCookie != "" {
+			req.AddCookie(&http.Cookie{Name: "csrf_token", Value: tc.csrfCookie})
+		}
+		if tc.csrfHeader != "" {
+			req.Header.Set("X-CSRF-Token", tc.csrfHeader)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: got status %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+}
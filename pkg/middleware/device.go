@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// DeviceMiddleware records the IP address and User-Agent of the client making the request,
+// which services pass on when issuing tokens so the refresh token store can show where each
+// session signed in. The device is stored in the gin context under jwt.DeviceKey and in the
+// request context.
+func DeviceMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		device := jwt.Device{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+		c.Set(jwt.DeviceKey, device)
+		c.Request = c.Request.WithContext(jwt.ContextWithDevice(c.Request.Context(), device))
+		c.Next()
+	}
+}
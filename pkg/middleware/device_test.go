@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+func TestDeviceMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var fromGin, fromRequest jwt.Device
+	router := gin.New()
+	router.Use(DeviceMiddleware())
+	router.POST("/auth/login", func(c *gin.Context) {
+		fromGin = jwt.DeviceFromContext(c)
+		fromRequest = jwt.DeviceFromContext(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	req.RemoteAddr = "203.0.113.7:4321"
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64)")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := jwt.Device{IP: "203.0.113.7", UserAgent: "Mozilla/5.0 (X11; Linux x86_64)"}
+	if fromGin != want || fromRequest != want {
+		t.Errorf("device = %+v (gin) and %+v (request), want %+v", fromGin, fromRequest, want)
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGroupAccess(t *testing.T) {
+	members := map[string]string{
+		"g1/owner":  "owner",
+		"g1/admin":  "admin",
+		"g1/member": "member",
+	}
+	access := GroupAccess{
+		Lookup: func(ctx context.Context, groupID, userID string) (string, error) {
+			return members[groupID+"/"+userID], nil
+		},
+		Checker: func(ctx context.Context, role, permission string) (bool, error) {
+			return role == "admin" && permission == "group.manage", nil
+		},
+		OverridePermission: "group.manage",
+		OverrideRole:       "owner",
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-User"); userID != "" {
+			c.Set("userID", userID)
+			c.Set("roles", []string{c.GetHeader("X-Role")})
+		}
+	})
+	handler := func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("groupID")+"/"+c.GetString("groupRole"))
+	}
+	router.GET("/groups/:id", access.Require(), handler)
+	router.PUT("/groups/:id", access.Require("owner", "admin"), handler)
+
+	tests := []struct {
+		name     string
+		method   string
+		group    string
+		user     string
+		role     string
+		wantCode int
+		wantBody string
+	}{
+		{"member reads", http.MethodGet, "g1", "member", "user", http.StatusOK, "g1/member"},
+		{"admin updates", http.MethodPut, "g1", "admin", "user", http.StatusOK, "g1/admin"},
+		{"member cannot update", http.MethodPut, "g1", "member", "user", http.StatusForbidden, ""},
+		{"non-member gets 404", http.MethodGet, "g1", "stranger", "user", http.StatusNotFound, ""},
+		{"other group gets 404", http.MethodGet, "g2", "owner", "user", http.StatusNotFound, ""},
+		{"platform admin overrides", http.MethodPut, "g2", "stranger", "admin", http.StatusOK, "g2/owner"},
+		{"unauthenticated", http.MethodGet, "g1", "", "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/groups/"+tt.group, nil)
+		if tt.user != "" {
+			req.Header.Set("X-User", tt.user)
+			req.Header.Set("X-Role", tt.role)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, want %d", tt.name, w.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+			t.Errorf("%s: got body %q, want %q", tt.name, w.Body.String(), tt.wantBody)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/i18n"
+)
+
+// LocaleResolver returns the language saved by the user making the request, or an empty
+// string to follow the client. It is only called for error responses.
+type LocaleResolver func(c *gin.Context) string
+
+// LocaleMiddleware translates the "error" message of JSON error responses with catalog into
+// the language saved by the user, else the best match of the Accept-Language header, else
+// the fallback of the catalog. Translated responses carry the language in Content-Language.
+// Successful responses pass through untouched.
+func LocaleMiddleware(catalog *i18n.Catalog, userLocale LocaleResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &localeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if !w.buffered {
+			return
+		}
+		body := w.body.Bytes()
+		var payload map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&payload); err == nil {
+			if message, ok := payload["error"].(string); ok {
+				tag := catalog.Match(userLocale(c), c.GetHeader("Accept-Language"))
+				payload["error"] = catalog.Translate(tag, message)
+				if translated, err := json.Marshal(payload); err == nil {
+					body = translated
+				}
+				c.Header("Content-Language", tag.String())
+			}
+		}
+		// 响应体长度可能已变化
+		if c.Writer.Header().Get("Content-Length") != "" {
+			c.Header("Content-Length", strconv.Itoa(len(body)))
+		}
+		c.Writer.Write(body)
+	}
+}
+
+// localeWriter holds back the body of JSON error responses so it can be translated
+type localeWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	buffered bool
+}
+
+// Write buffers the body of JSON error responses and writes any other body through
+func (w *localeWriter) Write(data []byte) (int, error) {
+	if w.buffered || w.holdBack() {
+		w.buffered = true
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString buffers like Write
+func (w *localeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// holdBack reports whether the response about to be written is a JSON error
+func (w *localeWriter) holdBack() bool {
+	return !w.ResponseWriter.Written() &&
+		w.Status() >= http.StatusBadRequest &&
+		strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/i18n"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	catalog, err := i18n.NewCatalog("en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(LocaleMiddleware(catalog, func(c *gin.Context) string {
+		return c.GetString("savedLocale")
+	}))
+	router.GET("/missing", func(c *gin.Context) {
+		if c.Query("saved") != "" {
+			c.Set("savedLocale", c.Query("saved"))
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found", "code": 7})
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"error": "user not found"})
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptLanguage string
+		want           string
+		wantLanguage   string
+	}{
+		{"accept language", "/missing", "zh-CN,zh;q=0.9", "用户不存在", "zh-CN"},
+		{"saved locale wins", "/missing?saved=en", "zh-CN", "user not found", "en"},
+		{"fallback", "/missing", "", "user not found", "en"},
+		{"successful responses untouched", "/ok", "zh-CN", "user not found", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["error"] != tt.want {
+				t.Errorf("error = %v, want %q", body["error"], tt.want)
+			}
+			if got := w.Header().Get("Content-Language"); got != tt.wantLanguage {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantLanguage)
+			}
+			if tt.path == "/missing" && body["code"] != float64(7) {
+				t.Errorf("other fields changed: %v", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/orgctx"
+)
+
+func TestOrgResolver(t *testing.T) {
+	tokenService := jwt.NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, jwt.TokenEncryption{}, jwt.NewMemoryTokenStore(), nil)
+	active := map[string]bool{"acme": true, "globex": true}
+	resolver := &OrgResolver{
+		Header: "X-Org-ID",
+		Lookup: func(ctx context.Context, orgID string) (bool, error) {
+			return active[orgID], nil
+		},
+	}
+
+	issue := func(orgID string) string {
+		pair, err := tokenService.GenerateTokenPairWithOptions(context.Background(), "u1", "a@example.com", nil, jwt.TokenOptions{OrgID: orgID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pair.AccessToken
+	}
+	acmeToken, platformToken := issue("acme"), issue("")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(resolver.Middleware())
+	router.GET("/me", AuthMiddleware(tokenService, nil, nil, nil, resolver, nil), func(c *gin.Context) {
+		c.String(http.StatusOK, orgctx.From(c.Request.Context()))
+	})
+	router.GET("/admin", AuthMiddleware(tokenService, nil, nil, nil, resolver, nil), PlatformOnlyMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.POST("/register", func(c *gin.Context) {
+		c.String(http.StatusOK, orgctx.From(c))
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		header   string
+		wantCode int
+		wantBody string
+	}{
+		{"token organization", http.MethodGet, "/me", acmeToken, "", http.StatusOK, "acme"},
+		{"same organization in header", http.MethodGet, "/me", acmeToken, "acme", http.StatusOK, "acme"},
+		{"other organization in header", http.MethodGet, "/me", acmeToken, "globex", http.StatusForbidden, ""},
+		{"platform user selects organization", http.MethodGet, "/me", platformToken, "globex", http.StatusOK, "globex"},
+		{"platform user without organization", http.MethodGet, "/me", platformToken, "", http.StatusOK, ""},
+		{"unknown organization", http.MethodGet, "/me", platformToken, "initech", http.StatusForbidden, ""},
+		{"unauthenticated selects organization", http.MethodPost, "/register", "", "acme", http.StatusOK, "acme"},
+		{"organization user on platform route", http.MethodGet, "/admin", acmeToken, "", http.StatusForbidden, ""},
+		{"platform user on platform route", http.MethodGet, "/admin", platformToken, "", http.StatusNoContent, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		if tt.header != "" {
+			req.Header.Set("X-Org-ID", tt.header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, want %d", tt.name, w.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+			t.Errorf("%s: got body %q, want %q", tt.name, w.Body.String(), tt.wantBody)
+		}
+	}
+
+	// Deactivating the organization rejects the tokens of its users
+	active["acme"] = false
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+acmeToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("inactive organization: got status %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PresenceRecorder records that a user made an authenticated request at the given time; ctx
+// is the request's. It is called on every such request, so it must be cheap, e.g. by
+// throttling writes.
+type PresenceRecorder func(ctx context.Context, userID string, at time.Time)
+
+// PresenceMiddleware records the user behind every request authenticated with a user token
+// once the handler chain has finished. Guests and admins impersonating a user are not
+// recorded, so neither shows up as activity of a real user.
+func PresenceMiddleware(record PresenceRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		userID := c.GetString("userID")
+		if userID == "" || c.GetBool("guest") || c.GetString("impersonatedBy") != "" {
+			return
+		}
+		record(c, userID, time.Now())
+	}
+}
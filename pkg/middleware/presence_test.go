@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPresenceMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		keys map[string]any
+		want string
+	}{
+		{name: "user", keys: map[string]any{"userID": "u1"}, want: "u1"},
+		{name: "unauthenticated", keys: map[string]any{}},
+		{name: "guest", keys: map[string]any{"userID": "g1", "guest": true}},
+		{name: "impersonation", keys: map[string]any{"userID": "u1", "impersonatedBy": "admin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded []string
+			router := gin.New()
+			router.Use(PresenceMiddleware(func(ctx context.Context, userID string, at time.Time) {
+				recorded = append(recorded, userID)
+			}))
+			// 认证中间件在各路由上执行，晚于记录中间件
+			router.GET("/", func(c *gin.Context) {
+				for key, value := range tt.keys {
+					c.Set(key, value)
+				}
+				c.Status(http.StatusNoContent)
+			})
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			switch {
+			case tt.want == "" && len(recorded) > 0:
+				t.Errorf("recorded %v, want nothing", recorded)
+			case tt.want != "" && (len(recorded) != 1 || recorded[0] != tt.want):
+				t.Errorf("recorded %v, want [%s]", recorded, tt.want)
+			}
+		})
+	}
+}
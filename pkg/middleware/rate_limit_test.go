@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryCounter is a RateLimitCounter that never resets its windows
+type memoryCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (m *memoryCounter) count(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[key]++
+	return m.counts[key], window, nil
+}
+
+func newRateLimitRouter(counter *memoryCounter, perIP, perAccount RateLimitRule) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	_ = router.SetTrustedProxies(nil)
+	router.POST("/login", AuthRateLimitMiddleware(counter.count, "login", perIP, perAccount), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestAuthRateLimitIgnoresForwardedFor(t *testing.T) {
+	counter := &memoryCounter{counts: make(map[string]int64)}
+	router := newRateLimitRouter(counter, RateLimitRule{Limit: 2, Window: time.Minute}, RateLimitRule{})
+
+	codes := make([]int, 0, 3)
+	for _, forwardedFor := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{}`))
+		req.RemoteAddr = "203.0.113.5:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+
+	if codes[2] != http.StatusTooManyRequests {
+		t.Errorf("statuses = %v, want the third request limited", codes)
+	}
+	if counter.counts["login:ip:203.0.113.5"] != 3 {
+		t.Errorf("counts = %v, want every request counted against the connection's address", counter.counts)
+	}
+}
+
+func TestAuthRateLimitBodySize(t *testing.T) {
+	counter := &memoryCounter{counts: make(map[string]int64)}
+	router := newRateLimitRouter(counter, RateLimitRule{}, RateLimitRule{Limit: 5, Window: time.Minute})
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantKey  string
+	}{
+		{"email", `{"email":" Alice@Example.com ","password":"x"}`, http.StatusOK, "login:account:alice@example.com"},
+		{"too large", `{"email":"bob@example.com","password":"` + strings.Repeat("x", maxAccountBodyBytes) + `"}`, http.StatusRequestEntityTooLarge, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if tt.wantKey != "" && counter.counts[tt.wantKey] != 1 {
+				t.Errorf("counts = %v, want %s counted", counter.counts, tt.wantKey)
+			}
+		})
+	}
+	if counter.counts["login:account:bob@example.com"] != 0 {
+		t.Errorf("oversized request was counted: %v", counter.counts)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/dbreplica"
+)
+
+// ReadPrimaryMiddleware makes requests read from the primary database instead of a read
+// replica when they change data, so they read back their own writes, or when the client
+// sets header to true, e.g. right after a write made by another request. The override is
+// stored in the gin context under dbreplica.Key and in the request context.
+func ReadPrimaryMiddleware(header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		primary, _ := strconv.ParseBool(c.GetHeader(header))
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			primary = true
+		}
+
+		if primary {
+			c.Set(dbreplica.Key, true)
+			c.Request = c.Request.WithContext(dbreplica.Primary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
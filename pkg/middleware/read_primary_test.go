@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/dbreplica"
+)
+
+func TestReadPrimaryMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		want   bool
+	}{
+		{name: "read", method: http.MethodGet, want: false},
+		{name: "write", method: http.MethodPost, want: true},
+		{name: "delete", method: http.MethodDelete, want: true},
+		{name: "read with override", method: http.MethodGet, header: "true", want: true},
+		{name: "read with invalid override", method: http.MethodGet, header: "yes please", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromGin, fromRequest bool
+			router := gin.New()
+			router.Use(ReadPrimaryMiddleware("X-Read-Primary"))
+			router.Handle(tt.method, "/", func(c *gin.Context) {
+				fromGin = dbreplica.UsesPrimary(c)
+				fromRequest = dbreplica.UsesPrimary(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Read-Primary", tt.header)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if fromGin != tt.want || fromRequest != tt.want {
+				t.Errorf("reads from primary = %v (gin) and %v (request), want %v", fromGin, fromRequest, tt.want)
+			}
+		})
+	}
+}
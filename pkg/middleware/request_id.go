@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// maxRequestIDLength bounds the client-supplied request IDs that are accepted
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware gives every request an ID: a well-formed ID sent by the client in
+// header is kept so that calls can be traced across services, otherwise a new one is
+// generated. The ID is returned in the same response header, stored in the gin context
+// under logger.RequestIDKey and carried by the request context, so logger.FromContext
+// includes it in log entries.
+func RequestIDMiddleware(header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(header)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(logger.RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(logger.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Header(header, requestID)
+
+		c.Next()
+	}
+}
+
+// validRequestID reports whether a client-supplied request ID is safe to log and echo:
+// non-empty, bounded in length and limited to characters common in trace IDs
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':', r == '/', r == '+', r == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	logger.SetDefaultLogger(logger.NewDefaultLogger(zapcore.AddSync(&logs), logger.DebugLevel))
+	defer logger.SetDefaultLogger(logger.NewZapLogger(logger.InfoLevel, true))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestIDMiddleware("X-Request-ID"))
+	router.GET("/api/v1/users", func(c *gin.Context) {
+		// 服务层拿到的是请求上下文或 gin.Context，两者都应带有请求ID
+		if got := logger.RequestIDFromContext(c.Request.Context()); got != c.GetString(logger.RequestIDKey) {
+			t.Errorf("request context carries %q, gin context %q", got, c.GetString(logger.RequestIDKey))
+		}
+		logger.FromContext(c).Infof("listing users")
+		c.Status(http.StatusNoContent)
+	})
+
+	send := func(requestID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+		if requestID != "" {
+			req.Header.Set("X-Request-ID", requestID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Header().Get("X-Request-ID")
+	}
+
+	if got := send("trace-123"); got != "trace-123" {
+		t.Errorf("client request ID: got %q", got)
+	}
+	if !strings.Contains(logs.String(), "trace-123") {
+		t.Errorf("service log does not include the request ID: %s", logs.String())
+	}
+
+	generated := send("")
+	if generated == "" {
+		t.Fatal("no request ID generated")
+	}
+	if other := send(""); other == generated {
+		t.Errorf("generated request IDs repeat: %q", other)
+	}
+
+	for _, invalid := range []string{"bad id", "bad\nid", strings.Repeat("a", maxRequestIDLength+1)} {
+		if got := send(invalid); got == invalid || got == "" {
+			t.Errorf("invalid request ID %q: got %q, want a generated one", invalid, got)
+		}
+	}
+}
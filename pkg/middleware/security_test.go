@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSkipRuleMatches(t *testing.T) {
+	cases := []struct {
+		rule   SkipRule
+		method string
+		route  string
+		want   bool
+	}{
+		{SkipRule{Path: "/api/v1/health"}, "GET", "/api/v1/health", true},
+		{SkipRule{Path: "/api/v1/health"}, "GET", "/api/v1/healthz", false},
+		{SkipRule{Method: "POST", Path: "/api/v1/webhooks/*"}, "POST", "/api/v1/webhooks/:provider", true},
+		{SkipRule{Method: "post", Path: "/api/v1/webhooks/*"}, "POST", "/api/v1/webhooks/:provider", true},
+		{SkipRule{Method: "POST", Path: "/api/v1/webhooks/*"}, "GET", "/api/v1/webhooks/:provider", false},
+		{SkipRule{Path: "/api/v1/webhooks/*"}, "POST", "/api/v1/webhooks/:provider/events", false},
+		{SkipRule{Path: "/api/v1/docs/**"}, "GET", "/api/v1/docs/*filepath", true},
+		{SkipRule{Path: "/api/v1/docs/**"}, "GET", "/api/v1/docs", true},
+		{SkipRule{Path: "/api/v1/docs/**"}, "GET", "/api/v1/docsearch", false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.rule.matches(tc.method, tc.route); got != tc.want {
+			t.Errorf("%+v.matches(%s, %s) = %v, want %v", tc.rule, tc.method, tc.route, got, tc.want)
+		}
+	}
+}
+
+func TestSecurityMiddlewareNeverLogsSecret(t *testing.T) {
+	const secret = "super-secret-signing-key"
+	var logs bytes.Buffer
+	logger.SetDefaultLogger(logger.NewDefaultLogger(zapcore.AddSync(&logs), logger.DebugLevel))
+	defer logger.SetDefaultLogger(logger.NewZapLogger(logger.InfoLevel, true))
+
+	service := security.NewSecurityService(secret, "", time.Minute, nil, nil,
+		func(context.Context, string) (bool, error) { return true, nil },
+		nil,
+	)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SecurityMiddleware(service, SecurityOptions{TimestampWindow: time.Minute, VerboseSignatureLogs: true}))
+	router.POST("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	body := `{"email":"a@example.com"}`
+	send := func(signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Timestamp", timestamp)
+		req.Header.Set("X-Nonce", "n")
+		req.Header.Set("X-Sign", signature)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	valid := security.GenerateSignature(map[string]string{"email": "a@example.com", "timestamp": timestamp, "nonce": "n"}, secret)
+	if code := send(valid); code != http.StatusNoContent {
+		t.Errorf("valid signature: got status %d", code)
+	}
+	if code := send("bad"); code != http.StatusBadRequest {
+		t.Errorf("invalid signature: got status %d", code)
+	}
+
+	if !strings.Contains(logs.String(), "signature mismatch") {
+		t.Fatalf("verbose diagnostics were not logged:\n%s", logs.String())
+	}
+	if strings.Contains(logs.String(), secret) {
+		t.Errorf("logs contain the signature secret:\n%s", logs.String())
+	}
+	// The rejected request differs from the valid one only by its signature, so logging the
+	// expected signature would let log readers replay it
+	if strings.Contains(logs.String(), valid) {
+		t.Errorf("logs contain the expected signature:\n%s", logs.String())
+	}
+}
+
+func TestSecurityMiddlewareCustomNames(t *testing.T) {
+	const secret = "secret"
+	service := security.NewSecurityService(secret, "", time.Minute, nil, nil,
+		func(context.Context, string) (bool, error) { return true, nil },
+		nil,
+	)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SecurityMiddleware(service, SecurityOptions{
+		TimestampWindow: time.Minute,
+		Names: SecurityNames{
+			TimestampHeader: "Gw-Ts",
+			NonceHeader:     "Gw-Nonce",
+			SignParam:       "signature",
+		},
+	}))
+	router.GET("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	signature := security.GenerateSignature(map[string]string{"page": "1", "timestamp": timestamp, "nonce": "n"}, secret)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users?page=1&signature="+signature, nil)
+	req.Header.Set("Gw-Ts", timestamp)
+	req.Header.Set("Gw-Nonce", "n")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("request with custom names: got status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSecurityMiddlewareModes(t *testing.T) {
+	service := security.NewSecurityService("secret", "", time.Minute, nil, nil,
+		func(context.Context, string) (bool, error) { return true, nil },
+		nil,
+	)
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		mode SecurityMode
+		want int
+	}{
+		{"", http.StatusBadRequest},
+		{SecurityModeEnforce, http.StatusBadRequest},
+		{SecurityModeLogOnly, http.StatusNoContent},
+		{SecurityModeOff, http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		router := gin.New()
+		router.Use(SecurityMiddleware(service, SecurityOptions{Mode: tc.mode, TimestampWindow: time.Minute}))
+		router.GET("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+		req.Header.Set("X-Timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+		req.Header.Set("X-Nonce", "n")
+		req.Header.Set("X-Sign", "bad")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("mode %q: got status %d, want %d", tc.mode, w.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// ServiceTokenIdentity describes the service account behind an authenticated service token
+type ServiceTokenIdentity struct {
+	ID     string
+	Name   string
+	Scopes []string
+}
+
+// ServiceTokenAuthenticator resolves a service token presented by the client at clientIP
+type ServiceTokenAuthenticator func(ctx context.Context, token, clientIP string) (*ServiceTokenIdentity, error)
+
+// ServiceTokenMiddleware authenticates long-lived service tokens sent in headerName.
+// Service tokens bypass request signing, so access is deny-by-default: only routes
+// listed in routeScopes ("METHOD /full/path" -> scope) are reachable, and the token
+// must carry the scope required by the route.
+func ServiceTokenMiddleware(headerName string, authenticate ServiceTokenAuthenticator, routeScopes map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(headerName)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		identity, err := authenticate(c.Request.Context(), token, c.ClientIP())
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		requiredScope, ok := routeScopes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "route is not available to service accounts"})
+			c.Abort()
+			return
+		}
+		if !jwt.HasScopes(identity.Scopes, requiredScope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient scope"})
+			c.Abort()
+			return
+		}
+
+		// Store the service identity in the context for later use
+		c.Set("serviceAuthenticated", true)
+		c.Set("serviceAccountID", identity.ID)
+		c.Set("serviceAccountName", identity.Name)
+		c.Set("scopes", identity.Scopes)
+		c.Set("roles", []string{"service"})
+
+		c.Next()
+	}
+}
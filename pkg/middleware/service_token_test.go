@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServiceTokenClientIP(t *testing.T) {
+	_, allowed, _ := net.ParseCIDR("10.0.0.0/8")
+	authenticate := func(ctx context.Context, token, clientIP string) (*ServiceTokenIdentity, error) {
+		if !allowed.Contains(net.ParseIP(clientIP)) {
+			return nil, errors.New("client IP not allowed")
+		}
+		return &ServiceTokenIdentity{ID: "sa1", Scopes: []string{"users:read"}}, nil
+	}
+	routeScopes := map[string]string{"GET /users": "users:read"}
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		wantCode       int
+	}{
+		{"allowed address", nil, "10.1.2.3:1234", "", http.StatusOK},
+		{"spoofed X-Forwarded-For from an untrusted address", nil, "203.0.113.5:1234", "10.1.2.3", http.StatusUnauthorized},
+		{"X-Forwarded-For from an untrusted proxy", []string{"192.0.2.0/24"}, "203.0.113.5:1234", "10.1.2.3", http.StatusUnauthorized},
+		{"X-Forwarded-For from a trusted proxy", []string{"192.0.2.0/24"}, "192.0.2.10:1234", "10.1.2.3", http.StatusOK},
+		{"trusted proxy forwarding a disallowed client", []string{"192.0.2.0/24"}, "192.0.2.10:1234", "203.0.113.5", http.StatusUnauthorized},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if err := router.SetTrustedProxies(tt.trustedProxies); err != nil {
+				t.Fatal(err)
+			}
+			router.GET("/users", ServiceTokenMiddleware("X-Service-Token", authenticate, routeScopes), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Service-Token", "token")
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
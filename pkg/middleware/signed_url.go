@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// SignedURLMiddleware only admits requests whose URL was minted by security.SignURL with
+// secret and has not expired, granting temporary access without a token. The routes it
+// guards should be exempted from request signing with security.skipPaths.
+func SignedURLMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := security.VerifySignedURL(c.Request.URL, secret); err != nil {
+			logger.FromContext(c).Warnf("Signed URL rejected for %s %s from %s: %v", c.Request.Method, c.Request.URL.Path, c.ClientIP(), err)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
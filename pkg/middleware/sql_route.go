@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/sqlcomment"
+)
+
+// SQLRouteMiddleware records the route of the request, its method and path pattern, for
+// tagging the SQL statements it issues. The route is stored in the gin context under
+// sqlcomment.RouteKey and in the request context. Requests matching no route are left alone.
+func SQLRouteMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if path := c.FullPath(); path != "" {
+			route := c.Request.Method + " " + path
+			c.Set(sqlcomment.RouteKey, route)
+			c.Request = c.Request.WithContext(sqlcomment.WithRoute(c.Request.Context(), route))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/sqlcomment"
+)
+
+func TestSQLRouteMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var fromGin, fromRequest string
+	router := gin.New()
+	router.Use(SQLRouteMiddleware())
+	router.GET("/users/:id", func(c *gin.Context) {
+		fromGin = sqlcomment.Route(c)
+		fromRequest = sqlcomment.Route(c.Request.Context())
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if fromGin != "GET /users/:id" || fromRequest != "GET /users/:id" {
+		t.Errorf("route = %q (gin) and %q (request), want the path pattern", fromGin, fromRequest)
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StrictJSONOptions declares the limits enforced on JSON request bodies. A zero value disables that limit.
+type StrictJSONOptions struct {
+	MaxBodyBytes int64
+	MaxDepth     int
+}
+
+// jsonLimitError describes why a body was rejected
+type jsonLimitError struct {
+	status int
+	code   string
+	msg    string
+}
+
+// StrictJSONMiddleware rejects JSON bodies that are too large, nested too deeply, malformed
+// or contain a key twice. Duplicate keys are rejected because decoders disagree on which value
+// wins, so the value the handler sees could differ from the one covered by the signature.
+// It must run before any middleware that reads the body; the body is restored afterwards.
+func StrictJSONMiddleware(options StrictJSONOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.ContentLength == 0 || !strings.HasPrefix(c.ContentType(), "application/json") {
+			c.Next()
+			return
+		}
+
+		if options.MaxBodyBytes > 0 && c.Request.ContentLength > options.MaxBodyBytes {
+			abortJSONLimit(c, tooLargeError(options.MaxBodyBytes))
+			return
+		}
+
+		reader := c.Request.Body
+		if options.MaxBodyBytes > 0 {
+			// Content-Length can be absent or wrong, so the limit is enforced on the read as well
+			reader = http.MaxBytesReader(c.Writer, c.Request.Body, options.MaxBodyBytes)
+		}
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				abortJSONLimit(c, tooLargeError(options.MaxBodyBytes))
+				return
+			}
+			abortJSONLimit(c, &jsonLimitError{http.StatusBadRequest, "invalid_body", "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		if len(bytes.TrimSpace(body)) > 0 {
+			if limitErr := checkJSON(body, options.MaxDepth); limitErr != nil {
+				abortJSONLimit(c, limitErr)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// checkJSON walks the token stream of body, checking nesting depth and duplicate object keys
+func checkJSON(body []byte, maxDepth int) *jsonLimitError {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	if err := walkJSON(dec, 0, maxDepth); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &jsonLimitError{http.StatusBadRequest, "invalid_json", "unexpected data after JSON value"}
+	}
+	return nil
+}
+
+// walkJSON consumes one JSON value from dec; depth is the number of enclosing containers
+func walkJSON(dec *json.Decoder, depth, maxDepth int) *jsonLimitError {
+	tok, err := dec.Token()
+	if err != nil {
+		return &jsonLimitError{http.StatusBadRequest, "invalid_json", err.Error()}
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+	if maxDepth > 0 && depth+1 > maxDepth {
+		return &jsonLimitError{http.StatusBadRequest, "too_deep", fmt.Sprintf("JSON nesting exceeds %d levels", maxDepth)}
+	}
+
+	keys := map[string]bool{}
+	for dec.More() {
+		if delim == '{' {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return &jsonLimitError{http.StatusBadRequest, "invalid_json", err.Error()}
+			}
+			key, _ := keyTok.(string)
+			if keys[key] {
+				return &jsonLimitError{http.StatusBadRequest, "duplicate_field", fmt.Sprintf("duplicate field %q", key)}
+			}
+			keys[key] = true
+		}
+		if err := walkJSON(dec, depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+
+	// Closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return &jsonLimitError{http.StatusBadRequest, "invalid_json", err.Error()}
+	}
+	return nil
+}
+
+// tooLargeError builds the error for a body over the size limit
+func tooLargeError(limit int64) *jsonLimitError {
+	return &jsonLimitError{http.StatusRequestEntityTooLarge, "body_too_large", fmt.Sprintf("request body exceeds %d bytes", limit)}
+}
+
+// abortJSONLimit rejects the request with a structured error
+func abortJSONLimit(c *gin.Context, err *jsonLimitError) {
+	c.AbortWithStatusJSON(err.status, gin.H{"error": err.msg, "code": err.code})
+}
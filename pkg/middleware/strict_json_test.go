@@ -0,0 +1,31 @@
+package middleware
+
+import "testing"
+
+func TestCheckJSON(t *testing.T) {
+	cases := []struct {
+		body     string
+		maxDepth int
+		code     string
+	}{
+		{`{"a": 1, "b": [1, 2, {"c": "d"}]}`, 3, ""},
+		{`{"a": 1, "b": [1, 2, {"c": "d"}]}`, 2, "too_deep"},
+		{`{"a": 1, "a": 2}`, 0, "duplicate_field"},
+		{`{"a": {"b": 1}, "c": {"b": 2}}`, 0, ""},
+		{`[{"a": 1}, {"a": 1}]`, 0, ""},
+		{`{"a": {"b": 1, "b": 2}}`, 0, "duplicate_field"},
+		{`{"a": 1`, 0, "invalid_json"},
+		{`{"a": 1} {"b": 2}`, 0, "invalid_json"},
+	}
+
+	for _, tc := range cases {
+		err := checkJSON([]byte(tc.body), tc.maxDepth)
+		code := ""
+		if err != nil {
+			code = err.code
+		}
+		if code != tc.code {
+			t.Errorf("checkJSON(%s, %d) = %q, want %q", tc.body, tc.maxDepth, code, tc.code)
+		}
+	}
+}
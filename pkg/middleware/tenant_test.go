@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	tokenService := jwt.NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, map[string]string{"acme": "acme-secret", "globex": "globex-secret"},
+		jwt.TokenEncryption{}, jwt.NewMemoryTokenStore(), nil)
+	issue := func(tenantID string) string {
+		pair, err := tokenService.GenerateTokenPairWithOptions(context.Background(), "u1", "a@example.com", nil, jwt.TokenOptions{TenantID: tenantID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pair.AccessToken
+	}
+	acmeToken, sharedToken := issue("acme"), issue("")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TenantMiddleware("X-Tenant-ID", map[string]bool{"acme": true, "globex": true}))
+	router.GET("/me", AuthMiddleware(tokenService, nil, nil, nil, nil, nil), func(c *gin.Context) {
+		c.String(http.StatusOK, dbtenant.From(c.Request.Context()))
+	})
+	router.POST("/login", func(c *gin.Context) {
+		c.String(http.StatusOK, dbtenant.From(c))
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		header   string
+		wantCode int
+		wantBody string
+	}{
+		{"tenant token in its tenant", http.MethodGet, "/me", acmeToken, "acme", http.StatusOK, "acme"},
+		{"tenant token in another tenant", http.MethodGet, "/me", acmeToken, "globex", http.StatusUnauthorized, ""},
+		{"tenant token in the shared schema", http.MethodGet, "/me", acmeToken, "", http.StatusUnauthorized, ""},
+		{"shared token in a tenant", http.MethodGet, "/me", sharedToken, "acme", http.StatusUnauthorized, ""},
+		{"shared token in the shared schema", http.MethodGet, "/me", sharedToken, "", http.StatusOK, ""},
+		{"unauthenticated request selects tenant", http.MethodPost, "/login", "", "globex", http.StatusOK, "globex"},
+		{"unknown tenant", http.MethodPost, "/login", "", "initech", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body)
+			}
+			if tt.wantCode == http.StatusOK && w.Body.String() != tt.wantBody {
+				t.Errorf("tenant = %q, want %q", w.Body, tt.wantBody)
+			}
+		})
+	}
+}
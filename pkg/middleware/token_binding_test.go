@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+func TestTokenBinding(t *testing.T) {
+	tokenService := jwt.NewJWTService("access", "refresh", 15*time.Minute, time.Hour, 24*time.Hour, 10*time.Minute,
+		900, 3600, nil, "gin-pkg", "", 0, nil, jwt.TokenEncryption{}, jwt.NewMemoryTokenStore(), nil)
+
+	for _, mode := range []TokenBindingMode{TokenBindingEnforce, TokenBindingLogOnly} {
+		binding := &TokenBinding{Mode: mode, IP: true, IPv4PrefixLength: 24, IPv6PrefixLength: 64, UserAgent: true}
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		// As in the app, X-Forwarded-For is only trusted from configured proxies, none here
+		if err := router.SetTrustedProxies(nil); err != nil {
+			t.Fatal(err)
+		}
+		router.Use(binding.Middleware())
+		router.POST("/login", func(c *gin.Context) {
+			pair, err := tokenService.GenerateTokenPairWithOptions(context.Background(), "u1", "a@example.com", nil, jwt.TokenOptions{
+				Fingerprint: jwt.FingerprintFromContext(c.Request.Context()),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.String(http.StatusOK, pair.AccessToken)
+		})
+		router.GET("/me", AuthMiddleware(tokenService, nil, nil, binding, nil, nil), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		send := func(method, path, remoteAddr, userAgent, token string, headers ...string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, nil)
+			req.RemoteAddr = remoteAddr
+			req.Header.Set("User-Agent", userAgent)
+			for i := 0; i+1 < len(headers); i += 2 {
+				req.Header.Set(headers[i], headers[i+1])
+			}
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		token := send(http.MethodPost, "/login", "203.0.113.10:1234", "app/1.0", "").Body.String()
+		if w := send(http.MethodGet, "/me", "203.0.113.10:1234", "app/1.0", token); w.Code != http.StatusNoContent {
+			t.Errorf("%s: same client: got status %d", mode, w.Code)
+		}
+		if w := send(http.MethodGet, "/me", "203.0.113.99:1234", "app/1.0", token); w.Code != http.StatusNoContent {
+			t.Errorf("%s: same network: got status %d", mode, w.Code)
+		}
+
+		want := http.StatusUnauthorized
+		if mode == TokenBindingLogOnly {
+			want = http.StatusNoContent
+		}
+		if w := send(http.MethodGet, "/me", "198.51.100.10:1234", "app/1.0", token); w.Code != want {
+			t.Errorf("%s: other network: got status %d, want %d", mode, w.Code, want)
+		}
+		if w := send(http.MethodGet, "/me", "203.0.113.10:1234", "curl/8.0", token); w.Code != want {
+			t.Errorf("%s: other user agent: got status %d, want %d", mode, w.Code, want)
+		}
+		// A stolen token cannot be made to match by claiming the victim's address
+		if w := send(http.MethodGet, "/me", "198.51.100.10:1234", "app/1.0", token, "X-Forwarded-For", "203.0.113.10"); w.Code != want {
+			t.Errorf("%s: spoofed X-Forwarded-For: got status %d, want %d", mode, w.Code, want)
+		}
+	}
+
+	// 启用绑定之前签发的令牌没有指纹，仍然有效
+	unbound, _ := tokenService.GenerateTokenPair(context.Background(), "u1", "a@example.com", nil)
+	binding := &TokenBinding{Mode: TokenBindingEnforce, UserAgent: true}
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(tokenService, nil, nil, binding, nil, nil), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+unbound.AccessToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("unbound token: got status %d", w.Code)
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// TokenCookies delivers access and refresh tokens in HttpOnly cookies instead of the
+// response body, for browser clients that should not keep tokens where scripts can read them.
+// Cookie-authenticated requests must pass CSRFMiddleware.
+type TokenCookies struct {
+	AccessName  string
+	RefreshName string
+	Domain      string
+	Path        string
+	// RefreshPath limits the refresh token cookie to the routes that read it
+	RefreshPath string
+	// RefreshMaxAge is how long browsers keep the refresh token cookie
+	RefreshMaxAge time.Duration
+	Secure        bool
+	SameSite      http.SameSite
+}
+
+// Set writes the cookies of a token pair. A pair without a refresh token leaves the
+// refresh token cookie as it is.
+func (t *TokenCookies) Set(c *gin.Context, tokens *jwt.TokenPair) {
+	t.write(c, t.AccessName, tokens.AccessToken, t.Path, time.Duration(tokens.ExpiresIn)*time.Second)
+	if tokens.RefreshToken != "" {
+		t.write(c, t.RefreshName, tokens.RefreshToken, t.RefreshPath, t.RefreshMaxAge)
+	}
+}
+
+// Clear removes both token cookies
+func (t *TokenCookies) Clear(c *gin.Context) {
+	t.write(c, t.AccessName, "", t.Path, -1)
+	t.write(c, t.RefreshName, "", t.RefreshPath, -1)
+}
+
+// AccessToken returns the access token cookie of a request, or "" if there is none
+func (t *TokenCookies) AccessToken(c *gin.Context) string {
+	token, _ := c.Cookie(t.AccessName)
+	return token
+}
+
+// RefreshToken returns the refresh token cookie of a request, or "" if there is none
+func (t *TokenCookies) RefreshToken(c *gin.Context) string {
+	token, _ := c.Cookie(t.RefreshName)
+	return token
+}
+
+// write sets a token cookie; a negative maxAge deletes it
+func (t *TokenCookies) write(c *gin.Context, name, value, path string, maxAge time.Duration) {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   t.Domain,
+		Path:     path,
+		Secure:   t.Secure,
+		HttpOnly: true,
+		SameSite: t.SameSite,
+	}
+	if maxAge < 0 {
+		cookie.MaxAge = -1
+	} else if maxAge > 0 {
+		cookie.MaxAge = int(maxAge.Seconds())
+	}
+	http.SetCookie(c.Writer, cookie)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/webhook"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// WebhookMiddleware only admits callbacks carrying a valid provider signature of the raw
+// body, which is restored for the handler. Webhook routes should be exempted from request
+// signing with security.skipPaths, since providers cannot sign requests the way clients do.
+func WebhookMiddleware(verifier webhook.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := c.GetRawData()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err := verifier.Verify(c.Request.Header, body); err != nil {
+			logger.FromContext(c).Warnf("Webhook rejected for %s %s from %s: %v", c.Request.Method, c.Request.URL.Path, c.ClientIP(), err)
+			status := http.StatusUnauthorized
+			if errors.Is(err, webhook.ErrMissingSignature) {
+				status = http.StatusBadRequest
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
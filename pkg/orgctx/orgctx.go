@@ -0,0 +1,37 @@
+// Package orgctx carries the organization a request acts in, so queries can be scoped to
+// a single tenant.
+package orgctx
+
+import "context"
+
+// Key is the gin context key holding the ID of the organization a request acts in
+const Key = "orgID"
+
+type orgContextKey struct{}
+
+// With returns a copy of ctx acting in the organization orgID
+func With(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, orgContextKey{}, orgID)
+}
+
+// From returns the organization ctx acts in, or "" when it is not scoped to one. It accepts
+// both request contexts and *gin.Context, which keeps the organization under Key.
+func From(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if orgID, ok := ctx.Value(orgContextKey{}).(string); ok {
+		return orgID
+	}
+	orgID, _ := ctx.Value(Key).(string)
+	return orgID
+}
+
+// Detach returns a context for work that outlives the request, such as background jobs,
+// acting in the same organization as ctx
+func Detach(ctx context.Context) context.Context {
+	if orgID := From(ctx); orgID != "" {
+		return With(context.Background(), orgID)
+	}
+	return context.Background()
+}
@@ -0,0 +1,165 @@
+// Package querylog logs the SQL statements of an ent client with their duration, the rows
+// they returned or affected and the request ID of their context. Statements are logged at
+// Debug level; those slower than a threshold are logged at Warn level.
+package querylog
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Options configures what is logged
+type Options struct {
+	// Debug logs every statement at Debug level
+	Debug bool
+	// SlowThreshold logs statements that take at least this long at Warn level; 0 disables it
+	SlowThreshold time.Duration
+}
+
+// Driver is an ent dialect.Driver that logs the statements it runs, also those of its
+// transactions. Arguments are not logged, as they may hold passwords and personal data.
+// Run schema migrations on the driver it wraps: they read the *sql.Rows of their queries,
+// which the row counting hides.
+type Driver struct {
+	dialect.Driver
+	options Options
+}
+
+// New wraps drv to log its statements
+func New(drv dialect.Driver, options Options) *Driver {
+	return &Driver{Driver: drv, options: options}
+}
+
+// Exec runs and logs a statement
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) error {
+	return d.options.exec(ctx, d.Driver, query, args, v)
+}
+
+// Query runs a query, which is logged once its rows are closed
+func (d *Driver) Query(ctx context.Context, query string, args, v any) error {
+	return d.options.query(ctx, d.Driver, query, args, v)
+}
+
+// Tx starts a transaction whose statements are logged
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, options: d.options}, nil
+}
+
+// BeginTx starts a transaction with options whose statements are logged, for ent clients
+// that set the isolation level or start read-only transactions
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	beginner, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return d.Tx(ctx)
+	}
+	tx, err := beginner.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, options: d.options}, nil
+}
+
+// Tx is a transaction that logs its statements
+type Tx struct {
+	dialect.Tx
+	options Options
+}
+
+// Exec runs and logs a statement in the transaction
+func (t *Tx) Exec(ctx context.Context, query string, args, v any) error {
+	return t.options.exec(ctx, t.Tx, query, args, v)
+}
+
+// Query runs a query in the transaction, which is logged once its rows are closed
+func (t *Tx) Query(ctx context.Context, query string, args, v any) error {
+	return t.options.query(ctx, t.Tx, query, args, v)
+}
+
+// exec runs a statement on drv and logs it with the number of rows it affected
+func (o Options) exec(ctx context.Context, drv dialect.ExecQuerier, query string, args, v any) error {
+	start := time.Now()
+	err := drv.Exec(ctx, query, args, v)
+	rows := int64(-1)
+	if res, ok := v.(*sql.Result); ok && err == nil && *res != nil {
+		if affected, err := (*res).RowsAffected(); err == nil {
+			rows = affected
+		}
+	}
+	o.log(ctx, query, time.Since(start), rows, err)
+	return err
+}
+
+// query runs a query on drv. Its rows are counted as they are read and the query is logged
+// when they are closed, so the duration includes reading the rows.
+func (o Options) query(ctx context.Context, drv dialect.ExecQuerier, query string, args, v any) error {
+	start := time.Now()
+	err := drv.Query(ctx, query, args, v)
+	if rows, ok := v.(*entsql.Rows); ok && err == nil && rows.ColumnScanner != nil {
+		rows.ColumnScanner = &countedRows{
+			ColumnScanner: rows.ColumnScanner,
+			done: func(n int64) {
+				o.log(ctx, query, time.Since(start), n, nil)
+			},
+		}
+		return nil
+	}
+	o.log(ctx, query, time.Since(start), -1, err)
+	return err
+}
+
+// log logs a statement; rows is -1 when unknown
+func (o Options) log(ctx context.Context, query string, duration time.Duration, rows int64, err error) {
+	slow := o.SlowThreshold > 0 && duration >= o.SlowThreshold
+	if !slow && !o.Debug {
+		return
+	}
+
+	keysAndValues := []any{"query", query, "duration", duration}
+	if rows >= 0 {
+		keysAndValues = append(keysAndValues, "rows", rows)
+	}
+	if err != nil {
+		keysAndValues = append(keysAndValues, "error", err)
+	}
+	if slow {
+		logger.FromContext(ctx).Warnw("Slow SQL statement", keysAndValues...)
+		return
+	}
+	logger.FromContext(ctx).Debugw("SQL statement", keysAndValues...)
+}
+
+// countedRows counts the rows read from a query and reports them once when closed
+type countedRows struct {
+	entsql.ColumnScanner
+	rows   int64
+	closed bool
+	done   func(rows int64)
+}
+
+func (r *countedRows) Next() bool {
+	next := r.ColumnScanner.Next()
+	if next {
+		r.rows++
+	}
+	return next
+}
+
+func (r *countedRows) Close() error {
+	err := r.ColumnScanner.Close()
+	if !r.closed {
+		r.closed = true
+		r.done(r.rows)
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+package querylog
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeDriver returns rows rows from queries and affects rows rows on exec, taking delay
+type fakeDriver struct {
+	rows  int
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeDriver) Exec(ctx context.Context, query string, args, v any) error {
+	time.Sleep(f.delay)
+	if res, ok := v.(*sql.Result); ok && f.err == nil {
+		*res = driverResult(f.rows)
+	}
+	return f.err
+}
+
+func (f *fakeDriver) Query(ctx context.Context, query string, args, v any) error {
+	time.Sleep(f.delay)
+	if f.err != nil {
+		return f.err
+	}
+	v.(*entsql.Rows).ColumnScanner = &fakeRows{left: f.rows}
+	return nil
+}
+
+func (f *fakeDriver) Tx(ctx context.Context) (dialect.Tx, error) { return dialect.NopTx(f), nil }
+func (f *fakeDriver) Close() error                               { return nil }
+func (f *fakeDriver) Dialect() string                            { return dialect.Postgres }
+
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+type fakeRows struct {
+	entsql.ColumnScanner
+	left int
+}
+
+func (r *fakeRows) Next() bool {
+	r.left--
+	return r.left >= 0
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var logs bytes.Buffer
+	logger.SetDefaultLogger(logger.NewDefaultLogger(zapcore.AddSync(&logs), logger.DebugLevel))
+	t.Cleanup(func() { logger.SetDefaultLogger(logger.NewZapLogger(logger.InfoLevel, true)) })
+	return &logs
+}
+
+func TestDriverLogsStatements(t *testing.T) {
+	logs := captureLogs(t)
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+	d := New(&fakeDriver{rows: 3}, Options{Debug: true})
+
+	var res sql.Result
+	if err := d.Exec(ctx, `UPDATE "users" SET "active" = $1`, []any{true}, &res); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	logged := logs.Len()
+	rows := &entsql.Rows{}
+	if err := d.Query(ctx, `SELECT "id" FROM "users"`, []any{}, rows); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if logs.Len() != logged {
+		t.Errorf("query logged before its rows were closed: %s", logs)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+	rows.Close()
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged %d lines, want one per statement:\n%s", len(lines), logs)
+	}
+	for i, want := range []string{"UPDATE", "SELECT"} {
+		line := lines[i]
+		if !strings.Contains(line, "DEBUG") || !strings.Contains(line, want) ||
+			!strings.Contains(line, `"rows": 3`) || !strings.Contains(line, `"request_id": "req-1"`) {
+			t.Errorf("log line %q, want a debug entry for %s with 3 rows and the request ID", line, want)
+		}
+		if strings.Contains(line, "true") {
+			t.Errorf("log line %q contains the arguments", line)
+		}
+	}
+}
+
+func TestDriverSlowStatements(t *testing.T) {
+	logs := captureLogs(t)
+	ctx := context.Background()
+
+	New(&fakeDriver{}, Options{SlowThreshold: time.Hour}).Exec(ctx, "UPDATE fast", nil, nil)
+	if logs.Len() != 0 {
+		t.Errorf("logged a fast statement without Debug: %s", logs)
+	}
+
+	queryErr := errors.New("canceling statement due to statement timeout")
+	d := New(&fakeDriver{delay: 5 * time.Millisecond, err: queryErr}, Options{SlowThreshold: time.Millisecond})
+	tx, err := d.Tx(ctx)
+	if err != nil {
+		t.Fatalf("Tx() error = %v", err)
+	}
+	if err := tx.Query(ctx, "SELECT slow", nil, &entsql.Rows{}); !errors.Is(err, queryErr) {
+		t.Errorf("Query() error = %v, want %v", err, queryErr)
+	}
+	if line := logs.String(); !strings.Contains(line, "WARN") || !strings.Contains(line, "SELECT slow") || !strings.Contains(line, "statement timeout") {
+		t.Errorf("log %q, want a warning for the slow statement in the transaction with its error", line)
+	}
+}
@@ -0,0 +1,21 @@
+package sms
+
+import (
+	"context"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// LogSender writes messages to the log instead of sending them, for development and tests
+type LogSender struct{}
+
+// NewLogSender creates a new log sender
+func NewLogSender() Sender {
+	return &LogSender{}
+}
+
+// Send logs the message
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	logger.Infof("【短信】To: %s\n%s", msg.To, msg.Body)
+	return nil
+}
@@ -0,0 +1,16 @@
+package sms
+
+import (
+	"context"
+)
+
+// Message is a plain-text SMS
+type Message struct {
+	To   string
+	Body string
+}
+
+// Sender sends SMS messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
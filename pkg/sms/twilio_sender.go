@@ -0,0 +1,81 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// twilioAPIURL is the base URL of the Twilio REST API
+const twilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// TwilioConfig holds the Twilio account settings
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	// From is the sending phone number in E.164 format
+	From string
+	// MessagingServiceSID is used instead of From when set
+	MessagingServiceSID string
+	// BaseURL overrides the Twilio API URL, e.g. for tests
+	BaseURL string
+}
+
+// TwilioSender sends messages through the Twilio Messages API
+type TwilioSender struct {
+	config TwilioConfig
+	client *http.Client
+}
+
+// NewTwilioSender creates a new Twilio sender
+func NewTwilioSender(config TwilioConfig) Sender {
+	if config.BaseURL == "" {
+		config.BaseURL = twilioAPIURL
+	}
+	return &TwilioSender{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send sends the message
+func (s *TwilioSender) Send(ctx context.Context, msg Message) error {
+	form := url.Values{
+		"To":   {msg.To},
+		"Body": {msg.Body},
+	}
+	if s.config.MessagingServiceSID != "" {
+		form.Set("MessagingServiceSid", s.config.MessagingServiceSID)
+	} else {
+		form.Set("From", s.config.From)
+	}
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", s.config.BaseURL, url.PathEscape(s.config.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.config.AccountSID, s.config.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		// Twilio reports failures as {"code": ..., "message": ...}
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("failed to send sms: twilio returned status %d: %d %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+	}
+	return nil
+}
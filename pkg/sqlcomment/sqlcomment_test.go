@@ -0,0 +1,91 @@
+package sqlcomment
+
+import (
+	"context"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"github.com/hewenyu/gin-pkg/pkg/actorctx"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// recordingDriver records the statements it is asked to run
+type recordingDriver struct {
+	dialect.Driver
+	queries []string
+}
+
+func (d *recordingDriver) Exec(ctx context.Context, query string, args, v any) error {
+	d.queries = append(d.queries, query)
+	return nil
+}
+
+func (d *recordingDriver) Query(ctx context.Context, query string, args, v any) error {
+	d.queries = append(d.queries, query)
+	return nil
+}
+
+func (d *recordingDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return dialect.NopTx(d), nil
+}
+
+func TestComment(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "background", ctx: context.Background(), want: ""},
+		{
+			name: "request",
+			ctx: actorctx.With(WithRoute(logger.ContextWithRequestID(context.Background(), "req-1"),
+				"GET /api/v1/users/:id"), "u1"),
+			want: "/*request_id='req-1',route='GET%20%2Fapi%2Fv1%2Fusers%2F:id',user_id='u1'*/",
+		},
+		{
+			name: "only some tags",
+			ctx:  logger.ContextWithRequestID(context.Background(), "req-2"),
+			want: "/*request_id='req-2'*/",
+		},
+		{
+			name: "values cannot end the comment",
+			ctx:  WithRoute(context.Background(), "GET /x'*/; DROP TABLE users; --"),
+			want: "/*route='GET%20%2Fx%27%2A%2F%3B%20DROP%20TABLE%20users%3B%20--'*/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Comment(tt.ctx); got != tt.want {
+				t.Errorf("Comment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDriverTagsStatements(t *testing.T) {
+	rec := &recordingDriver{}
+	drv := New(rec)
+	ctx := WithRoute(context.Background(), "POST /api/v1/groups")
+
+	drv.Query(ctx, "SELECT 1", nil, nil)
+	drv.Exec(context.Background(), "DELETE FROM nonces", nil, nil)
+	tx, err := drv.Tx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Exec(ctx, "INSERT INTO groups", nil, nil)
+
+	want := []string{
+		"SELECT 1 /*route='POST%20%2Fapi%2Fv1%2Fgroups'*/",
+		"DELETE FROM nonces",
+		"INSERT INTO groups /*route='POST%20%2Fapi%2Fv1%2Fgroups'*/",
+	}
+	if len(rec.queries) != len(want) {
+		t.Fatalf("ran %q, want %q", rec.queries, want)
+	}
+	for i := range want {
+		if rec.queries[i] != want[i] {
+			t.Errorf("statement %d = %q, want %q", i, rec.queries[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotExist is returned when an object does not exist
+var ErrNotExist = errors.New("object does not exist")
+
+// Object is a stored blob together with its content type
+type Object struct {
+	Data        []byte
+	ContentType string
+}
+
+// Storage stores blobs under slash-separated keys
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+}
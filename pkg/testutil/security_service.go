@@ -0,0 +1,181 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+)
+
+// defaultTimestampWindow is used when ValidateTimestamp is called without a window
+const defaultTimestampWindow = 5 * time.Minute
+
+// anySignature is an algorithm that accepts every signature, used while AcceptAnySignature is set
+type anySignature struct {
+	security.Algorithm
+}
+
+func (anySignature) Verify(message []byte, key, signature string) error {
+	return nil
+}
+
+// FakeSecurityService is an in-memory security.SecurityService. Nonces are predictable
+// ("nonce-1", "nonce-2", ...) and single use, timestamps are checked against Clock and
+// replays are remembered in memory.
+//
+// By default every signature is accepted, so requests through SecurityMiddleware only need
+// a timestamp, a nonce and any non-empty signature. Set AcceptAnySignature to false to verify
+// signatures with the signature secret, and use Sign to sign requests in tests.
+type FakeSecurityService struct {
+	// Clock is the time timestamps are validated against
+	Clock *Clock
+	// AcceptAnySignature skips signature verification
+	AcceptAnySignature bool
+
+	mu        sync.Mutex
+	secret    string
+	secondary string
+	// secondaryUntil ends the overlap of the secondary secret
+	secondaryUntil time.Time
+	seq            int
+	nonces         map[string]bool
+	seen           map[string]bool
+	signatures     *security.SignatureRegistry
+}
+
+// NewFakeSecurityService creates a fake security service that accepts every signature,
+// with the signature secret "test-secret" and a new Clock
+func NewFakeSecurityService() *FakeSecurityService {
+	signatures, _ := security.NewSignatureRegistry()
+	return &FakeSecurityService{
+		Clock:              NewClock(),
+		AcceptAnySignature: true,
+		secret:             "test-secret",
+		nonces:             make(map[string]bool),
+		seen:               make(map[string]bool),
+		signatures:         signatures,
+	}
+}
+
+// Timestamp returns the current time of Clock in the millisecond format clients send
+func (s *FakeSecurityService) Timestamp() string {
+	return strconv.FormatInt(s.Clock.Now().UnixMilli(), 10)
+}
+
+// Sign signs a request with the given signature version and the current secret
+func (s *FakeSecurityService) Sign(version string, req security.SignedRequest) (string, error) {
+	return s.signatures.Sign(version, req, s.GetSignatureSecret())
+}
+
+func (s *FakeSecurityService) GenerateNonce(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	nonce := fmt.Sprintf("nonce-%d", s.seq)
+	s.nonces[nonce] = true
+	return nonce, nil
+}
+
+func (s *FakeSecurityService) ValidateTimestamp(timestamp string, validityWindow time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid timestamp format")
+	}
+	if validityWindow <= 0 {
+		validityWindow = defaultTimestampWindow
+	}
+	diff := s.Clock.Now().Sub(time.UnixMilli(ts))
+	if diff < -validityWindow || diff > validityWindow {
+		return errors.New("timestamp is outside validity window")
+	}
+	return nil
+}
+
+func (s *FakeSecurityService) ValidateSignature(params map[string]string, signature string) error {
+	return s.VerifySignature(security.SignVersion1, security.SignedRequest{Params: params}, signature)
+}
+
+func (s *FakeSecurityService) SignatureScheme(version string) (security.SignatureScheme, bool) {
+	scheme, ok := s.signatures.Lookup(version)
+	if ok && s.AcceptAnySignature {
+		scheme.Algorithm = anySignature{scheme.Algorithm}
+	}
+	return scheme, ok
+}
+
+func (s *FakeSecurityService) VerifySignature(version string, req security.SignedRequest, signature string) error {
+	if _, ok := s.signatures.Lookup(version); !ok {
+		return fmt.Errorf("%w: %s", security.ErrUnsupportedSignVersion, version)
+	}
+	if s.AcceptAnySignature {
+		return nil
+	}
+	var err error
+	for _, secret := range s.SignatureSecrets() {
+		if err = s.signatures.Verify(version, req, secret, signature); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (s *FakeSecurityService) ValidateNonce(ctx context.Context, nonce string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.nonces[nonce] {
+		return errors.New("invalid or expired nonce")
+	}
+	delete(s.nonces, nonce)
+	return nil
+}
+
+func (s *FakeSecurityService) CheckReplay(ctx context.Context, timestamp, nonce, signature string, validityWindow time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := timestamp + "\n" + nonce + "\n" + signature
+	if s.seen[key] {
+		return security.ErrReplayed
+	}
+	s.seen[key] = true
+	return nil
+}
+
+func (s *FakeSecurityService) GetSignatureSecret() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.secret
+}
+
+func (s *FakeSecurityService) SignatureSecrets() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secrets := []string{s.secret}
+	if s.secondary != "" && s.Clock.Now().Before(s.secondaryUntil) {
+		secrets = append(secrets, s.secondary)
+	}
+	return secrets
+}
+
+// RotateSignatureSecret replaces the secret. The old secret stays accepted until Clock has
+// advanced by overlap.
+func (s *FakeSecurityService) RotateSignatureSecret(next string, overlap time.Duration) error {
+	if next == "" {
+		return errors.New("next signature secret is not configured")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next == s.secret {
+		return errors.New("next signature secret is already active")
+	}
+	s.secondary = ""
+	if overlap > 0 {
+		s.secondary = s.secret
+		s.secondaryUntil = s.Clock.Now().Add(overlap)
+	}
+	s.secret = next
+	return nil
+}
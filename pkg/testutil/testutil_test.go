@@ -0,0 +1,148 @@
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+	"github.com/hewenyu/gin-pkg/pkg/auth/security"
+	"github.com/hewenyu/gin-pkg/pkg/middleware"
+)
+
+func TestFakeTokenService(t *testing.T) {
+	tokens := NewFakeTokenService()
+	tokens.RoleScopes["admin"] = []string{"users:read", "users:write"}
+
+	pair, err := tokens.GenerateTokenPair(context.Background(), "u1", "u1@example.com", []string{"admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pair.AccessToken != "access-1" || pair.RefreshToken != "refresh-2" {
+		t.Errorf("tokens = %q, %q; want deterministic tokens", pair.AccessToken, pair.RefreshToken)
+	}
+	claims, err := tokens.ValidateToken(context.Background(), pair.AccessToken, jwt.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != "u1" || len(claims.Scopes) != 2 {
+		t.Errorf("claims = %+v", claims)
+	}
+	if _, err := tokens.ValidateToken(context.Background(), pair.AccessToken, jwt.RefreshToken); err == nil {
+		t.Error("access token accepted as a refresh token")
+	}
+
+	refreshed, err := tokens.RefreshTokens(context.Background(), pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.RefreshTokens(context.Background(), pair.RefreshToken); err == nil {
+		t.Error("refresh token reused")
+	}
+
+	tokens.Clock.Advance(tokens.AccessTokenDuration)
+	if _, err := tokens.ValidateToken(context.Background(), refreshed.AccessToken, jwt.AccessToken); err == nil {
+		t.Error("expired access token accepted")
+	}
+
+	if err := tokens.RevokeUserTokens(context.Background(), "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.ValidateToken(context.Background(), refreshed.RefreshToken, jwt.RefreshToken); err == nil {
+		t.Error("revoked refresh token accepted")
+	}
+	if _, err := tokens.ValidateToken(context.Background(), tokens.AccessToken("u1"), jwt.AccessToken); err != nil {
+		t.Errorf("token issued after revocation rejected: %v", err)
+	}
+
+	if err := tokens.BlacklistUser(context.Background(), "u1", tokens.Clock.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.ValidateToken(context.Background(), tokens.AccessToken("u1"), jwt.AccessToken); err == nil {
+		t.Error("token of a blacklisted user accepted")
+	}
+	tokens.Clock.Advance(time.Hour)
+	if blacklisted, _ := tokens.IsUserBlacklisted(context.Background(), "u1"); blacklisted {
+		t.Error("user still blacklisted after the ban ended")
+	}
+}
+
+func TestFakesWithMiddleware(t *testing.T) {
+	tokens := NewFakeTokenService()
+	securityService := NewFakeSecurityService()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.SecurityMiddleware(securityService, middleware.SecurityOptions{TimestampWindow: time.Minute}))
+	router.Use(middleware.AuthMiddleware(tokens, nil, nil, nil, nil, nil))
+	router.GET("/api/v1/users/me", func(c *gin.Context) {
+		claims, _ := middleware.ClaimsFromContext(c)
+		c.String(http.StatusOK, claims.UserID)
+	})
+
+	send := func(token, signature string) *httptest.ResponseRecorder {
+		nonce, _ := securityService.GenerateNonce(context.Background())
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Timestamp", securityService.Timestamp())
+		req.Header.Set("X-Nonce", nonce)
+		req.Header.Set("X-Sign", signature)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(tokens.AccessToken("u1"), "anything"); w.Code != http.StatusOK || w.Body.String() != "u1" {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if w := send("forged", "anything"); w.Code != http.StatusUnauthorized {
+		t.Errorf("unknown token: status = %d", w.Code)
+	}
+
+	securityService.AcceptAnySignature = false
+	if w := send(tokens.AccessToken("u1"), "anything"); w.Code != http.StatusBadRequest {
+		t.Errorf("bad signature with AcceptAnySignature off: status = %d", w.Code)
+	}
+}
+
+func TestFakeSecurityService(t *testing.T) {
+	s := NewFakeSecurityService()
+	s.AcceptAnySignature = false
+
+	nonce, _ := s.GenerateNonce(context.Background())
+	if err := s.ValidateNonce(context.Background(), nonce); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ValidateNonce(context.Background(), nonce); err == nil {
+		t.Error("nonce accepted twice")
+	}
+
+	timestamp := s.Timestamp()
+	s.Clock.Advance(2 * time.Minute)
+	if err := s.ValidateTimestamp(timestamp, time.Minute); err == nil {
+		t.Error("stale timestamp accepted")
+	}
+
+	params := map[string]string{"timestamp": timestamp, "email": "a@example.com"}
+	signature, err := s.Sign(security.SignVersion1, security.SignedRequest{Params: params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ValidateSignature(params, signature); err != nil {
+		t.Errorf("signature rejected: %v", err)
+	}
+
+	if err := s.RotateSignatureSecret("next-secret", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ValidateSignature(params, signature); err != nil {
+		t.Errorf("signature with the old secret rejected during the overlap: %v", err)
+	}
+	s.Clock.Advance(time.Minute)
+	if err := s.ValidateSignature(params, signature); err == nil {
+		t.Error("signature with the old secret accepted after the overlap")
+	}
+}
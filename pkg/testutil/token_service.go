@@ -0,0 +1,243 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/hewenyu/gin-pkg/pkg/auth/jwt"
+)
+
+// FakeTokenService is an in-memory jwt.TokenService. Tokens are opaque, predictable strings
+// such as "access-1" and "refresh-2" whose claims are kept in memory; nothing is signed.
+// Expiry follows Clock, and revocation, user blacklists, scopes and enrichers behave like
+// those of the real service.
+type FakeTokenService struct {
+	// Clock drives expiry and blacklist ends
+	Clock *Clock
+	// AccessTokenDuration and RefreshTokenDuration are the token lifetimes
+	AccessTokenDuration  time.Duration
+	RefreshTokenDuration time.Duration
+	// RoleScopes maps roles to the scopes tokens carry, as auth.roleScopes does
+	RoleScopes map[string][]string
+
+	mu     sync.Mutex
+	seq    int
+	tokens map[string]*fakeToken
+	// blacklist holds the IDs of blacklisted tokens
+	blacklist map[string]bool
+	// revokedSeq is the sequence number up to which the tokens of a user are revoked
+	revokedSeq       map[string]int
+	blacklistedUntil map[string]time.Time
+	enrichers        []jwt.ClaimsEnricher
+}
+
+// fakeToken is an issued token and its place in the issue order
+type fakeToken struct {
+	seq    int
+	claims jwt.Claims
+}
+
+// NewFakeTokenService creates a fake token service with 15 minute access tokens, 24 hour
+// refresh tokens and a new Clock
+func NewFakeTokenService() *FakeTokenService {
+	return &FakeTokenService{
+		Clock:                NewClock(),
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 24 * time.Hour,
+		RoleScopes:           map[string][]string{},
+		tokens:               make(map[string]*fakeToken),
+		blacklist:            make(map[string]bool),
+		revokedSeq:           make(map[string]int),
+		blacklistedUntil:     make(map[string]time.Time),
+	}
+}
+
+// AccessToken issues an access token for a user and returns it, for tests that only need
+// an Authorization header
+func (s *FakeTokenService) AccessToken(userID string, roles ...string) string {
+	pair, err := s.GenerateTokenPair(context.Background(), userID, userID+"@example.com", roles)
+	if err != nil {
+		panic(err)
+	}
+	return pair.AccessToken
+}
+
+func (s *FakeTokenService) GenerateTokenPair(ctx context.Context, userID string, email string, roles []string) (*jwt.TokenPair, error) {
+	return s.GenerateTokenPairWithOptions(ctx, userID, email, roles, jwt.TokenOptions{})
+}
+
+func (s *FakeTokenService) GenerateTokenPairWithOptions(ctx context.Context, userID string, email string, roles []string, opts jwt.TokenOptions) (*jwt.TokenPair, error) {
+	scopes := opts.Scopes
+	if scopes == nil {
+		scopes = s.ScopesForRoles(roles)
+	}
+	accessDuration, refreshDuration := s.AccessTokenDuration, s.RefreshTokenDuration
+	if opts.MaxLifetime > 0 {
+		accessDuration = min(accessDuration, opts.MaxLifetime)
+		refreshDuration = min(refreshDuration, opts.MaxLifetime)
+	}
+
+	claims := jwt.Claims{
+		UserID:                 userID,
+		Email:                  email,
+		Roles:                  roles,
+		RememberMe:             opts.RememberMe,
+		ImpersonatedBy:         opts.ImpersonatedBy,
+		ClientID:               opts.ClientID,
+		Guest:                  opts.Guest,
+		TenantID:               opts.TenantID,
+		OrgID:                  opts.OrgID,
+		Scopes:                 scopes,
+		PasswordChangeRequired: opts.PasswordChangeRequired,
+		Fingerprint:            opts.Fingerprint,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accessClaims := claims
+	accessClaims.TokenType = string(jwt.AccessToken)
+	extra := map[string]interface{}{}
+	for _, enricher := range s.enrichers {
+		if err := enricher(ctx, accessClaims, extra); err != nil {
+			return nil, fmt.Errorf("failed to enrich token claims: %w", err)
+		}
+	}
+	if len(extra) > 0 {
+		claims.Extra = extra
+	}
+
+	pair := &jwt.TokenPair{ExpiresIn: int64(accessDuration.Seconds())}
+	pair.AccessToken = s.issue(claims, jwt.AccessToken, accessDuration)
+	if !opts.Guest {
+		pair.RefreshToken = s.issue(claims, jwt.RefreshToken, refreshDuration)
+	}
+	return pair, nil
+}
+
+// issue stores the claims of a new token and returns the token. Callers hold mu.
+func (s *FakeTokenService) issue(claims jwt.Claims, tokenType jwt.TokenType, duration time.Duration) string {
+	s.seq++
+	now := s.Clock.Now()
+	token := fmt.Sprintf("%s-%d", tokenType, s.seq)
+	claims.TokenType = string(tokenType)
+	claims.TokenID = fmt.Sprintf("token-%d", s.seq)
+	claims.RegisteredClaims = gojwt.RegisteredClaims{
+		ID:        claims.TokenID,
+		Subject:   claims.UserID,
+		IssuedAt:  gojwt.NewNumericDate(now),
+		NotBefore: gojwt.NewNumericDate(now),
+		ExpiresAt: gojwt.NewNumericDate(now.Add(duration)),
+	}
+	s.tokens[token] = &fakeToken{seq: s.seq, claims: claims}
+	return token
+}
+
+func (s *FakeTokenService) ValidateToken(ctx context.Context, tokenString string, tokenType jwt.TokenType) (*jwt.Claims, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.tokens[tokenString]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	claims := issued.claims
+	now := s.Clock.Now()
+	if !now.Before(claims.ExpiresAt.Time) {
+		return nil, errors.New("token has expired")
+	}
+	if jwt.TokenType(claims.TokenType) != tokenType {
+		return nil, errors.New("token type mismatch")
+	}
+	if s.blacklist[claims.TokenID] || issued.seq <= s.revokedSeq[claims.UserID] {
+		return nil, errors.New("token has been revoked")
+	}
+	if now.Before(s.blacklistedUntil[claims.UserID]) {
+		return nil, errors.New("user has been blacklisted")
+	}
+	return &claims, nil
+}
+
+func (s *FakeTokenService) RefreshTokens(ctx context.Context, refreshToken string) (*jwt.TokenPair, error) {
+	claims, err := s.ValidateToken(ctx, refreshToken, jwt.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token: %w", err)
+	}
+	if err := s.BlacklistToken(ctx, claims.TokenID, 0); err != nil {
+		return nil, err
+	}
+	return s.GenerateTokenPairWithOptions(ctx, claims.UserID, claims.Email, claims.Roles, jwt.TokenOptions{
+		RememberMe:             claims.RememberMe,
+		ImpersonatedBy:         claims.ImpersonatedBy,
+		Scopes:                 claims.Scopes,
+		ClientID:               claims.ClientID,
+		Fingerprint:            claims.Fingerprint,
+		TenantID:               claims.TenantID,
+		OrgID:                  claims.OrgID,
+		PasswordChangeRequired: claims.PasswordChangeRequired,
+	})
+}
+
+// RevokeRefreshToken blacklists a refresh token; the fake records no sessions, so reason is ignored
+func (s *FakeTokenService) RevokeRefreshToken(ctx context.Context, claims *jwt.Claims, reason string) error {
+	return s.BlacklistToken(ctx, claims.TokenID, 0)
+}
+
+func (s *FakeTokenService) ScopesForRoles(roles []string) []string {
+	scopes := []string{}
+	for _, role := range roles {
+		for _, scope := range s.RoleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// BlacklistToken blacklists a token; the fake keeps it blacklisted regardless of expiration
+func (s *FakeTokenService) BlacklistToken(ctx context.Context, tokenID string, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blacklist[tokenID] = true
+	return nil
+}
+
+func (s *FakeTokenService) IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blacklist[tokenID], nil
+}
+
+// RevokeUserTokens revokes every token issued to a user so far. Unlike the real service,
+// which compares issue times in seconds, tokens issued right after the call stay valid.
+func (s *FakeTokenService) RevokeUserTokens(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revokedSeq[userID] = s.seq
+	return nil
+}
+
+func (s *FakeTokenService) BlacklistUser(ctx context.Context, userID string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blacklistedUntil[userID] = until
+	return nil
+}
+
+func (s *FakeTokenService) IsUserBlacklisted(ctx context.Context, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Clock.Now().Before(s.blacklistedUntil[userID]), nil
+}
+
+func (s *FakeTokenService) AddClaimsEnricher(enricher jwt.ClaimsEnricher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enrichers = append(s.enrichers, enricher)
+}
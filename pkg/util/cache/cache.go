@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound is returned by Get for keys that are not cached or have expired
+var ErrNotFound = errors.New("cache: key not found")
+
+// Cache is a key-value cache with TTLs, shared by the instances of the service when backed by
+// Redis (RedisCache) or local to the process (MemoryCache). Keys are used as given; prefix them
+// with dbtenant.ScopedKey for data that differs between tenants.
+type Cache interface {
+	// Get returns the value cached under key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set caches value under key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys; missing keys are ignored
+	Delete(ctx context.Context, keys ...string) error
+	// GetOrLoad returns the value cached under key, or loads and caches it for ttl. Concurrent
+	// calls for a missing key in the same process share a single load. Failing to read or write
+	// the cache is logged and only costs a load, so the cache never fails a read on its own.
+	GetOrLoad(ctx context.Context, key string, ttl time.Duration, load Loader[[]byte]) ([]byte, error)
+	// Increment increments the fixed-window counter under key and returns the new count and
+	// the time remaining until the window resets. The first increment opens a window.
+	Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+	// IncrementSliding counts a hit under key and returns the estimated number of hits within
+	// the last window, including this one, and the time until the current fixed window ends;
+	// see util.RedisClient.IncrementSlidingWindow
+	IncrementSliding(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+}
+
+// getOrLoad implements GetOrLoad on top of the Get and Set of c, sharing loads through loads
+func getOrLoad(ctx context.Context, c Cache, loads *singleflight.Group, key string, ttl time.Duration, load Loader[[]byte]) ([]byte, error) {
+	value, err := c.Get(ctx, key)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		logger.FromContext(ctx).Warnf("Failed to read cached %q: %v", key, err)
+	}
+
+	// The first caller loads for every caller waiting on the key, with its own context
+	shared, err, _ := loads.Do(key, func() (any, error) {
+		value, err := load(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Set(ctx, key, value, ttl); err != nil {
+			logger.FromContext(ctx).Warnf("Failed to cache %q: %v", key, err)
+		}
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shared.([]byte), nil
+}
+
+// GetOrLoadJSON is GetOrLoad for values cached as JSON
+func GetOrLoadJSON[V any](ctx context.Context, c Cache, key string, ttl time.Duration, load Loader[V]) (V, error) {
+	var v V
+	data, err := c.GetOrLoad(ctx, key, ttl, func(ctx context.Context, key string) ([]byte, error) {
+		loaded, err := load(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(loaded)
+	})
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		// An entry cached in another format, e.g. by an older version of V, is replaced
+		logger.FromContext(ctx).Warnf("Failed to decode cached %q, reloading it: %v", key, err)
+		if v, err = load(ctx, key); err != nil {
+			return v, err
+		}
+		if data, err := json.Marshal(v); err == nil {
+			_ = c.Set(ctx, key, data, ttl)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	c := NewMemoryCache(2)
+	c.now = func() time.Time { return now }
+
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a missing key = %v, want ErrNotFound", err)
+	}
+	_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+	if v, err := c.Get(ctx, "a"); err != nil || string(v) != "1" {
+		t.Fatalf("Get = %q, %v, want 1", v, err)
+	}
+	_ = c.Delete(ctx, "a", "missing")
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a deleted key = %v, want ErrNotFound", err)
+	}
+
+	_ = c.Set(ctx, "b", []byte("2"), time.Minute)
+	now = now.Add(2 * time.Minute)
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of an expired key = %v, want ErrNotFound", err)
+	}
+
+	// The cache holds two values, so adding a third drops one
+	_ = c.Set(ctx, "c", []byte("3"), time.Minute)
+	_ = c.Set(ctx, "d", []byte("4"), time.Minute)
+	_ = c.Set(ctx, "e", []byte("5"), time.Minute)
+	if n := len(c.values); n != 2 {
+		t.Fatalf("cache holds %d values, want 2", n)
+	}
+	if v, err := c.Get(ctx, "e"); err != nil || string(v) != "5" {
+		t.Fatalf("Get of the newest key = %q, %v, want 5", v, err)
+	}
+}
+
+func TestMemoryCacheIncrement(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	c := NewMemoryCache(0)
+	c.now = func() time.Time { return now }
+
+	for want := int64(1); want <= 3; want++ {
+		count, resetIn, err := c.Increment(ctx, "k", time.Minute)
+		if err != nil || count != want || resetIn != time.Minute {
+			t.Fatalf("Increment = %d, %v, %v, want %d, 1m0s", count, resetIn, err, want)
+		}
+	}
+	now = now.Add(30 * time.Second)
+	if _, resetIn, _ := c.Increment(ctx, "k", time.Minute); resetIn != 30*time.Second {
+		t.Fatalf("reset in %v, want 30s", resetIn)
+	}
+	now = now.Add(30 * time.Second)
+	if count, _, _ := c.Increment(ctx, "k", time.Minute); count != 1 {
+		t.Fatalf("count after the window = %d, want a new window", count)
+	}
+}
+
+func TestGetOrLoadSharesLoads(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(0)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context, key string) ([]byte, error) {
+		loads.Add(1)
+		<-release
+		return []byte("v:" + key), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := c.GetOrLoad(ctx, "k", time.Minute, load)
+			if err != nil {
+				t.Errorf("GetOrLoad: %v", err)
+			}
+			results[i] = string(v)
+		}(i)
+	}
+	// Give every caller the chance to join the load before it finishes
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("loaded %d times, want once", n)
+	}
+	for _, v := range results {
+		if v != "v:k" {
+			t.Fatalf("GetOrLoad = %q, want v:k", v)
+		}
+	}
+	if v, _ := c.GetOrLoad(ctx, "k", time.Minute, load); string(v) != "v:k" || loads.Load() != 1 {
+		t.Fatal("a cached key should not be loaded again")
+	}
+
+	failing := func(ctx context.Context, key string) ([]byte, error) { return nil, errors.New("down") }
+	if _, err := c.GetOrLoad(ctx, "other", time.Minute, failing); err == nil {
+		t.Fatal("load errors should be returned")
+	}
+	if _, err := c.Get(ctx, "other"); !errors.Is(err, ErrNotFound) {
+		t.Fatal("failed loads should not be cached")
+	}
+}
+
+func TestGetOrLoadJSON(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	ctx := context.Background()
+	c := NewMemoryCache(0)
+
+	load := func(ctx context.Context, key string) (user, error) { return user{Name: key}, nil }
+	if u, err := GetOrLoadJSON(ctx, c, "ann", time.Minute, load); err != nil || u.Name != "ann" {
+		t.Fatalf("GetOrLoadJSON = %+v, %v", u, err)
+	}
+	if v, _ := c.Get(ctx, "ann"); string(v) != `{"name":"ann"}` {
+		t.Fatalf("cached %s, want the JSON encoding", v)
+	}
+
+	// Entries that no longer decode are reloaded and replaced
+	_ = c.Set(ctx, "bob", []byte("not json"), time.Minute)
+	if u, err := GetOrLoadJSON(ctx, c, "bob", time.Minute, load); err != nil || u.Name != "bob" {
+		t.Fatalf("GetOrLoadJSON of a corrupt entry = %+v, %v", u, err)
+	}
+	if v, _ := c.Get(ctx, "bob"); string(v) != `{"name":"bob"}` {
+		t.Fatalf("cached %s, want the reloaded value", v)
+	}
+}
+
+func TestMemoryCacheIncrementSliding(t *testing.T) {
+	ctx := context.Background()
+	// Start at the beginning of a fixed window
+	now := time.Unix(600, 0)
+	c := NewMemoryCache(0)
+	c.now = func() time.Time { return now }
+
+	for want := int64(1); want <= 4; want++ {
+		count, resetIn, err := c.IncrementSliding(ctx, "k", time.Minute)
+		if err != nil || count != want || resetIn != time.Minute {
+			t.Fatalf("IncrementSliding = %d, %v, %v, want %d, 1m0s", count, resetIn, err, want)
+		}
+	}
+	// A fixed window would have reset; a quarter into the next window, three quarters of the
+	// previous one still count
+	now = now.Add(75 * time.Second)
+	if count, resetIn, _ := c.IncrementSliding(ctx, "k", time.Minute); count != 4 || resetIn != 45*time.Second {
+		t.Fatalf("IncrementSliding = %d, %v, want 1 + 3 of the previous window, 45s", count, resetIn)
+	}
+	if count, _, _ := c.IncrementSliding(ctx, "other", time.Minute); count != 1 {
+		t.Fatalf("another key counted %d, want 1", count)
+	}
+	now = now.Add(2 * time.Minute)
+	if count, _, _ := c.IncrementSliding(ctx, "k", time.Minute); count != 1 {
+		t.Fatalf("count after two windows = %d, want 1", count)
+	}
+}
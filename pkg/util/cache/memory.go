@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/util"
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultMemoryEntries bounds a MemoryCache when no size is given
+const defaultMemoryEntries = 10000
+
+// MemoryCache is a Cache in process memory, for tests, single-instance deployments and data
+// that may differ between instances for a while. It holds up to a fixed number of values and
+// of counters; once full, expired entries are dropped first and then arbitrary ones.
+type MemoryCache struct {
+	maxEntries int
+	loads      singleflight.Group
+
+	mu       sync.Mutex
+	values   map[string]memoryValue
+	counters map[string]memoryValue
+	// lastPurge is when expired entries were last dropped
+	lastPurge time.Time
+	// now returns the current time; tests replace it to expire entries
+	now func() time.Time
+}
+
+// memoryValue is a cached value or counter and the time it expires
+type memoryValue struct {
+	value     []byte
+	count     int64
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an empty in-memory cache holding up to maxEntries values and as many
+// counters, 10000 if zero
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryEntries
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		values:     make(map[string]memoryValue),
+		counters:   make(map[string]memoryValue),
+		now:        time.Now,
+	}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.values[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), e.value...), nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.makeRoom(c.values, key)
+	// The caller may reuse value
+	c.values[key] = memoryValue{value: append([]byte(nil), value...), expiresAt: c.now().Add(ttl)}
+	return nil
+}
+
+func (c *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.values, key)
+	}
+	return nil
+}
+
+func (c *MemoryCache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load Loader[[]byte]) ([]byte, error) {
+	return getOrLoad(ctx, c, &c.loads, key, ttl, load)
+}
+
+func (c *MemoryCache) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	e, ok := c.counters[key]
+	if !ok || !now.Before(e.expiresAt) {
+		c.makeRoom(c.counters, key)
+		e = memoryValue{expiresAt: now.Add(window)}
+	}
+	e.count++
+	c.counters[key] = e
+	return e.count, e.expiresAt.Sub(now), nil
+}
+
+func (c *MemoryCache) IncrementSliding(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	length := max(int64(window), 1)
+	index, elapsed := now.UnixNano()/length, now.UnixNano()%length
+
+	// Each fixed window has its own counter, read as the previous one during the next window
+	currentKey := slidingWindowKey(key, index)
+	e, ok := c.counters[currentKey]
+	if !ok || !now.Before(e.expiresAt) {
+		c.makeRoom(c.counters, currentKey)
+		e = memoryValue{expiresAt: now.Add(time.Duration(2*length - elapsed))}
+	}
+	e.count++
+	c.counters[currentKey] = e
+
+	var previous int64
+	if p, ok := c.counters[slidingWindowKey(key, index-1)]; ok && now.Before(p.expiresAt) {
+		previous = p.count
+	}
+	return util.SlidingWindowCount(e.count, previous, elapsed, length), time.Duration(length - elapsed), nil
+}
+
+// slidingWindowKey returns the key of the counter of a sliding window during the fixed
+// window with the given index
+func slidingWindowKey(key string, index int64) string {
+	return key + "\x00" + strconv.FormatInt(index, 10)
+}
+
+// makeRoom drops entries from m until key can be added without exceeding maxEntries:
+// expired ones first, at most once a minute, then arbitrary ones. Callers hold mu.
+func (c *MemoryCache) makeRoom(m map[string]memoryValue, key string) {
+	if _, ok := m[key]; ok || len(m) < c.maxEntries {
+		return
+	}
+	if now := c.now(); now.Sub(c.lastPurge) >= time.Minute {
+		c.lastPurge = now
+		for _, entries := range []map[string]memoryValue{c.values, c.counters} {
+			for k, e := range entries {
+				if !now.Before(e.expiresAt) {
+					delete(entries, k)
+				}
+			}
+		}
+	}
+	// Map iteration order is random, so this evicts random entries
+	for k := range m {
+		if len(m) < c.maxEntries {
+			break
+		}
+		delete(m, k)
+	}
+}
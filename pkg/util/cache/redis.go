@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/util"
+	"golang.org/x/sync/singleflight"
+)
+
+// RedisCache is a Cache in Redis, shared by every instance of the service. Values are stored
+// under cache:<namespace>:{key} and counters under ratelimit:{<namespace>:key}, so caches with
+// different namespaces do not collide and FlushNamespace("cache") clears the values.
+type RedisCache struct {
+	client    *util.RedisClient
+	namespace string
+	loads     singleflight.Group
+}
+
+// NewRedisCache creates a cache storing its entries in Redis under namespace
+func NewRedisCache(client *util.RedisClient, namespace string) *RedisCache {
+	return &RedisCache{client: client, namespace: namespace}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok, err := c.client.GetCached(ctx, c.namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.StoreCached(ctx, c.namespace, key, value, ttl)
+}
+
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	return c.client.DeleteCached(ctx, c.namespace, keys...)
+}
+
+func (c *RedisCache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load Loader[[]byte]) ([]byte, error) {
+	return getOrLoad(ctx, c, &c.loads, key, ttl, load)
+}
+
+func (c *RedisCache) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	return c.client.IncrementCounter(ctx, c.namespace+":"+key, window)
+}
+
+func (c *RedisCache) IncrementSliding(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	return c.client.IncrementSlidingWindow(ctx, c.namespace+":"+key, window)
+}
@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+)
+
+// Loader loads the current value for a key from the backing store
+type Loader[V any] func(ctx context.Context, key string) (V, error)
+
+// SWROptions configures a stale-while-revalidate cache
+type SWROptions struct {
+	// FreshFor is how long a loaded value is served without triggering a refresh
+	FreshFor time.Duration
+	// StaleFor is how long after FreshFor a value is still served while it is refreshed in the
+	// background. Past that point the next read loads synchronously.
+	StaleFor time.Duration
+	// RefreshTimeout bounds a single background or synchronous load
+	RefreshTimeout time.Duration
+}
+
+// entry is a cached value together with the time it was loaded
+type entry[V any] struct {
+	value    V
+	loadedAt time.Time
+}
+
+// SWRCache is an in-process stale-while-revalidate cache for small, hot, read-mostly data
+// such as settings and feature flags. Reads of a cached key never wait on the backing store:
+// fresh values are returned directly, stale values are returned while a single background
+// refresh per key brings them up to date. Only a cold key, or one older than FreshFor+StaleFor,
+// is loaded synchronously; if that load fails the last known value is served instead.
+// With a schema per tenant, keys are cached per tenant of the context and loaded in its schema.
+type SWRCache[V any] struct {
+	loader  Loader[V]
+	options SWROptions
+	metrics *swrMetrics
+
+	mu         sync.RWMutex
+	entries    map[string]entry[V]
+	refreshing map[string]bool
+}
+
+// NewSWRCache creates a new stale-while-revalidate cache. The name identifies the cache in the
+// expvar metrics published under "cache.<name>".
+func NewSWRCache[V any](name string, loader Loader[V], options SWROptions) *SWRCache[V] {
+	if options.FreshFor <= 0 {
+		options.FreshFor = 30 * time.Second
+	}
+	if options.StaleFor < 0 {
+		options.StaleFor = 0
+	}
+	if options.RefreshTimeout <= 0 {
+		options.RefreshTimeout = 2 * time.Second
+	}
+
+	c := &SWRCache[V]{
+		loader:     loader,
+		options:    options,
+		entries:    make(map[string]entry[V]),
+		refreshing: make(map[string]bool),
+	}
+	c.metrics = newSWRMetrics(name, c.maxStaleness)
+	return c
+}
+
+// Get returns the value for key, loading it only when nothing usable is cached
+func (c *SWRCache[V]) Get(ctx context.Context, key string) (V, error) {
+	c.mu.RLock()
+	e, ok := c.entries[dbtenant.ScopedKey(ctx, key)]
+	c.mu.RUnlock()
+
+	if ok {
+		age := time.Since(e.loadedAt)
+		switch {
+		case age < c.options.FreshFor:
+			c.metrics.hits.Add(1)
+			return e.value, nil
+		case age < c.options.FreshFor+c.options.StaleFor:
+			c.metrics.staleHits.Add(1)
+			c.refreshAsync(dbtenant.From(ctx), key)
+			return e.value, nil
+		}
+	}
+
+	// Cold or expired: load synchronously
+	c.metrics.misses.Add(1)
+	value, err := c.load(ctx, key)
+	if err != nil {
+		if ok {
+			// Stale-if-error: an old value is better than failing the request
+			c.metrics.staleHits.Add(1)
+			logger.Warnf("Cache %s: failed to reload %q, serving value loaded at %s: %v",
+				c.metrics.name, key, e.loadedAt.Format(time.RFC3339), err)
+			return e.value, nil
+		}
+		var zero V
+		return zero, err
+	}
+	return value, nil
+}
+
+// Set stores a value directly, e.g. when warming the cache or after a local write
+func (c *SWRCache[V]) Set(ctx context.Context, key string, value V) {
+	c.mu.Lock()
+	c.entries[dbtenant.ScopedKey(ctx, key)] = entry[V]{value: value, loadedAt: time.Now()}
+	c.mu.Unlock()
+}
+
+// Invalidate drops a key so the next read loads it again
+func (c *SWRCache[V]) Invalidate(ctx context.Context, key string) {
+	c.mu.Lock()
+	delete(c.entries, dbtenant.ScopedKey(ctx, key))
+	c.mu.Unlock()
+}
+
+// Clear drops every key, e.g. to force a reload after the backing store was changed directly
+func (c *SWRCache[V]) Clear() {
+	c.mu.Lock()
+	c.entries = make(map[string]entry[V])
+	c.mu.Unlock()
+}
+
+// load calls the loader and stores the result
+func (c *SWRCache[V]) load(ctx context.Context, key string) (V, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.options.RefreshTimeout)
+	defer cancel()
+
+	value, err := c.loader(ctx, key)
+	if err != nil {
+		c.metrics.refreshErrors.Add(1)
+		return value, err
+	}
+	c.metrics.refreshes.Add(1)
+	c.Set(ctx, key, value)
+	return value, nil
+}
+
+// refreshAsync reloads key of tenant in the background unless a refresh is already running
+func (c *SWRCache[V]) refreshAsync(tenant, key string) {
+	ctx := dbtenant.With(context.Background(), tenant)
+	scoped := dbtenant.ScopedKey(ctx, key)
+	c.mu.Lock()
+	if c.refreshing[scoped] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[scoped] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, scoped)
+			c.mu.Unlock()
+		}()
+
+		// Detached from the request: it must outlive the read that triggered it
+		if _, err := c.load(ctx, key); err != nil {
+			logger.Warnf("Cache %s: background refresh of %q failed: %v", c.metrics.name, key, err)
+		}
+	}()
+}
+
+// maxStaleness returns how far past FreshFor the oldest cached value is
+func (c *SWRCache[V]) maxStaleness() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var max time.Duration
+	for _, e := range c.entries {
+		if stale := time.Since(e.loadedAt) - c.options.FreshFor; stale > max {
+			max = stale
+		}
+	}
+	return max
+}
+
+// swrMetrics are the counters published for a cache
+type swrMetrics struct {
+	name          string
+	hits          *expvar.Int
+	staleHits     *expvar.Int
+	misses        *expvar.Int
+	refreshes     *expvar.Int
+	refreshErrors *expvar.Int
+}
+
+// newSWRMetrics publishes the metrics of a cache as the expvar map "cache.<name>"
+func newSWRMetrics(name string, maxStaleness func() time.Duration) *swrMetrics {
+	m := &swrMetrics{
+		name:          name,
+		hits:          new(expvar.Int),
+		staleHits:     new(expvar.Int),
+		misses:        new(expvar.Int),
+		refreshes:     new(expvar.Int),
+		refreshErrors: new(expvar.Int),
+	}
+
+	vars := new(expvar.Map).Init()
+	vars.Set("hits", m.hits)
+	vars.Set("stale_hits", m.staleHits)
+	vars.Set("misses", m.misses)
+	vars.Set("refreshes", m.refreshes)
+	vars.Set("refresh_errors", m.refreshErrors)
+	vars.Set("max_staleness_seconds", expvar.Func(func() any {
+		return maxStaleness().Seconds()
+	}))
+
+	// expvar panics on duplicate names; a cache re-created under the same name replaces the old one
+	if existing, ok := expvar.Get("cache." + name).(*expvar.Map); ok {
+		vars.Do(func(kv expvar.KeyValue) { existing.Set(kv.Key, kv.Value) })
+	} else {
+		expvar.Publish("cache."+name, vars)
+	}
+	return m
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/dbtenant"
+)
+
+func TestSWRCacheServesStaleWhileRefreshing(t *testing.T) {
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (int32, error) {
+		n := loads.Add(1)
+		if n > 1 {
+			<-release
+		}
+		return n, nil
+	}
+
+	c := NewSWRCache[int32]("test_stale", loader, SWROptions{FreshFor: 10 * time.Millisecond, StaleFor: time.Minute})
+	ctx := context.Background()
+
+	if v, err := c.Get(ctx, "k"); err != nil || v != 1 {
+		t.Fatalf("cold read = %d, %v", v, err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	// Stale reads return immediately even though the refresh is blocked
+	for i := 0; i < 3; i++ {
+		done := make(chan int32)
+		go func() {
+			v, _ := c.Get(ctx, "k")
+			done <- v
+		}()
+		select {
+		case v := <-done:
+			if v != 1 {
+				t.Fatalf("stale read = %d, want 1", v)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("stale read blocked on refresh")
+		}
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if v, _ := c.Get(ctx, "k"); v == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh never completed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := loads.Load(); n != 2 {
+		t.Fatalf("expected a single background refresh, got %d loads", n)
+	}
+	if c.metrics.staleHits.Value() < 3 {
+		t.Fatalf("stale hits = %d, want >= 3", c.metrics.staleHits.Value())
+	}
+}
+
+func TestSWRCacheServesLastValueOnError(t *testing.T) {
+	fail := false
+	loader := func(ctx context.Context, key string) (string, error) {
+		if fail {
+			return "", errors.New("backend down")
+		}
+		return "v1", nil
+	}
+
+	c := NewSWRCache[string]("test_error", loader, SWROptions{FreshFor: time.Millisecond})
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, "k"); err != nil {
+		t.Fatalf("cold read: %v", err)
+	}
+
+	fail = true
+	time.Sleep(5 * time.Millisecond)
+	if v, err := c.Get(ctx, "k"); err != nil || v != "v1" {
+		t.Fatalf("expired read with failing backend = %q, %v", v, err)
+	}
+	if _, err := c.Get(ctx, "other"); err == nil {
+		t.Fatal("cold read with failing backend should fail")
+	}
+}
+
+func TestSWRCacheKeysByTenant(t *testing.T) {
+	loader := func(ctx context.Context, key string) (string, error) {
+		return dbtenant.From(ctx) + ":" + key, nil
+	}
+	c := NewSWRCache[string]("test_tenants", loader, SWROptions{FreshFor: time.Minute})
+	shared, acme := context.Background(), dbtenant.With(context.Background(), "acme")
+
+	if v, _ := c.Get(shared, "k"); v != ":k" {
+		t.Errorf("shared read = %q, want :k", v)
+	}
+	if v, _ := c.Get(acme, "k"); v != "acme:k" {
+		t.Errorf("tenant read = %q, want the value loaded in the schema of acme", v)
+	}
+
+	c.Set(acme, "k", "updated")
+	if v, _ := c.Get(shared, "k"); v != ":k" {
+		t.Errorf("shared read after a tenant write = %q, want :k", v)
+	}
+	if v, _ := c.Get(acme, "k"); v != "updated" {
+		t.Errorf("tenant read after its write = %q, want updated", v)
+	}
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"golang.org/x/sync/singleflight"
+)
+
+// TieredCache keeps the values of a shared cache, usually a RedisCache, in a small in-process
+// LRU for a short time, so the hottest keys are served without a round trip. Writes and
+// deletes go to the shared cache and drop the local copy; with Publish and HandleInvalidation
+// wired to a broadcast channel such as Redis pub/sub, they drop the copies of the other
+// instances too. Otherwise, and for invalidations missed while disconnected, other instances
+// may serve a stale value for up to TTL. Counters are always kept in the shared cache.
+type TieredCache struct {
+	remote  Cache
+	ttl     time.Duration
+	publish func(ctx context.Context, key string) error
+	loads   singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// order lists the local entries from the most to the least recently used
+	order      *list.List
+	maxEntries int
+	// now returns the current time; tests replace it to expire entries
+	now func() time.Time
+}
+
+// TieredCacheOptions configures a TieredCache
+type TieredCacheOptions struct {
+	// TTL is how long a value is kept locally at most, 1 second if zero
+	TTL time.Duration
+	// MaxEntries bounds the local values, 10000 if zero; the least recently used are dropped
+	// when it is full
+	MaxEntries int
+	// Publish, when set, broadcasts the keys set or deleted to the other instances, which
+	// pass them to HandleInvalidation
+	Publish func(ctx context.Context, key string) error
+}
+
+// tieredEntry is a locally kept value and the time it expires
+type tieredEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewTieredCache puts an in-process LRU in front of remote
+func NewTieredCache(remote Cache, options TieredCacheOptions) *TieredCache {
+	if options.TTL <= 0 {
+		options.TTL = time.Second
+	}
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = defaultMemoryEntries
+	}
+	return &TieredCache{
+		remote:     remote,
+		ttl:        options.TTL,
+		publish:    options.Publish,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: options.MaxEntries,
+		now:        time.Now,
+	}
+}
+
+func (c *TieredCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, ok := c.getLocal(key); ok {
+		return value, nil
+	}
+	value, err := c.remote.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c.setLocal(key, value, c.ttl)
+	return value, nil
+}
+
+func (c *TieredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	c.setLocal(key, value, min(ttl, c.ttl))
+	c.broadcast(ctx, key)
+	return nil
+}
+
+func (c *TieredCache) Delete(ctx context.Context, keys ...string) error {
+	// Drop the local copies first, so this instance stops using them even if the shared delete fails
+	c.mu.Lock()
+	for _, key := range keys {
+		c.deleteLocal(key)
+	}
+	c.mu.Unlock()
+	if err := c.remote.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		c.broadcast(ctx, key)
+	}
+	return nil
+}
+
+func (c *TieredCache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load Loader[[]byte]) ([]byte, error) {
+	return getOrLoad(ctx, c, &c.loads, key, ttl, load)
+}
+
+func (c *TieredCache) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	return c.remote.Increment(ctx, key, window)
+}
+
+func (c *TieredCache) IncrementSliding(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	return c.remote.IncrementSliding(ctx, key, window)
+}
+
+// HandleInvalidation drops the local copy of a key set or deleted by another instance
+func (c *TieredCache) HandleInvalidation(key string) {
+	c.mu.Lock()
+	c.deleteLocal(key)
+	c.mu.Unlock()
+}
+
+// Flush drops every local value. Subscribers call it when they may have missed
+// invalidations, e.g. after reconnecting to the broadcast channel.
+func (c *TieredCache) Flush() {
+	c.mu.Lock()
+	clear(c.entries)
+	c.order.Init()
+	c.mu.Unlock()
+}
+
+// getLocal returns the local value of key if it has not expired
+func (c *TieredCache) getLocal(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*tieredEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.deleteLocal(key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return append([]byte(nil), entry.value...), true
+}
+
+// setLocal keeps value locally for ttl, dropping the least recently used value if full
+func (c *TieredCache) setLocal(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// The caller may reuse value
+	entry := &tieredEntry{key: key, value: append([]byte(nil), value...), expiresAt: c.now().Add(ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	if c.order.Len() >= c.maxEntries {
+		c.deleteLocal(c.order.Back().Value.(*tieredEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+}
+
+// deleteLocal drops the local value of key. Callers hold mu.
+func (c *TieredCache) deleteLocal(key string) {
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// broadcast publishes an invalidation; a failure only leaves other instances serving their
+// local copy until it expires, so it does not fail the write
+func (c *TieredCache) broadcast(ctx context.Context, key string) {
+	if c.publish == nil {
+		return
+	}
+	if err := c.publish(ctx, key); err != nil {
+		logger.FromContext(ctx).Warnf("Failed to publish the invalidation of cached %q, other instances notice it within %s: %v", key, c.ttl, err)
+	}
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingCache counts the reads reaching the cache it wraps
+type countingCache struct {
+	*MemoryCache
+	gets int
+}
+
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.gets++
+	return c.MemoryCache.Get(ctx, key)
+}
+
+func TestTieredCache(t *testing.T) {
+	ctx := context.Background()
+	remote := &countingCache{MemoryCache: NewMemoryCache(0)}
+	var published []string
+	c := NewTieredCache(remote, TieredCacheOptions{
+		TTL:        time.Second,
+		MaxEntries: 2,
+		Publish: func(ctx context.Context, key string) error {
+			published = append(published, key)
+			return nil
+		},
+	})
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	_ = remote.Set(ctx, "a", []byte("1"), time.Minute)
+	for i := 0; i < 3; i++ {
+		if v, err := c.Get(ctx, "a"); err != nil || string(v) != "1" {
+			t.Fatalf("Get = %q, %v, want 1", v, err)
+		}
+	}
+	if remote.gets != 1 {
+		t.Fatalf("%d reads reached the shared cache, want 1", remote.gets)
+	}
+
+	// A change made by another instance shows once the local copy expires or is invalidated
+	_ = remote.Set(ctx, "a", []byte("2"), time.Minute)
+	if v, _ := c.Get(ctx, "a"); string(v) != "1" {
+		t.Fatalf("Get = %q, want the local copy", v)
+	}
+	c.HandleInvalidation("a")
+	if v, _ := c.Get(ctx, "a"); string(v) != "2" {
+		t.Fatalf("Get after an invalidation = %q, want 2", v)
+	}
+	_ = remote.Set(ctx, "a", []byte("3"), time.Minute)
+	now = now.Add(time.Second)
+	if v, _ := c.Get(ctx, "a"); string(v) != "3" {
+		t.Fatalf("Get after the local TTL = %q, want 3", v)
+	}
+
+	// Writes through the cache apply locally at once and are published
+	_ = c.Set(ctx, "b", []byte("4"), time.Minute)
+	_ = c.Delete(ctx, "a")
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a deleted key = %v, want ErrNotFound", err)
+	}
+	if len(published) != 2 || published[0] != "b" || published[1] != "a" {
+		t.Fatalf("published %v, want b and a", published)
+	}
+}
+
+func TestTieredCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	remote := &countingCache{MemoryCache: NewMemoryCache(0)}
+	c := NewTieredCache(remote, TieredCacheOptions{TTL: time.Minute, MaxEntries: 2})
+
+	_ = c.Set(ctx, "a", []byte("1"), time.Hour)
+	_ = c.Set(ctx, "b", []byte("2"), time.Hour)
+	_, _ = c.Get(ctx, "a")
+	_ = c.Set(ctx, "c", []byte("3"), time.Hour)
+
+	for _, key := range []string{"a", "c"} {
+		if _, err := c.Get(ctx, key); err != nil {
+			t.Fatalf("Get(%s): %v", key, err)
+		}
+	}
+	if remote.gets != 0 {
+		t.Fatalf("the recently used keys should be served locally, %d reads reached the shared cache", remote.gets)
+	}
+	_, _ = c.Get(ctx, "b")
+	if remote.gets != 1 {
+		t.Fatal("the least recently used key should have been evicted")
+	}
+
+	c.Flush()
+	_, _ = c.Get(ctx, "a")
+	if remote.gets != 2 {
+		t.Fatal("Flush should drop the local copies")
+	}
+}
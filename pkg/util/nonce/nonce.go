@@ -1,9 +1,19 @@
+// Package nonce stores the one-time nonces clients sign requests with, in Redis, PostgreSQL or
+// process memory.
 package nonce
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
+// NonceService stores issued nonces until they are used or expire
 type NonceService interface {
-	StoreNonce(nonce string, expiration time.Duration) error
-	GetNonce(nonce string) (bool, error)
+	// StoreNonce stores a nonce for expiration
+	StoreNonce(ctx context.Context, nonce string, expiration time.Duration) error
+	// GetNonce removes a nonce and reports whether it was stored and had not expired, in one
+	// atomic step, so a nonce is accepted at most once even by concurrent requests
+	GetNonce(ctx context.Context, nonce string) (bool, error)
+	// Close releases the resources of the service
 	Close() error
 }
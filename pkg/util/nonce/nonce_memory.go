@@ -0,0 +1,85 @@
+package nonce
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultMemoryEntries bounds a NonceMemoryService when no size is given
+const defaultMemoryEntries = 100000
+
+// NonceMemoryService keeps nonces in process memory, for tests and single-instance
+// deployments without Redis. Nonces are lost on restart, and a nonce issued by one instance
+// is unknown to the others.
+type NonceMemoryService struct {
+	mu         sync.Mutex
+	maxEntries int
+	// nonces maps nonces to the time they expire
+	nonces    map[string]time.Time
+	lastPurge time.Time
+	// now returns the current time; tests replace it to expire nonces
+	now func() time.Time
+}
+
+// NewNonceMemoryService creates an empty in-memory nonce service holding up to maxEntries
+// nonces, 100000 if zero. Once full, expired nonces are dropped first and then arbitrary ones.
+func NewNonceMemoryService(maxEntries int) *NonceMemoryService {
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryEntries
+	}
+	return &NonceMemoryService{
+		maxEntries: maxEntries,
+		nonces:     make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+// StoreNonce stores a nonce with an expiration time
+func (s *NonceMemoryService) StoreNonce(ctx context.Context, nonce string, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.makeRoom(nonce)
+	s.nonces[nonce] = s.now().Add(expiration)
+	return nil
+}
+
+// GetNonce deletes a nonce and reports whether it was stored and had not expired
+func (s *NonceMemoryService) GetNonce(ctx context.Context, nonce string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.nonces[nonce]
+	if !ok {
+		return false, nil
+	}
+	delete(s.nonces, nonce)
+	return s.now().Before(expiresAt), nil
+}
+
+// Close does nothing; the nonces are dropped with the service
+func (s *NonceMemoryService) Close() error {
+	return nil
+}
+
+// makeRoom drops nonces until nonce can be added without exceeding maxEntries: expired ones
+// first, at most once a minute, then arbitrary ones. Callers hold mu.
+func (s *NonceMemoryService) makeRoom(nonce string) {
+	if _, ok := s.nonces[nonce]; ok || len(s.nonces) < s.maxEntries {
+		return
+	}
+	if now := s.now(); now.Sub(s.lastPurge) >= time.Minute {
+		s.lastPurge = now
+		for n, expiresAt := range s.nonces {
+			if !now.Before(expiresAt) {
+				delete(s.nonces, n)
+			}
+		}
+	}
+	// Map iteration order is random, so this evicts random nonces
+	for n := range s.nonces {
+		if len(s.nonces) < s.maxEntries {
+			break
+		}
+		delete(s.nonces, n)
+	}
+}
@@ -0,0 +1,67 @@
+package nonce
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Table is the PostgreSQL table of NoncePostgresService
+const Table = "nonces"
+
+// NoncePostgresService keeps nonces in PostgreSQL, shared by every instance of the service,
+// for deployments without Redis. Nonces are stored in the nonces table:
+//
+//	CREATE TABLE nonces (nonce text PRIMARY KEY, expires_at timestamptz NOT NULL)
+//
+// Used nonces are deleted at once; expired ones stay until PurgeExpired removes them.
+// Expiry is judged by the clock of the database, so instances with skewed clocks agree.
+type NoncePostgresService struct {
+	db *sql.DB
+}
+
+// NewNoncePostgresService creates a nonce service backed by db, which must hold the nonces table
+func NewNoncePostgresService(db *sql.DB) *NoncePostgresService {
+	return &NoncePostgresService{db: db}
+}
+
+// StoreNonce stores a nonce with an expiration time
+func (p *NoncePostgresService) StoreNonce(ctx context.Context, nonce string, expiration time.Duration) error {
+	_, err := p.db.ExecContext(ctx, `INSERT INTO `+Table+` (nonce, expires_at)
+		VALUES ($1, now() + $2::float8 * interval '1 second')
+		ON CONFLICT (nonce) DO UPDATE SET expires_at = EXCLUDED.expires_at`,
+		nonce, expiration.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to store nonce: %w", err)
+	}
+	return nil
+}
+
+// GetNonce deletes a nonce and reports whether it existed and had not expired. A single
+// DELETE both checks and invalidates it, so concurrent requests cannot both use the nonce.
+func (p *NoncePostgresService) GetNonce(ctx context.Context, nonce string) (bool, error) {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM `+Table+` WHERE nonce = $1 AND expires_at > now()`, nonce)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume nonce: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume nonce: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// PurgeExpired removes the nonces that expired without being used and returns how many
+func (p *NoncePostgresService) PurgeExpired(ctx context.Context) (int64, error) {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM `+Table+` WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired nonces: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Close closes the database connection
+func (p *NoncePostgresService) Close() error {
+	return p.db.Close()
+}
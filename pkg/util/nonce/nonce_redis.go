@@ -5,15 +5,17 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/go-redis/redis/v8"
+	"github.com/redis/go-redis/v9"
 )
 
+// NonceRedisService keeps nonces in Redis, shared by every instance of the service
 type NonceRedisService struct {
-	client   *redis.Client
+	client   redis.UniversalClient
 	redisKey string
 }
 
-func NewNonceRedisService(redisClient *redis.Client) *NonceRedisService {
+// NewNonceRedisService creates a nonce service backed by Redis
+func NewNonceRedisService(redisClient redis.UniversalClient) *NonceRedisService {
 	return &NonceRedisService{
 		client:   redisClient,
 		redisKey: "nonce",
@@ -21,43 +23,24 @@ func NewNonceRedisService(redisClient *redis.Client) *NonceRedisService {
 }
 
 // StoreNonce stores a nonce with an expiration time
-func (r *NonceRedisService) StoreNonce(nonce string, expiration time.Duration) error {
-	ctx := context.Background()
-	key := fmt.Sprintf("%s:%s", r.redisKey, nonce)
+func (r *NonceRedisService) StoreNonce(ctx context.Context, nonce string, expiration time.Duration) error {
+	key := r.key(nonce)
 	return r.client.Set(ctx, key, "1", expiration).Err()
 }
 
-// GetNonce checks if a nonce exists and invalidates it if found
-func (r *NonceRedisService) GetNonce(nonce string) (bool, error) {
-	ctx := context.Background()
-	key := fmt.Sprintf("%s:%s", r.redisKey, nonce)
-
-	// Use a transaction to check and delete in one atomic operation
-	txf := func(tx *redis.Tx) error {
-		// Check if the key exists
-		exists, err := tx.Exists(ctx, key).Result()
-		if err != nil {
-			return err
-		}
-
-		// If the key exists, delete it to ensure one-time use
-		if exists > 0 {
-			_, err = tx.Del(ctx, key).Result()
-			if err != nil {
-				return err
-			}
-		}
-
-		return nil
-	}
-
-	// Execute the transaction
-	err := r.client.Watch(ctx, txf, key)
+// GetNonce deletes a nonce and reports whether it existed. A single DEL both checks and
+// invalidates it, so concurrent requests cannot both use the nonce.
+func (r *NonceRedisService) GetNonce(ctx context.Context, nonce string) (bool, error) {
+	deleted, err := r.client.Del(ctx, r.key(nonce)).Result()
 	if err != nil {
-		return false, fmt.Errorf("transaction failed: %w", err)
+		return false, err
 	}
+	return deleted > 0, nil
+}
 
-	return true, nil
+// key returns the hash-tagged key for a nonce so it maps to a single cluster slot
+func (r *NonceRedisService) key(nonce string) string {
+	return fmt.Sprintf("%s:{%s}", r.redisKey, nonce)
 }
 
 // Close closes the Redis connection
@@ -0,0 +1,92 @@
+package nonce
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// testNonceService checks that a service accepts a stored nonce once
+func testNonceService(t *testing.T, s NonceService) {
+	t.Helper()
+	ctx := context.Background()
+	if err := s.StoreNonce(ctx, "n1", time.Minute); err != nil {
+		t.Fatalf("StoreNonce: %v", err)
+	}
+	if ok, err := s.GetNonce(ctx, "n1"); err != nil || !ok {
+		t.Fatalf("GetNonce of a stored nonce = %v, %v, want true", ok, err)
+	}
+	if ok, _ := s.GetNonce(ctx, "n1"); ok {
+		t.Fatal("a nonce should only be accepted once")
+	}
+	if ok, _ := s.GetNonce(ctx, "unknown"); ok {
+		t.Fatal("an unknown nonce should not be accepted")
+	}
+}
+
+func TestNonceMemoryService(t *testing.T) {
+	s := NewNonceMemoryService(2)
+	testNonceService(t, s)
+
+	ctx := context.Background()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	_ = s.StoreNonce(ctx, "expiring", time.Second)
+	now = now.Add(2 * time.Second)
+	if ok, _ := s.GetNonce(ctx, "expiring"); ok {
+		t.Fatal("an expired nonce should not be accepted")
+	}
+
+	// The service holds two nonces, so storing a third drops one
+	for _, n := range []string{"a", "b", "c"} {
+		_ = s.StoreNonce(ctx, n, time.Minute)
+	}
+	if len(s.nonces) != 2 {
+		t.Fatalf("service holds %d nonces, want 2", len(s.nonces))
+	}
+	if ok, _ := s.GetNonce(ctx, "c"); !ok {
+		t.Fatal("the newest nonce should be kept")
+	}
+}
+
+// TestNoncePostgresService runs against the database set by TEST_POSTGRES_HOST, with the
+// optional TEST_POSTGRES_PORT, _USERNAME, _PASSWORD and _DATABASE
+func TestNoncePostgresService(t *testing.T) {
+	host := os.Getenv("TEST_POSTGRES_HOST")
+	if host == "" {
+		t.Skip("TEST_POSTGRES_HOST is not set")
+	}
+	env := func(name, fallback string) string {
+		if value := os.Getenv("TEST_POSTGRES_" + name); value != "" {
+			return value
+		}
+		return fallback
+	}
+	db, err := sql.Open("pgx", fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		env("USERNAME", "postgres"), env("PASSWORD", ""), host, env("PORT", "5432"), env("DATABASE", "gin_pkg_test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+Table+` (nonce text PRIMARY KEY, expires_at timestamptz NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	s := NewNoncePostgresService(db)
+	defer s.Close()
+	testNonceService(t, s)
+
+	if err := s.StoreNonce(ctx, "expired", -time.Second); err != nil {
+		t.Fatalf("StoreNonce: %v", err)
+	}
+	if ok, _ := s.GetNonce(ctx, "expired"); ok {
+		t.Fatal("an expired nonce should not be accepted")
+	}
+	if purged, err := s.PurgeExpired(ctx); err != nil || purged < 1 {
+		t.Fatalf("PurgeExpired = %d, %v, want the expired nonce removed", purged, err)
+	}
+}
@@ -2,64 +2,472 @@ package util
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/go-redis/redis/v8"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// defaultClusterRetries is how often an operation is retried after a cluster redirect or resharding error
+	defaultClusterRetries = 3
+	// clusterRetryBackoff is the base delay between retries, doubled on every attempt
+	clusterRetryBackoff = 20 * time.Millisecond
+	// maxRetryBackoff caps the delay between retries
+	maxRetryBackoff = time.Second
+	// defaultFailoverTimeout is how long operations are retried while Sentinel fails over the master
+	defaultFailoverTimeout = 15 * time.Second
 )
 
 // RedisClient wraps Redis operations
 type RedisClient struct {
-	client *redis.Client
+	client     redis.UniversalClient
+	maxRetries int
+	// retryTimeout, if set, retries operations for this long instead of maxRetries times
+	retryTimeout time.Duration
+	// transient reports the errors worth retrying, IsClusterTransientError if nil
+	transient func(error) bool
+	// fallback, if set, keeps nonces and token revocations in process memory while Redis is unavailable
+	fallback *redisFallback
 }
 
 // NewRedisClient creates a new Redis client
 func NewRedisClient(host string, port int, password string, db int) (*RedisClient, error) {
+	return NewRedisClientWithOptions(RedisOptions{Host: host, Port: port, Password: password, DB: db})
+}
+
+// RedisConnOptions configures the connections to Redis nodes in every deployment mode. Zero
+// timeouts keep the go-redis defaults: 5 seconds to dial and 3 seconds to read and write.
+type RedisConnOptions struct {
+	// Username authenticates with Redis 6 ACLs; empty uses the default user
+	Username string
+	// TLSConfig, if set, connects over TLS, as most managed Redis providers require
+	TLSConfig    *tls.Config
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// RedisOptions configures a client talking to a single Redis node
+type RedisOptions struct {
+	Host     string
+	Port     int
+	Password string
+	DB       int
+	Conn     RedisConnOptions
+}
+
+// NewRedisClientWithOptions creates a new Redis client with the given connection options
+func NewRedisClientWithOptions(opts RedisOptions) (*RedisClient, error) {
 	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", host, port),
-		Password: password,
-		DB:       db,
+		Addr:         fmt.Sprintf("%s:%d", opts.Host, opts.Port),
+		Username:     opts.Conn.Username,
+		Password:     opts.Password,
+		DB:           opts.DB,
+		TLSConfig:    opts.Conn.TLSConfig,
+		DialTimeout:  opts.Conn.DialTimeout,
+		ReadTimeout:  opts.Conn.ReadTimeout,
+		WriteTimeout: opts.Conn.WriteTimeout,
+		// Request deadlines bound network reads and writes too
+		ContextTimeoutEnabled: true,
 	})
 
+	return newRedisClient(client)
+}
+
+// RedisClusterOptions configures a client talking to a Redis Cluster
+type RedisClusterOptions struct {
+	// Addrs are seed nodes (host:port); the other nodes are discovered from them
+	Addrs    []string
+	Password string
+	// MaxRedirects is how often a command follows MOVED and ASK replies, 3 if zero
+	MaxRedirects int
+	// ReadOnly sends read-only commands to replicas
+	ReadOnly bool
+	// RouteByLatency sends read-only commands to the node with the lowest latency, and
+	// RouteRandomly to a random node; both imply ReadOnly
+	RouteByLatency bool
+	RouteRandomly  bool
+	Conn           RedisConnOptions
+}
+
+// NewRedisClusterClient creates a new Redis client talking to a Redis Cluster
+func NewRedisClusterClient(addrs []string, password string) (*RedisClient, error) {
+	return NewRedisClusterClientWithOptions(RedisClusterOptions{Addrs: addrs, Password: password})
+}
+
+// NewRedisClusterClientWithOptions creates a new Redis client talking to a Redis Cluster
+// with the given routing options
+func NewRedisClusterClientWithOptions(opts RedisClusterOptions) (*RedisClient, error) {
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:          opts.Addrs,
+		Password:       opts.Password,
+		MaxRedirects:   opts.MaxRedirects,
+		ReadOnly:       opts.ReadOnly,
+		RouteByLatency: opts.RouteByLatency,
+		RouteRandomly:  opts.RouteRandomly,
+		Username:       opts.Conn.Username,
+		TLSConfig:      opts.Conn.TLSConfig,
+		DialTimeout:    opts.Conn.DialTimeout,
+		ReadTimeout:    opts.Conn.ReadTimeout,
+		WriteTimeout:   opts.Conn.WriteTimeout,
+		// Request deadlines bound network reads and writes too
+		ContextTimeoutEnabled: true,
+	})
+
+	return newRedisClient(client)
+}
+
+// RedisSentinelOptions configures a client finding its master through Redis Sentinel
+type RedisSentinelOptions struct {
+	// MasterName is the name the sentinels monitor the master under
+	MasterName string
+	// SentinelAddrs are the addresses (host:port) of the sentinels
+	SentinelAddrs []string
+	// SentinelUsername and SentinelPassword authenticate with the sentinels, Conn.Username and
+	// Password with the master. Conn.TLSConfig applies to both.
+	SentinelUsername string
+	SentinelPassword string
+	Password         string
+	DB               int
+	Conn             RedisConnOptions
+	// FailoverTimeout is how long operations are retried while the master is failed over,
+	// 15 seconds if zero
+	FailoverTimeout time.Duration
+}
+
+// NewRedisSentinelClient creates a new Redis client that asks the sentinels for the current
+// master and follows it when they fail over to a replica. Operations failing while the old
+// master is unreachable or already demoted are retried until FailoverTimeout has passed.
+func NewRedisSentinelClient(opts RedisSentinelOptions) (*RedisClient, error) {
+	client := redis.NewFailoverClient(&redis.FailoverOptions{
+		MasterName:       opts.MasterName,
+		SentinelAddrs:    opts.SentinelAddrs,
+		SentinelUsername: opts.SentinelUsername,
+		SentinelPassword: opts.SentinelPassword,
+		Username:         opts.Conn.Username,
+		Password:         opts.Password,
+		DB:               opts.DB,
+		TLSConfig:        opts.Conn.TLSConfig,
+		DialTimeout:      opts.Conn.DialTimeout,
+		ReadTimeout:      opts.Conn.ReadTimeout,
+		WriteTimeout:     opts.Conn.WriteTimeout,
+		// Request deadlines bound network reads and writes too
+		ContextTimeoutEnabled: true,
+	})
+
+	r, err := newRedisClient(client)
+	if err != nil {
+		return nil, err
+	}
+	r.retryTimeout = opts.FailoverTimeout
+	if r.retryTimeout <= 0 {
+		r.retryTimeout = defaultFailoverTimeout
+	}
+	r.transient = IsFailoverTransientError
+	return r, nil
+}
+
+// newRedisClient tests the connection and wraps the client
+func newRedisClient(client redis.UniversalClient) (*RedisClient, error) {
 	// Test the connection
 	ctx := context.Background()
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
-	return &RedisClient{client: client}, nil
+	instrument(client)
+	return &RedisClient{client: client, maxRetries: defaultClusterRetries}, nil
 }
 
+// Keys wrap their identifying part in a hash tag ({...}) so that all keys derived from
+// the same nonce, token or counter land in the same cluster slot and can be used together
+// in multi-key commands, pipelines and transactions.
+
+// nonceKey returns the key storing a nonce
+func nonceKey(nonce string) string {
+	return fmt.Sprintf("nonce:{%s}", nonce)
+}
+
+// replayKey returns the key marking a signed request as seen
+func replayKey(digest string) string {
+	return fmt.Sprintf("replay:{%s}", digest)
+}
+
+// tokenBlacklistKey returns the key marking a token as blacklisted
+func tokenBlacklistKey(tokenID string) string {
+	return fmt.Sprintf("blacklist:token:{%s}", tokenID)
+}
+
+// legacyTokenBlacklistKey returns the untagged key used before hash tagging was introduced.
+// It is still read so tokens revoked before an upgrade stay revoked until they expire.
+func legacyTokenBlacklistKey(tokenID string) string {
+	return fmt.Sprintf("blacklist:token:%s", tokenID)
+}
+
+// userRevocationKey returns the key holding the time before which all tokens of a user are revoked
+func userRevocationKey(userID string) string {
+	return fmt.Sprintf("revoked:user:{%s}", userID)
+}
+
+// userBlacklistKey returns the key holding the time until which every token of a user is rejected
+func userBlacklistKey(userID string) string {
+	return fmt.Sprintf("blacklist:user:{%s}", userID)
+}
+
+// otpKey returns the key storing the pending one-time login code of a phone number
+func otpKey(phone string) string {
+	return fmt.Sprintf("otp:{%s}", phone)
+}
+
+// authCodeKey returns the key storing a pending OAuth authorization code by its hash
+func authCodeKey(codeHash string) string {
+	return fmt.Sprintf("oauth:code:{%s}", codeHash)
+}
+
+// cacheKey returns the key of a cache entry, see RedisCache in pkg/util/cache
+func cacheKey(namespace, key string) string {
+	return fmt.Sprintf("cache:%s:{%s}", namespace, key)
+}
+
+// statsKey returns the key caching computed statistics
+func statsKey(key string) string {
+	return fmt.Sprintf("stats:{%s}", key)
+}
+
+// rateLimitKey returns the key of a rate limit counter
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("ratelimit:{%s}", key)
+}
+
+// slidingWindowKey returns the key of the counter of a sliding window rate limit during
+// the fixed window with the given index
+func slidingWindowKey(key string, index int64) string {
+	return fmt.Sprintf("ratelimit:{%s}:%d", key, index)
+}
+
+// sessionKey returns the key storing the claims of an opaque token by the token's hash
+func sessionKey(tokenHash string) string {
+	return fmt.Sprintf("session:{%s}", tokenHash)
+}
+
+// actionTokenKey returns the key storing a pending one-time action token by its hash
+func actionTokenKey(tokenHash string) string {
+	return fmt.Sprintf("action:{%s}", tokenHash)
+}
+
+// lastSeenKey is the hash of pending last-seen times, user ID to unix seconds, not yet
+// written to the database. It is a single key, so it lives in one cluster slot.
+const lastSeenKey = "lastseen:{pending}"
+
+// revocationChannel is the pub/sub channel carrying token revocation events between instances
+const revocationChannel = "revocations"
+
+// Namespaces are the key prefixes owned by this client, as accepted by FlushNamespace
+var Namespaces = []string{"nonce", "blacklist", "revoked", "ratelimit", "otp", "oauth", "replay", "session", "action", "lastseen", "stats", "cache", "lock"}
+
 // BlacklistToken adds a token to the blacklist
-func (r *RedisClient) BlacklistToken(tokenID string, expiration time.Duration) error {
-	ctx := context.Background()
-	key := fmt.Sprintf("blacklist:token:%s", tokenID)
-	return r.client.Set(ctx, key, "1", expiration).Err()
+func (r *RedisClient) BlacklistToken(ctx context.Context, tokenID string, expiration time.Duration) error {
+	key := tokenBlacklistKey(tokenID)
+	return r.withFallback(ctx, func() error {
+		return r.client.Set(ctx, key, "1", expiration).Err()
+	}, func(s *memoryStore) {
+		s.set(key, "1", expiration)
+	})
 }
 
 // IsTokenBlacklisted checks if a token is blacklisted
-func (r *RedisClient) IsTokenBlacklisted(tokenID string) (bool, error) {
-	ctx := context.Background()
-	key := fmt.Sprintf("blacklist:token:%s", tokenID)
-	exists, err := r.client.Exists(ctx, key).Result()
+func (r *RedisClient) IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	if _, ok := r.localGet(tokenBlacklistKey(tokenID)); ok {
+		return true, nil
+	}
+	// The two keys live in different slots, so they are checked one at a time
+	for _, key := range []string{tokenBlacklistKey(tokenID), legacyTokenBlacklistKey(tokenID)} {
+		var exists int64
+		unavailable := false
+		err := r.withFallback(ctx, func() error {
+			var err error
+			exists, err = r.client.Exists(ctx, key).Result()
+			return err
+		}, func(*memoryStore) {
+			unavailable = true
+		})
+		if err != nil {
+			return false, err
+		}
+		if exists > 0 {
+			return true, nil
+		}
+		// The fallback store was already checked, and the legacy key would fail the same way
+		if unavailable {
+			break
+		}
+	}
+	return false, nil
+}
+
+// RevokeUserTokens records that all tokens of a user issued at or before revokedAt are revoked
+func (r *RedisClient) RevokeUserTokens(ctx context.Context, userID string, revokedAt time.Time, expiration time.Duration) error {
+	key := userRevocationKey(userID)
+	return r.withFallback(ctx, func() error {
+		return r.client.Set(ctx, key, revokedAt.Unix(), expiration).Err()
+	}, func(s *memoryStore) {
+		s.set(key, strconv.FormatInt(revokedAt.Unix(), 10), expiration)
+	})
+}
+
+// UserTokensRevokedAt returns the time recorded by RevokeUserTokens, or the zero time if none
+func (r *RedisClient) UserTokensRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	return r.getUnixTime(ctx, userRevocationKey(userID))
+}
+
+// BlacklistUser rejects every token of a user until the given time. A zero or past time lifts the blacklist.
+func (r *RedisClient) BlacklistUser(ctx context.Context, userID string, until time.Time) error {
+	key := userBlacklistKey(userID)
+	expiration := time.Until(until)
+	if expiration <= 0 {
+		r.localDelete(key)
+		return r.withFallback(ctx, func() error {
+			return r.client.Del(ctx, key).Err()
+		}, func(*memoryStore) {})
+	}
+	return r.withFallback(ctx, func() error {
+		return r.client.Set(ctx, key, until.Unix(), expiration).Err()
+	}, func(s *memoryStore) {
+		s.set(key, strconv.FormatInt(until.Unix(), 10), expiration)
+	})
+}
+
+// UserBlacklistedUntil returns the time recorded by BlacklistUser, or the zero time if none
+func (r *RedisClient) UserBlacklistedUntil(ctx context.Context, userID string) (time.Time, error) {
+	return r.getUnixTime(ctx, userBlacklistKey(userID))
+}
+
+// getUnixTime returns the time stored under key in unix seconds, or the zero time if none
+func (r *RedisClient) getUnixTime(ctx context.Context, key string) (time.Time, error) {
+	var unix int64
+	err := r.withFallback(ctx, func() error {
+		var err error
+		unix, err = r.client.Get(ctx, key).Int64()
+		return err
+	}, func(*memoryStore) {})
+	if err != nil && err != redis.Nil {
+		return time.Time{}, err
+	}
+	return unixTime(r.laterLocalUnix(key, unix)), nil
+}
+
+// laterLocalUnix returns the later of unix and a time in unix seconds written to the fallback
+// store under key during an outage
+func (r *RedisClient) laterLocalUnix(key string, unix int64) int64 {
+	if value, ok := r.localGet(key); ok {
+		if local, err := strconv.ParseInt(value, 10, 64); err == nil && local > unix {
+			return local
+		}
+	}
+	return unix
+}
+
+// unixTime returns the time of unix seconds, or the zero time for 0
+func unixTime(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
+
+// TokenRevocation is the revocation state of a token and its user, see TokenRevocation
+type TokenRevocation struct {
+	// Blacklisted reports whether the token was blacklisted with BlacklistToken
+	Blacklisted bool
+	// UserRevokedAt and UserBlacklistedUntil are the times recorded by RevokeUserTokens and
+	// BlacklistUser, or the zero time if none
+	UserRevokedAt        time.Time
+	UserBlacklistedUntil time.Time
+}
+
+// TokenRevocation looks up whether a token is blacklisted and when the tokens of its user were
+// revoked or until when the user is blacklisted, pipelining the lookups into a single round
+// trip instead of one per IsTokenBlacklisted, UserTokensRevokedAt and UserBlacklistedUntil.
+// An empty userID, e.g. for guests, only looks up the token.
+func (r *RedisClient) TokenRevocation(ctx context.Context, tokenID, userID string) (TokenRevocation, error) {
+	var blacklisted, legacyBlacklisted *redis.IntCmd
+	var revokedAt, blacklistedUntil *redis.StringCmd
+	unavailable := false
+	err := r.withFallback(ctx, func() error {
+		// Cluster clients send the commands of each slot to its node, still in one round trip per node
+		cmds, _ := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			blacklisted = pipe.Exists(ctx, tokenBlacklistKey(tokenID))
+			legacyBlacklisted = pipe.Exists(ctx, legacyTokenBlacklistKey(tokenID))
+			if userID != "" {
+				revokedAt = pipe.Get(ctx, userRevocationKey(userID))
+				blacklistedUntil = pipe.Get(ctx, userBlacklistKey(userID))
+			}
+			return nil
+		})
+		// A GET of a missing key fails with redis.Nil, which only means there is no time
+		for _, cmd := range cmds {
+			if err := cmd.Err(); err != nil && err != redis.Nil {
+				return err
+			}
+		}
+		return nil
+	}, func(*memoryStore) {
+		unavailable = true
+	})
 	if err != nil {
-		return false, err
+		return TokenRevocation{}, err
 	}
-	return exists > 0, nil
+
+	var revocation TokenRevocation
+	var revokedAtUnix, blacklistedUntilUnix int64
+	if !unavailable {
+		revocation.Blacklisted = blacklisted.Val() > 0 || legacyBlacklisted.Val() > 0
+		if userID != "" {
+			revokedAtUnix, _ = revokedAt.Int64()
+			blacklistedUntilUnix, _ = blacklistedUntil.Int64()
+		}
+	}
+	if _, ok := r.localGet(tokenBlacklistKey(tokenID)); ok {
+		revocation.Blacklisted = true
+	}
+	if userID != "" {
+		revocation.UserRevokedAt = unixTime(r.laterLocalUnix(userRevocationKey(userID), revokedAtUnix))
+		revocation.UserBlacklistedUntil = unixTime(r.laterLocalUnix(userBlacklistKey(userID), blacklistedUntilUnix))
+	}
+	return revocation, nil
 }
 
 // StoreNonce stores a nonce with an expiration time
-func (r *RedisClient) StoreNonce(nonce string, expiration time.Duration) error {
-	ctx := context.Background()
-	key := fmt.Sprintf("nonce:%s", nonce)
-	return r.client.Set(ctx, key, "1", expiration).Err()
+func (r *RedisClient) StoreNonce(ctx context.Context, nonce string, expiration time.Duration) error {
+	key := nonceKey(nonce)
+	return r.withFallback(ctx, func() error {
+		return r.client.Set(ctx, key, "1", expiration).Err()
+	}, func(s *memoryStore) {
+		s.set(key, "1", expiration)
+	})
 }
 
 // GetNonce checks if a nonce exists
-func (r *RedisClient) GetNonce(nonce string) (bool, error) {
-	ctx := context.Background()
-	key := fmt.Sprintf("nonce:%s", nonce)
-	exists, err := r.client.Exists(ctx, key).Result()
+func (r *RedisClient) GetNonce(ctx context.Context, nonce string) (bool, error) {
+	key := nonceKey(nonce)
+	if _, ok := r.localGet(key); ok {
+		return true, nil
+	}
+	var exists int64
+	err := r.withFallback(ctx, func() error {
+		var err error
+		exists, err = r.client.Exists(ctx, key).Result()
+		return err
+	}, func(*memoryStore) {})
 	if err != nil {
 		return false, err
 	}
@@ -67,13 +475,468 @@ func (r *RedisClient) GetNonce(nonce string) (bool, error) {
 }
 
 // InvalidateNonce removes a nonce
-func (r *RedisClient) InvalidateNonce(nonce string) error {
-	ctx := context.Background()
-	key := fmt.Sprintf("nonce:%s", nonce)
-	return r.client.Del(ctx, key).Err()
+func (r *RedisClient) InvalidateNonce(ctx context.Context, nonce string) error {
+	key := nonceKey(nonce)
+	r.localDelete(key)
+	return r.withFallback(ctx, func() error {
+		return r.client.Del(ctx, key).Err()
+	}, func(*memoryStore) {})
+}
+
+// ConsumeNonce removes a nonce and reports whether it existed, in a single DEL, so checking
+// and invalidating a nonce costs one round trip and concurrent requests cannot both use it
+func (r *RedisClient) ConsumeNonce(ctx context.Context, nonce string) (bool, error) {
+	key := nonceKey(nonce)
+	if _, ok := r.localGet(key); ok {
+		r.localDelete(key)
+		// Redis may hold the nonce too if it was stored before an outage
+		_ = r.withFallback(ctx, func() error {
+			return r.client.Del(ctx, key).Err()
+		}, func(*memoryStore) {})
+		return true, nil
+	}
+	var deleted int64
+	err := r.withFallback(ctx, func() error {
+		var err error
+		deleted, err = r.client.Del(ctx, key).Result()
+		return err
+	}, func(*memoryStore) {})
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// MarkRequestSeen records a signed request for the expiration and reports whether it was
+// seen for the first time. The check and the write are a single SETNX, so concurrent
+// replays cannot both pass.
+func (r *RedisClient) MarkRequestSeen(ctx context.Context, digest string, expiration time.Duration) (bool, error) {
+	key := replayKey(digest)
+	if _, ok := r.localGet(key); ok {
+		return false, nil
+	}
+	var first bool
+	err := r.withFallback(ctx, func() error {
+		var err error
+		first, err = r.client.SetNX(ctx, key, "1", expiration).Result()
+		return err
+	}, func(s *memoryStore) {
+		first = s.setNX(key, "1", expiration)
+	})
+	if err != nil {
+		return false, err
+	}
+	return first, nil
+}
+
+// StoreOTP stores the hash of a one-time code for phone, replacing any pending code
+func (r *RedisClient) StoreOTP(ctx context.Context, phone, codeHash string, expiration time.Duration) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Set(ctx, otpKey(phone), codeHash, expiration).Err()
+	})
+}
+
+// GetOTP returns the pending code hash for phone, or an empty string if there is none
+func (r *RedisClient) GetOTP(ctx context.Context, phone string) (string, error) {
+	var codeHash string
+	err := r.withRetry(ctx, func() error {
+		var err error
+		codeHash, err = r.client.Get(ctx, otpKey(phone)).Result()
+		return err
+	})
+	if err == redis.Nil {
+		return "", nil
+	}
+	return codeHash, err
+}
+
+// DeleteOTP removes the pending code for phone
+func (r *RedisClient) DeleteOTP(ctx context.Context, phone string) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Del(ctx, otpKey(phone)).Err()
+	})
+}
+
+// StoreAuthCode stores the grant of an OAuth authorization code under the code's hash
+func (r *RedisClient) StoreAuthCode(ctx context.Context, codeHash, grant string, expiration time.Duration) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Set(ctx, authCodeKey(codeHash), grant, expiration).Err()
+	})
+}
+
+// TakeAuthCode returns and deletes the grant stored for an authorization code hash, so each
+// code can be redeemed once. It returns an empty string if there is no such code.
+func (r *RedisClient) TakeAuthCode(ctx context.Context, codeHash string) (string, error) {
+	var grant string
+	err := r.withRetry(ctx, func() error {
+		var err error
+		grant, err = r.client.GetDel(ctx, authCodeKey(codeHash)).Result()
+		return err
+	})
+	if err == redis.Nil {
+		return "", nil
+	}
+	return grant, err
+}
+
+// StoreSession stores the claims of an opaque token under the token's hash
+func (r *RedisClient) StoreSession(ctx context.Context, tokenHash, claims string, expiration time.Duration) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Set(ctx, sessionKey(tokenHash), claims, expiration).Err()
+	})
+}
+
+// GetSession returns the claims stored for an opaque token hash, or an empty string if there is no such session
+func (r *RedisClient) GetSession(ctx context.Context, tokenHash string) (string, error) {
+	var claims string
+	err := r.withRetry(ctx, func() error {
+		var err error
+		claims, err = r.client.Get(ctx, sessionKey(tokenHash)).Result()
+		return err
+	})
+	if err == redis.Nil {
+		return "", nil
+	}
+	return claims, err
+}
+
+// DeleteSession removes the session of an opaque token hash
+func (r *RedisClient) DeleteSession(ctx context.Context, tokenHash string) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Del(ctx, sessionKey(tokenHash)).Err()
+	})
+}
+
+// StoreActionToken stores a one-time action token under the token's hash
+func (r *RedisClient) StoreActionToken(ctx context.Context, tokenHash, token string, expiration time.Duration) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Set(ctx, actionTokenKey(tokenHash), token, expiration).Err()
+	})
+}
+
+// GetActionToken returns the action token stored for a hash, or an empty string if there is no such token
+func (r *RedisClient) GetActionToken(ctx context.Context, tokenHash string) (string, error) {
+	var token string
+	err := r.withRetry(ctx, func() error {
+		var err error
+		token, err = r.client.Get(ctx, actionTokenKey(tokenHash)).Result()
+		return err
+	})
+	if err == redis.Nil {
+		return "", nil
+	}
+	return token, err
+}
+
+// TakeActionToken returns and deletes the action token stored for a hash, so each token can
+// be redeemed once. It returns an empty string if there is no such token.
+func (r *RedisClient) TakeActionToken(ctx context.Context, tokenHash string) (string, error) {
+	var token string
+	err := r.withRetry(ctx, func() error {
+		var err error
+		token, err = r.client.GetDel(ctx, actionTokenKey(tokenHash)).Result()
+		return err
+	})
+	if err == redis.Nil {
+		return "", nil
+	}
+	return token, err
+}
+
+// PublishRevocation broadcasts a token revocation event to every instance subscribed with SubscribeRevocations
+func (r *RedisClient) PublishRevocation(ctx context.Context, event string) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Publish(ctx, revocationChannel, event).Err()
+	})
+}
+
+// SubscribeRevocations calls handle with every revocation event published until ctx is done.
+// The subscription reconnects on its own, but events published while it is disconnected are
+// lost, so subscribed is called each time it is established, including the first: callers
+// drop what they cached, as they may have missed revocations of it.
+func (r *RedisClient) SubscribeRevocations(ctx context.Context, handle func(event string), subscribed func()) {
+	r.subscribe(ctx, revocationChannel, handle, subscribed)
+}
+
+// cacheInvalidationChannel returns the pub/sub channel carrying the keys invalidated in the
+// local caches of a namespace
+func cacheInvalidationChannel(namespace string) string {
+	return "cache-invalidations:" + namespace
+}
+
+// PublishCacheInvalidation broadcasts that key changed in the cache namespace to every
+// instance subscribed with SubscribeCacheInvalidations
+func (r *RedisClient) PublishCacheInvalidation(ctx context.Context, namespace, key string) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Publish(ctx, cacheInvalidationChannel(namespace), key).Err()
+	})
+}
+
+// SubscribeCacheInvalidations calls handle with every key invalidated in the cache namespace
+// until ctx is done, and subscribed each time the subscription is established, like
+// SubscribeRevocations
+func (r *RedisClient) SubscribeCacheInvalidations(ctx context.Context, namespace string, handle func(key string), subscribed func()) {
+	r.subscribe(ctx, cacheInvalidationChannel(namespace), handle, subscribed)
+}
+
+// subscribe calls handle with every message published on channel until ctx is done, and
+// subscribed, if set, each time the subscription is established
+func (r *RedisClient) subscribe(ctx context.Context, channel string, handle func(payload string), subscribed func()) {
+	pubsub := r.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	messages := pubsub.ChannelWithSubscriptions()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			switch message := message.(type) {
+			case *redis.Subscription:
+				if message.Kind == "subscribe" && subscribed != nil {
+					subscribed()
+				}
+			case *redis.Message:
+				handle(message.Payload)
+			}
+		}
+	}
+}
+
+// RecordLastSeen stores the time a user was last seen until it is taken by TakeLastSeen.
+// A later time replaces an earlier one.
+func (r *RedisClient) RecordLastSeen(ctx context.Context, userID string, at time.Time) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.HSet(ctx, lastSeenKey, userID, at.Unix()).Err()
+	})
+}
+
+// GetLastSeen returns the pending last-seen time of a user, or the zero time if none is pending
+func (r *RedisClient) GetLastSeen(ctx context.Context, userID string) (time.Time, error) {
+	var seconds int64
+	err := r.withRetry(ctx, func() error {
+		var err error
+		seconds, err = r.client.HGet(ctx, lastSeenKey, userID).Int64()
+		return err
+	})
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// TakeLastSeen returns and removes every pending last-seen time in one transaction, so
+// times recorded concurrently are left for the next call
+func (r *RedisClient) TakeLastSeen(ctx context.Context) (map[string]time.Time, error) {
+	var pending map[string]string
+	err := r.withRetry(ctx, func() error {
+		var get *redis.MapStringStringCmd
+		_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			get = pipe.HGetAll(ctx, lastSeenKey)
+			pipe.Del(ctx, lastSeenKey)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		pending = get.Val()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]time.Time, len(pending))
+	for userID, value := range pending {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		seen[userID] = time.Unix(seconds, 0)
+	}
+	return seen, nil
+}
+
+// StoreCached caches a value under key in a namespace
+func (r *RedisClient) StoreCached(ctx context.Context, namespace, key string, value []byte, expiration time.Duration) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Set(ctx, cacheKey(namespace, key), value, expiration).Err()
+	})
+}
+
+// GetCached returns the value cached under key in a namespace, and whether there is one
+func (r *RedisClient) GetCached(ctx context.Context, namespace, key string) ([]byte, bool, error) {
+	var value []byte
+	err := r.withRetry(ctx, func() error {
+		var err error
+		value, err = r.client.Get(ctx, cacheKey(namespace, key)).Bytes()
+		return err
+	})
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// DeleteCached removes the values cached under keys in a namespace
+func (r *RedisClient) DeleteCached(ctx context.Context, namespace string, keys ...string) error {
+	// Each key lives in its own slot, so they are deleted one at a time
+	for _, key := range keys {
+		err := r.withRetry(ctx, func() error {
+			return r.client.Del(ctx, cacheKey(namespace, key)).Err()
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StoreStats caches computed statistics, encoded by the caller
+func (r *RedisClient) StoreStats(ctx context.Context, key, stats string, expiration time.Duration) error {
+	return r.withRetry(ctx, func() error {
+		return r.client.Set(ctx, statsKey(key), stats, expiration).Err()
+	})
+}
+
+// GetStats returns cached statistics, or an empty string if they are not cached
+func (r *RedisClient) GetStats(ctx context.Context, key string) (string, error) {
+	var stats string
+	err := r.withRetry(ctx, func() error {
+		var err error
+		stats, err = r.client.Get(ctx, statsKey(key)).Result()
+		return err
+	})
+	if err == redis.Nil {
+		return "", nil
+	}
+	return stats, err
+}
+
+// FlushNamespace deletes every key under namespace (one of Namespaces) and returns how many
+// were deleted. Keys are found with SCAN rather than KEYS so Redis is never blocked, and on a
+// cluster every master is scanned.
+func (r *RedisClient) FlushNamespace(ctx context.Context, namespace string) (int64, error) {
+	known := false
+	for _, ns := range Namespaces {
+		known = known || ns == namespace
+	}
+	if !known {
+		return 0, fmt.Errorf("unknown redis namespace: %s", namespace)
+	}
+
+	var deleted atomic.Int64
+	flush := func(ctx context.Context, client redis.UniversalClient) error {
+		iter := client.Scan(ctx, 0, namespace+":*", 500).Iterator()
+		for iter.Next(ctx) {
+			// Keys are deleted one by one: in a cluster they can belong to different slots
+			n, err := client.Del(ctx, iter.Val()).Result()
+			if err != nil {
+				return err
+			}
+			deleted.Add(n)
+		}
+		return iter.Err()
+	}
+
+	var err error
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return flush(ctx, node)
+		})
+	} else {
+		err = flush(ctx, r.client)
+	}
+	return deleted.Load(), err
 }
 
 // Close closes the Redis connection
 func (r *RedisClient) Close() error {
+	untrack(r.client)
 	return r.client.Close()
 }
+
+// withRetry runs op, retrying with exponential backoff while it fails with a transient
+// error. The cluster client already follows MOVED/ASK redirects, but during a resharding
+// storm it can run out of redirects or see TRYAGAIN/CLUSTERDOWN replies. The Sentinel client
+// switches to the new master once the sentinels announce it, which takes a few seconds.
+func (r *RedisClient) withRetry(ctx context.Context, op func() error) error {
+	transient := r.transient
+	if transient == nil {
+		transient = IsClusterTransientError
+	}
+
+	var err error
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		err = op()
+		if err == nil || !transient(err) {
+			return err
+		}
+		if r.retryTimeout > 0 {
+			if time.Since(start) >= r.retryTimeout {
+				return err
+			}
+		} else if attempt >= r.maxRetries {
+			return err
+		}
+
+		backoff := maxRetryBackoff
+		if attempt < 6 {
+			backoff = min(clusterRetryBackoff<<attempt, maxRetryBackoff)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// IsClusterTransientError reports whether err is a Redis Cluster reply that is expected
+// while slots are being migrated and therefore worth retrying
+func IsClusterTransientError(err error) bool {
+	if err == nil || err == redis.Nil {
+		return false
+	}
+	msg := err.Error()
+	for _, prefix := range []string{"MOVED ", "ASK ", "TRYAGAIN", "CLUSTERDOWN", "LOADING"} {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFailoverTransientError reports whether err is expected while Sentinel fails over the
+// master and therefore worth retrying: the old master is unreachable, has been demoted to a
+// replica, or the new one is still loading its data.
+func IsFailoverTransientError(err error) bool {
+	if err == nil || err == redis.Nil {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	// Refused, reset and timed out connections
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := err.Error()
+	for _, prefix := range []string{"READONLY", "LOADING", "MASTERDOWN", "redis: all sentinels"} {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return false
+}
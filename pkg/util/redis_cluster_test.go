@@ -0,0 +1,350 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// clusterSlots is the number of hash slots of a Redis Cluster
+const clusterSlots = 16384
+
+// fakeCluster is an in-process Redis Cluster of two masters speaking just enough RESP for
+// the cluster client: CLUSTER SLOTS, ASKING, and SET, EXISTS and DEL on in-memory data.
+// A slot can be migrated between the nodes the way redis-cli --cluster reshard does, with
+// the ASK, MOVED and TRYAGAIN replies real nodes send meanwhile.
+type fakeCluster struct {
+	mu        sync.Mutex
+	nodes     []*fakeClusterNode
+	owners    map[int]int // slots moved away from their initial node
+	importing map[int]int // slots being migrated, to the node importing them
+	tryAgain  int         // TRYAGAIN replies still to send for migrating slots
+	replies   map[string]int
+}
+
+type fakeClusterNode struct {
+	id   string
+	addr string
+	data map[string]string
+}
+
+func newFakeCluster(t *testing.T) *fakeCluster {
+	t.Helper()
+	c := &fakeCluster{owners: make(map[int]int), importing: make(map[int]int), replies: make(map[string]int)}
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		node := &fakeClusterNode{
+			id:   fmt.Sprintf("%040d", i),
+			addr: listener.Addr().String(),
+			data: make(map[string]string),
+		}
+		c.nodes = append(c.nodes, node)
+		go c.serve(i, listener)
+		t.Cleanup(func() { listener.Close() })
+	}
+	return c
+}
+
+func (c *fakeCluster) addrs() []string {
+	return []string{c.nodes[0].addr, c.nodes[1].addr}
+}
+
+// owner returns the node serving slot; the first node initially serves the lower half
+func (c *fakeCluster) owner(slot int) int {
+	if node, ok := c.owners[slot]; ok {
+		return node
+	}
+	if slot < clusterSlots/2 {
+		return 0
+	}
+	return 1
+}
+
+// startMigration marks slot as migrating from its owner to the other node and returns both
+func (c *fakeCluster) startMigration(slot int) (source, target int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	source = c.owner(slot)
+	target = 1 - source
+	c.importing[slot] = target
+	return source, target
+}
+
+// migrateKey moves a key from source to target, like MIGRATE
+func (c *fakeCluster) migrateKey(key string, source, target int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value, ok := c.nodes[source].data[key]; ok {
+		c.nodes[target].data[key] = value
+		delete(c.nodes[source].data, key)
+	}
+}
+
+// finishMigration hands slot over to the node importing it, like CLUSTER SETSLOT NODE
+func (c *fakeCluster) finishMigration(slot int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.owners[slot] = c.importing[slot]
+	delete(c.importing, slot)
+}
+
+func (c *fakeCluster) serve(node int, listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go c.handle(node, conn)
+	}
+}
+
+func (c *fakeCluster) handle(node int, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	asking := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		reply := c.execute(node, args, asking)
+		asking = strings.EqualFold(args[0], "ASKING")
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// execute runs a command on node and returns the RESP reply
+func (c *fakeCluster) execute(node int, args []string, asking bool) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "ASKING", "READONLY", "CLIENT":
+		return "+OK\r\n"
+	case "COMMAND":
+		return "*0\r\n"
+	case "CLUSTER":
+		if len(args) > 1 && strings.EqualFold(args[1], "SLOTS") {
+			return c.slotsReply()
+		}
+	case "SET", "EXISTS", "DEL":
+		return c.executeKey(node, args, asking)
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+// executeKey runs a single-key command, redirecting it like a real node would
+func (c *fakeCluster) executeKey(node int, args []string, asking bool) string {
+	key := args[1]
+	slot := keySlot(key)
+	owner := c.owner(slot)
+	importer, migrating := c.importing[slot]
+
+	if migrating && c.tryAgain > 0 {
+		c.tryAgain--
+		c.replies["TRYAGAIN"]++
+		return "-TRYAGAIN Multiple keys request during rehashing of slot\r\n"
+	}
+	data := c.nodes[node].data
+	switch {
+	case node == owner:
+		// Keys already moved are looked up on the importing node
+		if _, ok := data[key]; migrating && !ok {
+			c.replies["ASK"]++
+			return fmt.Sprintf("-ASK %d %s\r\n", slot, c.nodes[importer].addr)
+		}
+	case migrating && node == importer && asking:
+	default:
+		c.replies["MOVED"]++
+		return fmt.Sprintf("-MOVED %d %s\r\n", slot, c.nodes[owner].addr)
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "SET":
+		data[key] = args[2]
+		return "+OK\r\n"
+	case "EXISTS":
+		if _, ok := data[key]; ok {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	default:
+		if _, ok := data[key]; ok {
+			delete(data, key)
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	}
+}
+
+// slotsReply answers CLUSTER SLOTS with the ranges of slots each node serves
+func (c *fakeCluster) slotsReply() string {
+	var ranges []string
+	start := 0
+	for slot := 1; slot <= clusterSlots; slot++ {
+		if slot < clusterSlots && c.owner(slot) == c.owner(start) {
+			continue
+		}
+		node := c.nodes[c.owner(start)]
+		host, port, _ := net.SplitHostPort(node.addr)
+		ranges = append(ranges, fmt.Sprintf("*3\r\n:%d\r\n:%d\r\n*3\r\n$%d\r\n%s\r\n:%s\r\n$%d\r\n%s\r\n",
+			start, slot-1, len(host), host, port, len(node.id), node.id))
+		start = slot
+	}
+	return fmt.Sprintf("*%d\r\n%s", len(ranges), strings.Join(ranges, ""))
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid command header %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk string header %q", line)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// keySlot returns the cluster slot of a key: the CRC16 of its hash tag, if any, modulo 16384
+func keySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	var crc uint16
+	for i := 0; i < len(key); i++ {
+		crc ^= uint16(key[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return int(crc) % clusterSlots
+}
+
+func TestKeySlot(t *testing.T) {
+	// Values from the Redis Cluster specification and CLUSTER KEYSLOT
+	tests := map[string]int{
+		"123456789":            12739,
+		"foo":                  12182,
+		"{user1000}.following": keySlot("user1000"),
+	}
+	for key, want := range tests {
+		if got := keySlot(key); got != want {
+			t.Errorf("keySlot(%q) = %d, want %d", key, got, want)
+		}
+	}
+}
+
+// TestClusterResharding moves the slot holding a nonce and a blacklist entry between the two
+// masters of an in-process cluster while using them, and answers a burst of TRYAGAIN replies
+// longer than the cluster client follows on its own. TestClusterSlotMigration does the same
+// against a real cluster.
+func TestClusterResharding(t *testing.T) {
+	cluster := newFakeCluster(t)
+	client, err := NewRedisClusterClientWithOptions(RedisClusterOptions{
+		Addrs: cluster.addrs(),
+		Conn:  RedisConnOptions{DialTimeout: time.Second, ReadTimeout: time.Second, WriteTimeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to cluster: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	id := uuid.New().String()
+	if err := client.StoreNonce(ctx, id, time.Minute); err != nil {
+		t.Fatalf("StoreNonce: %v", err)
+	}
+	if err := client.BlacklistToken(ctx, id, time.Minute); err != nil {
+		t.Fatalf("BlacklistToken: %v", err)
+	}
+	slot := keySlot(nonceKey(id))
+	if keySlot(tokenBlacklistKey(id)) != slot {
+		t.Fatalf("nonce and blacklist keys are in different slots")
+	}
+
+	assertState := func(stage string) {
+		t.Helper()
+		exists, err := client.GetNonce(ctx, id)
+		if err != nil || !exists {
+			t.Fatalf("%s: GetNonce = %v, %v", stage, exists, err)
+		}
+		blacklisted, err := client.IsTokenBlacklisted(ctx, id)
+		if err != nil || !blacklisted {
+			t.Fatalf("%s: IsTokenBlacklisted = %v, %v", stage, blacklisted, err)
+		}
+	}
+
+	source, target := cluster.startMigration(slot)
+	assertState("migration started")
+	for _, key := range []string{nonceKey(id), tokenBlacklistKey(id)} {
+		cluster.migrateKey(key, source, target)
+		assertState("key " + key + " migrated")
+	}
+	cluster.finishMigration(slot)
+	assertState("migration finished")
+
+	// The next migration starts with more TRYAGAIN replies than the client retries itself
+	cluster.startMigration(slot)
+	cluster.mu.Lock()
+	cluster.tryAgain = 10
+	cluster.mu.Unlock()
+	assertState("TRYAGAIN burst")
+
+	if err := client.InvalidateNonce(ctx, id); err != nil {
+		t.Fatalf("InvalidateNonce: %v", err)
+	}
+	if exists, err := client.GetNonce(ctx, id); err != nil || exists {
+		t.Fatalf("nonce still present after invalidation: %v, %v", exists, err)
+	}
+
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	for _, reply := range []string{"ASK", "MOVED", "TRYAGAIN"} {
+		if cluster.replies[reply] == 0 {
+			t.Errorf("the cluster never replied %s, replies = %v", reply, cluster.replies)
+		}
+	}
+	if cluster.tryAgain != 0 {
+		t.Errorf("%d TRYAGAIN replies were left unused", cluster.tryAgain)
+	}
+}
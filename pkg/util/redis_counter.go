@@ -0,0 +1,90 @@
+package util
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fixedWindowScript increments the counter KEYS[1] and returns the new count and its
+// remaining time to live in milliseconds. The first increment opens a window of ARGV[1]
+// milliseconds, as does the first one after a counter was left without an expiry.
+var fixedWindowScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// slidingWindowScript increments the counter of the current window KEYS[1], which expires
+// after ARGV[1] milliseconds, and returns its new count and the count of the previous window
+// KEYS[2]
+var slidingWindowScript = redis.NewScript(`
+local current = redis.call('INCR', KEYS[1])
+if current == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+return {current, previous}
+`)
+
+// IncrementCounter increments a fixed-window counter and returns the new count
+// together with the time remaining until the window resets. The counter is incremented and
+// its window opened in a single atomic round trip.
+func (r *RedisClient) IncrementCounter(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	var result []int64
+	err := r.withRetry(ctx, func() error {
+		var err error
+		result, err = fixedWindowScript.Run(ctx, r.client, []string{rateLimitKey(key)}, windowMillis(window)).Int64Slice()
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return result[0], time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// IncrementSlidingWindow counts a hit against a sliding window of the given length and
+// returns the number of hits within the last window, including this one, and the time
+// until the current fixed window ends. Unlike IncrementCounter, which lets a client spend
+// its limit twice around a window boundary, it weights the count of the previous fixed
+// window by how much of it still overlaps the sliding window. Two counters per key are
+// kept, whatever the number of hits; the count is an estimate that assumes the hits of
+// the previous window were spread evenly.
+func (r *RedisClient) IncrementSlidingWindow(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	length := windowMillis(window)
+	now := time.Now().UnixMilli()
+	index, elapsed := now/length, now%length
+
+	var result []int64
+	err := r.withRetry(ctx, func() error {
+		var err error
+		keys := []string{slidingWindowKey(key, index), slidingWindowKey(key, index-1)}
+		// The counter of the current window is still read as the previous one during the next
+		result, err = slidingWindowScript.Run(ctx, r.client, keys, 2*length).Int64Slice()
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return SlidingWindowCount(result[0], result[1], elapsed, length), time.Duration(length-elapsed) * time.Millisecond, nil
+}
+
+// SlidingWindowCount estimates the hits within a sliding window from the count of the
+// current fixed window, the count of the previous one and how far into the current window
+// the sliding window ends, both in the same unit as length
+func SlidingWindowCount(current, previous, elapsed, length int64) int64 {
+	return current + int64(float64(previous)*float64(length-elapsed)/float64(length))
+}
+
+// windowMillis returns a window in milliseconds, at least one
+func windowMillis(window time.Duration) int64 {
+	if ms := window.Milliseconds(); ms > 0 {
+		return ms
+	}
+	return 1
+}
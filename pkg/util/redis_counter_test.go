@@ -0,0 +1,55 @@
+package util
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSlidingWindowCount(t *testing.T) {
+	cases := []struct {
+		current, previous, elapsed, want int64
+	}{
+		{1, 0, 0, 1},
+		{1, 10, 0, 11},
+		{1, 10, 25, 8},
+		{3, 10, 50, 8},
+		{3, 10, 99, 3},
+	}
+	for _, c := range cases {
+		if got := SlidingWindowCount(c.current, c.previous, c.elapsed, 100); got != c.want {
+			t.Errorf("SlidingWindowCount(%d, %d, %d, 100) = %d, want %d", c.current, c.previous, c.elapsed, got, c.want)
+		}
+	}
+}
+
+// TestCounters runs against the Redis server at REDIS_ADDR
+func TestCounters(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set, skipping Redis counter test")
+	}
+	r := &RedisClient{client: redis.NewClient(&redis.Options{Addr: addr, Password: os.Getenv("REDIS_PASSWORD")})}
+	defer r.Close()
+	ctx := context.Background()
+
+	key := uuid.New().String()
+	for want := int64(1); want <= 3; want++ {
+		count, resetIn, err := r.IncrementCounter(ctx, key, time.Minute)
+		if err != nil || count != want || resetIn <= 0 || resetIn > time.Minute {
+			t.Fatalf("IncrementCounter = %d, %v, %v, want %d within a minute", count, resetIn, err, want)
+		}
+	}
+
+	for want := int64(1); want <= 3; want++ {
+		count, resetIn, err := r.IncrementSlidingWindow(ctx, key, time.Minute)
+		// A window boundary passed during the loop only makes the earlier hits count as the previous window's
+		if err != nil || count < want-1 || count > want || resetIn <= 0 || resetIn > time.Minute {
+			t.Fatalf("IncrementSlidingWindow = %d, %v, %v, want %d within a minute", count, resetIn, err, want)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package util
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultFallbackEntries bounds the fallback store when no size is given
+const defaultFallbackEntries = 100000
+
+// fallbackMetrics are published as the expvar map "redis.fallback"
+var fallbackMetrics = struct {
+	degraded  *expvar.Int
+	outages   *expvar.Int
+	ops       *expvar.Int
+	entries   *expvar.Int
+	evictions *expvar.Int
+}{
+	degraded:  new(expvar.Int),
+	outages:   new(expvar.Int),
+	ops:       new(expvar.Int),
+	entries:   new(expvar.Int),
+	evictions: new(expvar.Int),
+}
+
+func init() {
+	vars := new(expvar.Map).Init()
+	vars.Set("degraded", fallbackMetrics.degraded)
+	vars.Set("outages", fallbackMetrics.outages)
+	vars.Set("ops", fallbackMetrics.ops)
+	vars.Set("entries", fallbackMetrics.entries)
+	vars.Set("evictions", fallbackMetrics.evictions)
+	expvar.Publish("redis.fallback", vars)
+}
+
+// EnableFallback keeps nonces, replay markers and token revocations in a bounded in-process
+// store while Redis is unavailable, instead of failing the requests that need them. It is
+// meant for development and for riding out outages: entries written during an outage are
+// only known to this instance and are not copied to Redis when it comes back, so they are
+// checked alongside Redis until they expire. maxEntries bounds the store, 100000 if zero;
+// when it is full the entries closest to expiring are dropped first.
+func (r *RedisClient) EnableFallback(maxEntries int) {
+	if maxEntries <= 0 {
+		maxEntries = defaultFallbackEntries
+	}
+	r.fallback = &redisFallback{store: newMemoryStore(maxEntries)}
+}
+
+// Degraded reports whether the last Redis operation with a fallback failed, so nonces and
+// token revocations are currently kept in process memory
+func (r *RedisClient) Degraded() bool {
+	return r.fallback != nil && r.fallback.degraded.Load()
+}
+
+// redisFallback is the in-process store used while Redis is unavailable and the state of the outage
+type redisFallback struct {
+	store    *memoryStore
+	degraded atomic.Bool
+}
+
+// withFallback runs op against Redis. If Redis is unavailable and a fallback is enabled,
+// local runs against the fallback store instead.
+func (r *RedisClient) withFallback(ctx context.Context, op func() error, local func(*memoryStore)) error {
+	err := r.withRetry(ctx, op)
+	f := r.fallback
+	if f == nil {
+		return err
+	}
+	if err == nil || errors.Is(err, redis.Nil) {
+		f.markAvailable()
+		return err
+	}
+	// A cancelled request says nothing about Redis
+	if ctx.Err() != nil || !IsUnavailableError(err) {
+		return err
+	}
+	f.markUnavailable(err)
+	fallbackMetrics.ops.Add(1)
+	local(f.store)
+	return nil
+}
+
+// localGet returns the value the fallback store holds under key, if enabled. Lookups use it
+// even while Redis is available, so entries written during an outage keep applying until
+// they expire.
+func (r *RedisClient) localGet(key string) (string, bool) {
+	if r.fallback == nil {
+		return "", false
+	}
+	return r.fallback.store.get(key)
+}
+
+// localDelete removes key from the fallback store, if enabled
+func (r *RedisClient) localDelete(key string) {
+	if r.fallback != nil {
+		r.fallback.store.delete(key)
+	}
+}
+
+// markUnavailable records that Redis is unavailable, logging the start of an outage once
+func (f *redisFallback) markUnavailable(err error) {
+	if f.degraded.CompareAndSwap(false, true) {
+		fallbackMetrics.degraded.Set(1)
+		fallbackMetrics.outages.Add(1)
+		logger.Warnf("Redis is unavailable, keeping nonces and token revocations in process memory: %v", err)
+	}
+}
+
+// markAvailable records that Redis answered again, logging the end of an outage once
+func (f *redisFallback) markAvailable() {
+	if f.degraded.CompareAndSwap(true, false) {
+		fallbackMetrics.degraded.Set(0)
+		logger.Infof("Redis is available again; %d entries written during the outage stay in process memory until they expire", f.store.len())
+	}
+}
+
+// IsUnavailableError reports whether err means Redis could not be reached or cannot serve
+// requests right now, as opposed to rejecting the command
+func IsUnavailableError(err error) bool {
+	if err == nil || err == redis.Nil {
+		return false
+	}
+	// Every pooled connection is stuck waiting on an unresponsive server
+	if err.Error() == "redis: connection pool timeout" {
+		return true
+	}
+	return IsFailoverTransientError(err) || IsClusterTransientError(err)
+}
+
+// memoryStore is a bounded in-process key-value store with TTLs
+type memoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*memoryEntry
+	// byExpiry orders the entries by expiry, so expired and evicted entries come first
+	byExpiry expiryHeap
+	// now returns the current time; tests replace it to expire entries
+	now func() time.Time
+}
+
+// memoryEntry is a value and the time it expires
+type memoryEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+	// index is the position in byExpiry
+	index int
+}
+
+// newMemoryStore creates an empty store holding up to maxEntries entries
+func newMemoryStore(maxEntries int) *memoryStore {
+	return &memoryStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*memoryEntry),
+		now:        time.Now,
+	}
+}
+
+// set stores value under key for ttl, replacing any value
+func (s *memoryStore) set(key, value string, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(key, value, ttl)
+}
+
+// setNX stores value under key for ttl unless the key is already set, and reports whether it stored it
+func (s *memoryStore) setNX(key, value string, ttl time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.lookup(key); ok {
+		return false
+	}
+	s.put(key, value, ttl)
+	return true
+}
+
+// get returns the value stored under key, if it has not expired
+func (s *memoryStore) get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.lookup(key)
+	if !ok {
+		return "", false
+	}
+	return e.value, true
+}
+
+// delete removes key
+func (s *memoryStore) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok {
+		s.remove(e)
+	}
+}
+
+// len returns the number of entries, including expired ones not yet dropped
+func (s *memoryStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// lookup returns the entry under key, dropping it if it has expired. Callers hold mu.
+func (s *memoryStore) lookup(key string) (*memoryEntry, bool) {
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !s.now().Before(e.expiresAt) {
+		s.remove(e)
+		return nil, false
+	}
+	return e, true
+}
+
+// put stores an entry, making room for it first. Callers hold mu.
+func (s *memoryStore) put(key, value string, ttl time.Duration) {
+	now := s.now()
+	if e, ok := s.entries[key]; ok {
+		e.value = value
+		e.expiresAt = now.Add(ttl)
+		heap.Fix(&s.byExpiry, e.index)
+		return
+	}
+	for len(s.byExpiry) > 0 && (len(s.entries) >= s.maxEntries || !now.Before(s.byExpiry[0].expiresAt)) {
+		if now.Before(s.byExpiry[0].expiresAt) {
+			fallbackMetrics.evictions.Add(1)
+		}
+		s.remove(s.byExpiry[0])
+	}
+	e := &memoryEntry{key: key, value: value, expiresAt: now.Add(ttl)}
+	s.entries[key] = e
+	heap.Push(&s.byExpiry, e)
+	fallbackMetrics.entries.Add(1)
+}
+
+// remove drops an entry. Callers hold mu.
+func (s *memoryStore) remove(e *memoryEntry) {
+	heap.Remove(&s.byExpiry, e.index)
+	delete(s.entries, e.key)
+	fallbackMetrics.entries.Add(-1)
+}
+
+// expiryHeap is a min-heap of entries by expiry, implementing heap.Interface
+type expiryHeap []*memoryEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x any) {
+	e := x.(*memoryEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
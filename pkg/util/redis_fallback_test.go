@@ -0,0 +1,167 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMemoryStoreExpiresAndEvicts(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newMemoryStore(2)
+	s.now = func() time.Time { return now }
+
+	s.set("a", "1", time.Minute)
+	s.set("b", "2", time.Hour)
+	if !s.setNX("c", "3", 2*time.Hour) {
+		t.Fatal("setNX of a new key should store it")
+	}
+	// The store is full, so the entry closest to expiring made room
+	if _, ok := s.get("a"); ok {
+		t.Fatal("the entry closest to expiring should have been evicted")
+	}
+	if v, ok := s.get("b"); !ok || v != "2" {
+		t.Fatalf("got %q, %v, want the entry with the longer TTL kept", v, ok)
+	}
+	if s.setNX("c", "4", time.Hour) {
+		t.Fatal("setNX of an existing key should not replace it")
+	}
+
+	now = now.Add(90 * time.Minute)
+	if _, ok := s.get("b"); ok {
+		t.Fatal("expired entries should not be returned")
+	}
+	if !s.setNX("b", "5", time.Hour) {
+		t.Fatal("setNX should store a key whose entry expired")
+	}
+	s.delete("c")
+	if _, ok := s.get("c"); ok {
+		t.Fatal("deleted entries should not be returned")
+	}
+	if got := s.len(); got != 1 {
+		t.Fatalf("len() = %d, want 1", got)
+	}
+}
+
+// unreachableRedisClient returns a client of a Redis server that refuses connections
+func unreachableRedisClient(t *testing.T) *RedisClient {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1, DialTimeout: time.Second})
+	t.Cleanup(func() { client.Close() })
+	return &RedisClient{client: client}
+}
+
+func TestFallbackWhileRedisIsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	r := unreachableRedisClient(t)
+
+	if err := r.BlacklistToken(ctx, "t1", time.Minute); err == nil {
+		t.Fatal("without a fallback, operations should fail while Redis is unavailable")
+	}
+
+	r.EnableFallback(0)
+	if err := r.BlacklistToken(ctx, "t1", time.Minute); err != nil {
+		t.Fatalf("BlacklistToken: %v", err)
+	}
+	if !r.Degraded() {
+		t.Fatal("the client should report running degraded")
+	}
+	if blacklisted, err := r.IsTokenBlacklisted(ctx, "t1"); err != nil || !blacklisted {
+		t.Fatalf("IsTokenBlacklisted = %v, %v, want true", blacklisted, err)
+	}
+	if blacklisted, err := r.IsTokenBlacklisted(ctx, "t2"); err != nil || blacklisted {
+		t.Fatalf("IsTokenBlacklisted of another token = %v, %v, want false", blacklisted, err)
+	}
+
+	revokedAt := time.Unix(time.Now().Unix(), 0)
+	if err := r.RevokeUserTokens(ctx, "u1", revokedAt, time.Hour); err != nil {
+		t.Fatalf("RevokeUserTokens: %v", err)
+	}
+	if got, err := r.UserTokensRevokedAt(ctx, "u1"); err != nil || !got.Equal(revokedAt) {
+		t.Fatalf("UserTokensRevokedAt = %v, %v, want %v", got, err, revokedAt)
+	}
+	until := revokedAt.Add(time.Hour)
+	if err := r.BlacklistUser(ctx, "u1", until); err != nil {
+		t.Fatalf("BlacklistUser: %v", err)
+	}
+	if got, _ := r.UserBlacklistedUntil(ctx, "u1"); !got.Equal(until) {
+		t.Fatalf("UserBlacklistedUntil = %v, want %v", got, until)
+	}
+	revocation, err := r.TokenRevocation(ctx, "t1", "u1")
+	if err != nil || !revocation.Blacklisted || !revocation.UserRevokedAt.Equal(revokedAt) || !revocation.UserBlacklistedUntil.Equal(until) {
+		t.Fatalf("TokenRevocation = %+v, %v, want the stored revocations", revocation, err)
+	}
+	if err := r.BlacklistUser(ctx, "u1", time.Time{}); err != nil {
+		t.Fatalf("lifting BlacklistUser: %v", err)
+	}
+	if got, _ := r.UserBlacklistedUntil(ctx, "u1"); !got.IsZero() {
+		t.Fatalf("UserBlacklistedUntil after lifting = %v, want zero", got)
+	}
+
+	if err := r.StoreNonce(ctx, "n1", time.Minute); err != nil {
+		t.Fatalf("StoreNonce: %v", err)
+	}
+	if found, err := r.GetNonce(ctx, "n1"); err != nil || !found {
+		t.Fatalf("GetNonce = %v, %v, want true", found, err)
+	}
+	if err := r.InvalidateNonce(ctx, "n1"); err != nil {
+		t.Fatalf("InvalidateNonce: %v", err)
+	}
+	if found, _ := r.GetNonce(ctx, "n1"); found {
+		t.Fatal("an invalidated nonce should not be found")
+	}
+
+	if err := r.StoreNonce(ctx, "n3", time.Minute); err != nil {
+		t.Fatalf("StoreNonce: %v", err)
+	}
+	if consumed, err := r.ConsumeNonce(ctx, "n3"); err != nil || !consumed {
+		t.Fatalf("ConsumeNonce = %v, %v, want true", consumed, err)
+	}
+	if consumed, _ := r.ConsumeNonce(ctx, "n3"); consumed {
+		t.Fatal("a nonce should only be consumed once")
+	}
+
+	if first, err := r.MarkRequestSeen(ctx, "d1", time.Minute); err != nil || !first {
+		t.Fatalf("MarkRequestSeen = %v, %v, want first", first, err)
+	}
+	if first, _ := r.MarkRequestSeen(ctx, "d1", time.Minute); first {
+		t.Fatal("a replayed request should be reported as seen")
+	}
+
+	// Cancelled requests fail instead of falling back
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := r.StoreNonce(cancelled, "n2", time.Minute); err == nil {
+		t.Fatal("a cancelled request should not fall back")
+	}
+}
+
+func TestIsUnavailableError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{redis.Nil, false},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{errors.New("redis: connection pool timeout"), true},
+		{errors.New("LOADING Redis is loading the dataset in memory"), true},
+		{errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"), false},
+	}
+	for _, c := range cases {
+		if got := IsUnavailableError(c.err); got != c.want {
+			t.Errorf("IsUnavailableError(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package util
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/gin-pkg/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrLockHeld is returned by TryLock when another owner holds the lock
+	ErrLockHeld = errors.New("redis: lock is held by another owner")
+	// ErrLockLost is returned by Unlock when the lock expired or was taken over before it was
+	// released, so the work done under it may have overlapped with another owner's
+	ErrLockLost = errors.New("redis: lock was lost before it was released")
+)
+
+// renewLockScript extends the lock KEYS[1] to ARGV[2] milliseconds if it is still held with
+// the token ARGV[1], and returns 1 if it was
+var renewLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLockScript deletes the lock KEYS[1] if it is still held with the token ARGV[1], and
+// returns 1 if it was
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock is a lock held in a single Redis deployment with SET NX PX, see TryLock. It is renewed
+// every third of its TTL until it is released, so it outlives work of any length while its
+// owner is alive, and expires within one TTL of the owner dying.
+type Lock struct {
+	client *RedisClient
+	key    string
+	token  string
+	ttl    time.Duration
+
+	// ctx is cancelled once the lock is lost, released or stopped
+	ctx    context.Context
+	cancel context.CancelFunc
+	// done is closed when the renewal stops
+	done chan struct{}
+
+	mu   sync.Mutex
+	lost bool
+}
+
+// lockKey returns the key of a lock
+func lockKey(name string) string {
+	return fmt.Sprintf("lock:{%s}", name)
+}
+
+// TryLock acquires the lock name for ttl, or returns ErrLockHeld if another owner holds it.
+// The lock is renewed until Unlock or Stop is called or ctx is cancelled; the context of the
+// returned lock is cancelled as soon as the lock can no longer be guaranteed, and work done
+// under the lock should stop then. Redis failovers can lose locks, as replication is
+// asynchronous, so locks suit avoiding duplicate work rather than guarding correctness.
+func (r *RedisClient) TryLock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	l := &Lock{client: r, key: lockKey(name), token: hex.EncodeToString(token), ttl: ttl, done: make(chan struct{})}
+
+	var acquired bool
+	err := r.withRetry(ctx, func() error {
+		var err error
+		acquired, err = r.client.SetNX(ctx, l.key, l.token, ttl).Result()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrLockHeld
+	}
+
+	l.ctx, l.cancel = context.WithCancel(ctx)
+	go l.renew()
+	return l, nil
+}
+
+// Lock acquires the lock name for ttl like TryLock, waiting until the current owner releases
+// it or it expires, or ctx is cancelled
+func (r *RedisClient) Lock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	// Waiters poll, as released locks are not announced
+	retry := min(max(ttl/10, 50*time.Millisecond), time.Second)
+	for {
+		l, err := r.TryLock(ctx, name, ttl)
+		if !errors.Is(err, ErrLockHeld) {
+			return l, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+// Context returns a context that is cancelled once the lock is lost, released or stopped
+func (l *Lock) Context() context.Context {
+	return l.ctx
+}
+
+// Unlock stops renewing the lock and releases it. It returns ErrLockLost if the lock was lost
+// before, e.g. because renewing failed for a whole TTL.
+func (l *Lock) Unlock(ctx context.Context) error {
+	l.Stop()
+	var released int64
+	err := l.client.withRetry(ctx, func() error {
+		var err error
+		released, err = releaseLockScript.Run(ctx, l.client.client, []string{l.key}, l.token).Int64()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if released == 0 || l.isLost() {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Stop stops renewing the lock without releasing it, so it is held until its TTL expires.
+// It suits claims that should outlast the work, such as a job run once per period.
+func (l *Lock) Stop() {
+	l.cancel()
+	<-l.done
+}
+
+// renew extends the lock every third of its TTL until the lock is stopped. If it cannot
+// renew the lock for a whole TTL, or finds it taken over, the lock is lost.
+func (l *Lock) renew() {
+	defer close(l.done)
+	ticker := time.NewTicker(max(l.ttl/3, time.Millisecond))
+	defer ticker.Stop()
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Each attempt gets the rest of the TTL at most, after which the lock is gone anyway
+		ctx, cancel := context.WithDeadline(l.ctx, renewed.Add(l.ttl))
+		held, err := renewLockScript.Run(ctx, l.client.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int64()
+		cancel()
+		switch {
+		case l.ctx.Err() != nil:
+			return
+		case err == nil && held == 1:
+			renewed = time.Now()
+			continue
+		case err == nil:
+			logger.Warnf("Lock %s was taken over by another owner", l.key)
+		case time.Since(renewed) < l.ttl:
+			logger.Warnf("Failed to renew lock %s, retrying: %v", l.key, err)
+			continue
+		default:
+			logger.Warnf("Lock %s expired while it could not be renewed: %v", l.key, err)
+		}
+		l.mu.Lock()
+		l.lost = true
+		l.mu.Unlock()
+		l.cancel()
+		return
+	}
+}
+
+// isLost reports whether renewing found the lock lost
+func (l *Lock) isLost() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lost
+}
@@ -0,0 +1,88 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestTryLockWhileRedisIsUnavailable(t *testing.T) {
+	r := unreachableRedisClient(t)
+	// The fallback store is local to the process, so locks never use it
+	r.EnableFallback(0)
+	if _, err := r.TryLock(context.Background(), "job", time.Second); err == nil || errors.Is(err, ErrLockHeld) {
+		t.Fatalf("TryLock = %v, want the connection error", err)
+	}
+}
+
+// TestLock runs against the Redis server at REDIS_ADDR
+func TestLock(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set, skipping Redis lock test")
+	}
+	r := &RedisClient{client: redis.NewClient(&redis.Options{Addr: addr, Password: os.Getenv("REDIS_PASSWORD")})}
+	defer r.Close()
+	ctx := context.Background()
+	name := uuid.New().String()
+
+	l, err := r.TryLock(ctx, name, 300*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	if _, err := r.TryLock(ctx, name, time.Second); !errors.Is(err, ErrLockHeld) {
+		t.Fatalf("TryLock of a held lock = %v, want ErrLockHeld", err)
+	}
+	// Renewal keeps the lock past its TTL
+	time.Sleep(time.Second)
+	if _, err := r.TryLock(ctx, name, time.Second); !errors.Is(err, ErrLockHeld) {
+		t.Fatalf("TryLock after the TTL = %v, want the renewed lock held", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		waiter, err := r.Lock(ctx, name, time.Second)
+		if err == nil {
+			err = waiter.Unlock(ctx)
+		}
+		acquired <- err
+	}()
+	if err := l.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if l.Context().Err() == nil {
+		t.Error("the context of a released lock should be cancelled")
+	}
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("Lock after release: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Lock did not acquire the released lock")
+	}
+
+	// Another owner taking over a stopped lock makes the first one lost
+	l, _ = r.TryLock(ctx, name, 100*time.Millisecond)
+	l.Stop()
+	time.Sleep(200 * time.Millisecond)
+	other, err := r.TryLock(ctx, name, time.Second)
+	if err != nil {
+		t.Fatalf("TryLock of an expired lock: %v", err)
+	}
+	defer other.Unlock(ctx)
+	if err := l.Unlock(ctx); !errors.Is(err, ErrLockLost) {
+		t.Fatalf("Unlock of a lost lock = %v, want ErrLockLost", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := r.Lock(cancelled, name, time.Second); err == nil {
+		t.Fatal("Lock with a cancelled context should fail")
+	}
+}
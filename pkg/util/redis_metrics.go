@@ -0,0 +1,203 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// latencyBuckets are the upper bounds of the command latency histogram buckets
+var latencyBuckets = []time.Duration{
+	500 * time.Microsecond,
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// redisMetrics are published as the expvar map "redis": the connection pool stats of every
+// open client, a latency histogram and error count per command, and dial errors
+var redisMetrics = struct {
+	vars       *expvar.Map
+	commands   *expvar.Map
+	dialErrors *expvar.Int
+
+	mu      sync.Mutex
+	clients map[redis.UniversalClient]struct{}
+}{
+	vars:       new(expvar.Map).Init(),
+	commands:   new(expvar.Map).Init(),
+	dialErrors: new(expvar.Int),
+	clients:    make(map[redis.UniversalClient]struct{}),
+}
+
+func init() {
+	redisMetrics.vars.Set("pool", expvar.Func(poolStats))
+	redisMetrics.vars.Set("commands", redisMetrics.commands)
+	redisMetrics.vars.Set("dial_errors", redisMetrics.dialErrors)
+	expvar.Publish("redis", redisMetrics.vars)
+}
+
+// commandMetrics is the latency histogram and error count of a command. It is published as
+// JSON with cumulative bucket counts, e.g. {"count":3,"errors":0,"sum_ms":1.7,"le_1ms":2,...}.
+type commandMetrics struct {
+	buckets []atomic.Int64
+	count   atomic.Int64
+	errors  atomic.Int64
+	sum     atomic.Int64
+}
+
+func newCommandMetrics() *commandMetrics {
+	// The last bucket counts latencies above the largest bound
+	return &commandMetrics{buckets: make([]atomic.Int64, len(latencyBuckets)+1)}
+}
+
+// observe records a command that took latency, and failed if failed is set
+func (m *commandMetrics) observe(latency time.Duration, failed bool) {
+	i := len(latencyBuckets)
+	for j, bound := range latencyBuckets {
+		if latency <= bound {
+			i = j
+			break
+		}
+	}
+	m.buckets[i].Add(1)
+	m.count.Add(1)
+	m.sum.Add(int64(latency))
+	if failed {
+		m.errors.Add(1)
+	}
+}
+
+func (m *commandMetrics) String() string {
+	out := map[string]any{
+		"count":  m.count.Load(),
+		"errors": m.errors.Load(),
+		"sum_ms": float64(m.sum.Load()) / float64(time.Millisecond),
+	}
+	var cumulative int64
+	for i, bound := range latencyBuckets {
+		cumulative += m.buckets[i].Load()
+		out["le_"+bound.String()] = cumulative
+	}
+	out["le_inf"] = cumulative + m.buckets[len(latencyBuckets)].Load()
+	data, _ := json.Marshal(out)
+	return string(data)
+}
+
+// commandMetricsFor returns the metrics of a command, creating them on first use
+func commandMetricsFor(name string) *commandMetrics {
+	if m, ok := redisMetrics.commands.Get(name).(*commandMetrics); ok {
+		return m
+	}
+	redisMetrics.mu.Lock()
+	defer redisMetrics.mu.Unlock()
+	if m, ok := redisMetrics.commands.Get(name).(*commandMetrics); ok {
+		return m
+	}
+	m := newCommandMetrics()
+	redisMetrics.commands.Set(name, m)
+	return m
+}
+
+// commandFailed reports whether err is a failure. Missing keys are not, nor are the NOSCRIPT
+// replies of scripts run for the first time on a server, which are then sent in full.
+func commandFailed(err error) bool {
+	return err != nil && !errors.Is(err, redis.Nil) && !redis.HasErrorPrefix(err, "NOSCRIPT")
+}
+
+// metricsHook records the latency and errors of the commands a client runs. Pipelines,
+// including transactions, are recorded as "pipeline" and fail if any of their commands does.
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			redisMetrics.dialErrors.Add(1)
+		}
+		return conn, err
+	}
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		commandMetricsFor(strings.ToLower(cmd.Name())).observe(time.Since(start), commandFailed(err))
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		failed := commandFailed(err)
+		for _, cmd := range cmds {
+			failed = failed || commandFailed(cmd.Err())
+		}
+		commandMetricsFor("pipeline").observe(time.Since(start), failed)
+		return err
+	}
+}
+
+// instrument records the command metrics of client and publishes its pool stats until
+// untrack is called
+func instrument(client redis.UniversalClient) {
+	client.AddHook(metricsHook{})
+	redisMetrics.mu.Lock()
+	redisMetrics.clients[client] = struct{}{}
+	redisMetrics.mu.Unlock()
+}
+
+// untrack stops publishing the pool stats of a closed client
+func untrack(client redis.UniversalClient) {
+	redisMetrics.mu.Lock()
+	delete(redisMetrics.clients, client)
+	redisMetrics.mu.Unlock()
+}
+
+// poolStats sums the connection pool stats of the open clients
+func poolStats() any {
+	redisMetrics.mu.Lock()
+	defer redisMetrics.mu.Unlock()
+	var total redis.PoolStats
+	for client := range redisMetrics.clients {
+		stats := client.PoolStats()
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Timeouts += stats.Timeouts
+		total.TotalConns += stats.TotalConns
+		total.IdleConns += stats.IdleConns
+		total.StaleConns += stats.StaleConns
+	}
+	return map[string]uint32{
+		"hits":        total.Hits,
+		"misses":      total.Misses,
+		"timeouts":    total.Timeouts,
+		"total_conns": total.TotalConns,
+		"idle_conns":  total.IdleConns,
+		"stale_conns": total.StaleConns,
+	}
+}
+
+// Ping checks that Redis answers, for readiness probes. It is not retried and does not use
+// the fallback, so it fails while Redis is unavailable even if the client is degraded.
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
@@ -0,0 +1,82 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestCommandMetrics(t *testing.T) {
+	m := newCommandMetrics()
+	m.observe(300*time.Microsecond, false)
+	m.observe(3*time.Millisecond, true)
+	m.observe(2*time.Second, false)
+
+	var got map[string]float64
+	if err := json.Unmarshal([]byte(m.String()), &got); err != nil {
+		t.Fatalf("metrics are not JSON: %v", err)
+	}
+	want := map[string]float64{
+		"count":    3,
+		"errors":   1,
+		"le_500µs": 1,
+		"le_2ms":   1,
+		"le_5ms":   2,
+		"le_1s":    2,
+		"le_inf":   3,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+}
+
+// replyError is an error reply of the Redis server
+type replyError string
+
+func (e replyError) Error() string { return string(e) }
+
+func (replyError) RedisError() {}
+
+func TestCommandFailed(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{redis.Nil, false},
+		{replyError("NOSCRIPT No matching script. Please use EVAL."), false},
+		{replyError("WRONGTYPE Operation against a key holding the wrong kind of value"), true},
+		{context.DeadlineExceeded, true},
+	}
+	for _, c := range cases {
+		if got := commandFailed(c.err); got != c.want {
+			t.Errorf("commandFailed(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestMetricsHookRecordsFailures(t *testing.T) {
+	r := unreachableRedisClient(t)
+	instrument(r.client)
+	defer r.Close()
+	commands, dialErrors := commandMetricsFor("get"), redisMetrics.dialErrors.Value()
+	count, failed := commands.count.Load(), commands.errors.Load()
+
+	if _, err := r.client.Get(context.Background(), "k").Result(); err == nil {
+		t.Fatal("Get should fail while Redis is unavailable")
+	}
+	if n := commands.count.Load() - count; n != 1 {
+		t.Errorf("recorded %d gets, want 1", n)
+	}
+	if n := commands.errors.Load() - failed; n != 1 {
+		t.Errorf("recorded %d failed gets, want 1", n)
+	}
+	if redisMetrics.dialErrors.Value() == dialErrors {
+		t.Error("the failed dial was not counted")
+	}
+}
@@ -0,0 +1,360 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestIsClusterTransientError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{redis.Nil, false},
+		{errors.New("MOVED 3999 127.0.0.1:6381"), true},
+		{errors.New("ASK 3999 127.0.0.1:6381"), true},
+		{errors.New("TRYAGAIN Multiple keys request during rehashing of slot"), true},
+		{errors.New("CLUSTERDOWN The cluster is down"), true},
+		{errors.New("LOADING Redis is loading the dataset in memory"), true},
+		{errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"), false},
+		{errors.New("dial tcp: connection refused"), false},
+	}
+
+	for _, tc := range cases {
+		if got := IsClusterTransientError(tc.err); got != tc.want {
+			t.Errorf("IsClusterTransientError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestKeysAreHashTagged(t *testing.T) {
+	id := uuid.New().String()
+	for _, key := range []string{nonceKey(id), tokenBlacklistKey(id), userRevocationKey(id), userBlacklistKey(id), rateLimitKey(id), slidingWindowKey(id, 1), lockKey(id), otpKey(id), replayKey(id), actionTokenKey(id)} {
+		if !strings.Contains(key, "{"+id+"}") {
+			t.Errorf("key %q does not hash-tag %q", key, id)
+		}
+	}
+}
+
+func TestWithRetryStopsOnPermanentError(t *testing.T) {
+	r := &RedisClient{maxRetries: defaultClusterRetries}
+
+	calls := 0
+	err := r.withRetry(context.Background(), func() error {
+		calls++
+		return errors.New("WRONGTYPE")
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("expected a single failed call, got %d calls and err %v", calls, err)
+	}
+
+	calls = 0
+	err = r.withRetry(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("TRYAGAIN")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on the third call, got %d calls and err %v", calls, err)
+	}
+}
+
+func TestIsFailoverTransientError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{redis.Nil, false},
+		{errors.New("READONLY You can't write against a read only replica."), true},
+		{errors.New("LOADING Redis is loading the dataset in memory"), true},
+		{errors.New("MASTERDOWN Link with MASTER is down and replica-serve-stale-data is set to 'no'."), true},
+		{errors.New("redis: all sentinels specified in configuration are unreachable"), true},
+		{io.EOF, true},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"), false},
+		{errors.New("MOVED 3999 127.0.0.1:6381"), false},
+	}
+
+	for _, tc := range cases {
+		if got := IsFailoverTransientError(tc.err); got != tc.want {
+			t.Errorf("IsFailoverTransientError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestWithRetryUntilFailoverTimeout(t *testing.T) {
+	r := &RedisClient{retryTimeout: time.Second, transient: IsFailoverTransientError}
+
+	// Retries past maxRetries until the master is back
+	calls := 0
+	err := r.withRetry(context.Background(), func() error {
+		calls++
+		if calls < 6 {
+			return errors.New("READONLY You can't write against a read only replica.")
+		}
+		return nil
+	})
+	if err != nil || calls != 6 {
+		t.Fatalf("expected success on the sixth call, got %d calls and err %v", calls, err)
+	}
+
+	// Gives up once the timeout has passed
+	start := time.Now()
+	err = r.withRetry(context.Background(), func() error {
+		return io.EOF
+	})
+	if err != io.EOF || time.Since(start) < r.retryTimeout {
+		t.Fatalf("expected io.EOF after %v, got %v after %v", r.retryTimeout, err, time.Since(start))
+	}
+}
+
+// TestSentinelFailover fails the master over to a replica while using the blacklist and
+// nonce store. It needs a master with a replica monitored by Sentinel, e.g. the bitnami
+// redis and redis-sentinel images, and runs with:
+//
+//	REDIS_SENTINEL_ADDRS=127.0.0.1:26379 REDIS_SENTINEL_MASTER=mymaster go test ./pkg/util -run Sentinel
+func TestSentinelFailover(t *testing.T) {
+	addrs, master := os.Getenv("REDIS_SENTINEL_ADDRS"), os.Getenv("REDIS_SENTINEL_MASTER")
+	if addrs == "" || master == "" {
+		t.Skip("REDIS_SENTINEL_ADDRS or REDIS_SENTINEL_MASTER not set, skipping Redis Sentinel test")
+	}
+
+	ctx := context.Background()
+	client, err := NewRedisSentinelClient(RedisSentinelOptions{
+		MasterName:      master,
+		SentinelAddrs:   strings.Split(addrs, ","),
+		Password:        os.Getenv("REDIS_SENTINEL_MASTER_PASSWORD"),
+		FailoverTimeout: 30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to connect through sentinel: %v", err)
+	}
+	defer client.Close()
+
+	id := uuid.New().String()
+	if err := client.BlacklistToken(ctx, id, time.Minute); err != nil {
+		t.Fatalf("BlacklistToken: %v", err)
+	}
+
+	sentinel := redis.NewSentinelClient(&redis.Options{Addr: strings.Split(addrs, ",")[0]})
+	defer sentinel.Close()
+	before, err := sentinel.GetMasterAddrByName(ctx, master).Result()
+	if err != nil {
+		t.Fatalf("SENTINEL GET-MASTER-ADDR-BY-NAME: %v", err)
+	}
+	// Let the replica catch up, so the blacklist entry survives the failover
+	time.Sleep(time.Second)
+	if err := sentinel.Failover(ctx, master).Err(); err != nil {
+		t.Fatalf("SENTINEL FAILOVER: %v", err)
+	}
+
+	// Keep writing while the sentinels promote the replica
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		if err := client.StoreNonce(ctx, uuid.New().String(), time.Minute); err != nil {
+			t.Fatalf("StoreNonce during failover: %v", err)
+		}
+		after, err := sentinel.GetMasterAddrByName(ctx, master).Result()
+		if err == nil && after[0]+":"+after[1] != before[0]+":"+before[1] {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("sentinels did not fail over within 30s")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	blacklisted, err := client.IsTokenBlacklisted(ctx, id)
+	if err != nil || !blacklisted {
+		t.Fatalf("IsTokenBlacklisted after failover = %v, %v", blacklisted, err)
+	}
+	nonce := uuid.New().String()
+	if err := client.StoreNonce(ctx, nonce, time.Minute); err != nil {
+		t.Fatalf("StoreNonce after failover: %v", err)
+	}
+	if exists, err := client.GetNonce(ctx, nonce); err != nil || !exists {
+		t.Fatalf("GetNonce after failover = %v, %v", exists, err)
+	}
+}
+
+// TestClusterSlotMigration moves the slot holding a nonce and a blacklist entry between two
+// masters while using them, mimicking what redis-cli --cluster reshard does. It needs a running
+// Redis Cluster, e.g.:
+//
+//	docker run -d -e IP=0.0.0.0 -p 7000-7005:7000-7005 grokzen/redis-cluster:7.0.10
+//	REDIS_CLUSTER_ADDRS=127.0.0.1:7000,127.0.0.1:7001,127.0.0.1:7002 go test ./pkg/util -run Cluster
+func TestClusterSlotMigration(t *testing.T) {
+	addrs := os.Getenv("REDIS_CLUSTER_ADDRS")
+	if addrs == "" {
+		t.Skip("REDIS_CLUSTER_ADDRS not set, skipping Redis Cluster test")
+	}
+
+	ctx := context.Background()
+	client, err := NewRedisClusterClient(strings.Split(addrs, ","), os.Getenv("REDIS_CLUSTER_PASSWORD"))
+	if err != nil {
+		t.Fatalf("failed to connect to cluster: %v", err)
+	}
+	defer client.Close()
+	cluster := client.client.(*redis.ClusterClient)
+
+	// Nonce and blacklist entry share a hash tag and therefore a slot
+	id := uuid.New().String()
+	if err := client.StoreNonce(ctx, id, time.Minute); err != nil {
+		t.Fatalf("StoreNonce: %v", err)
+	}
+	if err := client.BlacklistToken(ctx, id, time.Minute); err != nil {
+		t.Fatalf("BlacklistToken: %v", err)
+	}
+
+	slot, err := cluster.ClusterKeySlot(ctx, nonceKey(id)).Result()
+	if err != nil {
+		t.Fatalf("CLUSTER KEYSLOT: %v", err)
+	}
+	blacklistSlot, err := cluster.ClusterKeySlot(ctx, tokenBlacklistKey(id)).Result()
+	if err != nil {
+		t.Fatalf("CLUSTER KEYSLOT: %v", err)
+	}
+	if slot != blacklistSlot {
+		t.Fatalf("nonce and blacklist keys are in different slots: %d vs %d", slot, blacklistSlot)
+	}
+
+	source, target := findMigrationNodes(t, ctx, cluster, slot)
+	sourceClient := redis.NewClient(&redis.Options{Addr: source.Addr, Password: os.Getenv("REDIS_CLUSTER_PASSWORD")})
+	defer sourceClient.Close()
+	targetClient := redis.NewClient(&redis.Options{Addr: target.Addr, Password: os.Getenv("REDIS_CLUSTER_PASSWORD")})
+	defer targetClient.Close()
+
+	assertState := func(stage string) {
+		t.Helper()
+		exists, err := client.GetNonce(ctx, id)
+		if err != nil || !exists {
+			t.Fatalf("%s: GetNonce = %v, %v", stage, exists, err)
+		}
+		blacklisted, err := client.IsTokenBlacklisted(ctx, id)
+		if err != nil || !blacklisted {
+			t.Fatalf("%s: IsTokenBlacklisted = %v, %v", stage, blacklisted, err)
+		}
+	}
+
+	// Open the migration: the slot is now served by both nodes with ASK redirects
+	mustDo(t, targetClient.Do(ctx, "CLUSTER", "SETSLOT", slot, "IMPORTING", source.ID))
+	mustDo(t, sourceClient.Do(ctx, "CLUSTER", "SETSLOT", slot, "MIGRATING", target.ID))
+	assertState("migration started")
+
+	// Move the keys one by one, checking in between
+	keys, err := sourceClient.ClusterGetKeysInSlot(ctx, int(slot), 1000).Result()
+	if err != nil {
+		t.Fatalf("CLUSTER GETKEYSINSLOT: %v", err)
+	}
+	host, port := splitAddr(t, target.Addr)
+	for _, key := range keys {
+		mustDo(t, sourceClient.Do(ctx, "MIGRATE", host, port, key, 0, 5000))
+		assertState("key " + key + " migrated")
+	}
+
+	// Hand the slot over; the first access after this triggers MOVED
+	for _, c := range []*redis.Client{targetClient, sourceClient} {
+		mustDo(t, c.Do(ctx, "CLUSTER", "SETSLOT", slot, "NODE", target.ID))
+	}
+	assertState("migration finished")
+
+	if err := client.InvalidateNonce(ctx, id); err != nil {
+		t.Fatalf("InvalidateNonce: %v", err)
+	}
+	exists, err := client.GetNonce(ctx, id)
+	if err != nil || exists {
+		t.Fatalf("nonce still present after invalidation: %v, %v", exists, err)
+	}
+}
+
+// findMigrationNodes returns the master owning slot and another master to move it to
+func findMigrationNodes(t *testing.T, ctx context.Context, cluster *redis.ClusterClient, slot int64) (redis.ClusterNode, redis.ClusterNode) {
+	t.Helper()
+	slots, err := cluster.ClusterSlots(ctx).Result()
+	if err != nil {
+		t.Fatalf("CLUSTER SLOTS: %v", err)
+	}
+
+	var source redis.ClusterNode
+	masters := map[string]redis.ClusterNode{}
+	for _, s := range slots {
+		if len(s.Nodes) == 0 {
+			continue
+		}
+		masters[s.Nodes[0].ID] = s.Nodes[0]
+		if int64(s.Start) <= slot && slot <= int64(s.End) {
+			source = s.Nodes[0]
+		}
+	}
+	for id, node := range masters {
+		if id != source.ID {
+			return source, node
+		}
+	}
+	t.Skip("cluster needs at least two masters to migrate a slot")
+	return source, source
+}
+
+// splitAddr splits host:port
+func splitAddr(t *testing.T, addr string) (string, string) {
+	t.Helper()
+	i := strings.LastIndex(addr, ":")
+	if i < 0 {
+		t.Fatalf("invalid address %q", addr)
+	}
+	return addr[:i], addr[i+1:]
+}
+
+// mustDo fails the test if cmd returned an error
+func mustDo(t *testing.T, cmd *redis.Cmd) {
+	t.Helper()
+	if err := cmd.Err(); err != nil {
+		t.Fatalf("%v: %v", cmd.Args(), err)
+	}
+}
+
+// TestSubscribeRevocations runs against the Redis server at REDIS_ADDR
+func TestSubscribeRevocations(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set, skipping Redis pub/sub test")
+	}
+	r := &RedisClient{client: redis.NewClient(&redis.Options{Addr: addr, Password: os.Getenv("REDIS_PASSWORD")})}
+	defer r.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscribed := make(chan struct{}, 1)
+	events := make(chan string, 1)
+	go r.SubscribeRevocations(ctx, func(event string) { events <- event }, func() { subscribed <- struct{}{} })
+	select {
+	case <-subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscribed was not called")
+	}
+
+	if err := r.PublishRevocation(ctx, "token:t1"); err != nil {
+		t.Fatalf("PublishRevocation: %v", err)
+	}
+	select {
+	case event := <-events:
+		if event != "token:t1" {
+			t.Fatalf("received %q, want token:t1", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the published event was not received")
+	}
+}
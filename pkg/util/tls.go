@@ -0,0 +1,54 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSOptions locates the certificates of a TLS client connection in PEM files
+type TLSOptions struct {
+	// CAFile holds the certificates trusted to sign the server's; empty uses the system pool
+	CAFile string
+	// CertFile and KeyFile hold the client certificate for mutual TLS; both or neither are set
+	CertFile string
+	KeyFile  string
+	// ServerName is checked against the server's certificate; empty uses the dialed host
+	ServerName string
+	// InsecureSkipVerify accepts any server certificate, for testing only
+	InsecureSkipVerify bool
+}
+
+// Config loads the files into a client TLS configuration requiring TLS 1.2 or later
+func (o TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", o.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, fmt.Errorf("client certificate and key files must be set together")
+	}
+	if o.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePEM writes a PEM block to a file in dir and returns its path
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newTLSTestServer starts a TLS server that does not log the handshakes the tests fail on purpose
+func newTLSTestServer() *httptest.Server {
+	server := httptest.NewUnstartedServer(nil)
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	return server
+}
+
+func TestTLSOptionsVerifiesServerWithCAFile(t *testing.T) {
+	server := newTLSTestServer()
+	defer server.Close()
+	dir := t.TempDir()
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	dial := func(opts TLSOptions) error {
+		config, err := opts.Config()
+		if err != nil {
+			return err
+		}
+		conn, err := tls.Dial("tcp", server.Listener.Addr().String(), config)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	// The test certificate is issued for example.com and 127.0.0.1
+	if err := dial(TLSOptions{CAFile: caFile}); err != nil {
+		t.Errorf("dial with CA file: %v", err)
+	}
+	if err := dial(TLSOptions{CAFile: caFile, ServerName: "example.com"}); err != nil {
+		t.Errorf("dial with server name: %v", err)
+	}
+	if err := dial(TLSOptions{CAFile: caFile, ServerName: "redis.example.net"}); err == nil {
+		t.Error("certificate accepted for the wrong server name")
+	}
+	if err := dial(TLSOptions{}); err == nil {
+		t.Error("self-signed certificate accepted without the CA file")
+	}
+	if err := dial(TLSOptions{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("dial skipping verification: %v", err)
+	}
+}
+
+func TestTLSOptionsLoadsClientCertificate(t *testing.T) {
+	server := newTLSTestServer()
+	defer server.Close()
+	cert := server.TLS.Certificates[0]
+	dir := t.TempDir()
+	certFile := writePEM(t, dir, "client.pem", "CERTIFICATE", cert.Certificate[0])
+
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := writePEM(t, dir, "client-key.pem", "PRIVATE KEY", key)
+
+	config, err := TLSOptions{CertFile: certFile, KeyFile: keyFile}.Config()
+	if err != nil {
+		t.Fatalf("Config: %v", err)
+	}
+	if len(config.Certificates) != 1 || config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("config has %d certificates and minimum version %x", len(config.Certificates), config.MinVersion)
+	}
+
+	if _, err := (TLSOptions{CertFile: certFile}).Config(); err == nil {
+		t.Error("certificate without key accepted")
+	}
+	if _, err := (TLSOptions{CAFile: keyFile}).Config(); err == nil {
+		t.Error("CA file without certificates accepted")
+	}
+	if _, err := (TLSOptions{CAFile: filepath.Join(dir, "missing.pem")}).Config(); err == nil {
+		t.Error("missing CA file accepted")
+	}
+}
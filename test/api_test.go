@@ -97,14 +97,14 @@ type NonceResponse struct {
 // AuthResponse 认证响应
 type AuthResponse struct {
 	User struct {
-		ID        string  `json:"id"`
-		Email     string  `json:"email"`
-		Username  string  `json:"username"`
-		Role      string  `json:"role"`
-		Active    bool    `json:"active"`
-		AvatarURL *string `json:"avatar_url"`
-		CreatedAt string  `json:"created_at"`
-		UpdatedAt string  `json:"updated_at"`
+		ID        string   `json:"id"`
+		Email     string   `json:"email"`
+		Username  string   `json:"username"`
+		Roles     []string `json:"roles"`
+		Active    bool     `json:"active"`
+		AvatarURL *string  `json:"avatar_url"`
+		CreatedAt string   `json:"created_at"`
+		UpdatedAt string   `json:"updated_at"`
 	} `json:"user"`
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -113,14 +113,14 @@ type AuthResponse struct {
 
 // UserResponse 用户响应
 type UserResponse struct {
-	ID        string  `json:"id"`
-	Email     string  `json:"email"`
-	Username  string  `json:"username"`
-	Role      string  `json:"role"`
-	Active    bool    `json:"active"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	ID        string   `json:"id"`
+	Email     string   `json:"email"`
+	Username  string   `json:"username"`
+	Roles     []string `json:"roles"`
+	Active    bool     `json:"active"`
+	AvatarURL *string  `json:"avatar_url,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
 }
 
 // TokenResponse 刷新令牌响应
@@ -310,7 +310,7 @@ func (suite *ApiTestSuite) TestA_Registration() {
 	reqBody := map[string]string{
 		"email":    testEmail,
 		"username": "Test User",
-		"password": "password123",
+		"password": "Test-Passw0rd!",
 	}
 
 	// 发送注册请求
@@ -329,8 +329,8 @@ func (suite *ApiTestSuite) TestA_Registration() {
 	// 验证响应内容
 	assert.Equal(t, testEmail, user.Email)
 	assert.Equal(t, "Test User", user.Username)
-	assert.Equal(t, "user", user.Role) // 默认角色应为user
-	assert.True(t, user.Active)        // 默认应为激活状态
+	assert.Equal(t, []string{"user"}, user.Roles) // 默认角色应为user
+	assert.True(t, user.Active)                   // 默认应为激活状态
 }
 
 // 登录方法
@@ -372,7 +372,7 @@ func (suite *ApiTestSuite) TestB_Login() {
 
 	// 创建随机测试用户
 	testEmail := generateTestEmail()
-	testPassword := "password123"
+	testPassword := "Test-Passw0rd!"
 
 	// 先注册用户
 	reqBody := map[string]string{
@@ -572,6 +572,42 @@ func (suite *ApiTestSuite) TestG_InvalidSignature() {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
+// 弱密码注册测试
+func (suite *ApiTestSuite) TestH_WeakPasswordRejected() {
+	t := suite.T()
+
+	reqBody := map[string]string{
+		"email":    generateTestEmail(),
+		"username": "Weak Password User",
+		"password": "password123",
+	}
+
+	resp, err := suite.sendSecureRequest("POST", "/auth/register", reqBody, false)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	// 验证响应状态码应为错误请求
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// 验证返回了结构化的密码策略错误
+	var errResp struct {
+		Error      string `json:"error"`
+		Violations []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"violations"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&errResp)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, errResp.Violations)
+
+	codes := make([]string, 0, len(errResp.Violations))
+	for _, v := range errResp.Violations {
+		codes = append(codes, v.Code)
+	}
+	assert.Contains(t, codes, "common_password")
+}
+
 // 运行测试套件
 func TestAPISuite(t *testing.T) {
 	suite.Run(t, new(ApiTestSuite))
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hewenyu/gin-pkg/test/scenario"
+)
+
+// varFlags 收集可重复的 -var key=value 参数
+type varFlags map[string]string
+
+func (v varFlags) String() string {
+	return fmt.Sprint(map[string]string(v))
+}
+
+func (v varFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	v[key] = val
+	return nil
+}
+
+func main() {
+	// 定义命令行参数
+	apiURL := flag.String("url", envOr("API_BASE_URL", "http://localhost:8080"), "API服务地址（不含 /api/v1）")
+	signSecret := flag.String("secret", os.Getenv("API_SIGNATURE_SECRET"), "API签名密钥")
+	signVersion := flag.String("sign-version", envOr("API_SIGN_VERSION", "1"), "签名版本：1（参数签名）、2（请求体哈希签名，HMAC-SHA256）或 3（请求体哈希签名，HMAC-SHA512）")
+	dir := flag.String("dir", "scenarios", "场景目录，未指定场景文件时执行其中所有场景")
+	quiet := flag.Bool("q", false, "只输出结果")
+	vars := varFlags{}
+	flag.Var(vars, "var", "全局变量 key=value，可重复指定")
+	flag.Parse()
+
+	if *signSecret == "" {
+		fmt.Println("错误: 请通过 -secret 或 API_SIGNATURE_SECRET 指定签名密钥")
+		os.Exit(2)
+	}
+
+	// 加载场景
+	var scenarios []*scenario.Scenario
+	if flag.NArg() > 0 {
+		for _, file := range flag.Args() {
+			s, err := scenario.Load(file)
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				os.Exit(2)
+			}
+			scenarios = append(scenarios, s)
+		}
+	} else {
+		var err error
+		scenarios, err = scenario.LoadDir(*dir)
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	runner := scenario.NewRunner(*apiURL, *signSecret)
+	runner.SignVersion = *signVersion
+	for k, v := range vars {
+		runner.Vars[k] = v
+	}
+	if !*quiet {
+		runner.Logf = func(format string, args ...any) {
+			fmt.Printf(format+"\n", args...)
+		}
+	}
+
+	// 依次执行场景
+	failed := 0
+	for _, s := range scenarios {
+		if err := runner.Run(context.Background(), s); err != nil {
+			failed++
+			fmt.Printf("✗ %s: %v\n", s.Name, err)
+			continue
+		}
+		fmt.Printf("✓ %s\n", s.Name)
+	}
+
+	fmt.Printf("\n共 %d 个场景，失败 %d 个\n", len(scenarios), failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// envOr 读取环境变量，未设置时返回默认值
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
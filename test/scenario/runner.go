@@ -0,0 +1,455 @@
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Runner 执行场景，自动处理随机数获取和请求签名
+type Runner struct {
+	BaseURL   string
+	APIPrefix string
+	Secret    string
+	// SignVersion 为 "2"（HMAC-SHA256）或 "3"（HMAC-SHA512）时使用请求体哈希签名，否则使用参数签名（v1）
+	SignVersion string
+	Client      *http.Client
+	// Vars 全局变量，可被场景中的 vars 覆盖
+	Vars map[string]string
+	// Logf 输出执行日志，为空时不输出
+	Logf func(format string, args ...any)
+}
+
+// NewRunner 创建场景执行器
+func NewRunner(baseURL, secret string) *Runner {
+	return &Runner{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		APIPrefix: "/api/v1",
+		Secret:    secret,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+		Vars:      map[string]string{},
+	}
+}
+
+// StepError 描述失败的步骤
+type StepError struct {
+	Scenario string
+	Step     int
+	Name     string
+	Err      error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("scenario %q step %d (%s): %v", e.Scenario, e.Step, e.Name, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Run 按顺序执行场景中的所有步骤，遇到第一个失败的步骤即返回
+func (r *Runner) Run(ctx context.Context, s *Scenario) error {
+	vars := map[string]string{
+		// unique 在一次执行内保持不变，便于生成不冲突的邮箱和用户名
+		"unique": strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+	for k, v := range r.Vars {
+		vars[k] = v
+	}
+	// 场景变量本身也可以使用模板
+	keys := make([]string, 0, len(s.Vars))
+	for k := range s.Vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value, err := render(s.Vars[k], vars)
+		if err != nil {
+			return &StepError{Scenario: s.Name, Name: "vars", Err: err}
+		}
+		vars[k] = value
+	}
+
+	for i, step := range s.Steps {
+		name := step.Name
+		if name == "" {
+			name = step.Request.Method + " " + step.Request.Path
+		}
+		r.logf("[%s] step %d: %s", s.Name, i+1, name)
+
+		if err := r.runStep(ctx, step, vars); err != nil {
+			return &StepError{Scenario: s.Name, Step: i + 1, Name: name, Err: err}
+		}
+	}
+	return nil
+}
+
+// runStep 发送一个请求，校验断言并提取变量
+func (r *Runner) runStep(ctx context.Context, step Step, vars map[string]string) error {
+	req, err := r.buildRequest(ctx, step.Request, vars)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	r.logf("  -> %d %s", resp.StatusCode, truncate(string(body), 300))
+
+	if step.Expect.Status != 0 && resp.StatusCode != step.Expect.Status {
+		return fmt.Errorf("expected status %d, got %d: %s", step.Expect.Status, resp.StatusCode, truncate(string(body), 300))
+	}
+
+	var doc any
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &doc); err != nil && (len(step.Expect.JSON) > 0 || len(step.Expect.Exists) > 0 || len(step.Capture) > 0) {
+			return fmt.Errorf("response is not JSON: %w", err)
+		}
+	}
+
+	for path, want := range step.Expect.JSON {
+		want, err := render(want, vars)
+		if err != nil {
+			return err
+		}
+		got, ok := lookup(doc, path)
+		if !ok {
+			return fmt.Errorf("response has no %q", path)
+		}
+		if s := stringify(got); s != want {
+			return fmt.Errorf("expected %q to be %q, got %q", path, want, s)
+		}
+	}
+	for _, path := range step.Expect.Exists {
+		if _, ok := lookup(doc, path); !ok {
+			return fmt.Errorf("response has no %q", path)
+		}
+	}
+	for name, path := range step.Capture {
+		got, ok := lookup(doc, path)
+		if !ok {
+			return fmt.Errorf("cannot capture %s: response has no %q", name, path)
+		}
+		vars[name] = stringify(got)
+	}
+	return nil
+}
+
+// buildRequest 渲染模板并构建带签名的请求
+func (r *Runner) buildRequest(ctx context.Context, spec Request, vars map[string]string) (*http.Request, error) {
+	path, err := render(spec.Path, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	for k, v := range spec.Query {
+		value, err := render(v, vars)
+		if err != nil {
+			return nil, err
+		}
+		query.Set(k, value)
+	}
+
+	var body []byte
+	var bodyParams map[string]string
+	if spec.Body != nil {
+		rendered, err := renderValue(spec.Body, vars)
+		if err != nil {
+			return nil, err
+		}
+		body, err = json.Marshal(rendered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode body: %w", err)
+		}
+		bodyParams = stringFields(rendered)
+	}
+
+	fullURL := r.BaseURL + r.APIPrefix + path
+	if len(query) > 0 {
+		fullURL += "?" + query.Encode()
+	}
+
+	method := strings.ToUpper(spec.Method)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if spec.Sign == nil || *spec.Sign {
+		if err := r.sign(ctx, req, query, body, bodyParams); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.Token != "" {
+		token, err := render(spec.Token, vars)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range spec.Headers {
+		value, err := render(v, vars)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(k, value)
+	}
+	return req, nil
+}
+
+// sign 获取随机数并按服务端规则添加时间戳、随机数和签名头。
+// v1：查询参数、JSON请求体中的字符串字段、timestamp 和 nonce 按键排序后做 HMAC-SHA256；
+// v2：对方法、路径、查询参数、timestamp、nonce 和请求体的 SHA-256 组成的规范字符串做 HMAC-SHA256
+func (r *Runner) sign(ctx context.Context, req *http.Request, query url.Values, body []byte, bodyParams map[string]string) error {
+	nonce, err := r.fetchNonce(ctx)
+	if err != nil {
+		return err
+	}
+
+	if r.SignVersion == "2" || r.SignVersion == "3" {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		req.Header.Set("X-Sign-Version", r.SignVersion)
+		req.Header.Set("X-Timestamp", timestamp)
+		req.Header.Set("X-Nonce", nonce)
+		req.Header.Set("X-Sign", requestSignature(r.SignVersion, req.Method, req.URL.EscapedPath(), query, timestamp, nonce, body, r.Secret))
+		return nil
+	}
+
+	params := map[string]string{}
+	for k := range query {
+		params[k] = query.Get(k)
+	}
+	for k, v := range bodyParams {
+		params[k] = v
+	}
+	params["timestamp"] = strconv.FormatInt(time.Now().UnixMilli(), 10)
+	params["nonce"] = nonce
+
+	req.Header.Set("X-Timestamp", params["timestamp"])
+	req.Header.Set("X-Nonce", nonce)
+	req.Header.Set("X-Sign", signature(params, r.Secret))
+	return nil
+}
+
+// fetchNonce 从服务端获取一个新的随机数
+func (r *Runner) fetchNonce(ctx context.Context) (string, error) {
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		r.BaseURL+r.APIPrefix+"/auth/nonce?timestamp="+timestamp, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Timestamp", timestamp)
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get nonce: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get nonce, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var nonceResp struct {
+		Nonce string `json:"nonce"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&nonceResp); err != nil {
+		return "", fmt.Errorf("failed to decode nonce: %w", err)
+	}
+	return nonceResp.Nonce, nil
+}
+
+func (r *Runner) logf(format string, args ...any) {
+	if r.Logf != nil {
+		r.Logf(format, args...)
+	}
+}
+
+// signature 计算签名，与服务端 security.GenerateSignature 保持一致
+func signature(params map[string]string, secret string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "sign" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+params[k])
+	}
+
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strings.Join(pairs, "&")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalRequest 构建 v2/v3 签名的规范字符串，与服务端 security.CanonicalRequest 保持一致
+func canonicalRequest(method, path string, query url.Values, timestamp, nonce string, body []byte) string {
+	signed := url.Values{}
+	for k, v := range query {
+		if k != "sign" {
+			signed[k] = v
+		}
+	}
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		signed.Encode(),
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// requestSignature 计算 v2/v3 签名，与服务端 security.SignatureRegistry 中的方案保持一致
+func requestSignature(version, method, path string, query url.Values, timestamp, nonce string, body []byte, secret string) string {
+	newHash := sha256.New
+	if version == "3" {
+		newHash = sha512.New
+	}
+	h := hmac.New(newHash, []byte(secret))
+	h.Write([]byte(canonicalRequest(method, path, query, timestamp, nonce, body)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// templateFuncs 模板中可用的函数
+var templateFuncs = template.FuncMap{
+	"env": os.Getenv,
+}
+
+// render 渲染字符串模板，变量通过 {{ .name }} 引用
+func render(text string, vars map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %q: %w", text, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("failed to render %q: %w", text, err)
+	}
+	return buf.String(), nil
+}
+
+// renderValue 递归渲染请求体中的所有字符串
+func renderValue(value any, vars map[string]string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return render(v, vars)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			rendered, err := renderValue(item, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			rendered, err := renderValue(item, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// stringFields 返回请求体顶层的字符串字段，它们参与签名
+func stringFields(body any) map[string]string {
+	fields := map[string]string{}
+	if m, ok := body.(map[string]any); ok {
+		for k, v := range m {
+			if s, ok := v.(string); ok {
+				fields[k] = s
+			}
+		}
+	}
+	return fields
+}
+
+// lookup 按点分隔的路径（数组使用数字下标，如 items.0.id）查找JSON值
+func lookup(doc any, path string) (any, bool) {
+	current := doc
+	for _, part := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			current = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// stringify 把JSON值转换为字符串用于比较和变量提取
+func stringify(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// truncate 截断过长的日志内容
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,152 @@
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "scenario-secret"
+
+// fakeAPI 模拟服务端的随机数和签名校验
+func fakeAPI(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/auth/nonce", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"nonce": "n-1"})
+	})
+	mux.HandleFunc("/api/v1/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		params := map[string]string{
+			"email":     body["email"],
+			"password":  body["password"],
+			"timestamp": r.Header.Get("X-Timestamp"),
+			"nonce":     r.Header.Get("X-Nonce"),
+		}
+		if r.Header.Get("X-Sign") != signature(params, testSecret) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid signature"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "token-" + body["email"],
+			"user":         map[string]any{"id": "u-1", "email": body["email"]},
+		})
+	})
+	mux.HandleFunc("/api/v1/users/me/profile", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		expected := requestSignature(r.Header.Get("X-Sign-Version"), r.Method, r.URL.EscapedPath(), r.URL.Query(),
+			r.Header.Get("X-Timestamp"), r.Header.Get("X-Nonce"), body, testSecret)
+		if r.Header.Get("X-Sign-Version") == "" || r.Header.Get("X-Sign") != expected {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid signature"})
+			return
+		}
+		w.Write(body)
+	})
+	mux.HandleFunc("/api/v1/users/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-qa@example.com" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"id": "u-1", "roles": []string{"user"}})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestRunnerSignsCapturesAndAsserts(t *testing.T) {
+	server := fakeAPI(t)
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "flow.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+name: login flow
+vars:
+  email: "qa@example.com"
+steps:
+  - name: login
+    request:
+      method: POST
+      path: /auth/login
+      body:
+        email: "{{ .email }}"
+        password: secret
+    expect:
+      status: 200
+      json:
+        user.email: "{{ .email }}"
+    capture:
+      token: access_token
+      userId: user.id
+  - name: me
+    request:
+      method: GET
+      path: /users/me
+      token: "{{ .token }}"
+    expect:
+      status: 200
+      json:
+        id: "{{ .userId }}"
+        roles.0: user
+`), 0o644))
+
+	s, err := Load(file)
+	require.NoError(t, err)
+
+	runner := NewRunner(server.URL, testSecret)
+	assert.NoError(t, runner.Run(context.Background(), s))
+}
+
+func TestRunnerReportsFailingStep(t *testing.T) {
+	server := fakeAPI(t)
+	defer server.Close()
+
+	s := &Scenario{
+		Name: "bad secret",
+		Steps: []Step{{
+			Name:    "login",
+			Request: Request{Method: "POST", Path: "/auth/login", Body: map[string]any{"email": "a", "password": "b"}},
+			Expect:  Expect{Status: http.StatusOK},
+		}},
+	}
+
+	err := NewRunner(server.URL, "wrong-secret").Run(context.Background(), s)
+	var stepErr *StepError
+	require.ErrorAs(t, err, &stepErr)
+	assert.Equal(t, 1, stepErr.Step)
+	assert.Contains(t, err.Error(), "expected status 200, got 400")
+}
+
+func TestRunnerSignsBodyHash(t *testing.T) {
+	server := fakeAPI(t)
+	defer server.Close()
+
+	s := &Scenario{
+		Name: "body hash",
+		Steps: []Step{{
+			Name: "update profile",
+			Request: Request{
+				Method: "PUT",
+				Path:   "/users/me/profile",
+				Query:  map[string]string{"notify": "true"},
+				Body:   map[string]any{"nickname": "qa", "age": 30, "tags": []any{"a", "b"}},
+			},
+			Expect: Expect{Status: http.StatusOK, JSON: map[string]string{"age": "30", "tags.1": "b"}},
+		}},
+	}
+
+	for _, version := range []string{"2", "3"} {
+		runner := NewRunner(server.URL, testSecret)
+		runner.SignVersion = version
+		assert.NoError(t, runner.Run(context.Background(), s), "sign version %s", version)
+	}
+}
@@ -0,0 +1,99 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario 是一个由多个步骤组成的 API 流程，从 YAML 文件加载
+type Scenario struct {
+	Name  string            `yaml:"name"`
+	Vars  map[string]string `yaml:"vars"`
+	Steps []Step            `yaml:"steps"`
+
+	// 文件路径，用于错误信息
+	File string `yaml:"-"`
+}
+
+// Step 是场景中的一次请求及其断言
+type Step struct {
+	Name    string            `yaml:"name"`
+	Request Request           `yaml:"request"`
+	Expect  Expect            `yaml:"expect"`
+	Capture map[string]string `yaml:"capture"` // 变量名 -> 响应JSON路径
+}
+
+// Request 描述要发送的请求，所有字符串都支持模板变量
+type Request struct {
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Query   map[string]string `yaml:"query"`
+	Headers map[string]string `yaml:"headers"`
+	Body    any               `yaml:"body"`
+	// Token 不为空时作为 Bearer 令牌发送
+	Token string `yaml:"token"`
+	// Sign 为 false 时不添加签名头，用于测试签名校验本身
+	Sign *bool `yaml:"sign"`
+}
+
+// Expect 描述对响应的断言
+type Expect struct {
+	Status int `yaml:"status"`
+	// JSON 响应JSON路径 -> 期望值（按字符串比较）
+	JSON map[string]string `yaml:"json"`
+	// Exists 必须存在的响应JSON路径
+	Exists []string `yaml:"exists"`
+}
+
+// Load 从 YAML 文件加载场景
+func Load(path string) (*Scenario, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var s Scenario
+	if err := yaml.Unmarshal(content, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if s.Name == "" {
+		s.Name = filepath.Base(path)
+	}
+	if len(s.Steps) == 0 {
+		return nil, fmt.Errorf("scenario %s has no steps", path)
+	}
+	for i, step := range s.Steps {
+		if step.Request.Method == "" || step.Request.Path == "" {
+			return nil, fmt.Errorf("scenario %s step %d: request method and path are required", path, i+1)
+		}
+	}
+	s.File = path
+	return &s, nil
+}
+
+// LoadDir 按文件名顺序加载目录下所有 .yaml/.yml 场景
+func LoadDir(dir string) ([]*Scenario, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	scenarios := make([]*Scenario, 0, len(files))
+	for _, file := range files {
+		s, err := Load(file)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
@@ -0,0 +1,27 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hewenyu/gin-pkg/test/scenario"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScenarios 执行 scenarios 目录下所有 YAML 场景
+func TestScenarios(t *testing.T) {
+	scenarios, err := scenario.LoadDir("scenarios")
+	require.NoError(t, err)
+
+	runner := scenario.NewRunner(baseURL, signatureSecret)
+	runner.SignVersion = os.Getenv("API_SIGN_VERSION")
+	runner.Logf = t.Logf
+
+	for _, s := range scenarios {
+		t.Run(s.Name, func(t *testing.T) {
+			assert.NoError(t, runner.Run(context.Background(), s))
+		})
+	}
+}